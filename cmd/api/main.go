@@ -19,10 +19,12 @@ import (
 
 	_ "github.com/wealthpath/backend/docs"
 	"github.com/wealthpath/backend/internal/config"
+	"github.com/wealthpath/backend/internal/email"
 	"github.com/wealthpath/backend/internal/handler"
 	"github.com/wealthpath/backend/internal/repository"
 	"github.com/wealthpath/backend/internal/scheduler"
 	"github.com/wealthpath/backend/internal/service"
+	"github.com/wealthpath/backend/internal/storage"
 )
 
 // @title WealthPath API
@@ -74,6 +76,11 @@ func main() {
 	recurringRepo := repository.NewRecurringRepository(db)
 	interestRateRepo := repository.NewInterestRateRepository(db)
 	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	rateSubscriptionRepo := repository.NewRateSubscriptionRepository(db)
+	accountRepo := repository.NewAccountRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	ruleRepo := repository.NewRuleRepository(db)
 
 	// Initialize services
 	userService := service.NewUserServiceWithRefreshTokens(userRepo, refreshTokenRepo)
@@ -88,6 +95,13 @@ func main() {
 	reportRepo := repository.NewReportRepository(db)
 	reportService := service.NewReportService(reportRepo)
 	exportService := service.NewExportService(transactionRepo)
+	accountService := service.NewAccountService(accountRepo)
+	categoryService := service.NewCategoryService(categoryRepo)
+	tagService := service.NewTagService(tagRepo)
+	ruleService := service.NewRuleService(ruleRepo, transactionRepo, accountRepo)
+	duplicateService := service.NewDuplicateService(repository.NewDuplicateRepository(db))
+	exchangeRateService := service.NewExchangeRateService(repository.NewExchangeRateRepository(db))
+	bankExchangeRateService := service.NewBankExchangeRateService(repository.NewBankExchangeRateRepository(db), exchangeRateService)
 
 	// Initialize TOTP service with repository adapter
 	totpRepoAdapter := &TOTPUserRepoAdapter{userRepo: userRepo}
@@ -96,6 +110,91 @@ func main() {
 	// Initialize push notification service
 	pushRepo := repository.NewPushRepository(db)
 	pushService := service.NewPushNotificationService(pushRepo, cfg)
+	notificationInboxService := service.NewNotificationInboxService(repository.NewNotificationInboxRepository(db))
+
+	// Initialize email service. Messages are queued in the outbox and delivered by a background job.
+	emailRenderer, err := email.NewRenderer(cfg.FrontendURL)
+	if err != nil {
+		log.Fatalf("Failed to load email templates: %v", err)
+	}
+	emailTransport := email.NewSMTPTransport(email.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.SMTPFrom,
+		FromName: cfg.SMTPFromName,
+	})
+	emailService := service.NewEmailService(repository.NewEmailOutboxRepository(db), emailTransport, emailRenderer, cfg.EmailMaxAttempts)
+	var emailSender service.EmailSender
+	if cfg.IsEmailConfigured() {
+		emailSender = emailService
+	}
+
+	// Initialize interest rate alert service. Alerts are checked right after every scrape.
+	notificationService := service.NewNotificationService(rateSubscriptionRepo, interestRateRepo, userRepo, emailSender)
+	notificationService.SetPushSender(pushService)
+	notificationService.SetCooldown(cfg.RateAlertCooldown)
+	interestRateService.SetRateChangeNotifier(notificationService)
+
+	// Budget spending is needed for alerts, which are evaluated whenever an expense
+	// is created, updated or deleted, and for period rollovers
+	budgetService.SetTransactionRepo(transactionRepo)
+	if pushService.IsConfigured() {
+		budgetService.SetAlertNotifier(pushService)
+		transactionService.SetBudgetAlertChecker(budgetService)
+	}
+
+	// Savings contributions can link a transaction and trigger milestone notifications
+	savingsService.SetTransactionRepo(transactionRepo)
+	if pushService.IsConfigured() {
+		savingsService.SetMilestoneNotifier(pushService)
+	}
+
+	// Transactions can be recorded against an account, whose balances the dashboard shows
+	transactionService.SetAccountRepo(accountRepo)
+	dashboardService.SetAccountRepo(accountRepo)
+
+	// Categorization rules run over transactions created by hand, from the AI chat and from imports
+	transactionService.SetRuleApplier(ruleService)
+
+	// Totals are in the user's base currency. Transactions are converted when saved,
+	// or by a background job once the rate of their date is known. Savings goals,
+	// debts and budgets keep their own currency and are converted when compared.
+	transactionService.SetConverter(exchangeRateService)
+	userService.SetCurrencyConverter(exchangeRateService)
+	dashboardService.SetCurrencyRepo(reportRepo)
+	dashboardService.SetRateSource(exchangeRateService)
+	budgetService.SetCurrencyConverter(reportRepo, exchangeRateService)
+
+	// Weekly summaries are pushed to users who opted in
+	weeklySummaryService := service.NewWeeklySummaryService(pushRepo, transactionRepo, budgetService, recurringRepo, pushService)
+
+	// Receipts and invoices are kept on the local filesystem or in an S3-compatible bucket
+	var attachmentStorage storage.Storage
+	if cfg.AttachmentStorage == "s3" {
+		attachmentStorage, err = storage.NewS3Storage(storage.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			UsePathStyle:    cfg.S3UsePathStyle,
+		})
+	} else {
+		attachmentStorage, err = storage.NewLocalStorage(cfg.AttachmentDir)
+	}
+	if err != nil {
+		log.Fatalf("Failed to initialize attachment storage: %v", err)
+	}
+	attachmentService := service.NewAttachmentService(repository.NewAttachmentRepository(db), attachmentStorage, int64(cfg.AttachmentMaxSizeMB)<<20)
+
+	// Transactions can be imported from files exported by banks and other apps
+	importRepo := repository.NewImportRepository(db)
+	importService := service.NewImportService(importRepo, accountRepo)
+	importService.SetRuleApplier(ruleService)
+	migrationService := service.NewMigrationService(importRepo, accountService, categoryService, debtService, budgetService)
+	migrationService.SetRuleApplier(ruleService)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandlerWithConfig(userService, cfg)
@@ -110,6 +209,7 @@ func main() {
 	dashboardHandler := handler.NewDashboardHandler(dashboardService)
 	aiHandler := handler.NewAIHandler(aiService)
 	interestRateHandler := handler.NewInterestRateHandler(interestRateService)
+	exchangeRateHandler := handler.NewExchangeRateHandler(bankExchangeRateService)
 	reportHandler := handler.NewReportHandler(reportService)
 	exportHandler := handler.NewExportHandler(exportService, reportService)
 	calendarHandler := handler.NewCalendarHandler(recurringService, func(ctx context.Context, userID uuid.UUID) string {
@@ -117,6 +217,16 @@ func main() {
 		return currency
 	})
 	pushHandler := handler.NewPushHandler(pushService)
+	rateSubscriptionHandler := handler.NewRateSubscriptionHandler(notificationService)
+	notificationInboxHandler := handler.NewNotificationInboxHandler(notificationInboxService)
+	accountHandler := handler.NewAccountHandler(accountService)
+	categoryHandler := handler.NewCategoryHandler(categoryService)
+	tagHandler := handler.NewTagHandler(tagService)
+	ruleHandler := handler.NewRuleHandler(ruleService)
+	duplicateHandler := handler.NewDuplicateHandler(duplicateService)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService)
+	importHandler := handler.NewImportHandler(importService)
+	migrationHandler := handler.NewMigrationHandler(migrationService)
 
 	r := chi.NewRouter()
 
@@ -178,6 +288,10 @@ func main() {
 	r.Post("/api/interest-rates/seed", interestRateHandler.SeedRates)                 // Admin: seed sample data
 	r.Post("/api/interest-rates/scrape", interestRateHandler.ScrapeRates)             // Admin: scrape live rates
 
+	// Exchange rates (public - no auth required)
+	r.Get("/api/exchange-rates", exchangeRateHandler.ListRates)
+	r.Get("/api/exchange-rates/history", exchangeRateHandler.GetHistory)
+
 	// Protected routes
 	r.Group(func(r chi.Router) {
 		r.Use(handler.AuthMiddleware)
@@ -206,9 +320,63 @@ func main() {
 		r.Get("/api/transactions", transactionHandler.List)
 		r.Post("/api/transactions", transactionHandler.Create)
 		r.Get("/api/transactions/export/csv", exportHandler.ExportTransactionsCSV)
+		r.Post("/api/transactions/bulk", transactionHandler.Bulk)
 		r.Get("/api/transactions/{id}", transactionHandler.Get)
 		r.Put("/api/transactions/{id}", transactionHandler.Update)
 		r.Delete("/api/transactions/{id}", transactionHandler.Delete)
+		r.Get("/api/transactions/{id}/attachments", attachmentHandler.ListForTransaction)
+		r.Post("/api/transactions/{id}/attachments", attachmentHandler.UploadForTransaction)
+
+		// Accounts & Transfers
+		r.Get("/api/accounts", accountHandler.List)
+		r.Post("/api/accounts", accountHandler.Create)
+		r.Get("/api/accounts/{id}", accountHandler.Get)
+		r.Put("/api/accounts/{id}", accountHandler.Update)
+		r.Delete("/api/accounts/{id}", accountHandler.Delete)
+		r.Get("/api/transfers", accountHandler.ListTransfers)
+		r.Post("/api/transfers", accountHandler.CreateTransfer)
+		r.Get("/api/transfers/{id}", accountHandler.GetTransfer)
+		r.Delete("/api/transfers/{id}", accountHandler.DeleteTransfer)
+
+		// Categories
+		r.Get("/api/categories", categoryHandler.List)
+		r.Post("/api/categories", categoryHandler.Create)
+		r.Put("/api/categories/{id}", categoryHandler.Update)
+		r.Post("/api/categories/{id}/merge", categoryHandler.Merge)
+
+		// Tags
+		r.Get("/api/tags", tagHandler.List)
+		r.Post("/api/tags", tagHandler.Create)
+		r.Get("/api/tags/autocomplete", tagHandler.Autocomplete)
+		r.Put("/api/tags/{id}", tagHandler.Update)
+		r.Delete("/api/tags/{id}", tagHandler.Delete)
+
+		// Categorization rules
+		r.Get("/api/rules", ruleHandler.List)
+		r.Post("/api/rules", ruleHandler.Create)
+		r.Get("/api/rules/suggestions", ruleHandler.Suggest)
+		r.Post("/api/rules/apply", ruleHandler.Reapply)
+		r.Put("/api/rules/{id}", ruleHandler.Update)
+		r.Delete("/api/rules/{id}", ruleHandler.Delete)
+
+		// Duplicate review
+		r.Get("/api/duplicates", duplicateHandler.Queue)
+		r.Post("/api/duplicates/merge", duplicateHandler.Merge)
+		r.Post("/api/duplicates/dismiss", duplicateHandler.Dismiss)
+		r.Post("/api/duplicates/not-duplicate", duplicateHandler.NotDuplicate)
+
+		// Attachments
+		r.Get("/api/attachments/{id}/download", attachmentHandler.Download)
+		r.Delete("/api/attachments/{id}", attachmentHandler.Delete)
+
+		// Imports
+		r.Get("/api/imports", importHandler.List)
+		r.Post("/api/imports/csv", importHandler.UploadCSV)
+		r.Post("/api/imports/statement", importHandler.UploadStatement)
+		r.Post("/api/imports/migrations", migrationHandler.Import)
+		r.Post("/api/imports/{id}/preview", importHandler.Preview)
+		r.Post("/api/imports/{id}/commit", importHandler.Commit)
+		r.Post("/api/imports/{id}/undo", importHandler.Undo)
 
 		// Budgets
 		r.Get("/api/budgets", budgetHandler.List)
@@ -216,6 +384,7 @@ func main() {
 		r.Get("/api/budgets/{id}", budgetHandler.Get)
 		r.Put("/api/budgets/{id}", budgetHandler.Update)
 		r.Delete("/api/budgets/{id}", budgetHandler.Delete)
+		r.Get("/api/budgets/{id}/rollovers", budgetHandler.ListRollovers)
 
 		// Savings Goals
 		r.Get("/api/savings-goals", savingsHandler.List)
@@ -224,6 +393,9 @@ func main() {
 		r.Put("/api/savings-goals/{id}", savingsHandler.Update)
 		r.Delete("/api/savings-goals/{id}", savingsHandler.Delete)
 		r.Post("/api/savings-goals/{id}/contribute", savingsHandler.Contribute)
+		r.Post("/api/savings-goals/{id}/withdraw", savingsHandler.Withdraw)
+		r.Get("/api/savings-goals/{id}/contributions", savingsHandler.ListContributions)
+		r.Post("/api/savings-goals/{id}/contributions/{contributionId}/reverse", savingsHandler.ReverseContribution)
 
 		// Debt Management
 		r.Get("/api/debts", debtHandler.List)
@@ -234,6 +406,9 @@ func main() {
 		r.Put("/api/debts/{id}", debtHandler.Update)
 		r.Delete("/api/debts/{id}", debtHandler.Delete)
 		r.Post("/api/debts/{id}/payment", debtHandler.MakePayment)
+		r.Get("/api/debts/{id}/payments", debtHandler.ListPayments)
+		r.Get("/api/debts/{id}/payments/{paymentId}/attachments", attachmentHandler.ListForDebtPayment)
+		r.Post("/api/debts/{id}/payments/{paymentId}/attachments", attachmentHandler.UploadForDebtPayment)
 		r.Get("/api/debts/{id}/payoff-plan", debtHandler.GetPayoffPlan)
 
 		// Recurring Transactions
@@ -250,36 +425,121 @@ func main() {
 		// Reports
 		r.Get("/api/reports/monthly", reportHandler.GetMonthlyReport)
 		r.Get("/api/reports/category-trends", reportHandler.GetCategoryTrends)
+		r.Get("/api/reports/tags", reportHandler.GetTagBreakdown)
 		r.Get("/api/reports/monthly/{year}/{month}/export/pdf", exportHandler.ExportMonthlyReportPDF)
 
 		// AI Chat
 		r.Post("/api/chat", aiHandler.Chat)
 
+		// Interest Rate Subscriptions
+		r.Get("/api/interest-rates/subscriptions", rateSubscriptionHandler.List)
+		r.Post("/api/interest-rates/subscriptions", rateSubscriptionHandler.Create)
+		r.Get("/api/interest-rates/subscriptions/history", rateSubscriptionHandler.History)
+		r.Get("/api/interest-rates/subscriptions/{id}", rateSubscriptionHandler.Get)
+		r.Put("/api/interest-rates/subscriptions/{id}", rateSubscriptionHandler.Update)
+		r.Delete("/api/interest-rates/subscriptions/{id}", rateSubscriptionHandler.Delete)
+
 		// Push Notifications
 		r.Post("/api/notifications/subscribe", pushHandler.Subscribe)
 		r.Delete("/api/notifications/unsubscribe", pushHandler.Unsubscribe)
 		r.Get("/api/notifications/preferences", pushHandler.GetPreferences)
 		r.Put("/api/notifications/preferences", pushHandler.UpdatePreferences)
+
+		// Notification Inbox
+		r.Get("/api/notifications", notificationInboxHandler.List)
+		r.Get("/api/notifications/unread-count", notificationInboxHandler.UnreadCount)
+		r.Post("/api/notifications/read-all", notificationInboxHandler.MarkAllRead)
+		r.Post("/api/notifications/{id}/read", notificationInboxHandler.MarkRead)
+		r.Delete("/api/notifications/{id}", notificationInboxHandler.Delete)
 	})
 
-	// Initialize and start scheduler for interest rate scraping
-	var scraperScheduler *scheduler.Scheduler
-	if cfg.ScraperEnabled {
-		schedCfg := scheduler.Config{
+	// Register background jobs and start the scheduler
+	jobScheduler := scheduler.New(repository.NewJobRunRepository(db), logger)
+	jobs := []scheduler.Job{
+		{
+			Name:     "interest_rate_scraper",
 			Schedule: cfg.ScraperSchedule,
 			Timeout:  cfg.ScraperTimeout,
 			Enabled:  cfg.ScraperEnabled,
-		}
-		scraperScheduler = scheduler.New(schedCfg, interestRateService, logger)
-		if err := scraperScheduler.Start(); err != nil {
-			logger.Error("Failed to start scraper scheduler", slog.String("error", err.Error()))
-		} else {
-			logger.Info("Scraper scheduler started",
-				slog.String("schedule", cfg.ScraperSchedule),
-				slog.Duration("timeout", cfg.ScraperTimeout),
-			)
+			Run:      interestRateService.ScrapeAndUpdateRates,
+		},
+		{
+			Name:     "recurring_transactions",
+			Schedule: cfg.RecurringJob.Schedule,
+			Timeout:  cfg.RecurringJob.Timeout,
+			Enabled:  cfg.RecurringJob.Enabled,
+			Run:      recurringService.ProcessDueTransactions,
+		},
+		{
+			Name:     "bill_reminders",
+			Schedule: cfg.BillReminderJob.Schedule,
+			Timeout:  cfg.BillReminderJob.Timeout,
+			Enabled:  cfg.BillReminderJob.Enabled && pushService.IsConfigured(),
+			Run:      pushService.SendDueBillReminders,
+		},
+		{
+			Name:     "debt_reminders",
+			Schedule: cfg.DebtReminderJob.Schedule,
+			Timeout:  cfg.DebtReminderJob.Timeout,
+			Enabled:  cfg.DebtReminderJob.Enabled && pushService.IsConfigured(),
+			Run:      pushService.SendDueDebtReminders,
+		},
+		{
+			Name:     "budget_rollovers",
+			Schedule: cfg.BudgetRolloverJob.Schedule,
+			Timeout:  cfg.BudgetRolloverJob.Timeout,
+			Enabled:  cfg.BudgetRolloverJob.Enabled,
+			Run:      budgetService.ProcessRollovers,
+		},
+		{
+			Name:     "weekly_summaries",
+			Schedule: cfg.WeeklySummaryJob.Schedule,
+			Timeout:  cfg.WeeklySummaryJob.Timeout,
+			Enabled:  cfg.WeeklySummaryJob.Enabled && pushService.IsConfigured(),
+			Run:      weeklySummaryService.SendWeeklySummaries,
+		},
+		{
+			Name:     "email_outbox",
+			Schedule: cfg.EmailOutboxJob.Schedule,
+			Timeout:  cfg.EmailOutboxJob.Timeout,
+			Enabled:  cfg.EmailOutboxJob.Enabled && cfg.IsEmailConfigured(),
+			Run:      emailService.ProcessOutbox,
+		},
+		{
+			Name:     "attachment_cleanup",
+			Schedule: cfg.AttachmentCleanupJob.Schedule,
+			Timeout:  cfg.AttachmentCleanupJob.Timeout,
+			Enabled:  cfg.AttachmentCleanupJob.Enabled,
+			Run:      attachmentService.PurgeDeletedBlobs,
+		},
+		{
+			Name:     "exchange_rate_scraper",
+			Schedule: cfg.ExchangeRateScraperJob.Schedule,
+			Timeout:  cfg.ExchangeRateScraperJob.Timeout,
+			Enabled:  cfg.ExchangeRateScraperJob.Enabled,
+			Run:      bankExchangeRateService.ScrapeAndUpdateRates,
+		},
+		{
+			Name:     "currency_conversion",
+			Schedule: cfg.CurrencyConversionJob.Schedule,
+			Timeout:  cfg.CurrencyConversionJob.Timeout,
+			Enabled:  cfg.CurrencyConversionJob.Enabled,
+			Run:      exchangeRateService.ConvertPending,
+		},
+		{
+			Name:     "import_cleanup",
+			Schedule: cfg.ImportCleanupJob.Schedule,
+			Timeout:  cfg.ImportCleanupJob.Timeout,
+			Enabled:  cfg.ImportCleanupJob.Enabled,
+			Run:      importService.PurgeStaleImports,
+		},
+	}
+	for _, job := range jobs {
+		if err := jobScheduler.Register(job); err != nil {
+			logger.Error("Failed to register job", slog.String("job", job.Name), slog.String("error", err.Error()))
 		}
 	}
+	jobScheduler.Start()
 
 	port := cfg.Port
 	if port == "" {
@@ -300,12 +560,9 @@ func main() {
 
 		logger.Info("Shutting down server...")
 
-		// Stop scheduler first
-		if scraperScheduler != nil {
-			ctx := scraperScheduler.Stop()
-			<-ctx.Done()
-			logger.Info("Scheduler stopped")
-		}
+		// Stop scheduler first and wait for running jobs
+		<-jobScheduler.Stop().Done()
+		logger.Info("Scheduler stopped")
 
 		// Shutdown HTTP server
 		if err := server.Shutdown(context.Background()); err != nil {
@@ -94,6 +94,14 @@ func Conflict(message string) *AppError {
 	}
 }
 
+func PayloadTooLarge(message string) *AppError {
+	return &AppError{
+		Err:        ErrBadRequest,
+		Message:    message,
+		StatusCode: http.StatusRequestEntityTooLarge,
+	}
+}
+
 func Internal(err error) *AppError {
 	return &AppError{
 		Err:        err,
@@ -158,6 +158,16 @@ func TestConflict(t *testing.T) {
 	assert.True(t, errors.Is(err, ErrConflict))
 }
 
+func TestPayloadTooLarge(t *testing.T) {
+	t.Parallel()
+
+	err := PayloadTooLarge("file is too large")
+
+	assert.Equal(t, "file is too large", err.Message)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, err.StatusCode)
+	assert.True(t, errors.Is(err, ErrBadRequest))
+}
+
 func TestInternal(t *testing.T) {
 	t.Parallel()
 
@@ -15,6 +15,13 @@ type CookieConfig struct {
 	Path     string // Cookie path
 }
 
+// JobConfig holds scheduling settings for a background job.
+type JobConfig struct {
+	Enabled  bool
+	Schedule string        // Cron expression (5 fields)
+	Timeout  time.Duration // Maximum duration of a single run
+}
+
 type Config struct {
 	// Server
 	Port string
@@ -53,10 +60,51 @@ type Config struct {
 	ScraperSchedule string        // Cron expression (e.g., "0 * * * *" for hourly)
 	ScraperTimeout  time.Duration // Timeout for complete scrape cycle
 
+	// Background jobs
+	RecurringJob      JobConfig // Generates transactions from due recurring templates
+	BillReminderJob   JobConfig // Push reminders for upcoming recurring bills
+	DebtReminderJob   JobConfig // Push reminders for upcoming debt payments
+	BudgetRolloverJob JobConfig // Closes finished budget periods and carries the remainder over
+	WeeklySummaryJob  JobConfig // Pushes last week's summary to opted-in users
+
+	// Interest rate alerts
+	RateAlertCooldown time.Duration // Minimum time between two alerts for the same subscription
+
+	// Email (SMTP). Email delivery is disabled when SMTPHost is empty.
+	SMTPHost         string
+	SMTPPort         int
+	SMTPUsername     string
+	SMTPPassword     string
+	SMTPFrom         string    // Sender address
+	SMTPFromName     string    // Sender display name
+	EmailMaxAttempts int       // Delivery attempts before an outbox email is marked failed
+	EmailOutboxJob   JobConfig // Delivers queued email from the outbox
+
 	// Web Push Notifications
 	VAPIDPublicKey  string
 	VAPIDPrivateKey string
 	VAPIDSubject    string // mailto:email or URL
+
+	// Attachments (receipts and invoices)
+	AttachmentStorage    string    // "local" or "s3"
+	AttachmentDir        string    // Root directory for local storage
+	AttachmentMaxSizeMB  int       // Largest accepted upload
+	AttachmentCleanupJob JobConfig // Deletes the files of deleted attachments
+
+	// S3-compatible attachment storage
+	S3Endpoint        string // Empty = AWS S3 in S3Region
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3UsePathStyle    bool // Required by MinIO and most self-hosted servers
+
+	// Transaction imports
+	ImportCleanupJob JobConfig // Removes uploads that were never committed
+
+	// Multi-currency
+	CurrencyConversionJob  JobConfig // Converts transactions to their owner's currency once rates are known
+	ExchangeRateScraperJob JobConfig // Scrapes bank exchange rates used for conversion
 }
 
 func Load() *Config {
@@ -106,10 +154,51 @@ func Load() *Config {
 		ScraperSchedule: getEnv("SCRAPER_SCHEDULE", "0 * * * *"), // Default: hourly at minute 0
 		ScraperTimeout:  getDurationEnv("SCRAPER_TIMEOUT", 5*time.Minute),
 
+		// Background jobs
+		RecurringJob:      getJobConfig("RECURRING_JOB", "0 1 * * *", 5*time.Minute),        // Daily at 01:00
+		BillReminderJob:   getJobConfig("BILL_REMINDER_JOB", "0 9 * * *", 5*time.Minute),    // Daily at 09:00
+		DebtReminderJob:   getJobConfig("DEBT_REMINDER_JOB", "5 9 * * *", 5*time.Minute),    // Daily at 09:05
+		BudgetRolloverJob: getJobConfig("BUDGET_ROLLOVER_JOB", "5 0 * * *", 10*time.Minute), // Daily at 00:05
+		WeeklySummaryJob:  getJobConfig("WEEKLY_SUMMARY_JOB", "0 8 * * 1", 10*time.Minute),  // Mondays at 08:00
+
+		// Interest rate alerts
+		RateAlertCooldown: getDurationEnv("RATE_ALERT_COOLDOWN", 12*time.Hour),
+
+		// Email (SMTP)
+		SMTPHost:         os.Getenv("SMTP_HOST"),
+		SMTPPort:         getIntEnv("SMTP_PORT", 587),
+		SMTPUsername:     os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:     os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:         getEnv("SMTP_FROM", "notifications@wealthpath.app"),
+		SMTPFromName:     getEnv("SMTP_FROM_NAME", "WealthPath"),
+		EmailMaxAttempts: getIntEnv("EMAIL_MAX_ATTEMPTS", 6),
+		EmailOutboxJob:   getJobConfig("EMAIL_OUTBOX_JOB", "* * * * *", 2*time.Minute), // Every minute
+
 		// Web Push Notifications
 		VAPIDPublicKey:  os.Getenv("VAPID_PUBLIC_KEY"),
 		VAPIDPrivateKey: os.Getenv("VAPID_PRIVATE_KEY"),
 		VAPIDSubject:    getEnv("VAPID_SUBJECT", "mailto:notifications@wealthpath.app"),
+
+		// Attachments
+		AttachmentStorage:    getEnv("ATTACHMENT_STORAGE", "local"),
+		AttachmentDir:        getEnv("ATTACHMENT_DIR", "./data/attachments"),
+		AttachmentMaxSizeMB:  getIntEnv("ATTACHMENT_MAX_SIZE_MB", 10),
+		AttachmentCleanupJob: getJobConfig("ATTACHMENT_CLEANUP_JOB", "*/10 * * * *", 5*time.Minute), // Every 10 minutes
+
+		// S3-compatible attachment storage
+		S3Endpoint:        os.Getenv("S3_ENDPOINT"),
+		S3Region:          getEnv("S3_REGION", "ap-southeast-1"),
+		S3Bucket:          os.Getenv("S3_BUCKET"),
+		S3AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+		S3SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		S3UsePathStyle:    getBoolEnv("S3_USE_PATH_STYLE", false),
+
+		// Transaction imports
+		ImportCleanupJob: getJobConfig("IMPORT_CLEANUP_JOB", "30 * * * *", 5*time.Minute), // Every hour
+
+		// Multi-currency
+		CurrencyConversionJob:  getJobConfig("CURRENCY_CONVERSION_JOB", "15 * * * *", 10*time.Minute), // Every hour
+		ExchangeRateScraperJob: getJobConfig("EXCHANGE_RATE_SCRAPER_JOB", "5 * * * *", 5*time.Minute), // Every hour, before conversion
 	}
 }
 
@@ -121,6 +210,11 @@ func (c *Config) IsProduction() bool {
 	return c.Env == "production"
 }
 
+// IsEmailConfigured returns true if an SMTP server is configured.
+func (c *Config) IsEmailConfigured() bool {
+	return c.SMTPHost != ""
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -137,6 +231,15 @@ func getBoolEnv(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+func getIntEnv(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if d, err := time.ParseDuration(value); err == nil {
@@ -145,3 +248,12 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+// getJobConfig reads <PREFIX>_ENABLED, <PREFIX>_SCHEDULE and <PREFIX>_TIMEOUT.
+func getJobConfig(prefix, defaultSchedule string, defaultTimeout time.Duration) JobConfig {
+	return JobConfig{
+		Enabled:  getBoolEnv(prefix+"_ENABLED", true),
+		Schedule: getEnv(prefix+"_SCHEDULE", defaultSchedule),
+		Timeout:  getDurationEnv(prefix+"_TIMEOUT", defaultTimeout),
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -124,3 +125,28 @@ func TestGetBoolEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestGetJobConfig(t *testing.T) {
+	t.Setenv("TEST_JOB_ENABLED", "false")
+	t.Setenv("TEST_JOB_SCHEDULE", "*/5 * * * *")
+	t.Setenv("TEST_JOB_TIMEOUT", "30s")
+
+	job := getJobConfig("TEST_JOB", "0 1 * * *", time.Minute)
+	assert.False(t, job.Enabled)
+	assert.Equal(t, "*/5 * * * *", job.Schedule)
+	assert.Equal(t, 30*time.Second, job.Timeout)
+
+	defaults := getJobConfig("UNSET_JOB", "0 1 * * *", time.Minute)
+	assert.True(t, defaults.Enabled)
+	assert.Equal(t, "0 1 * * *", defaults.Schedule)
+	assert.Equal(t, time.Minute, defaults.Timeout)
+}
+
+func TestGetIntEnv(t *testing.T) {
+	t.Setenv("TEST_INT", "465")
+	t.Setenv("TEST_INT_INVALID", "abc")
+
+	assert.Equal(t, 465, getIntEnv("TEST_INT", 587))
+	assert.Equal(t, 587, getIntEnv("TEST_INT_INVALID", 587))
+	assert.Equal(t, 587, getIntEnv("NON_EXISTENT_INT", 587))
+}
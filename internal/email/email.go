@@ -0,0 +1,34 @@
+// Package email renders localized email templates and delivers messages over SMTP.
+package email
+
+import "strings"
+
+// Supported locales
+const (
+	LocaleVietnamese = "vi"
+	LocaleEnglish    = "en"
+
+	// DefaultLocale is used when a template has no variant for the requested locale
+	DefaultLocale = LocaleVietnamese
+)
+
+// Message is a fully rendered email ready for delivery
+type Message struct {
+	To       string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
+
+// NormalizeLocale maps a language tag such as "en-US" or "vi_VN" to a supported locale
+func NormalizeLocale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	switch {
+	case strings.HasPrefix(locale, LocaleEnglish):
+		return LocaleEnglish
+	case strings.HasPrefix(locale, LocaleVietnamese):
+		return LocaleVietnamese
+	default:
+		return DefaultLocale
+	}
+}
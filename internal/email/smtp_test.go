@@ -0,0 +1,103 @@
+package email
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/email/smtptest"
+)
+
+func newTestTransport(t *testing.T, srv *smtptest.Server) *SMTPTransport {
+	t.Helper()
+	return NewSMTPTransport(SMTPConfig{
+		Host:     srv.Host(),
+		Port:     srv.Port(),
+		Username: "user",
+		Password: "secret",
+		From:     "notifications@wealthpath.app",
+		FromName: "WealthPath",
+	})
+}
+
+func TestSMTPTransport_Deliver_Multipart(t *testing.T) {
+	t.Parallel()
+
+	srv, err := smtptest.NewServer()
+	require.NoError(t, err)
+	defer srv.Close()
+
+	transport := newTestTransport(t, srv)
+	err = transport.Deliver(context.Background(), &Message{
+		To:       "an@example.com",
+		Subject:  "Lãi suất đã tăng",
+		TextBody: "Xin chào An",
+		HTMLBody: "<p>Xin chào An</p>",
+	})
+	require.NoError(t, err)
+
+	msgs := srv.Messages()
+	require.Len(t, msgs, 1)
+	assert.Equal(t, "notifications@wealthpath.app", msgs[0].From)
+	assert.Equal(t, []string{"an@example.com"}, msgs[0].To)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(msgs[0].Data))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Lãi suất đã tăng", subject)
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	var bodies []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		b, err := io.ReadAll(part)
+		require.NoError(t, err)
+		bodies = append(bodies, string(b))
+	}
+	assert.Equal(t, []string{"Xin chào An", "<p>Xin chào An</p>"}, bodies)
+}
+
+func TestSMTPTransport_Deliver_Rejected(t *testing.T) {
+	t.Parallel()
+
+	srv, err := smtptest.NewServer()
+	require.NoError(t, err)
+	defer srv.Close()
+
+	srv.RejectNext(1)
+	transport := newTestTransport(t, srv)
+
+	err = transport.Deliver(context.Background(), &Message{To: "an@example.com", Subject: "Hi", TextBody: "Hello"})
+	assert.Error(t, err)
+	assert.Empty(t, srv.Messages())
+
+	err = transport.Deliver(context.Background(), &Message{To: "an@example.com", Subject: "Hi", TextBody: "Hello"})
+	assert.NoError(t, err)
+	assert.Len(t, srv.Messages(), 1)
+}
+
+func TestSMTPTransport_Deliver_Validation(t *testing.T) {
+	t.Parallel()
+
+	unconfigured := NewSMTPTransport(SMTPConfig{})
+	assert.ErrorIs(t, unconfigured.Deliver(context.Background(), &Message{To: "an@example.com"}), ErrNotConfigured)
+
+	transport := NewSMTPTransport(SMTPConfig{Host: "127.0.0.1", Port: 1})
+	assert.Error(t, transport.Deliver(context.Background(), &Message{To: "not-an-address"}))
+}
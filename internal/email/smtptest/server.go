@@ -0,0 +1,187 @@
+// Package smtptest provides an in-process SMTP server for tests.
+package smtptest
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Message is an email received by the test server
+type Message struct {
+	From string
+	To   []string
+	Data string // Raw message including headers
+}
+
+// Server is a minimal SMTP server that accepts every message and keeps it in memory.
+// It supports EHLO/HELO, AUTH PLAIN, MAIL, RCPT, DATA, RSET, NOOP and QUIT.
+type Server struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	messages []Message
+	rejects  int // number of upcoming messages to reject at DATA
+	wg       sync.WaitGroup
+}
+
+// NewServer starts a server listening on a random local port
+func NewServer() (*Server, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{listener: ln}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Host returns the host the server listens on
+func (s *Server) Host() string {
+	host, _, _ := net.SplitHostPort(s.listener.Addr().String())
+	return host
+}
+
+// Port returns the port the server listens on
+func (s *Server) Port() int {
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return p
+}
+
+// Messages returns a copy of the messages received so far
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// RejectNext makes the server reject the next n messages with a temporary failure
+func (s *Server) RejectNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rejects = n
+}
+
+// Close stops the server
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	reply := func(line string) {
+		_, _ = w.WriteString(line + "\r\n")
+		_ = w.Flush()
+	}
+
+	reply("220 smtptest ready")
+
+	var current Message
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(line)
+		if i := strings.IndexByte(verb, ' '); i >= 0 {
+			verb = verb[:i]
+		}
+
+		switch verb {
+		case "EHLO":
+			reply("250-smtptest")
+			reply("250 AUTH PLAIN")
+		case "HELO":
+			reply("250 smtptest")
+		case "AUTH":
+			reply("235 2.7.0 Authentication successful")
+		case "MAIL":
+			current = Message{From: addressArg(line)}
+			reply("250 OK")
+		case "RCPT":
+			current.To = append(current.To, addressArg(line))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dl, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dl == ".\r\n" || dl == ".\n" {
+					break
+				}
+				// Undo dot-stuffing
+				dl = strings.TrimPrefix(dl, ".")
+				data.WriteString(dl)
+			}
+			current.Data = data.String()
+
+			s.mu.Lock()
+			reject := s.rejects > 0
+			if reject {
+				s.rejects--
+			} else {
+				s.messages = append(s.messages, current)
+			}
+			s.mu.Unlock()
+
+			if reject {
+				reply("451 4.3.0 Temporary failure")
+			} else {
+				reply("250 OK: queued")
+			}
+			current = Message{}
+		case "RSET":
+			current = Message{}
+			reply("250 OK")
+		case "NOOP":
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+// addressArg extracts the address from "MAIL FROM:<a@b>" or "RCPT TO:<a@b>"
+func addressArg(line string) string {
+	start := strings.IndexByte(line, '<')
+	end := strings.IndexByte(line, '>')
+	if start >= 0 && end > start {
+		return line[start+1 : end]
+	}
+	if i := strings.IndexByte(line, ':'); i >= 0 {
+		return strings.TrimSpace(line[i+1:])
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Template names
+const (
+	TemplateRateAlert = "rate_alert"
+)
+
+//go:embed templates/*
+var templateFS embed.FS
+
+// ErrTemplateNotFound is returned when no variant of a template exists
+var ErrTemplateNotFound = errors.New("email template not found")
+
+// RateAlertData is the data for the rate_alert template
+type RateAlertData struct {
+	Name       string
+	BankName   string
+	TermMonths int
+	OldRate    string
+	NewRate    string
+	Change     string
+	Increased  bool
+}
+
+// templateSet holds the parsed variants of a template for one locale.
+// Each template is made of two files:
+//
+//	<name>.<locale>.txt  defines "subject" and "text"
+//	<name>.<locale>.html defines "content" and "footer", rendered inside layout.html
+type templateSet struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// Renderer renders localized email templates into messages
+type Renderer struct {
+	sets map[string]templateSet // keyed by "<name>.<locale>"
+}
+
+// NewRenderer parses the embedded templates. appURL is the frontend base URL
+// used by the appURL template function to build links.
+func NewRenderer(appURL string) (*Renderer, error) {
+	return newRenderer(templateFS, "templates", appURL)
+}
+
+func newRenderer(fsys fs.FS, dir, appURL string) (*Renderer, error) {
+	appURL = strings.TrimRight(appURL, "/")
+	funcs := map[string]any{
+		"appURL": func(path string) string { return appURL + path },
+	}
+
+	layout, err := fs.ReadFile(fsys, dir+"/layout.html")
+	if err != nil {
+		return nil, fmt.Errorf("read layout: %w", err)
+	}
+
+	textFiles, err := fs.Glob(fsys, dir+"/*.txt")
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Renderer{sets: make(map[string]templateSet)}
+	for _, textFile := range textFiles {
+		key := strings.TrimSuffix(strings.TrimPrefix(textFile, dir+"/"), ".txt")
+
+		textSrc, err := fs.ReadFile(fsys, textFile)
+		if err != nil {
+			return nil, err
+		}
+		text, err := texttemplate.New(key).Funcs(funcs).Option("missingkey=error").Parse(string(textSrc))
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", textFile, err)
+		}
+
+		htmlSrc, err := fs.ReadFile(fsys, dir+"/"+key+".html")
+		if err != nil {
+			return nil, fmt.Errorf("template %s has no html variant: %w", key, err)
+		}
+		html, err := htmltemplate.New("layout").Funcs(funcs).Option("missingkey=error").Parse(string(layout))
+		if err != nil {
+			return nil, fmt.Errorf("parse layout: %w", err)
+		}
+		if html, err = html.Parse(string(htmlSrc)); err != nil {
+			return nil, fmt.Errorf("parse %s.html: %w", key, err)
+		}
+
+		r.sets[key] = templateSet{text: text, html: html}
+	}
+
+	return r, nil
+}
+
+// Render renders the named template for the locale, falling back to DefaultLocale
+func (r *Renderer) Render(name, locale string, data any) (*Message, error) {
+	locale = NormalizeLocale(locale)
+	set, ok := r.sets[name+"."+locale]
+	if !ok {
+		locale = DefaultLocale
+		if set, ok = r.sets[name+"."+locale]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+		}
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := set.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("render %s subject: %w", name, err)
+	}
+	if err := set.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return nil, fmt.Errorf("render %s text: %w", name, err)
+	}
+
+	layoutData := struct {
+		Locale  string
+		Subject string
+		Data    any
+	}{locale, strings.TrimSpace(subject.String()), data}
+	if err := set.html.ExecuteTemplate(&html, "layout", layoutData); err != nil {
+		return nil, fmt.Errorf("render %s html: %w", name, err)
+	}
+
+	return &Message{
+		Subject:  layoutData.Subject,
+		TextBody: strings.TrimSpace(text.String()) + "\n",
+		HTMLBody: html.String(),
+	}, nil
+}
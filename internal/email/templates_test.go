@@ -0,0 +1,79 @@
+package email
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"en", LocaleEnglish},
+		{"en-US", LocaleEnglish},
+		{"vi_VN", LocaleVietnamese},
+		{"", DefaultLocale},
+		{"fr", DefaultLocale},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, NormalizeLocale(tt.in))
+		})
+	}
+}
+
+func TestRenderer_RateAlert(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewRenderer("https://wealthpath.app/")
+	require.NoError(t, err)
+
+	data := RateAlertData{
+		Name:       "An <script>",
+		BankName:   "Techcombank",
+		TermMonths: 12,
+		OldRate:    "4.80",
+		NewRate:    "5.00",
+		Change:     "0.20",
+		Increased:  true,
+	}
+
+	vi, err := r.Render(TemplateRateAlert, "vi", data)
+	require.NoError(t, err)
+	assert.Equal(t, "📈 Lãi suất Techcombank đã tăng", vi.Subject)
+	assert.Contains(t, vi.TextBody, "kỳ hạn 12 tháng đã tăng")
+	assert.Contains(t, vi.TextBody, "Lãi suất mới: 5.00%")
+	assert.Contains(t, vi.TextBody, "https://wealthpath.app/interest-rates")
+	assert.Contains(t, vi.HTMLBody, `lang="vi"`)
+	assert.Contains(t, vi.HTMLBody, "An &lt;script&gt;")
+	assert.NotContains(t, vi.HTMLBody, "<script>")
+
+	data.Increased = false
+	en, err := r.Render(TemplateRateAlert, "en-US", data)
+	require.NoError(t, err)
+	assert.Equal(t, "📉 Techcombank interest rate went down", en.Subject)
+	assert.Contains(t, en.TextBody, "12-month Techcombank interest rate has decreased")
+	assert.Contains(t, en.HTMLBody, "Personal finance")
+}
+
+func TestRenderer_Fallback(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewRenderer("https://wealthpath.app/")
+	require.NoError(t, err)
+
+	msg, err := r.Render(TemplateRateAlert, "de", RateAlertData{BankName: "ACB", Increased: true})
+	require.NoError(t, err)
+	assert.Contains(t, msg.Subject, "Lãi suất ACB")
+
+	_, err = r.Render("missing", "vi", nil)
+	assert.ErrorIs(t, err, ErrTemplateNotFound)
+}
@@ -0,0 +1,382 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/wealthpath/backend/internal/apperror"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// AccountServiceInterface defines the service contract for accounts and transfers.
+type AccountServiceInterface interface {
+	Create(ctx context.Context, userID uuid.UUID, input service.CreateAccountInput) (*model.Account, error)
+	Get(ctx context.Context, userID, id uuid.UUID) (*model.AccountWithBalance, error)
+	List(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]model.AccountWithBalance, error)
+	Update(ctx context.Context, userID, id uuid.UUID, input service.UpdateAccountInput) (*model.Account, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	CreateTransfer(ctx context.Context, userID uuid.UUID, input service.CreateTransferInput) (*model.Transfer, error)
+	GetTransfer(ctx context.Context, userID, id uuid.UUID) (*model.Transfer, error)
+	ListTransfers(ctx context.Context, userID uuid.UUID, input service.ListTransfersInput) ([]model.Transfer, error)
+	DeleteTransfer(ctx context.Context, userID, id uuid.UUID) error
+}
+
+// AccountHandler handles HTTP requests for accounts (wallets) and transfers.
+type AccountHandler struct {
+	service AccountServiceInterface
+}
+
+// NewAccountHandler creates a new AccountHandler with the given service.
+func NewAccountHandler(service AccountServiceInterface) *AccountHandler {
+	return &AccountHandler{service: service}
+}
+
+// Create godoc
+// @Summary Create an account
+// @Description Create a cash, bank, credit card or e-wallet account with an opening balance
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body service.CreateAccountInput true "Account data"
+// @Success 201 {object} model.Account
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /accounts [post]
+func (h *AccountHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	var input service.CreateAccountInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondAppError(w, apperror.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	account, err := h.service.Create(r.Context(), userID, input)
+	if err != nil {
+		respondAppError(w, accountError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, account)
+}
+
+// Get godoc
+// @Summary Get an account
+// @Description Get an account with its current balance
+// @Tags accounts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Success 200 {object} model.AccountWithBalance
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /accounts/{id} [get]
+func (h *AccountHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid account ID"))
+		return
+	}
+
+	account, err := h.service.Get(r.Context(), userID, id)
+	if err != nil {
+		respondAppError(w, accountError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, account)
+}
+
+// List godoc
+// @Summary List accounts
+// @Description Get the current user's accounts with their current balances
+// @Tags accounts
+// @Produce json
+// @Security BearerAuth
+// @Param archived query bool false "Include archived accounts"
+// @Success 200 {array} model.AccountWithBalance
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /accounts [get]
+func (h *AccountHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	includeArchived := false
+	if archived := r.URL.Query().Get("archived"); archived != "" {
+		b, err := strconv.ParseBool(archived)
+		if err != nil {
+			respondAppError(w, apperror.BadRequest("invalid archived"))
+			return
+		}
+		includeArchived = b
+	}
+
+	accounts, err := h.service.List(r.Context(), userID, includeArchived)
+	if err != nil {
+		respondAppError(w, apperror.Internal(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, accounts)
+}
+
+// Update godoc
+// @Summary Update an account
+// @Description Update an account's details, or archive it
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Param input body service.UpdateAccountInput true "Account data"
+// @Success 200 {object} model.Account
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /accounts/{id} [put]
+func (h *AccountHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid account ID"))
+		return
+	}
+
+	var input service.UpdateAccountInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondAppError(w, apperror.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	account, err := h.service.Update(r.Context(), userID, id, input)
+	if err != nil {
+		respondAppError(w, accountError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, account)
+}
+
+// Delete godoc
+// @Summary Delete an account
+// @Description Delete an account. Its transactions are kept; accounts with transfers must be archived instead.
+// @Tags accounts
+// @Security BearerAuth
+// @Param id path string true "Account ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /accounts/{id} [delete]
+func (h *AccountHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid account ID"))
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), userID, id); err != nil {
+		respondAppError(w, accountError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateTransfer godoc
+// @Summary Transfer between accounts
+// @Description Move money between two of the current user's accounts. Transfers are not income or expenses.
+// @Tags transfers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body service.CreateTransferInput true "Transfer data"
+// @Success 201 {object} model.Transfer
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /transfers [post]
+func (h *AccountHandler) CreateTransfer(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	var input service.CreateTransferInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondAppError(w, apperror.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	transfer, err := h.service.CreateTransfer(r.Context(), userID, input)
+	if err != nil {
+		// An unknown account in the request body is a validation error, not a missing resource
+		if errors.Is(err, repository.ErrAccountNotFound) {
+			respondAppError(w, apperror.ValidationError("accountId", "account not found"))
+			return
+		}
+		respondAppError(w, accountError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, transfer)
+}
+
+// GetTransfer godoc
+// @Summary Get a transfer
+// @Tags transfers
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} model.Transfer
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /transfers/{id} [get]
+func (h *AccountHandler) GetTransfer(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid transfer ID"))
+		return
+	}
+
+	transfer, err := h.service.GetTransfer(r.Context(), userID, id)
+	if err != nil {
+		respondAppError(w, accountError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, transfer)
+}
+
+// ListTransfers godoc
+// @Summary List transfers
+// @Description Get the current user's transfers, newest first
+// @Tags transfers
+// @Produce json
+// @Security BearerAuth
+// @Param accountId query string false "Only transfers into or out of this account"
+// @Param startDate query string false "Filter by start date (YYYY-MM-DD)"
+// @Param endDate query string false "Filter by end date (YYYY-MM-DD)"
+// @Param limit query int false "Number of results" default(20)
+// @Param offset query int false "Number of results to skip" default(0)
+// @Success 200 {array} model.Transfer
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /transfers [get]
+func (h *AccountHandler) ListTransfers(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+	query := r.URL.Query()
+
+	var input service.ListTransfersInput
+	if accountID := query.Get("accountId"); accountID != "" {
+		id, err := uuid.Parse(accountID)
+		if err != nil {
+			respondAppError(w, apperror.BadRequest("invalid accountId"))
+			return
+		}
+		input.AccountID = &id
+	}
+	if startDate := query.Get("startDate"); startDate != "" {
+		t, err := time.Parse("2006-01-02", startDate)
+		if err != nil {
+			respondAppError(w, apperror.BadRequest("invalid startDate, expected YYYY-MM-DD"))
+			return
+		}
+		input.StartDate = &t
+	}
+	if endDate := query.Get("endDate"); endDate != "" {
+		t, err := time.Parse("2006-01-02", endDate)
+		if err != nil {
+			respondAppError(w, apperror.BadRequest("invalid endDate, expected YYYY-MM-DD"))
+			return
+		}
+		input.EndDate = &t
+	}
+	input.Limit, _ = strconv.Atoi(query.Get("limit"))
+	input.Offset, _ = strconv.Atoi(query.Get("offset"))
+
+	transfers, err := h.service.ListTransfers(r.Context(), userID, input)
+	if err != nil {
+		respondAppError(w, apperror.Internal(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, transfers)
+}
+
+// DeleteTransfer godoc
+// @Summary Delete a transfer
+// @Tags transfers
+// @Security BearerAuth
+// @Param id path string true "Transfer ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /transfers/{id} [delete]
+func (h *AccountHandler) DeleteTransfer(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid transfer ID"))
+		return
+	}
+
+	if err := h.service.DeleteTransfer(r.Context(), userID, id); err != nil {
+		respondAppError(w, accountError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// accountError maps service errors to API errors.
+func accountError(err error) *apperror.AppError {
+	switch {
+	case errors.Is(err, repository.ErrAccountNotFound):
+		return apperror.NotFound("account")
+	case errors.Is(err, repository.ErrTransferNotFound):
+		return apperror.NotFound("transfer")
+	case errors.Is(err, repository.ErrAccountInUse):
+		return apperror.Conflict("account has transfers; archive it instead")
+	case errors.Is(err, service.ErrAccountNameRequired):
+		return apperror.ValidationError("name", err.Error())
+	case errors.Is(err, service.ErrInvalidAccountType):
+		return apperror.ValidationError("type", err.Error())
+	case errors.Is(err, service.ErrInvalidAccountCurrency):
+		return apperror.ValidationError("currency", err.Error())
+	case errors.Is(err, service.ErrInvalidTransferAmount):
+		return apperror.ValidationError("amount", err.Error())
+	case errors.Is(err, service.ErrTransferSameAccount),
+		errors.Is(err, service.ErrAccountArchived),
+		errors.Is(err, service.ErrAccountCurrencyMismatch):
+		return apperror.ValidationError("accountId", err.Error())
+	default:
+		return apperror.Internal(err)
+	}
+}
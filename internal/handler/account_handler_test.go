@@ -0,0 +1,311 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// MockAccountService implements AccountServiceInterface for testing
+type MockAccountService struct {
+	mock.Mock
+}
+
+func (m *MockAccountService) Create(ctx context.Context, userID uuid.UUID, input service.CreateAccountInput) (*model.Account, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Account), args.Error(1)
+}
+
+func (m *MockAccountService) Get(ctx context.Context, userID, id uuid.UUID) (*model.AccountWithBalance, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AccountWithBalance), args.Error(1)
+}
+
+func (m *MockAccountService) List(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]model.AccountWithBalance, error) {
+	args := m.Called(ctx, userID, includeArchived)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AccountWithBalance), args.Error(1)
+}
+
+func (m *MockAccountService) Update(ctx context.Context, userID, id uuid.UUID, input service.UpdateAccountInput) (*model.Account, error) {
+	args := m.Called(ctx, userID, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Account), args.Error(1)
+}
+
+func (m *MockAccountService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockAccountService) CreateTransfer(ctx context.Context, userID uuid.UUID, input service.CreateTransferInput) (*model.Transfer, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Transfer), args.Error(1)
+}
+
+func (m *MockAccountService) GetTransfer(ctx context.Context, userID, id uuid.UUID) (*model.Transfer, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Transfer), args.Error(1)
+}
+
+func (m *MockAccountService) ListTransfers(ctx context.Context, userID uuid.UUID, input service.ListTransfersInput) ([]model.Transfer, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Transfer), args.Error(1)
+}
+
+func (m *MockAccountService) DeleteTransfer(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func TestAccountHandler_Create(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{
+			name:       "success",
+			body:       `{"name":"MoMo","type":"e_wallet","currency":"VND","openingBalance":"250000"}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "invalid body",
+			body:       `{`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid type",
+			body:       `{"name":"Stocks","type":"brokerage"}`,
+			serviceErr: service.ErrInvalidAccountType,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "service error",
+			body:       `{"name":"Cash","type":"cash"}`,
+			serviceErr: errors.New("db error"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockAccountService)
+			handler := NewAccountHandler(mockService)
+			userID := uuid.New()
+
+			if tt.wantStatus == http.StatusCreated {
+				mockService.On("Create", mock.Anything, userID, service.CreateAccountInput{
+					Name: "MoMo", Type: model.AccountTypeEWallet, Currency: "VND", OpeningBalance: decimal.NewFromInt(250000),
+				}).Return(&model.Account{ID: uuid.New(), Name: "MoMo"}, nil)
+			} else if tt.serviceErr != nil {
+				mockService.On("Create", mock.Anything, userID, mock.Anything).Return(nil, tt.serviceErr)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/accounts", bytes.NewBufferString(tt.body))
+			req = req.WithContext(ctxWithUserID(userID))
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccountHandler_List(t *testing.T) {
+	t.Parallel()
+
+	mockService := new(MockAccountService)
+	handler := NewAccountHandler(mockService)
+	userID := uuid.New()
+
+	mockService.On("List", mock.Anything, userID, true).Return([]model.AccountWithBalance{
+		{Account: model.Account{Name: "Vietcombank", Type: model.AccountTypeBank}, Balance: decimal.NewFromInt(1000)},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/accounts?archived=true", nil)
+	req = req.WithContext(ctxWithUserID(userID))
+	w := httptest.NewRecorder()
+
+	handler.List(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var accounts []model.AccountWithBalance
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&accounts))
+	assert.Len(t, accounts, 1)
+	assert.True(t, decimal.NewFromInt(1000).Equal(accounts[0].Balance))
+	mockService.AssertExpectations(t)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/accounts?archived=maybe", nil)
+	req = req.WithContext(ctxWithUserID(userID))
+	w = httptest.NewRecorder()
+
+	handler.List(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAccountHandler_Delete(t *testing.T) {
+	t.Parallel()
+
+	accountID := uuid.New()
+
+	tests := []struct {
+		name       string
+		id         string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", id: accountID.String(), wantStatus: http.StatusNoContent},
+		{name: "invalid id", id: "abc", wantStatus: http.StatusBadRequest},
+		{name: "not found", id: accountID.String(), serviceErr: repository.ErrAccountNotFound, wantStatus: http.StatusNotFound},
+		{name: "has transfers", id: accountID.String(), serviceErr: repository.ErrAccountInUse, wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockAccountService)
+			handler := NewAccountHandler(mockService)
+			userID := uuid.New()
+
+			if id, err := uuid.Parse(tt.id); err == nil {
+				mockService.On("Delete", mock.Anything, userID, id).Return(tt.serviceErr)
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/accounts/"+tt.id, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			req = req.WithContext(context.WithValue(ctxWithUserID(userID), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.Delete(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccountHandler_CreateTransfer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", wantStatus: http.StatusCreated},
+		{name: "unknown account", serviceErr: repository.ErrAccountNotFound, wantStatus: http.StatusBadRequest},
+		{name: "same account", serviceErr: service.ErrTransferSameAccount, wantStatus: http.StatusBadRequest},
+		{name: "invalid amount", serviceErr: service.ErrInvalidTransferAmount, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockAccountService)
+			handler := NewAccountHandler(mockService)
+			userID := uuid.New()
+			fromID, toID := uuid.New(), uuid.New()
+
+			input := service.CreateTransferInput{FromAccountID: fromID, ToAccountID: toID, Amount: decimal.NewFromInt(100)}
+			if tt.serviceErr != nil {
+				mockService.On("CreateTransfer", mock.Anything, userID, input).Return(nil, tt.serviceErr)
+			} else {
+				mockService.On("CreateTransfer", mock.Anything, userID, input).Return(&model.Transfer{
+					ID: uuid.New(), FromAccountID: fromID, ToAccountID: toID, Amount: input.Amount,
+				}, nil)
+			}
+
+			body, _ := json.Marshal(map[string]string{
+				"fromAccountId": fromID.String(),
+				"toAccountId":   toID.String(),
+				"amount":        "100",
+			})
+			req := httptest.NewRequest(http.MethodPost, "/api/transfers", bytes.NewReader(body))
+			req = req.WithContext(ctxWithUserID(userID))
+			w := httptest.NewRecorder()
+
+			handler.CreateTransfer(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccountHandler_ListTransfers(t *testing.T) {
+	t.Parallel()
+
+	mockService := new(MockAccountService)
+	handler := NewAccountHandler(mockService)
+	userID := uuid.New()
+	accountID := uuid.New()
+
+	mockService.On("ListTransfers", mock.Anything, userID, service.ListTransfersInput{AccountID: &accountID, Limit: 10}).
+		Return([]model.Transfer{{ID: uuid.New()}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/transfers?accountId="+accountID.String()+"&limit=10", nil)
+	req = req.WithContext(ctxWithUserID(userID))
+	w := httptest.NewRecorder()
+
+	handler.ListTransfers(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+
+	for _, query := range []string{"?accountId=abc", "?startDate=June"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/transfers"+query, nil)
+		req = req.WithContext(ctxWithUserID(userID))
+		w := httptest.NewRecorder()
+
+		handler.ListTransfers(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
@@ -0,0 +1,329 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/wealthpath/backend/internal/apperror"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// multipartOverhead allows for the multipart boundaries and part headers on top
+// of the file itself
+const multipartOverhead = 64 << 10
+
+// AttachmentServiceInterface defines the service contract for attachments.
+type AttachmentServiceInterface interface {
+	MaxSize() int64
+	UploadForTransaction(ctx context.Context, userID, transactionID uuid.UUID, fileName string, body io.Reader) (*model.Attachment, error)
+	UploadForDebtPayment(ctx context.Context, userID, debtID, paymentID uuid.UUID, fileName string, body io.Reader) (*model.Attachment, error)
+	ListForTransaction(ctx context.Context, userID, transactionID uuid.UUID) ([]model.Attachment, error)
+	ListForDebtPayment(ctx context.Context, userID, debtID, paymentID uuid.UUID) ([]model.Attachment, error)
+	Open(ctx context.Context, userID, id uuid.UUID) (*model.Attachment, io.ReadCloser, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+}
+
+// AttachmentHandler handles HTTP requests for receipts and invoices attached to
+// transactions and debt payments.
+type AttachmentHandler struct {
+	service AttachmentServiceInterface
+}
+
+// NewAttachmentHandler creates a new AttachmentHandler with the given service.
+func NewAttachmentHandler(service AttachmentServiceInterface) *AttachmentHandler {
+	return &AttachmentHandler{service: service}
+}
+
+// UploadForTransaction godoc
+// @Summary Attach a file to a transaction
+// @Description Upload a receipt photo or invoice as the "file" field of a multipart form. JPEG, PNG, WebP, HEIC and PDF files are accepted.
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transaction ID"
+// @Param file formData file true "Receipt or invoice"
+// @Success 201 {object} model.Attachment
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /transactions/{id}/attachments [post]
+func (h *AttachmentHandler) UploadForTransaction(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid transaction ID"))
+		return
+	}
+
+	part, ok := h.filePart(w, r)
+	if !ok {
+		return
+	}
+	defer func() { _ = part.Close() }()
+
+	attachment, err := h.service.UploadForTransaction(r.Context(), userID, transactionID, part.FileName(), part)
+	if err != nil {
+		respondAppError(w, attachmentError(err, h.service.MaxSize()))
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, attachment)
+}
+
+// ListForTransaction godoc
+// @Summary List a transaction's attachments
+// @Description Get the files attached to a transaction, oldest first
+// @Tags attachments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transaction ID"
+// @Success 200 {array} model.Attachment
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /transactions/{id}/attachments [get]
+func (h *AttachmentHandler) ListForTransaction(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid transaction ID"))
+		return
+	}
+
+	attachments, err := h.service.ListForTransaction(r.Context(), userID, transactionID)
+	if err != nil {
+		respondAppError(w, attachmentError(err, h.service.MaxSize()))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, attachments)
+}
+
+// UploadForDebtPayment godoc
+// @Summary Attach a file to a debt payment
+// @Description Upload a receipt photo or invoice as the "file" field of a multipart form. JPEG, PNG, WebP, HEIC and PDF files are accepted.
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Debt ID"
+// @Param paymentId path string true "Payment ID"
+// @Param file formData file true "Receipt or invoice"
+// @Success 201 {object} model.Attachment
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /debts/{id}/payments/{paymentId}/attachments [post]
+func (h *AttachmentHandler) UploadForDebtPayment(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	debtID, paymentID, ok := parseDebtPaymentIDs(w, r)
+	if !ok {
+		return
+	}
+
+	part, ok := h.filePart(w, r)
+	if !ok {
+		return
+	}
+	defer func() { _ = part.Close() }()
+
+	attachment, err := h.service.UploadForDebtPayment(r.Context(), userID, debtID, paymentID, part.FileName(), part)
+	if err != nil {
+		respondAppError(w, attachmentError(err, h.service.MaxSize()))
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, attachment)
+}
+
+// ListForDebtPayment godoc
+// @Summary List a debt payment's attachments
+// @Description Get the files attached to a debt payment, oldest first
+// @Tags attachments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Debt ID"
+// @Param paymentId path string true "Payment ID"
+// @Success 200 {array} model.Attachment
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /debts/{id}/payments/{paymentId}/attachments [get]
+func (h *AttachmentHandler) ListForDebtPayment(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	debtID, paymentID, ok := parseDebtPaymentIDs(w, r)
+	if !ok {
+		return
+	}
+
+	attachments, err := h.service.ListForDebtPayment(r.Context(), userID, debtID, paymentID)
+	if err != nil {
+		respondAppError(w, attachmentError(err, h.service.MaxSize()))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, attachments)
+}
+
+// Download godoc
+// @Summary Download an attachment
+// @Description Get the file. It is shown inline unless download=true is passed.
+// @Tags attachments
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path string true "Attachment ID"
+// @Param download query bool false "Send as a file download instead of inline"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /attachments/{id}/download [get]
+func (h *AttachmentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid attachment ID"))
+		return
+	}
+
+	attachment, body, err := h.service.Open(r.Context(), userID, id)
+	if err != nil {
+		respondAppError(w, attachmentError(err, h.service.MaxSize()))
+		return
+	}
+	defer func() { _ = body.Close() }()
+
+	disposition := "inline"
+	if download, _ := strconv.ParseBool(r.URL.Query().Get("download")); download {
+		disposition = "attachment"
+	}
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, body); err != nil {
+		slog.Warn("Failed to send attachment", slog.String("id", id.String()), slog.String("error", err.Error()))
+	}
+}
+
+// Delete godoc
+// @Summary Delete an attachment
+// @Description Remove an attachment and its file
+// @Tags attachments
+// @Security BearerAuth
+// @Param id path string true "Attachment ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /attachments/{id} [delete]
+func (h *AttachmentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid attachment ID"))
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), userID, id); err != nil {
+		respondAppError(w, attachmentError(err, h.service.MaxSize()))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// filePart returns the "file" part of a multipart upload. The request body is
+// capped a little above the maximum attachment size.
+func (h *AttachmentHandler) filePart(w http.ResponseWriter, r *http.Request) (*multipart.Part, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.service.MaxSize()+multipartOverhead)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("expected a multipart/form-data upload"))
+		return nil, false
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			respondAppError(w, apperror.ValidationError("file", "file is required"))
+			return nil, false
+		}
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				respondAppError(w, attachmentError(err, h.service.MaxSize()))
+			} else {
+				respondAppError(w, apperror.BadRequest("invalid multipart upload"))
+			}
+			return nil, false
+		}
+		if part.FormName() == "file" {
+			return part, true
+		}
+		_ = part.Close()
+	}
+}
+
+func parseDebtPaymentIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	debtID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid debt ID"))
+		return uuid.Nil, uuid.Nil, false
+	}
+	paymentID, err := uuid.Parse(chi.URLParam(r, "paymentId"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid payment ID"))
+		return uuid.Nil, uuid.Nil, false
+	}
+	return debtID, paymentID, true
+}
+
+func attachmentError(err error, maxSize int64) *apperror.AppError {
+	var maxErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, service.ErrAttachmentTooLarge), errors.As(err, &maxErr):
+		return apperror.PayloadTooLarge(fmt.Sprintf("attachments must be at most %d MB", maxSize>>20))
+	case errors.Is(err, service.ErrAttachmentType), errors.Is(err, service.ErrEmptyAttachment):
+		return apperror.ValidationError("file", err.Error())
+	case errors.Is(err, repository.ErrTransactionNotFound):
+		return apperror.NotFound("transaction")
+	case errors.Is(err, repository.ErrDebtPaymentNotFound):
+		return apperror.NotFound("debt payment")
+	case errors.Is(err, repository.ErrAttachmentNotFound):
+		return apperror.NotFound("attachment")
+	default:
+		return apperror.Internal(err)
+	}
+}
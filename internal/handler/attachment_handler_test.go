@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// MockAttachmentService implements AttachmentServiceInterface for testing
+type MockAttachmentService struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentService) MaxSize() int64 {
+	return 1 << 20
+}
+
+func (m *MockAttachmentService) UploadForTransaction(ctx context.Context, userID, transactionID uuid.UUID, fileName string, body io.Reader) (*model.Attachment, error) {
+	data, _ := io.ReadAll(body)
+	args := m.Called(ctx, userID, transactionID, fileName, string(data))
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentService) UploadForDebtPayment(ctx context.Context, userID, debtID, paymentID uuid.UUID, fileName string, body io.Reader) (*model.Attachment, error) {
+	data, _ := io.ReadAll(body)
+	args := m.Called(ctx, userID, debtID, paymentID, fileName, string(data))
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentService) ListForTransaction(ctx context.Context, userID, transactionID uuid.UUID) ([]model.Attachment, error) {
+	args := m.Called(ctx, userID, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentService) ListForDebtPayment(ctx context.Context, userID, debtID, paymentID uuid.UUID) ([]model.Attachment, error) {
+	args := m.Called(ctx, userID, debtID, paymentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentService) Open(ctx context.Context, userID, id uuid.UUID) (*model.Attachment, io.ReadCloser, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*model.Attachment), args.Get(1).(io.ReadCloser), args.Error(2)
+}
+
+func (m *MockAttachmentService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func multipartBody(t *testing.T, field, fileName, content string) (*bytes.Buffer, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	require.NoError(t, mw.WriteField("note", "lunch"))
+	fw, err := mw.CreateFormFile(field, fileName)
+	require.NoError(t, err)
+	_, err = io.WriteString(fw, content)
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+	return &buf, mw.FormDataContentType()
+}
+
+func TestAttachmentHandler_UploadForTransaction(t *testing.T) {
+	t.Parallel()
+
+	transactionID := uuid.New()
+
+	tests := []struct {
+		name        string
+		id          string
+		field       string
+		contentType string
+		serviceErr  error
+		wantStatus  int
+	}{
+		{name: "success", id: transactionID.String(), field: "file", wantStatus: http.StatusCreated},
+		{name: "invalid id", id: "abc", field: "file", wantStatus: http.StatusBadRequest},
+		{name: "not multipart", id: transactionID.String(), field: "file", contentType: "application/json", wantStatus: http.StatusBadRequest},
+		{name: "missing file", id: transactionID.String(), field: "receipt", wantStatus: http.StatusBadRequest},
+		{name: "too large", id: transactionID.String(), field: "file", serviceErr: service.ErrAttachmentTooLarge, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "unsupported type", id: transactionID.String(), field: "file", serviceErr: service.ErrAttachmentType, wantStatus: http.StatusBadRequest},
+		{name: "other user's transaction", id: transactionID.String(), field: "file", serviceErr: repository.ErrTransactionNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockAttachmentService)
+			handler := NewAttachmentHandler(mockService)
+			userID := uuid.New()
+
+			body, contentType := multipartBody(t, tt.field, "receipt.pdf", "%PDF-1.7")
+			if tt.contentType != "" {
+				contentType = tt.contentType
+			}
+
+			call := mockService.On("UploadForTransaction", mock.Anything, userID, transactionID, "receipt.pdf", "%PDF-1.7").Maybe()
+			if tt.serviceErr != nil {
+				call.Return(nil, tt.serviceErr)
+			} else {
+				call.Return(&model.Attachment{ID: uuid.New(), FileName: "receipt.pdf"}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/transactions/"+tt.id+"/attachments", body)
+			req.Header.Set("Content-Type", contentType)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			req = req.WithContext(context.WithValue(ctxWithUserID(userID), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.UploadForTransaction(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusCreated || tt.serviceErr != nil {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "UploadForTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAttachmentHandler_UploadForTransaction_BodyTooLarge(t *testing.T) {
+	t.Parallel()
+
+	mockService := new(MockAttachmentService)
+	handler := NewAttachmentHandler(mockService)
+	userID, transactionID := uuid.New(), uuid.New()
+
+	// The note field alone is larger than the request limit
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	require.NoError(t, mw.WriteField("note", strings.Repeat("x", 2<<20)))
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/transactions/"+transactionID.String()+"/attachments", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", transactionID.String())
+	req = req.WithContext(context.WithValue(ctxWithUserID(userID), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	handler.UploadForTransaction(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestAttachmentHandler_Download(t *testing.T) {
+	t.Parallel()
+
+	mockService := new(MockAttachmentService)
+	handler := NewAttachmentHandler(mockService)
+	userID, id := uuid.New(), uuid.New()
+
+	mockService.On("Open", mock.Anything, userID, id).Return(
+		&model.Attachment{ID: id, FileName: "hóa đơn.pdf", ContentType: "application/pdf", Size: 8},
+		io.NopCloser(strings.NewReader("%PDF-1.7")), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/attachments/"+id.String()+"/download?download=true", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id.String())
+	req = req.WithContext(context.WithValue(ctxWithUserID(userID), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	handler.Download(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Equal(t, "8", w.Header().Get("Content-Length"))
+	assert.Equal(t, "attachment; filename*=utf-8''h%C3%B3a%20%C4%91%C6%A1n.pdf", w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "%PDF-1.7", w.Body.String())
+}
+
+func TestAttachmentHandler_Delete(t *testing.T) {
+	t.Parallel()
+
+	attachmentID := uuid.New()
+
+	tests := []struct {
+		name       string
+		id         string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", id: attachmentID.String(), wantStatus: http.StatusNoContent},
+		{name: "invalid id", id: "abc", wantStatus: http.StatusBadRequest},
+		{name: "not found", id: attachmentID.String(), serviceErr: repository.ErrAttachmentNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockAttachmentService)
+			handler := NewAttachmentHandler(mockService)
+			userID := uuid.New()
+
+			if tt.id == attachmentID.String() {
+				mockService.On("Delete", mock.Anything, userID, attachmentID).Return(tt.serviceErr)
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/attachments/"+tt.id, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			req = req.WithContext(context.WithValue(ctxWithUserID(userID), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.Delete(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
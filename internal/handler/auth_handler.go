@@ -198,6 +198,10 @@ func (h *AuthHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.userService.UpdateSettings(r.Context(), userID, input)
 	if err != nil {
+		if errors.Is(err, service.ErrUnsupportedCurrency) || errors.Is(err, service.ErrUnsupportedLocale) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		respondError(w, http.StatusInternalServerError, "failed to update settings: "+err.Error())
 		return
 	}
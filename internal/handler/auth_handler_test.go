@@ -513,6 +513,28 @@ func TestAuthHandler_UpdateSettings_ServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestAuthHandler_UpdateSettings_UnsupportedLocale(t *testing.T) {
+	mockService := new(MockUserService)
+	handler := NewAuthHandler(mockService)
+
+	userID := uuid.New()
+	locale := "fr"
+	input := service.UpdateSettingsInput{Locale: &locale}
+
+	mockService.On("UpdateSettings", mock.Anything, userID, mock.AnythingOfType("service.UpdateSettingsInput")).Return(nil, service.ErrUnsupportedLocale)
+
+	body, _ := json.Marshal(input)
+	req := httptest.NewRequest(http.MethodPut, "/api/auth/settings", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(context.WithValue(req.Context(), UserIDKey, userID))
+
+	rr := httptest.NewRecorder()
+	handler.UpdateSettings(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestAuthHandler_RefreshToken_Success(t *testing.T) {
 	mockService := new(MockUserService)
 	handler := NewAuthHandler(mockService)
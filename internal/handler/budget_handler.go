@@ -2,11 +2,13 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	_ "github.com/wealthpath/backend/internal/model" // swagger types
+	"github.com/wealthpath/backend/internal/repository"
 	"github.com/wealthpath/backend/internal/service"
 )
 
@@ -164,3 +166,38 @@ func (h *BudgetHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// ListRollovers godoc
+// @Summary List budget rollovers
+// @Description Get the rollover history of a budget, newest period first
+// @Tags budgets
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Budget ID"
+// @Success 200 {array} model.BudgetRollover
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /budgets/{id}/rollovers [get]
+func (h *BudgetHandler) ListRollovers(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	rollovers, err := h.service.ListRollovers(r.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrBudgetNotFound) {
+			respondError(w, http.StatusNotFound, "budget not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to list budget rollovers")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rollovers)
+}
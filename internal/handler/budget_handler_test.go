@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
 	"github.com/wealthpath/backend/internal/service"
 )
 
@@ -68,6 +69,14 @@ func (m *MockBudgetService) Delete(ctx context.Context, id, userID uuid.UUID) er
 	return args.Error(0)
 }
 
+func (m *MockBudgetService) ListRollovers(ctx context.Context, id, userID uuid.UUID) ([]model.BudgetRollover, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.BudgetRollover), args.Error(1)
+}
+
 // Helper to create context with userID
 func ctxWithUserID(userID uuid.UUID) context.Context {
 	return context.WithValue(context.Background(), UserIDKey, userID)
@@ -392,3 +401,79 @@ func TestBudgetHandler_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestBudgetHandler_ListRollovers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		budgetID   string
+		setupMock  func(*MockBudgetService, uuid.UUID, uuid.UUID)
+		wantStatus int
+		wantLen    int
+	}{
+		{
+			name:     "success",
+			budgetID: uuid.New().String(),
+			setupMock: func(m *MockBudgetService, budgetID, userID uuid.UUID) {
+				m.On("ListRollovers", mock.Anything, budgetID, userID).Return([]model.BudgetRollover{
+					{BudgetID: budgetID, Amount: decimal.NewFromInt(120)},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantLen:    1,
+		},
+		{
+			name:       "invalid uuid",
+			budgetID:   "invalid-uuid",
+			setupMock:  func(m *MockBudgetService, budgetID, userID uuid.UUID) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "not found",
+			budgetID: uuid.New().String(),
+			setupMock: func(m *MockBudgetService, budgetID, userID uuid.UUID) {
+				m.On("ListRollovers", mock.Anything, budgetID, userID).Return(nil, repository.ErrBudgetNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:     "service error",
+			budgetID: uuid.New().String(),
+			setupMock: func(m *MockBudgetService, budgetID, userID uuid.UUID) {
+				m.On("ListRollovers", mock.Anything, budgetID, userID).Return(nil, errors.New("error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockBudgetService)
+			handler := NewBudgetHandler(mockService)
+			userID := uuid.New()
+			budgetID, _ := uuid.Parse(tt.budgetID)
+
+			tt.setupMock(mockService, budgetID, userID)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/budgets/"+tt.budgetID+"/rollovers", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.budgetID)
+			req = req.WithContext(context.WithValue(ctxWithUserID(userID), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.ListRollovers(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				var rollovers []model.BudgetRollover
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&rollovers))
+				assert.Len(t, rollovers, tt.wantLen)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
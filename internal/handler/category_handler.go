@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/wealthpath/backend/internal/apperror"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// CategoryServiceInterface defines the service contract for categories.
+type CategoryServiceInterface interface {
+	List(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]model.Category, error)
+	Create(ctx context.Context, userID uuid.UUID, input service.CreateCategoryInput) (*model.Category, error)
+	Update(ctx context.Context, userID, id uuid.UUID, input service.UpdateCategoryInput) (*model.Category, error)
+	Merge(ctx context.Context, userID, sourceID, targetID uuid.UUID) (*model.Category, error)
+}
+
+// CategoryHandler handles HTTP requests for the user's categories.
+type CategoryHandler struct {
+	service CategoryServiceInterface
+}
+
+// NewCategoryHandler creates a new CategoryHandler with the given service.
+func NewCategoryHandler(service CategoryServiceInterface) *CategoryHandler {
+	return &CategoryHandler{service: service}
+}
+
+// MergeCategoryRequest names the category another one is merged into.
+type MergeCategoryRequest struct {
+	TargetID uuid.UUID `json:"targetId"`
+}
+
+// List godoc
+// @Summary List categories
+// @Description Get the current user's income and expense categories. Each top-level category is followed by its subcategories.
+// @Tags categories
+// @Produce json
+// @Security BearerAuth
+// @Param archived query bool false "Include archived categories"
+// @Success 200 {array} model.Category
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /categories [get]
+func (h *CategoryHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	includeArchived := false
+	if archived := r.URL.Query().Get("archived"); archived != "" {
+		b, err := strconv.ParseBool(archived)
+		if err != nil {
+			respondAppError(w, apperror.BadRequest("invalid archived"))
+			return
+		}
+		includeArchived = b
+	}
+
+	categories, err := h.service.List(r.Context(), userID, includeArchived)
+	if err != nil {
+		respondAppError(w, apperror.Internal(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, categories)
+}
+
+// Create godoc
+// @Summary Create a category
+// @Description Create an income or expense category, optionally under a top-level category
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body service.CreateCategoryInput true "Category data"
+// @Success 201 {object} model.Category
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /categories [post]
+func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	var input service.CreateCategoryInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondAppError(w, apperror.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	category, err := h.service.Create(r.Context(), userID, input)
+	if err != nil {
+		respondAppError(w, categoryError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, category)
+}
+
+// Update godoc
+// @Summary Update a category
+// @Description Rename, move, restyle or archive a category. Renaming refiles the category's transactions, budgets and recurring transactions.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Category ID"
+// @Param input body service.UpdateCategoryInput true "Updated category data"
+// @Success 200 {object} model.Category
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /categories/{id} [put]
+func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid category ID"))
+		return
+	}
+
+	var input service.UpdateCategoryInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondAppError(w, apperror.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	category, err := h.service.Update(r.Context(), userID, id, input)
+	if err != nil {
+		respondAppError(w, categoryError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, category)
+}
+
+// Merge godoc
+// @Summary Merge a category into another
+// @Description Move the category's transactions, budgets, recurring transactions and subcategories to the target category, then delete it
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Category ID to merge away"
+// @Param input body MergeCategoryRequest true "Target category"
+// @Success 200 {object} model.Category
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /categories/{id}/merge [post]
+func (h *CategoryHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid category ID"))
+		return
+	}
+
+	var req MergeCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondAppError(w, apperror.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+	if req.TargetID == uuid.Nil {
+		respondAppError(w, apperror.ValidationError("targetId", "targetId is required"))
+		return
+	}
+
+	target, err := h.service.Merge(r.Context(), userID, id, req.TargetID)
+	if err != nil {
+		respondAppError(w, categoryError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, target)
+}
+
+func categoryError(err error) *apperror.AppError {
+	switch {
+	case errors.Is(err, repository.ErrCategoryNotFound):
+		return apperror.NotFound("category")
+	case errors.Is(err, repository.ErrCategoryExists):
+		return apperror.Conflict("a category with this name already exists; merge them instead")
+	case errors.Is(err, repository.ErrCategoryBudgetCurrency):
+		return apperror.Conflict("both categories have a budget for the same period in different currencies; change or delete one of them first")
+	case errors.Is(err, service.ErrInvalidCategoryName):
+		return apperror.ValidationError("name", err.Error())
+	case errors.Is(err, service.ErrInvalidCategoryType):
+		return apperror.ValidationError("type", err.Error())
+	case errors.Is(err, service.ErrInvalidCategoryIcon):
+		return apperror.ValidationError("icon", err.Error())
+	case errors.Is(err, service.ErrInvalidCategoryColor):
+		return apperror.ValidationError("color", err.Error())
+	case errors.Is(err, service.ErrInvalidCategoryParent),
+		errors.Is(err, service.ErrCategoryHasChildren):
+		return apperror.ValidationError("parentId", err.Error())
+	case errors.Is(err, service.ErrCategoryMergeSelf),
+		errors.Is(err, service.ErrCategoryMergeType):
+		return apperror.ValidationError("targetId", err.Error())
+	default:
+		return apperror.Internal(err)
+	}
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// MockCategoryService implements CategoryServiceInterface for testing
+type MockCategoryService struct {
+	mock.Mock
+}
+
+func (m *MockCategoryService) List(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]model.Category, error) {
+	args := m.Called(ctx, userID, includeArchived)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Category), args.Error(1)
+}
+
+func (m *MockCategoryService) Create(ctx context.Context, userID uuid.UUID, input service.CreateCategoryInput) (*model.Category, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Category), args.Error(1)
+}
+
+func (m *MockCategoryService) Update(ctx context.Context, userID, id uuid.UUID, input service.UpdateCategoryInput) (*model.Category, error) {
+	args := m.Called(ctx, userID, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Category), args.Error(1)
+}
+
+func (m *MockCategoryService) Merge(ctx context.Context, userID, sourceID, targetID uuid.UUID) (*model.Category, error) {
+	args := m.Called(ctx, userID, sourceID, targetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Category), args.Error(1)
+}
+
+func TestCategoryHandler_Create(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", body: `{"name":"Pets","type":"expense","color":"#795548"}`, wantStatus: http.StatusCreated},
+		{name: "invalid body", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "invalid parent", body: `{"name":"Pets","type":"expense"}`, serviceErr: service.ErrInvalidCategoryParent, wantStatus: http.StatusBadRequest},
+		{name: "duplicate name", body: `{"name":"Pets","type":"expense"}`, serviceErr: repository.ErrCategoryExists, wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockCategoryService)
+			handler := NewCategoryHandler(mockService)
+			userID := uuid.New()
+
+			if tt.serviceErr != nil {
+				mockService.On("Create", mock.Anything, userID, mock.Anything).Return(nil, tt.serviceErr)
+			} else if tt.wantStatus == http.StatusCreated {
+				mockService.On("Create", mock.Anything, userID, service.CreateCategoryInput{
+					Name: "Pets", Type: model.TransactionTypeExpense, Color: "#795548",
+				}).Return(&model.Category{ID: uuid.New(), Name: "Pets"}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/categories", bytes.NewBufferString(tt.body))
+			req = req.WithContext(ctxWithUserID(userID))
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCategoryHandler_Merge(t *testing.T) {
+	t.Parallel()
+
+	sourceID, targetID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name       string
+		id         string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", id: sourceID.String(), body: `{"targetId":"` + targetID.String() + `"}`, wantStatus: http.StatusOK},
+		{name: "invalid id", id: "abc", body: `{"targetId":"` + targetID.String() + `"}`, wantStatus: http.StatusBadRequest},
+		{name: "missing target", id: sourceID.String(), body: `{}`, wantStatus: http.StatusBadRequest},
+		{
+			name: "different types", id: sourceID.String(), body: `{"targetId":"` + targetID.String() + `"}`,
+			serviceErr: service.ErrCategoryMergeType, wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not found", id: sourceID.String(), body: `{"targetId":"` + targetID.String() + `"}`,
+			serviceErr: repository.ErrCategoryNotFound, wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "budgets in different currencies", id: sourceID.String(), body: `{"targetId":"` + targetID.String() + `"}`,
+			serviceErr: repository.ErrCategoryBudgetCurrency, wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockCategoryService)
+			handler := NewCategoryHandler(mockService)
+			userID := uuid.New()
+
+			if tt.serviceErr != nil {
+				mockService.On("Merge", mock.Anything, userID, sourceID, targetID).Return(nil, tt.serviceErr)
+			} else if tt.wantStatus == http.StatusOK {
+				mockService.On("Merge", mock.Anything, userID, sourceID, targetID).Return(&model.Category{ID: targetID}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/categories/"+tt.id+"/merge", bytes.NewBufferString(tt.body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			req = req.WithContext(context.WithValue(ctxWithUserID(userID), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.Merge(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
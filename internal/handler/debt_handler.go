@@ -2,12 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	_ "github.com/wealthpath/backend/internal/model" // swagger types
+	"github.com/wealthpath/backend/internal/repository"
 	"github.com/wealthpath/backend/internal/service"
 )
 
@@ -204,6 +206,41 @@ func (h *DebtHandler) MakePayment(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, debt)
 }
 
+// ListPayments godoc
+// @Summary List debt payments
+// @Description Get the payments made on a debt, newest first
+// @Tags debts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Debt ID"
+// @Success 200 {array} model.DebtPayment
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /debts/{id}/payments [get]
+func (h *DebtHandler) ListPayments(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	payments, err := h.service.ListPayments(r.Context(), id, userID)
+	if errors.Is(err, repository.ErrDebtNotFound) {
+		respondError(w, http.StatusNotFound, "debt not found")
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to list payments")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, payments)
+}
+
 // GetPayoffPlan godoc
 // @Summary Get debt payoff plan
 // @Description Calculate a payoff plan for a debt
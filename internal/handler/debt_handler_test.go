@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
 	"github.com/wealthpath/backend/internal/service"
 )
 
@@ -68,6 +69,14 @@ func (m *MockDebtService) MakePayment(ctx context.Context, id, userID uuid.UUID,
 	return args.Get(0).(*model.Debt), args.Error(1)
 }
 
+func (m *MockDebtService) ListPayments(ctx context.Context, id, userID uuid.UUID) ([]model.DebtPayment, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.DebtPayment), args.Error(1)
+}
+
 func (m *MockDebtService) GetPayoffPlan(ctx context.Context, id uuid.UUID, monthlyPayment decimal.Decimal) (*model.PayoffPlan, error) {
 	args := m.Called(ctx, id, monthlyPayment)
 	if args.Get(0) == nil {
@@ -474,6 +483,51 @@ func TestDebtHandler_MakePayment(t *testing.T) {
 	}
 }
 
+func TestDebtHandler_ListPayments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		debtID     string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", debtID: uuid.New().String(), wantStatus: http.StatusOK},
+		{name: "invalid uuid", debtID: "invalid", wantStatus: http.StatusBadRequest},
+		{name: "other user's debt", debtID: uuid.New().String(), serviceErr: repository.ErrDebtNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockDebtService)
+			handler := NewDebtHandler(mockService)
+			userID := uuid.New()
+
+			if debtID, err := uuid.Parse(tt.debtID); err == nil {
+				if tt.serviceErr != nil {
+					mockService.On("ListPayments", mock.Anything, debtID, userID).Return(nil, tt.serviceErr)
+				} else {
+					mockService.On("ListPayments", mock.Anything, debtID, userID).Return([]model.DebtPayment{{ID: uuid.New(), DebtID: debtID}}, nil)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/debts/"+tt.debtID+"/payments", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.debtID)
+			req = req.WithContext(context.WithValue(ctxWithUserID(userID), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.ListPayments(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestDebtHandler_GetPayoffPlan(t *testing.T) {
 	t.Parallel()
 
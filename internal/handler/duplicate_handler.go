@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/wealthpath/backend/internal/apperror"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// DuplicateServiceInterface defines the service contract for reviewing duplicate transactions.
+type DuplicateServiceInterface interface {
+	Queue(ctx context.Context, userID uuid.UUID, input service.DuplicateQueueInput) ([]service.DuplicatePair, error)
+	Merge(ctx context.Context, userID uuid.UUID, input service.MergeDuplicatesInput) (*model.Transaction, error)
+	Dismiss(ctx context.Context, userID uuid.UUID, input service.DuplicatePairInput) (*model.DuplicateReview, error)
+	NotDuplicate(ctx context.Context, userID uuid.UUID, input service.DuplicatePairInput) (*model.DuplicateReview, error)
+}
+
+// DuplicateHandler handles HTTP requests for reviewing duplicate transactions.
+type DuplicateHandler struct {
+	service DuplicateServiceInterface
+}
+
+// NewDuplicateHandler creates a new DuplicateHandler with the given service.
+func NewDuplicateHandler(service DuplicateServiceInterface) *DuplicateHandler {
+	return &DuplicateHandler{service: service}
+}
+
+// Queue godoc
+// @Summary List suspected duplicate transactions
+// @Description Get pairs of transactions that may be the same one recorded twice, scored out of 100 by how close their amounts and dates are, how similar their descriptions are and whether their categories match. The most likely duplicates come first. Pairs dismissed or marked as not duplicates are never listed again.
+// @Tags duplicates
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "How many days back to look (default: 90, max: 365)"
+// @Param minScore query int false "Lowest score to include (default: 60)"
+// @Param limit query int false "Number of pairs (default: 50, max: 200)"
+// @Success 200 {array} service.DuplicatePair
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /duplicates [get]
+func (h *DuplicateHandler) Queue(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	var input service.DuplicateQueueInput
+	for _, param := range []struct {
+		name  string
+		value *int
+	}{
+		{"days", &input.Days},
+		{"minScore", &input.MinScore},
+		{"limit", &input.Limit},
+	} {
+		v := r.URL.Query().Get(param.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			respondAppError(w, apperror.ValidationError(param.name, param.name+" must be a positive number"))
+			return
+		}
+		*param.value = n
+	}
+
+	pairs, err := h.service.Queue(r.Context(), userID, input)
+	if err != nil {
+		respondAppError(w, apperror.Internal(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, pairs)
+}
+
+// Merge godoc
+// @Summary Merge duplicate transactions
+// @Description Keep one transaction of a pair and delete the other. The kept transaction gains the other's tags, attachments and savings contributions, and its description when it has none.
+// @Tags duplicates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body service.MergeDuplicatesInput true "Pair and the transaction to keep"
+// @Success 200 {object} model.Transaction
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /duplicates/merge [post]
+func (h *DuplicateHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	var input service.MergeDuplicatesInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondAppError(w, apperror.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	tx, err := h.service.Merge(r.Context(), userID, input)
+	if err != nil {
+		respondAppError(w, duplicateError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tx)
+}
+
+// Dismiss godoc
+// @Summary Dismiss a suspected duplicate
+// @Description Hide a pair from the duplicate review queue for good
+// @Tags duplicates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body service.DuplicatePairInput true "Pair of transactions"
+// @Success 200 {object} model.DuplicateReview
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /duplicates/dismiss [post]
+func (h *DuplicateHandler) Dismiss(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, h.service.Dismiss)
+}
+
+// NotDuplicate godoc
+// @Summary Mark a pair as not duplicates
+// @Description Record that two transactions are separate, which keeps the pair out of the duplicate review queue for good
+// @Tags duplicates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body service.DuplicatePairInput true "Pair of transactions"
+// @Success 200 {object} model.DuplicateReview
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /duplicates/not-duplicate [post]
+func (h *DuplicateHandler) NotDuplicate(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, h.service.NotDuplicate)
+}
+
+// review passes a pair from the request body to the service
+func (h *DuplicateHandler) review(w http.ResponseWriter, r *http.Request,
+	reviewFn func(ctx context.Context, userID uuid.UUID, input service.DuplicatePairInput) (*model.DuplicateReview, error),
+) {
+	userID := GetUserID(r.Context())
+
+	var input service.DuplicatePairInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondAppError(w, apperror.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	review, err := reviewFn(r.Context(), userID, input)
+	if err != nil {
+		respondAppError(w, duplicateError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, review)
+}
+
+func duplicateError(err error) *apperror.AppError {
+	switch {
+	case errors.Is(err, repository.ErrTransactionNotFound):
+		return apperror.NotFound("transaction")
+	case errors.Is(err, service.ErrInvalidDuplicatePair):
+		return apperror.ValidationError("duplicateId", err.Error())
+	case errors.Is(err, service.ErrInvalidKeepID):
+		return apperror.ValidationError("keepId", err.Error())
+	case errors.Is(err, service.ErrDuplicateMismatch):
+		return apperror.BadRequest(err.Error())
+	default:
+		return apperror.Internal(err)
+	}
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// MockDuplicateService implements DuplicateServiceInterface for testing
+type MockDuplicateService struct {
+	mock.Mock
+}
+
+func (m *MockDuplicateService) Queue(ctx context.Context, userID uuid.UUID, input service.DuplicateQueueInput) ([]service.DuplicatePair, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]service.DuplicatePair), args.Error(1)
+}
+
+func (m *MockDuplicateService) Merge(ctx context.Context, userID uuid.UUID, input service.MergeDuplicatesInput) (*model.Transaction, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Transaction), args.Error(1)
+}
+
+func (m *MockDuplicateService) Dismiss(ctx context.Context, userID uuid.UUID, input service.DuplicatePairInput) (*model.DuplicateReview, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.DuplicateReview), args.Error(1)
+}
+
+func (m *MockDuplicateService) NotDuplicate(ctx context.Context, userID uuid.UUID, input service.DuplicatePairInput) (*model.DuplicateReview, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.DuplicateReview), args.Error(1)
+}
+
+func TestDuplicateHandler_Queue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantInput  *service.DuplicateQueueInput
+		wantStatus int
+	}{
+		{name: "defaults", query: "", wantInput: &service.DuplicateQueueInput{}, wantStatus: http.StatusOK},
+		{name: "with filters", query: "?days=30&minScore=80&limit=10", wantInput: &service.DuplicateQueueInput{Days: 30, MinScore: 80, Limit: 10}, wantStatus: http.StatusOK},
+		{name: "invalid days", query: "?days=abc", wantStatus: http.StatusBadRequest},
+		{name: "negative limit", query: "?limit=-1", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockDuplicateService)
+			handler := NewDuplicateHandler(mockService)
+			userID := uuid.New()
+
+			if tt.wantInput != nil {
+				mockService.On("Queue", mock.Anything, userID, *tt.wantInput).Return([]service.DuplicatePair{}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/duplicates"+tt.query, nil)
+			req = req.WithContext(ctxWithUserID(userID))
+			w := httptest.NewRecorder()
+
+			handler.Queue(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDuplicateHandler_Merge(t *testing.T) {
+	t.Parallel()
+
+	keepID, dropID := uuid.New(), uuid.New()
+	body := `{"transactionId":"` + keepID.String() + `","duplicateId":"` + dropID.String() + `","keepId":"` + keepID.String() + `"}`
+
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", body: body, wantStatus: http.StatusOK},
+		{name: "invalid body", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "invalid keep ID", body: body, serviceErr: service.ErrInvalidKeepID, wantStatus: http.StatusBadRequest},
+		{name: "mismatch", body: body, serviceErr: service.ErrDuplicateMismatch, wantStatus: http.StatusBadRequest},
+		{name: "not found", body: body, serviceErr: repository.ErrTransactionNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockDuplicateService)
+			handler := NewDuplicateHandler(mockService)
+			userID := uuid.New()
+
+			input := service.MergeDuplicatesInput{
+				DuplicatePairInput: service.DuplicatePairInput{TransactionID: keepID, DuplicateID: dropID},
+				KeepID:             keepID,
+			}
+			if tt.serviceErr != nil {
+				mockService.On("Merge", mock.Anything, userID, input).Return(nil, tt.serviceErr)
+			} else if tt.wantStatus == http.StatusOK {
+				mockService.On("Merge", mock.Anything, userID, input).Return(&model.Transaction{ID: keepID}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/duplicates/merge", bytes.NewBufferString(tt.body))
+			req = req.WithContext(ctxWithUserID(userID))
+			w := httptest.NewRecorder()
+
+			handler.Merge(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDuplicateHandler_Review(t *testing.T) {
+	t.Parallel()
+
+	first, second := uuid.New(), uuid.New()
+	body := `{"transactionId":"` + first.String() + `","duplicateId":"` + second.String() + `"}`
+	input := service.DuplicatePairInput{TransactionID: first, DuplicateID: second}
+
+	tests := []struct {
+		name       string
+		method     string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "dismiss", method: "Dismiss", wantStatus: http.StatusOK},
+		{name: "not duplicate", method: "NotDuplicate", wantStatus: http.StatusOK},
+		{name: "invalid pair", method: "Dismiss", serviceErr: service.ErrInvalidDuplicatePair, wantStatus: http.StatusBadRequest},
+		{name: "not found", method: "NotDuplicate", serviceErr: repository.ErrTransactionNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockDuplicateService)
+			handler := NewDuplicateHandler(mockService)
+			userID := uuid.New()
+
+			if tt.serviceErr != nil {
+				mockService.On(tt.method, mock.Anything, userID, input).Return(nil, tt.serviceErr)
+			} else {
+				mockService.On(tt.method, mock.Anything, userID, input).Return(&model.DuplicateReview{ID: uuid.New()}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/duplicates", bytes.NewBufferString(body))
+			req = req.WithContext(ctxWithUserID(userID))
+			w := httptest.NewRecorder()
+
+			if tt.method == "Dismiss" {
+				handler.Dismiss(w, req)
+			} else {
+				handler.NotDuplicate(w, req)
+			}
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
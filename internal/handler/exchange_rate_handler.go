@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/wealthpath/backend/internal/apperror"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+)
+
+// ExchangeRateServiceInterface defines the service contract for bank exchange rates.
+type ExchangeRateServiceInterface interface {
+	ListRates(ctx context.Context, bankCode, currency string) ([]model.BankExchangeRate, error)
+	GetRateHistory(ctx context.Context, bankCode, currency string, days int) ([]repository.ExchangeRateHistoryEntry, error)
+}
+
+// ExchangeRateHandler handles HTTP requests for the exchange rates banks publish.
+type ExchangeRateHandler struct {
+	service ExchangeRateServiceInterface
+}
+
+// NewExchangeRateHandler creates a new ExchangeRateHandler with the given service.
+func NewExchangeRateHandler(service ExchangeRateServiceInterface) *ExchangeRateHandler {
+	return &ExchangeRateHandler{service: service}
+}
+
+// ListRates godoc
+// @Summary List bank exchange rates
+// @Description Get the latest foreign exchange rates against VND published by banks: the price in VND the bank buys one unit of cash at, buys one unit by transfer at and sells one unit at. Rates a bank doesn't quote are omitted.
+// @Tags exchange-rates
+// @Produce json
+// @Param bank query string false "Bank code (vcb)"
+// @Param currency query string false "Currency code (USD, EUR, etc.)"
+// @Success 200 {array} model.BankExchangeRate
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /exchange-rates [get]
+func (h *ExchangeRateHandler) ListRates(w http.ResponseWriter, r *http.Request) {
+	currency, ok := currencyParam(w, r, false)
+	if !ok {
+		return
+	}
+
+	rates, err := h.service.ListRates(r.Context(), r.URL.Query().Get("bank"), currency)
+	if err != nil {
+		respondAppError(w, apperror.Internal(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rates)
+}
+
+// GetHistory godoc
+// @Summary Get historical bank exchange rates
+// @Description Get a bank's daily exchange rates for a currency for charting
+// @Tags exchange-rates
+// @Produce json
+// @Param bank query string false "Bank code" default(vcb)
+// @Param currency query string true "Currency code (USD, EUR, etc.)"
+// @Param days query int false "Number of days of history" default(90)
+// @Success 200 {array} repository.ExchangeRateHistoryEntry
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /exchange-rates/history [get]
+func (h *ExchangeRateHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	currency, ok := currencyParam(w, r, true)
+	if !ok {
+		return
+	}
+
+	bankCode := r.URL.Query().Get("bank")
+	if bankCode == "" {
+		bankCode = "vcb"
+	}
+
+	days := 90
+	if v := r.URL.Query().Get("days"); v != "" {
+		d, err := strconv.Atoi(v)
+		if err != nil || d < 1 {
+			respondAppError(w, apperror.ValidationError("days", "days must be a positive number"))
+			return
+		}
+		days = d
+	}
+
+	history, err := h.service.GetRateHistory(r.Context(), bankCode, currency, days)
+	if err != nil {
+		respondAppError(w, apperror.Internal(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, history)
+}
+
+// currencyParam returns the upper-cased currency query parameter. It responds
+// with a validation error and returns false if it is not a 3-letter code, or
+// missing when required.
+func currencyParam(w http.ResponseWriter, r *http.Request, required bool) (string, bool) {
+	currency := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("currency")))
+	if currency == "" && !required {
+		return "", true
+	}
+	if len(currency) != 3 || strings.Trim(currency, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		respondAppError(w, apperror.ValidationError("currency", "currency must be a 3-letter currency code"))
+		return "", false
+	}
+	return currency, true
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+)
+
+// MockExchangeRateService implements ExchangeRateServiceInterface for testing
+type MockExchangeRateService struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateService) ListRates(ctx context.Context, bankCode, currency string) ([]model.BankExchangeRate, error) {
+	args := m.Called(ctx, bankCode, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.BankExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateService) GetRateHistory(ctx context.Context, bankCode, currency string, days int) ([]repository.ExchangeRateHistoryEntry, error) {
+	args := m.Called(ctx, bankCode, currency, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.ExchangeRateHistoryEntry), args.Error(1)
+}
+
+func TestExchangeRateHandler_ListRates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		query        string
+		wantBank     string
+		wantCurrency string
+		serviceErr   error
+		wantStatus   int
+	}{
+		{name: "all rates", query: "", wantStatus: http.StatusOK},
+		{name: "filtered", query: "?bank=vcb&currency=usd", wantBank: "vcb", wantCurrency: "USD", wantStatus: http.StatusOK},
+		{name: "invalid currency", query: "?currency=dollars", wantStatus: http.StatusBadRequest},
+		{name: "service error", query: "", serviceErr: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockExchangeRateService)
+			handler := NewExchangeRateHandler(mockService)
+
+			if tt.wantStatus != http.StatusBadRequest {
+				mockService.On("ListRates", mock.Anything, tt.wantBank, tt.wantCurrency).
+					Return([]model.BankExchangeRate{}, tt.serviceErr)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/exchange-rates"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.ListRates(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExchangeRateHandler_GetHistory(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantBank   string
+		wantDays   int
+		wantStatus int
+	}{
+		{name: "defaults", query: "?currency=USD", wantBank: "vcb", wantDays: 90, wantStatus: http.StatusOK},
+		{name: "with filters", query: "?bank=bidv&currency=EUR&days=30", wantBank: "bidv", wantDays: 30, wantStatus: http.StatusOK},
+		{name: "missing currency", query: "", wantStatus: http.StatusBadRequest},
+		{name: "invalid days", query: "?currency=USD&days=0", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockExchangeRateService)
+			handler := NewExchangeRateHandler(mockService)
+
+			if tt.wantStatus == http.StatusOK {
+				mockService.On("GetRateHistory", mock.Anything, tt.wantBank, mock.Anything, tt.wantDays).
+					Return([]repository.ExchangeRateHistoryEntry{}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/exchange-rates/history"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.GetHistory(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
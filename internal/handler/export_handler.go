@@ -37,7 +37,10 @@ func NewExportHandler(exportService *service.ExportService, reportService Report
 // @Param search query string false "Search in description"
 // @Param startDate query string false "Start date (YYYY-MM-DD)"
 // @Param endDate query string false "End date (YYYY-MM-DD)"
+// @Param tags query string false "Comma-separated tags"
+// @Param tagMatch query string false "Match any (default) or all of the tags"
 // @Success 200 {file} file "CSV file"
+// @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /transactions/export/csv [get]
@@ -71,6 +74,16 @@ func (h *ExportHandler) ExportTransactionsCSV(w http.ResponseWriter, r *http.Req
 			filters.EndDate = &t
 		}
 	}
+	if tags := r.URL.Query().Get("tags"); tags != "" {
+		filters.Tags = splitAndTrim(tags, ",")
+	}
+	if tagMatch := r.URL.Query().Get("tagMatch"); tagMatch != "" {
+		if tagMatch != repository.TagMatchAny && tagMatch != repository.TagMatchAll {
+			respondError(w, http.StatusBadRequest, "tagMatch must be any or all")
+			return
+		}
+		filters.TagMatch = tagMatch
+	}
 
 	csvData, err := h.exportService.ExportTransactionsCSV(r.Context(), userID, filters)
 	if err != nil {
@@ -115,7 +128,7 @@ func (h *ExportHandler) ExportMonthlyReportPDF(w http.ResponseWriter, r *http.Re
 	}
 
 	// Get the monthly report data
-	report, err := h.reportService.GetMonthlyReport(r.Context(), userID, year, month)
+	report, err := h.reportService.GetMonthlyReport(r.Context(), userID, year, month, false)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to get monthly report")
 		return
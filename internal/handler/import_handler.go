@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/wealthpath/backend/internal/apperror"
+	"github.com/wealthpath/backend/internal/importer"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// ImportServiceInterface defines the service contract for importing transactions.
+type ImportServiceInterface interface {
+	UploadCSV(ctx context.Context, userID uuid.UUID, fileName string, body io.Reader) (*service.ImportPreview, error)
+	UploadStatement(ctx context.Context, userID uuid.UUID, fileName string, body io.Reader) (*service.ImportPreview, error)
+	Preview(ctx context.Context, userID, id uuid.UUID, settings service.ImportSettings) (*service.ImportPreview, error)
+	Commit(ctx context.Context, userID, id uuid.UUID, input service.CommitImportInput) (*service.ImportResult, error)
+	List(ctx context.Context, userID uuid.UUID) ([]model.ImportBatch, error)
+	Undo(ctx context.Context, userID, id uuid.UUID) (*model.ImportBatch, error)
+}
+
+// ImportHandler handles HTTP requests for importing transactions from files.
+type ImportHandler struct {
+	service ImportServiceInterface
+}
+
+// NewImportHandler creates a new ImportHandler with the given service.
+func NewImportHandler(service ImportServiceInterface) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+// UploadCSV godoc
+// @Summary Upload a CSV file to import
+// @Description Upload a CSV file as the "file" field of a multipart form. Nothing is imported yet: the response previews the rows with the detected delimiter, encoding, date format, number format and column mapping, and flags rows that look like existing transactions.
+// @Tags imports
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV file"
+// @Success 201 {object} service.ImportPreview
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /imports/csv [post]
+func (h *ImportHandler) UploadCSV(w http.ResponseWriter, r *http.Request) {
+	h.upload(w, r, h.service.UploadCSV)
+}
+
+// UploadStatement godoc
+// @Summary Upload an OFX, QFX, QIF or PDF bank statement to import
+// @Description Upload a statement as the "file" field of a multipart form. PDF e-statements from Vietcombank, Techcombank, MB Bank and ACB are read from their transaction table and checked against the running balance. Nothing is imported yet: the response previews the transactions and flags those already imported from an earlier statement, which are always skipped, and those that look like existing transactions.
+// @Tags imports
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "OFX, QFX, QIF or PDF file"
+// @Success 201 {object} service.ImportPreview
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /imports/statement [post]
+func (h *ImportHandler) UploadStatement(w http.ResponseWriter, r *http.Request) {
+	h.upload(w, r, h.service.UploadStatement)
+}
+
+// upload passes the "file" part of a multipart upload to the service
+func (h *ImportHandler) upload(w http.ResponseWriter, r *http.Request,
+	uploadFn func(ctx context.Context, userID uuid.UUID, fileName string, body io.Reader) (*service.ImportPreview, error),
+) {
+	userID := GetUserID(r.Context())
+
+	r.Body = http.MaxBytesReader(w, r.Body, service.MaxImportSize+multipartOverhead)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxErr):
+			respondAppError(w, importError(service.ErrImportTooLarge))
+		case errors.Is(err, http.ErrMissingFile):
+			respondAppError(w, apperror.ValidationError("file", "file is required"))
+		default:
+			respondAppError(w, apperror.BadRequest("expected a multipart/form-data upload"))
+		}
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	preview, err := uploadFn(r.Context(), userID, header.Filename, file)
+	if err != nil {
+		respondAppError(w, importError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, preview)
+}
+
+// Preview godoc
+// @Summary Preview an import with different settings
+// @Description Re-read an uploaded file with the given delimiter, formats and column mapping
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Import ID"
+// @Param settings body service.ImportSettings true "Import settings"
+// @Success 200 {object} service.ImportPreview
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /imports/{id}/preview [post]
+func (h *ImportHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid import ID"))
+		return
+	}
+
+	var settings service.ImportSettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		respondAppError(w, apperror.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	preview, err := h.service.Preview(r.Context(), userID, id, settings)
+	if err != nil {
+		respondAppError(w, importError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, preview)
+}
+
+// Commit godoc
+// @Summary Import the transactions of an uploaded file
+// @Description Create the transactions with the confirmed settings, all at once. Rows that cannot be read are skipped, as are likely duplicates unless includeDuplicates is set.
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Import ID"
+// @Param input body service.CommitImportInput true "Confirmed settings"
+// @Success 200 {object} service.ImportResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /imports/{id}/commit [post]
+func (h *ImportHandler) Commit(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid import ID"))
+		return
+	}
+
+	var input service.CommitImportInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondAppError(w, apperror.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	result, err := h.service.Commit(r.Context(), userID, id, input)
+	if err != nil {
+		respondAppError(w, importError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// List godoc
+// @Summary List imports
+// @Description Get the current user's imports, newest first
+// @Tags imports
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.ImportBatch
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /imports [get]
+func (h *ImportHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	batches, err := h.service.List(r.Context(), userID)
+	if err != nil {
+		respondAppError(w, apperror.Internal(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, batches)
+}
+
+// Undo godoc
+// @Summary Undo an import
+// @Description Delete every transaction the import created
+// @Tags imports
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Import ID"
+// @Success 200 {object} model.ImportBatch
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /imports/{id}/undo [post]
+func (h *ImportHandler) Undo(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid import ID"))
+		return
+	}
+
+	batch, err := h.service.Undo(r.Context(), userID, id)
+	if err != nil {
+		respondAppError(w, importError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, batch)
+}
+
+func importError(err error) *apperror.AppError {
+	switch {
+	case errors.Is(err, service.ErrImportTooLarge):
+		return apperror.PayloadTooLarge(err.Error())
+	case errors.Is(err, service.ErrImportTooManyRows),
+		errors.Is(err, service.ErrNothingToImport),
+		errors.Is(err, importer.ErrEmptyFile),
+		errors.Is(err, importer.ErrMalformedFile),
+		errors.Is(err, importer.ErrUnknownStatementFormat),
+		errors.Is(err, importer.ErrNoTransactions),
+		errors.Is(err, importer.ErrUnsupportedPDF),
+		errors.Is(err, importer.ErrStatementUnbalanced),
+		errors.Is(err, importer.ErrNotAppExport),
+		errors.Is(err, importer.ErrNoMigrationRows):
+		return apperror.ValidationError("file", err.Error())
+	case errors.Is(err, importer.ErrUnknownApp):
+		return apperror.ValidationError("app", err.Error())
+	case errors.Is(err, importer.ErrInvalidMapping):
+		return apperror.ValidationError("mapping", err.Error())
+	case errors.Is(err, importer.ErrInvalidSettings):
+		return apperror.ValidationError("settings", err.Error())
+	case errors.Is(err, repository.ErrImportNotFound):
+		return apperror.NotFound("import")
+	case errors.Is(err, repository.ErrImportNotPending),
+		errors.Is(err, repository.ErrImportNotCommitted),
+		errors.Is(err, repository.ErrAlreadyImported),
+		errors.Is(err, repository.ErrCategoryExists):
+		return apperror.Conflict(err.Error())
+	}
+	if appErr := transactionInputError(err); appErr != nil {
+		return appErr
+	}
+	return apperror.Internal(err)
+}
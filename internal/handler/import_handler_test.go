@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wealthpath/backend/internal/importer"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// MockImportService implements ImportServiceInterface for testing
+type MockImportService struct {
+	mock.Mock
+}
+
+func (m *MockImportService) UploadCSV(ctx context.Context, userID uuid.UUID, fileName string, body io.Reader) (*service.ImportPreview, error) {
+	data, _ := io.ReadAll(body)
+	args := m.Called(ctx, userID, fileName, string(data))
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.ImportPreview), args.Error(1)
+}
+
+func (m *MockImportService) UploadStatement(ctx context.Context, userID uuid.UUID, fileName string, body io.Reader) (*service.ImportPreview, error) {
+	data, _ := io.ReadAll(body)
+	args := m.Called(ctx, userID, fileName, string(data))
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.ImportPreview), args.Error(1)
+}
+
+func (m *MockImportService) Preview(ctx context.Context, userID, id uuid.UUID, settings service.ImportSettings) (*service.ImportPreview, error) {
+	args := m.Called(ctx, userID, id, settings)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.ImportPreview), args.Error(1)
+}
+
+func (m *MockImportService) Commit(ctx context.Context, userID, id uuid.UUID, input service.CommitImportInput) (*service.ImportResult, error) {
+	args := m.Called(ctx, userID, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.ImportResult), args.Error(1)
+}
+
+func (m *MockImportService) List(ctx context.Context, userID uuid.UUID) ([]model.ImportBatch, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ImportBatch), args.Error(1)
+}
+
+func (m *MockImportService) Undo(ctx context.Context, userID, id uuid.UUID) (*model.ImportBatch, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImportBatch), args.Error(1)
+}
+
+func TestImportHandler_UploadCSV(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		field      string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", field: "file", wantStatus: http.StatusCreated},
+		{name: "missing file", field: "upload", wantStatus: http.StatusBadRequest},
+		{name: "empty file", field: "file", serviceErr: importer.ErrEmptyFile, wantStatus: http.StatusBadRequest},
+		{name: "too large", field: "file", serviceErr: service.ErrImportTooLarge, wantStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockImportService)
+			handler := NewImportHandler(mockService)
+			userID := uuid.New()
+
+			body, contentType := multipartBody(t, tt.field, "vcb.csv", "date,amount\n2024-03-05,10\n")
+			call := mockService.On("UploadCSV", mock.Anything, userID, "vcb.csv", "date,amount\n2024-03-05,10\n").Maybe()
+			if tt.serviceErr != nil {
+				call.Return(nil, tt.serviceErr)
+			} else {
+				call.Return(&service.ImportPreview{Batch: &model.ImportBatch{ID: uuid.New()}}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/imports/csv", body)
+			req.Header.Set("Content-Type", contentType)
+			req = req.WithContext(ctxWithUserID(userID))
+			w := httptest.NewRecorder()
+
+			handler.UploadCSV(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestImportHandler_Commit(t *testing.T) {
+	t.Parallel()
+
+	importID := uuid.New()
+
+	tests := []struct {
+		name       string
+		id         string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", id: importID.String(), body: `{"settings":{"delimiter":","},"includeDuplicates":true}`, wantStatus: http.StatusOK},
+		{name: "invalid id", id: "abc", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid body", id: importID.String(), body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "bad mapping", id: importID.String(), body: `{}`, serviceErr: importer.ErrInvalidMapping, wantStatus: http.StatusBadRequest},
+		{name: "unknown account", id: importID.String(), body: `{}`, serviceErr: repository.ErrAccountNotFound, wantStatus: http.StatusBadRequest},
+		{name: "not found", id: importID.String(), body: `{}`, serviceErr: repository.ErrImportNotFound, wantStatus: http.StatusNotFound},
+		{name: "already committed", id: importID.String(), body: `{}`, serviceErr: repository.ErrImportNotPending, wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockImportService)
+			handler := NewImportHandler(mockService)
+			userID := uuid.New()
+
+			call := mockService.On("Commit", mock.Anything, userID, importID, mock.Anything).Maybe()
+			if tt.serviceErr != nil {
+				call.Return(nil, tt.serviceErr)
+			} else {
+				call.Return(&service.ImportResult{Imported: 3}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/imports/"+tt.id+"/commit", strings.NewReader(tt.body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			req = req.WithContext(context.WithValue(ctxWithUserID(userID), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.Commit(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.name == "success" {
+				input := mockService.Calls[0].Arguments.Get(3).(service.CommitImportInput)
+				assert.True(t, input.IncludeDuplicates)
+				assert.Equal(t, ",", input.Settings.Delimiter)
+			}
+		})
+	}
+}
+
+func TestImportHandler_Undo(t *testing.T) {
+	t.Parallel()
+
+	mockService := new(MockImportService)
+	handler := NewImportHandler(mockService)
+	userID, importID := uuid.New(), uuid.New()
+
+	mockService.On("Undo", mock.Anything, userID, importID).Return(nil, repository.ErrImportNotCommitted)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/imports/"+importID.String()+"/undo", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", importID.String())
+	req = req.WithContext(context.WithValue(ctxWithUserID(userID), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	handler.Undo(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
@@ -17,6 +17,7 @@ type BudgetServiceInterface interface {
 	ListWithSpent(ctx context.Context, userID uuid.UUID) ([]model.BudgetWithSpent, error)
 	Update(ctx context.Context, id, userID uuid.UUID, input service.UpdateBudgetInput) (*model.Budget, error)
 	Delete(ctx context.Context, id, userID uuid.UUID) error
+	ListRollovers(ctx context.Context, id, userID uuid.UUID) ([]model.BudgetRollover, error)
 }
 
 // DebtServiceInterface for handler testing
@@ -27,6 +28,7 @@ type DebtServiceInterface interface {
 	Update(ctx context.Context, id, userID uuid.UUID, input service.UpdateDebtInput) (*model.Debt, error)
 	Delete(ctx context.Context, id, userID uuid.UUID) error
 	MakePayment(ctx context.Context, id, userID uuid.UUID, input service.MakePaymentInput) (*model.Debt, error)
+	ListPayments(ctx context.Context, id, userID uuid.UUID) ([]model.DebtPayment, error)
 	GetPayoffPlan(ctx context.Context, id uuid.UUID, monthlyPayment decimal.Decimal) (*model.PayoffPlan, error)
 	GetDebtSummary(ctx context.Context, userID uuid.UUID) (*service.DebtSummary, error)
 	CalculateInterest(input service.InterestCalculatorInput) (*service.InterestCalculatorResult, error)
@@ -40,7 +42,10 @@ type SavingsGoalServiceInterface interface {
 	ListWithProjections(ctx context.Context, userID uuid.UUID) ([]service.SavingsGoalWithProjection, error)
 	Update(ctx context.Context, id, userID uuid.UUID, input service.UpdateSavingsGoalInput) (*model.SavingsGoal, error)
 	Delete(ctx context.Context, id, userID uuid.UUID) error
-	Contribute(ctx context.Context, id, userID uuid.UUID, amount decimal.Decimal) (*model.SavingsGoal, error)
+	Contribute(ctx context.Context, id, userID uuid.UUID, input service.ContributeInput) (*model.SavingsGoal, error)
+	Withdraw(ctx context.Context, id, userID uuid.UUID, input service.ContributeInput) (*model.SavingsGoal, error)
+	ListContributions(ctx context.Context, id, userID uuid.UUID, limit, offset int) ([]model.SavingsContribution, error)
+	ReverseContribution(ctx context.Context, goalID, contributionID, userID uuid.UUID) (*model.SavingsGoal, error)
 }
 
 // RecurringServiceInterface for handler testing
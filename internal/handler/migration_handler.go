@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/wealthpath/backend/internal/apperror"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// MigrationServiceInterface defines the service contract for migrating from other apps.
+type MigrationServiceInterface interface {
+	Import(ctx context.Context, userID uuid.UUID, input service.MigrationInput, body io.Reader) (*service.MigrationReport, error)
+}
+
+// MigrationHandler handles HTTP requests for migrating data exported from other apps.
+type MigrationHandler struct {
+	service MigrationServiceInterface
+}
+
+// NewMigrationHandler creates a new MigrationHandler with the given service.
+func NewMigrationHandler(service MigrationServiceInterface) *MigrationHandler {
+	return &MigrationHandler{service: service}
+}
+
+// Import godoc
+// @Summary Import an export from Money Lover or MISA MoneyKeeper
+// @Description Upload a Money Lover CSV or a MISA MoneyKeeper Excel or CSV export as the "file" field of a multipart form. Wallets, categories, transactions, transfers, debts and budgets are matched to existing data and created when missing, in one step that can be undone like any import. Records imported before are skipped. With dryRun, the report shows what would happen without saving anything.
+// @Tags imports
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Export file"
+// @Param app formData string true "App the file was exported from" Enums(moneylover, misa)
+// @Param currency formData string false "Currency for records that do not say (default VND)"
+// @Param dryRun formData bool false "Report without importing"
+// @Success 200 {object} service.MigrationReport "Dry run, or nothing to import"
+// @Success 201 {object} service.MigrationReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /imports/migrations [post]
+func (h *MigrationHandler) Import(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	r.Body = http.MaxBytesReader(w, r.Body, service.MaxImportSize+multipartOverhead)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxErr):
+			respondAppError(w, importError(service.ErrImportTooLarge))
+		case errors.Is(err, http.ErrMissingFile):
+			respondAppError(w, apperror.ValidationError("file", "file is required"))
+		default:
+			respondAppError(w, apperror.BadRequest("expected a multipart/form-data upload"))
+		}
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	input := service.MigrationInput{
+		App:      r.FormValue("app"),
+		FileName: header.Filename,
+		Currency: r.FormValue("currency"),
+	}
+	if v := r.FormValue("dryRun"); v != "" {
+		if input.DryRun, err = strconv.ParseBool(v); err != nil {
+			respondAppError(w, apperror.ValidationError("dryRun", "must be true or false"))
+			return
+		}
+	}
+
+	report, err := h.service.Import(r.Context(), userID, input, file)
+	if err != nil {
+		respondAppError(w, importError(err))
+		return
+	}
+
+	status := http.StatusOK
+	if report.Batch != nil {
+		status = http.StatusCreated
+	}
+	respondJSON(w, status, report)
+}
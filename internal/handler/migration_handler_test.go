@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/importer"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// MockMigrationService implements MigrationServiceInterface for testing
+type MockMigrationService struct {
+	mock.Mock
+}
+
+func (m *MockMigrationService) Import(ctx context.Context, userID uuid.UUID, input service.MigrationInput, body io.Reader) (*service.MigrationReport, error) {
+	data, _ := io.ReadAll(body)
+	args := m.Called(ctx, userID, input, string(data))
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.MigrationReport), args.Error(1)
+}
+
+func TestMigrationHandler_Import(t *testing.T) {
+	t.Parallel()
+
+	const export = "Id,Date,Category,Amount\n1,01/03/2024,Food,-50000\n"
+
+	tests := []struct {
+		name       string
+		field      string
+		dryRun     string
+		report     *service.MigrationReport
+		serviceErr error
+		wantInput  service.MigrationInput
+		wantStatus int
+	}{
+		{
+			name:       "imported",
+			field:      "file",
+			report:     &service.MigrationReport{Batch: &model.ImportBatch{ID: uuid.New()}},
+			wantInput:  service.MigrationInput{App: importer.AppMoneyLover, FileName: "ml.csv", Currency: "VND"},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "dry run",
+			field:      "file",
+			dryRun:     "true",
+			report:     &service.MigrationReport{DryRun: true},
+			wantInput:  service.MigrationInput{App: importer.AppMoneyLover, FileName: "ml.csv", Currency: "VND", DryRun: true},
+			wantStatus: http.StatusOK,
+		},
+		{name: "invalid dry run", field: "file", dryRun: "maybe", wantStatus: http.StatusBadRequest},
+		{name: "missing file", field: "upload", wantStatus: http.StatusBadRequest},
+		{
+			name:       "unknown app",
+			field:      "file",
+			serviceErr: importer.ErrUnknownApp,
+			wantInput:  service.MigrationInput{App: importer.AppMoneyLover, FileName: "ml.csv", Currency: "VND"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "export of another app",
+			field:      "file",
+			serviceErr: importer.ErrNotAppExport,
+			wantInput:  service.MigrationInput{App: importer.AppMoneyLover, FileName: "ml.csv", Currency: "VND"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "too large",
+			field:      "file",
+			serviceErr: service.ErrImportTooLarge,
+			wantInput:  service.MigrationInput{App: importer.AppMoneyLover, FileName: "ml.csv", Currency: "VND"},
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockMigrationService)
+			handler := NewMigrationHandler(mockService)
+			userID := uuid.New()
+
+			var buf bytes.Buffer
+			mw := multipart.NewWriter(&buf)
+			require.NoError(t, mw.WriteField("app", importer.AppMoneyLover))
+			require.NoError(t, mw.WriteField("currency", "VND"))
+			if tt.dryRun != "" {
+				require.NoError(t, mw.WriteField("dryRun", tt.dryRun))
+			}
+			fw, err := mw.CreateFormFile(tt.field, "ml.csv")
+			require.NoError(t, err)
+			_, err = io.WriteString(fw, export)
+			require.NoError(t, err)
+			require.NoError(t, mw.Close())
+
+			mockService.On("Import", mock.Anything, userID, tt.wantInput, export).Return(tt.report, tt.serviceErr).Maybe()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/imports/migrations", &buf)
+			req.Header.Set("Content-Type", mw.FormDataContentType())
+			req = req.WithContext(ctxWithUserID(userID))
+			w := httptest.NewRecorder()
+
+			handler.Import(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
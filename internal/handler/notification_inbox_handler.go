@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/wealthpath/backend/internal/apperror"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// NotificationInboxServiceInterface defines the service contract for the notification inbox.
+type NotificationInboxServiceInterface interface {
+	List(ctx context.Context, userID uuid.UUID, input service.ListNotificationsInput) (*service.NotificationInboxPage, error)
+	UnreadCount(ctx context.Context, userID uuid.UUID) (int, error)
+	MarkRead(ctx context.Context, userID, id uuid.UUID) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID, notifType *model.NotificationType) (int64, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+}
+
+// NotificationInboxHandler handles HTTP requests for the in-app notification inbox.
+type NotificationInboxHandler struct {
+	service NotificationInboxServiceInterface
+}
+
+// NewNotificationInboxHandler creates a new NotificationInboxHandler with the given service.
+func NewNotificationInboxHandler(service NotificationInboxServiceInterface) *NotificationInboxHandler {
+	return &NotificationInboxHandler{service: service}
+}
+
+// List godoc
+// @Summary List notifications
+// @Description Get the notifications sent to the current user, newest first
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param type query string false "Only this notification type (e.g. bill_reminder, budget_alert)"
+// @Param unread query bool false "Only unread notifications"
+// @Param limit query int false "Number of results" default(20)
+// @Param offset query int false "Number of results to skip" default(0)
+// @Success 200 {object} service.NotificationInboxPage
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /notifications [get]
+func (h *NotificationInboxHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+	query := r.URL.Query()
+
+	input := service.ListNotificationsInput{Type: notificationTypeParam(r)}
+	if unread := query.Get("unread"); unread != "" {
+		b, err := strconv.ParseBool(unread)
+		if err != nil {
+			respondAppError(w, apperror.BadRequest("invalid unread"))
+			return
+		}
+		input.UnreadOnly = b
+	}
+	input.Limit, _ = strconv.Atoi(query.Get("limit"))
+	input.Offset, _ = strconv.Atoi(query.Get("offset"))
+
+	page, err := h.service.List(r.Context(), userID, input)
+	if err != nil {
+		respondAppError(w, notificationInboxError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, page)
+}
+
+// UnreadCount godoc
+// @Summary Unread notification count
+// @Description Get the number of unread notifications of the current user
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]int
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /notifications/unread-count [get]
+func (h *NotificationInboxHandler) UnreadCount(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	count, err := h.service.UnreadCount(r.Context(), userID)
+	if err != nil {
+		respondAppError(w, apperror.Internal(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]int{"count": count})
+}
+
+// MarkRead godoc
+// @Summary Mark a notification read
+// @Tags notifications
+// @Security BearerAuth
+// @Param id path string true "Notification ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /notifications/{id}/read [post]
+func (h *NotificationInboxHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid notification ID"))
+		return
+	}
+
+	if err := h.service.MarkRead(r.Context(), userID, id); err != nil {
+		respondAppError(w, notificationInboxError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MarkAllRead godoc
+// @Summary Mark all notifications read
+// @Description Mark every unread notification as read, optionally only one type
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param type query string false "Only this notification type"
+// @Success 200 {object} map[string]int64
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /notifications/read-all [post]
+func (h *NotificationInboxHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	marked, err := h.service.MarkAllRead(r.Context(), userID, notificationTypeParam(r))
+	if err != nil {
+		respondAppError(w, notificationInboxError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]int64{"marked": marked})
+}
+
+// Delete godoc
+// @Summary Delete a notification
+// @Description Remove a notification from the current user's inbox
+// @Tags notifications
+// @Security BearerAuth
+// @Param id path string true "Notification ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /notifications/{id} [delete]
+func (h *NotificationInboxHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid notification ID"))
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), userID, id); err != nil {
+		respondAppError(w, notificationInboxError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// notificationTypeParam reads the optional type query parameter
+func notificationTypeParam(r *http.Request) *model.NotificationType {
+	t := r.URL.Query().Get("type")
+	if t == "" {
+		return nil
+	}
+	notifType := model.NotificationType(t)
+	return &notifType
+}
+
+// notificationInboxError maps service errors to API errors.
+func notificationInboxError(err error) *apperror.AppError {
+	switch {
+	case errors.Is(err, repository.ErrNotificationNotFound):
+		return apperror.NotFound("notification")
+	case errors.Is(err, service.ErrInvalidNotificationType):
+		return apperror.ValidationError("type", err.Error())
+	default:
+		return apperror.Internal(err)
+	}
+}
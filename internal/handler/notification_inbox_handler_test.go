@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// MockNotificationInboxService implements NotificationInboxServiceInterface for testing
+type MockNotificationInboxService struct {
+	mock.Mock
+}
+
+func (m *MockNotificationInboxService) List(ctx context.Context, userID uuid.UUID, input service.ListNotificationsInput) (*service.NotificationInboxPage, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.NotificationInboxPage), args.Error(1)
+}
+
+func (m *MockNotificationInboxService) UnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockNotificationInboxService) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockNotificationInboxService) MarkAllRead(ctx context.Context, userID uuid.UUID, notifType *model.NotificationType) (int64, error) {
+	args := m.Called(ctx, userID, notifType)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationInboxService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func TestNotificationInboxHandler_List(t *testing.T) {
+	t.Parallel()
+
+	billReminder := model.NotificationTypeBillReminder
+
+	tests := []struct {
+		name       string
+		query      string
+		setupMock  func(*MockNotificationInboxService, uuid.UUID)
+		wantStatus int
+	}{
+		{
+			name:  "success with filters",
+			query: "?type=bill_reminder&unread=true&limit=10&offset=20",
+			setupMock: func(m *MockNotificationInboxService, userID uuid.UUID) {
+				m.On("List", mock.Anything, userID, service.ListNotificationsInput{
+					Type: &billReminder, UnreadOnly: true, Limit: 10, Offset: 20,
+				}).Return(&service.NotificationInboxPage{
+					Notifications: []model.NotificationLog{{ID: uuid.New(), NotificationType: billReminder}},
+					Total:         21,
+					UnreadCount:   3,
+					Limit:         10,
+					Offset:        20,
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid unread flag",
+			query:      "?unread=maybe",
+			setupMock:  func(m *MockNotificationInboxService, userID uuid.UUID) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "unknown type",
+			query: "?type=spam",
+			setupMock: func(m *MockNotificationInboxService, userID uuid.UUID) {
+				m.On("List", mock.Anything, userID, mock.Anything).Return(nil, service.ErrInvalidNotificationType)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "service error",
+			query: "",
+			setupMock: func(m *MockNotificationInboxService, userID uuid.UUID) {
+				m.On("List", mock.Anything, userID, mock.Anything).Return(nil, errors.New("db error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockNotificationInboxService)
+			handler := NewNotificationInboxHandler(mockService)
+			userID := uuid.New()
+
+			tt.setupMock(mockService, userID)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/notifications"+tt.query, nil)
+			req = req.WithContext(ctxWithUserID(userID))
+			w := httptest.NewRecorder()
+
+			handler.List(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				var page service.NotificationInboxPage
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&page))
+				assert.Len(t, page.Notifications, 1)
+				assert.Equal(t, 21, page.Total)
+				assert.Equal(t, 3, page.UnreadCount)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationInboxHandler_UnreadCount(t *testing.T) {
+	t.Parallel()
+
+	mockService := new(MockNotificationInboxService)
+	handler := NewNotificationInboxHandler(mockService)
+	userID := uuid.New()
+
+	mockService.On("UnreadCount", mock.Anything, userID).Return(4, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/notifications/unread-count", nil)
+	req = req.WithContext(ctxWithUserID(userID))
+	w := httptest.NewRecorder()
+
+	handler.UnreadCount(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body map[string]int
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Equal(t, 4, body["count"])
+	mockService.AssertExpectations(t)
+}
+
+func TestNotificationInboxHandler_MarkRead(t *testing.T) {
+	t.Parallel()
+
+	notificationID := uuid.New()
+
+	tests := []struct {
+		name       string
+		id         string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", id: notificationID.String(), wantStatus: http.StatusNoContent},
+		{name: "invalid id", id: "abc", wantStatus: http.StatusBadRequest},
+		{name: "not found", id: notificationID.String(), serviceErr: repository.ErrNotificationNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockNotificationInboxService)
+			handler := NewNotificationInboxHandler(mockService)
+			userID := uuid.New()
+
+			if id, err := uuid.Parse(tt.id); err == nil {
+				mockService.On("MarkRead", mock.Anything, userID, id).Return(tt.serviceErr)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/notifications/"+tt.id+"/read", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			req = req.WithContext(context.WithValue(ctxWithUserID(userID), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.MarkRead(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationInboxHandler_MarkAllRead(t *testing.T) {
+	t.Parallel()
+
+	mockService := new(MockNotificationInboxService)
+	handler := NewNotificationInboxHandler(mockService)
+	userID := uuid.New()
+	budgetAlert := model.NotificationTypeBudgetAlert
+
+	mockService.On("MarkAllRead", mock.Anything, userID, &budgetAlert).Return(int64(5), nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/notifications/read-all?type=budget_alert", nil)
+	req = req.WithContext(ctxWithUserID(userID))
+	w := httptest.NewRecorder()
+
+	handler.MarkAllRead(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body map[string]int64
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Equal(t, int64(5), body["marked"])
+	mockService.AssertExpectations(t)
+}
+
+func TestNotificationInboxHandler_Delete(t *testing.T) {
+	t.Parallel()
+
+	notificationID := uuid.New()
+
+	tests := []struct {
+		name       string
+		id         string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", id: notificationID.String(), wantStatus: http.StatusNoContent},
+		{name: "invalid id", id: "abc", wantStatus: http.StatusBadRequest},
+		{name: "not found", id: notificationID.String(), serviceErr: repository.ErrNotificationNotFound, wantStatus: http.StatusNotFound},
+		{name: "service error", id: notificationID.String(), serviceErr: errors.New("db error"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockNotificationInboxService)
+			handler := NewNotificationInboxHandler(mockService)
+			userID := uuid.New()
+
+			if id, err := uuid.Parse(tt.id); err == nil {
+				mockService.On("Delete", mock.Anything, userID, id).Return(tt.serviceErr)
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/notifications/"+tt.id, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			req = req.WithContext(context.WithValue(ctxWithUserID(userID), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.Delete(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/wealthpath/backend/internal/apperror"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// RateSubscriptionServiceInterface defines the service contract for interest rate subscriptions.
+type RateSubscriptionServiceInterface interface {
+	CreateSubscription(ctx context.Context, userID uuid.UUID, input service.CreateRateSubscriptionInput) (*model.RateSubscription, error)
+	GetSubscription(ctx context.Context, userID uuid.UUID, subscriptionID int64) (*model.RateSubscription, error)
+	GetUserSubscriptions(ctx context.Context, userID uuid.UUID) ([]model.RateSubscription, error)
+	UpdateSubscription(ctx context.Context, userID uuid.UUID, subscriptionID int64, input service.UpdateRateSubscriptionInput) (*model.RateSubscription, error)
+	Unsubscribe(ctx context.Context, userID uuid.UUID, subscriptionID int64) error
+	GetNotificationHistory(ctx context.Context, userID uuid.UUID, subscriptionID *int64, limit, offset int) ([]model.RateNotificationHistory, error)
+}
+
+// RateSubscriptionHandler handles HTTP requests for interest rate change subscriptions.
+type RateSubscriptionHandler struct {
+	service RateSubscriptionServiceInterface
+}
+
+// NewRateSubscriptionHandler creates a new RateSubscriptionHandler with the given service.
+func NewRateSubscriptionHandler(service RateSubscriptionServiceInterface) *RateSubscriptionHandler {
+	return &RateSubscriptionHandler{service: service}
+}
+
+// Create godoc
+// @Summary Subscribe to rate changes
+// @Description Subscribe to change alerts for a bank, product and term. Subscribing again to the same combination updates it.
+// @Tags interest-rates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body service.CreateRateSubscriptionInput true "Subscription data"
+// @Success 201 {object} model.RateSubscription
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /interest-rates/subscriptions [post]
+func (h *RateSubscriptionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	var input service.CreateRateSubscriptionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondAppError(w, apperror.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	sub, err := h.service.CreateSubscription(r.Context(), userID, input)
+	if err != nil {
+		respondAppError(w, rateSubscriptionError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, sub)
+}
+
+// List godoc
+// @Summary List rate subscriptions
+// @Description Get all interest rate subscriptions of the current user
+// @Tags interest-rates
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.RateSubscription
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /interest-rates/subscriptions [get]
+func (h *RateSubscriptionHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	subs, err := h.service.GetUserSubscriptions(r.Context(), userID)
+	if err != nil {
+		respondAppError(w, apperror.Internal(err))
+		return
+	}
+	if subs == nil {
+		subs = []model.RateSubscription{}
+	}
+
+	respondJSON(w, http.StatusOK, subs)
+}
+
+// Get godoc
+// @Summary Get a rate subscription
+// @Description Get an interest rate subscription by ID
+// @Tags interest-rates
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} model.RateSubscription
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /interest-rates/subscriptions/{id} [get]
+func (h *RateSubscriptionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid subscription ID"))
+		return
+	}
+
+	sub, err := h.service.GetSubscription(r.Context(), userID, id)
+	if err != nil {
+		respondAppError(w, rateSubscriptionError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, sub)
+}
+
+// Update godoc
+// @Summary Update a rate subscription
+// @Description Change the threshold and delivery options of a subscription
+// @Tags interest-rates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Subscription ID"
+// @Param input body service.UpdateRateSubscriptionInput true "Updated subscription data"
+// @Success 200 {object} model.RateSubscription
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /interest-rates/subscriptions/{id} [put]
+func (h *RateSubscriptionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid subscription ID"))
+		return
+	}
+
+	var input service.UpdateRateSubscriptionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondAppError(w, apperror.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	sub, err := h.service.UpdateSubscription(r.Context(), userID, id, input)
+	if err != nil {
+		respondAppError(w, rateSubscriptionError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, sub)
+}
+
+// Delete godoc
+// @Summary Delete a rate subscription
+// @Description Stop receiving alerts for a subscription
+// @Tags interest-rates
+// @Security BearerAuth
+// @Param id path int true "Subscription ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /interest-rates/subscriptions/{id} [delete]
+func (h *RateSubscriptionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid subscription ID"))
+		return
+	}
+
+	if err := h.service.Unsubscribe(r.Context(), userID, id); err != nil {
+		respondAppError(w, rateSubscriptionError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// History godoc
+// @Summary Rate alert history
+// @Description Get the rate change alerts already sent to the current user
+// @Tags interest-rates
+// @Produce json
+// @Security BearerAuth
+// @Param subscriptionId query int false "Only alerts for this subscription"
+// @Param limit query int false "Number of results" default(20)
+// @Param offset query int false "Number of results to skip" default(0)
+// @Success 200 {array} model.RateNotificationHistory
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /interest-rates/subscriptions/history [get]
+func (h *RateSubscriptionHandler) History(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+	query := r.URL.Query()
+
+	var subscriptionID *int64
+	if idStr := query.Get("subscriptionId"); idStr != "" {
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			respondAppError(w, apperror.BadRequest("invalid subscriptionId"))
+			return
+		}
+		subscriptionID = &id
+	}
+
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+
+	history, err := h.service.GetNotificationHistory(r.Context(), userID, subscriptionID, limit, offset)
+	if err != nil {
+		respondAppError(w, apperror.Internal(err))
+		return
+	}
+	if history == nil {
+		history = []model.RateNotificationHistory{}
+	}
+
+	respondJSON(w, http.StatusOK, history)
+}
+
+// rateSubscriptionError maps service errors to API errors.
+func rateSubscriptionError(err error) *apperror.AppError {
+	switch {
+	case errors.Is(err, repository.ErrRateSubscriptionNotFound):
+		return apperror.NotFound("subscription")
+	case errors.Is(err, service.ErrInvalidBankCode):
+		return apperror.ValidationError("bankCode", err.Error())
+	case errors.Is(err, service.ErrInvalidProductType):
+		return apperror.ValidationError("productType", err.Error())
+	case errors.Is(err, service.ErrInvalidTermMonths):
+		return apperror.ValidationError("termMonths", err.Error())
+	case errors.Is(err, service.ErrInvalidThresholdPercent):
+		return apperror.ValidationError("thresholdPercent", err.Error())
+	default:
+		return apperror.Internal(err)
+	}
+}
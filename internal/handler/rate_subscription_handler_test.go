@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// MockRateSubscriptionService implements RateSubscriptionServiceInterface for testing
+type MockRateSubscriptionService struct {
+	mock.Mock
+}
+
+func (m *MockRateSubscriptionService) CreateSubscription(ctx context.Context, userID uuid.UUID, input service.CreateRateSubscriptionInput) (*model.RateSubscription, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RateSubscription), args.Error(1)
+}
+
+func (m *MockRateSubscriptionService) GetSubscription(ctx context.Context, userID uuid.UUID, subscriptionID int64) (*model.RateSubscription, error) {
+	args := m.Called(ctx, userID, subscriptionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RateSubscription), args.Error(1)
+}
+
+func (m *MockRateSubscriptionService) GetUserSubscriptions(ctx context.Context, userID uuid.UUID) ([]model.RateSubscription, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.RateSubscription), args.Error(1)
+}
+
+func (m *MockRateSubscriptionService) UpdateSubscription(ctx context.Context, userID uuid.UUID, subscriptionID int64, input service.UpdateRateSubscriptionInput) (*model.RateSubscription, error) {
+	args := m.Called(ctx, userID, subscriptionID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RateSubscription), args.Error(1)
+}
+
+func (m *MockRateSubscriptionService) Unsubscribe(ctx context.Context, userID uuid.UUID, subscriptionID int64) error {
+	args := m.Called(ctx, userID, subscriptionID)
+	return args.Error(0)
+}
+
+func (m *MockRateSubscriptionService) GetNotificationHistory(ctx context.Context, userID uuid.UUID, subscriptionID *int64, limit, offset int) ([]model.RateNotificationHistory, error) {
+	args := m.Called(ctx, userID, subscriptionID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.RateNotificationHistory), args.Error(1)
+}
+
+func TestRateSubscriptionHandler_Create(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		body       interface{}
+		setupMock  func(*MockRateSubscriptionService, uuid.UUID)
+		wantStatus int
+	}{
+		{
+			name: "success",
+			body: map[string]interface{}{"bankCode": "tcb", "termMonths": 12, "thresholdPercent": "0.2"},
+			setupMock: func(m *MockRateSubscriptionService, userID uuid.UUID) {
+				m.On("CreateSubscription", mock.Anything, userID, mock.AnythingOfType("service.CreateRateSubscriptionInput")).
+					Return(&model.RateSubscription{ID: 1, UserID: userID, BankCode: "tcb", TermMonths: 12}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "invalid body",
+			body:       "invalid json",
+			setupMock:  func(m *MockRateSubscriptionService, userID uuid.UUID) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unknown bank",
+			body: map[string]interface{}{"bankCode": "xyz", "termMonths": 12},
+			setupMock: func(m *MockRateSubscriptionService, userID uuid.UUID) {
+				m.On("CreateSubscription", mock.Anything, userID, mock.AnythingOfType("service.CreateRateSubscriptionInput")).
+					Return(nil, service.ErrInvalidBankCode)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "service error",
+			body: map[string]interface{}{"bankCode": "tcb", "termMonths": 12},
+			setupMock: func(m *MockRateSubscriptionService, userID uuid.UUID) {
+				m.On("CreateSubscription", mock.Anything, userID, mock.AnythingOfType("service.CreateRateSubscriptionInput")).
+					Return(nil, errors.New("db error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockRateSubscriptionService)
+			handler := NewRateSubscriptionHandler(mockService)
+			userID := uuid.New()
+
+			tt.setupMock(mockService, userID)
+
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPost, "/api/interest-rates/subscriptions", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req = req.WithContext(ctxWithUserID(userID))
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRateSubscriptionHandler_Delete(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		id         string
+		setupMock  func(*MockRateSubscriptionService, uuid.UUID)
+		wantStatus int
+	}{
+		{
+			name: "success",
+			id:   "7",
+			setupMock: func(m *MockRateSubscriptionService, userID uuid.UUID) {
+				m.On("Unsubscribe", mock.Anything, userID, int64(7)).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "invalid id",
+			id:         "abc",
+			setupMock:  func(m *MockRateSubscriptionService, userID uuid.UUID) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   "7",
+			setupMock: func(m *MockRateSubscriptionService, userID uuid.UUID) {
+				m.On("Unsubscribe", mock.Anything, userID, int64(7)).Return(repository.ErrRateSubscriptionNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockRateSubscriptionService)
+			handler := NewRateSubscriptionHandler(mockService)
+			userID := uuid.New()
+
+			tt.setupMock(mockService, userID)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/interest-rates/subscriptions/"+tt.id, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			ctx := context.WithValue(ctxWithUserID(userID), chi.RouteCtxKey, rctx)
+			req = req.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			handler.Delete(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRateSubscriptionHandler_History(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		query      string
+		setupMock  func(*MockRateSubscriptionService, uuid.UUID)
+		wantStatus int
+	}{
+		{
+			name:  "all subscriptions",
+			query: "?limit=10&offset=20",
+			setupMock: func(m *MockRateSubscriptionService, userID uuid.UUID) {
+				m.On("GetNotificationHistory", mock.Anything, userID, (*int64)(nil), 10, 20).
+					Return([]model.RateNotificationHistory{{BankCode: "tcb"}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:  "filtered by subscription",
+			query: "?subscriptionId=3",
+			setupMock: func(m *MockRateSubscriptionService, userID uuid.UUID) {
+				m.On("GetNotificationHistory", mock.Anything, userID, mock.MatchedBy(func(id *int64) bool {
+					return id != nil && *id == 3
+				}), 0, 0).Return(nil, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid subscription id",
+			query:      "?subscriptionId=abc",
+			setupMock:  func(m *MockRateSubscriptionService, userID uuid.UUID) {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockRateSubscriptionService)
+			handler := NewRateSubscriptionHandler(mockService)
+			userID := uuid.New()
+
+			tt.setupMock(mockService, userID)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/interest-rates/subscriptions/history"+tt.query, nil)
+			req = req.WithContext(ctxWithUserID(userID))
+			w := httptest.NewRecorder()
+
+			handler.History(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
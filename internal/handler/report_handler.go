@@ -11,8 +11,9 @@ import (
 
 // ReportServiceInterface defines the contract for report business logic.
 type ReportServiceInterface interface {
-	GetMonthlyReport(ctx context.Context, userID uuid.UUID, year, month int) (*service.MonthlyReport, error)
-	GetCategoryTrends(ctx context.Context, userID uuid.UUID, months, limit int) (*service.CategoryTrendsResponse, error)
+	GetMonthlyReport(ctx context.Context, userID uuid.UUID, year, month int, byParent bool) (*service.MonthlyReport, error)
+	GetCategoryTrends(ctx context.Context, userID uuid.UUID, months, limit int, byParent bool) (*service.CategoryTrendsResponse, error)
+	GetTagBreakdown(ctx context.Context, userID uuid.UUID, months int) (*service.TagBreakdownResponse, error)
 }
 
 // ReportHandler handles HTTP requests for financial reports.
@@ -33,6 +34,7 @@ func NewReportHandler(reportService ReportServiceInterface) *ReportHandler {
 // @Security BearerAuth
 // @Param year query int true "Year for the report (e.g., 2026)"
 // @Param month query int true "Month for the report (1-12)"
+// @Param groupBy query string false "category (default) or parent to roll subcategories up into their parent"
 // @Success 200 {object} service.MonthlyReport
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
@@ -99,7 +101,12 @@ func (h *ReportHandler) GetMonthlyReport(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	report, err := h.reportService.GetMonthlyReport(r.Context(), userID, year, month)
+	byParent, ok := parseCategoryGrouping(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.reportService.GetMonthlyReport(r.Context(), userID, year, month, byParent)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to generate report")
 		return
@@ -116,6 +123,7 @@ func (h *ReportHandler) GetMonthlyReport(w http.ResponseWriter, r *http.Request)
 // @Security BearerAuth
 // @Param months query int false "Number of months to include (default: 6, max: 24)"
 // @Param limit query int false "Number of categories to return (default: 10, max: 20)"
+// @Param groupBy query string false "category (default) or parent to roll subcategories up into their parent"
 // @Success 200 {object} service.CategoryTrendsResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
@@ -128,25 +136,9 @@ func (h *ReportHandler) GetCategoryTrends(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// Parse months parameter (optional, default 6)
-	months := 6
-	if monthsStr := r.URL.Query().Get("months"); monthsStr != "" {
-		m, err := strconv.Atoi(monthsStr)
-		if err != nil {
-			respondJSON(w, http.StatusBadRequest, ErrorResponse{
-				Error: "invalid months parameter: must be a number",
-				Field: "months",
-			})
-			return
-		}
-		if m < 1 || m > 24 {
-			respondJSON(w, http.StatusBadRequest, ErrorResponse{
-				Error: "invalid months parameter: must be between 1 and 24",
-				Field: "months",
-			})
-			return
-		}
-		months = m
+	months, ok := parseReportMonths(w, r)
+	if !ok {
+		return
 	}
 
 	// Parse limit parameter (optional, default 10)
@@ -170,7 +162,12 @@ func (h *ReportHandler) GetCategoryTrends(w http.ResponseWriter, r *http.Request
 		limit = l
 	}
 
-	trends, err := h.reportService.GetCategoryTrends(r.Context(), userID, months, limit)
+	byParent, ok := parseCategoryGrouping(w, r)
+	if !ok {
+		return
+	}
+
+	trends, err := h.reportService.GetCategoryTrends(r.Context(), userID, months, limit, byParent)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to generate category trends")
 		return
@@ -178,3 +175,79 @@ func (h *ReportHandler) GetCategoryTrends(w http.ResponseWriter, r *http.Request
 
 	respondJSON(w, http.StatusOK, trends)
 }
+
+// GetTagBreakdown godoc
+// @Summary Get income and spending by tag
+// @Description Returns income, expenses and monthly expenses for each tag, ordered by total expenses. A transaction with several tags counts towards each of them.
+// @Tags reports
+// @Produce json
+// @Security BearerAuth
+// @Param months query int false "Number of months to include (default: 6, max: 24)"
+// @Success 200 {object} service.TagBreakdownResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /reports/tags [get]
+func (h *ReportHandler) GetTagBreakdown(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+	if userID == uuid.Nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	months, ok := parseReportMonths(w, r)
+	if !ok {
+		return
+	}
+
+	breakdown, err := h.reportService.GetTagBreakdown(r.Context(), userID, months)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to generate tag breakdown")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, breakdown)
+}
+
+// parseReportMonths reads the optional months parameter, 6 by default. It writes
+// the error response and returns false if the value is not between 1 and 24.
+func parseReportMonths(w http.ResponseWriter, r *http.Request) (months int, ok bool) {
+	monthsStr := r.URL.Query().Get("months")
+	if monthsStr == "" {
+		return 6, true
+	}
+	m, err := strconv.Atoi(monthsStr)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: "invalid months parameter: must be a number",
+			Field: "months",
+		})
+		return 0, false
+	}
+	if m < 1 || m > 24 {
+		respondJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: "invalid months parameter: must be between 1 and 24",
+			Field: "months",
+		})
+		return 0, false
+	}
+	return m, true
+}
+
+// parseCategoryGrouping reads the groupBy parameter and reports whether categories
+// should roll up into their parent. It writes the error response and returns
+// false if the value is not supported.
+func parseCategoryGrouping(w http.ResponseWriter, r *http.Request) (byParent bool, ok bool) {
+	switch r.URL.Query().Get("groupBy") {
+	case "", "category":
+		return false, true
+	case "parent":
+		return true, true
+	default:
+		respondJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: "invalid groupBy parameter: must be category or parent",
+			Field: "groupBy",
+		})
+		return false, false
+	}
+}
@@ -19,22 +19,30 @@ type MockReportServiceImpl struct {
 	mock.Mock
 }
 
-func (m *MockReportServiceImpl) GetMonthlyReport(ctx context.Context, userID uuid.UUID, year, month int) (*service.MonthlyReport, error) {
-	args := m.Called(ctx, userID, year, month)
+func (m *MockReportServiceImpl) GetMonthlyReport(ctx context.Context, userID uuid.UUID, year, month int, byParent bool) (*service.MonthlyReport, error) {
+	args := m.Called(ctx, userID, year, month, byParent)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*service.MonthlyReport), args.Error(1)
 }
 
-func (m *MockReportServiceImpl) GetCategoryTrends(ctx context.Context, userID uuid.UUID, months, limit int) (*service.CategoryTrendsResponse, error) {
-	args := m.Called(ctx, userID, months, limit)
+func (m *MockReportServiceImpl) GetCategoryTrends(ctx context.Context, userID uuid.UUID, months, limit int, byParent bool) (*service.CategoryTrendsResponse, error) {
+	args := m.Called(ctx, userID, months, limit, byParent)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*service.CategoryTrendsResponse), args.Error(1)
 }
 
+func (m *MockReportServiceImpl) GetTagBreakdown(ctx context.Context, userID uuid.UUID, months int) (*service.TagBreakdownResponse, error) {
+	args := m.Called(ctx, userID, months)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.TagBreakdownResponse), args.Error(1)
+}
+
 func TestReportHandler_GetMonthlyReport(t *testing.T) {
 	t.Parallel()
 
@@ -52,7 +60,7 @@ func TestReportHandler_GetMonthlyReport(t *testing.T) {
 			year:   "2026",
 			month:  "1",
 			setupMock: func(m *MockReportServiceImpl, userID uuid.UUID) {
-				m.On("GetMonthlyReport", mock.Anything, userID, 2026, 1).Return(&service.MonthlyReport{
+				m.On("GetMonthlyReport", mock.Anything, userID, 2026, 1, false).Return(&service.MonthlyReport{
 					Year:          2026,
 					Month:         1,
 					Currency:      "USD",
@@ -148,7 +156,7 @@ func TestReportHandler_GetMonthlyReport(t *testing.T) {
 			year:   "2026",
 			month:  "1",
 			setupMock: func(m *MockReportServiceImpl, userID uuid.UUID) {
-				m.On("GetMonthlyReport", mock.Anything, userID, 2026, 1).Return(nil, errors.New("database error"))
+				m.On("GetMonthlyReport", mock.Anything, userID, 2026, 1, false).Return(nil, errors.New("database error"))
 			},
 			wantStatus: http.StatusInternalServerError,
 		},
@@ -158,7 +166,7 @@ func TestReportHandler_GetMonthlyReport(t *testing.T) {
 			year:   "2026",
 			month:  "1",
 			setupMock: func(m *MockReportServiceImpl, userID uuid.UUID) {
-				m.On("GetMonthlyReport", mock.Anything, userID, 2026, 1).Return(&service.MonthlyReport{
+				m.On("GetMonthlyReport", mock.Anything, userID, 2026, 1, false).Return(&service.MonthlyReport{
 					Year:  2026,
 					Month: 1,
 				}, nil)
@@ -171,7 +179,7 @@ func TestReportHandler_GetMonthlyReport(t *testing.T) {
 			year:   "2026",
 			month:  "12",
 			setupMock: func(m *MockReportServiceImpl, userID uuid.UUID) {
-				m.On("GetMonthlyReport", mock.Anything, userID, 2026, 12).Return(&service.MonthlyReport{
+				m.On("GetMonthlyReport", mock.Anything, userID, 2026, 12, false).Return(&service.MonthlyReport{
 					Year:  2026,
 					Month: 12,
 				}, nil)
@@ -233,7 +241,7 @@ func TestReportHandler_GetCategoryTrends(t *testing.T) {
 			months: "",
 			limit:  "",
 			setupMock: func(m *MockReportServiceImpl, userID uuid.UUID, months, limit int) {
-				m.On("GetCategoryTrends", mock.Anything, userID, 6, 10).Return(&service.CategoryTrendsResponse{
+				m.On("GetCategoryTrends", mock.Anything, userID, 6, 10, false).Return(&service.CategoryTrendsResponse{
 					Currency:    "USD",
 					PeriodStart: "2025-08-01",
 					PeriodEnd:   "2026-01-31",
@@ -260,7 +268,7 @@ func TestReportHandler_GetCategoryTrends(t *testing.T) {
 			months: "12",
 			limit:  "",
 			setupMock: func(m *MockReportServiceImpl, userID uuid.UUID, months, limit int) {
-				m.On("GetCategoryTrends", mock.Anything, userID, 12, 10).Return(&service.CategoryTrendsResponse{
+				m.On("GetCategoryTrends", mock.Anything, userID, 12, 10, false).Return(&service.CategoryTrendsResponse{
 					Currency: "USD",
 					Trends:   []service.CategoryTrend{},
 				}, nil)
@@ -273,7 +281,7 @@ func TestReportHandler_GetCategoryTrends(t *testing.T) {
 			months: "",
 			limit:  "5",
 			setupMock: func(m *MockReportServiceImpl, userID uuid.UUID, months, limit int) {
-				m.On("GetCategoryTrends", mock.Anything, userID, 6, 5).Return(&service.CategoryTrendsResponse{
+				m.On("GetCategoryTrends", mock.Anything, userID, 6, 5, false).Return(&service.CategoryTrendsResponse{
 					Currency: "USD",
 					Trends:   []service.CategoryTrend{},
 				}, nil)
@@ -310,7 +318,7 @@ func TestReportHandler_GetCategoryTrends(t *testing.T) {
 			months: "",
 			limit:  "",
 			setupMock: func(m *MockReportServiceImpl, userID uuid.UUID, months, limit int) {
-				m.On("GetCategoryTrends", mock.Anything, userID, 6, 10).Return(nil, errors.New("database error"))
+				m.On("GetCategoryTrends", mock.Anything, userID, 6, 10, false).Return(nil, errors.New("database error"))
 			},
 			wantStatus: http.StatusInternalServerError,
 		},
@@ -320,7 +328,7 @@ func TestReportHandler_GetCategoryTrends(t *testing.T) {
 			months: "1",
 			limit:  "",
 			setupMock: func(m *MockReportServiceImpl, userID uuid.UUID, months, limit int) {
-				m.On("GetCategoryTrends", mock.Anything, userID, 1, 10).Return(&service.CategoryTrendsResponse{
+				m.On("GetCategoryTrends", mock.Anything, userID, 1, 10, false).Return(&service.CategoryTrendsResponse{
 					Currency: "USD",
 				}, nil)
 			},
@@ -332,7 +340,7 @@ func TestReportHandler_GetCategoryTrends(t *testing.T) {
 			months: "24",
 			limit:  "",
 			setupMock: func(m *MockReportServiceImpl, userID uuid.UUID, months, limit int) {
-				m.On("GetCategoryTrends", mock.Anything, userID, 24, 10).Return(&service.CategoryTrendsResponse{
+				m.On("GetCategoryTrends", mock.Anything, userID, 24, 10, false).Return(&service.CategoryTrendsResponse{
 					Currency: "USD",
 				}, nil)
 			},
@@ -344,7 +352,7 @@ func TestReportHandler_GetCategoryTrends(t *testing.T) {
 			months: "",
 			limit:  "1",
 			setupMock: func(m *MockReportServiceImpl, userID uuid.UUID, months, limit int) {
-				m.On("GetCategoryTrends", mock.Anything, userID, 6, 1).Return(&service.CategoryTrendsResponse{
+				m.On("GetCategoryTrends", mock.Anything, userID, 6, 1, false).Return(&service.CategoryTrendsResponse{
 					Currency: "USD",
 				}, nil)
 			},
@@ -356,7 +364,7 @@ func TestReportHandler_GetCategoryTrends(t *testing.T) {
 			months: "",
 			limit:  "20",
 			setupMock: func(m *MockReportServiceImpl, userID uuid.UUID, months, limit int) {
-				m.On("GetCategoryTrends", mock.Anything, userID, 6, 20).Return(&service.CategoryTrendsResponse{
+				m.On("GetCategoryTrends", mock.Anything, userID, 6, 20, false).Return(&service.CategoryTrendsResponse{
 					Currency: "USD",
 				}, nil)
 			},
@@ -446,7 +454,7 @@ func TestReportHandler_ResponseFormat(t *testing.T) {
 		GeneratedAt: time.Now().Format(time.RFC3339),
 	}
 
-	mockService.On("GetMonthlyReport", mock.Anything, userID, 2026, 1).Return(expectedReport, nil)
+	mockService.On("GetMonthlyReport", mock.Anything, userID, 2026, 1, false).Return(expectedReport, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/reports/monthly?year=2026&month=1", nil)
 	req = req.WithContext(context.WithValue(context.Background(), UserIDKey, userID))
@@ -484,7 +492,7 @@ func TestReportHandler_EmptyState(t *testing.T) {
 		GeneratedAt:    time.Now().Format(time.RFC3339),
 	}
 
-	mockService.On("GetMonthlyReport", mock.Anything, userID, 2026, 1).Return(emptyReport, nil)
+	mockService.On("GetMonthlyReport", mock.Anything, userID, 2026, 1, false).Return(emptyReport, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/reports/monthly?year=2026&month=1", nil)
 	req = req.WithContext(context.WithValue(context.Background(), UserIDKey, userID))
@@ -497,13 +505,86 @@ func TestReportHandler_EmptyState(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "topCategories")
 }
 
+func TestReportHandler_GroupByParent(t *testing.T) {
+	t.Parallel()
+
+	mockService := new(MockReportServiceImpl)
+	handler := NewReportHandler(mockService)
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), UserIDKey, userID)
+
+	mockService.On("GetMonthlyReport", mock.Anything, userID, 2026, 1, true).Return(&service.MonthlyReport{Year: 2026, Month: 1}, nil)
+	mockService.On("GetCategoryTrends", mock.Anything, userID, 6, 10, true).Return(&service.CategoryTrendsResponse{}, nil)
+
+	w := httptest.NewRecorder()
+	handler.GetMonthlyReport(w, httptest.NewRequest(http.MethodGet, "/api/reports/monthly?year=2026&month=1&groupBy=parent", nil).WithContext(ctx))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.GetCategoryTrends(w, httptest.NewRequest(http.MethodGet, "/api/reports/category-trends?groupBy=parent", nil).WithContext(ctx))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.GetCategoryTrends(w, httptest.NewRequest(http.MethodGet, "/api/reports/category-trends?groupBy=tag", nil).WithContext(ctx))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "groupBy")
+
+	mockService.AssertExpectations(t)
+}
+
+func TestReportHandler_GetTagBreakdown(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		query      string
+		months     int
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "default months", query: "", months: 6, wantStatus: http.StatusOK},
+		{name: "custom months", query: "?months=12", months: 12, wantStatus: http.StatusOK},
+		{name: "months out of range", query: "?months=25", wantStatus: http.StatusBadRequest},
+		{name: "service error", query: "", months: 6, serviceErr: errors.New("database error"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockReportServiceImpl)
+			handler := NewReportHandler(mockService)
+			userID := uuid.New()
+
+			if tt.serviceErr != nil {
+				mockService.On("GetTagBreakdown", mock.Anything, userID, tt.months).Return(nil, tt.serviceErr)
+			} else if tt.wantStatus == http.StatusOK {
+				mockService.On("GetTagBreakdown", mock.Anything, userID, tt.months).Return(&service.TagBreakdownResponse{
+					Currency: "USD",
+					Tags:     []service.TagBreakdown{{Tag: "vacation", TotalExpenses: "500.00"}},
+				}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/reports/tags"+tt.query, nil)
+			req = req.WithContext(context.WithValue(context.Background(), UserIDKey, userID))
+			w := httptest.NewRecorder()
+
+			handler.GetTagBreakdown(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 // Benchmark tests
 func BenchmarkReportHandler_GetMonthlyReport(b *testing.B) {
 	mockService := new(MockReportServiceImpl)
 	handler := NewReportHandler(mockService)
 	userID := uuid.New()
 
-	mockService.On("GetMonthlyReport", mock.Anything, userID, 2026, 1).Return(&service.MonthlyReport{
+	mockService.On("GetMonthlyReport", mock.Anything, userID, 2026, 1, false).Return(&service.MonthlyReport{
 		Year:          2026,
 		Month:         1,
 		Currency:      "USD",
@@ -524,7 +605,7 @@ func BenchmarkReportHandler_GetCategoryTrends(b *testing.B) {
 	handler := NewReportHandler(mockService)
 	userID := uuid.New()
 
-	mockService.On("GetCategoryTrends", mock.Anything, userID, 6, 10).Return(&service.CategoryTrendsResponse{
+	mockService.On("GetCategoryTrends", mock.Anything, userID, 6, 10, false).Return(&service.CategoryTrendsResponse{
 		Currency: "USD",
 		Trends:   []service.CategoryTrend{},
 	}, nil)
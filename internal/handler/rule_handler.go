@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/wealthpath/backend/internal/apperror"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// RuleServiceInterface defines the service contract for categorization rules.
+type RuleServiceInterface interface {
+	List(ctx context.Context, userID uuid.UUID) ([]model.CategorizationRule, error)
+	Create(ctx context.Context, userID uuid.UUID, input service.RuleInput) (*model.CategorizationRule, error)
+	Update(ctx context.Context, userID, id uuid.UUID, input service.RuleInput) (*model.CategorizationRule, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	Reapply(ctx context.Context, userID uuid.UUID, input service.ReapplyRulesInput) (*service.ReapplyRulesResult, error)
+	Suggest(ctx context.Context, userID uuid.UUID) ([]service.RuleSuggestion, error)
+}
+
+// RuleHandler handles HTTP requests for the user's categorization rules.
+type RuleHandler struct {
+	service RuleServiceInterface
+}
+
+// NewRuleHandler creates a new RuleHandler with the given service.
+func NewRuleHandler(service RuleServiceInterface) *RuleHandler {
+	return &RuleHandler{service: service}
+}
+
+// List godoc
+// @Summary List categorization rules
+// @Description Get the current user's categorization rules in the order they run
+// @Tags rules
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.CategorizationRule
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /rules [get]
+func (h *RuleHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	rules, err := h.service.List(r.Context(), userID)
+	if err != nil {
+		respondAppError(w, apperror.Internal(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rules)
+}
+
+// Create godoc
+// @Summary Create a categorization rule
+// @Description Create a rule that sets the category, tags or description of new transactions matching all of its conditions. Rules run when transactions are created, imported or added from the AI chat.
+// @Tags rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body service.RuleInput true "Rule data"
+// @Success 201 {object} model.CategorizationRule
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /rules [post]
+func (h *RuleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	var input service.RuleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondAppError(w, apperror.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	rule, err := h.service.Create(r.Context(), userID, input)
+	if err != nil {
+		respondAppError(w, ruleError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, rule)
+}
+
+// Update godoc
+// @Summary Update a categorization rule
+// @Description Replace the conditions and actions of a rule. Transactions it already changed are left as they are.
+// @Tags rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Rule ID"
+// @Param input body service.RuleInput true "Updated rule data"
+// @Success 200 {object} model.CategorizationRule
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /rules/{id} [put]
+func (h *RuleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid rule ID"))
+		return
+	}
+
+	var input service.RuleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondAppError(w, apperror.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	rule, err := h.service.Update(r.Context(), userID, id, input)
+	if err != nil {
+		respondAppError(w, ruleError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rule)
+}
+
+// Delete godoc
+// @Summary Delete a categorization rule
+// @Description Delete a rule. Transactions it already changed are left as they are.
+// @Tags rules
+// @Security BearerAuth
+// @Param id path string true "Rule ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /rules/{id} [delete]
+func (h *RuleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid rule ID"))
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), userID, id); err != nil {
+		respondAppError(w, ruleError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Reapply godoc
+// @Summary Re-apply categorization rules to past transactions
+// @Description Run the enabled rules, or only the given ones, over the transactions dated in a range and save those they change. With dryRun, the changes are reported without saving them.
+// @Tags rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body service.ReapplyRulesInput true "Date range and rules"
+// @Success 200 {object} service.ReapplyRulesResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /rules/apply [post]
+func (h *RuleHandler) Reapply(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	var input service.ReapplyRulesInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondAppError(w, apperror.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	result, err := h.service.Reapply(r.Context(), userID, input)
+	if err != nil {
+		respondAppError(w, ruleError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// Suggest godoc
+// @Summary Suggest categorization rules
+// @Description Suggest rules for descriptions the user keeps filing under the same category and that no rule categorizes yet. Each suggestion can be created as is with POST /rules.
+// @Tags rules
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} service.RuleSuggestion
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /rules/suggestions [get]
+func (h *RuleHandler) Suggest(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	suggestions, err := h.service.Suggest(r.Context(), userID)
+	if err != nil {
+		respondAppError(w, apperror.Internal(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, suggestions)
+}
+
+func ruleError(err error) *apperror.AppError {
+	switch {
+	case errors.Is(err, repository.ErrRuleNotFound):
+		return apperror.NotFound("rule")
+	case errors.Is(err, service.ErrInvalidRuleName):
+		return apperror.ValidationError("name", err.Error())
+	case errors.Is(err, service.ErrInvalidType):
+		return apperror.ValidationError("transactionType", err.Error())
+	case errors.Is(err, service.ErrInvalidRulePattern):
+		return apperror.ValidationError("descriptionPattern", err.Error())
+	case errors.Is(err, service.ErrInvalidRuleAmounts):
+		return apperror.ValidationError("minAmount", err.Error())
+	case errors.Is(err, service.ErrRuleNoConditions),
+		errors.Is(err, service.ErrRuleNoActions),
+		errors.Is(err, service.ErrRuleTextTooLong):
+		return apperror.BadRequest(err.Error())
+	case errors.Is(err, service.ErrInvalidReapplyRange):
+		return apperror.ValidationError("startDate", err.Error())
+	case errors.Is(err, service.ErrInvalidTag),
+		errors.Is(err, service.ErrTooManyTags):
+		return apperror.ValidationError("addTags", err.Error())
+	case errors.Is(err, repository.ErrAccountNotFound):
+		return apperror.ValidationError("accountId", "account not found")
+	default:
+		return apperror.Internal(err)
+	}
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// MockRuleService implements RuleServiceInterface for testing
+type MockRuleService struct {
+	mock.Mock
+}
+
+func (m *MockRuleService) List(ctx context.Context, userID uuid.UUID) ([]model.CategorizationRule, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.CategorizationRule), args.Error(1)
+}
+
+func (m *MockRuleService) Create(ctx context.Context, userID uuid.UUID, input service.RuleInput) (*model.CategorizationRule, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CategorizationRule), args.Error(1)
+}
+
+func (m *MockRuleService) Update(ctx context.Context, userID, id uuid.UUID, input service.RuleInput) (*model.CategorizationRule, error) {
+	args := m.Called(ctx, userID, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CategorizationRule), args.Error(1)
+}
+
+func (m *MockRuleService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockRuleService) Reapply(ctx context.Context, userID uuid.UUID, input service.ReapplyRulesInput) (*service.ReapplyRulesResult, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.ReapplyRulesResult), args.Error(1)
+}
+
+func (m *MockRuleService) Suggest(ctx context.Context, userID uuid.UUID) ([]service.RuleSuggestion, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]service.RuleSuggestion), args.Error(1)
+}
+
+func TestRuleHandler_Create(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", body: `{"name":"Grab","descriptionContains":"grab","setCategory":"Transportation"}`, wantStatus: http.StatusCreated},
+		{name: "invalid body", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "invalid pattern", body: `{"name":"Grab"}`, serviceErr: service.ErrInvalidRulePattern, wantStatus: http.StatusBadRequest},
+		{name: "no conditions", body: `{"name":"Grab"}`, serviceErr: service.ErrRuleNoConditions, wantStatus: http.StatusBadRequest},
+		{name: "unknown account", body: `{"name":"Grab"}`, serviceErr: repository.ErrAccountNotFound, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockRuleService)
+			handler := NewRuleHandler(mockService)
+			userID := uuid.New()
+
+			if tt.serviceErr != nil {
+				mockService.On("Create", mock.Anything, userID, mock.Anything).Return(nil, tt.serviceErr)
+			} else if tt.wantStatus == http.StatusCreated {
+				mockService.On("Create", mock.Anything, userID, service.RuleInput{
+					Name: "Grab", DescriptionContains: "grab", SetCategory: "Transportation",
+				}).Return(&model.CategorizationRule{ID: uuid.New(), Name: "Grab"}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/rules", bytes.NewBufferString(tt.body))
+			req = req.WithContext(ctxWithUserID(userID))
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRuleHandler_Delete(t *testing.T) {
+	t.Parallel()
+
+	ruleID := uuid.New()
+
+	tests := []struct {
+		name       string
+		id         string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", id: ruleID.String(), wantStatus: http.StatusNoContent},
+		{name: "invalid id", id: "abc", wantStatus: http.StatusBadRequest},
+		{name: "not found", id: ruleID.String(), serviceErr: repository.ErrRuleNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockRuleService)
+			handler := NewRuleHandler(mockService)
+			userID := uuid.New()
+			mockService.On("Delete", mock.Anything, userID, ruleID).Return(tt.serviceErr).Maybe()
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/rules/"+tt.id, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			req = req.WithContext(context.WithValue(ctxWithUserID(userID), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.Delete(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestRuleHandler_Reapply(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", body: `{"startDate":"2024-03-01","endDate":"2024-03-31","dryRun":true}`, wantStatus: http.StatusOK},
+		{name: "invalid date", body: `{"startDate":"March"}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid range", body: `{}`, serviceErr: service.ErrInvalidReapplyRange, wantStatus: http.StatusBadRequest},
+		{name: "unknown rule", body: `{}`, serviceErr: repository.ErrRuleNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockRuleService)
+			handler := NewRuleHandler(mockService)
+			userID := uuid.New()
+
+			if tt.serviceErr != nil {
+				mockService.On("Reapply", mock.Anything, userID, mock.Anything).Return(nil, tt.serviceErr)
+			} else if tt.wantStatus == http.StatusOK {
+				mockService.On("Reapply", mock.Anything, userID, mock.MatchedBy(func(in service.ReapplyRulesInput) bool {
+					return in.DryRun && in.StartDate.Format("2006-01-02") == "2024-03-01"
+				})).Return(&service.ReapplyRulesResult{DryRun: true}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/rules/apply", bytes.NewBufferString(tt.body))
+			req = req.WithContext(ctxWithUserID(userID))
+			w := httptest.NewRecorder()
+
+			handler.Reapply(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -2,11 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	_ "github.com/wealthpath/backend/internal/model" // swagger types
+	"github.com/wealthpath/backend/internal/repository"
 	"github.com/wealthpath/backend/internal/service"
 )
 
@@ -167,16 +170,17 @@ func (h *SavingsGoalHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 // Contribute godoc
 // @Summary Contribute to a savings goal
-// @Description Add money to a savings goal
+// @Description Add money to a savings goal. The contribution is recorded in the goal's history.
 // @Tags savings-goals
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Savings Goal ID"
-// @Param input body service.ContributeInput true "Contribution amount"
+// @Param input body service.ContributeInput true "Contribution"
 // @Success 200 {object} model.SavingsGoal
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /savings-goals/{id}/contribute [post]
 func (h *SavingsGoalHandler) Contribute(w http.ResponseWriter, r *http.Request) {
@@ -194,11 +198,142 @@ func (h *SavingsGoalHandler) Contribute(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	goal, err := h.service.Contribute(r.Context(), id, userID, input.Amount)
+	goal, err := h.service.Contribute(r.Context(), id, userID, input)
+	if err != nil {
+		respondSavingsLedgerError(w, err, "failed to contribute")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, goal)
+}
+
+// Withdraw godoc
+// @Summary Withdraw from a savings goal
+// @Description Take money out of a savings goal. The goal cannot go below zero.
+// @Tags savings-goals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Savings Goal ID"
+// @Param input body service.ContributeInput true "Withdrawal"
+// @Success 200 {object} model.SavingsGoal
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /savings-goals/{id}/withdraw [post]
+func (h *SavingsGoalHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var input service.ContributeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	goal, err := h.service.Withdraw(r.Context(), id, userID, input)
+	if err != nil {
+		respondSavingsLedgerError(w, err, "failed to withdraw")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, goal)
+}
+
+// ListContributions godoc
+// @Summary List savings goal contributions
+// @Description Get the contributions and withdrawals of a savings goal, newest first
+// @Tags savings-goals
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Savings Goal ID"
+// @Param limit query int false "Number of results" default(50)
+// @Param offset query int false "Number of results to skip" default(0)
+// @Success 200 {array} model.SavingsContribution
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /savings-goals/{id}/contributions [get]
+func (h *SavingsGoalHandler) ListContributions(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	entries, err := h.service.ListContributions(r.Context(), id, userID, limit, offset)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "failed to contribute")
+		respondSavingsLedgerError(w, err, "failed to list contributions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, entries)
+}
+
+// ReverseContribution godoc
+// @Summary Reverse a savings goal contribution
+// @Description Undo a contribution or withdrawal by recording an opposite entry
+// @Tags savings-goals
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Savings Goal ID"
+// @Param contributionId path string true "Contribution ID"
+// @Success 200 {object} model.SavingsGoal
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /savings-goals/{id}/contributions/{contributionId}/reverse [post]
+func (h *SavingsGoalHandler) ReverseContribution(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+	contributionID, err := uuid.Parse(chi.URLParam(r, "contributionId"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid contribution id")
+		return
+	}
+
+	goal, err := h.service.ReverseContribution(r.Context(), id, contributionID, userID)
+	if err != nil {
+		respondSavingsLedgerError(w, err, "failed to reverse contribution")
 		return
 	}
 
 	respondJSON(w, http.StatusOK, goal)
 }
+
+// respondSavingsLedgerError maps contribution errors to HTTP responses
+func respondSavingsLedgerError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, repository.ErrSavingsGoalNotFound):
+		respondError(w, http.StatusNotFound, "savings goal not found")
+	case errors.Is(err, repository.ErrSavingsContributionNotFound):
+		respondError(w, http.StatusNotFound, "contribution not found")
+	case errors.Is(err, repository.ErrContributionAlreadyReversed), errors.Is(err, service.ErrCannotReverseReversal):
+		respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrInvalidContributionAmount),
+		errors.Is(err, service.ErrInvalidLinkedTransaction),
+		errors.Is(err, repository.ErrInsufficientSavings):
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, fallback)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
 	"github.com/wealthpath/backend/internal/service"
 )
 
@@ -68,8 +69,32 @@ func (m *MockSavingsGoalService) Delete(ctx context.Context, id, userID uuid.UUI
 	return args.Error(0)
 }
 
-func (m *MockSavingsGoalService) Contribute(ctx context.Context, id, userID uuid.UUID, amount decimal.Decimal) (*model.SavingsGoal, error) {
-	args := m.Called(ctx, id, userID, amount)
+func (m *MockSavingsGoalService) Contribute(ctx context.Context, id, userID uuid.UUID, input service.ContributeInput) (*model.SavingsGoal, error) {
+	args := m.Called(ctx, id, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SavingsGoal), args.Error(1)
+}
+
+func (m *MockSavingsGoalService) Withdraw(ctx context.Context, id, userID uuid.UUID, input service.ContributeInput) (*model.SavingsGoal, error) {
+	args := m.Called(ctx, id, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SavingsGoal), args.Error(1)
+}
+
+func (m *MockSavingsGoalService) ListContributions(ctx context.Context, id, userID uuid.UUID, limit, offset int) ([]model.SavingsContribution, error) {
+	args := m.Called(ctx, id, userID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.SavingsContribution), args.Error(1)
+}
+
+func (m *MockSavingsGoalService) ReverseContribution(ctx context.Context, goalID, contributionID, userID uuid.UUID) (*model.SavingsGoal, error) {
+	args := m.Called(ctx, goalID, contributionID, userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -400,7 +425,7 @@ func TestSavingsGoalHandler_Contribute(t *testing.T) {
 			goalID: uuid.New().String(),
 			body:   map[string]interface{}{"amount": 500},
 			setupMock: func(m *MockSavingsGoalService, goalID, userID uuid.UUID) {
-				m.On("Contribute", mock.Anything, goalID, userID, mock.AnythingOfType("decimal.Decimal")).Return(&model.SavingsGoal{ID: goalID}, nil)
+				m.On("Contribute", mock.Anything, goalID, userID, mock.AnythingOfType("service.ContributeInput")).Return(&model.SavingsGoal{ID: goalID}, nil)
 			},
 			wantStatus: http.StatusOK,
 		},
@@ -423,7 +448,7 @@ func TestSavingsGoalHandler_Contribute(t *testing.T) {
 			goalID: uuid.New().String(),
 			body:   map[string]interface{}{"amount": 500},
 			setupMock: func(m *MockSavingsGoalService, goalID, userID uuid.UUID) {
-				m.On("Contribute", mock.Anything, goalID, userID, mock.AnythingOfType("decimal.Decimal")).Return(nil, errors.New("error"))
+				m.On("Contribute", mock.Anything, goalID, userID, mock.AnythingOfType("service.ContributeInput")).Return(nil, errors.New("error"))
 			},
 			wantStatus: http.StatusInternalServerError,
 		},
@@ -455,3 +480,147 @@ func TestSavingsGoalHandler_Contribute(t *testing.T) {
 		})
 	}
 }
+
+func TestSavingsGoalHandler_Withdraw(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		goalID     string
+		body       interface{}
+		setupMock  func(*MockSavingsGoalService, uuid.UUID, uuid.UUID)
+		wantStatus int
+	}{
+		{
+			name:   "success",
+			goalID: uuid.New().String(),
+			body:   map[string]interface{}{"amount": 200, "note": "Car repair"},
+			setupMock: func(m *MockSavingsGoalService, goalID, userID uuid.UUID) {
+				m.On("Withdraw", mock.Anything, goalID, userID, mock.MatchedBy(func(in service.ContributeInput) bool {
+					return in.Amount.Equal(decimal.NewFromInt(200)) && in.Note != nil && *in.Note == "Car repair"
+				})).Return(&model.SavingsGoal{ID: goalID}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "more than saved",
+			goalID: uuid.New().String(),
+			body:   map[string]interface{}{"amount": 5000},
+			setupMock: func(m *MockSavingsGoalService, goalID, userID uuid.UUID) {
+				m.On("Withdraw", mock.Anything, goalID, userID, mock.Anything).Return(nil, repository.ErrInsufficientSavings)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "goal not found",
+			goalID: uuid.New().String(),
+			body:   map[string]interface{}{"amount": 100},
+			setupMock: func(m *MockSavingsGoalService, goalID, userID uuid.UUID) {
+				m.On("Withdraw", mock.Anything, goalID, userID, mock.Anything).Return(nil, repository.ErrSavingsGoalNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockSavingsGoalService)
+			handler := NewSavingsGoalHandler(mockService)
+			userID := uuid.New()
+			goalID, _ := uuid.Parse(tt.goalID)
+
+			tt.setupMock(mockService, goalID, userID)
+
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPost, "/api/savings-goals/"+tt.goalID+"/withdraw", bytes.NewReader(body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.goalID)
+			req = req.WithContext(context.WithValue(ctxWithUserID(userID), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.Withdraw(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSavingsGoalHandler_ListContributions(t *testing.T) {
+	t.Parallel()
+
+	mockService := new(MockSavingsGoalService)
+	handler := NewSavingsGoalHandler(mockService)
+	userID := uuid.New()
+	goalID := uuid.New()
+
+	mockService.On("ListContributions", mock.Anything, goalID, userID, 10, 20).Return([]model.SavingsContribution{
+		{ID: uuid.New(), GoalID: goalID, Amount: decimal.NewFromInt(100)},
+		{ID: uuid.New(), GoalID: goalID, Amount: decimal.NewFromInt(-50)},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/savings-goals/"+goalID.String()+"/contributions?limit=10&offset=20", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", goalID.String())
+	req = req.WithContext(context.WithValue(ctxWithUserID(userID), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	handler.ListContributions(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var entries []model.SavingsContribution
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&entries))
+	assert.Len(t, entries, 2)
+	mockService.AssertExpectations(t)
+}
+
+func TestSavingsGoalHandler_ReverseContribution(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		contributionID string
+		serviceErr     error
+		wantStatus     int
+	}{
+		{name: "success", contributionID: uuid.New().String(), wantStatus: http.StatusOK},
+		{name: "invalid contribution id", contributionID: "invalid", wantStatus: http.StatusBadRequest},
+		{name: "not found", contributionID: uuid.New().String(), serviceErr: repository.ErrSavingsContributionNotFound, wantStatus: http.StatusNotFound},
+		{name: "already reversed", contributionID: uuid.New().String(), serviceErr: repository.ErrContributionAlreadyReversed, wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockSavingsGoalService)
+			handler := NewSavingsGoalHandler(mockService)
+			userID := uuid.New()
+			goalID := uuid.New()
+
+			if contributionID, err := uuid.Parse(tt.contributionID); err == nil {
+				if tt.serviceErr != nil {
+					mockService.On("ReverseContribution", mock.Anything, goalID, contributionID, userID).Return(nil, tt.serviceErr)
+				} else {
+					mockService.On("ReverseContribution", mock.Anything, goalID, contributionID, userID).Return(&model.SavingsGoal{ID: goalID}, nil)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/savings-goals/"+goalID.String()+"/contributions/"+tt.contributionID+"/reverse", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", goalID.String())
+			rctx.URLParams.Add("contributionId", tt.contributionID)
+			req = req.WithContext(context.WithValue(ctxWithUserID(userID), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.ReverseContribution(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
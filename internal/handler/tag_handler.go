@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/wealthpath/backend/internal/apperror"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// TagServiceInterface defines the service contract for tags.
+type TagServiceInterface interface {
+	List(ctx context.Context, userID uuid.UUID) ([]model.TagUsage, error)
+	Autocomplete(ctx context.Context, userID uuid.UUID, query string, limit int) ([]model.Tag, error)
+	Create(ctx context.Context, userID uuid.UUID, input service.TagInput) (*model.Tag, error)
+	Update(ctx context.Context, userID, id uuid.UUID, input service.TagInput) (*model.Tag, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+}
+
+// TagHandler handles HTTP requests for the user's tags.
+type TagHandler struct {
+	service TagServiceInterface
+}
+
+// NewTagHandler creates a new TagHandler with the given service.
+func NewTagHandler(service TagServiceInterface) *TagHandler {
+	return &TagHandler{service: service}
+}
+
+// List godoc
+// @Summary List tags
+// @Description Get the current user's tags in alphabetical order with how many transactions each is on
+// @Tags tags
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.TagUsage
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tags [get]
+func (h *TagHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	tags, err := h.service.List(r.Context(), userID)
+	if err != nil {
+		respondAppError(w, apperror.Internal(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tags)
+}
+
+// Autocomplete godoc
+// @Summary Suggest tags
+// @Description Get the current user's tags containing the query. Tags starting with it come first, then the most used ones.
+// @Tags tags
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "What the user has typed so far"
+// @Param limit query int false "Number of suggestions (default: 10, max: 50)"
+// @Success 200 {array} model.Tag
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tags/autocomplete [get]
+func (h *TagHandler) Autocomplete(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	limit := 0
+	if l := r.URL.Query().Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 {
+			respondAppError(w, apperror.ValidationError("limit", "limit must be a positive number"))
+			return
+		}
+		limit = n
+	}
+
+	tags, err := h.service.Autocomplete(r.Context(), userID, r.URL.Query().Get("q"), limit)
+	if err != nil {
+		respondAppError(w, apperror.Internal(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tags)
+}
+
+// Create godoc
+// @Summary Create a tag
+// @Description Create a tag. Tags are also created the first time a transaction uses them.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body service.TagInput true "Tag data"
+// @Success 201 {object} model.Tag
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tags [post]
+func (h *TagHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	var input service.TagInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondAppError(w, apperror.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	tag, err := h.service.Create(r.Context(), userID, input)
+	if err != nil {
+		respondAppError(w, tagError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, tag)
+}
+
+// Update godoc
+// @Summary Update a tag
+// @Description Rename or recolour a tag on every transaction and recurring transaction it is on
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tag ID"
+// @Param input body service.TagInput true "Updated tag data"
+// @Success 200 {object} model.Tag
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tags/{id} [put]
+func (h *TagHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid tag ID"))
+		return
+	}
+
+	var input service.TagInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondAppError(w, apperror.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	tag, err := h.service.Update(r.Context(), userID, id, input)
+	if err != nil {
+		respondAppError(w, tagError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tag)
+}
+
+// Delete godoc
+// @Summary Delete a tag
+// @Description Remove a tag from every transaction and recurring transaction and delete it
+// @Tags tags
+// @Security BearerAuth
+// @Param id path string true "Tag ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tags/{id} [delete]
+func (h *TagHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondAppError(w, apperror.BadRequest("invalid tag ID"))
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), userID, id); err != nil {
+		respondAppError(w, tagError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func tagError(err error) *apperror.AppError {
+	switch {
+	case errors.Is(err, repository.ErrTagNotFound):
+		return apperror.NotFound("tag")
+	case errors.Is(err, repository.ErrTagExists):
+		return apperror.Conflict("a tag with this name already exists")
+	case errors.Is(err, service.ErrInvalidTag):
+		return apperror.ValidationError("name", err.Error())
+	case errors.Is(err, service.ErrInvalidTagColor):
+		return apperror.ValidationError("color", err.Error())
+	default:
+		return apperror.Internal(err)
+	}
+}
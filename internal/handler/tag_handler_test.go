@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/service"
+)
+
+// MockTagService implements TagServiceInterface for testing
+type MockTagService struct {
+	mock.Mock
+}
+
+func (m *MockTagService) List(ctx context.Context, userID uuid.UUID) ([]model.TagUsage, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.TagUsage), args.Error(1)
+}
+
+func (m *MockTagService) Autocomplete(ctx context.Context, userID uuid.UUID, query string, limit int) ([]model.Tag, error) {
+	args := m.Called(ctx, userID, query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Tag), args.Error(1)
+}
+
+func (m *MockTagService) Create(ctx context.Context, userID uuid.UUID, input service.TagInput) (*model.Tag, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Tag), args.Error(1)
+}
+
+func (m *MockTagService) Update(ctx context.Context, userID, id uuid.UUID, input service.TagInput) (*model.Tag, error) {
+	args := m.Called(ctx, userID, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Tag), args.Error(1)
+}
+
+func (m *MockTagService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func TestTagHandler_Create(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", body: `{"name":"Vacation","color":"#FF9800"}`, wantStatus: http.StatusCreated},
+		{name: "invalid body", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "invalid name", body: `{"name":""}`, serviceErr: service.ErrInvalidTag, wantStatus: http.StatusBadRequest},
+		{name: "duplicate name", body: `{"name":"vacation"}`, serviceErr: repository.ErrTagExists, wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockTagService)
+			handler := NewTagHandler(mockService)
+			userID := uuid.New()
+
+			if tt.serviceErr != nil {
+				mockService.On("Create", mock.Anything, userID, mock.Anything).Return(nil, tt.serviceErr)
+			} else if tt.wantStatus == http.StatusCreated {
+				mockService.On("Create", mock.Anything, userID, service.TagInput{Name: "Vacation", Color: "#FF9800"}).
+					Return(&model.Tag{ID: uuid.New(), Name: "Vacation"}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/tags", bytes.NewBufferString(tt.body))
+			req = req.WithContext(ctxWithUserID(userID))
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTagHandler_Autocomplete(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		query      string
+		limit      int
+		wantStatus int
+	}{
+		{name: "default limit", query: "?q=vac", wantStatus: http.StatusOK},
+		{name: "custom limit", query: "?q=vac&limit=5", limit: 5, wantStatus: http.StatusOK},
+		{name: "invalid limit", query: "?q=vac&limit=abc", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockTagService)
+			handler := NewTagHandler(mockService)
+			userID := uuid.New()
+
+			if tt.wantStatus == http.StatusOK {
+				mockService.On("Autocomplete", mock.Anything, userID, "vac", tt.limit).Return([]model.Tag{{Name: "Vacation"}}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/tags/autocomplete"+tt.query, nil)
+			req = req.WithContext(ctxWithUserID(userID))
+			w := httptest.NewRecorder()
+
+			handler.Autocomplete(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTagHandler_Delete(t *testing.T) {
+	t.Parallel()
+
+	tagID := uuid.New()
+
+	tests := []struct {
+		name       string
+		id         string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", id: tagID.String(), wantStatus: http.StatusNoContent},
+		{name: "invalid id", id: "abc", wantStatus: http.StatusBadRequest},
+		{name: "not found", id: tagID.String(), serviceErr: repository.ErrTagNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockService := new(MockTagService)
+			handler := NewTagHandler(mockService)
+			userID := uuid.New()
+
+			if tt.id == tagID.String() {
+				mockService.On("Delete", mock.Anything, userID, tagID).Return(tt.serviceErr)
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/tags/"+tt.id, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			req = req.WithContext(context.WithValue(ctxWithUserID(userID), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.Delete(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -24,8 +24,10 @@ type TransactionHandlerServiceInterface interface {
 	Create(ctx context.Context, userID uuid.UUID, input service.CreateTransactionInput) (*model.Transaction, error)
 	Get(ctx context.Context, id uuid.UUID) (*model.Transaction, error)
 	List(ctx context.Context, userID uuid.UUID, input service.ListTransactionsInput) ([]model.Transaction, error)
+	ListPage(ctx context.Context, userID uuid.UUID, input service.ListTransactionsInput) (*service.TransactionPage, error)
 	Update(ctx context.Context, id, userID uuid.UUID, input service.UpdateTransactionInput) (*model.Transaction, error)
 	Delete(ctx context.Context, id, userID uuid.UUID) error
+	Bulk(ctx context.Context, userID uuid.UUID, input service.BulkTransactionInput) (*service.BulkTransactionResult, error)
 }
 
 // TransactionHandler handles HTTP requests for transaction operations.
@@ -69,13 +71,17 @@ func (h *TransactionHandler) Create(w http.ResponseWriter, r *http.Request) {
 		respondAppError(w, apperror.ValidationError("amount", "amount is required and must be greater than 0"))
 		return
 	}
-	if input.Category == "" {
+	if input.Category == "" && len(input.Splits) == 0 {
 		respondAppError(w, apperror.ValidationError("category", "category is required"))
 		return
 	}
 
 	tx, err := h.service.Create(r.Context(), userID, input)
 	if err != nil {
+		if appErr := transactionInputError(err); appErr != nil {
+			respondAppError(w, appErr)
+			return
+		}
 		respondAppError(w, apperror.Internal(err))
 		return
 	}
@@ -118,12 +124,15 @@ func (h *TransactionHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 // List godoc
 // @Summary List transactions
-// @Description Get a list of transactions with optional filters
+// @Description Get a list of transactions with optional filters, newest first. By default pages are picked by number and the response is a plain array. With pagination=cursor, or a cursor from an earlier page, the response is a service.TransactionPage wrapped with next and prev links; those pages stay stable while transactions are added and can include totals for all matching transactions.
 // @Tags transactions
 // @Produce json
 // @Security BearerAuth
-// @Param page query int false "Page number" default(0)
+// @Param page query int false "Page number, without a cursor" default(0)
 // @Param pageSize query int false "Items per page" default(20)
+// @Param pagination query string false "offset (default) or cursor"
+// @Param cursor query string false "nextCursor or prevCursor of an earlier page"
+// @Param totals query bool false "Include the count, income and expenses of all matching transactions (cursor pagination only)"
 // @Param type query string false "Filter by type (income or expense)"
 // @Param category query string false "Filter by single category"
 // @Param categories query string false "Filter by multiple categories (comma-separated)"
@@ -133,7 +142,11 @@ func (h *TransactionHandler) Get(w http.ResponseWriter, r *http.Request) {
 // @Param datePreset query string false "Date preset (last7days, last30days, thisMonth, lastMonth)"
 // @Param startDate query string false "Filter by start date (YYYY-MM-DD)"
 // @Param endDate query string false "Filter by end date (YYYY-MM-DD)"
+// @Param accountId query string false "Filter by account"
+// @Param tags query string false "Filter by tags (comma-separated)"
+// @Param tagMatch query string false "Match any (default) or all of the tags"
 // @Success 200 {array} model.Transaction
+// @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /transactions [get]
@@ -194,6 +207,27 @@ func (h *TransactionHandler) List(w http.ResponseWriter, r *http.Request) {
 			input.EndDate = &t
 		}
 	}
+	if accountID := r.URL.Query().Get("accountId"); accountID != "" {
+		if id, err := uuid.Parse(accountID); err == nil {
+			input.AccountID = &id
+		}
+	}
+	if tags := r.URL.Query().Get("tags"); tags != "" {
+		input.Tags = splitAndTrim(tags, ",")
+	}
+	if tagMatch := r.URL.Query().Get("tagMatch"); tagMatch != "" {
+		if tagMatch != repository.TagMatchAny && tagMatch != repository.TagMatchAll {
+			respondAppError(w, apperror.ValidationError("tagMatch", "tagMatch must be any or all"))
+			return
+		}
+		input.TagMatch = tagMatch
+	}
+
+	query := r.URL.Query()
+	if query.Get("pagination") == "cursor" || query.Get("cursor") != "" {
+		h.listPage(w, r, input)
+		return
+	}
 
 	transactions, err := h.service.List(r.Context(), userID, input)
 	if err != nil {
@@ -204,6 +238,59 @@ func (h *TransactionHandler) List(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, transactions)
 }
 
+// transactionPageResponse is a page of transactions with links to the pages
+// next to it
+type transactionPageResponse struct {
+	*service.TransactionPage
+	Links transactionPageLinks `json:"links"`
+}
+
+type transactionPageLinks struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// listPage responds with a cursor-paginated page of transactions
+func (h *TransactionHandler) listPage(w http.ResponseWriter, r *http.Request, input service.ListTransactionsInput) {
+	query := r.URL.Query()
+	input.Cursor = query.Get("cursor")
+	if totals := query.Get("totals"); totals != "" {
+		b, err := strconv.ParseBool(totals)
+		if err != nil {
+			respondAppError(w, apperror.ValidationError("totals", "totals must be true or false"))
+			return
+		}
+		input.IncludeTotals = b
+	}
+
+	page, err := h.service.ListPage(r.Context(), GetUserID(r.Context()), input)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) {
+			respondAppError(w, apperror.ValidationError("cursor", err.Error()))
+			return
+		}
+		respondAppError(w, apperror.Internal(err))
+		return
+	}
+
+	// Links repeat the request with the other page's cursor
+	link := func(cursor string) string {
+		if cursor == "" {
+			return ""
+		}
+		q := r.URL.Query()
+		q.Del("page")
+		q.Del("pagination")
+		q.Set("cursor", cursor)
+		return r.URL.Path + "?" + q.Encode()
+	}
+
+	respondJSON(w, http.StatusOK, transactionPageResponse{
+		TransactionPage: page,
+		Links:           transactionPageLinks{Next: link(page.NextCursor), Prev: link(page.PrevCursor)},
+	})
+}
+
 // Update godoc
 // @Summary Update a transaction
 // @Description Update an existing transaction
@@ -240,6 +327,10 @@ func (h *TransactionHandler) Update(w http.ResponseWriter, r *http.Request) {
 			respondAppError(w, apperror.NotFound("transaction"))
 			return
 		}
+		if appErr := transactionInputError(err); appErr != nil {
+			respondAppError(w, appErr)
+			return
+		}
 		respondAppError(w, apperror.Internal(err))
 		return
 	}
@@ -279,3 +370,83 @@ func (h *TransactionHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// Bulk godoc
+// @Summary Change many transactions at once
+// @Description Recategorize, retag, change the date or account of, or delete up to 1000 transactions picked either by ID or by the same filters as listing transactions. All changes are saved in one database transaction. Transactions the action does not apply to, such as split transactions when recategorizing, are skipped and reported. With dryRun nothing is saved and the result shows what would change.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body service.BulkTransactionInput true "Selection and action"
+// @Success 200 {object} service.BulkTransactionResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /transactions/bulk [post]
+func (h *TransactionHandler) Bulk(w http.ResponseWriter, r *http.Request) {
+	userID := GetUserID(r.Context())
+
+	var input service.BulkTransactionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondAppError(w, apperror.BadRequest("invalid request body: "+err.Error()))
+		return
+	}
+
+	result, err := h.service.Bulk(r.Context(), userID, input)
+	if err != nil {
+		respondAppError(w, bulkError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+func bulkError(err error) *apperror.AppError {
+	if appErr := transactionInputError(err); appErr != nil {
+		return appErr
+	}
+	switch {
+	case errors.Is(err, service.ErrInvalidBulkAction):
+		return apperror.ValidationError("action", err.Error())
+	case errors.Is(err, service.ErrInvalidBulkSelector),
+		errors.Is(err, service.ErrBulkTooMany):
+		return apperror.ValidationError("ids", err.Error())
+	case errors.Is(err, service.ErrBulkCategoryRequired):
+		return apperror.ValidationError("category", err.Error())
+	case errors.Is(err, service.ErrBulkTagsRequired):
+		return apperror.ValidationError("addTags", err.Error())
+	case errors.Is(err, service.ErrBulkDateRequired):
+		return apperror.ValidationError("date", err.Error())
+	case errors.Is(err, repository.ErrTransactionNotFound):
+		// A selected transaction was deleted while the action ran
+		return apperror.Conflict("transactions changed while the bulk action ran, try again")
+	default:
+		return apperror.Internal(err)
+	}
+}
+
+// transactionInputError maps errors about the account a transaction is recorded
+// against, its split lines, tags and exchange rate to API errors. It returns nil
+// for any other error.
+func transactionInputError(err error) *apperror.AppError {
+	switch {
+	case errors.Is(err, repository.ErrAccountNotFound):
+		return apperror.ValidationError("accountId", "account not found")
+	case errors.Is(err, service.ErrAccountArchived),
+		errors.Is(err, service.ErrAccountCurrencyMismatch):
+		return apperror.ValidationError("accountId", err.Error())
+	case errors.Is(err, service.ErrSplitTooFewLines),
+		errors.Is(err, service.ErrInvalidSplitLine),
+		errors.Is(err, service.ErrSplitSumMismatch):
+		return apperror.ValidationError("splits", err.Error())
+	case errors.Is(err, service.ErrInvalidTag),
+		errors.Is(err, service.ErrTooManyTags):
+		return apperror.ValidationError("tags", err.Error())
+	case errors.Is(err, service.ErrInvalidExchangeRate):
+		return apperror.ValidationError("exchangeRate", err.Error())
+	default:
+		return nil
+	}
+}
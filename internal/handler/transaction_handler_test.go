@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -48,6 +49,22 @@ func (m *MockTransactionService) List(ctx context.Context, userID uuid.UUID, inp
 	return args.Get(0).([]model.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionService) ListPage(ctx context.Context, userID uuid.UUID, input service.ListTransactionsInput) (*service.TransactionPage, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.TransactionPage), args.Error(1)
+}
+
+func (m *MockTransactionService) Bulk(ctx context.Context, userID uuid.UUID, input service.BulkTransactionInput) (*service.BulkTransactionResult, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.BulkTransactionResult), args.Error(1)
+}
+
 func (m *MockTransactionService) Update(ctx context.Context, id, userID uuid.UUID, input service.UpdateTransactionInput) (*model.Transaction, error) {
 	args := m.Called(ctx, id, userID, input)
 	if args.Get(0) == nil {
@@ -91,6 +108,61 @@ func TestTransactionHandler_Create_Success(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestTransactionHandler_Create_InputErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		serviceErr error
+		wantStatus int
+	}{
+		{"unknown account", fmt.Errorf("getting account: %w", repository.ErrAccountNotFound), http.StatusBadRequest},
+		{"archived account", service.ErrAccountArchived, http.StatusBadRequest},
+		{"currency mismatch", service.ErrAccountCurrencyMismatch, http.StatusBadRequest},
+		{"splits do not add up", service.ErrSplitSumMismatch, http.StatusBadRequest},
+		{"invalid exchange rate", fmt.Errorf("converting to base currency: %w", service.ErrInvalidExchangeRate), http.StatusBadRequest},
+		{"other error", errors.New("db error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockTransactionService)
+			handler := NewTransactionHandler(mockService)
+			userID := uuid.New()
+
+			mockService.On("Create", mock.Anything, userID, mock.Anything).Return(nil, tt.serviceErr)
+
+			body := []byte(`{"type":"expense","amount":"100","category":"Food","accountId":"` + uuid.NewString() + `"}`)
+			req := httptest.NewRequest(http.MethodPost, "/api/transactions", bytes.NewReader(body))
+			req = req.WithContext(context.WithValue(req.Context(), UserIDKey, userID))
+
+			rr := httptest.NewRecorder()
+			handler.Create(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+		})
+	}
+}
+
+func TestTransactionHandler_Create_SplitsWithoutCategory(t *testing.T) {
+	mockService := new(MockTransactionService)
+	handler := NewTransactionHandler(mockService)
+	userID := uuid.New()
+
+	mockService.On("Create", mock.Anything, userID, mock.MatchedBy(func(input service.CreateTransactionInput) bool {
+		return len(input.Splits) == 2 && input.Splits[1].Note == "Detergent"
+	})).Return(&model.Transaction{ID: uuid.New(), Category: "Groceries"}, nil)
+
+	body := []byte(`{"type":"expense","amount":"300","splits":[` +
+		`{"category":"Groceries","amount":"200"},{"category":"Household","amount":"100","note":"Detergent"}]}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/transactions", bytes.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), UserIDKey, userID))
+
+	rr := httptest.NewRecorder()
+	handler.Create(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestTransactionHandler_Create_InvalidBody(t *testing.T) {
 	mockService := new(MockTransactionService)
 	handler := NewTransactionHandler(mockService)
@@ -283,6 +355,156 @@ func TestTransactionHandler_List_Error(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestTransactionHandler_List_TagFilters(t *testing.T) {
+	mockService := new(MockTransactionService)
+	handler := NewTransactionHandler(mockService)
+
+	userID := uuid.New()
+	mockService.On("List", mock.Anything, userID, mock.MatchedBy(func(input service.ListTransactionsInput) bool {
+		return len(input.Tags) == 2 && input.Tags[1] == "family" && input.TagMatch == "all"
+	})).Return([]model.Transaction{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/transactions?tags=vacation,%20family&tagMatch=all", nil)
+	req = req.WithContext(context.WithValue(req.Context(), UserIDKey, userID))
+	rr := httptest.NewRecorder()
+	handler.List(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/transactions?tags=vacation&tagMatch=some", nil)
+	req = req.WithContext(context.WithValue(req.Context(), UserIDKey, userID))
+	rr = httptest.NewRecorder()
+	handler.List(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "tagMatch")
+}
+
+func TestTransactionHandler_List_Cursor(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		page       *service.TransactionPage
+		serviceErr error
+		wantInput  func(input service.ListTransactionsInput) bool
+		wantStatus int
+		wantNext   string
+		wantPrev   string
+	}{
+		{
+			name:       "first page with totals",
+			query:      "?pagination=cursor&type=expense&totals=true",
+			page:       &service.TransactionPage{Transactions: []model.Transaction{}, NextCursor: "abc", Totals: &model.TransactionTotals{Count: 3}},
+			wantInput:  func(in service.ListTransactionsInput) bool { return in.Cursor == "" && in.IncludeTotals },
+			wantStatus: http.StatusOK,
+			wantNext:   "/api/transactions?cursor=abc&totals=true&type=expense",
+		},
+		{
+			name:       "page after a cursor",
+			query:      "?cursor=abc&page=3",
+			page:       &service.TransactionPage{Transactions: []model.Transaction{}, PrevCursor: "xyz"},
+			wantInput:  func(in service.ListTransactionsInput) bool { return in.Cursor == "abc" && !in.IncludeTotals },
+			wantStatus: http.StatusOK,
+			wantPrev:   "/api/transactions?cursor=xyz",
+		},
+		{
+			name:       "invalid totals",
+			query:      "?pagination=cursor&totals=maybe",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid cursor",
+			query:      "?cursor=abc",
+			serviceErr: service.ErrInvalidCursor,
+			wantInput:  func(in service.ListTransactionsInput) bool { return true },
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockTransactionService)
+			handler := NewTransactionHandler(mockService)
+			userID := uuid.New()
+
+			if tt.wantInput != nil {
+				var page interface{}
+				if tt.page != nil {
+					page = tt.page
+				}
+				mockService.On("ListPage", mock.Anything, userID, mock.MatchedBy(tt.wantInput)).Return(page, tt.serviceErr)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/transactions"+tt.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), UserIDKey, userID))
+			rr := httptest.NewRecorder()
+			handler.List(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			mockService.AssertExpectations(t)
+			mockService.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Transactions []model.Transaction `json:"transactions"`
+				Links        struct {
+					Next string `json:"next"`
+					Prev string `json:"prev"`
+				} `json:"links"`
+			}
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+			assert.NotNil(t, body.Transactions)
+			assert.Equal(t, tt.wantNext, body.Links.Next)
+			assert.Equal(t, tt.wantPrev, body.Links.Prev)
+		})
+	}
+}
+
+func TestTransactionHandler_Bulk(t *testing.T) {
+	txID := uuid.New()
+
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "success", body: `{"ids":["` + txID.String() + `"],"action":"recategorize","category":"Food","dryRun":true}`, wantStatus: http.StatusOK},
+		{name: "invalid body", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "unknown action", body: `{}`, serviceErr: service.ErrInvalidBulkAction, wantStatus: http.StatusBadRequest},
+		{name: "too many", body: `{}`, serviceErr: service.ErrBulkTooMany, wantStatus: http.StatusBadRequest},
+		{name: "archived account", body: `{}`, serviceErr: service.ErrAccountArchived, wantStatus: http.StatusBadRequest},
+		{name: "changed meanwhile", body: `{}`, serviceErr: repository.ErrTransactionNotFound, wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockTransactionService)
+			handler := NewTransactionHandler(mockService)
+			userID := uuid.New()
+
+			if tt.serviceErr != nil {
+				mockService.On("Bulk", mock.Anything, userID, mock.Anything).Return(nil, tt.serviceErr)
+			} else if tt.wantStatus == http.StatusOK {
+				mockService.On("Bulk", mock.Anything, userID, mock.MatchedBy(func(in service.BulkTransactionInput) bool {
+					return len(in.IDs) == 1 && in.IDs[0] == txID && in.Action == service.BulkActionRecategorize && in.DryRun
+				})).Return(&service.BulkTransactionResult{Action: service.BulkActionRecategorize, DryRun: true, Matched: 1, Affected: 1}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/transactions/bulk", bytes.NewBufferString(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), UserIDKey, userID))
+			rr := httptest.NewRecorder()
+			handler.Bulk(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestTransactionHandler_Update_Success(t *testing.T) {
 	mockService := new(MockTransactionService)
 	handler := NewTransactionHandler(mockService)
@@ -0,0 +1,534 @@
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/scraper/pdf"
+	"github.com/wealthpath/backend/pkg/currency"
+)
+
+// Banks whose PDF e-statements ParseBankPDF can read
+const (
+	BankVietcombank = "vcb"
+	BankTechcombank = "tcb"
+	BankMB          = "mb"
+	BankACB         = "acb"
+)
+
+var (
+	ErrUnsupportedPDF      = errors.New("the PDF is not a Vietcombank, Techcombank, MB Bank or ACB statement in a layout we can read")
+	ErrStatementUnbalanced = errors.New("the transactions do not add up to the statement's running balance")
+)
+
+// Columns of a statement's transaction table
+const (
+	columnDate        = "date"
+	columnDescription = "description"
+	columnDebit       = "debit"
+	columnCredit      = "credit"
+	columnBalance     = "balance"
+)
+
+// bankLayout describes one bank's e-statement. All text is folded with
+// pdf.FoldVietnamese: lower case and without diacritics.
+type bankLayout struct {
+	bank    string
+	name    string
+	markers []string            // Words in the letterhead that name the bank
+	columns map[string][]string // Header text of each column, Vietnamese and English
+}
+
+// bankLayouts are tried in order. A header cell belongs to the first column
+// whose names it contains; columns such as reference numbers are ignored.
+var bankLayouts = []bankLayout{
+	{
+		bank:    BankVietcombank,
+		name:    "Vietcombank",
+		markers: []string{"vietcombank", "ngan hang tmcp ngoai thuong"},
+		columns: map[string][]string{
+			columnDate:        {"ngay giao dich", "transaction date"},
+			columnDescription: {"mo ta", "noi dung", "description", "transactions in detail"},
+			columnDebit:       {"ghi no", "debit"},
+			columnCredit:      {"ghi co", "credit"},
+			columnBalance:     {"so du", "balance"},
+		},
+	},
+	{
+		bank:    BankTechcombank,
+		name:    "Techcombank",
+		markers: []string{"techcombank", "ngan hang tmcp ky thuong"},
+		columns: map[string][]string{
+			columnDate:        {"ngay giao dich", "transaction date"},
+			columnDescription: {"dien giai", "details"},
+			columnDebit:       {"no/debit", "ghi no", "debit"},
+			columnCredit:      {"co/credit", "ghi co", "credit"},
+			columnBalance:     {"so du", "balance"},
+		},
+	},
+	{
+		bank:    BankMB,
+		name:    "MB Bank",
+		markers: []string{"mb bank", "mbbank", "ngan hang tmcp quan doi", "military commercial"},
+		columns: map[string][]string{
+			columnDate:        {"ngay giao dich", "ngay gd", "transaction date"},
+			columnDescription: {"noi dung", "description"},
+			columnDebit:       {"ghi no", "debit"},
+			columnCredit:      {"ghi co", "credit"},
+			columnBalance:     {"so du", "balance"},
+		},
+	},
+	{
+		bank:    BankACB,
+		name:    "ACB",
+		markers: []string{"acb", "ngan hang tmcp a chau", "asia commercial bank"},
+		columns: map[string][]string{
+			columnDate:        {"ngay hieu luc", "ngay giao dich", "effective date", "transaction date"},
+			columnDescription: {"mo ta", "noi dung", "description"},
+			columnDebit:       {"rut ra", "ghi no", "debit"},
+			columnCredit:      {"gui vao", "ghi co", "credit"},
+			columnBalance:     {"so du", "balance"},
+		},
+	},
+}
+
+// columnOrder is the order header cells are matched against columns. The
+// balance comes first so that "so du" is not mistaken for anything else.
+var columnOrder = []string{columnBalance, columnDebit, columnCredit, columnDate, columnDescription}
+
+// Labels of the lines around the transaction table, folded. Totals must start
+// the line; the others can follow other text.
+var (
+	openingBalanceLabels = []string{"so du dau ky", "so du dau", "opening balance", "beginning balance"}
+	closingBalanceLabels = []string{"so du cuoi ky", "so du cuoi", "closing balance", "ending balance"}
+	totalLabels          = []string{"tong cong", "tong so", "total"}
+	accountLabels        = []string{"so tai khoan", "account number", "account no"}
+	currencyLabels       = []string{"loai tien", "currency"}
+)
+
+var (
+	// tableDate matches the start of a date cell such as 05/03/2024 or 2024-03-05
+	tableDate = regexp.MustCompile(`^\d{1,4}[/.-]\d{1,2}[/.-]\d{1,4}`)
+	// accountNumber matches a bank account number
+	accountNumber = regexp.MustCompile(`\d{6,20}`)
+	// currencyCode matches an ISO currency code
+	currencyCode = regexp.MustCompile(`\b[A-Z]{3}\b`)
+	// wordSeparators are replaced with spaces before looking for whole words
+	wordSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// tableColumn is a header cell of the transaction table
+type tableColumn struct {
+	name     string // Empty for columns that are not read
+	x, right float64
+}
+
+// pdfRow is a transaction as printed on the statement
+type pdfRow struct {
+	date, description, debit, credit, balance string
+
+	page int
+	y    float64
+}
+
+// IsPDF reports whether a file is a PDF
+func IsPDF(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimLeft(data[:min(len(data), 1024)], " \t\r\n"), []byte("%PDF-"))
+}
+
+// ParseBankPDF reads the transaction table of a Vietcombank, Techcombank, MB
+// Bank or ACB e-statement. Every transaction is checked against the
+// statement's running balance, so that a misread layout is reported rather
+// than imported. Statements have no transaction IDs; each ExternalID is a hash
+// of the transaction's date, amounts, balance and description.
+func ParseBankPDF(data []byte) (*Statement, error) {
+	lines, err := pdf.ExtractLines(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedFile, err)
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%w: the PDF has no text; scanned statements cannot be read", ErrUnsupportedPDF)
+	}
+	return parseBankStatement(lines)
+}
+
+// parseBankStatement reads a statement from the lines of its PDF
+func parseBankStatement(lines []pdf.TextLine) (*Statement, error) {
+	folded := make([]string, len(lines))
+	for i, line := range lines {
+		folded[i] = pdf.FoldVietnamese(line.Text())
+	}
+
+	layout, start, err := detectBankLayout(lines, folded)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &Statement{Currency: string(currency.VND)}
+	for i, line := range lines[:start] {
+		switch {
+		case statement.Account == "" && hasLabel(folded[i], accountLabels):
+			statement.Account = accountNumber.FindString(strings.ReplaceAll(line.Text(), " ", ""))
+		case hasLabel(folded[i], currencyLabels):
+			// The code follows the label, as in "Loại tiền/Currency: VND"
+			if codes := currencyCode.FindAllString(strings.ToUpper(line.Text()), -1); len(codes) > 0 {
+				statement.Currency = codes[len(codes)-1]
+			}
+		}
+	}
+
+	rows, opening, closing := readBankTable(layout, lines[start:], folded[start:])
+	if opening == "" || closing == "" {
+		// The balances can also be printed before the table
+		for i, line := range lines[:start] {
+			if opening == "" && hasLabel(folded[i], openingBalanceLabels) {
+				opening = lastAmountCell(line)
+			}
+			if closing == "" && hasLabel(folded[i], closingBalanceLabels) {
+				closing = lastAmountCell(line)
+			}
+		}
+	}
+	if len(rows) == 0 {
+		return nil, ErrNoTransactions
+	}
+
+	transactions, err := bankTransactions(layout, statement, rows, opening, closing)
+	if err != nil {
+		return nil, err
+	}
+	statement.Transactions = transactions
+	return statement, nil
+}
+
+// detectBankLayout finds the bank whose letterhead and table header the
+// statement has. It returns the index of the header line.
+func detectBankLayout(lines []pdf.TextLine, folded []string) (*bankLayout, int, error) {
+	var named *bankLayout
+	for i := range bankLayouts {
+		layout := &bankLayouts[i]
+		for j := range lines {
+			if hasWord(folded[j], layout.markers) {
+				if named == nil {
+					named = layout
+				}
+				if start := layout.findHeader(lines[j:]); start >= 0 {
+					return layout, j + start, nil
+				}
+				break
+			}
+		}
+	}
+	if named != nil {
+		return nil, 0, fmt.Errorf("%w: the transaction table of this %s statement has columns we do not recognise", ErrUnsupportedPDF, named.name)
+	}
+	return nil, 0, ErrUnsupportedPDF
+}
+
+// findHeader returns the index of the first line that is the layout's table
+// header, or -1
+func (l *bankLayout) findHeader(lines []pdf.TextLine) int {
+	for i, line := range lines {
+		if l.headerColumns(line) != nil {
+			return i
+		}
+	}
+	return -1
+}
+
+// headerColumns returns the table columns if the line is the layout's table
+// header, or nil if it is not
+func (l *bankLayout) headerColumns(line pdf.TextLine) []tableColumn {
+	columns := make([]tableColumn, len(line.Cells))
+	found := map[string]bool{}
+	for i, cell := range line.Cells {
+		text := pdf.FoldVietnamese(cell.Text)
+		columns[i] = tableColumn{x: cell.X, right: cell.Right}
+		for _, name := range columnOrder {
+			if found[name] {
+				continue
+			}
+			if slices.ContainsFunc(l.columns[name], func(s string) bool { return strings.Contains(text, s) }) {
+				columns[i].name = name
+				found[name] = true
+				break
+			}
+		}
+	}
+	if len(found) != len(columnOrder) {
+		return nil
+	}
+	return columns
+}
+
+// readBankTable reads the rows of the transaction table, which starts with a
+// header line and may continue over several pages. A description that wraps
+// continues on the following lines. It also returns the opening and closing
+// balances when the table has them.
+func readBankTable(layout *bankLayout, lines []pdf.TextLine, folded []string) ([]pdfRow, string, string) {
+	var (
+		rows             []pdfRow
+		columns          []tableColumn
+		current          *pdfRow
+		opening, closing string
+	)
+	for i, line := range lines {
+		if header := layout.headerColumns(line); header != nil {
+			columns, current = header, nil
+			continue
+		}
+		if columns == nil {
+			continue
+		}
+
+		switch {
+		case hasLabel(folded[i], openingBalanceLabels):
+			opening, current = lastAmountCell(line), nil
+			continue
+		case hasLabel(folded[i], closingBalanceLabels):
+			closing, current = lastAmountCell(line), nil
+			continue
+		case slices.ContainsFunc(totalLabels, func(label string) bool { return strings.HasPrefix(folded[i], label) }):
+			current = nil
+			continue
+		}
+
+		cells := assignCells(columns, line)
+		switch {
+		case tableDate.MatchString(cells[columnDate]):
+			rows = append(rows, pdfRow{
+				date:        cells[columnDate],
+				description: cells[columnDescription],
+				debit:       cells[columnDebit],
+				credit:      cells[columnCredit],
+				balance:     cells[columnBalance],
+				page:        line.Page,
+				y:           line.Y,
+			})
+			current = &rows[len(rows)-1]
+		case current != nil && cells[columnDebit] == "" && cells[columnCredit] == "" && cells[columnBalance] == "" &&
+			line.Page == current.page && current.y-line.Y <= 2.5*line.Size:
+			if cells[columnDescription] != "" {
+				current.description = strings.TrimSpace(current.description + " " + cells[columnDescription])
+			}
+			current.y = line.Y
+		default:
+			current = nil
+		}
+	}
+	return rows, opening, closing
+}
+
+// assignCells puts each cell of a line in the column it overlaps most, or the
+// nearest one. Cells of columns that are not read are dropped.
+func assignCells(columns []tableColumn, line pdf.TextLine) map[string]string {
+	cells := map[string]string{}
+	for _, cell := range line.Cells {
+		best, bestScore := -1, 0.0
+		for i, col := range columns {
+			score := min(cell.Right, col.right) - max(cell.X, col.x)
+			if score <= 0 {
+				// No overlap: prefer the nearest column, behind any that overlap
+				score = -min(abs(cell.X-col.right), abs(col.x-cell.Right)) - 1e6
+			}
+			if best < 0 || score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best < 0 || columns[best].name == "" {
+			continue
+		}
+		name := columns[best].name
+		cells[name] = strings.TrimSpace(cells[name] + " " + cell.Text)
+	}
+	return cells
+}
+
+// bankTransactions parses the rows and checks them against the balances
+func bankTransactions(layout *bankLayout, statement *Statement, rows []pdfRow, opening, closing string) ([]model.Transaction, error) {
+	dates := make([]string, len(rows))
+	amounts := []string{opening, closing}
+	for i, row := range rows {
+		dates[i] = row.date
+		amounts = append(amounts, row.debit, row.credit, row.balance)
+	}
+	dateFormat, ok := DetectDateFormat(dates)
+	if !ok {
+		return nil, fmt.Errorf("%w: the transaction dates are in an unknown format", ErrUnsupportedPDF)
+	}
+	numberFormat := DetectNumberFormat(amounts)
+
+	amount := func(value string) (*decimal.Decimal, error) {
+		if value == "" || value == "-" {
+			return nil, nil
+		}
+		d, err := ParseAmount(value, numberFormat)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedPDF, err)
+		}
+		return &d, nil
+	}
+
+	openingBalance, err := amount(opening)
+	if err != nil {
+		return nil, err
+	}
+	closingBalance, err := amount(closing)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]balanceEntry, len(rows))
+	for i, row := range rows {
+		date, err := ParseDate(row.date, dateFormat)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedPDF, err)
+		}
+		debit, err := amount(row.debit)
+		if err != nil {
+			return nil, err
+		}
+		credit, err := amount(row.credit)
+		if err != nil {
+			return nil, err
+		}
+		balance, err := amount(row.balance)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := balanceEntry{row: row, balance: balance}
+		switch {
+		case isNonZero(debit) && !isNonZero(credit):
+			entry.amount = debit.Abs().Neg()
+		case isNonZero(credit) && !isNonZero(debit):
+			entry.amount = credit.Abs()
+		default:
+			return nil, fmt.Errorf("%w: the transaction of %s must have either a debit or a credit amount", ErrUnsupportedPDF, row.date)
+		}
+		entry.tx = model.Transaction{
+			Type:        model.TransactionTypeIncome,
+			Amount:      entry.amount.Abs(),
+			Currency:    statement.Currency,
+			Description: row.description,
+			Date:        date,
+		}
+		if entry.amount.IsNegative() {
+			entry.tx.Type = model.TransactionTypeExpense
+		}
+		entries[i] = entry
+	}
+
+	entries, err = reconcile(entries, openingBalance, closingBalance)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := externalIDs{}
+	transactions := make([]model.Transaction, len(entries))
+	for i, e := range entries {
+		balance := ""
+		if e.balance != nil {
+			balance = e.balance.String()
+		}
+		id := ids.next("pdf:"+layout.bank+":"+statement.Account,
+			e.tx.Date.Format("2006-01-02"), e.amount.String(), balance, e.tx.Description)
+		e.tx.ExternalID = &id
+		transactions[i] = e.tx
+	}
+	return transactions, nil
+}
+
+// balanceEntry is a parsed row with its signed amount and printed balance
+type balanceEntry struct {
+	row     pdfRow
+	tx      model.Transaction
+	amount  decimal.Decimal
+	balance *decimal.Decimal
+}
+
+// reconcile checks that each transaction takes the previous balance to the
+// one printed next to it, and that the last balance is the closing balance.
+// Statements that list the newest transaction first are put in date order.
+func reconcile(entries []balanceEntry, opening, closing *decimal.Decimal) ([]balanceEntry, error) {
+	err := checkBalances(entries, opening, closing)
+	if err == nil {
+		return entries, nil
+	}
+	reversed := slices.Clone(entries)
+	slices.Reverse(reversed)
+	if checkBalances(reversed, opening, closing) == nil {
+		return reversed, nil
+	}
+	return nil, err
+}
+
+func checkBalances(entries []balanceEntry, opening, closing *decimal.Decimal) error {
+	var running *decimal.Decimal
+	if opening != nil {
+		running = opening
+	}
+	printed := 0
+	for _, e := range entries {
+		if running != nil {
+			expected := running.Add(e.amount)
+			if e.balance != nil && !e.balance.Equal(expected) {
+				return fmt.Errorf("%w: after the transaction of %s the balance should be %s, but the statement shows %s",
+					ErrStatementUnbalanced, e.row.date, expected, e.balance)
+			}
+			running = &expected
+		}
+		if e.balance != nil {
+			running = e.balance
+			printed++
+		}
+	}
+	if printed == 0 {
+		return fmt.Errorf("%w: no balances could be read", ErrStatementUnbalanced)
+	}
+	if closing != nil && running != nil && !closing.Equal(*running) {
+		return fmt.Errorf("%w: the transactions end at %s, but the closing balance is %s", ErrStatementUnbalanced, running, closing)
+	}
+	return nil
+}
+
+// lastAmountCell returns the last cell of a line that starts with a digit,
+// which is where statements print balances next to their label
+func lastAmountCell(line pdf.TextLine) string {
+	for i := len(line.Cells) - 1; i >= 0; i-- {
+		text := strings.TrimLeft(line.Cells[i].Text, "-+(")
+		if text != "" && text[0] >= '0' && text[0] <= '9' {
+			return line.Cells[i].Text
+		}
+	}
+	return ""
+}
+
+// hasLabel reports whether folded text contains one of the labels
+func hasLabel(folded string, labels []string) bool {
+	return slices.ContainsFunc(labels, func(label string) bool { return strings.Contains(folded, label) })
+}
+
+// hasWord reports whether folded text contains one of the phrases as whole words
+func hasWord(folded string, phrases []string) bool {
+	text := " " + wordSeparators.ReplaceAllString(folded, " ") + " "
+	return slices.ContainsFunc(phrases, func(p string) bool { return strings.Contains(text, " "+p+" ") })
+}
+
+func isNonZero(d *decimal.Decimal) bool {
+	return d != nil && !d.IsZero()
+}
+
+func abs(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
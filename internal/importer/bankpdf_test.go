@@ -0,0 +1,198 @@
+package importer
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/scraper/pdf"
+)
+
+// layoutCells splits a fixture line into cells separated by two or more spaces
+var layoutCells = regexp.MustCompile(`\S+(?: \S+)*`)
+
+// textLines lays out a fixed-width text statement as the lines of a PDF, with
+// each character 5 points wide and lines 12 points apart. A form feed starts a
+// new page.
+func textLines(text string) []pdf.TextLine {
+	var lines []pdf.TextLine
+	for p, page := range strings.Split(text, "\f\n") {
+		for i, line := range strings.Split(page, "\n") {
+			tl := pdf.TextLine{Page: p + 1, Y: 800 - float64(i*12), Size: 10}
+			for _, loc := range layoutCells.FindAllStringIndex(line, -1) {
+				start := utf8.RuneCountInString(line[:loc[0]])
+				end := start + utf8.RuneCountInString(line[loc[0]:loc[1]])
+				tl.Cells = append(tl.Cells, pdf.TextCell{X: float64(start * 5), Right: float64(end * 5), Text: line[loc[0]:loc[1]]})
+			}
+			if len(tl.Cells) > 0 {
+				lines = append(lines, tl)
+			}
+		}
+	}
+	return lines
+}
+
+func TestParseBankStatement(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		fixture      string
+		wantAccount  string
+		wantCurrency string
+		wantIDPrefix string
+		want         []wantTxn
+	}{
+		{
+			name:         "Vietcombank with balances outside the table",
+			fixture:      "vietcombank.txt",
+			wantAccount:  "0071000123456",
+			wantCurrency: "VND",
+			wantIDPrefix: "pdf:vcb:0071000123456:",
+			want: []wantTxn{
+				{date: "2024-03-01", txType: model.TransactionTypeExpense, amount: "50000", description: "GRAB*A-5XYZ Ha Noi"},
+				{date: "2024-03-02", txType: model.TransactionTypeIncome, amount: "15000000", description: "CONG TY ABC TRA LUONG THANG 02/2024"},
+				{date: "2024-03-05", txType: model.TransactionTypeExpense, amount: "1200000", description: "Thanh toán hóa đơn điện EVN"},
+			},
+		},
+		{
+			name:         "Techcombank listing the newest transaction first",
+			fixture:      "techcombank.txt",
+			wantAccount:  "19036812345678",
+			wantCurrency: "VND",
+			wantIDPrefix: "pdf:tcb:19036812345678:",
+			want: []wantTxn{
+				{date: "2024-03-15", txType: model.TransactionTypeExpense, amount: "50000", description: "Mua hang"},
+				{date: "2024-03-18", txType: model.TransactionTypeIncome, amount: "1000000", description: "Chuyen tien an trua nhom van phong"},
+				{date: "2024-03-20", txType: model.TransactionTypeExpense, amount: "350000", description: "Thanh toan don hang"},
+			},
+		},
+		{
+			name:         "MB Bank over two pages",
+			fixture:      "mbbank.txt",
+			wantAccount:  "0801234567890",
+			wantCurrency: "VND",
+			wantIDPrefix: "pdf:mb:0801234567890:",
+			want: []wantTxn{
+				{date: "2024-04-01", txType: model.TransactionTypeIncome, amount: "500000", description: "NHAN TIEN TU TRAN THI B"},
+				{date: "2024-04-02", txType: model.TransactionTypeExpense, amount: "120000", description: "THANH TOAN QR HIGHLANDS COFFEE"},
+				{date: "2024-04-03", txType: model.TransactionTypeExpense, amount: "80000", description: "PHI DICH VU SMS BANKING THANG 03"},
+			},
+		},
+		{
+			name:         "ACB with the opening balance as a table row",
+			fixture:      "acb.txt",
+			wantAccount:  "12345678",
+			wantCurrency: "VND",
+			wantIDPrefix: "pdf:acb:12345678:",
+			want: []wantTxn{
+				{date: "2024-05-10", txType: model.TransactionTypeExpense, amount: "500000", description: "Rut tien ATM"},
+				{date: "2024-05-11", txType: model.TransactionTypeIncome, amount: "1250", description: "Lai tien gui"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			statement, err := parseBankStatement(textLines(readFixture(t, tt.fixture)))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantAccount, statement.Account)
+			assert.Equal(t, tt.wantCurrency, statement.Currency)
+			assertTransactions(t, tt.want, statement.Transactions)
+			for _, tx := range statement.Transactions {
+				require.NotNil(t, tx.ExternalID)
+				assert.True(t, strings.HasPrefix(*tx.ExternalID, tt.wantIDPrefix), *tx.ExternalID)
+				assert.Equal(t, tt.wantCurrency, tx.Currency)
+			}
+		})
+	}
+}
+
+func TestParseBankStatement_StableIDs(t *testing.T) {
+	t.Parallel()
+
+	first, err := parseBankStatement(textLines(readFixture(t, "vietcombank.txt")))
+	require.NoError(t, err)
+	second, err := parseBankStatement(textLines(readFixture(t, "vietcombank.txt")))
+	require.NoError(t, err)
+
+	for i := range first.Transactions {
+		assert.Equal(t, *first.Transactions[i].ExternalID, *second.Transactions[i].ExternalID)
+	}
+	assert.NotEqual(t, *first.Transactions[0].ExternalID, *first.Transactions[1].ExternalID)
+}
+
+func TestParseBankStatement_Errors(t *testing.T) {
+	t.Parallel()
+
+	vcb := readFixture(t, "vietcombank.txt")
+	tests := []struct {
+		name    string
+		text    string
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "balance that does not follow from the transactions",
+			text:    strings.Replace(vcb, "9,950,000", "9,990,000", 1),
+			wantErr: ErrStatementUnbalanced,
+			wantMsg: "01/03/2024",
+		},
+		{
+			name:    "closing balance that does not match",
+			text:    strings.Replace(vcb, "Closing balance:  23,750,000", "Closing balance:  23,000,000", 1),
+			wantErr: ErrStatementUnbalanced,
+			wantMsg: "closing balance",
+		},
+		{
+			name:    "transaction with both a debit and a credit",
+			text:    strings.Replace(vcb, "50,000                         9,950,000", "50,000           50,000        9,950,000", 1),
+			wantErr: ErrUnsupportedPDF,
+			wantMsg: "01/03/2024",
+		},
+		{
+			name:    "known bank with an unknown table",
+			text:    strings.NewReplacer("Số dư  Mô tả", "Total  Mô tả", "Balance  Description", "Total  Description").Replace(vcb),
+			wantErr: ErrUnsupportedPDF,
+			wantMsg: "Vietcombank",
+		},
+		{
+			name:    "unknown bank",
+			text:    strings.Replace(vcb, "NGOẠI THƯƠNG VIỆT NAM  (VIETCOMBANK)", "XYZ", 1),
+			wantErr: ErrUnsupportedPDF,
+		},
+		{
+			name:    "table without transactions",
+			text:    vcb[:strings.Index(vcb, "01/03/2024")],
+			wantErr: ErrNoTransactions,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parseBankStatement(textLines(tt.text))
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Contains(t, err.Error(), tt.wantMsg)
+		})
+	}
+}
+
+func TestParseBankPDF_NotAPDF(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseBankPDF([]byte("%PDF-1.4\nnot really a PDF"))
+	assert.ErrorIs(t, err, ErrMalformedFile)
+	assert.False(t, IsPDF([]byte("date,amount\n")))
+	assert.True(t, IsPDF([]byte("\n%PDF-1.7\n")))
+}
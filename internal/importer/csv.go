@@ -0,0 +1,264 @@
+// Package importer reads transactions from files exported by banks and other
+// finance apps.
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/wealthpath/backend/internal/model"
+)
+
+// delimiterCandidates are tried by DetectDelimiter in order of preference
+var delimiterCandidates = []rune{',', ';', '\t', '|'}
+
+// sniffLines is how many lines detection looks at
+const sniffLines = 50
+
+var (
+	ErrEmptyFile       = errors.New("the file has no rows")
+	ErrMalformedFile   = errors.New("the file is not valid CSV")
+	ErrInvalidMapping  = errors.New("the date and amount columns are required and every column must exist in the file")
+	ErrInvalidSettings = errors.New("invalid import settings")
+)
+
+// ReadCSV splits CSV text into records. Records may have different numbers of
+// fields; blank lines are skipped.
+func ReadCSV(text string, delimiter rune) ([][]string, error) {
+	r := csv.NewReader(strings.NewReader(text))
+	r.Comma = delimiter
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.TrimLeadingSpace = true
+
+	var records [][]string
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformedFile, err)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		records = append(records, record)
+	}
+	if len(records) == 0 {
+		return nil, ErrEmptyFile
+	}
+	return records, nil
+}
+
+// DetectDelimiter picks the delimiter that splits the first lines of the text
+// into the same number of fields most consistently
+func DetectDelimiter(text string) rune {
+	lines := strings.SplitN(text, "\n", sniffLines+1)
+	if len(lines) > sniffLines {
+		lines = lines[:sniffLines]
+	}
+	sample := strings.Join(lines, "\n")
+
+	best, bestScore := ',', 0
+	for _, d := range delimiterCandidates {
+		records, err := ReadCSV(sample, d)
+		if err != nil {
+			continue
+		}
+		counts := map[int]int{}
+		for _, rec := range records {
+			counts[len(rec)]++
+		}
+		// Score the most common field count by how many lines share it
+		for fields, lines := range counts {
+			if fields < 2 {
+				continue
+			}
+			if score := lines*100 + fields; score > bestScore {
+				best, bestScore = d, score
+			}
+		}
+	}
+	return best
+}
+
+// ColumnMapping says which column, counted from zero, holds each field. Date and
+// Amount are required. Without a Type column, negative amounts are expenses and
+// positive ones income.
+type ColumnMapping struct {
+	Date        *int `json:"date"`
+	Amount      *int `json:"amount"`
+	Type        *int `json:"type,omitempty"`
+	Category    *int `json:"category,omitempty"`
+	Description *int `json:"description,omitempty"`
+	Currency    *int `json:"currency,omitempty"`
+}
+
+// Validate checks the required columns are mapped and every mapped column is
+// within the given number of columns
+func (m ColumnMapping) Validate(columns int) error {
+	if m.Date == nil || m.Amount == nil {
+		return ErrInvalidMapping
+	}
+	for _, col := range []*int{m.Date, m.Amount, m.Type, m.Category, m.Description, m.Currency} {
+		if col != nil && (*col < 0 || *col >= columns) {
+			return ErrInvalidMapping
+		}
+	}
+	return nil
+}
+
+// headerNames are the column headers SuggestMapping recognises, in English and
+// Vietnamese, with and without diacritics
+var headerNames = map[string][]string{
+	"date":        {"date", "transaction date", "posting date", "ngày", "ngay", "ngày giao dịch", "ngay giao dich", "thời gian", "ngày gd"},
+	"amount":      {"amount", "value", "số tiền", "so tien", "giá trị", "số tiền (vnd)"},
+	"type":        {"type", "transaction type", "loại", "loai", "loại giao dịch", "thu/chi"},
+	"category":    {"category", "danh mục", "danh muc", "nhóm", "hạng mục"},
+	"description": {"description", "memo", "note", "notes", "details", "payee", "mô tả", "mo ta", "nội dung", "noi dung", "diễn giải", "dien giai", "ghi chú", "ghi chu"},
+	"currency":    {"currency", "tiền tệ", "loại tiền", "đơn vị tiền"},
+}
+
+// SuggestMapping maps columns whose headers it recognises
+func SuggestMapping(header []string) ColumnMapping {
+	var m ColumnMapping
+	targets := map[string]**int{
+		"date": &m.Date, "amount": &m.Amount, "type": &m.Type,
+		"category": &m.Category, "description": &m.Description, "currency": &m.Currency,
+	}
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		for field, names := range headerNames {
+			target := targets[field]
+			if *target != nil {
+				continue
+			}
+			for _, name := range names {
+				if h == name {
+					col := i
+					*target = &col
+					break
+				}
+			}
+		}
+	}
+	return m
+}
+
+// LooksLikeHeader reports whether the first record is a header row: none of its
+// fields is a number or a date in a known format
+func LooksLikeHeader(record []string) bool {
+	for _, field := range record {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if _, ok := DetectDateFormat([]string{field}); ok {
+			return false
+		}
+		if _, err := ParseAmount(field, NumberFormatEN); err == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Column returns the values of one column, skipping records that are too short
+func Column(records [][]string, col int) []string {
+	values := make([]string, 0, len(records))
+	for _, rec := range records {
+		if col < len(rec) {
+			values = append(values, rec[col])
+		}
+	}
+	return values
+}
+
+// Row is one transaction read from a file. Rows that cannot be read keep their
+// line number and the reason in Error.
+type Row struct {
+	Line        int                   `json:"line"` // 1-based record number in the file
+	Date        time.Time             `json:"date"`
+	Type        model.TransactionType `json:"type"`
+	Amount      decimal.Decimal       `json:"amount"` // Always positive
+	Currency    string                `json:"currency,omitempty"`
+	Category    string                `json:"category,omitempty"`
+	Description string                `json:"description"`
+	ExternalID  string                `json:"externalId,omitempty"` // Set for statements that identify transactions
+	Error       string                `json:"error,omitempty"`
+}
+
+// Settings control how records are turned into rows
+type Settings struct {
+	HasHeader    bool
+	DateFormat   string
+	NumberFormat string
+	Mapping      ColumnMapping
+}
+
+// ApplyMapping turns CSV records into rows using the settings. The header
+// record, when there is one, is skipped.
+func ApplyMapping(records [][]string, s Settings) []Row {
+	start := 0
+	if s.HasHeader {
+		start = 1
+	}
+
+	rows := make([]Row, 0, len(records)-start)
+	for i := start; i < len(records); i++ {
+		rows = append(rows, applyRecord(records[i], i+1, s))
+	}
+	return rows
+}
+
+func applyRecord(rec []string, line int, s Settings) Row {
+	row := Row{Line: line}
+	field := func(col *int) string {
+		if col == nil || *col >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[*col])
+	}
+
+	date, err := ParseDate(field(s.Mapping.Date), s.DateFormat)
+	if err != nil {
+		row.Error = err.Error()
+		return row
+	}
+	amount, err := ParseAmount(field(s.Mapping.Amount), s.NumberFormat)
+	if err != nil {
+		row.Error = err.Error()
+		return row
+	}
+	if amount.IsZero() {
+		row.Error = "the amount is zero"
+		return row
+	}
+
+	row.Date = date
+	row.Amount = amount.Abs()
+	row.Currency = strings.ToUpper(field(s.Mapping.Currency))
+	row.Category = field(s.Mapping.Category)
+	row.Description = field(s.Mapping.Description)
+
+	if s.Mapping.Type != nil {
+		t, err := ParseTransactionType(field(s.Mapping.Type))
+		if err != nil {
+			row.Error = err.Error()
+			return row
+		}
+		row.Type = t
+	} else if amount.IsNegative() {
+		row.Type = model.TransactionTypeExpense
+	} else {
+		row.Type = model.TransactionTypeIncome
+	}
+	return row
+}
@@ -0,0 +1,115 @@
+package importer
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Text encodings recognised by Decode
+const (
+	EncodingUTF8        = "utf-8"
+	EncodingUTF16LE     = "utf-16le"
+	EncodingUTF16BE     = "utf-16be"
+	EncodingWindows1258 = "windows-1258"
+)
+
+// Decode converts an uploaded file to UTF-8 text and reports the encoding it was
+// in. Byte order marks are removed. Files that are neither UTF-8 nor UTF-16 are
+// read as Windows-1258, the code page Excel uses for Vietnamese CSV files.
+func Decode(data []byte) (string, string) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return string(data[3:]), EncodingUTF8
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return decodeUTF16(data[2:], false), EncodingUTF16LE
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return decodeUTF16(data[2:], true), EncodingUTF16BE
+	case utf8.Valid(data):
+		return string(data), EncodingUTF8
+	default:
+		return decodeWindows1258(data), EncodingWindows1258
+	}
+}
+
+func decodeUTF16(data []byte, bigEndian bool) string {
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		if bigEndian {
+			units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+		} else {
+			units = append(units, uint16(data[i+1])<<8|uint16(data[i]))
+		}
+	}
+	return string(utf16.Decode(units))
+}
+
+// windows1258High maps bytes 0x80-0xFF of Windows-1258. Undefined bytes map to
+// U+FFFD. Bytes 0xCC, 0xD2, 0xDE, 0xEC and 0xF2 are combining tone marks.
+var windows1258High = [128]rune{
+	0x20AC, 0xFFFD, 0x201A, 0x0192, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x02C6, 0x2030, 0xFFFD, 0x2039, 0x0152, 0xFFFD, 0xFFFD, 0xFFFD,
+	0xFFFD, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0x02DC, 0x2122, 0xFFFD, 0x203A, 0x0153, 0xFFFD, 0xFFFD, 0x0178,
+	0x00A0, 0x00A1, 0x00A2, 0x00A3, 0x00A4, 0x00A5, 0x00A6, 0x00A7,
+	0x00A8, 0x00A9, 0x00AA, 0x00AB, 0x00AC, 0x00AD, 0x00AE, 0x00AF,
+	0x00B0, 0x00B1, 0x00B2, 0x00B3, 0x00B4, 0x00B5, 0x00B6, 0x00B7,
+	0x00B8, 0x00B9, 0x00BA, 0x00BB, 0x00BC, 0x00BD, 0x00BE, 0x00BF,
+	0x00C0, 0x00C1, 0x00C2, 0x0102, 0x00C4, 0x00C5, 0x00C6, 0x00C7,
+	0x00C8, 0x00C9, 0x00CA, 0x00CB, 0x0300, 0x00CD, 0x00CE, 0x00CF,
+	0x0110, 0x00D1, 0x0309, 0x00D3, 0x00D4, 0x01A0, 0x00D6, 0x00D7,
+	0x00D8, 0x00D9, 0x00DA, 0x00DB, 0x00DC, 0x01AF, 0x0303, 0x00DF,
+	0x00E0, 0x00E1, 0x00E2, 0x0103, 0x00E4, 0x00E5, 0x00E6, 0x00E7,
+	0x00E8, 0x00E9, 0x00EA, 0x00EB, 0x0301, 0x00ED, 0x00EE, 0x00EF,
+	0x0111, 0x00F1, 0x0323, 0x00F3, 0x00F4, 0x01A1, 0x00F6, 0x00F7,
+	0x00F8, 0x00F9, 0x00FA, 0x00FB, 0x00FC, 0x01B0, 0x20AB, 0x00FF,
+}
+
+// toneMarks lists the combining marks in the order used by vietnameseTones
+var toneMarks = map[rune]int{0x0300: 0, 0x0301: 1, 0x0309: 2, 0x0303: 3, 0x0323: 4}
+
+// vietnameseTones maps each vowel to its precomposed forms with a grave, acute,
+// hook above, tilde and dot below
+var vietnameseTones = map[rune][]rune{
+	'a': []rune("àáảãạ"), 'ă': []rune("ằắẳẵặ"), 'â': []rune("ầấẩẫậ"),
+	'e': []rune("èéẻẽẹ"), 'ê': []rune("ềếểễệ"), 'i': []rune("ìíỉĩị"),
+	'o': []rune("òóỏõọ"), 'ô': []rune("ồốổỗộ"), 'ơ': []rune("ờớởỡợ"),
+	'u': []rune("ùúủũụ"), 'ư': []rune("ừứửữự"), 'y': []rune("ỳýỷỹỵ"),
+	'A': []rune("ÀÁẢÃẠ"), 'Ă': []rune("ẰẮẲẴẶ"), 'Â': []rune("ẦẤẨẪẬ"),
+	'E': []rune("ÈÉẺẼẸ"), 'Ê': []rune("ỀẾỂỄỆ"), 'I': []rune("ÌÍỈĨỊ"),
+	'O': []rune("ÒÓỎÕỌ"), 'Ô': []rune("ỒỐỔỖỘ"), 'Ơ': []rune("ỜỚỞỠỢ"),
+	'U': []rune("ÙÚỦŨỤ"), 'Ư': []rune("ỪỨỬỮỰ"), 'Y': []rune("ỲÝỶỸỴ"),
+}
+
+// decodeWindows1258 converts Windows-1258 text to UTF-8. Windows-1258 writes
+// most toned vowels as a base letter followed by a combining mark; these are
+// composed into the single characters every other source uses, so that
+// descriptions compare equal.
+func decodeWindows1258(data []byte) string {
+	var b strings.Builder
+	b.Grow(len(data))
+
+	var prev rune = -1
+	for _, c := range data {
+		r := rune(c)
+		if c >= 0x80 {
+			r = windows1258High[c-0x80]
+		}
+
+		if tone, ok := toneMarks[r]; ok && prev >= 0 {
+			if forms, ok := vietnameseTones[prev]; ok {
+				prev = forms[tone]
+				continue
+			}
+		}
+		if prev >= 0 {
+			b.WriteRune(prev)
+		}
+		prev = r
+	}
+	if prev >= 0 {
+		b.WriteRune(prev)
+	}
+	return b.String()
+}
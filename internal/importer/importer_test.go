@@ -0,0 +1,183 @@
+package importer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		data         []byte
+		wantText     string
+		wantEncoding string
+	}{
+		{name: "utf-8", data: []byte("Cà phê"), wantText: "Cà phê", wantEncoding: EncodingUTF8},
+		{name: "utf-8 with BOM", data: []byte("\xEF\xBB\xBFNgày"), wantText: "Ngày", wantEncoding: EncodingUTF8},
+		{name: "utf-16le", data: []byte{0xFF, 0xFE, 'C', 0, 0xE0, 0}, wantText: "Cà", wantEncoding: EncodingUTF16LE},
+		{name: "utf-16be", data: []byte{0xFE, 0xFF, 0, 'C', 0, 0xE0}, wantText: "Cà", wantEncoding: EncodingUTF16BE},
+		// Windows-1258 writes ề as ê (0xEA) followed by a combining grave (0xCC)
+		{name: "windows-1258", data: []byte("Ti\xEA\xCCn \xF0i\xEA\xD2n 5\xFE"), wantText: "Tiền điển 5₫", wantEncoding: EncodingWindows1258},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			text, encoding := Decode(tt.data)
+			assert.Equal(t, tt.wantText, text)
+			assert.Equal(t, tt.wantEncoding, encoding)
+		})
+	}
+}
+
+func TestDetectDelimiter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		text string
+		want rune
+	}{
+		{name: "comma", text: "Date,Amount,Description\n2024-01-15,100,Lunch\n", want: ','},
+		{name: "semicolon with decimal commas", text: "Ngày;Số tiền;Nội dung\n15/01/2024;1.234,50;Ăn trưa, cà phê\n16/01/2024;200,00;Xăng\n", want: ';'},
+		{name: "tab", text: "Date\tAmount\n2024-01-15\t100\n", want: '\t'},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, DetectDelimiter(tt.text))
+		})
+	}
+}
+
+func TestDetectDateFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		values []string
+		want   string
+		ok     bool
+	}{
+		{name: "iso with time", values: []string{"2024-01-15 10:30:00", "2024-02-01"}, want: DateFormatISO, ok: true},
+		{name: "day first wins when ambiguous", values: []string{"01/02/2024", "03/04/2024"}, want: DateFormatDMYSlash, ok: true},
+		{name: "month first", values: []string{"01/02/2024", "12/31/2024"}, want: DateFormatMDYSlash, ok: true},
+		{name: "dots without leading zeros", values: []string{"5.1.2024", ""}, want: DateFormatDMYDot, ok: true},
+		{name: "unknown", values: []string{"yesterday"}},
+		{name: "empty", values: []string{""}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := DetectDateFormat(tt.values)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDetectNumberFormat(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, NumberFormatVI, DetectNumberFormat([]string{"1.234.567", "50.000"}))
+	assert.Equal(t, NumberFormatVI, DetectNumberFormat([]string{"1.234,50", "12,5"}))
+	assert.Equal(t, NumberFormatEN, DetectNumberFormat([]string{"1,234,567.89", "12.99"}))
+	assert.Equal(t, NumberFormatEN, DetectNumberFormat([]string{"-45.5", "100"}))
+}
+
+func TestParseAmount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value   string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{value: "1.234.567", format: NumberFormatVI, want: "1234567"},
+		{value: "1.234.567,5 ₫", format: NumberFormatVI, want: "1234567.5"},
+		{value: "-50.000 VND", format: NumberFormatVI, want: "-50000"},
+		{value: "50.000đ", format: NumberFormatVI, want: "50000"},
+		{value: "(1,234.56)", format: NumberFormatEN, want: "-1234.56"},
+		{value: "$ 12.99", format: NumberFormatEN, want: "12.99"},
+		{value: "200-", format: NumberFormatEN, want: "-200"},
+		{value: "+1e5", format: NumberFormatEN, wantErr: true},
+		{value: "", format: NumberFormatEN, wantErr: true},
+		{value: "abc", format: NumberFormatVI, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseAmount(tt.value, tt.format)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, decimal.RequireFromString(tt.want).Equal(got), "got %s", got)
+		})
+	}
+}
+
+func TestSuggestMapping(t *testing.T) {
+	t.Parallel()
+
+	m := SuggestMapping([]string{"Ngày giao dịch", "Số tiền", "Loại", "Danh mục", "Nội dung", "Ghi chú"})
+
+	require.NotNil(t, m.Date)
+	require.NotNil(t, m.Amount)
+	require.NotNil(t, m.Type)
+	require.NotNil(t, m.Category)
+	require.NotNil(t, m.Description)
+	assert.Equal(t, 0, *m.Date)
+	assert.Equal(t, 1, *m.Amount)
+	assert.Equal(t, 2, *m.Type)
+	assert.Equal(t, 3, *m.Category)
+	assert.Equal(t, 4, *m.Description, "the first matching column is used")
+	assert.Nil(t, m.Currency)
+	assert.NoError(t, m.Validate(6))
+	assert.ErrorIs(t, m.Validate(3), ErrInvalidMapping)
+}
+
+func TestApplyMapping(t *testing.T) {
+	t.Parallel()
+
+	records, err := ReadCSV("Ngày;Số tiền;Nội dung\n15/01/2024;-45.000;Cà phê\n16/01/2024;10.000.000;Lương\n17/01/2024;abc;Lỗi\n\n", ';')
+	require.NoError(t, err)
+	require.True(t, LooksLikeHeader(records[0]))
+	assert.False(t, LooksLikeHeader(records[1]))
+
+	rows := ApplyMapping(records, Settings{
+		HasHeader:    true,
+		DateFormat:   DateFormatDMYSlash,
+		NumberFormat: NumberFormatVI,
+		Mapping:      SuggestMapping(records[0]),
+	})
+
+	require.Len(t, rows, 3)
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), rows[0].Date)
+	assert.Equal(t, model.TransactionTypeExpense, rows[0].Type)
+	assert.True(t, decimal.NewFromInt(45000).Equal(rows[0].Amount))
+	assert.Equal(t, "Cà phê", rows[0].Description)
+	assert.Equal(t, model.TransactionTypeIncome, rows[1].Type)
+	assert.Empty(t, rows[1].Error)
+	assert.Contains(t, rows[2].Error, "not an amount")
+}
@@ -0,0 +1,613 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/scraper/pdf"
+)
+
+// Budgeting apps whose exports ParseMigration reads
+const (
+	AppMoneyLover = model.ImportSourceMoneyLover
+	AppMISA       = model.ImportSourceMISA // MISA MoneyKeeper
+)
+
+var (
+	ErrUnknownApp      = errors.New("unknown app: must be moneylover or misa")
+	ErrNotAppExport    = errors.New("the file is not an export of that app")
+	ErrNoMigrationRows = errors.New("the export has no transactions")
+)
+
+// MigrationKind is what a record of another app's export becomes
+type MigrationKind string
+
+const (
+	KindExpense    MigrationKind = "expense"
+	KindIncome     MigrationKind = "income"
+	KindTransfer   MigrationKind = "transfer"
+	KindBorrow     MigrationKind = "borrow"     // Money borrowed from someone
+	KindRepay      MigrationKind = "repay"      // Repayment of money borrowed
+	KindLend       MigrationKind = "lend"       // Money lent to someone
+	KindCollect    MigrationKind = "collect"    // Money lent that was paid back
+	KindAdjustment MigrationKind = "adjustment" // Manual correction of a wallet balance
+)
+
+// MigrationRecord is one transaction of another app's export. Records that
+// cannot be read keep their line number and the reason in Error.
+type MigrationRecord struct {
+	Line           int // 1-based row in the file or worksheet
+	Kind           MigrationKind
+	Date           time.Time
+	Amount         decimal.Decimal // Always positive
+	Currency       string          // Empty when the export does not say
+	Wallet         string          // Wallet the money left, or came into
+	ToWallet       string          // Wallet a transfer went to
+	Category       string          // WealthPath's name for well-known categories
+	ParentCategory string
+	Counterparty   string // Who money was borrowed from or lent to
+	Note           string
+	ID             string // Stays the same when the same export is imported again
+	Error          string
+}
+
+// MigrationBudget is a budget of another app's export
+type MigrationBudget struct {
+	Line     int
+	Category string
+	Amount   decimal.Decimal
+	Currency string
+	Period   string // monthly, weekly or yearly
+	Start    *time.Time
+	End      *time.Time
+	Error    string
+}
+
+// MigrationExport is what ParseMigration read from an export
+type MigrationExport struct {
+	App     string
+	Records []MigrationRecord
+	Budgets []MigrationBudget
+}
+
+// Column headers of each app's exports, in English and Vietnamese, written as
+// pdf.FoldVietnamese folds them
+var (
+	moneyLoverColumns = map[string][]string{
+		"id":       {"id", "stt"},
+		"date":     {"date", "ngay"},
+		"category": {"category", "nhom", "danh muc"},
+		"amount":   {"amount", "so tien"},
+		"currency": {"currency", "tien te", "loai tien"},
+		"note":     {"note", "ghi chu"},
+		"wallet":   {"wallet", "vi"},
+		"with":     {"with", "voi"},
+	}
+	misaColumns = map[string][]string{
+		"date":     {"date", "ngay", "ngay giao dich", "thoi gian"},
+		"type":     {"type", "transaction type", "loai giao dich", "loai thu chi", "loai"},
+		"amount":   {"amount", "so tien"},
+		"currency": {"currency", "loai tien", "tien te"},
+		"category": {"category", "hang muc", "hang muc con"},
+		"parent":   {"parent category", "hang muc cha"},
+		"wallet":   {"account", "tai khoan", "tu tai khoan", "tai khoan nguon"},
+		"to":       {"to account", "tai khoan nhan", "den tai khoan", "tai khoan dich"},
+		"note":     {"note", "description", "dien giai", "ghi chu", "mo ta"},
+		"with":     {"counterparty", "payee", "doi tuong"},
+	}
+	budgetColumns = map[string][]string{
+		"category": {"category", "hang muc", "nhom"},
+		"amount":   {"amount", "budget", "so tien", "han muc"},
+		"currency": {"currency", "loai tien", "tien te"},
+		"period":   {"period", "ky", "chu ky", "lap lai"},
+		"start":    {"start", "start date", "from", "tu ngay", "ngay bat dau"},
+		"end":      {"end", "end date", "to", "den ngay", "ngay ket thuc"},
+	}
+)
+
+// Money Lover keeps debts, loans, transfers and balance adjustments as
+// transactions in special categories
+var moneyLoverKinds = map[string]MigrationKind{
+	"debt":              KindBorrow,
+	"di vay":            KindBorrow,
+	"repayment":         KindRepay,
+	"tra no":            KindRepay,
+	"loan":              KindLend,
+	"cho vay":           KindLend,
+	"debt collection":   KindCollect,
+	"thu no":            KindCollect,
+	"adjustment":        KindAdjustment,
+	"dieu chinh so du":  KindAdjustment,
+	"transfer":          KindTransfer,
+	"outgoing transfer": KindTransfer,
+	"incoming transfer": KindTransfer,
+	"chuyen tien":       KindTransfer,
+	"chuyen tien di":    KindTransfer,
+	"chuyen tien den":   KindTransfer,
+	"nhan tien":         KindTransfer,
+}
+
+// misaKinds are the transaction types of MISA MoneyKeeper exports
+var misaKinds = map[string]MigrationKind{
+	"chi tien":         KindExpense,
+	"chi":              KindExpense,
+	"expense":          KindExpense,
+	"thu tien":         KindIncome,
+	"thu":              KindIncome,
+	"income":           KindIncome,
+	"chuyen khoan":     KindTransfer,
+	"transfer":         KindTransfer,
+	"di vay":           KindBorrow,
+	"borrow":           KindBorrow,
+	"tra no":           KindRepay,
+	"repay":            KindRepay,
+	"cho vay":          KindLend,
+	"lend":             KindLend,
+	"thu no":           KindCollect,
+	"collect debt":     KindCollect,
+	"dieu chinh so du": KindAdjustment,
+	"adjustment":       KindAdjustment,
+}
+
+// Categories lent and collected money is recorded in, as WealthPath has no
+// model for money owed to the user
+const (
+	CategoryLending        = "Lending"
+	CategoryDebtCollection = "Debt Collection"
+)
+
+// categoryNames maps the categories both apps create by default, in English
+// and Vietnamese, onto WealthPath's default categories
+var categoryNames = map[model.TransactionType]map[string]string{
+	model.TransactionTypeExpense: {
+		"an uong": "Food & Dining", "an ngoai": "Food & Dining", "nha hang": "Food & Dining", "ca phe": "Food & Dining",
+		"cafe": "Food & Dining", "di cho": "Food & Dining", "food & beverage": "Food & Dining", "food & dining": "Food & Dining",
+		"food": "Food & Dining", "restaurants": "Food & Dining",
+		"di chuyen": "Transportation", "xang xe": "Transportation", "gui xe": "Transportation", "taxi": "Transportation",
+		"transportation": "Transportation", "transport": "Transportation", "fuel": "Transportation",
+		"hoa don": "Utilities", "hoa don & tien ich": "Utilities", "tien dien": "Utilities", "tien nuoc": "Utilities",
+		"internet": "Utilities", "dien thoai": "Utilities", "bills & utilities": "Utilities", "utilities": "Utilities",
+		"nha cua": "Housing", "thue nha": "Housing", "tien nha": "Housing", "housing": "Housing", "rentals": "Housing", "home": "Housing",
+		"suc khoe": "Healthcare", "kham chua benh": "Healthcare", "thuoc": "Healthcare", "health & fitness": "Healthcare",
+		"healthcare": "Healthcare", "doctor": "Healthcare", "pharmacy": "Healthcare",
+		"bao hiem": "Insurance", "insurance": "Insurance",
+		"giai tri": "Entertainment", "vui choi": "Entertainment", "xem phim": "Entertainment", "entertainment": "Entertainment",
+		"movies": "Entertainment", "games": "Entertainment",
+		"mua sam": "Shopping", "quan ao": "Shopping", "do dung": "Shopping", "shopping": "Shopping", "clothing": "Shopping",
+		"lam dep": "Personal Care", "cham soc ca nhan": "Personal Care", "personal care": "Personal Care", "beauty": "Personal Care",
+		"giao duc": "Education", "hoc phi": "Education", "hoc tap": "Education", "sach": "Education", "education": "Education", "books": "Education",
+		"du lich": "Travel", "travel": "Travel", "vacation": "Travel",
+		"qua tang & quyen gop": "Gifts & Donations", "qua tang": "Gifts & Donations", "tu thien": "Gifts & Donations",
+		"hieu hi": "Gifts & Donations", "gifts & donations": "Gifts & Donations", "charity": "Gifts & Donations",
+		"dau tu": "Investments", "investment": "Investments", "investments": "Investments",
+		"khac": "Other", "chi phi khac": "Other", "chi tieu khac": "Other", "other": "Other", "others": "Other", "other expense": "Other",
+	},
+	model.TransactionTypeIncome: {
+		"luong": "Salary", "tien luong": "Salary", "thuong": "Salary", "salary": "Salary", "bonus": "Salary",
+		"lam them": "Freelance", "freelance": "Freelance",
+		"kinh doanh": "Business", "ban hang": "Business", "business": "Business", "selling": "Business",
+		"tien lai": "Investments", "lai tiet kiem": "Investments", "dau tu": "Investments", "interest money": "Investments",
+		"investment": "Investments", "investments": "Investments",
+		"cho thue": "Rental", "rental": "Rental", "rental income": "Rental",
+		"duoc tang": "Gifts", "qua tang": "Gifts", "gifts": "Gifts", "gift": "Gifts", "award": "Gifts",
+		"hoan tien": "Refunds", "refund": "Refunds", "refunds": "Refunds",
+		"thu nhap khac": "Other", "khac": "Other", "other": "Other", "others": "Other", "other income": "Other",
+	},
+}
+
+// budgetPeriods maps how apps write budget periods onto WealthPath's
+var budgetPeriods = map[string]string{
+	"monthly": "monthly", "month": "monthly", "hang thang": "monthly", "thang": "monthly",
+	"weekly": "weekly", "week": "weekly", "hang tuan": "weekly", "tuan": "weekly",
+	"yearly": "yearly", "year": "yearly", "hang nam": "yearly", "nam": "yearly",
+}
+
+// headerRows is how many rows above the header an export may have for a title
+const headerRows = 10
+
+// ParseMigration reads a CSV or Excel export of Money Lover or MISA
+// MoneyKeeper. Worksheets of a workbook that hold budgets are read as budgets.
+func ParseMigration(app string, data []byte) (*MigrationExport, error) {
+	var columns map[string][]string
+	var required []string
+	switch app {
+	case AppMoneyLover:
+		columns, required = moneyLoverColumns, []string{"date", "category", "amount"}
+	case AppMISA:
+		columns, required = misaColumns, []string{"date", "type", "amount"}
+	default:
+		return nil, ErrUnknownApp
+	}
+
+	sheets, err := readSheets(data)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &MigrationExport{App: app}
+	found := false
+	for _, sheet := range sheets {
+		if header, cols, ok := findHeader(sheet.Records, columns, required); ok {
+			found = true
+			table := newMigrationTable(sheet, header, cols)
+			if app == AppMoneyLover {
+				export.Records = append(export.Records, table.moneyLoverRecords()...)
+			} else {
+				export.Records = append(export.Records, table.misaRecords()...)
+			}
+			continue
+		}
+		if header, cols, ok := findHeader(sheet.Records, budgetColumns, []string{"category", "amount"}); ok {
+			export.Budgets = append(export.Budgets, newMigrationTable(sheet, header, cols).budgets()...)
+		}
+	}
+	if !found {
+		return nil, ErrNotAppExport
+	}
+	if len(export.Records) == 0 {
+		return nil, ErrNoMigrationRows
+	}
+	return export, nil
+}
+
+// readSheets reads an Excel workbook, or CSV text as a single sheet
+func readSheets(data []byte) ([]Sheet, error) {
+	if IsXLSX(data) {
+		return ReadXLSX(data)
+	}
+	text, _ := Decode(data)
+	records, err := ReadCSV(text, DetectDelimiter(text))
+	if err != nil {
+		return nil, err
+	}
+	return []Sheet{{Records: records}}, nil
+}
+
+// findHeader looks for the header row among the first rows of a sheet and
+// returns the column of each field it names
+func findHeader(records [][]string, columns map[string][]string, required []string) (int, map[string]int, bool) {
+	for i := 0; i < len(records) && i < headerRows; i++ {
+		cols := map[string]int{}
+		for col, cell := range records[i] {
+			name := pdf.FoldVietnamese(cell)
+			for field, aliases := range columns {
+				if _, ok := cols[field]; ok {
+					continue
+				}
+				for _, alias := range aliases {
+					if name == alias {
+						cols[field] = col
+						break
+					}
+				}
+			}
+		}
+		ok := true
+		for _, field := range required {
+			if _, found := cols[field]; !found {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return i, cols, true
+		}
+	}
+	return 0, nil, false
+}
+
+// migrationTable is the rows of a sheet below its header
+type migrationTable struct {
+	records      [][]string
+	numbers      [][]bool // Cells stored as numbers, for Excel sheets
+	start        int      // Index of the first row after the header
+	cols         map[string]int
+	dateFormat   string // Empty when no format fits every date
+	numberFormat string // Of the amounts stored as text
+	ids          externalIDs
+}
+
+func newMigrationTable(sheet Sheet, header int, cols map[string]int) *migrationTable {
+	t := &migrationTable{records: sheet.Records, numbers: sheet.Numbers, start: header + 1, cols: cols, ids: externalIDs{}}
+	rows := sheet.Records[t.start:]
+	for _, field := range []string{"date", "start"} {
+		if col, ok := cols[field]; ok {
+			t.dateFormat, _ = DetectDateFormat(Column(rows, col))
+		}
+	}
+	if col, ok := cols["amount"]; ok {
+		var amounts []string
+		for i := t.start; i < len(t.records); i++ {
+			if col < len(t.records[i]) && !t.isNumber(i, "amount") {
+				amounts = append(amounts, t.records[i][col])
+			}
+		}
+		t.numberFormat = DetectNumberFormat(amounts)
+	}
+	return t
+}
+
+// isNumber reports whether a field of the record at index i was stored as a
+// number rather than text
+func (t *migrationTable) isNumber(i int, name string) bool {
+	col, ok := t.cols[name]
+	return ok && i < len(t.numbers) && col < len(t.numbers[i]) && t.numbers[i][col]
+}
+
+// amount parses the amount of the record at index i: as Excel stores it for a
+// number, otherwise in the format of the column's other amounts
+func (t *migrationTable) amount(i int, rec []string) (decimal.Decimal, error) {
+	format := t.numberFormat
+	if t.isNumber(i, "amount") {
+		format = NumberFormatEN
+	}
+	return ParseAmount(t.field(rec, "amount"), format)
+}
+
+// field returns a field of a record, or an empty string when the sheet has no
+// such column
+func (t *migrationTable) field(rec []string, name string) string {
+	col, ok := t.cols[name]
+	if !ok || col >= len(rec) {
+		return ""
+	}
+	return strings.TrimSpace(rec[col])
+}
+
+// date parses a date, in the format the column's other dates are in when there
+// is one that fits them all
+func (t *migrationTable) date(value string) (time.Time, error) {
+	format := t.dateFormat
+	if format == "" {
+		var ok bool
+		if format, ok = DetectDateFormat([]string{value}); !ok {
+			return time.Time{}, fmt.Errorf("%q is not a date", value)
+		}
+	}
+	return ParseDate(value, format)
+}
+
+// rows calls fn for each row below the header that has a date, skipping
+// blank rows and the totals some exports end with
+func (t *migrationTable) rows(dateField string, fn func(line int, rec []string)) {
+	for i := t.start; i < len(t.records); i++ {
+		if t.field(t.records[i], dateField) == "" {
+			continue
+		}
+		fn(i+1, t.records[i])
+	}
+}
+
+// read fills in the fields Money Lover and MISA records have in common and
+// returns the signed amount
+func (t *migrationTable) read(line int, rec []string) (MigrationRecord, decimal.Decimal) {
+	r := MigrationRecord{
+		Line:         line,
+		Currency:     strings.ToUpper(t.field(rec, "currency")),
+		Wallet:       t.field(rec, "wallet"),
+		Counterparty: t.field(rec, "with"),
+		Note:         t.field(rec, "note"),
+	}
+	date, err := t.date(t.field(rec, "date"))
+	if err != nil {
+		r.Error = err.Error()
+		return r, decimal.Zero
+	}
+	r.Date = date
+
+	amount, err := t.amount(line-1, rec)
+	if err != nil {
+		r.Error = err.Error()
+		return r, decimal.Zero
+	}
+	if amount.IsZero() {
+		r.Error = "the amount is zero"
+		return r, decimal.Zero
+	}
+	r.Amount = amount.Abs()
+	return r, amount
+}
+
+// moneyLoverRecords reads the transactions of a Money Lover export. Amounts
+// are negative for money out. A transfer is exported as two transactions, one
+// for each wallet, which are joined back into one.
+func (t *migrationTable) moneyLoverRecords() []MigrationRecord {
+	var records []MigrationRecord
+	var outgoing, incoming []int // Indexes of the two sides of transfers
+	t.rows("date", func(line int, rec []string) {
+		r, amount := t.read(line, rec)
+		category := t.field(rec, "category")
+		if r.Error == "" {
+			kind, special := moneyLoverKinds[pdf.FoldVietnamese(category)]
+			switch {
+			case special:
+				r.Kind = kind
+			case amount.IsNegative():
+				r.Kind = KindExpense
+			default:
+				r.Kind = KindIncome
+			}
+			if r.Kind == KindTransfer {
+				if amount.IsNegative() {
+					outgoing = append(outgoing, len(records))
+				} else {
+					incoming = append(incoming, len(records))
+				}
+			}
+			r.Category = category
+		}
+		if id := t.field(rec, "id"); id != "" {
+			r.ID = AppMoneyLover + ":" + id
+		}
+		records = append(records, r)
+	})
+
+	// Join each outgoing side with the first incoming side of the same amount
+	// on the same day into another wallet
+	joined := make(map[int]bool, len(incoming))
+	for _, o := range outgoing {
+		out := &records[o]
+		for _, i := range incoming {
+			in := records[i]
+			if joined[i] || !in.Date.Equal(out.Date) || !in.Amount.Equal(out.Amount) || strings.EqualFold(in.Wallet, out.Wallet) {
+				continue
+			}
+			joined[i] = true
+			out.ToWallet = in.Wallet
+			break
+		}
+		if out.ToWallet == "" {
+			out.Error = "the other side of this transfer is not in the file"
+		}
+	}
+	for _, i := range incoming {
+		if !joined[i] {
+			records[i].Error = "the other side of this transfer is not in the file"
+		}
+	}
+
+	result := make([]MigrationRecord, 0, len(records))
+	for i, r := range records {
+		if r.Kind == KindTransfer && r.Error == "" && joined[i] {
+			continue
+		}
+		result = append(result, t.finish(AppMoneyLover, r, ""))
+	}
+	return result
+}
+
+// misaRecords reads the transactions of a MISA MoneyKeeper export, which has a
+// transaction type column and positive amounts
+func (t *migrationTable) misaRecords() []MigrationRecord {
+	var records []MigrationRecord
+	t.rows("date", func(line int, rec []string) {
+		r, _ := t.read(line, rec)
+		if r.Error == "" {
+			kind, ok := misaKinds[pdf.FoldVietnamese(t.field(rec, "type"))]
+			switch {
+			case !ok:
+				r.Error = fmt.Sprintf("unknown transaction type %q", t.field(rec, "type"))
+			case kind == KindTransfer && t.field(rec, "to") == "":
+				r.Error = "the transfer has no receiving account"
+			default:
+				r.Kind = kind
+				r.ToWallet = t.field(rec, "to")
+				r.Category = t.field(rec, "category")
+			}
+		}
+		records = append(records, t.finish(AppMISA, r, t.field(rec, "parent")))
+	})
+	return records
+}
+
+// finish maps the record's category and gives it an ID when the export does
+// not have one
+func (t *migrationTable) finish(app string, r MigrationRecord, parent string) MigrationRecord {
+	if r.Error != "" {
+		return r
+	}
+	switch r.Kind {
+	case KindExpense, KindIncome:
+		r.Category, r.ParentCategory = mapCategory(model.TransactionType(r.Kind), r.Category, parent)
+	case KindLend:
+		r.Category, r.ParentCategory = CategoryLending, ""
+	case KindCollect:
+		r.Category, r.ParentCategory = CategoryDebtCollection, ""
+	default:
+		r.Category, r.ParentCategory = "", ""
+	}
+	if r.ID == "" {
+		r.ID = t.ids.next(app, r.Date.Format("2006-01-02"), string(r.Kind), r.Amount.String(),
+			r.Wallet, r.ToWallet, r.Category, r.Counterparty, r.Note)
+	}
+	return r
+}
+
+// mapCategory returns WealthPath's name for a category and its parent. A
+// subcategory without a WealthPath equivalent is kept under the mapped parent.
+func mapCategory(t model.TransactionType, name, parent string) (string, string) {
+	if mapped, ok := categoryNames[t][pdf.FoldVietnamese(name)]; ok {
+		return mapped, ""
+	}
+	if parent != "" {
+		if mapped, ok := categoryNames[t][pdf.FoldVietnamese(parent)]; ok {
+			parent = mapped
+		}
+	}
+	if name == "" {
+		return parent, ""
+	}
+	return name, parent
+}
+
+// budgets reads a sheet of budgets
+func (t *migrationTable) budgets() []MigrationBudget {
+	var budgets []MigrationBudget
+	for i := t.start; i < len(t.records); i++ {
+		rec := t.records[i]
+		category := t.field(rec, "category")
+		if category == "" {
+			continue
+		}
+		b := MigrationBudget{Line: i + 1, Currency: strings.ToUpper(t.field(rec, "currency"))}
+		b.Category, _ = mapCategory(model.TransactionTypeExpense, category, "")
+
+		amount, err := t.amount(i, rec)
+		if err != nil || !amount.IsPositive() {
+			b.Error = fmt.Sprintf("%q is not a budget amount", t.field(rec, "amount"))
+			budgets = append(budgets, b)
+			continue
+		}
+		b.Amount = amount
+
+		for _, d := range []struct {
+			field string
+			dst   **time.Time
+		}{{"start", &b.Start}, {"end", &b.End}} {
+			value := t.field(rec, d.field)
+			if value == "" {
+				continue
+			}
+			date, err := t.date(value)
+			if err != nil {
+				b.Error = err.Error()
+				break
+			}
+			*d.dst = &date
+		}
+
+		period := t.field(rec, "period")
+		switch {
+		case b.Error != "":
+		case period != "":
+			if b.Period = budgetPeriods[pdf.FoldVietnamese(period)]; b.Period == "" {
+				b.Error = fmt.Sprintf("unknown budget period %q", period)
+			}
+		default:
+			b.Period = inferPeriod(b.Start, b.End)
+		}
+		budgets = append(budgets, b)
+	}
+	return budgets
+}
+
+// inferPeriod guesses the period of a budget from its dates. Budgets without
+// dates are monthly, the default in both apps.
+func inferPeriod(start, end *time.Time) string {
+	if start == nil || end == nil {
+		return "monthly"
+	}
+	switch days := end.Sub(*start).Hours() / 24; {
+	case days <= 7:
+		return "weekly"
+	case days >= 364:
+		return "yearly"
+	default:
+		return "monthly"
+	}
+}
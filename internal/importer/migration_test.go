@@ -0,0 +1,190 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wantRecord is the expected outcome of one migration record
+type wantRecord struct {
+	line         int
+	kind         MigrationKind
+	date         string
+	amount       string
+	wallet       string
+	toWallet     string
+	category     string
+	parent       string
+	counterparty string
+	err          string // Substring of the error, for records that cannot be read
+}
+
+func assertRecords(t *testing.T, want []wantRecord, got []MigrationRecord) {
+	t.Helper()
+	require.Len(t, got, len(want))
+	for i, w := range want {
+		r := got[i]
+		assert.Equal(t, w.line, r.Line, "record %d", i)
+		if w.err != "" {
+			assert.Contains(t, r.Error, w.err, "line %d", w.line)
+			continue
+		}
+		assert.Empty(t, r.Error, "line %d", w.line)
+		assert.Equal(t, w.kind, r.Kind, "line %d", w.line)
+		assert.Equal(t, w.date, r.Date.Format("2006-01-02"), "line %d", w.line)
+		assert.Equal(t, w.amount, r.Amount.String(), "line %d", w.line)
+		assert.Equal(t, w.wallet, r.Wallet, "line %d", w.line)
+		assert.Equal(t, w.toWallet, r.ToWallet, "line %d", w.line)
+		assert.Equal(t, w.category, r.Category, "line %d", w.line)
+		assert.Equal(t, w.parent, r.ParentCategory, "line %d", w.line)
+		assert.Equal(t, w.counterparty, r.Counterparty, "line %d", w.line)
+		assert.NotEmpty(t, r.ID, "line %d", w.line)
+	}
+}
+
+func TestParseMigration_MoneyLover(t *testing.T) {
+	t.Parallel()
+
+	export, err := ParseMigration(AppMoneyLover, []byte(readFixture(t, "moneylover.csv")))
+	require.NoError(t, err)
+
+	assert.Equal(t, AppMoneyLover, export.App)
+	assert.Empty(t, export.Budgets)
+	assertRecords(t, []wantRecord{
+		{line: 2, kind: KindExpense, date: "2024-03-01", amount: "50000", wallet: "Tiền mặt", category: "Food & Dining"},
+		{line: 3, kind: KindIncome, date: "2024-03-02", amount: "15000000", wallet: "Vietcombank", category: "Salary"},
+		// Both sides of the transfer become one record
+		{line: 4, kind: KindTransfer, date: "2024-03-03", amount: "2000000", wallet: "Vietcombank", toWallet: "Tiền mặt"},
+		{line: 6, kind: KindBorrow, date: "2024-03-05", amount: "5000000", wallet: "Vietcombank", counterparty: "Anh Tuan"},
+		{line: 7, kind: KindRepay, date: "2024-03-20", amount: "1000000", wallet: "Vietcombank", counterparty: "Anh Tuan"},
+		{line: 8, kind: KindLend, date: "2024-03-21", amount: "300000", wallet: "Tiền mặt", category: CategoryLending, counterparty: "Lan"},
+		{line: 9, kind: KindAdjustment, date: "2024-03-22", amount: "120000", wallet: "Tiền mặt"},
+		{line: 10, kind: KindExpense, date: "2024-03-23", amount: "45000", wallet: "Tiền mặt", category: "Food & Dining"},
+		// Categories without a WealthPath equivalent keep their name
+		{line: 11, kind: KindExpense, date: "2024-03-23", amount: "500000", wallet: "Tiền mặt", category: "Quà cưới"},
+		{line: 12, err: "other side of this transfer"},
+		{line: 13, err: "not a date"},
+	}, export.Records)
+	assert.Equal(t, "moneylover:1", export.Records[0].ID)
+}
+
+func TestParseMigration_MISA(t *testing.T) {
+	t.Parallel()
+
+	header := []any{"Ngày", "Loại giao dịch", "Số tiền", "Loại tiền", "Hạng mục", "Hạng mục cha", "Tài khoản", "Tài khoản nhận", "Diễn giải", "Đối tượng"}
+	data := buildXLSX(t,
+		testSheet{name: "Thu chi", rows: [][]any{
+			{"BÁO CÁO THU CHI"},
+			header,
+			{xlsxDate(45352), "Chi tiền", 50000, "VND", "Ăn sáng", "Ăn uống", "Ví tiền mặt", "", "Bún chả"},
+			{xlsxDate(45352), "Thu tiền", 15000000, "VND", "Lương", "", "Vietcombank"},
+			{xlsxDate(45353), "Chuyển khoản", 1000000, "VND", "", "", "Vietcombank", "Ví tiền mặt", "Rút tiền"},
+			{xlsxDate(45354), "Đi vay", 3000000, "VND", "", "", "Vietcombank", "", "", "Chị Hoa"},
+			{xlsxDate(45360), "Trả nợ", 1000000, "VND", "", "", "Vietcombank", "", "", "Chị Hoa"},
+			{xlsxDate(45361), "Thu nợ", 200000, "VND", "", "", "Ví tiền mặt", "", "", "Minh"},
+			{xlsxDate(45362), "Hoàn ứng", 5000, "VND", "", "", "Ví tiền mặt"},
+			{xlsxDate(45362), "Chuyển khoản", 5000, "VND", "", "", "Vietcombank"},
+			{"", "Tổng cộng", 20255000},
+		}},
+		testSheet{name: "Ngân sách", rows: [][]any{
+			{"Hạng mục", "Số tiền", "Từ ngày", "Đến ngày"},
+			{"Ăn uống", 3000000, xlsxDate(45352), xlsxDate(45382)},
+			{"Du lịch", 12000000, xlsxDate(45292), xlsxDate(45657)},
+			{"Mua sắm", "nhiều"},
+		}},
+	)
+
+	export, err := ParseMigration(AppMISA, data)
+	require.NoError(t, err)
+
+	assertRecords(t, []wantRecord{
+		// A subcategory without a WealthPath equivalent stays under the mapped parent
+		{line: 3, kind: KindExpense, date: "2024-03-01", amount: "50000", wallet: "Ví tiền mặt", category: "Ăn sáng", parent: "Food & Dining"},
+		{line: 4, kind: KindIncome, date: "2024-03-01", amount: "15000000", wallet: "Vietcombank", category: "Salary"},
+		{line: 5, kind: KindTransfer, date: "2024-03-02", amount: "1000000", wallet: "Vietcombank", toWallet: "Ví tiền mặt"},
+		{line: 6, kind: KindBorrow, date: "2024-03-03", amount: "3000000", wallet: "Vietcombank", counterparty: "Chị Hoa"},
+		{line: 7, kind: KindRepay, date: "2024-03-09", amount: "1000000", wallet: "Vietcombank", counterparty: "Chị Hoa"},
+		{line: 8, kind: KindCollect, date: "2024-03-10", amount: "200000", wallet: "Ví tiền mặt", category: CategoryDebtCollection, counterparty: "Minh"},
+		{line: 9, err: "unknown transaction type"},
+		{line: 10, err: "no receiving account"},
+	}, export.Records)
+
+	require.Len(t, export.Budgets, 3)
+	assert.Equal(t, "Food & Dining", export.Budgets[0].Category)
+	assert.Equal(t, "3000000", export.Budgets[0].Amount.String())
+	assert.Equal(t, "monthly", export.Budgets[0].Period)
+	require.NotNil(t, export.Budgets[0].Start)
+	assert.Equal(t, "2024-03-01", export.Budgets[0].Start.Format("2006-01-02"))
+	assert.Equal(t, "Travel", export.Budgets[1].Category)
+	assert.Equal(t, "yearly", export.Budgets[1].Period)
+	assert.Contains(t, export.Budgets[2].Error, "not a budget amount")
+}
+
+func TestParseMigration_ExcelNumbers(t *testing.T) {
+	t.Parallel()
+
+	// Excel stores numbers with a decimal point, which would otherwise read as
+	// Vietnamese thousands; amounts typed as text are still detected
+	data := buildXLSX(t, testSheet{name: "Transactions", rows: [][]any{
+		{"Date", "Category", "Amount", "Currency", "Wallet"},
+		{xlsxDate(45352), "Food & Beverage", -12.345, "USD", "Card"},
+		{xlsxDate(45353), "Salary", 1500.5, "USD", "Card"},
+		{xlsxDate(45354), "Food & Beverage", "-1.500.000", "VND", "Tiền mặt"},
+	}})
+
+	export, err := ParseMigration(AppMoneyLover, data)
+	require.NoError(t, err)
+
+	assertRecords(t, []wantRecord{
+		{line: 2, kind: KindExpense, date: "2024-03-01", amount: "12.345", wallet: "Card", category: "Food & Dining"},
+		{line: 3, kind: KindIncome, date: "2024-03-02", amount: "1500.5", wallet: "Card", category: "Salary"},
+		{line: 4, kind: KindExpense, date: "2024-03-03", amount: "1500000", wallet: "Tiền mặt", category: "Food & Dining"},
+	}, export.Records)
+}
+
+func TestParseMigration_StableIDs(t *testing.T) {
+	t.Parallel()
+
+	// Without an ID column, IDs come from the records' fields
+	csv := "Ngày,Loại giao dịch,Số tiền,Tài khoản,Hạng mục\n" +
+		"01/03/2024,Chi tiền,50000,Ví,Ăn uống\n" +
+		"01/03/2024,Chi tiền,50000,Ví,Ăn uống\n"
+
+	first, err := ParseMigration(AppMISA, []byte(csv))
+	require.NoError(t, err)
+	second, err := ParseMigration(AppMISA, []byte(csv))
+	require.NoError(t, err)
+
+	require.Len(t, first.Records, 2)
+	assert.Equal(t, first.Records[0].ID, second.Records[0].ID)
+	assert.Equal(t, first.Records[1].ID, second.Records[1].ID)
+	assert.NotEqual(t, first.Records[0].ID, first.Records[1].ID)
+}
+
+func TestParseMigration_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		app     string
+		data    string
+		wantErr error
+	}{
+		{name: "unknown app", app: "mint", data: "Date,Amount\n", wantErr: ErrUnknownApp},
+		{name: "export of another app", app: AppMISA, data: readFixture(t, "moneylover.csv"), wantErr: ErrNotAppExport},
+		{name: "header only", app: AppMoneyLover, data: "Id,Date,Category,Amount\n", wantErr: ErrNoMigrationRows},
+		{name: "empty file", app: AppMoneyLover, data: "", wantErr: ErrEmptyFile},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseMigration(tt.app, []byte(tt.data))
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
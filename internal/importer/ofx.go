@@ -0,0 +1,154 @@
+package importer
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/wealthpath/backend/internal/model"
+)
+
+// ofxElement is a start tag, an end tag or the text of a leaf element
+type ofxElement struct {
+	tag   string // Upper case, without brackets; end tags start with "/"
+	value string // Text up to the next tag, for leaf elements
+}
+
+// ParseOFX parses bank and credit card statements in OFX 1.x, which is SGML and
+// leaves leaf elements unclosed, or OFX 2.x, which is XML. QFX files are OFX
+// with Quicken's extra elements. Each transaction's ExternalID is built from the
+// account and its FITID, which banks keep stable across downloads.
+func ParseOFX(text string) (*Statement, error) {
+	start := strings.Index(strings.ToUpper(text), "<OFX>")
+	if start < 0 {
+		return nil, ErrUnknownStatementFormat
+	}
+
+	// A file can hold statements for several accounts, so each transaction
+	// remembers the account and currency of the statement it is in
+	statement := &Statement{}
+	var account, currency string
+	var txn map[string]string
+	var txns []map[string]string
+	for _, el := range tokenizeOFX(text[start:]) {
+		switch {
+		case el.tag == "STMTTRN":
+			txn = map[string]string{"ACCTID": account, "CURDEF": currency}
+		case el.tag == "/STMTTRN":
+			if txn != nil {
+				txns = append(txns, txn)
+			}
+			txn = nil
+		case txn != nil:
+			// Transfers name the other account in a nested ACCTID; keep the statement's
+			if el.value != "" && el.tag != "ACCTID" && el.tag != "CURDEF" {
+				txn[el.tag] = el.value
+			}
+		case el.tag == "CURDEF":
+			currency = strings.ToUpper(el.value)
+			if statement.Currency == "" {
+				statement.Currency = currency
+			}
+		case el.tag == "ACCTID":
+			account = el.value
+			if statement.Account == "" {
+				statement.Account = account
+			}
+		}
+	}
+	if len(txns) == 0 {
+		return nil, ErrNoTransactions
+	}
+
+	for i, fields := range txns {
+		tx, err := ofxTransaction(fields)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i+1, err)
+		}
+		statement.Transactions = append(statement.Transactions, tx)
+	}
+	return statement, nil
+}
+
+// tokenizeOFX splits OFX into elements. Text is unescaped and trimmed; the
+// SGML header before <OFX> must already be removed.
+func tokenizeOFX(text string) []ofxElement {
+	var elements []ofxElement
+	for {
+		open := strings.IndexByte(text, '<')
+		if open < 0 {
+			return elements
+		}
+		end := strings.IndexByte(text[open:], '>')
+		if end < 0 {
+			return elements
+		}
+		tag := strings.ToUpper(strings.TrimSpace(text[open+1 : open+end]))
+		text = text[open+end+1:]
+
+		value := text
+		if next := strings.IndexByte(text, '<'); next >= 0 {
+			value = text[:next]
+		}
+		if strings.HasPrefix(tag, "?") || strings.HasPrefix(tag, "!") {
+			continue
+		}
+		elements = append(elements, ofxElement{tag: tag, value: strings.TrimSpace(html.UnescapeString(value))})
+	}
+}
+
+func ofxTransaction(fields map[string]string) (model.Transaction, error) {
+	fitID := fields["FITID"]
+	if fitID == "" {
+		return model.Transaction{}, fmt.Errorf("FITID is missing")
+	}
+
+	date, err := parseOFXDate(fields["DTPOSTED"])
+	if err != nil {
+		return model.Transaction{}, err
+	}
+
+	// The spec uses a decimal point, but some banks write a decimal comma
+	raw := fields["TRNAMT"]
+	if !strings.Contains(raw, ".") {
+		raw = strings.Replace(raw, ",", ".", 1)
+	}
+	amount, err := ParseAmount(raw, NumberFormatEN)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+
+	// CREDIT and DEBIT say which way the money went. Other types, such as XFER,
+	// CHECK or PAYMENT, can go either way, so the amount's sign decides.
+	txType := model.TransactionTypeIncome
+	trnType := strings.ToUpper(fields["TRNTYPE"])
+	if trnType == "DEBIT" || (trnType != "CREDIT" && amount.IsNegative()) {
+		txType = model.TransactionTypeExpense
+	}
+
+	externalID := "ofx:" + fields["ACCTID"] + ":" + fitID
+
+	return model.Transaction{
+		Type:        txType,
+		Amount:      amount.Abs(),
+		Currency:    fields["CURDEF"],
+		Description: describe(fields["NAME"], fields["MEMO"]),
+		Date:        date,
+		ExternalID:  &externalID,
+	}, nil
+}
+
+// parseOFXDate reads the date of an OFX datetime such as 20240315,
+// 20240315120000 or 20240315120000.000[-7:MST]. The time and zone are dropped:
+// the date is the one the bank printed.
+func parseOFXDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("%q is not an OFX date", value)
+	}
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not an OFX date", value)
+	}
+	return date, nil
+}
@@ -0,0 +1,209 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wealthpath/backend/internal/model"
+)
+
+// qifDateLayouts are tried in order until one fits every date in the file.
+// Quicken writes month-first dates, so those come before day-first ones.
+var qifDateLayouts = []string{"1/2/2006", "1/2/06", "2006-1-2", "2006/1/2", "2/1/2006", "2/1/06", "2.1.2006", "2.1.06"}
+
+// qifTransactionTypes are the !Type sections that hold cash account transactions.
+// Investment, category, class and memorized transaction lists are skipped.
+var qifTransactionTypes = map[string]bool{"bank": true, "cash": true, "ccard": true, "oth a": true, "oth l": true}
+
+// qifRecord holds the fields of one QIF transaction
+type qifRecord struct {
+	account  string
+	date     string
+	amount   string
+	payee    string
+	memo     string
+	category string
+	number   string
+}
+
+// ParseQIF parses the bank, cash and credit card transactions of a QIF file.
+// QIF has no transaction IDs, so each ExternalID is a hash of the
+// transaction's fields; identical transactions on the same day are told apart
+// by their order in the file.
+func ParseQIF(text string) (*Statement, error) {
+	records, account, err := readQIF(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrNoTransactions
+	}
+
+	dates := make([]string, len(records))
+	amounts := make([]string, len(records))
+	for i, rec := range records {
+		dates[i], amounts[i] = rec.date, rec.amount
+	}
+	layout, ok := qifDateLayout(dates)
+	if !ok {
+		return nil, fmt.Errorf("%w: the dates are in an unknown format", ErrMalformedFile)
+	}
+	numberFormat := DetectNumberFormat(amounts)
+
+	statement := &Statement{Account: account}
+	ids := externalIDs{}
+	for i, rec := range records {
+		date, _ := time.Parse(layout, normalizeQIFDate(rec.date))
+		amount, err := ParseAmount(rec.amount, numberFormat)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i+1, err)
+		}
+
+		txType := model.TransactionTypeIncome
+		if amount.IsNegative() {
+			txType = model.TransactionTypeExpense
+		}
+
+		externalID := ids.next("qif:"+rec.account,
+			date.Format("2006-01-02"), amount.String(), rec.payee, rec.memo, rec.number)
+
+		statement.Transactions = append(statement.Transactions, model.Transaction{
+			Type:        txType,
+			Amount:      amount.Abs(),
+			Category:    qifCategory(rec.category),
+			Description: describe(rec.payee, rec.memo),
+			Date:        date,
+			ExternalID:  &externalID,
+		})
+	}
+	return statement, nil
+}
+
+// readQIF splits a QIF file into transaction records. It also returns the name
+// of the first account the file declares, if any.
+func readQIF(text string) ([]qifRecord, string, error) {
+	var (
+		records      []qifRecord
+		firstAccount string
+		account      string
+		inAccount    bool // Inside an !Account block, which describes the account
+		inTxns       bool
+		rec          qifRecord
+		hasFields    bool
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if line[0] == '!' {
+			header := strings.ToLower(strings.TrimSpace(line))
+			switch {
+			case header == "!account":
+				inAccount, inTxns = true, false
+			case strings.HasPrefix(header, "!type:"):
+				inAccount = false
+				inTxns = qifTransactionTypes[strings.TrimSpace(strings.TrimPrefix(header, "!type:"))]
+			}
+			continue
+		}
+
+		code, value := line[0], strings.TrimSpace(line[1:])
+		if code == '^' {
+			if inTxns && hasFields {
+				rec.account = account
+				records = append(records, rec)
+			}
+			rec, hasFields, inAccount = qifRecord{}, false, false
+			continue
+		}
+		if inAccount {
+			if code == 'N' {
+				account = value
+				if firstAccount == "" {
+					firstAccount = value
+				}
+			}
+			continue
+		}
+		if !inTxns {
+			continue
+		}
+
+		hasFields = true
+		switch code {
+		case 'D':
+			rec.date = value
+		case 'T', 'U':
+			rec.amount = value
+		case 'P':
+			rec.payee = value
+		case 'M':
+			rec.memo = value
+		case 'L':
+			rec.category = value
+		case 'N':
+			rec.number = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrMalformedFile, err)
+	}
+	// The last record may be missing its closing caret
+	if inTxns && hasFields {
+		rec.account = account
+		records = append(records, rec)
+	}
+
+	for i, rec := range records {
+		if rec.date == "" || rec.amount == "" {
+			return nil, "", fmt.Errorf("%w: transaction %d has no date or amount", ErrMalformedFile, i+1)
+		}
+	}
+	return records, firstAccount, nil
+}
+
+// normalizeQIFDate rewrites Quicken's 3/15'24 and padded 3/ 5/24 dates with
+// plain slashes
+func normalizeQIFDate(value string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(value, " ", ""), "'", "/")
+}
+
+// qifDateLayout returns the first layout every date parses with
+func qifDateLayout(dates []string) (string, bool) {
+	for _, layout := range qifDateLayouts {
+		ok := true
+		for _, d := range dates {
+			if _, err := time.Parse(layout, normalizeQIFDate(d)); err != nil {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return layout, true
+		}
+	}
+	return "", false
+}
+
+// qifCategory turns a QIF category into a category name. "Food:Groceries"
+// becomes the subcategory "Groceries", a class after a slash is dropped and
+// transfers, written as [Account], have no category.
+func qifCategory(value string) string {
+	if i := strings.IndexByte(value, '/'); i >= 0 {
+		value = value[:i]
+	}
+	if strings.HasPrefix(value, "[") {
+		return ""
+	}
+	if i := strings.LastIndexByte(value, ':'); i >= 0 {
+		value = value[i+1:]
+	}
+	return strings.TrimSpace(value)
+}
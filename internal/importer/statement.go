@@ -0,0 +1,116 @@
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/wealthpath/backend/internal/model"
+)
+
+// Statement file formats recognised by DetectStatementFormat
+const (
+	FormatOFX = "ofx" // OFX 1.x (SGML) and 2.x (XML), including Quicken's QFX
+	FormatQIF = "qif"
+)
+
+// EncodingBase64 is the encoding recorded for binary statements, such as PDFs,
+// which are stored as base64 text
+const EncodingBase64 = "base64"
+
+var (
+	ErrUnknownStatementFormat = errors.New("the file is not an OFX, QFX or QIF statement")
+	ErrNoTransactions         = errors.New("the statement has no transactions")
+)
+
+// Statement is the result of parsing a statement file. Transactions are not
+// yet owned by a user; each has an ExternalID that stays the same when the
+// same statement, or an overlapping one, is imported again.
+type Statement struct {
+	Currency     string // Empty when the file does not say
+	Account      string // Account number or name, when the file has one
+	Transactions []model.Transaction
+}
+
+// Rows returns the statement's transactions as rows numbered from one
+func (s *Statement) Rows() []Row {
+	rows := make([]Row, len(s.Transactions))
+	for i, tx := range s.Transactions {
+		rows[i] = Row{
+			Line:        i + 1,
+			Date:        tx.Date,
+			Type:        tx.Type,
+			Amount:      tx.Amount,
+			Currency:    tx.Currency,
+			Category:    tx.Category,
+			Description: tx.Description,
+			ExternalID:  derefString(tx.ExternalID),
+		}
+	}
+	return rows
+}
+
+// DetectStatementFormat tells OFX and QIF files apart by their contents
+func DetectStatementFormat(text string) (string, error) {
+	head := strings.ToUpper(text[:min(len(text), 4096)])
+	switch {
+	case strings.Contains(head, "<OFX>") || strings.Contains(head, "OFXHEADER"):
+		return FormatOFX, nil
+	// QIF files start with a !Type header, possibly after !Option or !Account lines
+	case strings.HasPrefix(strings.TrimSpace(head), "!") && strings.Contains(head, "!TYPE:"):
+		return FormatQIF, nil
+	default:
+		return "", ErrUnknownStatementFormat
+	}
+}
+
+// ParseStatement parses an OFX or QIF statement in the given format
+func ParseStatement(text, format string) (*Statement, error) {
+	switch format {
+	case FormatOFX:
+		return ParseOFX(text)
+	case FormatQIF:
+		return ParseQIF(text)
+	default:
+		return nil, ErrUnknownStatementFormat
+	}
+}
+
+// describe joins a payee and memo into a transaction description, leaving out
+// a memo that only repeats the payee
+func describe(payee, memo string) string {
+	payee, memo = strings.TrimSpace(payee), strings.TrimSpace(memo)
+	switch {
+	case payee == "":
+		return memo
+	case memo == "" || strings.EqualFold(payee, memo):
+		return payee
+	default:
+		return payee + " - " + memo
+	}
+}
+
+// externalIDs builds IDs for transactions that come without one from a hash
+// of their fields. Identical transactions are told apart by their order in
+// the file, so a re-import of the same file produces the same IDs.
+type externalIDs map[string]int
+
+// next returns the ID of the next transaction with the given fields
+func (ids externalIDs) next(prefix string, fields ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x1f")))
+	id := prefix + ":" + hex.EncodeToString(sum[:12])
+	ids[id]++
+	if n := ids[id]; n > 1 {
+		id += "#" + strconv.Itoa(n)
+	}
+	return id
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,173 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	text, _ := Decode(data)
+	return text
+}
+
+type wantTxn struct {
+	date        string
+	txType      model.TransactionType
+	amount      string
+	category    string
+	description string
+	externalID  string
+}
+
+func assertTransactions(t *testing.T, want []wantTxn, got []model.Transaction) {
+	t.Helper()
+	require.Len(t, got, len(want))
+	for i, w := range want {
+		tx := got[i]
+		assert.Equal(t, w.date, tx.Date.Format(time.DateOnly), "date of transaction %d", i+1)
+		assert.Equal(t, w.txType, tx.Type, "type of transaction %d", i+1)
+		assert.True(t, decimal.RequireFromString(w.amount).Equal(tx.Amount), "amount of transaction %d: %s", i+1, tx.Amount)
+		assert.Equal(t, w.category, tx.Category, "category of transaction %d", i+1)
+		assert.Equal(t, w.description, tx.Description, "description of transaction %d", i+1)
+		if w.externalID != "" && assert.NotNil(t, tx.ExternalID) {
+			assert.Equal(t, w.externalID, *tx.ExternalID)
+		}
+	}
+}
+
+func TestParseOFX(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		fixture      string
+		wantCurrency string
+		wantAccount  string
+		want         []wantTxn
+	}{
+		{
+			name:         "OFX 1.x SGML checking statement",
+			fixture:      "checking.ofx",
+			wantCurrency: "USD",
+			wantAccount:  "1234567890",
+			want: []wantTxn{
+				{date: "2024-03-05", txType: model.TransactionTypeExpense, amount: "45.67", description: "AT&T WIRELESS - Monthly bill", externalID: "ofx:1234567890:202403050001"},
+				{date: "2024-03-15", txType: model.TransactionTypeIncome, amount: "2500", description: "ACME CORP PAYROLL", externalID: "ofx:1234567890:202403150002"},
+				// The transfer's destination account must not replace the statement's
+				{date: "2024-03-20", txType: model.TransactionTypeExpense, amount: "100", description: "Transfer to savings", externalID: "ofx:1234567890:202403200003"},
+			},
+		},
+		{
+			name:         "OFX 2.x XML credit card statement",
+			fixture:      "creditcard.qfx",
+			wantCurrency: "VND",
+			wantAccount:  "4111XXXXXXXX1111",
+			want: []wantTxn{
+				{date: "2024-03-10", txType: model.TransactionTypeExpense, amount: "1250000", description: "Highlands Coffee - Cà phê sữa đá", externalID: "ofx:4111XXXXXXXX1111:CC-0310-01"},
+				{date: "2024-03-25", txType: model.TransactionTypeIncome, amount: "500000.50", description: "Hoàn tiền", externalID: "ofx:4111XXXXXXXX1111:CC-0325-01"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			text := readFixture(t, tt.fixture)
+			format, err := DetectStatementFormat(text)
+			require.NoError(t, err)
+			assert.Equal(t, FormatOFX, format)
+
+			statement, err := ParseOFX(text)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCurrency, statement.Currency)
+			assert.Equal(t, tt.wantAccount, statement.Account)
+			assertTransactions(t, tt.want, statement.Transactions)
+			for _, tx := range statement.Transactions {
+				assert.Equal(t, tt.wantCurrency, tx.Currency)
+			}
+		})
+	}
+}
+
+func TestParseOFX_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseOFX("<OFX><BANKTRANLIST></BANKTRANLIST></OFX>")
+	assert.ErrorIs(t, err, ErrNoTransactions)
+
+	_, err = ParseOFX("<OFX><STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20240301<TRNAMT>-5</STMTTRN></OFX>")
+	assert.ErrorContains(t, err, "FITID")
+
+	_, err = ParseOFX("Date,Amount\n")
+	assert.ErrorIs(t, err, ErrUnknownStatementFormat)
+}
+
+func TestParseQIF(t *testing.T) {
+	t.Parallel()
+
+	text := readFixture(t, "bank.qif")
+	format, err := DetectStatementFormat(text)
+	require.NoError(t, err)
+	assert.Equal(t, FormatQIF, format)
+
+	statement, err := ParseQIF(text)
+	require.NoError(t, err)
+	assert.Equal(t, "Everyday Checking", statement.Account)
+	assert.Empty(t, statement.Currency)
+
+	assertTransactions(t, []wantTxn{
+		{date: "2024-03-05", txType: model.TransactionTypeExpense, amount: "1234.56", category: "Groceries", description: "Whole Foods - Weekly shop"},
+		{date: "2024-03-15", txType: model.TransactionTypeIncome, amount: "2500", category: "Salary", description: "ACME Corp"},
+		{date: "2024-03-20", txType: model.TransactionTypeExpense, amount: "100", description: "Transfer"},
+		{date: "2024-03-21", txType: model.TransactionTypeExpense, amount: "4.50", description: "Coffee"},
+		{date: "2024-03-21", txType: model.TransactionTypeExpense, amount: "4.50", description: "Coffee"},
+	}, statement.Transactions)
+
+	// Identical transactions get different IDs, and parsing again gives the same ones
+	ids := map[string]bool{}
+	for _, tx := range statement.Transactions {
+		require.NotNil(t, tx.ExternalID)
+		assert.Contains(t, *tx.ExternalID, "qif:Everyday Checking:")
+		ids[*tx.ExternalID] = true
+	}
+	assert.Len(t, ids, 5)
+
+	again, err := ParseQIF(text)
+	require.NoError(t, err)
+	for i, tx := range again.Transactions {
+		assert.Equal(t, *statement.Transactions[i].ExternalID, *tx.ExternalID)
+	}
+}
+
+func TestParseQIF_DayFirstDates(t *testing.T) {
+	t.Parallel()
+
+	statement, err := ParseQIF("!Type:Cash\nD25/03/2024\nT-50.000\nPPhở\n^\nD01/04/2024\nT-30.000\n^\n")
+	require.NoError(t, err)
+	assertTransactions(t, []wantTxn{
+		{date: "2024-03-25", txType: model.TransactionTypeExpense, amount: "50000", description: "Phở"},
+		{date: "2024-04-01", txType: model.TransactionTypeExpense, amount: "30000"},
+	}, statement.Transactions)
+}
+
+func TestParseQIF_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseQIF("!Type:Invst\nD3/1'24\nNBuy\nT100\n^\n")
+	assert.ErrorIs(t, err, ErrNoTransactions)
+
+	_, err = ParseQIF("!Type:Bank\nPNo date\nT-5\n^\n")
+	assert.ErrorIs(t, err, ErrMalformedFile)
+}
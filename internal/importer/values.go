@@ -0,0 +1,223 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/wealthpath/backend/internal/model"
+)
+
+// Date formats a file can use. Day and month may be written with or without a
+// leading zero, and anything after the date, such as a time, is ignored.
+const (
+	DateFormatISO      = "YYYY-MM-DD"
+	DateFormatDMYSlash = "DD/MM/YYYY"
+	DateFormatMDYSlash = "MM/DD/YYYY"
+	DateFormatDMYDash  = "DD-MM-YYYY"
+	DateFormatDMYDot   = "DD.MM.YYYY"
+	DateFormatYMDSlash = "YYYY/MM/DD"
+	DateFormatDMYShort = "DD/MM/YY"
+)
+
+// dateFormats lists the supported formats in detection order. Day-first formats
+// come before month-first ones because that is how dates are written in Vietnam.
+var dateFormats = []struct {
+	name   string
+	layout string
+}{
+	{DateFormatISO, "2006-1-2"},
+	{DateFormatDMYSlash, "2/1/2006"},
+	{DateFormatMDYSlash, "1/2/2006"},
+	{DateFormatDMYDash, "2-1-2006"},
+	{DateFormatDMYDot, "2.1.2006"},
+	{DateFormatYMDSlash, "2006/1/2"},
+	{DateFormatDMYShort, "2/1/06"},
+}
+
+// Number formats. Vietnamese files group thousands with dots and use a decimal
+// comma (1.234.567,5); English ones do the opposite (1,234,567.5).
+const (
+	NumberFormatVI = "vi"
+	NumberFormatEN = "en"
+)
+
+var (
+	ErrUnknownDateFormat   = errors.New("unknown date format")
+	ErrUnknownNumberFormat = errors.New("unknown number format")
+)
+
+// IsDateFormat reports whether format is one of the supported date formats
+func IsDateFormat(format string) bool {
+	_, ok := dateLayout(format)
+	return ok
+}
+
+// IsNumberFormat reports whether format is one of the supported number formats
+func IsNumberFormat(format string) bool {
+	return format == NumberFormatVI || format == NumberFormatEN
+}
+
+func dateLayout(format string) (string, bool) {
+	for _, f := range dateFormats {
+		if f.name == format {
+			return f.layout, true
+		}
+	}
+	return "", false
+}
+
+// ParseDate parses a date written in the given format
+func ParseDate(value, format string) (time.Time, error) {
+	layout, ok := dateLayout(format)
+	if !ok {
+		return time.Time{}, ErrUnknownDateFormat
+	}
+	datePart := strings.TrimSpace(value)
+	if i := strings.IndexAny(datePart, " T"); i > 0 {
+		datePart = datePart[:i]
+	}
+	t, err := time.Parse(layout, datePart)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a %s date", value, format)
+	}
+	return t, nil
+}
+
+// DetectDateFormat returns the first format every non-empty value parses with.
+// It returns false when there are no values or no format fits them all.
+func DetectDateFormat(values []string) (string, bool) {
+	for _, f := range dateFormats {
+		matched := 0
+		for _, v := range values {
+			if strings.TrimSpace(v) == "" {
+				continue
+			}
+			if _, err := ParseDate(v, f.name); err != nil {
+				matched = -1
+				break
+			}
+			matched++
+		}
+		if matched > 0 {
+			return f.name, true
+		}
+	}
+	return "", false
+}
+
+var (
+	// amountNoise matches currency codes and symbols written around amounts
+	amountNoise = regexp.MustCompile(`(?i)\s|vnđ|vnd|usd|eur|₫|đ|\$|€|£|¥`)
+	// dotGroups and commaGroups match whole numbers grouped in thousands
+	dotGroups   = regexp.MustCompile(`^\d{1,3}(\.\d{3})+(,\d+)?$`)
+	commaGroups = regexp.MustCompile(`^\d{1,3}(,\d{3})+(\.\d+)?$`)
+)
+
+// DetectNumberFormat guesses the number format from sample amounts. Values such
+// as 1.234 that fit both formats count towards the Vietnamese one.
+func DetectNumberFormat(values []string) string {
+	vi, en := 0, 0
+	for _, v := range values {
+		digits := strings.Trim(amountNoise.ReplaceAllString(v, ""), "+-()")
+		dot, comma := strings.LastIndex(digits, "."), strings.LastIndex(digits, ",")
+		switch {
+		case dot >= 0 && comma >= 0:
+			if comma > dot {
+				vi++
+			} else {
+				en++
+			}
+		case dot >= 0:
+			// 1.5 or 12.99 is a decimal point; 1.234 or 1.234.567 groups thousands
+			if dotGroups.MatchString(digits) {
+				vi++
+			} else {
+				en++
+			}
+		case comma >= 0:
+			// 1,234,567 groups thousands; 1,5 is a decimal comma
+			if commaGroups.MatchString(digits) {
+				en++
+			} else {
+				vi++
+			}
+		}
+	}
+	if en > vi {
+		return NumberFormatEN
+	}
+	return NumberFormatVI
+}
+
+// ParseAmount parses a signed amount in the given number format. Currency
+// symbols and codes are ignored, and amounts in parentheses or with a trailing
+// minus sign are negative.
+func ParseAmount(value, format string) (decimal.Decimal, error) {
+	if !IsNumberFormat(format) {
+		return decimal.Zero, ErrUnknownNumberFormat
+	}
+
+	s := amountNoise.ReplaceAllString(value, "")
+	negative := false
+	switch {
+	case strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")"):
+		negative, s = true, s[1:len(s)-1]
+	case strings.HasPrefix(s, "-"):
+		negative, s = true, s[1:]
+	case strings.HasSuffix(s, "-"):
+		negative, s = true, s[:len(s)-1]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	group, point := ",", "."
+	if format == NumberFormatVI {
+		group, point = ".", ","
+	}
+	s = strings.ReplaceAll(s, group, "")
+	s = strings.Replace(s, point, ".", 1)
+
+	amount, err := decimal.NewFromString(s)
+	if err != nil || s == "" || strings.ContainsAny(s, "eE") {
+		return decimal.Zero, fmt.Errorf("%q is not an amount", value)
+	}
+	if negative {
+		amount = amount.Neg()
+	}
+	return amount, nil
+}
+
+// transactionTypes maps the ways files name transaction types, in English and
+// Vietnamese, to types
+var transactionTypes = map[string]model.TransactionType{
+	"income":    model.TransactionTypeIncome,
+	"credit":    model.TransactionTypeIncome,
+	"cr":        model.TransactionTypeIncome,
+	"in":        model.TransactionTypeIncome,
+	"thu":       model.TransactionTypeIncome,
+	"thu nhập":  model.TransactionTypeIncome,
+	"tiền vào":  model.TransactionTypeIncome,
+	"expense":   model.TransactionTypeExpense,
+	"debit":     model.TransactionTypeExpense,
+	"dr":        model.TransactionTypeExpense,
+	"out":       model.TransactionTypeExpense,
+	"chi":       model.TransactionTypeExpense,
+	"chi tiêu":  model.TransactionTypeExpense,
+	"tiền ra":   model.TransactionTypeExpense,
+	"khoản chi": model.TransactionTypeExpense,
+	"khoản thu": model.TransactionTypeIncome,
+}
+
+// ParseTransactionType parses a transaction type such as "expense" or "chi"
+func ParseTransactionType(value string) (model.TransactionType, error) {
+	t, ok := transactionTypes[strings.ToLower(strings.TrimSpace(value))]
+	if !ok {
+		return "", fmt.Errorf("%q is not a transaction type", value)
+	}
+	return t, nil
+}
@@ -0,0 +1,268 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxXLSXPart is the most a single part of a workbook may decompress to, so
+// that a small upload cannot expand into gigabytes
+const maxXLSXPart = 64 << 20
+
+// Sheet is a worksheet of an Excel workbook, read as text records
+type Sheet struct {
+	Name    string
+	Records [][]string
+	// Numbers marks the cells of Records stored as numbers rather than text,
+	// which are written with a decimal point whatever the user's locale. Nil
+	// for CSV.
+	Numbers [][]bool
+}
+
+// IsXLSX reports whether a file is a zip archive, as Excel workbooks are
+func IsXLSX(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("PK\x03\x04"))
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText is rich or plain text: either a single t element or runs of them
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxStyles struct {
+	NumFmts []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellXfs []struct {
+		NumFmtID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Style  int      `xml:"s,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadXLSX reads every worksheet of an Excel workbook. Cells formatted as
+// dates are returned as YYYY-MM-DD; other numbers as Excel stores them, and
+// marked in Numbers.
+func ReadXLSX(data []byte) ([]Sheet, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedFile, err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var workbook xlsxWorkbook
+	if err := readXLSXPart(files, "xl/workbook.xml", &workbook, true); err != nil {
+		return nil, err
+	}
+	var rels xlsxRelationships
+	if err := readXLSXPart(files, "xl/_rels/workbook.xml.rels", &rels, true); err != nil {
+		return nil, err
+	}
+	var shared xlsxSharedStrings
+	if err := readXLSXPart(files, "xl/sharedStrings.xml", &shared, false); err != nil {
+		return nil, err
+	}
+	var styles xlsxStyles
+	if err := readXLSXPart(files, "xl/styles.xml", &styles, false); err != nil {
+		return nil, err
+	}
+	dateStyles := xlsxDateStyles(styles)
+
+	targets := make(map[string]string, len(rels.Relationships))
+	for _, r := range rels.Relationships {
+		target := strings.TrimPrefix(r.Target, "/")
+		if !strings.HasPrefix(target, "xl/") {
+			target = path.Join("xl", target)
+		}
+		targets[r.ID] = target
+	}
+
+	sheets := make([]Sheet, 0, len(workbook.Sheets))
+	for _, s := range workbook.Sheets {
+		var ws xlsxWorksheet
+		if err := readXLSXPart(files, targets[s.RID], &ws, true); err != nil {
+			return nil, err
+		}
+
+		sheet := Sheet{Name: s.Name}
+		for _, row := range ws.Rows {
+			var record []string
+			var numbers []bool
+			for i, c := range row.Cells {
+				col := i
+				if c.Ref != "" {
+					col = xlsxColumn(c.Ref)
+				}
+				if col < len(record) || col > 16384 {
+					continue
+				}
+				for len(record) < col {
+					record = append(record, "")
+					numbers = append(numbers, false)
+				}
+
+				value := c.Value
+				number := false
+				switch c.Type {
+				case "s":
+					n, err := strconv.Atoi(c.Value)
+					if err != nil || n < 0 || n >= len(shared.Items) {
+						return nil, fmt.Errorf("%w: cell %s refers to a missing string", ErrMalformedFile, c.Ref)
+					}
+					value = shared.Items[n].String()
+				case "inlineStr":
+					value = c.Inline.String()
+				case "", "n":
+					number = value != ""
+					if c.Style >= 0 && c.Style < len(dateStyles) && dateStyles[c.Style] && value != "" {
+						if date, ok := excelDate(value); ok {
+							value, number = date.Format("2006-01-02"), false
+						}
+					}
+				}
+				record = append(record, value)
+				numbers = append(numbers, number)
+			}
+			sheet.Records = append(sheet.Records, record)
+			sheet.Numbers = append(sheet.Numbers, numbers)
+		}
+		sheets = append(sheets, sheet)
+	}
+	return sheets, nil
+}
+
+// readXLSXPart decodes one XML part of a workbook
+func readXLSXPart(files map[string]*zip.File, name string, v any, required bool) error {
+	f, ok := files[name]
+	if !ok {
+		if required {
+			return fmt.Errorf("%w: the workbook has no %s", ErrMalformedFile, name)
+		}
+		return nil
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedFile, err)
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(io.LimitReader(rc, maxXLSXPart)).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrMalformedFile, name, err)
+	}
+	return nil
+}
+
+// xlsxDateStyles reports, for each cell style, whether it formats numbers as dates
+func xlsxDateStyles(styles xlsxStyles) []bool {
+	custom := make(map[int]string, len(styles.NumFmts))
+	for _, f := range styles.NumFmts {
+		custom[f.ID] = f.Code
+	}
+	dates := make([]bool, len(styles.CellXfs))
+	for i, xf := range styles.CellXfs {
+		id := xf.NumFmtID
+		switch {
+		// Built-in date and time formats
+		case (id >= 14 && id <= 22) || (id >= 45 && id <= 47):
+			dates[i] = true
+		case custom[id] != "":
+			dates[i] = isDateFormatCode(custom[id])
+		}
+	}
+	return dates
+}
+
+// isDateFormatCode reports whether a custom number format shows a date: it has
+// day, month or year placeholders outside quoted text and brackets
+func isDateFormatCode(code string) bool {
+	inQuote, inBracket := false, false
+	for _, r := range strings.ToLower(code) {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case r == '[':
+			inBracket = true
+		case r == ']':
+			inBracket = false
+		case inBracket:
+		case r == 'd' || r == 'm' || r == 'y':
+			return true
+		}
+	}
+	return false
+}
+
+// xlsxColumn returns the zero-based column of a cell reference such as AB12
+func xlsxColumn(ref string) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+	}
+	return col - 1
+}
+
+// excelDate converts an Excel serial date, days since 30 December 1899 in the
+// 1900 date system, to a date
+func excelDate(value string) (time.Time, bool) {
+	serial, err := strconv.ParseFloat(value, 64)
+	if err != nil || serial < 1 || serial > 2958465 {
+		return time.Time{}, false
+	}
+	return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(serial)), true
+}
@@ -0,0 +1,176 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// xlsxDate is an Excel serial date, written with a built-in date format
+type xlsxDate float64
+
+// xlsxCustomDate is an Excel serial date, written with a custom dd/mm/yyyy format
+type xlsxCustomDate float64
+
+// testSheet is a worksheet for buildXLSX. Cells are strings, numbers or dates;
+// empty strings are left out of the file, as Excel does.
+type testSheet struct {
+	name string
+	rows [][]any
+}
+
+// buildXLSX writes a minimal Excel workbook
+func buildXLSX(t *testing.T, sheets ...testSheet) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	write := func(name, content string) {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(xml.Header + content))
+		require.NoError(t, err)
+	}
+
+	var workbook, rels strings.Builder
+	var shared []string
+	for i, sheet := range sheets {
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, sheet.name, i+1, i+1)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+
+		var data strings.Builder
+		for r, row := range sheet.rows {
+			fmt.Fprintf(&data, `<row r="%d">`, r+1)
+			for c, cell := range row {
+				ref := fmt.Sprintf("%c%d", 'A'+c, r+1)
+				switch v := cell.(type) {
+				case string:
+					if v == "" {
+						continue
+					}
+					fmt.Fprintf(&data, `<c r="%s" t="s"><v>%d</v></c>`, ref, len(shared))
+					shared = append(shared, v)
+				case xlsxDate:
+					fmt.Fprintf(&data, `<c r="%s" s="1"><v>%g</v></c>`, ref, float64(v))
+				case xlsxCustomDate:
+					fmt.Fprintf(&data, `<c r="%s" s="2"><v>%g</v></c>`, ref, float64(v))
+				default:
+					fmt.Fprintf(&data, `<c r="%s"><v>%v</v></c>`, ref, v)
+				}
+			}
+			data.WriteString(`</row>`)
+		}
+		write(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1),
+			`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`+data.String()+`</sheetData></worksheet>`)
+	}
+
+	write("xl/workbook.xml", `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" `+
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`+workbook.String()+`</sheets></workbook>`)
+	write("xl/_rels/workbook.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+rels.String()+`</Relationships>`)
+	write("xl/styles.xml", `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`+
+		`<numFmts count="1"><numFmt numFmtId="164" formatCode="dd/mm/yyyy;@"/></numFmts>`+
+		`<cellXfs count="3"><xf numFmtId="0"/><xf numFmtId="14"/><xf numFmtId="164"/></cellXfs></styleSheet>`)
+
+	var strs strings.Builder
+	for _, s := range shared {
+		strs.WriteString("<si><t>")
+		require.NoError(t, xml.EscapeText(&strs, []byte(s)))
+		strs.WriteString("</t></si>")
+	}
+	write("xl/sharedStrings.xml", `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`+strs.String()+`</sst>`)
+
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestReadXLSX(t *testing.T) {
+	t.Parallel()
+
+	data := buildXLSX(t,
+		testSheet{name: "Giao dịch", rows: [][]any{
+			{"Ngày", "Số tiền", "Ghi chú", "Ví"},
+			{xlsxDate(45352), -50000, "", "Tiền mặt"},
+			{xlsxCustomDate(45353), 1234.5, "A & B"},
+		}},
+		testSheet{name: "Budget", rows: [][]any{{"Category", "Amount"}}},
+	)
+	require.True(t, IsXLSX(data))
+
+	sheets, err := ReadXLSX(data)
+	require.NoError(t, err)
+	require.Len(t, sheets, 2)
+
+	assert.Equal(t, "Giao dịch", sheets[0].Name)
+	assert.Equal(t, [][]string{
+		{"Ngày", "Số tiền", "Ghi chú", "Ví"},
+		// The empty note is missing from the file and filled in
+		{"2024-03-01", "-50000", "", "Tiền mặt"},
+		{"2024-03-02", "1234.5", "A & B"},
+	}, sheets[0].Records)
+	assert.Equal(t, [][]bool{
+		{false, false, false, false},
+		{false, true, false, false},
+		{false, true, false},
+	}, sheets[0].Numbers)
+	assert.Equal(t, "Budget", sheets[1].Name)
+	assert.Equal(t, [][]string{{"Category", "Amount"}}, sheets[1].Records)
+}
+
+func TestReadXLSX_Malformed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"not a zip archive", []byte("PK\x03\x04garbage")},
+		{"zip archive that is not a workbook", func() []byte {
+			var buf bytes.Buffer
+			zw := zip.NewWriter(&buf)
+			w, _ := zw.Create("readme.txt")
+			_, _ = w.Write([]byte("hello"))
+			_ = zw.Close()
+			return buf.Bytes()
+		}()},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ReadXLSX(tt.data)
+			assert.ErrorIs(t, err, ErrMalformedFile)
+		})
+	}
+}
+
+func TestIsDateFormatCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"dd/mm/yyyy", true},
+		{"[$-409]mmm d, yyyy", true},
+		{"#,##0", false},
+		{`#,##0 "đ"`, false},
+		{`[Red]0.00`, false},
+		{"0.00%", false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.code, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, isDateFormatCode(tt.code))
+		})
+	}
+}
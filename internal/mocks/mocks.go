@@ -89,6 +89,15 @@ func (m *TransactionRepositoryInterface) List(ctx context.Context, userID uuid.U
 	return r0, ret.Error(1)
 }
 
+func (m *TransactionRepositoryInterface) Totals(ctx context.Context, userID uuid.UUID, filters repository.TransactionFilters) (*model.TransactionTotals, error) {
+	ret := m.Called(ctx, userID, filters)
+	var r0 *model.TransactionTotals
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.TransactionTotals)
+	}
+	return r0, ret.Error(1)
+}
+
 func (m *TransactionRepositoryInterface) Update(ctx context.Context, tx *model.Transaction) error {
 	ret := m.Called(ctx, tx)
 	return ret.Error(0)
@@ -99,11 +108,21 @@ func (m *TransactionRepositoryInterface) Delete(ctx context.Context, id, userID
 	return ret.Error(0)
 }
 
+func (m *TransactionRepositoryInterface) ApplyBulk(ctx context.Context, userID uuid.UUID, filters repository.TransactionFilters, dryRun bool, change repository.BulkChange) error {
+	ret := m.Called(ctx, userID, filters, dryRun, change)
+	return ret.Error(0)
+}
+
 func (m *TransactionRepositoryInterface) GetMonthlyTotals(ctx context.Context, userID uuid.UUID, year, month int) (decimal.Decimal, decimal.Decimal, error) {
 	ret := m.Called(ctx, userID, year, month)
 	return ret.Get(0).(decimal.Decimal), ret.Get(1).(decimal.Decimal), ret.Error(2)
 }
 
+func (m *TransactionRepositoryInterface) GetTotalsForPeriod(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) (decimal.Decimal, decimal.Decimal, error) {
+	ret := m.Called(ctx, userID, startDate, endDate)
+	return ret.Get(0).(decimal.Decimal), ret.Get(1).(decimal.Decimal), ret.Error(2)
+}
+
 func (m *TransactionRepositoryInterface) GetExpensesByCategory(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) (map[string]decimal.Decimal, error) {
 	ret := m.Called(ctx, userID, startDate, endDate)
 	var r0 map[string]decimal.Decimal
@@ -269,9 +288,31 @@ func (m *SavingsGoalRepositoryInterface) Delete(ctx context.Context, id, userID
 	return ret.Error(0)
 }
 
-func (m *SavingsGoalRepositoryInterface) AddContribution(ctx context.Context, id, userID uuid.UUID, amount decimal.Decimal) error {
-	ret := m.Called(ctx, id, userID, amount)
-	return ret.Error(0)
+func (m *SavingsGoalRepositoryInterface) RecordContribution(ctx context.Context, entry *model.SavingsContribution) (*model.SavingsGoal, error) {
+	ret := m.Called(ctx, entry)
+	var r0 *model.SavingsGoal
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.SavingsGoal)
+	}
+	return r0, ret.Error(1)
+}
+
+func (m *SavingsGoalRepositoryInterface) GetContribution(ctx context.Context, id uuid.UUID) (*model.SavingsContribution, error) {
+	ret := m.Called(ctx, id)
+	var r0 *model.SavingsContribution
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.SavingsContribution)
+	}
+	return r0, ret.Error(1)
+}
+
+func (m *SavingsGoalRepositoryInterface) ListContributions(ctx context.Context, goalID uuid.UUID, limit, offset int) ([]model.SavingsContribution, error) {
+	ret := m.Called(ctx, goalID, limit, offset)
+	var r0 []model.SavingsContribution
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]model.SavingsContribution)
+	}
+	return r0, ret.Error(1)
 }
 
 func (m *SavingsGoalRepositoryInterface) GetTotalSavings(ctx context.Context, userID uuid.UUID) (decimal.Decimal, error) {
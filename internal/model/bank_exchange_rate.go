@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BankExchangeRate is the price in VND a bank buys and sells one unit of a
+// foreign currency at. Banks don't quote every rate for every currency, so
+// missing ones are nil.
+type BankExchangeRate struct {
+	ID           int64            `db:"id" json:"id"`
+	BankCode     string           `db:"bank_code" json:"bankCode"`
+	BankName     string           `db:"bank_name" json:"bankName"`
+	Currency     string           `db:"currency" json:"currency"`
+	CurrencyName string           `db:"currency_name" json:"currencyName,omitempty"`
+	BuyCash      *decimal.Decimal `db:"buy_cash" json:"buyCash,omitempty"`
+	BuyTransfer  *decimal.Decimal `db:"buy_transfer" json:"buyTransfer,omitempty"`
+	Sell         *decimal.Decimal `db:"sell" json:"sell,omitempty"`
+	RateDate     time.Time        `db:"rate_date" json:"rateDate"`
+	ScrapedAt    time.Time        `db:"scraped_at" json:"scrapedAt"`
+	CreatedAt    time.Time        `db:"created_at" json:"createdAt"`
+	UpdatedAt    time.Time        `db:"updated_at" json:"updatedAt"`
+}
+
+// MidRate returns the rate between the bank's transfer buying and selling
+// price, or whichever of them is quoted. ok is false when neither is.
+func (r BankExchangeRate) MidRate() (rate decimal.Decimal, ok bool) {
+	switch {
+	case r.BuyTransfer != nil && r.Sell != nil:
+		return r.BuyTransfer.Add(*r.Sell).Div(decimal.NewFromInt(2)), true
+	case r.BuyTransfer != nil:
+		return *r.BuyTransfer, true
+	case r.Sell != nil:
+		return *r.Sell, true
+	}
+	return decimal.Zero, false
+}
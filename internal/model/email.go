@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Email outbox statuses
+const (
+	EmailStatusPending = "pending"
+	EmailStatusSending = "sending"
+	EmailStatusSent    = "sent"
+	EmailStatusFailed  = "failed"
+)
+
+// OutboxEmail is an outgoing email stored in the outbox until delivered
+type OutboxEmail struct {
+	ID            uuid.UUID  `db:"id" json:"id"`
+	ToAddress     string     `db:"to_address" json:"toAddress"`
+	Subject       string     `db:"subject" json:"subject"`
+	TextBody      string     `db:"text_body" json:"textBody"`
+	HTMLBody      *string    `db:"html_body" json:"htmlBody,omitempty"`
+	Template      *string    `db:"template" json:"template,omitempty"`
+	Status        string     `db:"status" json:"status"` // pending, sending, sent, failed
+	Attempts      int        `db:"attempts" json:"attempts"`
+	NextAttemptAt time.Time  `db:"next_attempt_at" json:"nextAttemptAt"`
+	LastError     *string    `db:"last_error" json:"lastError,omitempty"`
+	CreatedAt     time.Time  `db:"created_at" json:"createdAt"`
+	SentAt        *time.Time `db:"sent_at" json:"sentAt,omitempty"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Exchange rate sources
+const (
+	ExchangeRateSourceManual = "manual"
+)
+
+// ExchangeRate is the number of units of ToCurrency one unit of FromCurrency
+// buys on RateDate
+type ExchangeRate struct {
+	ID           uuid.UUID       `db:"id" json:"id"`
+	FromCurrency string          `db:"from_currency" json:"fromCurrency"`
+	ToCurrency   string          `db:"to_currency" json:"toCurrency"`
+	Rate         decimal.Decimal `db:"rate" json:"rate"`
+	RateDate     time.Time       `db:"rate_date" json:"rateDate"`
+	Source       string          `db:"source" json:"source"`
+	CreatedAt    time.Time       `db:"created_at" json:"createdAt"`
+	UpdatedAt    time.Time       `db:"updated_at" json:"updatedAt"`
+}
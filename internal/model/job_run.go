@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Job run statuses
+const (
+	JobRunStatusRunning   = "running"
+	JobRunStatusSucceeded = "succeeded"
+	JobRunStatusFailed    = "failed"
+)
+
+// JobRun represents a single execution of a scheduled background job
+type JobRun struct {
+	ID             uuid.UUID  `db:"id" json:"id"`
+	JobName        string     `db:"job_name" json:"jobName"`
+	Status         string     `db:"status" json:"status"` // running, succeeded, failed
+	ItemsProcessed int        `db:"items_processed" json:"itemsProcessed"`
+	ErrorMessage   *string    `db:"error_message" json:"errorMessage,omitempty"`
+	StartedAt      time.Time  `db:"started_at" json:"startedAt"`
+	FinishedAt     *time.Time `db:"finished_at" json:"finishedAt,omitempty"`
+}
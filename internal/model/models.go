@@ -14,6 +14,7 @@ type User struct {
 	PasswordHash    *string    `db:"password_hash" json:"-"`
 	Name            string     `db:"name" json:"name"`
 	Currency        string     `db:"currency" json:"currency"`
+	Locale          string     `db:"locale" json:"locale"` // Language of the user's emails
 	OAuthProvider   *string    `db:"oauth_provider" json:"oauthProvider,omitempty"`
 	OAuthID         *string    `db:"oauth_id" json:"-"`
 	AvatarURL       *string    `db:"avatar_url" json:"avatarUrl,omitempty"`
@@ -41,8 +42,75 @@ type Transaction struct {
 	Category    string          `db:"category" json:"category"`
 	Description string          `db:"description" json:"description"`
 	Date        time.Time       `db:"date" json:"date"`
+	AccountID   *uuid.UUID      `db:"account_id" json:"accountId,omitempty"`
 	CreatedAt   time.Time       `db:"created_at" json:"createdAt"`
 	UpdatedAt   time.Time       `db:"updated_at" json:"updatedAt"`
+
+	ImportBatchID *uuid.UUID `db:"import_batch_id" json:"importBatchId,omitempty"`
+	ExternalID    *string    `db:"external_id" json:"externalId,omitempty"` // Bank's ID for imported transactions, e.g. an OFX FITID
+
+	// Amount converted to the user's currency at the rate of the transaction's
+	// date. Nil until a rate is known.
+	BaseCurrency *string          `db:"base_currency" json:"baseCurrency,omitempty"`
+	ExchangeRate *decimal.Decimal `db:"exchange_rate" json:"exchangeRate,omitempty"` // Units of BaseCurrency per unit of Currency
+	BaseAmount   *decimal.Decimal `db:"base_amount" json:"baseAmount,omitempty"`
+
+	Splits []TransactionSplit `db:"-" json:"splits,omitempty"`
+	Tags   []string           `db:"-" json:"tags,omitempty"`
+}
+
+// TransactionSplit is one line of a split transaction. The lines add up to the
+// transaction amount and replace its category in reports and budgets.
+type TransactionSplit struct {
+	ID            uuid.UUID       `db:"id" json:"id"`
+	TransactionID uuid.UUID       `db:"transaction_id" json:"transactionId"`
+	Category      string          `db:"category" json:"category"`
+	Amount        decimal.Decimal `db:"amount" json:"amount"`
+	Note          string          `db:"note" json:"note,omitempty"`
+	Position      int             `db:"position" json:"-"`
+}
+
+type AccountType string
+
+const (
+	AccountTypeCash       AccountType = "cash"
+	AccountTypeBank       AccountType = "bank"
+	AccountTypeCreditCard AccountType = "credit_card"
+	AccountTypeEWallet    AccountType = "e_wallet" // MoMo, ZaloPay, ...
+)
+
+// Account is somewhere a user keeps money. Its balance is computed from the
+// opening balance, the account's transactions and its transfers.
+type Account struct {
+	ID             uuid.UUID       `db:"id" json:"id"`
+	UserID         uuid.UUID       `db:"user_id" json:"userId"`
+	Name           string          `db:"name" json:"name"`
+	Type           AccountType     `db:"type" json:"type"`
+	Institution    string          `db:"institution" json:"institution"` // Bank or wallet provider
+	Currency       string          `db:"currency" json:"currency"`
+	OpeningBalance decimal.Decimal `db:"opening_balance" json:"openingBalance"`
+	Archived       bool            `db:"archived" json:"archived"`
+	CreatedAt      time.Time       `db:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time       `db:"updated_at" json:"updatedAt"`
+}
+
+type AccountWithBalance struct {
+	Account
+	Balance decimal.Decimal `db:"balance" json:"balance"`
+}
+
+// Transfer moves money between two of a user's accounts. Transfers change
+// account balances but are never counted as income or expenses.
+type Transfer struct {
+	ID            uuid.UUID       `db:"id" json:"id"`
+	UserID        uuid.UUID       `db:"user_id" json:"userId"`
+	FromAccountID uuid.UUID       `db:"from_account_id" json:"fromAccountId"`
+	ToAccountID   uuid.UUID       `db:"to_account_id" json:"toAccountId"`
+	Amount        decimal.Decimal `db:"amount" json:"amount"`
+	Description   string          `db:"description" json:"description"`
+	Date          time.Time       `db:"date" json:"date"`
+	ImportBatchID *uuid.UUID      `db:"import_batch_id" json:"importBatchId,omitempty"`
+	CreatedAt     time.Time       `db:"created_at" json:"createdAt"`
 }
 
 type Budget struct {
@@ -56,7 +124,9 @@ type Budget struct {
 	EndDate           *time.Time       `db:"end_date" json:"endDate,omitempty"`
 	EnableRollover    bool             `db:"enable_rollover" json:"enableRollover"`
 	MaxRolloverAmount *decimal.Decimal `db:"max_rollover_amount" json:"maxRolloverAmount,omitempty"`
+	RolloverOverspend bool             `db:"rollover_overspend" json:"rolloverOverspend"` // Carry overspending as a negative rollover
 	RolloverAmount    decimal.Decimal  `db:"rollover_amount" json:"rolloverAmount"`
+	ImportBatchID     *uuid.UUID       `db:"import_batch_id" json:"importBatchId,omitempty"`
 	CreatedAt         time.Time        `db:"created_at" json:"createdAt"`
 	UpdatedAt         time.Time        `db:"updated_at" json:"updatedAt"`
 }
@@ -74,9 +144,12 @@ type BudgetRollover struct {
 
 type BudgetWithSpent struct {
 	Budget
-	Spent      decimal.Decimal `db:"spent" json:"spent"`
+	Spent      decimal.Decimal `db:"spent" json:"spent"` // In the budget's currency
 	Remaining  decimal.Decimal `json:"remaining"`
 	Percentage float64         `json:"percentage"`
+	// Spending has no rate to the budget's currency yet, so Spent, Remaining
+	// and Percentage are left unset
+	Unconverted bool `json:"unconverted,omitempty"`
 }
 
 type SavingsGoal struct {
@@ -93,6 +166,21 @@ type SavingsGoal struct {
 	UpdatedAt     time.Time       `db:"updated_at" json:"updatedAt"`
 }
 
+// SavingsContribution is an entry in a savings goal's ledger.
+// Withdrawals and reversals are stored as negative amounts.
+type SavingsContribution struct {
+	ID            uuid.UUID       `db:"id" json:"id"`
+	GoalID        uuid.UUID       `db:"goal_id" json:"goalId"`
+	UserID        uuid.UUID       `db:"user_id" json:"userId"`
+	Amount        decimal.Decimal `db:"amount" json:"amount"`
+	Date          time.Time       `db:"date" json:"date"`
+	Note          *string         `db:"note" json:"note,omitempty"`
+	TransactionID *uuid.UUID      `db:"transaction_id" json:"transactionId,omitempty"`
+	ReversalOf    *uuid.UUID      `db:"reversal_of" json:"reversalOf,omitempty"` // Entry this one reverses
+	Reversed      bool            `db:"reversed" json:"reversed"`                // Set when listing history
+	CreatedAt     time.Time       `db:"created_at" json:"createdAt"`
+}
+
 type DebtType string
 
 const (
@@ -117,6 +205,7 @@ type Debt struct {
 	DueDay         int             `db:"due_day" json:"dueDay"` // Day of month
 	StartDate      time.Time       `db:"start_date" json:"startDate"`
 	ExpectedPayoff *time.Time      `db:"expected_payoff" json:"expectedPayoff,omitempty"`
+	ImportBatchID  *uuid.UUID      `db:"import_batch_id" json:"importBatchId,omitempty"`
 	CreatedAt      time.Time       `db:"created_at" json:"createdAt"`
 	UpdatedAt      time.Time       `db:"updated_at" json:"updatedAt"`
 }
@@ -152,6 +241,7 @@ type AmortizationRow struct {
 
 // Dashboard aggregates
 type DashboardData struct {
+	Currency           string                     `json:"currency"` // The user's base currency, which totals are in
 	TotalIncome        decimal.Decimal            `json:"totalIncome"`
 	TotalExpenses      decimal.Decimal            `json:"totalExpenses"`
 	NetCashFlow        decimal.Decimal            `json:"netCashFlow"`
@@ -162,6 +252,13 @@ type DashboardData struct {
 	RecentTransactions []Transaction              `json:"recentTransactions"`
 	ExpensesByCategory map[string]decimal.Decimal `json:"expensesByCategory"`
 	IncomeVsExpenses   []MonthlyComparison        `json:"incomeVsExpenses"`
+	Accounts           []AccountWithBalance       `json:"accounts"` // Active accounts with their balances
+	// Transactions of the month without a rate to the base currency yet, which
+	// are left out of the totals
+	UnconvertedTransactions int `json:"unconvertedTransactions"`
+	// Savings goals, debts and budgets in a currency without a rate to the base
+	// currency yet, which are left out of the totals or flagged as unconverted
+	UnconvertedBalances int `json:"unconvertedBalances"`
 }
 
 type MonthlyComparison struct {
@@ -170,6 +267,14 @@ type MonthlyComparison struct {
 	Expenses decimal.Decimal `json:"expenses"`
 }
 
+// TransactionTotals aggregates the transactions matching a filter
+type TransactionTotals struct {
+	Count       int             `db:"count" json:"count"`
+	Income      decimal.Decimal `db:"income" json:"income"`
+	Expenses    decimal.Decimal `db:"expenses" json:"expenses"`
+	Unconverted int             `db:"unconverted" json:"unconverted"` // Not in the base currency yet, so left out of Income and Expenses
+}
+
 // Recurring Transactions
 type RecurringFrequency string
 
@@ -197,6 +302,8 @@ type RecurringTransaction struct {
 	IsActive       bool               `db:"is_active" json:"isActive"`
 	CreatedAt      time.Time          `db:"created_at" json:"createdAt"`
 	UpdatedAt      time.Time          `db:"updated_at" json:"updatedAt"`
+
+	Tags []string `db:"-" json:"tags,omitempty"`
 }
 
 // UpcomingBill is a simplified view for dashboard widget
@@ -210,7 +317,133 @@ type UpcomingBill struct {
 	Type        TransactionType `db:"type" json:"type"`
 }
 
-// Categories
+// Category is one of a user's income or expense categories. Transactions, budgets
+// and recurring transactions refer to it by name. A category with a parent is a
+// subcategory; there is only one level of nesting.
+type Category struct {
+	ID        uuid.UUID       `db:"id" json:"id"`
+	UserID    uuid.UUID       `db:"user_id" json:"userId"`
+	ParentID  *uuid.UUID      `db:"parent_id" json:"parentId,omitempty"`
+	Name      string          `db:"name" json:"name"`
+	Type      TransactionType `db:"type" json:"type"`
+	Icon      string          `db:"icon" json:"icon"`
+	Color     string          `db:"color" json:"color"`
+	Archived  bool            `db:"archived" json:"archived"`
+	CreatedAt time.Time       `db:"created_at" json:"createdAt"`
+	UpdatedAt time.Time       `db:"updated_at" json:"updatedAt"`
+}
+
+// Tag is a free-form label a user puts on transactions and recurring
+// transactions. Names are unique per user regardless of case.
+type Tag struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	UserID    uuid.UUID `db:"user_id" json:"userId"`
+	Name      string    `db:"name" json:"name"`
+	Color     string    `db:"color" json:"color"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+// TagUsage is a tag with the number of transactions it is on
+type TagUsage struct {
+	Tag
+	TransactionCount int `db:"transaction_count" json:"transactionCount"`
+}
+
+// CategorizationRule sets the category, tags or description of transactions
+// matching all of its conditions. Empty conditions match everything.
+type CategorizationRule struct {
+	ID       uuid.UUID `db:"id" json:"id"`
+	UserID   uuid.UUID `db:"user_id" json:"userId"`
+	Name     string    `db:"name" json:"name"`
+	Priority int       `db:"priority" json:"priority"` // Lower runs first
+	Enabled  bool      `db:"enabled" json:"enabled"`
+
+	// Conditions
+	TransactionType     *TransactionType `db:"transaction_type" json:"transactionType,omitempty"`
+	DescriptionContains string           `db:"description_contains" json:"descriptionContains,omitempty"`
+	DescriptionPattern  string           `db:"description_pattern" json:"descriptionPattern,omitempty"` // Regular expression
+	MinAmount           *decimal.Decimal `db:"min_amount" json:"minAmount,omitempty"`
+	MaxAmount           *decimal.Decimal `db:"max_amount" json:"maxAmount,omitempty"`
+	AccountID           *uuid.UUID       `db:"account_id" json:"accountId,omitempty"`
+
+	// Actions
+	SetCategory    string   `db:"set_category" json:"setCategory,omitempty"`
+	SetDescription string   `db:"set_description" json:"setDescription,omitempty"`
+	AddTags        []string `db:"-" json:"addTags,omitempty"`
+
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+// Duplicate review statuses
+const (
+	DuplicateStatusDismissed    = "dismissed"
+	DuplicateStatusNotDuplicate = "not_duplicate"
+)
+
+// DuplicateReview is the user's decision on a pair of suspected duplicate
+// transactions. TransactionID is the lower of the two IDs.
+type DuplicateReview struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	UserID        uuid.UUID `db:"user_id" json:"userId"`
+	TransactionID uuid.UUID `db:"transaction_id" json:"transactionId"`
+	DuplicateID   uuid.UUID `db:"duplicate_id" json:"duplicateId"`
+	Status        string    `db:"status" json:"status"`
+	CreatedAt     time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+// Import batch sources
+const (
+	ImportSourceCSV = "csv"
+	ImportSourceOFX = "ofx"
+	ImportSourceQIF = "qif"
+	ImportSourcePDF = "pdf"
+
+	// Migrations from other apps
+	ImportSourceMoneyLover = "moneylover"
+	ImportSourceMISA       = "misa"
+)
+
+// Import batch statuses
+const (
+	ImportStatusPending   = "pending"
+	ImportStatusCommitted = "committed"
+	ImportStatusUndone    = "undone"
+)
+
+// ImportBatch is a file imported into transactions. Content holds the upload
+// while the batch is pending.
+type ImportBatch struct {
+	ID            uuid.UUID  `db:"id" json:"id"`
+	UserID        uuid.UUID  `db:"user_id" json:"userId"`
+	Source        string     `db:"source" json:"source"`
+	FileName      string     `db:"file_name" json:"fileName"`
+	Encoding      string     `db:"encoding" json:"encoding"`
+	Status        string     `db:"status" json:"status"`
+	Content       *string    `db:"content" json:"-"`
+	RowCount      int        `db:"row_count" json:"rowCount"`
+	ImportedCount int        `db:"imported_count" json:"importedCount"`
+	CreatedAt     time.Time  `db:"created_at" json:"createdAt"`
+	CommittedAt   *time.Time `db:"committed_at" json:"committedAt,omitempty"`
+	UndoneAt      *time.Time `db:"undone_at" json:"undoneAt,omitempty"`
+}
+
+// Attachment is a receipt or invoice attached to either a transaction or a debt payment
+type Attachment struct {
+	ID            uuid.UUID  `db:"id" json:"id"`
+	UserID        uuid.UUID  `db:"user_id" json:"userId"`
+	TransactionID *uuid.UUID `db:"transaction_id" json:"transactionId,omitempty"`
+	DebtPaymentID *uuid.UUID `db:"debt_payment_id" json:"debtPaymentId,omitempty"`
+	FileName      string     `db:"file_name" json:"fileName"`
+	ContentType   string     `db:"content_type" json:"contentType"`
+	Size          int64      `db:"size_bytes" json:"size"`
+	StorageKey    string     `db:"storage_key" json:"-"`
+	CreatedAt     time.Time  `db:"created_at" json:"createdAt"`
+}
+
+// Default categories, seeded for each user the first time they use categories
 var ExpenseCategories = []string{
 	"Housing",
 	"Transportation",
@@ -269,10 +502,12 @@ type NotificationPreferences struct {
 type NotificationType string
 
 const (
-	NotificationTypeBillReminder  NotificationType = "bill_reminder"
-	NotificationTypeBudgetAlert   NotificationType = "budget_alert"
-	NotificationTypeGoalMilestone NotificationType = "goal_milestone"
-	NotificationTypeWeeklySummary NotificationType = "weekly_summary"
+	NotificationTypeBillReminder   NotificationType = "bill_reminder"
+	NotificationTypeDebtReminder   NotificationType = "debt_reminder"
+	NotificationTypeBudgetAlert    NotificationType = "budget_alert"
+	NotificationTypeBudgetExceeded NotificationType = "budget_exceeded"
+	NotificationTypeGoalMilestone  NotificationType = "goal_milestone"
+	NotificationTypeWeeklySummary  NotificationType = "weekly_summary"
 )
 
 type NotificationLog struct {
@@ -281,11 +516,14 @@ type NotificationLog struct {
 	NotificationType NotificationType `db:"notification_type" json:"notificationType"`
 	ReferenceID      *uuid.UUID       `db:"reference_id" json:"referenceId,omitempty"`
 	ReferenceDate    *time.Time       `db:"reference_date" json:"referenceDate,omitempty"`
+	Milestone        *int             `db:"milestone" json:"milestone,omitempty"` // Goal percentage of a goal milestone
 	Title            string           `db:"title" json:"title"`
 	Body             string           `db:"body" json:"body"`
 	SentAt           time.Time        `db:"sent_at" json:"sentAt"`
 	Success          bool             `db:"success" json:"success"`
 	ErrorMessage     *string          `db:"error_message" json:"errorMessage,omitempty"`
+	ReadAt           *time.Time       `db:"read_at" json:"readAt,omitempty"`
+	DeletedAt        *time.Time       `db:"deleted_at" json:"-"`
 }
 
 // Refresh Tokens for Remember Me feature
@@ -25,6 +25,20 @@ type RateSubscription struct {
 	UpdatedAt        time.Time       `db:"updated_at" json:"updatedAt"`
 }
 
+// Rate notification delivery channels
+const (
+	RateNotificationChannelEmail = "email"
+	RateNotificationChannelPush  = "push"
+)
+
+// Rate notification delivery statuses. An email is sent once it is queued in
+// the email outbox, which retries it and tracks whether it went out.
+const (
+	RateNotificationStatusPending = "pending"
+	RateNotificationStatusSent    = "sent"
+	RateNotificationStatusFailed  = "failed"
+)
+
 // RateNotification represents a notification sent for a rate change
 type RateNotification struct {
 	ID               int64           `db:"id" json:"id"`
@@ -37,6 +51,14 @@ type RateNotification struct {
 	Status           string          `db:"status" json:"status"` // pending, sent, failed
 }
 
+// RateNotificationHistory is a sent notification together with the subscription it was for
+type RateNotificationHistory struct {
+	RateNotification
+	BankCode    string `db:"bank_code" json:"bankCode"`
+	ProductType string `db:"product_type" json:"productType"`
+	TermMonths  int    `db:"term_months" json:"termMonths"`
+}
+
 // RateChangeAlert represents an alert to be sent to a user
 type RateChangeAlert struct {
 	Subscription RateSubscription
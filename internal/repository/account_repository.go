@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+var (
+	// ErrAccountNotFound is returned when an account does not exist or belongs to another user
+	ErrAccountNotFound = errors.New("account not found")
+	// ErrAccountInUse is returned when deleting an account that has transfers; archive it instead
+	ErrAccountInUse = errors.New("account has transfers")
+	// ErrTransferNotFound is returned when a transfer does not exist or belongs to another user
+	ErrTransferNotFound = errors.New("transfer not found")
+)
+
+// TransferFilter narrows the transfers listed for a user
+type TransferFilter struct {
+	AccountID *uuid.UUID // Transfers into or out of this account
+	StartDate *time.Time
+	EndDate   *time.Time
+	Limit     int
+	Offset    int
+}
+
+// AccountRepository stores accounts and the transfers between them.
+// Balances are computed as of a date from the opening balance, the account's
+// transactions and its transfers.
+type AccountRepository interface {
+	Create(ctx context.Context, account *model.Account) error
+	GetByID(ctx context.Context, userID, id uuid.UUID) (*model.Account, error)
+	GetWithBalance(ctx context.Context, userID, id uuid.UUID, asOf time.Time) (*model.AccountWithBalance, error)
+	ListWithBalances(ctx context.Context, userID uuid.UUID, includeArchived bool, asOf time.Time) ([]model.AccountWithBalance, error)
+	Update(ctx context.Context, account *model.Account) error
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+
+	CreateTransfer(ctx context.Context, transfer *model.Transfer) error
+	GetTransfer(ctx context.Context, userID, id uuid.UUID) (*model.Transfer, error)
+	ListTransfers(ctx context.Context, userID uuid.UUID, filter TransferFilter) ([]model.Transfer, error)
+	DeleteTransfer(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type accountRepository struct {
+	db *sqlx.DB
+}
+
+// NewAccountRepository creates a new account repository
+func NewAccountRepository(db *sqlx.DB) AccountRepository {
+	return &accountRepository{db: db}
+}
+
+// accountsWithBalanceQuery selects accounts with their balance as of $2.
+// Callers append the WHERE clause; $1 is always the user ID.
+const accountsWithBalanceQuery = `
+	SELECT a.*,
+		a.opening_balance
+		+ COALESCE((
+			SELECT SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END)
+			FROM transactions t
+			WHERE t.account_id = a.id AND t.date <= $2
+		), 0)
+		+ COALESCE((
+			SELECT SUM(tr.amount) FROM transfers tr
+			WHERE tr.to_account_id = a.id AND tr.date <= $2
+		), 0)
+		- COALESCE((
+			SELECT SUM(tr.amount) FROM transfers tr
+			WHERE tr.from_account_id = a.id AND tr.date <= $2
+		), 0) AS balance
+	FROM accounts a`
+
+func (r *accountRepository) Create(ctx context.Context, account *model.Account) error {
+	query := `
+		INSERT INTO accounts (id, user_id, name, type, institution, currency, opening_balance, archived, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		RETURNING created_at, updated_at`
+
+	account.ID = uuid.New()
+	return r.db.QueryRowxContext(ctx, query,
+		account.ID, account.UserID, account.Name, account.Type, account.Institution,
+		account.Currency, account.OpeningBalance, account.Archived,
+	).Scan(&account.CreatedAt, &account.UpdatedAt)
+}
+
+func (r *accountRepository) GetByID(ctx context.Context, userID, id uuid.UUID) (*model.Account, error) {
+	var account model.Account
+	query := `SELECT * FROM accounts WHERE id = $1 AND user_id = $2`
+	err := r.db.GetContext(ctx, &account, query, id, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAccountNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *accountRepository) GetWithBalance(ctx context.Context, userID, id uuid.UUID, asOf time.Time) (*model.AccountWithBalance, error) {
+	var account model.AccountWithBalance
+	query := accountsWithBalanceQuery + ` WHERE a.user_id = $1 AND a.id = $3`
+	err := r.db.GetContext(ctx, &account, query, userID, asOf, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAccountNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// ListWithBalances returns the user's accounts, active ones first
+func (r *accountRepository) ListWithBalances(ctx context.Context, userID uuid.UUID, includeArchived bool, asOf time.Time) ([]model.AccountWithBalance, error) {
+	query := accountsWithBalanceQuery + `
+		WHERE a.user_id = $1 AND ($3::boolean OR NOT a.archived)
+		ORDER BY a.archived, a.created_at`
+
+	accounts := []model.AccountWithBalance{}
+	err := r.db.SelectContext(ctx, &accounts, query, userID, asOf, includeArchived)
+	return accounts, err
+}
+
+func (r *accountRepository) Update(ctx context.Context, account *model.Account) error {
+	query := `
+		UPDATE accounts
+		SET name = $3, type = $4, institution = $5, opening_balance = $6, archived = $7, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+		RETURNING updated_at`
+
+	err := r.db.QueryRowxContext(ctx, query,
+		account.ID, account.UserID, account.Name, account.Type, account.Institution,
+		account.OpeningBalance, account.Archived,
+	).Scan(&account.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrAccountNotFound
+	}
+	return err
+}
+
+// Delete removes an account. Its transactions are kept without an account, but an
+// account with transfers cannot be deleted because that would change the balance
+// of the other side.
+func (r *accountRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Locking the account keeps a transfer from being added before it is deleted
+	var hasTransfers bool
+	err = tx.GetContext(ctx, &hasTransfers, `
+		SELECT EXISTS (
+			SELECT 1 FROM transfers WHERE from_account_id = a.id OR to_account_id = a.id
+		)
+		FROM accounts a
+		WHERE a.id = $1 AND a.user_id = $2
+		FOR UPDATE`, id, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrAccountNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if hasTransfers {
+		return ErrAccountInUse
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM accounts WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *accountRepository) CreateTransfer(ctx context.Context, transfer *model.Transfer) error {
+	query := `
+		INSERT INTO transfers (id, user_id, from_account_id, to_account_id, amount, description, date, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		RETURNING created_at`
+
+	transfer.ID = uuid.New()
+	return r.db.QueryRowxContext(ctx, query,
+		transfer.ID, transfer.UserID, transfer.FromAccountID, transfer.ToAccountID,
+		transfer.Amount, transfer.Description, transfer.Date,
+	).Scan(&transfer.CreatedAt)
+}
+
+func (r *accountRepository) GetTransfer(ctx context.Context, userID, id uuid.UUID) (*model.Transfer, error) {
+	var transfer model.Transfer
+	query := `SELECT * FROM transfers WHERE id = $1 AND user_id = $2`
+	err := r.db.GetContext(ctx, &transfer, query, id, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTransferNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (r *accountRepository) ListTransfers(ctx context.Context, userID uuid.UUID, filter TransferFilter) ([]model.Transfer, error) {
+	query := `
+		SELECT * FROM transfers
+		WHERE user_id = $1
+		AND ($2::uuid IS NULL OR from_account_id = $2 OR to_account_id = $2)
+		AND ($3::date IS NULL OR date >= $3)
+		AND ($4::date IS NULL OR date <= $4)
+		ORDER BY date DESC, created_at DESC
+		LIMIT $5 OFFSET $6`
+
+	transfers := []model.Transfer{}
+	err := r.db.SelectContext(ctx, &transfers, query,
+		userID, filter.AccountID, filter.StartDate, filter.EndDate, filter.Limit, filter.Offset,
+	)
+	return transfers, err
+}
+
+func (r *accountRepository) DeleteTransfer(ctx context.Context, userID, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM transfers WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrTransferNotFound
+	}
+	return nil
+}
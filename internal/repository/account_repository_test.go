@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountRepository_Delete(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock, uuid.UUID, uuid.UUID)
+		wantErr   error
+	}{
+		{
+			name: "success",
+			setupMock: func(mock sqlmock.Sqlmock, id, userID uuid.UUID) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT EXISTS .* FROM accounts a WHERE a.id = \$1 AND a.user_id = \$2 FOR UPDATE`).
+					WithArgs(id, userID).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectExec(`DELETE FROM accounts WHERE id = \$1`).
+					WithArgs(id).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "not found",
+			setupMock: func(mock sqlmock.Sqlmock, id, userID uuid.UUID) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT EXISTS`).
+					WithArgs(id, userID).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr: ErrAccountNotFound,
+		},
+		{
+			name: "has transfers",
+			setupMock: func(mock sqlmock.Sqlmock, id, userID uuid.UUID) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT EXISTS`).
+					WithArgs(id, userID).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrAccountInUse,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := newMockDB(t)
+			defer func() { _ = db.Close() }()
+			repo := NewAccountRepository(db)
+
+			id, userID := uuid.New(), uuid.New()
+			tt.setupMock(mock, id, userID)
+
+			err := repo.Delete(context.Background(), userID, id)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+var (
+	// ErrAttachmentNotFound is returned when an attachment does not exist or belongs to another user
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	// ErrDebtPaymentNotFound is returned when a debt payment does not exist or belongs to another user
+	ErrDebtPaymentNotFound = errors.New("debt payment not found")
+)
+
+// AttachmentRepository stores attachment metadata. Deleting an attachment row,
+// directly or through a cascade, queues its blob for deletion from storage.
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *model.Attachment) error
+	GetByID(ctx context.Context, userID, id uuid.UUID) (*model.Attachment, error)
+	ListByTransaction(ctx context.Context, userID, transactionID uuid.UUID) ([]model.Attachment, error)
+	ListByDebtPayment(ctx context.Context, userID, paymentID uuid.UUID) ([]model.Attachment, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) (*model.Attachment, error)
+
+	OwnsTransaction(ctx context.Context, userID, transactionID uuid.UUID) (bool, error)
+	OwnsDebtPayment(ctx context.Context, userID, debtID, paymentID uuid.UUID) (bool, error)
+
+	PendingBlobDeletions(ctx context.Context, limit int) ([]string, error)
+	ForgetBlobDeletion(ctx context.Context, storageKey string) error
+	RecordBlobDeletionFailure(ctx context.Context, storageKey, reason string) error
+}
+
+type attachmentRepository struct {
+	db *sqlx.DB
+}
+
+// NewAttachmentRepository creates a new attachment repository
+func NewAttachmentRepository(db *sqlx.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) Create(ctx context.Context, attachment *model.Attachment) error {
+	query := `
+		INSERT INTO attachments (id, user_id, transaction_id, debt_payment_id, file_name, content_type, size_bytes, storage_key, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		RETURNING created_at`
+
+	return r.db.QueryRowxContext(ctx, query,
+		attachment.ID, attachment.UserID, attachment.TransactionID, attachment.DebtPaymentID,
+		attachment.FileName, attachment.ContentType, attachment.Size, attachment.StorageKey,
+	).Scan(&attachment.CreatedAt)
+}
+
+func (r *attachmentRepository) GetByID(ctx context.Context, userID, id uuid.UUID) (*model.Attachment, error) {
+	var attachment model.Attachment
+	err := r.db.GetContext(ctx, &attachment, `SELECT * FROM attachments WHERE id = $1 AND user_id = $2`, id, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// ListByTransaction returns a transaction's attachments, oldest first
+func (r *attachmentRepository) ListByTransaction(ctx context.Context, userID, transactionID uuid.UUID) ([]model.Attachment, error) {
+	query := `
+		SELECT * FROM attachments
+		WHERE user_id = $1 AND transaction_id = $2
+		ORDER BY created_at, id`
+
+	attachments := []model.Attachment{}
+	err := r.db.SelectContext(ctx, &attachments, query, userID, transactionID)
+	return attachments, err
+}
+
+// ListByDebtPayment returns a debt payment's attachments, oldest first
+func (r *attachmentRepository) ListByDebtPayment(ctx context.Context, userID, paymentID uuid.UUID) ([]model.Attachment, error) {
+	query := `
+		SELECT * FROM attachments
+		WHERE user_id = $1 AND debt_payment_id = $2
+		ORDER BY created_at, id`
+
+	attachments := []model.Attachment{}
+	err := r.db.SelectContext(ctx, &attachments, query, userID, paymentID)
+	return attachments, err
+}
+
+// Delete removes an attachment and returns it. Its blob is queued for deletion.
+func (r *attachmentRepository) Delete(ctx context.Context, userID, id uuid.UUID) (*model.Attachment, error) {
+	var attachment model.Attachment
+	err := r.db.GetContext(ctx, &attachment,
+		`DELETE FROM attachments WHERE id = $1 AND user_id = $2 RETURNING *`, id, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// OwnsTransaction reports whether the transaction exists and belongs to the user
+func (r *attachmentRepository) OwnsTransaction(ctx context.Context, userID, transactionID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists,
+		`SELECT EXISTS (SELECT 1 FROM transactions WHERE id = $1 AND user_id = $2)`, transactionID, userID)
+	return exists, err
+}
+
+// OwnsDebtPayment reports whether the payment exists, was made on the debt and
+// the debt belongs to the user
+func (r *attachmentRepository) OwnsDebtPayment(ctx context.Context, userID, debtID, paymentID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM debt_payments p
+			JOIN debts d ON d.id = p.debt_id
+			WHERE p.id = $1 AND p.debt_id = $2 AND d.user_id = $3
+		)`
+
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, query, paymentID, debtID, userID)
+	return exists, err
+}
+
+// PendingBlobDeletions returns storage keys of deleted attachments whose blobs
+// still have to be removed. Keys that have failed the fewest times come first.
+func (r *attachmentRepository) PendingBlobDeletions(ctx context.Context, limit int) ([]string, error) {
+	query := `
+		SELECT storage_key FROM attachment_blob_deletions
+		ORDER BY attempts, deleted_at
+		LIMIT $1`
+
+	var keys []string
+	err := r.db.SelectContext(ctx, &keys, query, limit)
+	return keys, err
+}
+
+// ForgetBlobDeletion removes a key from the deletion queue once its blob is gone
+func (r *attachmentRepository) ForgetBlobDeletion(ctx context.Context, storageKey string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM attachment_blob_deletions WHERE storage_key = $1`, storageKey)
+	return err
+}
+
+// RecordBlobDeletionFailure keeps a key queued and records why its blob could not be deleted
+func (r *attachmentRepository) RecordBlobDeletionFailure(ctx context.Context, storageKey, reason string) error {
+	query := `
+		UPDATE attachment_blob_deletions
+		SET attempts = attempts + 1, last_error = $2
+		WHERE storage_key = $1`
+
+	_, err := r.db.ExecContext(ctx, query, storageKey, reason)
+	return err
+}
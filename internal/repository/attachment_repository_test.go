@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var attachmentColumns = []string{"id", "user_id", "transaction_id", "debt_payment_id", "file_name", "content_type", "size_bytes", "storage_key", "created_at"}
+
+func TestAttachmentRepository_Delete(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		setupMock func(mock sqlmock.Sqlmock, userID, id uuid.UUID)
+		wantErr   error
+	}{
+		{
+			name: "success",
+			setupMock: func(mock sqlmock.Sqlmock, userID, id uuid.UUID) {
+				mock.ExpectQuery(`DELETE FROM attachments WHERE id = \$1 AND user_id = \$2 RETURNING \*`).
+					WithArgs(id, userID).
+					WillReturnRows(sqlmock.NewRows(attachmentColumns).
+						AddRow(id, userID, uuid.New(), nil, "receipt.jpg", "image/jpeg", 1024, userID.String()+"/"+id.String(), time.Now()))
+			},
+		},
+		{
+			name: "not found",
+			setupMock: func(mock sqlmock.Sqlmock, userID, id uuid.UUID) {
+				mock.ExpectQuery(`DELETE FROM attachments`).WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: ErrAttachmentNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := newMockDB(t)
+			defer func() { _ = db.Close() }()
+			repo := NewAttachmentRepository(db)
+			userID, id := uuid.New(), uuid.New()
+			tt.setupMock(mock, userID, id)
+
+			attachment, err := repo.Delete(context.Background(), userID, id)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, userID.String()+"/"+id.String(), attachment.StorageKey)
+				assert.Nil(t, attachment.DebtPaymentID)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAttachmentRepository_OwnsDebtPayment(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewAttachmentRepository(db)
+	userID, debtID, paymentID := uuid.New(), uuid.New(), uuid.New()
+
+	mock.ExpectQuery(`SELECT EXISTS \(\s+SELECT 1 FROM debt_payments p\s+JOIN debts d ON d.id = p.debt_id`).
+		WithArgs(paymentID, debtID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	owns, err := repo.OwnsDebtPayment(context.Background(), userID, debtID, paymentID)
+
+	assert.NoError(t, err)
+	assert.False(t, owns)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
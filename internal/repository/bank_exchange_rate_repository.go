@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+// ExchangeRateHistoryEntry is a bank's exchange rates for a currency on a day
+type ExchangeRateHistoryEntry struct {
+	BankCode     string           `db:"bank_code" json:"bankCode"`
+	Currency     string           `db:"currency" json:"currency"`
+	BuyCash      *decimal.Decimal `db:"buy_cash" json:"buyCash,omitempty"`
+	BuyTransfer  *decimal.Decimal `db:"buy_transfer" json:"buyTransfer,omitempty"`
+	Sell         *decimal.Decimal `db:"sell" json:"sell,omitempty"`
+	RecordedDate time.Time        `db:"recorded_date" json:"recordedDate"`
+}
+
+// BankExchangeRateRepository stores the exchange rates scraped from banks
+type BankExchangeRateRepository interface {
+	Upsert(ctx context.Context, rates []model.BankExchangeRate) error
+	List(ctx context.Context, bankCode, currency string) ([]model.BankExchangeRate, error)
+	GetHistory(ctx context.Context, bankCode, currency string, days int) ([]ExchangeRateHistoryEntry, error)
+}
+
+type bankExchangeRateRepository struct {
+	db *sqlx.DB
+}
+
+// NewBankExchangeRateRepository creates a new bank exchange rate repository
+func NewBankExchangeRateRepository(db *sqlx.DB) BankExchangeRateRepository {
+	return &bankExchangeRateRepository{db: db}
+}
+
+// Upsert replaces the latest rates of each bank and currency. Changed rates are
+// recorded in the history by a trigger.
+func (r *bankExchangeRateRepository) Upsert(ctx context.Context, rates []model.BankExchangeRate) error {
+	dbTx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	query := `
+		INSERT INTO bank_exchange_rates (
+			bank_code, bank_name, currency, currency_name, buy_cash, buy_transfer, sell, rate_date, scraped_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (bank_code, currency)
+		DO UPDATE SET
+			bank_name = EXCLUDED.bank_name,
+			currency_name = EXCLUDED.currency_name,
+			buy_cash = EXCLUDED.buy_cash,
+			buy_transfer = EXCLUDED.buy_transfer,
+			sell = EXCLUDED.sell,
+			rate_date = EXCLUDED.rate_date,
+			scraped_at = EXCLUDED.scraped_at,
+			updated_at = NOW()
+		RETURNING id, created_at, updated_at`
+
+	for i := range rates {
+		rate := &rates[i]
+		err := dbTx.QueryRowxContext(ctx, query,
+			rate.BankCode, rate.BankName, rate.Currency, rate.CurrencyName,
+			rate.BuyCash, rate.BuyTransfer, rate.Sell, rate.RateDate, rate.ScrapedAt,
+		).Scan(&rate.ID, &rate.CreatedAt, &rate.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("upsert %s %s rate: %w", rate.BankCode, rate.Currency, err)
+		}
+	}
+	return dbTx.Commit()
+}
+
+// List returns the latest exchange rates, optionally of one bank or currency
+func (r *bankExchangeRateRepository) List(ctx context.Context, bankCode, currency string) ([]model.BankExchangeRate, error) {
+	query := `
+		SELECT * FROM bank_exchange_rates
+		WHERE ($1 = '' OR bank_code = $1)
+		AND ($2 = '' OR currency = $2)
+		ORDER BY bank_code, currency`
+
+	rates := []model.BankExchangeRate{}
+	if err := r.db.SelectContext(ctx, &rates, query, bankCode, currency); err != nil {
+		return nil, fmt.Errorf("list exchange rates: %w", err)
+	}
+	return rates, nil
+}
+
+// GetHistory returns a bank's daily rates for a currency over the last days
+func (r *bankExchangeRateRepository) GetHistory(ctx context.Context, bankCode, currency string, days int) ([]ExchangeRateHistoryEntry, error) {
+	query := `
+		SELECT bank_code, currency, buy_cash, buy_transfer, sell, recorded_date
+		FROM bank_exchange_rate_history
+		WHERE bank_code = $1
+		AND currency = $2
+		AND recorded_date >= CURRENT_DATE - $3 * INTERVAL '1 day'
+		ORDER BY recorded_date ASC`
+
+	history := []ExchangeRateHistoryEntry{}
+	if err := r.db.SelectContext(ctx, &history, query, bankCode, currency, days); err != nil {
+		return nil, fmt.Errorf("get exchange rate history: %w", err)
+	}
+	return history, nil
+}
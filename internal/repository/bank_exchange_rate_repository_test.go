@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+func TestBankExchangeRateRepository_Upsert(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewBankExchangeRateRepository(db)
+
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	transfer, sell := decimal.NewFromInt(25000), decimal.NewFromInt(25400)
+	rates := []model.BankExchangeRate{
+		{BankCode: "vcb", BankName: "Vietcombank", Currency: "USD", BuyTransfer: &transfer, Sell: &sell, RateDate: date, ScrapedAt: date},
+	}
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO bank_exchange_rates .* ON CONFLICT \(bank_code, currency\)`).
+		WithArgs("vcb", "Vietcombank", "USD", "", nil, &transfer, &sell, date, date).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(7, now, now))
+	mock.ExpectCommit()
+
+	err := repo.Upsert(context.Background(), rates)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), rates[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBankExchangeRateRepository_List(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewBankExchangeRateRepository(db)
+
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "bank_code", "bank_name", "currency", "currency_name", "buy_cash", "buy_transfer", "sell", "rate_date", "scraped_at", "created_at", "updated_at"}
+
+	mock.ExpectQuery(`SELECT \* FROM bank_exchange_rates`).
+		WithArgs("vcb", "").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "vcb", "Vietcombank", "KWD", "KUWAITI DINAR", nil, "82797.35", "86110.06", date, date, date, date))
+
+	rates, err := repo.List(context.Background(), "vcb", "")
+
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	assert.Nil(t, rates[0].BuyCash)
+	assert.Equal(t, "82797.35", rates[0].BuyTransfer.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -22,14 +23,16 @@ func NewBudgetRepository(db *sqlx.DB) *BudgetRepository {
 
 func (r *BudgetRepository) Create(ctx context.Context, budget *model.Budget) error {
 	query := `
-		INSERT INTO budgets (id, user_id, category, amount, currency, period, start_date, end_date, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		INSERT INTO budgets (id, user_id, category, amount, currency, period, start_date, end_date,
+			enable_rollover, max_rollover_amount, rollover_overspend, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW(), NOW())
 		RETURNING created_at, updated_at`
 
 	budget.ID = uuid.New()
 	return r.db.QueryRowxContext(ctx, query,
 		budget.ID, budget.UserID, budget.Category, budget.Amount, budget.Currency,
 		budget.Period, budget.StartDate, budget.EndDate,
+		budget.EnableRollover, budget.MaxRolloverAmount, budget.RolloverOverspend,
 	).Scan(&budget.CreatedAt, &budget.UpdatedAt)
 }
 
@@ -50,17 +53,22 @@ func (r *BudgetRepository) List(ctx context.Context, userID uuid.UUID) ([]model.
 	return budgets, err
 }
 
+// Update saves the budget settings. Turning rollover off clears the current rollover amount.
 func (r *BudgetRepository) Update(ctx context.Context, budget *model.Budget) error {
 	query := `
 		UPDATE budgets 
-		SET category = $2, amount = $3, currency = $4, period = $5, start_date = $6, end_date = $7, updated_at = NOW()
+		SET category = $2, amount = $3, currency = $4, period = $5, start_date = $6, end_date = $7,
+			enable_rollover = $9, max_rollover_amount = $10, rollover_overspend = $11,
+			rollover_amount = CASE WHEN $9 THEN rollover_amount ELSE 0 END,
+			updated_at = NOW()
 		WHERE id = $1 AND user_id = $8
-		RETURNING updated_at`
+		RETURNING rollover_amount, updated_at`
 	result := r.db.QueryRowxContext(ctx, query,
 		budget.ID, budget.Category, budget.Amount, budget.Currency,
 		budget.Period, budget.StartDate, budget.EndDate, budget.UserID,
+		budget.EnableRollover, budget.MaxRolloverAmount, budget.RolloverOverspend,
 	)
-	return result.Scan(&budget.UpdatedAt)
+	return result.Scan(&budget.RolloverAmount, &budget.UpdatedAt)
 }
 
 func (r *BudgetRepository) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
@@ -89,3 +97,65 @@ func (r *BudgetRepository) GetActiveForUser(ctx context.Context, userID uuid.UUI
 	err := r.db.SelectContext(ctx, &budgets, query, userID)
 	return budgets, err
 }
+
+// ListRolloverEnabled returns all budgets with rollover enabled that have not ended
+// before the given date.
+func (r *BudgetRepository) ListRolloverEnabled(ctx context.Context, since time.Time) ([]model.Budget, error) {
+	var budgets []model.Budget
+	query := `
+		SELECT * FROM budgets
+		WHERE enable_rollover = TRUE
+		AND (end_date IS NULL OR end_date >= $1)
+		ORDER BY user_id, category`
+	err := r.db.SelectContext(ctx, &budgets, query, since)
+	return budgets, err
+}
+
+// ListRollovers returns a budget's rollover history, newest period first.
+func (r *BudgetRepository) ListRollovers(ctx context.Context, budgetID uuid.UUID) ([]model.BudgetRollover, error) {
+	rollovers := []model.BudgetRollover{}
+	query := `
+		SELECT * FROM budget_rollovers
+		WHERE budget_id = $1
+		ORDER BY from_period_start DESC`
+	err := r.db.SelectContext(ctx, &rollovers, query, budgetID)
+	return rollovers, err
+}
+
+// ApplyRollover records a rollover and sets it as the budget's current rollover amount.
+// It returns false without changing anything if the period was already closed.
+func (r *BudgetRepository) ApplyRollover(ctx context.Context, rollover *model.BudgetRollover) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO budget_rollovers (id, budget_id, from_period_start, from_period_end, to_period_start, amount, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (budget_id, from_period_start) DO NOTHING
+		RETURNING created_at`
+
+	rollover.ID = uuid.New()
+	err = tx.QueryRowxContext(ctx, query,
+		rollover.ID, rollover.BudgetID, rollover.FromPeriodStart, rollover.FromPeriodEnd,
+		rollover.ToPeriodStart, rollover.Amount,
+	).Scan(&rollover.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE budgets SET rollover_amount = $2, updated_at = NOW() WHERE id = $1`,
+		rollover.BudgetID, rollover.Amount,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
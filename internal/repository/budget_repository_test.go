@@ -47,7 +47,7 @@ func TestBudgetRepository_Create(t *testing.T) {
 	rows := sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now)
 
 	mock.ExpectQuery(`INSERT INTO budgets`).
-		WithArgs(sqlmock.AnyArg(), budget.UserID, budget.Category, budget.Amount, budget.Currency, budget.Period, budget.StartDate, nil).
+		WithArgs(sqlmock.AnyArg(), budget.UserID, budget.Category, budget.Amount, budget.Currency, budget.Period, budget.StartDate, nil, false, nil, false).
 		WillReturnRows(rows)
 
 	err := repo.Create(ctx, budget)
@@ -166,10 +166,10 @@ func TestBudgetRepository_Update(t *testing.T) {
 	}
 
 	now := time.Now()
-	rows := sqlmock.NewRows([]string{"updated_at"}).AddRow(now)
+	rows := sqlmock.NewRows([]string{"rollover_amount", "updated_at"}).AddRow("0", now)
 
 	mock.ExpectQuery(`UPDATE budgets`).
-		WithArgs(budget.ID, budget.Category, budget.Amount, budget.Currency, budget.Period, budget.StartDate, nil, budget.UserID).
+		WithArgs(budget.ID, budget.Category, budget.Amount, budget.Currency, budget.Period, budget.StartDate, nil, budget.UserID, false, nil, false).
 		WillReturnRows(rows)
 
 	err := repo.Update(ctx, budget)
@@ -263,6 +263,111 @@ func TestBudgetRepository_GetActiveForUser(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestBudgetRepository_ApplyRollover(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		setupMock   func(sqlmock.Sqlmock, *model.BudgetRollover)
+		wantApplied bool
+		wantErr     bool
+	}{
+		{
+			name: "records rollover and updates budget",
+			setupMock: func(mock sqlmock.Sqlmock, r *model.BudgetRollover) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO budget_rollovers`).
+					WithArgs(sqlmock.AnyArg(), r.BudgetID, r.FromPeriodStart, r.FromPeriodEnd, r.ToPeriodStart, r.Amount).
+					WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+				mock.ExpectExec(`UPDATE budgets SET rollover_amount`).
+					WithArgs(r.BudgetID, r.Amount).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantApplied: true,
+		},
+		{
+			name: "already closed period is a no-op",
+			setupMock: func(mock sqlmock.Sqlmock, r *model.BudgetRollover) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO budget_rollovers`).
+					WillReturnRows(sqlmock.NewRows([]string{"created_at"}))
+				mock.ExpectRollback()
+			},
+			wantApplied: false,
+		},
+		{
+			name: "update error rolls back",
+			setupMock: func(mock sqlmock.Sqlmock, r *model.BudgetRollover) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`INSERT INTO budget_rollovers`).
+					WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+				mock.ExpectExec(`UPDATE budgets SET rollover_amount`).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockDB, mock, _ := sqlmock.New()
+			defer func() { _ = mockDB.Close() }()
+			db := sqlx.NewDb(mockDB, "sqlmock")
+			repo := NewBudgetRepository(db)
+
+			start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+			rollover := &model.BudgetRollover{
+				BudgetID:        uuid.New(),
+				FromPeriodStart: start,
+				FromPeriodEnd:   start.AddDate(0, 1, 0).Add(-time.Second),
+				ToPeriodStart:   start.AddDate(0, 1, 0),
+				Amount:          decimal.NewFromFloat(125.50),
+			}
+			tt.setupMock(mock, rollover)
+
+			applied, err := repo.ApplyRollover(context.Background(), rollover)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantApplied, applied)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestBudgetRepository_ListRollovers(t *testing.T) {
+	t.Parallel()
+
+	mockDB, mock, _ := sqlmock.New()
+	defer func() { _ = mockDB.Close() }()
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	repo := NewBudgetRepository(db)
+
+	budgetID := uuid.New()
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "budget_id", "from_period_start", "from_period_end", "to_period_start", "amount", "created_at"}).
+		AddRow(uuid.New(), budgetID, start, start.AddDate(0, 1, -1), start.AddDate(0, 1, 0), "125.50", time.Now())
+
+	mock.ExpectQuery(`SELECT \* FROM budget_rollovers`).
+		WithArgs(budgetID).
+		WillReturnRows(rows)
+
+	rollovers, err := repo.ListRollovers(context.Background(), budgetID)
+
+	assert.NoError(t, err)
+	assert.Len(t, rollovers, 1)
+	assert.True(t, rollovers[0].Amount.Equal(decimal.NewFromFloat(125.50)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestErrBudgetNotFound(t *testing.T) {
 	t.Parallel()
 
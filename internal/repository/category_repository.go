@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+var (
+	// ErrCategoryNotFound is returned when a category does not exist or belongs to another user
+	ErrCategoryNotFound = errors.New("category not found")
+	// ErrCategoryExists is returned when the user already has a category of that type with the name
+	ErrCategoryExists = errors.New("category already exists")
+	// ErrCategoryBudgetCurrency is returned when moving a category's budgets would
+	// fold one into a budget for the same period in another currency
+	ErrCategoryBudgetCurrency = errors.New("both categories have a budget for the same period in different currencies")
+)
+
+// CategoryRepository stores the user's categories. Transactions, transaction
+// splits, budgets, recurring transactions and categorization rules refer to a
+// category by name, so renaming and merging rewrite them in the same database
+// transaction.
+type CategoryRepository interface {
+	Create(ctx context.Context, category *model.Category) error
+	GetByID(ctx context.Context, userID, id uuid.UUID) (*model.Category, error)
+	List(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]model.Category, error)
+	HasChildren(ctx context.Context, userID, id uuid.UUID) (bool, error)
+	Update(ctx context.Context, category *model.Category) error
+	Merge(ctx context.Context, source, target *model.Category) error
+	Seed(ctx context.Context, userID uuid.UUID, defaults []model.Category) error
+}
+
+type categoryRepository struct {
+	db *sqlx.DB
+}
+
+// NewCategoryRepository creates a new category repository
+func NewCategoryRepository(db *sqlx.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+const insertCategoryQuery = `
+	INSERT INTO categories (id, user_id, parent_id, name, type, icon, color, archived, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())`
+
+func (r *categoryRepository) Create(ctx context.Context, category *model.Category) error {
+	category.ID = uuid.New()
+	err := r.db.QueryRowxContext(ctx, insertCategoryQuery+` RETURNING created_at, updated_at`,
+		category.ID, category.UserID, category.ParentID, category.Name, category.Type,
+		category.Icon, category.Color, category.Archived,
+	).Scan(&category.CreatedAt, &category.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrCategoryExists
+	}
+	return err
+}
+
+func (r *categoryRepository) GetByID(ctx context.Context, userID, id uuid.UUID) (*model.Category, error) {
+	var category model.Category
+	err := r.db.GetContext(ctx, &category, `SELECT * FROM categories WHERE id = $1 AND user_id = $2`, id, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// List returns the user's categories with each top-level category followed by its
+// subcategories
+func (r *categoryRepository) List(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]model.Category, error) {
+	query := `
+		SELECT c.*
+		FROM categories c
+		LEFT JOIN categories p ON p.id = c.parent_id
+		WHERE c.user_id = $1 AND ($2::boolean OR NOT c.archived)
+		ORDER BY c.type, COALESCE(p.name, c.name), c.parent_id IS NOT NULL, c.name`
+
+	var categories []model.Category
+	err := r.db.SelectContext(ctx, &categories, query, userID, includeArchived)
+	return categories, err
+}
+
+func (r *categoryRepository) HasChildren(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists,
+		`SELECT EXISTS (SELECT 1 FROM categories WHERE parent_id = $1 AND user_id = $2)`, id, userID)
+	return exists, err
+}
+
+// Update saves a category. When the name changes, everything filed under the old
+// name is moved to the new one.
+func (r *categoryRepository) Update(ctx context.Context, category *model.Category) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var oldName string
+	err = tx.GetContext(ctx, &oldName,
+		`SELECT name FROM categories WHERE id = $1 AND user_id = $2 FOR UPDATE`, category.ID, category.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCategoryNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRowxContext(ctx, `
+		UPDATE categories
+		SET parent_id = $3, name = $4, icon = $5, color = $6, archived = $7, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+		RETURNING updated_at`,
+		category.ID, category.UserID, category.ParentID, category.Name,
+		category.Icon, category.Color, category.Archived,
+	).Scan(&category.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrCategoryExists
+	}
+	if err != nil {
+		return err
+	}
+
+	if oldName != category.Name {
+		if err := moveCategoryReferences(ctx, tx, category.UserID, category.Type, oldName, category.Name); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Merge moves everything filed under source to target and deletes source. The
+// subcategories of source move under target, or under target's parent when
+// target is itself a subcategory.
+func (r *categoryRepository) Merge(ctx context.Context, source, target *model.Category) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := moveCategoryReferences(ctx, tx, source.UserID, source.Type, source.Name, target.Name); err != nil {
+		return err
+	}
+
+	newParent := target.ID
+	if target.ParentID != nil {
+		if *target.ParentID == source.ID {
+			// Merging a category into one of its own subcategories promotes the subcategory
+			if _, err := tx.ExecContext(ctx,
+				`UPDATE categories SET parent_id = NULL, updated_at = NOW() WHERE id = $1`, target.ID); err != nil {
+				return err
+			}
+			target.ParentID = nil
+		} else {
+			newParent = *target.ParentID
+		}
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE categories SET parent_id = $2, updated_at = NOW() WHERE parent_id = $1`, source.ID, newParent); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM categories WHERE id = $1 AND user_id = $2`, source.ID, source.UserID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrCategoryNotFound
+	}
+	return tx.Commit()
+}
+
+// Seed gives a user the default categories plus any category name they already
+// use. It does nothing if the user already has categories, so categories they
+// merged away do not come back.
+func (r *categoryRepository) Seed(ctx context.Context, userID uuid.UUID, defaults []model.Category) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var seeded bool
+	if err := tx.GetContext(ctx, &seeded,
+		`SELECT EXISTS (SELECT 1 FROM categories WHERE user_id = $1)`, userID); err != nil {
+		return err
+	}
+	if seeded {
+		return nil
+	}
+
+	var inUse []model.Category
+	err = tx.SelectContext(ctx, &inUse, `
+		SELECT type, category AS name FROM transactions WHERE user_id = $1
+		UNION
+		SELECT t.type, s.category FROM transaction_splits s
+		JOIN transactions t ON t.id = s.transaction_id
+		WHERE t.user_id = $1
+		UNION
+		SELECT type, category FROM recurring_transactions WHERE user_id = $1
+		UNION
+		SELECT 'expense', category FROM budgets WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, category := range append(defaults, inUse...) {
+		if _, err := tx.ExecContext(ctx, insertCategoryQuery+` ON CONFLICT (user_id, type, name) DO NOTHING`,
+			uuid.New(), userID, nil, category.Name, category.Type, category.Icon, category.Color, false,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// moveCategoryReferences refiles everything of the given type under category from
+// to category to. Budgets only track expenses; rules for any type follow too. A
+// budget of from for a period to already has a budget for is folded into that
+// budget, adding its amounts. Returns ErrCategoryBudgetCurrency, changing
+// nothing, if the two budgets are in different currencies.
+func moveCategoryReferences(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, categoryType model.TransactionType, from, to string) error {
+	queries := []string{
+		`UPDATE transactions SET category = $4, updated_at = NOW()
+		WHERE user_id = $1 AND type = $2 AND category = $3`,
+		`UPDATE transaction_splits s SET category = $4
+		FROM transactions t
+		WHERE s.transaction_id = t.id AND t.user_id = $1 AND t.type = $2 AND s.category = $3`,
+		`UPDATE recurring_transactions SET category = $4, updated_at = NOW()
+		WHERE user_id = $1 AND type = $2 AND category = $3`,
+		`UPDATE categorization_rules SET set_category = $4, updated_at = NOW()
+		WHERE user_id = $1 AND (transaction_type IS NULL OR transaction_type = $2) AND set_category = $3`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, userID, categoryType, from, to); err != nil {
+			return err
+		}
+	}
+
+	if categoryType == model.TransactionTypeExpense {
+		var currencyClash bool
+		if err := tx.GetContext(ctx, &currencyClash, `
+			SELECT EXISTS (
+				SELECT 1 FROM budgets s
+				JOIN budgets t ON t.user_id = s.user_id AND t.category = $3 AND t.period = s.period
+				WHERE s.user_id = $1 AND s.category = $2 AND s.currency IS DISTINCT FROM t.currency
+			)`, userID, from, to); err != nil {
+			return err
+		}
+		if currencyClash {
+			return ErrCategoryBudgetCurrency
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			WITH clashing AS (
+				DELETE FROM budgets s
+				USING budgets t
+				WHERE s.user_id = $1 AND s.category = $2
+				AND t.user_id = $1 AND t.category = $3 AND t.period = s.period
+				AND s.currency IS NOT DISTINCT FROM t.currency
+				RETURNING t.id AS target_id, s.amount, s.rollover_amount
+			)
+			UPDATE budgets b
+			SET amount = b.amount + c.amount, rollover_amount = b.rollover_amount + c.rollover_amount, updated_at = NOW()
+			FROM (
+				SELECT target_id, SUM(amount) AS amount, SUM(rollover_amount) AS rollover_amount
+				FROM clashing
+				GROUP BY target_id
+			) c
+			WHERE b.id = c.target_id`,
+			userID, from, to); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE budgets SET category = $3, updated_at = NOW() WHERE user_id = $1 AND category = $2`,
+			userID, from, to); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+func TestCategoryRepository_Update(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		newName   string
+		setupMock func(mock sqlmock.Sqlmock, category *model.Category)
+		wantErr   error
+	}{
+		{
+			name:    "rename moves transactions, splits, recurring and budgets",
+			newName: "Groceries",
+			setupMock: func(mock sqlmock.Sqlmock, c *model.Category) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT name FROM categories WHERE id = \$1 AND user_id = \$2 FOR UPDATE`).
+					WithArgs(c.ID, c.UserID).
+					WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Food & Dining"))
+				mock.ExpectQuery(`UPDATE categories`).
+					WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(c.CreatedAt))
+				for _, table := range []string{"transactions", "transaction_splits", "recurring_transactions", "categorization_rules"} {
+					mock.ExpectExec(`UPDATE `+table).
+						WithArgs(c.UserID, model.TransactionTypeExpense, "Food & Dining", "Groceries").
+						WillReturnResult(sqlmock.NewResult(0, 3))
+				}
+				mock.ExpectQuery(`SELECT EXISTS`).
+					WithArgs(c.UserID, "Food & Dining", "Groceries").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectExec(`WITH clashing AS \(\s+DELETE FROM budgets`).
+					WithArgs(c.UserID, "Food & Dining", "Groceries").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`UPDATE budgets`).
+					WithArgs(c.UserID, "Food & Dining", "Groceries").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "same name only updates the category",
+			newName: "Food & Dining",
+			setupMock: func(mock sqlmock.Sqlmock, c *model.Category) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT name FROM categories`).
+					WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Food & Dining"))
+				mock.ExpectQuery(`UPDATE categories`).
+					WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(c.CreatedAt))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "name taken",
+			newName: "Shopping",
+			setupMock: func(mock sqlmock.Sqlmock, c *model.Category) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT name FROM categories`).
+					WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Food & Dining"))
+				mock.ExpectQuery(`UPDATE categories`).
+					WillReturnError(&pq.Error{Code: "23505"})
+				mock.ExpectRollback()
+			},
+			wantErr: ErrCategoryExists,
+		},
+		{
+			name:    "not found",
+			newName: "Groceries",
+			setupMock: func(mock sqlmock.Sqlmock, c *model.Category) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT name FROM categories`).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr: ErrCategoryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := newMockDB(t)
+			defer func() { _ = db.Close() }()
+			repo := NewCategoryRepository(db)
+
+			category := &model.Category{ID: uuid.New(), UserID: uuid.New(), Name: tt.newName, Type: model.TransactionTypeExpense}
+			tt.setupMock(mock, category)
+
+			err := repo.Update(context.Background(), category)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCategoryRepository_Merge(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	freelance := &model.Category{ID: uuid.New(), UserID: userID, Name: "Freelance", Type: model.TransactionTypeIncome}
+
+	t.Run("children move under the target", func(t *testing.T) {
+		t.Parallel()
+
+		db, mock := newMockDB(t)
+		defer func() { _ = db.Close() }()
+		repo := NewCategoryRepository(db)
+		target := &model.Category{ID: uuid.New(), UserID: userID, Name: "Side Jobs", Type: model.TransactionTypeIncome}
+
+		mock.ExpectBegin()
+		for _, table := range []string{"transactions", "transaction_splits", "recurring_transactions", "categorization_rules"} {
+			mock.ExpectExec(`UPDATE `+table).
+				WithArgs(userID, model.TransactionTypeIncome, "Freelance", "Side Jobs").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		mock.ExpectExec(`UPDATE categories SET parent_id = \$2, updated_at = NOW\(\) WHERE parent_id = \$1`).
+			WithArgs(freelance.ID, target.ID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`DELETE FROM categories WHERE id = \$1 AND user_id = \$2`).
+			WithArgs(freelance.ID, userID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.Merge(context.Background(), freelance, target))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("budgets of a period the target has fold into its budget", func(t *testing.T) {
+		t.Parallel()
+
+		db, mock := newMockDB(t)
+		defer func() { _ = db.Close() }()
+		repo := NewCategoryRepository(db)
+		coffee := &model.Category{ID: uuid.New(), UserID: userID, Name: "Coffee", Type: model.TransactionTypeExpense}
+		target := &model.Category{ID: uuid.New(), UserID: userID, Name: "Food & Dining", Type: model.TransactionTypeExpense}
+
+		mock.ExpectBegin()
+		for i := 0; i < 4; i++ {
+			mock.ExpectExec(`UPDATE`).WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+		mock.ExpectQuery(`SELECT EXISTS`).
+			WithArgs(userID, "Coffee", "Food & Dining").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectExec(`DELETE FROM budgets s\s+USING budgets t\s+WHERE s.user_id = \$1 AND s.category = \$2\s+AND t.user_id = \$1 AND t.category = \$3 AND t.period = s.period\s+AND s.currency IS NOT DISTINCT FROM t.currency`).
+			WithArgs(userID, "Coffee", "Food & Dining").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE budgets SET category = \$3`).
+			WithArgs(userID, "Coffee", "Food & Dining").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE categories SET parent_id = \$2`).
+			WithArgs(coffee.ID, target.ID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM categories`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.Merge(context.Background(), coffee, target))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("budgets of a period the target has in another currency block the merge", func(t *testing.T) {
+		t.Parallel()
+
+		db, mock := newMockDB(t)
+		defer func() { _ = db.Close() }()
+		repo := NewCategoryRepository(db)
+		coffee := &model.Category{ID: uuid.New(), UserID: userID, Name: "Coffee", Type: model.TransactionTypeExpense}
+		target := &model.Category{ID: uuid.New(), UserID: userID, Name: "Food & Dining", Type: model.TransactionTypeExpense}
+
+		mock.ExpectBegin()
+		for i := 0; i < 4; i++ {
+			mock.ExpectExec(`UPDATE`).WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+		mock.ExpectQuery(`SELECT EXISTS \(\s+SELECT 1 FROM budgets s\s+JOIN budgets t ON .* t.period = s.period\s+WHERE .* s.currency IS DISTINCT FROM t.currency`).
+			WithArgs(userID, "Coffee", "Food & Dining").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, repo.Merge(context.Background(), coffee, target), ErrCategoryBudgetCurrency)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("merging into a subcategory promotes it", func(t *testing.T) {
+		t.Parallel()
+
+		db, mock := newMockDB(t)
+		defer func() { _ = db.Close() }()
+		repo := NewCategoryRepository(db)
+		target := &model.Category{ID: uuid.New(), UserID: userID, ParentID: &freelance.ID, Name: "Design Gigs", Type: model.TransactionTypeIncome}
+
+		mock.ExpectBegin()
+		for i := 0; i < 4; i++ {
+			mock.ExpectExec(`UPDATE`).WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+		mock.ExpectExec(`UPDATE categories SET parent_id = NULL`).
+			WithArgs(target.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE categories SET parent_id = \$2`).
+			WithArgs(freelance.ID, target.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`DELETE FROM categories`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.Merge(context.Background(), freelance, target))
+		assert.Nil(t, target.ParentID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCategoryRepository_Seed_AlreadySeeded(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewCategoryRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM categories WHERE user_id = \$1\)`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+
+	err := repo.Seed(context.Background(), userID, []model.Category{{Name: "Food", Type: model.TransactionTypeExpense}})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryRepository_Seed(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewCategoryRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT EXISTS`).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery(`SELECT type, category AS name FROM transactions`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "name"}).AddRow("expense", "Cà phê"))
+	for _, name := range []string{"Food", "Salary", "Cà phê"} {
+		mock.ExpectExec(`INSERT INTO categories .* ON CONFLICT \(user_id, type, name\) DO NOTHING`).
+			WithArgs(sqlmock.AnyArg(), userID, nil, name, sqlmock.AnyArg(), "", "", false).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+
+	err := repo.Seed(context.Background(), userID, []model.Category{
+		{Name: "Food", Type: model.TransactionTypeExpense},
+		{Name: "Salary", Type: model.TransactionTypeIncome},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
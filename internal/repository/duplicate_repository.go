@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+// DuplicateCandidate is a pair of the user's transactions that may be the same
+// one recorded twice. TransactionID is the lower of the two IDs.
+type DuplicateCandidate struct {
+	TransactionID         uuid.UUID `db:"transaction_id"`
+	DuplicateID           uuid.UUID `db:"duplicate_id"`
+	DescriptionSimilarity float64   `db:"description_similarity"` // pg_trgm similarity, 0 to 1
+}
+
+// DuplicateRepository finds suspected duplicate transactions and stores what the
+// user decided about them
+type DuplicateRepository interface {
+	Candidates(ctx context.Context, userID uuid.UUID, since time.Time, maxDaysApart int, amountTolerance decimal.Decimal, limit int) ([]DuplicateCandidate, error)
+	TransactionsByIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) ([]model.Transaction, error)
+	SaveReview(ctx context.Context, review *model.DuplicateReview) error
+	Merge(ctx context.Context, userID, keepID, dropID uuid.UUID) error
+}
+
+type duplicateRepository struct {
+	db *sqlx.DB
+}
+
+// NewDuplicateRepository creates a new duplicate repository
+func NewDuplicateRepository(db *sqlx.DB) DuplicateRepository {
+	return &duplicateRepository{db: db}
+}
+
+// Candidates returns pairs of transactions of the same type and currency, at
+// most maxDaysApart days apart and with amounts within amountTolerance (a
+// fraction of the larger amount), where at least one is dated since. Pairs the
+// user reviewed are left out, as are pairs that both carry a bank's ID: the bank
+// says those are different transactions. The most recent pairs come first.
+func (r *duplicateRepository) Candidates(ctx context.Context, userID uuid.UUID, since time.Time, maxDaysApart int, amountTolerance decimal.Decimal, limit int) ([]DuplicateCandidate, error) {
+	query := `
+		SELECT a.id AS transaction_id, b.id AS duplicate_id,
+			similarity(coalesce(a.description, ''), coalesce(b.description, '')) AS description_similarity
+		FROM transactions a
+		JOIN transactions b ON b.user_id = a.user_id AND b.type = a.type AND b.currency = a.currency
+			AND b.id > a.id
+			AND b.date BETWEEN a.date - $3::int AND a.date + $3::int
+			AND abs(b.amount - a.amount) <= greatest(a.amount, b.amount) * $4
+			AND (a.external_id IS NULL OR b.external_id IS NULL)
+		WHERE a.user_id = $1 AND (a.date >= $2 OR b.date >= $2)
+		AND NOT EXISTS (
+			SELECT 1 FROM duplicate_reviews dr
+			WHERE dr.user_id = $1 AND dr.transaction_id = a.id AND dr.duplicate_id = b.id
+		)
+		ORDER BY greatest(a.date, b.date) DESC, a.id, b.id
+		LIMIT $5`
+
+	var candidates []DuplicateCandidate
+	err := r.db.SelectContext(ctx, &candidates, query, userID, since, maxDaysApart, amountTolerance, limit)
+	return candidates, err
+}
+
+// TransactionsByIDs returns the user's transactions with the given IDs and their
+// tags. IDs of other users' transactions are ignored.
+func (r *duplicateRepository) TransactionsByIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) ([]model.Transaction, error) {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = id.String()
+	}
+
+	var transactions []model.Transaction
+	err := r.db.SelectContext(ctx, &transactions,
+		`SELECT * FROM transactions WHERE user_id = $1 AND id = ANY($2::uuid[])`, userID, pq.Array(keys))
+	if err != nil {
+		return nil, err
+	}
+
+	txIDs := make([]uuid.UUID, len(transactions))
+	for i, tx := range transactions {
+		txIDs[i] = tx.ID
+	}
+	tags, err := tagsByOwner(ctx, r.db, transactionTagLink, txIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range transactions {
+		transactions[i].Tags = tags[transactions[i].ID]
+	}
+	return transactions, nil
+}
+
+// SaveReview records the user's decision on a pair, replacing an earlier one
+func (r *duplicateRepository) SaveReview(ctx context.Context, review *model.DuplicateReview) error {
+	query := `
+		INSERT INTO duplicate_reviews (id, user_id, transaction_id, duplicate_id, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		ON CONFLICT (user_id, transaction_id, duplicate_id)
+		DO UPDATE SET status = EXCLUDED.status, updated_at = NOW()
+		RETURNING id, created_at, updated_at`
+
+	return r.db.QueryRowxContext(ctx, query,
+		uuid.New(), review.UserID, review.TransactionID, review.DuplicateID, review.Status,
+	).Scan(&review.ID, &review.CreatedAt, &review.UpdatedAt)
+}
+
+// Merge folds one transaction into another and deletes it. The kept transaction
+// gains the tags, attachments and savings contributions of the other, and its
+// description and bank's ID when it has none. The other is deleted before its
+// bank's ID moves, as a user's bank IDs are unique.
+func (r *duplicateRepository) Merge(ctx context.Context, userID, keepID, dropID uuid.UUID) error {
+	dbTx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	var locked []uuid.UUID
+	err = dbTx.SelectContext(ctx, &locked,
+		`SELECT id FROM transactions WHERE user_id = $1 AND id IN ($2, $3) FOR UPDATE`, userID, keepID, dropID)
+	if err != nil {
+		return err
+	}
+	if len(locked) != 2 {
+		return ErrTransactionNotFound
+	}
+
+	queries := []string{
+		`INSERT INTO transaction_tags (transaction_id, tag_id)
+		SELECT $1, tag_id FROM transaction_tags WHERE transaction_id = $2
+		ON CONFLICT DO NOTHING`,
+		`UPDATE attachments SET transaction_id = $1 WHERE transaction_id = $2`,
+		`UPDATE savings_contributions SET transaction_id = $1 WHERE transaction_id = $2`,
+	}
+	for _, query := range queries {
+		if _, err := dbTx.ExecContext(ctx, query, keepID, dropID); err != nil {
+			return err
+		}
+	}
+
+	var dropped struct {
+		Description *string `db:"description"`
+		ExternalID  *string `db:"external_id"`
+	}
+	err = dbTx.GetContext(ctx, &dropped,
+		`DELETE FROM transactions WHERE id = $1 RETURNING description, external_id`, dropID)
+	if err != nil {
+		return err
+	}
+
+	_, err = dbTx.ExecContext(ctx, `
+		UPDATE transactions SET
+			description = CASE WHEN btrim(coalesce(description, '')) = '' THEN $2 ELSE description END,
+			external_id = coalesce(external_id, $3),
+			updated_at = NOW()
+		WHERE id = $1
+		AND (btrim(coalesce(description, '')) = '' OR (external_id IS NULL AND $3::text IS NOT NULL))`,
+		keepID, dropped.Description, dropped.ExternalID)
+	if err != nil {
+		return err
+	}
+	return dbTx.Commit()
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+func TestDuplicateRepository_SaveReview(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewDuplicateRepository(db)
+
+	review := &model.DuplicateReview{
+		UserID:        uuid.New(),
+		TransactionID: uuid.New(),
+		DuplicateID:   uuid.New(),
+		Status:        model.DuplicateStatusNotDuplicate,
+	}
+	reviewID, now := uuid.New(), time.Now()
+
+	mock.ExpectQuery(`INSERT INTO duplicate_reviews .* ON CONFLICT \(user_id, transaction_id, duplicate_id\)`).
+		WithArgs(sqlmock.AnyArg(), review.UserID, review.TransactionID, review.DuplicateID, "not_duplicate").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(reviewID, now, now))
+
+	err := repo.SaveReview(context.Background(), review)
+
+	assert.NoError(t, err)
+	assert.Equal(t, reviewID, review.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDuplicateRepository_Merge(t *testing.T) {
+	t.Parallel()
+
+	userID, keepID, dropID := uuid.New(), uuid.New(), uuid.New()
+	bankID := "ofx:123:FIT42"
+
+	tests := []struct {
+		name        string
+		locked      []uuid.UUID
+		description *string
+		externalID  *string
+		wantErr     error
+	}{
+		{name: "success", locked: []uuid.UUID{keepID, dropID}},
+		{name: "moves the bank's ID after deleting", locked: []uuid.UUID{keepID, dropID}, externalID: &bankID},
+		{name: "not found", locked: []uuid.UUID{keepID}, wantErr: ErrTransactionNotFound},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := newMockDB(t)
+			defer func() { _ = db.Close() }()
+			repo := NewDuplicateRepository(db)
+
+			rows := sqlmock.NewRows([]string{"id"})
+			for _, id := range tt.locked {
+				rows.AddRow(id)
+			}
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT id FROM transactions WHERE user_id = \$1 AND id IN \(\$2, \$3\) FOR UPDATE`).
+				WithArgs(userID, keepID, dropID).
+				WillReturnRows(rows)
+			if tt.wantErr == nil {
+				for _, query := range []string{
+					`INSERT INTO transaction_tags`,
+					`UPDATE attachments`,
+					`UPDATE savings_contributions`,
+				} {
+					mock.ExpectExec(query).WithArgs(keepID, dropID).WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+				mock.ExpectQuery(`DELETE FROM transactions WHERE id = \$1 RETURNING description, external_id`).
+					WithArgs(dropID).
+					WillReturnRows(sqlmock.NewRows([]string{"description", "external_id"}).AddRow(tt.description, tt.externalID))
+				mock.ExpectExec(`UPDATE transactions SET\s+description = .*external_id = coalesce\(external_id, \$3\)`).
+					WithArgs(keepID, tt.description, tt.externalID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			err := repo.Merge(context.Background(), userID, keepID, dropID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
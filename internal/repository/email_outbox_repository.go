@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+// EmailOutboxRepository persists outgoing email until it is delivered.
+type EmailOutboxRepository struct {
+	db *sqlx.DB
+}
+
+// NewEmailOutboxRepository creates a new email outbox repository.
+func NewEmailOutboxRepository(db *sqlx.DB) *EmailOutboxRepository {
+	return &EmailOutboxRepository{db: db}
+}
+
+const insertOutboxEmailQuery = `
+	INSERT INTO email_outbox (id, to_address, subject, text_body, html_body, template, status, next_attempt_at, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, 'pending', NOW(), NOW())
+	RETURNING status, next_attempt_at, created_at`
+
+// Create queues an email for delivery.
+func (r *EmailOutboxRepository) Create(ctx context.Context, email *model.OutboxEmail) error {
+	return r.create(ctx, r.db, email)
+}
+
+// CreateTx queues an email inside an existing transaction, so it is only sent
+// if the surrounding change commits.
+func (r *EmailOutboxRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, email *model.OutboxEmail) error {
+	return r.create(ctx, tx, email)
+}
+
+func (r *EmailOutboxRepository) create(ctx context.Context, q sqlx.QueryerContext, email *model.OutboxEmail) error {
+	if email.ID == uuid.Nil {
+		email.ID = uuid.New()
+	}
+	return q.QueryRowxContext(ctx, insertOutboxEmailQuery,
+		email.ID, email.ToAddress, email.Subject, email.TextBody, email.HTMLBody, email.Template,
+	).Scan(&email.Status, &email.NextAttemptAt, &email.CreatedAt)
+}
+
+// ClaimDue marks up to limit due emails as sending and returns them. A claimed
+// email is leased until lease expires; if the process dies before recording the
+// outcome, the email becomes due again.
+func (r *EmailOutboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxEmail, error) {
+	var emails []model.OutboxEmail
+	query := `
+		UPDATE email_outbox
+		SET status = 'sending', attempts = attempts + 1, next_attempt_at = NOW() + $2 * INTERVAL '1 second'
+		WHERE id IN (
+			SELECT id FROM email_outbox
+			WHERE status IN ('pending', 'sending')
+			AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`
+
+	err := r.db.SelectContext(ctx, &emails, query, limit, lease.Seconds())
+	return emails, err
+}
+
+// MarkSent records a successful delivery.
+func (r *EmailOutboxRepository) MarkSent(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE email_outbox SET status = 'sent', sent_at = NOW(), last_error = NULL WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// MarkRetry records a failed attempt and schedules the next one.
+func (r *EmailOutboxRepository) MarkRetry(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time, lastError string) error {
+	query := `UPDATE email_outbox SET status = 'pending', next_attempt_at = $2, last_error = $3 WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, nextAttemptAt, lastError)
+	return err
+}
+
+// MarkFailed gives up on an email after too many attempts.
+func (r *EmailOutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error {
+	query := `UPDATE email_outbox SET status = 'failed', last_error = $2 WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, lastError)
+	return err
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+// ErrExchangeRateNotFound is returned when no rate is stored for a currency pair
+var ErrExchangeRateNotFound = errors.New("exchange rate not found")
+
+// laterRateDays is how many days after a date FindRate looks for a rate when
+// there is none from before it. Older dates stay without a rate.
+const laterRateDays = 7
+
+// UnconvertedTransaction is a transaction without an amount in its owner's
+// current currency
+type UnconvertedTransaction struct {
+	ID           uuid.UUID       `db:"id"`
+	UserID       uuid.UUID       `db:"user_id"`
+	Amount       decimal.Decimal `db:"amount"`
+	Currency     string          `db:"currency"`
+	Date         time.Time       `db:"date"`
+	BaseCurrency string          `db:"base_currency"` // The user's currency
+}
+
+// ExchangeRateRepository stores exchange rates and the base currency amounts of
+// transactions converted with them
+type ExchangeRateRepository interface {
+	SaveRates(ctx context.Context, rates []model.ExchangeRate) error
+	FindRate(ctx context.Context, from, to string, date time.Time) (*model.ExchangeRate, error)
+	UserCurrency(ctx context.Context, userID uuid.UUID) (string, error)
+	Unconverted(ctx context.Context, userID *uuid.UUID, after uuid.UUID, limit int) ([]UnconvertedTransaction, error)
+	SetBaseAmount(ctx context.Context, tx UnconvertedTransaction, rate, baseAmount decimal.Decimal) error
+}
+
+type exchangeRateRepository struct {
+	db *sqlx.DB
+}
+
+// NewExchangeRateRepository creates a new exchange rate repository
+func NewExchangeRateRepository(db *sqlx.DB) ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+// SaveRates inserts the rates, replacing any stored for the same pair, day and
+// source
+func (r *exchangeRateRepository) SaveRates(ctx context.Context, rates []model.ExchangeRate) error {
+	dbTx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	query := `
+		INSERT INTO exchange_rates (id, from_currency, to_currency, rate, rate_date, source, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		ON CONFLICT (from_currency, to_currency, rate_date, source)
+		DO UPDATE SET rate = EXCLUDED.rate, updated_at = NOW()
+		RETURNING id, created_at, updated_at`
+
+	for i := range rates {
+		rate := &rates[i]
+		if rate.Source == "" {
+			rate.Source = model.ExchangeRateSourceManual
+		}
+		err := dbTx.QueryRowxContext(ctx, query,
+			uuid.New(), rate.FromCurrency, rate.ToCurrency, rate.Rate, rate.RateDate, rate.Source,
+		).Scan(&rate.ID, &rate.CreatedAt, &rate.UpdatedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return dbTx.Commit()
+}
+
+// FindRate returns the latest rate from one currency to another on or before
+// the date, or the earliest one up to laterRateDays after it when none is that old
+func (r *exchangeRateRepository) FindRate(ctx context.Context, from, to string, date time.Time) (*model.ExchangeRate, error) {
+	query := `
+		SELECT * FROM exchange_rates
+		WHERE from_currency = $1 AND to_currency = $2
+		AND rate_date <= $3::date + $4::int
+		ORDER BY rate_date > $3::date, abs(rate_date - $3::date), updated_at DESC
+		LIMIT 1`
+
+	var rate model.ExchangeRate
+	err := r.db.GetContext(ctx, &rate, query, from, to, date, laterRateDays)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrExchangeRateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// UserCurrency returns the user's base currency, or "" when none is set
+func (r *exchangeRateRepository) UserCurrency(ctx context.Context, userID uuid.UUID) (string, error) {
+	var curr sql.NullString
+	if err := r.db.GetContext(ctx, &curr, `SELECT currency FROM users WHERE id = $1`, userID); err != nil {
+		return "", err
+	}
+	return curr.String, nil
+}
+
+// Unconverted returns up to limit transactions, ordered by ID and after the
+// given one, that have no base amount or one in a currency other than their
+// owner's. With a user ID, only that user's transactions are returned.
+func (r *exchangeRateRepository) Unconverted(ctx context.Context, userID *uuid.UUID, after uuid.UUID, limit int) ([]UnconvertedTransaction, error) {
+	query := `
+		SELECT t.id, t.user_id, t.amount, t.currency, t.date,
+			COALESCE(NULLIF(u.currency, ''), 'USD') AS base_currency
+		FROM transactions t
+		JOIN users u ON u.id = t.user_id
+		WHERE (t.base_amount IS NULL OR t.base_currency IS DISTINCT FROM COALESCE(NULLIF(u.currency, ''), 'USD'))
+		AND ($1::uuid IS NULL OR t.user_id = $1)
+		AND t.id > $2
+		ORDER BY t.id
+		LIMIT $3`
+
+	var transactions []UnconvertedTransaction
+	err := r.db.SelectContext(ctx, &transactions, query, userID, after, limit)
+	return transactions, err
+}
+
+// SetBaseAmount stores the base currency amount of a transaction. It is left
+// alone if its amount, currency or date changed since it was read.
+func (r *exchangeRateRepository) SetBaseAmount(ctx context.Context, tx UnconvertedTransaction, rate, baseAmount decimal.Decimal) error {
+	query := `
+		UPDATE transactions
+		SET base_currency = $2, exchange_rate = $3, base_amount = $4
+		WHERE id = $1 AND amount = $5 AND currency = $6 AND date = $7`
+
+	_, err := r.db.ExecContext(ctx, query, tx.ID, tx.BaseCurrency, rate, baseAmount, tx.Amount, tx.Currency, tx.Date)
+	return err
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+func TestExchangeRateRepository_SaveRates(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewExchangeRateRepository(db)
+
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	rates := []model.ExchangeRate{
+		{FromCurrency: "USD", ToCurrency: "VND", Rate: decimal.NewFromInt(25000), RateDate: date},
+	}
+	rateID, now := uuid.New(), time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO exchange_rates .* ON CONFLICT \(from_currency, to_currency, rate_date, source\)`).
+		WithArgs(sqlmock.AnyArg(), "USD", "VND", rates[0].Rate, date, "manual").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(rateID, now, now))
+	mock.ExpectCommit()
+
+	err := repo.SaveRates(context.Background(), rates)
+
+	assert.NoError(t, err)
+	assert.Equal(t, rateID, rates[0].ID)
+	assert.Equal(t, model.ExchangeRateSourceManual, rates[0].Source)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExchangeRateRepository_FindRate(t *testing.T) {
+	t.Parallel()
+
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "from_currency", "to_currency", "rate", "rate_date", "source", "created_at", "updated_at"}
+
+	tests := []struct {
+		name     string
+		rows     *sqlmock.Rows
+		wantRate string
+		wantErr  error
+	}{
+		{
+			name:     "found",
+			rows:     sqlmock.NewRows(columns).AddRow(uuid.New(), "USD", "VND", "25000", date, "manual", date, date),
+			wantRate: "25000",
+		},
+		{
+			name:    "not found",
+			rows:    sqlmock.NewRows(columns),
+			wantErr: ErrExchangeRateNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := newMockDB(t)
+			defer func() { _ = db.Close() }()
+			repo := NewExchangeRateRepository(db)
+
+			mock.ExpectQuery(`SELECT \* FROM exchange_rates\s+WHERE from_currency = \$1 AND to_currency = \$2\s+AND rate_date <= \$3::date \+ \$4::int`).
+				WithArgs("USD", "VND", date, 7).
+				WillReturnRows(tt.rows)
+
+			rate, err := repo.FindRate(context.Background(), "USD", "VND", date)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantRate, rate.Rate.String())
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestExchangeRateRepository_SetBaseAmount(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewExchangeRateRepository(db)
+
+	tx := UnconvertedTransaction{
+		ID:           uuid.New(),
+		UserID:       uuid.New(),
+		Amount:       decimal.NewFromInt(10),
+		Currency:     "USD",
+		Date:         time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		BaseCurrency: "VND",
+	}
+	rate, baseAmount := decimal.NewFromInt(25000), decimal.NewFromInt(250000)
+
+	mock.ExpectExec(`UPDATE transactions\s+SET base_currency = \$2, exchange_rate = \$3, base_amount = \$4`).
+		WithArgs(tx.ID, "VND", rate, baseAmount, tx.Amount, "USD", tx.Date).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.SetBaseAmount(context.Background(), tx, rate, baseAmount)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,364 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+var (
+	// ErrImportNotFound is returned when an import batch does not exist or belongs to another user
+	ErrImportNotFound = errors.New("import not found")
+	// ErrImportNotPending is returned when committing a batch that was already committed or undone
+	ErrImportNotPending = errors.New("import has already been committed")
+	// ErrImportNotCommitted is returned when undoing a batch that was never committed or was already undone
+	ErrImportNotCommitted = errors.New("import has not been committed")
+	// ErrAlreadyImported is returned when a transaction with the same bank ID was imported meanwhile
+	ErrAlreadyImported = errors.New("some of these transactions have already been imported")
+)
+
+// ImportRepository stores import batches and the transactions they create
+type ImportRepository interface {
+	CreateBatch(ctx context.Context, batch *model.ImportBatch) error
+	GetBatch(ctx context.Context, userID, id uuid.UUID) (*model.ImportBatch, error)
+	ListBatches(ctx context.Context, userID uuid.UUID) ([]model.ImportBatch, error)
+	CommitBatch(ctx context.Context, batch *model.ImportBatch, transactions []model.Transaction) error
+	CommitMigration(ctx context.Context, batch *model.ImportBatch, m *Migration) error
+	UndoBatch(ctx context.Context, userID, id uuid.UUID) (*model.ImportBatch, error)
+	DeleteStalePending(ctx context.Context, olderThan time.Time) (int, error)
+
+	TransactionsBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]model.Transaction, error)
+	TransfersBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]model.Transfer, error)
+	ExistingExternalIDs(ctx context.Context, userID uuid.UUID, ids []string) ([]string, error)
+}
+
+type importRepository struct {
+	db *sqlx.DB
+}
+
+// NewImportRepository creates a new import repository
+func NewImportRepository(db *sqlx.DB) ImportRepository {
+	return &importRepository{db: db}
+}
+
+func (r *importRepository) CreateBatch(ctx context.Context, batch *model.ImportBatch) error {
+	query := `
+		INSERT INTO import_batches (id, user_id, source, file_name, encoding, status, content, row_count, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		RETURNING created_at`
+
+	batch.ID = uuid.New()
+	batch.Status = model.ImportStatusPending
+	return r.db.QueryRowxContext(ctx, query,
+		batch.ID, batch.UserID, batch.Source, batch.FileName, batch.Encoding, batch.Status, batch.Content, batch.RowCount,
+	).Scan(&batch.CreatedAt)
+}
+
+func (r *importRepository) GetBatch(ctx context.Context, userID, id uuid.UUID) (*model.ImportBatch, error) {
+	var batch model.ImportBatch
+	err := r.db.GetContext(ctx, &batch, `SELECT * FROM import_batches WHERE id = $1 AND user_id = $2`, id, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrImportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// ListBatches returns the user's imports, newest first, without their contents
+func (r *importRepository) ListBatches(ctx context.Context, userID uuid.UUID) ([]model.ImportBatch, error) {
+	query := `
+		SELECT id, user_id, source, file_name, encoding, status, NULL AS content, row_count,
+			imported_count, created_at, committed_at, undone_at
+		FROM import_batches
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id`
+
+	batches := []model.ImportBatch{}
+	err := r.db.SelectContext(ctx, &batches, query, userID)
+	return batches, err
+}
+
+// CommitBatch creates the transactions of a pending batch and marks it
+// committed, all in one database transaction. The uploaded content is dropped.
+func (r *importRepository) CommitBatch(ctx context.Context, batch *model.ImportBatch, transactions []model.Transaction) error {
+	dbTx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	var status string
+	err = dbTx.GetContext(ctx, &status,
+		`SELECT status FROM import_batches WHERE id = $1 AND user_id = $2 FOR UPDATE`, batch.ID, batch.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrImportNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if status != model.ImportStatusPending {
+		return ErrImportNotPending
+	}
+
+	for i := range transactions {
+		transactions[i].ImportBatchID = &batch.ID
+		if err := insertTransaction(ctx, dbTx, &transactions[i]); err != nil {
+			if isUniqueViolation(err) {
+				return ErrAlreadyImported
+			}
+			return err
+		}
+	}
+
+	query := `
+		UPDATE import_batches
+		SET status = $2, imported_count = $3, content = NULL, committed_at = NOW()
+		WHERE id = $1
+		RETURNING committed_at`
+
+	batch.Status = model.ImportStatusCommitted
+	batch.ImportedCount = len(transactions)
+	batch.Content = nil
+	if err := dbTx.QueryRowxContext(ctx, query, batch.ID, batch.Status, batch.ImportedCount).Scan(&batch.CommittedAt); err != nil {
+		return err
+	}
+	return dbTx.Commit()
+}
+
+// Migration is everything an import from another budgeting app creates.
+// Accounts, categories and debts come with their IDs set, as the other
+// records refer to them; categories come before their subcategories.
+type Migration struct {
+	Accounts     []model.Account
+	Categories   []model.Category
+	Transactions []model.Transaction
+	Transfers    []model.Transfer
+	Debts        []model.Debt
+	DebtPayments []model.DebtPayment
+	Budgets      []model.Budget
+}
+
+// CommitMigration creates a committed batch and everything the migration
+// creates, all in one database transaction. The batch's counts are stored as
+// they are.
+func (r *importRepository) CommitMigration(ctx context.Context, batch *model.ImportBatch, m *Migration) error {
+	dbTx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	batchQuery := `
+		INSERT INTO import_batches (id, user_id, source, file_name, encoding, status, row_count, imported_count, created_at, committed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		RETURNING created_at, committed_at`
+
+	batch.ID = uuid.New()
+	batch.Status = model.ImportStatusCommitted
+	batch.Content = nil
+	if err := dbTx.QueryRowxContext(ctx, batchQuery,
+		batch.ID, batch.UserID, batch.Source, batch.FileName, batch.Encoding, batch.Status, batch.RowCount, batch.ImportedCount,
+	).Scan(&batch.CreatedAt, &batch.CommittedAt); err != nil {
+		return err
+	}
+
+	accountQuery := `
+		INSERT INTO accounts (id, user_id, name, type, institution, currency, opening_balance, archived, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		RETURNING created_at, updated_at`
+	for i := range m.Accounts {
+		a := &m.Accounts[i]
+		if err := dbTx.QueryRowxContext(ctx, accountQuery,
+			a.ID, a.UserID, a.Name, a.Type, a.Institution, a.Currency, a.OpeningBalance, a.Archived,
+		).Scan(&a.CreatedAt, &a.UpdatedAt); err != nil {
+			return err
+		}
+	}
+
+	for i := range m.Categories {
+		c := &m.Categories[i]
+		err := dbTx.QueryRowxContext(ctx, insertCategoryQuery+` RETURNING created_at, updated_at`,
+			c.ID, c.UserID, c.ParentID, c.Name, c.Type, c.Icon, c.Color, c.Archived,
+		).Scan(&c.CreatedAt, &c.UpdatedAt)
+		if isUniqueViolation(err) {
+			return ErrCategoryExists
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range m.Transactions {
+		m.Transactions[i].ImportBatchID = &batch.ID
+		if err := insertTransaction(ctx, dbTx, &m.Transactions[i]); err != nil {
+			if isUniqueViolation(err) {
+				return ErrAlreadyImported
+			}
+			return err
+		}
+	}
+
+	transferQuery := `
+		INSERT INTO transfers (id, user_id, from_account_id, to_account_id, amount, description, date, import_batch_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		RETURNING created_at`
+	for i := range m.Transfers {
+		tr := &m.Transfers[i]
+		tr.ID = uuid.New()
+		tr.ImportBatchID = &batch.ID
+		if err := dbTx.QueryRowxContext(ctx, transferQuery,
+			tr.ID, tr.UserID, tr.FromAccountID, tr.ToAccountID, tr.Amount, tr.Description, tr.Date, tr.ImportBatchID,
+		).Scan(&tr.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	debtQuery := `
+		INSERT INTO debts (id, user_id, name, type, original_amount, current_balance, interest_rate, minimum_payment, currency, due_day, start_date, expected_payoff, import_batch_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NOW(), NOW())
+		RETURNING created_at, updated_at`
+	for i := range m.Debts {
+		d := &m.Debts[i]
+		d.ImportBatchID = &batch.ID
+		if err := dbTx.QueryRowxContext(ctx, debtQuery,
+			d.ID, d.UserID, d.Name, d.Type, d.OriginalAmount, d.CurrentBalance, d.InterestRate, d.MinimumPayment,
+			d.Currency, d.DueDay, d.StartDate, d.ExpectedPayoff, d.ImportBatchID,
+		).Scan(&d.CreatedAt, &d.UpdatedAt); err != nil {
+			return err
+		}
+	}
+
+	// The debts' balances already take the payments into account
+	paymentQuery := `
+		INSERT INTO debt_payments (id, debt_id, amount, principal, interest, date, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING created_at`
+	for i := range m.DebtPayments {
+		p := &m.DebtPayments[i]
+		p.ID = uuid.New()
+		if err := dbTx.QueryRowxContext(ctx, paymentQuery,
+			p.ID, p.DebtID, p.Amount, p.Principal, p.Interest, p.Date,
+		).Scan(&p.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	budgetQuery := `
+		INSERT INTO budgets (id, user_id, category, amount, currency, period, start_date, end_date,
+			enable_rollover, max_rollover_amount, rollover_overspend, import_batch_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW(), NOW())
+		RETURNING created_at, updated_at`
+	for i := range m.Budgets {
+		b := &m.Budgets[i]
+		b.ID = uuid.New()
+		b.ImportBatchID = &batch.ID
+		if err := dbTx.QueryRowxContext(ctx, budgetQuery,
+			b.ID, b.UserID, b.Category, b.Amount, b.Currency, b.Period, b.StartDate, b.EndDate,
+			b.EnableRollover, b.MaxRolloverAmount, b.RolloverOverspend, b.ImportBatchID,
+		).Scan(&b.CreatedAt, &b.UpdatedAt); err != nil {
+			return err
+		}
+	}
+
+	return dbTx.Commit()
+}
+
+// UndoBatch deletes the transactions, transfers, debts and budgets a committed
+// batch created and marks it undone. Accounts and categories created by a
+// migration are kept, as the user may have used them since.
+func (r *importRepository) UndoBatch(ctx context.Context, userID, id uuid.UUID) (*model.ImportBatch, error) {
+	dbTx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	var batch model.ImportBatch
+	err = dbTx.GetContext(ctx, &batch,
+		`SELECT * FROM import_batches WHERE id = $1 AND user_id = $2 FOR UPDATE`, id, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrImportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status != model.ImportStatusCommitted {
+		return nil, ErrImportNotCommitted
+	}
+
+	// Debt payments go with their debts
+	for _, table := range []string{"transactions", "transfers", "debts", "budgets"} {
+		if _, err := dbTx.ExecContext(ctx,
+			`DELETE FROM `+table+` WHERE import_batch_id = $1 AND user_id = $2`, id, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	batch.Status = model.ImportStatusUndone
+	if err := dbTx.QueryRowxContext(ctx,
+		`UPDATE import_batches SET status = $2, undone_at = NOW() WHERE id = $1 RETURNING undone_at`,
+		id, batch.Status,
+	).Scan(&batch.UndoneAt); err != nil {
+		return nil, err
+	}
+	if err := dbTx.Commit(); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// DeleteStalePending removes batches that were uploaded but never committed
+func (r *importRepository) DeleteStalePending(ctx context.Context, olderThan time.Time) (int, error) {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM import_batches WHERE status = $1 AND created_at < $2`, model.ImportStatusPending, olderThan)
+	if err != nil {
+		return 0, err
+	}
+	n, err := result.RowsAffected()
+	return int(n), err
+}
+
+// TransactionsBetween returns the user's transactions dated within [from, to],
+// without split lines or tags
+func (r *importRepository) TransactionsBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]model.Transaction, error) {
+	query := `
+		SELECT * FROM transactions
+		WHERE user_id = $1 AND date >= $2 AND date <= $3
+		ORDER BY date, id`
+
+	var transactions []model.Transaction
+	err := r.db.SelectContext(ctx, &transactions, query, userID, from, to)
+	return transactions, err
+}
+
+// TransfersBetween returns the user's transfers dated within [from, to]
+func (r *importRepository) TransfersBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]model.Transfer, error) {
+	query := `
+		SELECT * FROM transfers
+		WHERE user_id = $1 AND date >= $2 AND date <= $3
+		ORDER BY date, id`
+
+	var transfers []model.Transfer
+	err := r.db.SelectContext(ctx, &transfers, query, userID, from, to)
+	return transfers, err
+}
+
+// ExistingExternalIDs returns which of the given external IDs the user's
+// transactions already have
+func (r *importRepository) ExistingExternalIDs(ctx context.Context, userID uuid.UUID, ids []string) ([]string, error) {
+	query := `
+		SELECT external_id FROM transactions
+		WHERE user_id = $1 AND external_id = ANY($2)`
+
+	var existing []string
+	err := r.db.SelectContext(ctx, &existing, query, userID, pq.Array(ids))
+	return existing, err
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+var importBatchColumns = []string{"id", "user_id", "source", "file_name", "encoding", "status", "content", "row_count",
+	"imported_count", "created_at", "committed_at", "undone_at"}
+
+func TestImportRepository_CommitBatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		setupMock func(mock sqlmock.Sqlmock, batch *model.ImportBatch)
+		wantErr   error
+	}{
+		{
+			name: "success",
+			setupMock: func(mock sqlmock.Sqlmock, batch *model.ImportBatch) {
+				now := time.Now()
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT status FROM import_batches WHERE id = \$1 AND user_id = \$2 FOR UPDATE`).
+					WithArgs(batch.ID, batch.UserID).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.ImportStatusPending))
+				for i := 0; i < 2; i++ {
+					mock.ExpectQuery(`INSERT INTO transactions`).
+						WithArgs(sqlmock.AnyArg(), batch.UserID, sqlmock.AnyArg(), sqlmock.AnyArg(), "VND", sqlmock.AnyArg(),
+							sqlmock.AnyArg(), sqlmock.AnyArg(), nil, &batch.ID, nil, nil, nil, nil).
+						WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now))
+				}
+				mock.ExpectQuery(`UPDATE import_batches\s+SET status = \$2, imported_count = \$3, content = NULL`).
+					WithArgs(batch.ID, model.ImportStatusCommitted, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"committed_at"}).AddRow(now))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "already committed",
+			setupMock: func(mock sqlmock.Sqlmock, batch *model.ImportBatch) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT status FROM import_batches`).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.ImportStatusCommitted))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrImportNotPending,
+		},
+		{
+			name: "not found",
+			setupMock: func(mock sqlmock.Sqlmock, batch *model.ImportBatch) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT status FROM import_batches`).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr: ErrImportNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := newMockDB(t)
+			defer func() { _ = db.Close() }()
+			repo := NewImportRepository(db)
+
+			content := "date,amount\n"
+			batch := &model.ImportBatch{ID: uuid.New(), UserID: uuid.New(), Status: model.ImportStatusPending, Content: &content}
+			txns := []model.Transaction{
+				{UserID: batch.UserID, Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(50000), Currency: "VND", Category: "Other", Date: time.Now()},
+				{UserID: batch.UserID, Type: model.TransactionTypeIncome, Amount: decimal.NewFromInt(900000), Currency: "VND", Category: "Salary", Date: time.Now()},
+			}
+			tt.setupMock(mock, batch)
+
+			err := repo.CommitBatch(context.Background(), batch, txns)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, model.ImportStatusCommitted, batch.Status)
+				assert.Equal(t, 2, batch.ImportedCount)
+				assert.Nil(t, batch.Content)
+				assert.NotNil(t, batch.CommittedAt)
+				for _, tx := range txns {
+					assert.Equal(t, &batch.ID, tx.ImportBatchID)
+				}
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestImportRepository_UndoBatch(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewImportRepository(db)
+	userID, id := uuid.New(), uuid.New()
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM import_batches WHERE id = \$1 AND user_id = \$2 FOR UPDATE`).
+		WithArgs(id, userID).
+		WillReturnRows(sqlmock.NewRows(importBatchColumns).
+			AddRow(id, userID, "csv", "vcb.csv", "utf-8", model.ImportStatusCommitted, nil, 3, 3, now, now, nil))
+	mock.ExpectExec(`DELETE FROM transactions WHERE import_batch_id = \$1 AND user_id = \$2`).
+		WithArgs(id, userID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	for _, table := range []string{"transfers", "debts", "budgets"} {
+		mock.ExpectExec(`DELETE FROM `+table+` WHERE import_batch_id = \$1 AND user_id = \$2`).
+			WithArgs(id, userID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	mock.ExpectQuery(`UPDATE import_batches SET status = \$2, undone_at = NOW\(\)`).
+		WithArgs(id, model.ImportStatusUndone).
+		WillReturnRows(sqlmock.NewRows([]string{"undone_at"}).AddRow(now))
+	mock.ExpectCommit()
+
+	batch, err := repo.UndoBatch(context.Background(), userID, id)
+	require.NoError(t, err)
+	assert.Equal(t, model.ImportStatusUndone, batch.Status)
+	assert.NotNil(t, batch.UndoneAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportRepository_CommitMigration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		txErr   error
+		wantErr error
+	}{
+		{name: "success"},
+		{name: "transaction imported meanwhile", txErr: &pq.Error{Code: "23505"}, wantErr: ErrAlreadyImported},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := newMockDB(t)
+			defer func() { _ = db.Close() }()
+			repo := NewImportRepository(db)
+
+			userID, accountID, cashID, debtID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+			date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+			externalID := "moneylover:1"
+			m := &Migration{
+				Accounts:     []model.Account{{ID: accountID, UserID: userID, Name: "Vietcombank", Type: model.AccountTypeBank, Currency: "VND"}},
+				Categories:   []model.Category{{ID: uuid.New(), UserID: userID, Name: "Quà cưới", Type: model.TransactionTypeExpense}},
+				Transactions: []model.Transaction{{UserID: userID, Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(50000), Currency: "VND", Date: date, ExternalID: &externalID}},
+				Transfers:    []model.Transfer{{UserID: userID, FromAccountID: accountID, ToAccountID: cashID, Amount: decimal.NewFromInt(1000000), Date: date}},
+				Debts:        []model.Debt{{ID: debtID, UserID: userID, Name: "Anh Tuan", Type: model.DebtTypePersonalLoan, Currency: "VND", StartDate: date}},
+				DebtPayments: []model.DebtPayment{{DebtID: debtID, Amount: decimal.NewFromInt(1000000), Principal: decimal.NewFromInt(1000000), Date: date}},
+				Budgets:      []model.Budget{{UserID: userID, Category: "Food & Dining", Amount: decimal.NewFromInt(3000000), Currency: "VND", Period: "monthly", StartDate: date}},
+			}
+			batch := &model.ImportBatch{UserID: userID, Source: model.ImportSourceMoneyLover, FileName: "moneylover.csv", RowCount: 6, ImportedCount: 6}
+
+			now := time.Now()
+			timestamps := func() *sqlmock.Rows { return sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now) }
+			mock.ExpectBegin()
+			mock.ExpectQuery(`INSERT INTO import_batches`).
+				WithArgs(sqlmock.AnyArg(), userID, model.ImportSourceMoneyLover, "moneylover.csv", "", model.ImportStatusCommitted, 6, 6).
+				WillReturnRows(sqlmock.NewRows([]string{"created_at", "committed_at"}).AddRow(now, now))
+			mock.ExpectQuery(`INSERT INTO accounts`).
+				WithArgs(accountID, userID, "Vietcombank", model.AccountTypeBank, "", "VND", sqlmock.AnyArg(), false).
+				WillReturnRows(timestamps())
+			mock.ExpectQuery(`INSERT INTO categories`).WillReturnRows(timestamps())
+			if tt.txErr != nil {
+				mock.ExpectQuery(`INSERT INTO transactions`).WillReturnError(tt.txErr)
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectQuery(`INSERT INTO transactions`).WillReturnRows(timestamps())
+				mock.ExpectQuery(`INSERT INTO transfers`).
+					WithArgs(sqlmock.AnyArg(), userID, accountID, cashID, sqlmock.AnyArg(), "", date, sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(now))
+				mock.ExpectQuery(`INSERT INTO debts`).WillReturnRows(timestamps())
+				mock.ExpectQuery(`INSERT INTO debt_payments`).
+					WithArgs(sqlmock.AnyArg(), debtID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), date).
+					WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(now))
+				mock.ExpectQuery(`INSERT INTO budgets`).WillReturnRows(timestamps())
+				mock.ExpectCommit()
+			}
+
+			err := repo.CommitMigration(context.Background(), batch, m)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, model.ImportStatusCommitted, batch.Status)
+				assert.NotNil(t, batch.CommittedAt)
+				assert.Equal(t, &batch.ID, m.Transactions[0].ImportBatchID)
+				assert.Equal(t, &batch.ID, m.Transfers[0].ImportBatchID)
+				assert.Equal(t, &batch.ID, m.Debts[0].ImportBatchID)
+				assert.Equal(t, &batch.ID, m.Budgets[0].ImportBatchID)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
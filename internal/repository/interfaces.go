@@ -24,9 +24,12 @@ type TransactionRepositoryInterface interface {
 	Create(ctx context.Context, tx *model.Transaction) error
 	GetByID(ctx context.Context, id uuid.UUID) (*model.Transaction, error)
 	List(ctx context.Context, userID uuid.UUID, filters TransactionFilters) ([]model.Transaction, error)
+	Totals(ctx context.Context, userID uuid.UUID, filters TransactionFilters) (*model.TransactionTotals, error)
 	Update(ctx context.Context, tx *model.Transaction) error
 	Delete(ctx context.Context, id, userID uuid.UUID) error
+	ApplyBulk(ctx context.Context, userID uuid.UUID, filters TransactionFilters, dryRun bool, change BulkChange) error
 	GetMonthlyTotals(ctx context.Context, userID uuid.UUID, year, month int) (decimal.Decimal, decimal.Decimal, error)
+	GetTotalsForPeriod(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) (decimal.Decimal, decimal.Decimal, error)
 	GetExpensesByCategory(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) (map[string]decimal.Decimal, error)
 	GetSpentByCategory(ctx context.Context, userID uuid.UUID, category string, startDate, endDate time.Time) (decimal.Decimal, error)
 	GetRecentTransactions(ctx context.Context, userID uuid.UUID, limit int) ([]model.Transaction, error)
@@ -61,7 +64,9 @@ type SavingsGoalRepositoryInterface interface {
 	List(ctx context.Context, userID uuid.UUID) ([]model.SavingsGoal, error)
 	Update(ctx context.Context, goal *model.SavingsGoal) error
 	Delete(ctx context.Context, id, userID uuid.UUID) error
-	AddContribution(ctx context.Context, id, userID uuid.UUID, amount decimal.Decimal) error
+	RecordContribution(ctx context.Context, entry *model.SavingsContribution) (*model.SavingsGoal, error)
+	GetContribution(ctx context.Context, id uuid.UUID) (*model.SavingsContribution, error)
+	ListContributions(ctx context.Context, goalID uuid.UUID, limit, offset int) ([]model.SavingsContribution, error)
 	GetTotalSavings(ctx context.Context, userID uuid.UUID) (decimal.Decimal, error)
 }
 
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+// JobRunRepository persists the execution history of scheduled jobs.
+type JobRunRepository struct {
+	db *sqlx.DB
+}
+
+// NewJobRunRepository creates a new job run repository.
+func NewJobRunRepository(db *sqlx.DB) *JobRunRepository {
+	return &JobRunRepository{db: db}
+}
+
+// StartRun inserts a new run in the running state.
+func (r *JobRunRepository) StartRun(ctx context.Context, run *model.JobRun) error {
+	if run.ID == uuid.Nil {
+		run.ID = uuid.New()
+	}
+
+	query := `
+		INSERT INTO job_runs (id, job_name, status, started_at)
+		VALUES ($1, $2, $3, $4)`
+
+	_, err := r.db.ExecContext(ctx, query, run.ID, run.JobName, run.Status, run.StartedAt)
+	return err
+}
+
+// FinishRun records the outcome of a run.
+func (r *JobRunRepository) FinishRun(ctx context.Context, run *model.JobRun) error {
+	query := `
+		UPDATE job_runs
+		SET status = $2, items_processed = $3, error_message = $4, finished_at = $5
+		WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query,
+		run.ID, run.Status, run.ItemsProcessed, run.ErrorMessage, run.FinishedAt,
+	)
+	return err
+}
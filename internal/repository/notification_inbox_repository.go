@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+// ErrNotificationNotFound is returned when a notification does not exist, was deleted or belongs to another user
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// NotificationInboxFilter narrows the notifications listed in the inbox
+type NotificationInboxFilter struct {
+	Type       *model.NotificationType
+	UnreadOnly bool
+	Limit      int
+	Offset     int
+}
+
+// NotificationInboxRepository reads and updates the notification log as a user's inbox.
+// Deleted notifications are hidden but kept, because the log also de-duplicates sends.
+type NotificationInboxRepository interface {
+	List(ctx context.Context, userID uuid.UUID, filter NotificationInboxFilter) ([]model.NotificationLog, error)
+	Count(ctx context.Context, userID uuid.UUID, filter NotificationInboxFilter) (int, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+	MarkRead(ctx context.Context, userID, id uuid.UUID) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID, notifType *model.NotificationType) (int64, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type notificationInboxRepository struct {
+	db *sqlx.DB
+}
+
+// NewNotificationInboxRepository creates a new notification inbox repository
+func NewNotificationInboxRepository(db *sqlx.DB) NotificationInboxRepository {
+	return &notificationInboxRepository{db: db}
+}
+
+// inboxWhere builds the WHERE clause shared by List and Count
+func inboxWhere(userID uuid.UUID, filter NotificationInboxFilter) (string, []interface{}) {
+	conditions := []string{"user_id = $1", "deleted_at IS NULL"}
+	args := []interface{}{userID}
+
+	if filter.Type != nil {
+		args = append(args, *filter.Type)
+		conditions = append(conditions, fmt.Sprintf("notification_type = $%d", len(args)))
+	}
+	if filter.UnreadOnly {
+		conditions = append(conditions, "read_at IS NULL")
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func (r *notificationInboxRepository) List(ctx context.Context, userID uuid.UUID, filter NotificationInboxFilter) ([]model.NotificationLog, error) {
+	where, args := inboxWhere(userID, filter)
+	args = append(args, filter.Limit, filter.Offset)
+	query := `SELECT * FROM notification_log` + where +
+		fmt.Sprintf(` ORDER BY sent_at DESC, id DESC LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	notifications := []model.NotificationLog{}
+	err := r.db.SelectContext(ctx, &notifications, query, args...)
+	return notifications, err
+}
+
+func (r *notificationInboxRepository) Count(ctx context.Context, userID uuid.UUID, filter NotificationInboxFilter) (int, error) {
+	where, args := inboxWhere(userID, filter)
+
+	var count int
+	err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM notification_log`+where, args...)
+	return count, err
+}
+
+func (r *notificationInboxRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	return r.Count(ctx, userID, NotificationInboxFilter{UnreadOnly: true})
+}
+
+// MarkRead marks one notification as read. Marking a read notification again keeps
+// the original read time.
+func (r *notificationInboxRepository) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	query := `
+		UPDATE notification_log SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread notification as read, optionally only those of
+// one type. It returns the number of notifications marked.
+func (r *notificationInboxRepository) MarkAllRead(ctx context.Context, userID uuid.UUID, notifType *model.NotificationType) (int64, error) {
+	query := `
+		UPDATE notification_log SET read_at = NOW()
+		WHERE user_id = $1 AND read_at IS NULL AND deleted_at IS NULL
+		AND ($2::text IS NULL OR notification_type = $2)`
+
+	result, err := r.db.ExecContext(ctx, query, userID, notifType)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *notificationInboxRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	query := `
+		UPDATE notification_log SET deleted_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
 	"github.com/wealthpath/backend/internal/model"
 )
 
@@ -104,13 +105,13 @@ func (r *PushRepository) UpsertPreferences(ctx context.Context, prefs *model.Not
 
 func (r *PushRepository) LogNotification(ctx context.Context, log *model.NotificationLog) error {
 	query := `
-		INSERT INTO notification_log (id, user_id, notification_type, reference_id, reference_date, title, body, sent_at, success, error_message)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO notification_log (id, user_id, notification_type, reference_id, reference_date, title, body, sent_at, success, error_message, milestone)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		RETURNING sent_at`
 
 	return r.db.QueryRowxContext(ctx, query,
 		log.ID, log.UserID, log.NotificationType, log.ReferenceID, log.ReferenceDate,
-		log.Title, log.Body, log.SentAt, log.Success, log.ErrorMessage,
+		log.Title, log.Body, log.SentAt, log.Success, log.ErrorMessage, log.Milestone,
 	).Scan(&log.SentAt)
 }
 
@@ -159,3 +160,126 @@ func (r *PushRepository) GetUsersNearBudgetLimit(ctx context.Context) ([]uuid.UU
 	err := r.db.SelectContext(ctx, &userIDs, query)
 	return userIDs, err
 }
+
+// DueBill is a recurring expense that falls inside its owner's bill reminder window
+type DueBill struct {
+	RecurringID    uuid.UUID       `db:"recurring_id"`
+	UserID         uuid.UUID       `db:"user_id"`
+	Description    string          `db:"description"`
+	Category       string          `db:"category"`
+	Amount         decimal.Decimal `db:"amount"`
+	Currency       string          `db:"currency"`
+	NextOccurrence time.Time       `db:"next_occurrence"`
+}
+
+// GetDueBills returns active recurring expenses due within each user's reminder window.
+// Users without stored preferences get the defaults (enabled, 3 days before).
+func (r *PushRepository) GetDueBills(ctx context.Context) ([]DueBill, error) {
+	var bills []DueBill
+	query := `
+		SELECT rt.id AS recurring_id, rt.user_id, COALESCE(rt.description, '') AS description,
+			rt.category, rt.amount, COALESCE(rt.currency, 'USD') AS currency, rt.next_occurrence
+		FROM recurring_transactions rt
+		LEFT JOIN notification_preferences np ON rt.user_id = np.user_id
+		WHERE rt.is_active = TRUE
+		AND rt.type = 'expense'
+		AND COALESCE(np.bill_reminders_enabled, TRUE) = TRUE
+		AND rt.next_occurrence BETWEEN CURRENT_DATE
+			AND CURRENT_DATE + COALESCE(np.bill_reminder_days_before, 3) * INTERVAL '1 day'
+		ORDER BY rt.next_occurrence, rt.user_id`
+
+	err := r.db.SelectContext(ctx, &bills, query)
+	return bills, err
+}
+
+// ReminderDebt is an unpaid debt with a due day, along with its owner's reminder settings
+type ReminderDebt struct {
+	DebtID         uuid.UUID       `db:"debt_id"`
+	UserID         uuid.UUID       `db:"user_id"`
+	Name           string          `db:"name"`
+	MinimumPayment decimal.Decimal `db:"minimum_payment"`
+	Currency       string          `db:"currency"`
+	DueDay         int             `db:"due_day"`
+	StartDate      time.Time       `db:"start_date"`
+	DaysBefore     int             `db:"days_before"`
+}
+
+// GetDebtsForReminders returns debts with an outstanding balance and a due day whose
+// owners have bill reminders enabled. Users without stored preferences get the
+// defaults (enabled, 3 days before). The next due date is worked out by the caller.
+func (r *PushRepository) GetDebtsForReminders(ctx context.Context) ([]ReminderDebt, error) {
+	var debts []ReminderDebt
+	query := `
+		SELECT d.id AS debt_id, d.user_id, d.name, d.minimum_payment,
+			COALESCE(d.currency, 'USD') AS currency, d.due_day, d.start_date,
+			COALESCE(np.bill_reminder_days_before, 3) AS days_before
+		FROM debts d
+		LEFT JOIN notification_preferences np ON d.user_id = np.user_id
+		WHERE d.current_balance > 0
+		AND d.due_day IS NOT NULL
+		AND COALESCE(np.bill_reminders_enabled, TRUE) = TRUE
+		ORDER BY d.user_id, d.due_day`
+
+	err := r.db.SelectContext(ctx, &debts, query)
+	return debts, err
+}
+
+// HasNotificationForDate reports whether a notification of the given type, reference
+// and reference date was already delivered to the user, however long ago it was sent.
+// A nil refID matches any reference.
+func (r *PushRepository) HasNotificationForDate(ctx context.Context, userID uuid.UUID, notifType model.NotificationType, refID *uuid.UUID, refDate time.Time) (bool, error) {
+	var exists bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM notification_log
+			WHERE user_id = $1
+			AND notification_type = $2
+			AND ($3::uuid IS NULL OR reference_id = $3)
+			AND reference_date = $4::date
+			AND success = TRUE
+		)`
+
+	err := r.db.GetContext(ctx, &exists, query, userID, notifType, refID, refDate)
+	return exists, err
+}
+
+// HasMilestoneNotification reports whether the milestone of a savings goal was
+// sent to the user, on any day
+func (r *PushRepository) HasMilestoneNotification(ctx context.Context, userID, goalID uuid.UUID, milestone int) (bool, error) {
+	var exists bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM notification_log
+			WHERE user_id = $1
+			AND notification_type = $2
+			AND reference_id = $3
+			AND milestone = $4
+			AND success = TRUE
+		)`
+
+	err := r.db.GetContext(ctx, &exists, query, userID, model.NotificationTypeGoalMilestone, goalID, milestone)
+	return exists, err
+}
+
+// WeeklySummaryRecipient is a user who opted in to the weekly summary
+type WeeklySummaryRecipient struct {
+	UserID               uuid.UUID `db:"user_id"`
+	Currency             string    `db:"currency"`
+	BudgetAlertThreshold int       `db:"budget_alert_threshold"`
+}
+
+// GetWeeklySummaryRecipients returns users with the weekly summary enabled and at
+// least one push subscription to deliver it to.
+func (r *PushRepository) GetWeeklySummaryRecipients(ctx context.Context) ([]WeeklySummaryRecipient, error) {
+	var recipients []WeeklySummaryRecipient
+	query := `
+		SELECT np.user_id, COALESCE(NULLIF(u.currency, ''), 'USD') AS currency, np.budget_alert_threshold
+		FROM notification_preferences np
+		JOIN users u ON u.id = np.user_id
+		WHERE np.weekly_summary_enabled = TRUE
+		AND EXISTS (SELECT 1 FROM push_subscriptions ps WHERE ps.user_id = np.user_id)
+		ORDER BY np.user_id`
+
+	err := r.db.SelectContext(ctx, &recipients, query)
+	return recipients, err
+}
@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -10,6 +13,9 @@ import (
 	"github.com/wealthpath/backend/internal/model"
 )
 
+// ErrRateSubscriptionNotFound is returned when a subscription does not exist or belongs to another user
+var ErrRateSubscriptionNotFound = errors.New("rate subscription not found")
+
 // RateSubscriptionRepository defines the interface for rate subscription data access
 type RateSubscriptionRepository interface {
 	Create(ctx context.Context, sub *model.RateSubscription) error
@@ -20,6 +26,8 @@ type RateSubscriptionRepository interface {
 	UpdateLastRate(ctx context.Context, id int64, rate decimal.Decimal) error
 	Delete(ctx context.Context, userID uuid.UUID, id int64) error
 	LogNotification(ctx context.Context, notification *model.RateNotification) error
+	HasRecentNotification(ctx context.Context, subscriptionID int64, since time.Time) (bool, error)
+	ListNotificationsByUser(ctx context.Context, userID uuid.UUID, subscriptionID *int64, limit, offset int) ([]model.RateNotificationHistory, error)
 }
 
 type rateSubscriptionRepository struct {
@@ -61,6 +69,9 @@ func (r *rateSubscriptionRepository) GetByID(ctx context.Context, id int64) (*mo
 	err := r.db.GetContext(ctx, &sub, `
 		SELECT * FROM rate_subscriptions WHERE id = $1
 	`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRateSubscriptionNotFound
+	}
 	if err != nil {
 		return nil, fmt.Errorf("get subscription: %w", err)
 	}
@@ -95,7 +106,7 @@ func (r *rateSubscriptionRepository) ListAll(ctx context.Context) ([]model.RateS
 
 // Update updates a subscription
 func (r *rateSubscriptionRepository) Update(ctx context.Context, sub *model.RateSubscription) error {
-	_, err := r.db.ExecContext(ctx, `
+	result, err := r.db.ExecContext(ctx, `
 		UPDATE rate_subscriptions SET
 			threshold_percent = $1,
 			notify_on_increase = $2,
@@ -109,6 +120,11 @@ func (r *rateSubscriptionRepository) Update(ctx context.Context, sub *model.Rate
 	if err != nil {
 		return fmt.Errorf("update subscription: %w", err)
 	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrRateSubscriptionNotFound
+	}
 	return nil
 }
 
@@ -138,22 +154,63 @@ func (r *rateSubscriptionRepository) Delete(ctx context.Context, userID uuid.UUI
 
 	rows, _ := result.RowsAffected()
 	if rows == 0 {
-		return fmt.Errorf("subscription not found")
+		return ErrRateSubscriptionNotFound
 	}
 
 	return nil
 }
 
-// LogNotification logs a sent notification
+// LogNotification logs a notification delivery attempt
 func (r *rateSubscriptionRepository) LogNotification(ctx context.Context, notification *model.RateNotification) error {
-	_, err := r.db.ExecContext(ctx, `
+	err := r.db.QueryRowContext(ctx, `
 		INSERT INTO rate_notifications (
 			subscription_id, old_rate, new_rate, change_percent, notification_type, status
 		) VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, sent_at
 	`, notification.SubscriptionID, notification.OldRate, notification.NewRate,
-		notification.ChangePercent, notification.NotificationType, notification.Status)
+		notification.ChangePercent, notification.NotificationType, notification.Status,
+	).Scan(&notification.ID, &notification.SentAt)
 	if err != nil {
 		return fmt.Errorf("log notification: %w", err)
 	}
 	return nil
 }
+
+// HasRecentNotification reports whether an alert for the subscription was sent,
+// is still pending or failed since the given time
+func (r *rateSubscriptionRepository) HasRecentNotification(ctx context.Context, subscriptionID int64, since time.Time) (bool, error) {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, `
+		SELECT EXISTS (
+			SELECT 1 FROM rate_notifications
+			WHERE subscription_id = $1
+			AND status IN ('pending', 'sent', 'failed')
+			AND sent_at >= $2
+		)
+	`, subscriptionID, since)
+	if err != nil {
+		return false, fmt.Errorf("check recent notification: %w", err)
+	}
+	return exists, nil
+}
+
+// ListNotificationsByUser returns the rate alerts sent to a user, newest first.
+// If subscriptionID is set, only alerts for that subscription are returned.
+func (r *rateSubscriptionRepository) ListNotificationsByUser(ctx context.Context, userID uuid.UUID, subscriptionID *int64, limit, offset int) ([]model.RateNotificationHistory, error) {
+	var history []model.RateNotificationHistory
+	err := r.db.SelectContext(ctx, &history, `
+		SELECT n.id, n.subscription_id, n.old_rate, n.new_rate, n.change_percent,
+			n.notification_type, n.sent_at, n.status,
+			s.bank_code, s.product_type, s.term_months
+		FROM rate_notifications n
+		JOIN rate_subscriptions s ON s.id = n.subscription_id
+		WHERE s.user_id = $1
+		AND ($2::int IS NULL OR n.subscription_id = $2)
+		ORDER BY n.sent_at DESC, n.id DESC
+		LIMIT $3 OFFSET $4
+	`, userID, subscriptionID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list notifications: %w", err)
+	}
+	return history, nil
+}
@@ -19,6 +19,7 @@ func NewRecurringRepository(db *sqlx.DB) *RecurringRepository {
 	return &RecurringRepository{db: db}
 }
 
+// Create inserts a recurring transaction together with its tags
 func (r *RecurringRepository) Create(ctx context.Context, rt *model.RecurringTransaction) error {
 	query := `
 		INSERT INTO recurring_transactions (id, user_id, type, amount, currency, category, description, 
@@ -26,11 +27,25 @@ func (r *RecurringRepository) Create(ctx context.Context, rt *model.RecurringTra
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW(), NOW())
 		RETURNING created_at, updated_at`
 
+	dbTx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
 	rt.ID = uuid.New()
-	return r.db.QueryRowxContext(ctx, query,
+	err = dbTx.QueryRowxContext(ctx, query,
 		rt.ID, rt.UserID, rt.Type, rt.Amount, rt.Currency, rt.Category, rt.Description,
 		rt.Frequency, rt.StartDate, rt.EndDate, rt.NextOccurrence, rt.IsActive,
 	).Scan(&rt.CreatedAt, &rt.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := attachTags(ctx, dbTx, recurringTagLink, rt.UserID, rt.ID, rt.Tags); err != nil {
+		return err
+	}
+	return dbTx.Commit()
 }
 
 func (r *RecurringRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.RecurringTransaction, error) {
@@ -40,23 +55,36 @@ func (r *RecurringRepository) GetByID(ctx context.Context, id uuid.UUID) (*model
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, errors.New("recurring transaction not found")
 	}
-	return &rt, err
+	if err != nil {
+		return &rt, err
+	}
+
+	items := []model.RecurringTransaction{rt}
+	if err := r.loadTags(ctx, items); err != nil {
+		return nil, err
+	}
+	return &items[0], nil
 }
 
 func (r *RecurringRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]model.RecurringTransaction, error) {
 	var items []model.RecurringTransaction
 	query := `SELECT * FROM recurring_transactions WHERE user_id = $1 ORDER BY next_occurrence ASC`
-	err := r.db.SelectContext(ctx, &items, query, userID)
-	return items, err
+	if err := r.db.SelectContext(ctx, &items, query, userID); err != nil {
+		return nil, err
+	}
+	return items, r.loadTags(ctx, items)
 }
 
 func (r *RecurringRepository) GetActiveByUserID(ctx context.Context, userID uuid.UUID) ([]model.RecurringTransaction, error) {
 	var items []model.RecurringTransaction
 	query := `SELECT * FROM recurring_transactions WHERE user_id = $1 AND is_active = true ORDER BY next_occurrence ASC`
-	err := r.db.SelectContext(ctx, &items, query, userID)
-	return items, err
+	if err := r.db.SelectContext(ctx, &items, query, userID); err != nil {
+		return nil, err
+	}
+	return items, r.loadTags(ctx, items)
 }
 
+// Update saves a recurring transaction and replaces its tags
 func (r *RecurringRepository) Update(ctx context.Context, rt *model.RecurringTransaction) error {
 	query := `
 		UPDATE recurring_transactions 
@@ -65,10 +93,28 @@ func (r *RecurringRepository) Update(ctx context.Context, rt *model.RecurringTra
 			is_active = $11, updated_at = NOW()
 		WHERE id = $1
 		RETURNING updated_at`
-	return r.db.QueryRowxContext(ctx, query,
+
+	dbTx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	err = dbTx.QueryRowxContext(ctx, query,
 		rt.ID, rt.Type, rt.Amount, rt.Currency, rt.Category, rt.Description,
 		rt.Frequency, rt.StartDate, rt.EndDate, rt.NextOccurrence, rt.IsActive,
 	).Scan(&rt.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := detachTags(ctx, dbTx, recurringTagLink, rt.ID); err != nil {
+		return err
+	}
+	if err := attachTags(ctx, dbTx, recurringTagLink, rt.UserID, rt.ID, rt.Tags); err != nil {
+		return err
+	}
+	return dbTx.Commit()
 }
 
 func (r *RecurringRepository) Delete(ctx context.Context, id uuid.UUID) error {
@@ -86,8 +132,10 @@ func (r *RecurringRepository) GetDueTransactions(ctx context.Context, before tim
 			AND next_occurrence <= $1
 			AND (end_date IS NULL OR end_date >= $1)
 		ORDER BY next_occurrence ASC`
-	err := r.db.SelectContext(ctx, &items, query, before)
-	return items, err
+	if err := r.db.SelectContext(ctx, &items, query, before); err != nil {
+		return nil, err
+	}
+	return items, r.loadTags(ctx, items)
 }
 
 // UpdateLastGenerated updates the last_generated and next_occurrence after generating a transaction
@@ -114,3 +162,23 @@ func (r *RecurringRepository) GetUpcoming(ctx context.Context, userID uuid.UUID,
 	err := r.db.SelectContext(ctx, &items, query, userID, limit)
 	return items, err
 }
+
+// loadTags fills in the tags of the given recurring transactions with one query
+func (r *RecurringRepository) loadTags(ctx context.Context, items []model.RecurringTransaction) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(items))
+	for i, rt := range items {
+		ids[i] = rt.ID
+	}
+	tags, err := tagsByOwner(ctx, r.db, recurringTagLink, ids)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		items[i].Tags = tags[items[i].ID]
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -30,6 +31,15 @@ type CategoryMonthlyAmount struct {
 	Amount   decimal.Decimal `db:"amount"`
 }
 
+// TagMonthlyAmount represents a tag's income and expenses for a specific month.
+type TagMonthlyAmount struct {
+	Tag              string          `db:"tag"`
+	Month            string          `db:"month"`
+	Income           decimal.Decimal `db:"income"`
+	Expenses         decimal.Decimal `db:"expenses"`
+	TransactionCount int             `db:"transaction_count"`
+}
+
 // ReportRepository provides data access for report generation.
 type ReportRepository struct {
 	db *sqlx.DB
@@ -40,12 +50,13 @@ func NewReportRepository(db *sqlx.DB) *ReportRepository {
 	return &ReportRepository{db: db}
 }
 
-// GetMonthlyTotals retrieves total income and expenses for a specific month.
+// GetMonthlyTotals retrieves total income and expenses for a specific month, in
+// the user's base currency.
 func (r *ReportRepository) GetMonthlyTotals(ctx context.Context, userID uuid.UUID, year, month int) (income, expenses decimal.Decimal, err error) {
 	query := `
 		SELECT
-			COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE 0 END), 0) as income,
-			COALESCE(SUM(CASE WHEN type = 'expense' THEN amount ELSE 0 END), 0) as expenses
+			COALESCE(SUM(CASE WHEN type = 'income' THEN base_amount ELSE 0 END), 0) as income,
+			COALESCE(SUM(CASE WHEN type = 'expense' THEN base_amount ELSE 0 END), 0) as expenses
 		FROM transactions
 		WHERE user_id = $1
 		AND EXTRACT(YEAR FROM date) = $2
@@ -60,20 +71,22 @@ func (r *ReportRepository) GetMonthlyTotals(ctx context.Context, userID uuid.UUI
 }
 
 // GetTopExpenseCategories retrieves the top expense categories for a specific month.
-func (r *ReportRepository) GetTopExpenseCategories(ctx context.Context, userID uuid.UUID, year, month int, limit int) ([]CategoryTotal, error) {
-	query := `
+// With byParent, subcategories are counted towards their parent category.
+func (r *ReportRepository) GetTopExpenseCategories(ctx context.Context, userID uuid.UUID, year, month int, limit int, byParent bool) ([]CategoryTotal, error) {
+	query := fmt.Sprintf(`
 		SELECT
-			category,
-			SUM(amount) as amount,
-			COUNT(*) as transaction_count
-		FROM transactions
-		WHERE user_id = $1
-			AND type = 'expense'
-			AND EXTRACT(YEAR FROM date) = $2
-			AND EXTRACT(MONTH FROM date) = $3
-		GROUP BY category
+			%s as category,
+			SUM(l.base_amount) as amount,
+			COUNT(DISTINCT l.transaction_id) as transaction_count
+		FROM transaction_lines l
+		%s
+		WHERE l.user_id = $1
+			AND l.type = 'expense'
+			AND EXTRACT(YEAR FROM l.date) = $2
+			AND EXTRACT(MONTH FROM l.date) = $3
+		GROUP BY 1
 		ORDER BY amount DESC
-		LIMIT $4`
+		LIMIT $4`, reportCategoryColumn(byParent), reportCategoryJoin(byParent))
 
 	var results []CategoryTotal
 	err := r.db.SelectContext(ctx, &results, query, userID, year, month, limit)
@@ -84,9 +97,9 @@ func (r *ReportRepository) GetTopExpenseCategories(ctx context.Context, userID u
 func (r *ReportRepository) GetCategoryAverageForPeriod(ctx context.Context, userID uuid.UUID, category string, startDate, endDate time.Time) (decimal.Decimal, int, error) {
 	query := `
 		SELECT
-			COALESCE(SUM(amount), 0) as total,
+			COALESCE(SUM(base_amount), 0) as total,
 			COUNT(DISTINCT TO_CHAR(date, 'YYYY-MM')) as month_count
-		FROM transactions
+		FROM transaction_lines
 		WHERE user_id = $1
 			AND type = 'expense'
 			AND category = $2
@@ -114,9 +127,9 @@ func (r *ReportRepository) GetCategoryAverageForPeriod(ctx context.Context, user
 func (r *ReportRepository) GetIncomeCategoryAverageForPeriod(ctx context.Context, userID uuid.UUID, category string, startDate, endDate time.Time) (decimal.Decimal, int, error) {
 	query := `
 		SELECT
-			COALESCE(SUM(amount), 0) as total,
+			COALESCE(SUM(base_amount), 0) as total,
 			COUNT(DISTINCT TO_CHAR(date, 'YYYY-MM')) as month_count
-		FROM transactions
+		FROM transaction_lines
 		WHERE user_id = $1
 			AND type = 'income'
 			AND category = $2
@@ -143,8 +156,8 @@ func (r *ReportRepository) GetIncomeCategoryAverageForPeriod(ctx context.Context
 // GetCategoryAmountForMonth retrieves the total spending for a category in a specific month.
 func (r *ReportRepository) GetCategoryAmountForMonth(ctx context.Context, userID uuid.UUID, category string, year, month int) (decimal.Decimal, error) {
 	query := `
-		SELECT COALESCE(SUM(amount), 0)
-		FROM transactions
+		SELECT COALESCE(SUM(base_amount), 0)
+		FROM transaction_lines
 		WHERE user_id = $1
 			AND type = 'expense'
 			AND category = $2
@@ -159,8 +172,8 @@ func (r *ReportRepository) GetCategoryAmountForMonth(ctx context.Context, userID
 // GetIncomeCategoryAmountForMonth retrieves the total income for a category in a specific month.
 func (r *ReportRepository) GetIncomeCategoryAmountForMonth(ctx context.Context, userID uuid.UUID, category string, year, month int) (decimal.Decimal, error) {
 	query := `
-		SELECT COALESCE(SUM(amount), 0)
-		FROM transactions
+		SELECT COALESCE(SUM(base_amount), 0)
+		FROM transaction_lines
 		WHERE user_id = $1
 			AND type = 'income'
 			AND category = $2
@@ -173,15 +186,22 @@ func (r *ReportRepository) GetIncomeCategoryAmountForMonth(ctx context.Context,
 }
 
 // GetCategoryTrendsData retrieves monthly spending by category for trend analysis.
-func (r *ReportRepository) GetCategoryTrendsData(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, categoryLimit int) ([]CategoryMonthlyAmount, error) {
-	query := `
-		WITH top_categories AS (
+// With byParent, subcategories are counted towards their parent category.
+func (r *ReportRepository) GetCategoryTrendsData(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, categoryLimit int, byParent bool) ([]CategoryMonthlyAmount, error) {
+	query := fmt.Sprintf(`
+		WITH lines AS (
+			SELECT %s as category, l.date, l.base_amount AS amount
+			FROM transaction_lines l
+			%s
+			WHERE l.user_id = $1
+				AND l.type = 'expense'
+				AND l.date >= $2
+				AND l.date < $3
+				AND l.base_amount IS NOT NULL
+		),
+		top_categories AS (
 			SELECT category
-			FROM transactions
-			WHERE user_id = $1
-				AND type = 'expense'
-				AND date >= $2
-				AND date < $3
+			FROM lines
 			GROUP BY category
 			ORDER BY SUM(amount) DESC
 			LIMIT $4
@@ -190,25 +210,45 @@ func (r *ReportRepository) GetCategoryTrendsData(ctx context.Context, userID uui
 			t.category,
 			TO_CHAR(t.date, 'YYYY-MM') as month,
 			COALESCE(SUM(t.amount), 0) as amount
-		FROM transactions t
+		FROM lines t
 		INNER JOIN top_categories tc ON t.category = tc.category
-		WHERE t.user_id = $1
-			AND t.type = 'expense'
-			AND t.date >= $2
-			AND t.date < $3
 		GROUP BY t.category, TO_CHAR(t.date, 'YYYY-MM')
-		ORDER BY t.category, month`
+		ORDER BY t.category, month`, reportCategoryColumn(byParent), reportCategoryJoin(byParent))
 
 	var results []CategoryMonthlyAmount
 	err := r.db.SelectContext(ctx, &results, query, userID, startDate, endDate, categoryLimit)
 	return results, err
 }
 
+// GetTagMonthlyAmounts retrieves monthly income and expenses by tag. A transaction
+// with several tags counts towards each of them.
+func (r *ReportRepository) GetTagMonthlyAmounts(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]TagMonthlyAmount, error) {
+	query := `
+		SELECT
+			tg.name as tag,
+			TO_CHAR(t.date, 'YYYY-MM') as month,
+			COALESCE(SUM(CASE WHEN t.type = 'income' THEN t.base_amount ELSE 0 END), 0) as income,
+			COALESCE(SUM(CASE WHEN t.type = 'expense' THEN t.base_amount ELSE 0 END), 0) as expenses,
+			COUNT(*) as transaction_count
+		FROM transactions t
+		JOIN transaction_tags tt ON tt.transaction_id = t.id
+		JOIN tags tg ON tg.id = tt.tag_id
+		WHERE t.user_id = $1
+			AND t.date >= $2
+			AND t.date < $3
+		GROUP BY tg.name, TO_CHAR(t.date, 'YYYY-MM')
+		ORDER BY tg.name, month`
+
+	var results []TagMonthlyAmount
+	err := r.db.SelectContext(ctx, &results, query, userID, startDate, endDate)
+	return results, err
+}
+
 // GetDistinctExpenseCategories retrieves all distinct expense categories for a user in a period.
 func (r *ReportRepository) GetDistinctExpenseCategories(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]string, error) {
 	query := `
 		SELECT DISTINCT category
-		FROM transactions
+		FROM transaction_lines
 		WHERE user_id = $1
 			AND type = 'expense'
 			AND date >= $2
@@ -224,7 +264,7 @@ func (r *ReportRepository) GetDistinctExpenseCategories(ctx context.Context, use
 func (r *ReportRepository) GetDistinctIncomeCategories(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]string, error) {
 	query := `
 		SELECT DISTINCT category
-		FROM transactions
+		FROM transaction_lines
 		WHERE user_id = $1
 			AND type = 'income'
 			AND date >= $2
@@ -236,6 +276,22 @@ func (r *ReportRepository) GetDistinctIncomeCategories(ctx context.Context, user
 	return categories, err
 }
 
+// CountUnconverted returns how many of the user's transactions between two dates,
+// inclusive, have no amount in the base currency yet.
+func (r *ReportRepository) CountUnconverted(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM transactions
+		WHERE user_id = $1
+		AND date >= $2
+		AND date <= $3
+		AND base_amount IS NULL`
+
+	var count int
+	err := r.db.GetContext(ctx, &count, query, userID, startDate, endDate)
+	return count, err
+}
+
 // GetUserCurrency retrieves the user's preferred currency.
 func (r *ReportRepository) GetUserCurrency(ctx context.Context, userID uuid.UUID) (string, error) {
 	query := `SELECT currency FROM users WHERE id = $1`
@@ -249,3 +305,22 @@ func (r *ReportRepository) GetUserCurrency(ctx context.Context, userID uuid.UUID
 	}
 	return currency, nil
 }
+
+// reportCategoryColumn returns the category to group transaction_lines l by:
+// its own category, or the parent category when rolling up.
+func reportCategoryColumn(byParent bool) string {
+	if byParent {
+		return "COALESCE(p.name, l.category)"
+	}
+	return "l.category"
+}
+
+// reportCategoryJoin joins the category and parent category of transaction_lines l
+// when rolling up. Categories missing from the categories table stay as they are.
+func reportCategoryJoin(byParent bool) string {
+	if !byParent {
+		return ""
+	}
+	return `LEFT JOIN categories c ON c.user_id = l.user_id AND c.type = l.type AND c.name = l.category
+		LEFT JOIN categories p ON p.id = c.parent_id`
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+// ErrRuleNotFound is returned when a categorization rule does not exist or belongs to another user
+var ErrRuleNotFound = errors.New("rule not found")
+
+// DescriptionCategory is how often the user filed transactions with a description
+// under a category
+type DescriptionCategory struct {
+	Description string                `db:"description"` // Most common spelling of the description
+	Type        model.TransactionType `db:"type"`
+	Category    string                `db:"category"`
+	Count       int                   `db:"count"`
+}
+
+// RuleRepository stores the user's categorization rules and the tags they add
+type RuleRepository interface {
+	Create(ctx context.Context, rule *model.CategorizationRule) error
+	GetByID(ctx context.Context, userID, id uuid.UUID) (*model.CategorizationRule, error)
+	List(ctx context.Context, userID uuid.UUID) ([]model.CategorizationRule, error)
+	Update(ctx context.Context, rule *model.CategorizationRule) error
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	DescriptionCategories(ctx context.Context, userID uuid.UUID, minCount int) ([]DescriptionCategory, error)
+}
+
+type ruleRepository struct {
+	db *sqlx.DB
+}
+
+// NewRuleRepository creates a new categorization rule repository
+func NewRuleRepository(db *sqlx.DB) RuleRepository {
+	return &ruleRepository{db: db}
+}
+
+var ruleTagLink = tagLink{table: "categorization_rule_tags", column: "rule_id"}
+
+// Create inserts a rule with a new ID together with its tags
+func (r *ruleRepository) Create(ctx context.Context, rule *model.CategorizationRule) error {
+	query := `
+		INSERT INTO categorization_rules (id, user_id, name, priority, enabled, transaction_type, description_contains,
+			description_pattern, min_amount, max_amount, account_id, set_category, set_description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NOW(), NOW())
+		RETURNING created_at, updated_at`
+
+	dbTx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	rule.ID = uuid.New()
+	err = dbTx.QueryRowxContext(ctx, query,
+		rule.ID, rule.UserID, rule.Name, rule.Priority, rule.Enabled, rule.TransactionType, rule.DescriptionContains,
+		rule.DescriptionPattern, rule.MinAmount, rule.MaxAmount, rule.AccountID, rule.SetCategory, rule.SetDescription,
+	).Scan(&rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if err := attachTags(ctx, dbTx, ruleTagLink, rule.UserID, rule.ID, rule.AddTags); err != nil {
+		return err
+	}
+	return dbTx.Commit()
+}
+
+func (r *ruleRepository) GetByID(ctx context.Context, userID, id uuid.UUID) (*model.CategorizationRule, error) {
+	var rule model.CategorizationRule
+	err := r.db.GetContext(ctx, &rule, `SELECT * FROM categorization_rules WHERE id = $1 AND user_id = $2`, id, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRuleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []model.CategorizationRule{rule}
+	if err := r.loadTags(ctx, rules); err != nil {
+		return nil, err
+	}
+	return &rules[0], nil
+}
+
+// List returns the user's rules in the order they run
+func (r *ruleRepository) List(ctx context.Context, userID uuid.UUID) ([]model.CategorizationRule, error) {
+	var rules []model.CategorizationRule
+	err := r.db.SelectContext(ctx, &rules,
+		`SELECT * FROM categorization_rules WHERE user_id = $1 ORDER BY priority, created_at, id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadTags(ctx, rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Update saves a rule and replaces its tags
+func (r *ruleRepository) Update(ctx context.Context, rule *model.CategorizationRule) error {
+	query := `
+		UPDATE categorization_rules
+		SET name = $3, priority = $4, enabled = $5, transaction_type = $6, description_contains = $7,
+			description_pattern = $8, min_amount = $9, max_amount = $10, account_id = $11, set_category = $12,
+			set_description = $13, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+		RETURNING updated_at`
+
+	dbTx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	err = dbTx.QueryRowxContext(ctx, query,
+		rule.ID, rule.UserID, rule.Name, rule.Priority, rule.Enabled, rule.TransactionType, rule.DescriptionContains,
+		rule.DescriptionPattern, rule.MinAmount, rule.MaxAmount, rule.AccountID, rule.SetCategory, rule.SetDescription,
+	).Scan(&rule.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrRuleNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := detachTags(ctx, dbTx, ruleTagLink, rule.ID); err != nil {
+		return err
+	}
+	if err := attachTags(ctx, dbTx, ruleTagLink, rule.UserID, rule.ID, rule.AddTags); err != nil {
+		return err
+	}
+	return dbTx.Commit()
+}
+
+func (r *ruleRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM categorization_rules WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrRuleNotFound
+	}
+	return nil
+}
+
+// DescriptionCategories counts the categories of the user's transactions by
+// description, ignoring case, for descriptions used at least minCount times.
+// Split transactions are left out because their lines carry the categories.
+func (r *ruleRepository) DescriptionCategories(ctx context.Context, userID uuid.UUID, minCount int) ([]DescriptionCategory, error) {
+	query := `
+		SELECT description, type, category, count
+		FROM (
+			SELECT mode() WITHIN GROUP (ORDER BY t.description) AS description, lower(btrim(t.description)) AS key,
+				t.type, t.category, COUNT(*) AS count,
+				SUM(COUNT(*)) OVER (PARTITION BY lower(btrim(t.description)), t.type) AS total
+			FROM transactions t
+			WHERE t.user_id = $1 AND btrim(coalesce(t.description, '')) <> ''
+			AND NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id)
+			GROUP BY lower(btrim(t.description)), t.type, t.category
+		) c
+		WHERE total >= $2
+		ORDER BY key, type, count DESC`
+
+	var counts []DescriptionCategory
+	err := r.db.SelectContext(ctx, &counts, query, userID, minCount)
+	return counts, err
+}
+
+// loadTags fills in the tags of the given rules
+func (r *ruleRepository) loadTags(ctx context.Context, rules []model.CategorizationRule) error {
+	ids := make([]uuid.UUID, len(rules))
+	for i, rule := range rules {
+		ids[i] = rule.ID
+	}
+	tags, err := tagsByOwner(ctx, r.db, ruleTagLink, ids)
+	if err != nil {
+		return err
+	}
+	for i := range rules {
+		rules[i].AddTags = tags[rules[i].ID]
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+func TestRuleRepository_Create_WithTags(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewRuleRepository(db)
+
+	rule := &model.CategorizationRule{
+		UserID:              uuid.New(),
+		Name:                "Coffee",
+		Enabled:             true,
+		DescriptionContains: "highlands",
+		SetCategory:         "Food & Dining",
+		AddTags:             []string{"Coffee"},
+	}
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO categorization_rules`).
+		WithArgs(sqlmock.AnyArg(), rule.UserID, "Coffee", 0, true, nil, "highlands", "", nil, nil, nil, "Food & Dining", "").
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now))
+	mock.ExpectExec(`INSERT INTO tags .* ON CONFLICT \(user_id, \(lower\(name\)\)\) DO NOTHING`).
+		WithArgs(sqlmock.AnyArg(), rule.UserID, "Coffee").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO categorization_rule_tags \(rule_id, tag_id\)`).
+		WithArgs(sqlmock.AnyArg(), rule.UserID, pq.Array([]string{"coffee"})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.Create(context.Background(), rule)
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, rule.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRuleRepository_List(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewRuleRepository(db)
+	userID, ruleID := uuid.New(), uuid.New()
+
+	mock.ExpectQuery(`SELECT \* FROM categorization_rules WHERE user_id = \$1 ORDER BY priority, created_at, id`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "priority", "enabled", "transaction_type",
+			"description_contains", "description_pattern", "min_amount", "max_amount", "account_id", "set_category",
+			"set_description", "created_at", "updated_at"}).
+			AddRow(ruleID, userID, "Grab", 0, true, "expense", "grab", "", "10000.00", nil, nil, "Transportation", "Grab", time.Now(), time.Now()))
+	mock.ExpectQuery(`SELECT l.rule_id AS owner_id, t.name\s+FROM categorization_rule_tags l`).
+		WillReturnRows(sqlmock.NewRows(tagColumns).AddRow(ruleID, "ride"))
+
+	rules, err := repo.List(context.Background(), userID)
+
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, model.TransactionTypeExpense, *rules[0].TransactionType)
+	assert.Equal(t, "10000", rules[0].MinAmount.String())
+	assert.Nil(t, rules[0].MaxAmount)
+	assert.Equal(t, []string{"ride"}, rules[0].AddTags)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRuleRepository_Update_NotFound(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewRuleRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE categorization_rules`).WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	err := repo.Update(context.Background(), &model.CategorizationRule{ID: uuid.New(), UserID: uuid.New(), Name: "Grab"})
+
+	assert.ErrorIs(t, err, ErrRuleNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
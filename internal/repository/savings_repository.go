@@ -11,7 +11,12 @@ import (
 	"github.com/wealthpath/backend/internal/model"
 )
 
-var ErrSavingsGoalNotFound = errors.New("savings goal not found")
+var (
+	ErrSavingsGoalNotFound         = errors.New("savings goal not found")
+	ErrSavingsContributionNotFound = errors.New("savings contribution not found")
+	ErrContributionAlreadyReversed = errors.New("savings contribution already reversed")
+	ErrInsufficientSavings         = errors.New("withdrawal exceeds saved amount")
+)
 
 type SavingsGoalRepository struct {
 	db *sqlx.DB
@@ -83,18 +88,103 @@ func (r *SavingsGoalRepository) Delete(ctx context.Context, id uuid.UUID, userID
 	return nil
 }
 
-func (r *SavingsGoalRepository) AddContribution(ctx context.Context, id uuid.UUID, userID uuid.UUID, amount decimal.Decimal) error {
-	query := `
-		UPDATE savings_goals 
-		SET current_amount = current_amount + $3, updated_at = NOW()
-		WHERE id = $1 AND user_id = $2`
-	_, err := r.db.ExecContext(ctx, query, id, userID, amount)
-	return err
-}
-
 func (r *SavingsGoalRepository) GetTotalSavings(ctx context.Context, userID uuid.UUID) (decimal.Decimal, error) {
 	var total decimal.Decimal
 	query := `SELECT COALESCE(SUM(current_amount), 0) FROM savings_goals WHERE user_id = $1`
 	err := r.db.GetContext(ctx, &total, query, userID)
 	return total, err
 }
+
+// RecordContribution adds an entry to the goal's ledger and applies it to the goal's
+// current amount in one transaction. It returns the updated goal.
+func (r *SavingsGoalRepository) RecordContribution(ctx context.Context, entry *model.SavingsContribution) (*model.SavingsGoal, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Lock the goal so concurrent entries see each other's balance
+	var current decimal.Decimal
+	err = tx.GetContext(ctx, &current,
+		`SELECT COALESCE(current_amount, 0) FROM savings_goals WHERE id = $1 AND user_id = $2 FOR UPDATE`,
+		entry.GoalID, entry.UserID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSavingsGoalNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if current.Add(entry.Amount).IsNegative() {
+		return nil, ErrInsufficientSavings
+	}
+
+	if entry.ReversalOf != nil {
+		var reversed bool
+		err = tx.GetContext(ctx, &reversed,
+			`SELECT EXISTS(SELECT 1 FROM savings_contributions WHERE reversal_of = $1)`,
+			*entry.ReversalOf,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if reversed {
+			return nil, ErrContributionAlreadyReversed
+		}
+	}
+
+	query := `
+		INSERT INTO savings_contributions (id, goal_id, user_id, amount, date, note, transaction_id, reversal_of, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		RETURNING created_at`
+
+	entry.ID = uuid.New()
+	err = tx.QueryRowxContext(ctx, query,
+		entry.ID, entry.GoalID, entry.UserID, entry.Amount, entry.Date,
+		entry.Note, entry.TransactionID, entry.ReversalOf,
+	).Scan(&entry.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	var goal model.SavingsGoal
+	err = tx.GetContext(ctx, &goal, `
+		UPDATE savings_goals
+		SET current_amount = COALESCE(current_amount, 0) + $2, updated_at = NOW()
+		WHERE id = $1
+		RETURNING *`,
+		entry.GoalID, entry.Amount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+func (r *SavingsGoalRepository) GetContribution(ctx context.Context, id uuid.UUID) (*model.SavingsContribution, error) {
+	var entry model.SavingsContribution
+	query := `SELECT * FROM savings_contributions WHERE id = $1`
+	err := r.db.GetContext(ctx, &entry, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSavingsContributionNotFound
+	}
+	return &entry, err
+}
+
+// ListContributions returns a goal's ledger, newest first.
+func (r *SavingsGoalRepository) ListContributions(ctx context.Context, goalID uuid.UUID, limit, offset int) ([]model.SavingsContribution, error) {
+	entries := []model.SavingsContribution{}
+	query := `
+		SELECT c.*, EXISTS(SELECT 1 FROM savings_contributions r WHERE r.reversal_of = c.id) AS reversed
+		FROM savings_contributions c
+		WHERE c.goal_id = $1
+		ORDER BY c.date DESC, c.created_at DESC
+		LIMIT $2 OFFSET $3`
+	err := r.db.SelectContext(ctx, &entries, query, goalID, limit, offset)
+	return entries, err
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+var (
+	// ErrTagNotFound is returned when a tag does not exist or belongs to another user
+	ErrTagNotFound = errors.New("tag not found")
+	// ErrTagExists is returned when the user already has a tag with the name, ignoring case
+	ErrTagExists = errors.New("tag already exists")
+)
+
+// Tag matching modes for TransactionFilters.TagMatch
+const (
+	TagMatchAny = "any"
+	TagMatchAll = "all"
+)
+
+// TagRepository stores the user's tags. Transactions and recurring transactions
+// are linked to tags by ID, so renaming a tag renames it everywhere and deleting
+// it removes it from everything it was on.
+type TagRepository interface {
+	Create(ctx context.Context, tag *model.Tag) error
+	GetByID(ctx context.Context, userID, id uuid.UUID) (*model.Tag, error)
+	List(ctx context.Context, userID uuid.UUID) ([]model.TagUsage, error)
+	Search(ctx context.Context, userID uuid.UUID, query string, limit int) ([]model.Tag, error)
+	Update(ctx context.Context, tag *model.Tag) error
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type tagRepository struct {
+	db *sqlx.DB
+}
+
+// NewTagRepository creates a new tag repository
+func NewTagRepository(db *sqlx.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) Create(ctx context.Context, tag *model.Tag) error {
+	query := `
+		INSERT INTO tags (id, user_id, name, color, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		RETURNING created_at, updated_at`
+
+	tag.ID = uuid.New()
+	err := r.db.QueryRowxContext(ctx, query, tag.ID, tag.UserID, tag.Name, tag.Color).
+		Scan(&tag.CreatedAt, &tag.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrTagExists
+	}
+	return err
+}
+
+func (r *tagRepository) GetByID(ctx context.Context, userID, id uuid.UUID) (*model.Tag, error) {
+	var tag model.Tag
+	err := r.db.GetContext(ctx, &tag, `SELECT * FROM tags WHERE id = $1 AND user_id = $2`, id, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTagNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// List returns the user's tags in alphabetical order with how often each is used
+func (r *tagRepository) List(ctx context.Context, userID uuid.UUID) ([]model.TagUsage, error) {
+	query := `
+		SELECT t.*, COUNT(tt.transaction_id) AS transaction_count
+		FROM tags t
+		LEFT JOIN transaction_tags tt ON tt.tag_id = t.id
+		WHERE t.user_id = $1
+		GROUP BY t.id
+		ORDER BY lower(t.name)`
+
+	var tags []model.TagUsage
+	err := r.db.SelectContext(ctx, &tags, query, userID)
+	return tags, err
+}
+
+// Search returns the user's tags containing query, ignoring case. Tags starting
+// with query come first, then the most used ones.
+func (r *tagRepository) Search(ctx context.Context, userID uuid.UUID, query string, limit int) ([]model.Tag, error) {
+	sqlQuery := `
+		SELECT t.*
+		FROM tags t
+		LEFT JOIN transaction_tags tt ON tt.tag_id = t.id
+		WHERE t.user_id = $1 AND strpos(lower(t.name), lower($2)) > 0
+		GROUP BY t.id
+		ORDER BY strpos(lower(t.name), lower($2)) = 1 DESC, COUNT(tt.transaction_id) DESC, lower(t.name)
+		LIMIT $3`
+
+	var tags []model.Tag
+	err := r.db.SelectContext(ctx, &tags, sqlQuery, userID, query, limit)
+	return tags, err
+}
+
+func (r *tagRepository) Update(ctx context.Context, tag *model.Tag) error {
+	query := `
+		UPDATE tags SET name = $3, color = $4, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+		RETURNING updated_at`
+
+	err := r.db.QueryRowxContext(ctx, query, tag.ID, tag.UserID, tag.Name, tag.Color).Scan(&tag.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTagNotFound
+	}
+	if isUniqueViolation(err) {
+		return ErrTagExists
+	}
+	return err
+}
+
+// Delete removes a tag from the user's transactions and recurring transactions
+// and deletes it
+func (r *tagRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tags WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrTagNotFound
+	}
+	return nil
+}
+
+// tagLink is a table linking tags to the rows of another table
+type tagLink struct {
+	table  string
+	column string
+}
+
+var (
+	transactionTagLink = tagLink{table: "transaction_tags", column: "transaction_id"}
+	recurringTagLink   = tagLink{table: "recurring_transaction_tags", column: "recurring_transaction_id"}
+)
+
+// attachTags links the named tags to a row, creating the tags the user does not
+// have yet. Names match existing tags regardless of case.
+func attachTags(ctx context.Context, dbTx *sqlx.Tx, link tagLink, userID, ownerID uuid.UUID, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		if _, err := dbTx.ExecContext(ctx, `
+			INSERT INTO tags (id, user_id, name, created_at, updated_at)
+			VALUES ($1, $2, $3, NOW(), NOW())
+			ON CONFLICT (user_id, (lower(name))) DO NOTHING`,
+			uuid.New(), userID, name,
+		); err != nil {
+			return err
+		}
+		lowered[i] = strings.ToLower(name)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (%s, tag_id)
+		SELECT $1, id FROM tags WHERE user_id = $2 AND lower(name) = ANY($3)
+		ON CONFLICT DO NOTHING`, link.table, link.column)
+	_, err := dbTx.ExecContext(ctx, query, ownerID, userID, pq.Array(lowered))
+	return err
+}
+
+// detachTags removes every tag from a row
+func detachTags(ctx context.Context, dbTx *sqlx.Tx, link tagLink, ownerID uuid.UUID) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE %s = $1`, link.table, link.column)
+	_, err := dbTx.ExecContext(ctx, query, ownerID)
+	return err
+}
+
+// tagsByOwner returns the tag names of the given rows with one query, keyed by row
+func tagsByOwner(ctx context.Context, db sqlx.QueryerContext, link tagLink, ownerIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	tags := make(map[uuid.UUID][]string)
+	if len(ownerIDs) == 0 {
+		return tags, nil
+	}
+
+	ids := make([]string, len(ownerIDs))
+	for i, id := range ownerIDs {
+		ids[i] = id.String()
+	}
+
+	query := fmt.Sprintf(`
+		SELECT l.%s AS owner_id, t.name
+		FROM %s l
+		JOIN tags t ON t.id = l.tag_id
+		WHERE l.%s = ANY($1::uuid[])
+		ORDER BY lower(t.name)`, link.column, link.table, link.column)
+
+	var rows []struct {
+		OwnerID uuid.UUID `db:"owner_id"`
+		Name    string    `db:"name"`
+	}
+	if err := sqlx.SelectContext(ctx, db, &rows, query, pq.Array(ids)); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		tags[row.OwnerID] = append(tags[row.OwnerID], row.Name)
+	}
+	return tags, nil
+}
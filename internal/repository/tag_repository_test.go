@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+func TestTagRepository_Update(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		setupMock func(mock sqlmock.Sqlmock, tag *model.Tag)
+		wantErr   error
+	}{
+		{
+			name: "success",
+			setupMock: func(mock sqlmock.Sqlmock, tag *model.Tag) {
+				mock.ExpectQuery(`UPDATE tags SET name = \$3, color = \$4`).
+					WithArgs(tag.ID, tag.UserID, "Vacation", "#FF9800").
+					WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(time.Now()))
+			},
+		},
+		{
+			name: "name taken",
+			setupMock: func(mock sqlmock.Sqlmock, tag *model.Tag) {
+				mock.ExpectQuery(`UPDATE tags`).WillReturnError(&pq.Error{Code: "23505"})
+			},
+			wantErr: ErrTagExists,
+		},
+		{
+			name: "not found",
+			setupMock: func(mock sqlmock.Sqlmock, tag *model.Tag) {
+				mock.ExpectQuery(`UPDATE tags`).WillReturnError(sql.ErrNoRows)
+			},
+			wantErr: ErrTagNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := newMockDB(t)
+			defer func() { _ = db.Close() }()
+			repo := NewTagRepository(db)
+
+			tag := &model.Tag{ID: uuid.New(), UserID: uuid.New(), Name: "Vacation", Color: "#FF9800"}
+			tt.setupMock(mock, tag)
+
+			err := repo.Update(context.Background(), tag)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTagRepository_Delete_NotFound(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewTagRepository(db)
+	userID, id := uuid.New(), uuid.New()
+
+	mock.ExpectExec(`DELETE FROM tags WHERE id = \$1 AND user_id = \$2`).
+		WithArgs(id, userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.Delete(context.Background(), userID, id)
+
+	assert.ErrorIs(t, err, ErrTagNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagRepository_Search(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewTagRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectQuery(`SELECT t\.\*\s+FROM tags t\s+LEFT JOIN transaction_tags tt .* LIMIT \$3`).
+		WithArgs(userID, "vac", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "color", "created_at", "updated_at"}).
+			AddRow(uuid.New(), userID, "Vacation", "", time.Now(), time.Now()))
+
+	tags, err := repo.Search(context.Background(), userID, "vac", 10)
+
+	assert.NoError(t, err)
+	if assert.Len(t, tags, 1) {
+		assert.Equal(t, "Vacation", tags[0].Name)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/shopspring/decimal"
 	"github.com/wealthpath/backend/internal/model"
 )
@@ -22,16 +24,41 @@ func NewTransactionRepository(db *sqlx.DB) *TransactionRepository {
 	return &TransactionRepository{db: db}
 }
 
+// Create inserts a transaction together with its split lines and tags
 func (r *TransactionRepository) Create(ctx context.Context, tx *model.Transaction) error {
+	dbTx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	if err := insertTransaction(ctx, dbTx, tx); err != nil {
+		return err
+	}
+	return dbTx.Commit()
+}
+
+// insertTransaction inserts a transaction with a new ID, its split lines and its
+// tags inside a database transaction
+func insertTransaction(ctx context.Context, dbTx *sqlx.Tx, tx *model.Transaction) error {
 	query := `
-		INSERT INTO transactions (id, user_id, type, amount, currency, category, description, date, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		INSERT INTO transactions (id, user_id, type, amount, currency, category, description, date, account_id, import_batch_id, external_id, base_currency, exchange_rate, base_amount, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NOW(), NOW())
 		RETURNING created_at, updated_at`
 
 	tx.ID = uuid.New()
-	return r.db.QueryRowxContext(ctx, query,
-		tx.ID, tx.UserID, tx.Type, tx.Amount, tx.Currency, tx.Category, tx.Description, tx.Date,
+	err := dbTx.QueryRowxContext(ctx, query,
+		tx.ID, tx.UserID, tx.Type, tx.Amount, tx.Currency, tx.Category, tx.Description, tx.Date, tx.AccountID, tx.ImportBatchID, tx.ExternalID,
+		tx.BaseCurrency, tx.ExchangeRate, tx.BaseAmount,
 	).Scan(&tx.CreatedAt, &tx.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := insertSplits(ctx, dbTx, tx); err != nil {
+		return err
+	}
+	return attachTags(ctx, dbTx, transactionTagLink, tx.UserID, tx.ID, tx.Tags)
 }
 
 func (r *TransactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Transaction, error) {
@@ -41,32 +68,64 @@ func (r *TransactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*mod
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrTransactionNotFound
 	}
-	return &tx, err
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := []model.Transaction{tx}
+	if err := r.loadSplits(ctx, r.db, transactions); err != nil {
+		return nil, err
+	}
+	if err := r.loadTags(ctx, r.db, transactions); err != nil {
+		return nil, err
+	}
+	return &transactions[0], nil
 }
 
-func (r *TransactionRepository) List(ctx context.Context, userID uuid.UUID, filters TransactionFilters) ([]model.Transaction, error) {
-	var transactions []model.Transaction
-	query := `
-		SELECT * FROM transactions
+// transactionFilterWhere selects the transactions matching TransactionFilters,
+// with the arguments from transactionFilterArgs as $1 to $13
+const transactionFilterWhere = `
 		WHERE user_id = $1
 		AND ($2::text IS NULL OR type = $2)
-		AND ($3::text IS NULL OR category = $3)
-		AND ($4::text[] IS NULL OR category = ANY($4))
+		AND ($3::text IS NULL OR category = $3 OR id IN (
+			SELECT transaction_id FROM transaction_splits WHERE category = $3
+		))
+		AND ($4::text[] IS NULL OR category = ANY($4) OR id IN (
+			SELECT transaction_id FROM transaction_splits WHERE category = ANY($4)
+		))
 		AND ($5::text IS NULL OR description ILIKE '%' || $5 || '%')
 		AND ($6::numeric IS NULL OR amount >= $6)
 		AND ($7::numeric IS NULL OR amount <= $7)
 		AND ($8::timestamp IS NULL OR date >= $8)
 		AND ($9::timestamp IS NULL OR date <= $9)
-		ORDER BY date DESC, created_at DESC
-		LIMIT $10 OFFSET $11`
+		AND ($10::uuid IS NULL OR account_id = $10)
+		AND ($11::text[] IS NULL OR id IN (
+			SELECT tt.transaction_id
+			FROM transaction_tags tt
+			JOIN tags tg ON tg.id = tt.tag_id
+			WHERE lower(tg.name) = ANY($11)
+			GROUP BY tt.transaction_id
+			HAVING NOT $12::boolean OR COUNT(*) = cardinality($11::text[])
+		))
+		AND ($13::uuid[] IS NULL OR id = ANY($13))`
 
+// transactionFilterArgs returns the arguments of transactionFilterWhere
+func transactionFilterArgs(userID uuid.UUID, filters TransactionFilters) []interface{} {
 	// Convert categories slice to pq.StringArray for PostgreSQL
 	var categories interface{}
 	if len(filters.Categories) > 0 {
 		categories = filters.Categories
 	}
+	var ids interface{}
+	if filters.IDs != nil {
+		keys := make([]string, len(filters.IDs))
+		for i, id := range filters.IDs {
+			keys[i] = id.String()
+		}
+		ids = pq.Array(keys)
+	}
 
-	err := r.db.SelectContext(ctx, &transactions, query,
+	return []interface{}{
 		userID,
 		filters.Type,
 		filters.Category,
@@ -76,22 +135,192 @@ func (r *TransactionRepository) List(ctx context.Context, userID uuid.UUID, filt
 		filters.MaxAmount,
 		filters.StartDate,
 		filters.EndDate,
-		filters.Limit,
-		filters.Offset,
-	)
-	return transactions, err
+		filters.AccountID,
+		tagFilter(filters.Tags),
+		filters.TagMatch == TagMatchAll,
+		ids,
+	}
+}
+
+// List returns the user's transactions matching the filters, newest first, with
+// their splits and tags. Pages are picked by Limit and either Offset or one of
+// the After and Before keys.
+func (r *TransactionRepository) List(ctx context.Context, userID uuid.UUID, filters TransactionFilters) ([]model.Transaction, error) {
+	args := append(transactionFilterArgs(userID, filters), filters.Limit, filters.Offset)
+	keyset, order := "", "DESC"
+	switch {
+	case filters.After != nil:
+		keyset = `
+		AND (date, created_at, id) < ($16::date, $17::timestamptz, $18::uuid)`
+		args = append(args, filters.After.Date, filters.After.CreatedAt, filters.After.ID)
+	case filters.Before != nil:
+		// Walk back from the key, nearest first, and reverse the page below
+		keyset, order = `
+		AND (date, created_at, id) > ($16::date, $17::timestamptz, $18::uuid)`, "ASC"
+		args = append(args, filters.Before.Date, filters.Before.CreatedAt, filters.Before.ID)
+	}
+
+	query := `
+		SELECT * FROM transactions` + transactionFilterWhere + keyset + `
+		ORDER BY date ` + order + `, created_at ` + order + `, id ` + order + `
+		LIMIT $14 OFFSET $15`
+
+	var transactions []model.Transaction
+	if err := r.db.SelectContext(ctx, &transactions, query, args...); err != nil {
+		return nil, err
+	}
+	if filters.Before != nil {
+		for i, j := 0, len(transactions)-1; i < j; i, j = i+1, j-1 {
+			transactions[i], transactions[j] = transactions[j], transactions[i]
+		}
+	}
+	if err := r.loadSplits(ctx, r.db, transactions); err != nil {
+		return nil, err
+	}
+	if err := r.loadTags(ctx, r.db, transactions); err != nil {
+		return nil, err
+	}
+	return transactions, nil
 }
 
+// Totals counts the user's transactions matching the filters and sums their
+// income and expenses in the base currency. Transactions not converted yet are
+// counted as unconverted instead. Limit, Offset, After and Before are ignored.
+func (r *TransactionRepository) Totals(ctx context.Context, userID uuid.UUID, filters TransactionFilters) (*model.TransactionTotals, error) {
+	query := `
+		SELECT COUNT(*) AS count,
+			COALESCE(SUM(base_amount) FILTER (WHERE type = 'income'), 0) AS income,
+			COALESCE(SUM(base_amount) FILTER (WHERE type = 'expense'), 0) AS expenses,
+			COUNT(*) FILTER (WHERE base_amount IS NULL) AS unconverted
+		FROM transactions` + transactionFilterWhere
+
+	var totals model.TransactionTotals
+	if err := r.db.GetContext(ctx, &totals, query, transactionFilterArgs(userID, filters)...); err != nil {
+		return nil, err
+	}
+	return &totals, nil
+}
+
+// Update saves a transaction and replaces its split lines and tags
 func (r *TransactionRepository) Update(ctx context.Context, tx *model.Transaction) error {
+	dbTx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	if err := updateTransaction(ctx, dbTx, tx); err != nil {
+		return err
+	}
+	return dbTx.Commit()
+}
+
+// updateTransaction saves a transaction and replaces its split lines and tags
+// inside a database transaction
+func updateTransaction(ctx context.Context, dbTx *sqlx.Tx, tx *model.Transaction) error {
 	query := `
 		UPDATE transactions 
-		SET type = $2, amount = $3, currency = $4, category = $5, description = $6, date = $7, updated_at = NOW()
+		SET type = $2, amount = $3, currency = $4, category = $5, description = $6, date = $7, account_id = $9,
+			base_currency = $10, exchange_rate = $11, base_amount = $12, updated_at = NOW()
 		WHERE id = $1 AND user_id = $8
 		RETURNING updated_at`
-	result := r.db.QueryRowxContext(ctx, query,
-		tx.ID, tx.Type, tx.Amount, tx.Currency, tx.Category, tx.Description, tx.Date, tx.UserID,
-	)
-	return result.Scan(&tx.UpdatedAt)
+
+	err := dbTx.QueryRowxContext(ctx, query,
+		tx.ID, tx.Type, tx.Amount, tx.Currency, tx.Category, tx.Description, tx.Date, tx.UserID, tx.AccountID,
+		tx.BaseCurrency, tx.ExchangeRate, tx.BaseAmount,
+	).Scan(&tx.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if _, err := dbTx.ExecContext(ctx, `DELETE FROM transaction_splits WHERE transaction_id = $1`, tx.ID); err != nil {
+		return err
+	}
+	if err := insertSplits(ctx, dbTx, tx); err != nil {
+		return err
+	}
+	if err := detachTags(ctx, dbTx, transactionTagLink, tx.ID); err != nil {
+		return err
+	}
+	return attachTags(ctx, dbTx, transactionTagLink, tx.UserID, tx.ID, tx.Tags)
+}
+
+// BulkChange decides what a bulk action does to the transactions it selected:
+// the ones to save and the IDs of the ones to delete
+type BulkChange func(txs []model.Transaction) (updated []model.Transaction, deleted []uuid.UUID, err error)
+
+// ApplyBulk selects the user's transactions matching the filters, newest first
+// with their splits and tags, and applies the change to them in one database
+// transaction. The selected rows are locked until the change is saved, so it
+// never overwrites a concurrent edit. If the change fails or a transaction is
+// missing, nothing is changed; missing ones return ErrTransactionNotFound. A
+// dry run reads the rows without locking them and saves nothing.
+func (r *TransactionRepository) ApplyBulk(ctx context.Context, userID uuid.UUID, filters TransactionFilters, dryRun bool, change BulkChange) error {
+	dbTx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dbTx.Rollback() }()
+
+	query := `
+		SELECT * FROM transactions` + transactionFilterWhere + `
+		ORDER BY date DESC, created_at DESC, id DESC
+		LIMIT $14 OFFSET $15`
+	if !dryRun {
+		query += `
+		FOR UPDATE`
+	}
+	args := append(transactionFilterArgs(userID, filters), filters.Limit, filters.Offset)
+
+	var transactions []model.Transaction
+	if err := dbTx.SelectContext(ctx, &transactions, query, args...); err != nil {
+		return err
+	}
+	if err := r.loadSplits(ctx, dbTx, transactions); err != nil {
+		return err
+	}
+	if err := r.loadTags(ctx, dbTx, transactions); err != nil {
+		return err
+	}
+
+	updated, deleted, err := change(transactions)
+	if err != nil {
+		return err
+	}
+	if dryRun || (len(updated) == 0 && len(deleted) == 0) {
+		return nil
+	}
+
+	for i := range updated {
+		updated[i].UserID = userID
+		err := updateTransaction(ctx, dbTx, &updated[i])
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTransactionNotFound
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(deleted) > 0 {
+		keys := make([]string, len(deleted))
+		for i, id := range deleted {
+			keys[i] = id.String()
+		}
+		result, err := dbTx.ExecContext(ctx,
+			`DELETE FROM transactions WHERE user_id = $1 AND id = ANY($2::uuid[])`, userID, pq.Array(keys))
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows != int64(len(deleted)) {
+			return ErrTransactionNotFound
+		}
+	}
+	return dbTx.Commit()
 }
 
 func (r *TransactionRepository) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
@@ -110,11 +339,13 @@ func (r *TransactionRepository) Delete(ctx context.Context, id uuid.UUID, userID
 	return nil
 }
 
+// GetMonthlyTotals returns a month's income and expenses in the user's base
+// currency. Transactions not converted yet are left out.
 func (r *TransactionRepository) GetMonthlyTotals(ctx context.Context, userID uuid.UUID, year int, month int) (income, expenses decimal.Decimal, err error) {
 	query := `
 		SELECT 
-			COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE 0 END), 0) as income,
-			COALESCE(SUM(CASE WHEN type = 'expense' THEN amount ELSE 0 END), 0) as expenses
+			COALESCE(SUM(CASE WHEN type = 'income' THEN base_amount ELSE 0 END), 0) as income,
+			COALESCE(SUM(CASE WHEN type = 'expense' THEN base_amount ELSE 0 END), 0) as expenses
 		FROM transactions
 		WHERE user_id = $1 
 		AND EXTRACT(YEAR FROM date) = $2 
@@ -128,10 +359,27 @@ func (r *TransactionRepository) GetMonthlyTotals(ctx context.Context, userID uui
 	return result.Income, result.Expenses, err
 }
 
-func (r *TransactionRepository) GetExpensesByCategory(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) (map[string]decimal.Decimal, error) {
+// GetTotalsForPeriod returns total income and expenses in the base currency between two dates, inclusive
+func (r *TransactionRepository) GetTotalsForPeriod(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) (income, expenses decimal.Decimal, err error) {
 	query := `
-		SELECT category, SUM(amount) as total
+		SELECT
+			COALESCE(SUM(CASE WHEN type = 'income' THEN base_amount ELSE 0 END), 0) as income,
+			COALESCE(SUM(CASE WHEN type = 'expense' THEN base_amount ELSE 0 END), 0) as expenses
 		FROM transactions
+		WHERE user_id = $1 AND date >= $2 AND date <= $3`
+
+	var result struct {
+		Income   decimal.Decimal `db:"income"`
+		Expenses decimal.Decimal `db:"expenses"`
+	}
+	err = r.db.GetContext(ctx, &result, query, userID, startDate, endDate)
+	return result.Income, result.Expenses, err
+}
+
+func (r *TransactionRepository) GetExpensesByCategory(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) (map[string]decimal.Decimal, error) {
+	query := `
+		SELECT category, SUM(base_amount) as total
+		FROM transaction_lines
 		WHERE user_id = $1 AND type = 'expense' AND date >= $2 AND date <= $3
 		GROUP BY category`
 
@@ -153,8 +401,8 @@ func (r *TransactionRepository) GetExpensesByCategory(ctx context.Context, userI
 
 func (r *TransactionRepository) GetSpentByCategory(ctx context.Context, userID uuid.UUID, category string, startDate, endDate time.Time) (decimal.Decimal, error) {
 	query := `
-		SELECT COALESCE(SUM(amount), 0)
-		FROM transactions
+		SELECT COALESCE(SUM(base_amount), 0)
+		FROM transaction_lines
 		WHERE user_id = $1 AND type = 'expense' AND category = $2 AND date >= $3 AND date <= $4`
 
 	var spent decimal.Decimal
@@ -165,16 +413,94 @@ func (r *TransactionRepository) GetSpentByCategory(ctx context.Context, userID u
 func (r *TransactionRepository) GetRecentTransactions(ctx context.Context, userID uuid.UUID, limit int) ([]model.Transaction, error) {
 	var transactions []model.Transaction
 	query := `SELECT * FROM transactions WHERE user_id = $1 ORDER BY date DESC, created_at DESC LIMIT $2`
-	err := r.db.SelectContext(ctx, &transactions, query, userID, limit)
-	return transactions, err
+	if err := r.db.SelectContext(ctx, &transactions, query, userID, limit); err != nil {
+		return nil, err
+	}
+	if err := r.loadSplits(ctx, r.db, transactions); err != nil {
+		return nil, err
+	}
+	if err := r.loadTags(ctx, r.db, transactions); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// insertSplits stores the split lines of a transaction in order
+func insertSplits(ctx context.Context, dbTx *sqlx.Tx, tx *model.Transaction) error {
+	query := `
+		INSERT INTO transaction_splits (id, transaction_id, category, amount, note, position)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	for i := range tx.Splits {
+		split := &tx.Splits[i]
+		split.ID = uuid.New()
+		split.TransactionID = tx.ID
+		split.Position = i
+		if _, err := dbTx.ExecContext(ctx, query,
+			split.ID, split.TransactionID, split.Category, split.Amount, split.Note, split.Position,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadSplits fills in the split lines of the given transactions with one query
+func (r *TransactionRepository) loadSplits(ctx context.Context, db sqlx.QueryerContext, transactions []model.Transaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(transactions))
+	for i, tx := range transactions {
+		ids[i] = tx.ID.String()
+	}
+
+	var splits []model.TransactionSplit
+	query := `
+		SELECT * FROM transaction_splits
+		WHERE transaction_id = ANY($1::uuid[])
+		ORDER BY transaction_id, position`
+	if err := sqlx.SelectContext(ctx, db, &splits, query, pq.Array(ids)); err != nil {
+		return err
+	}
+
+	byTransaction := make(map[uuid.UUID][]model.TransactionSplit)
+	for _, split := range splits {
+		byTransaction[split.TransactionID] = append(byTransaction[split.TransactionID], split)
+	}
+	for i := range transactions {
+		transactions[i].Splits = byTransaction[transactions[i].ID]
+	}
+	return nil
+}
+
+// loadTags fills in the tags of the given transactions with one query
+func (r *TransactionRepository) loadTags(ctx context.Context, db sqlx.QueryerContext, transactions []model.Transaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(transactions))
+	for i, tx := range transactions {
+		ids[i] = tx.ID
+	}
+	tags, err := tagsByOwner(ctx, db, transactionTagLink, ids)
+	if err != nil {
+		return err
+	}
+	for i := range transactions {
+		transactions[i].Tags = tags[transactions[i].ID]
+	}
+	return nil
 }
 
 func (r *TransactionRepository) GetMonthlyComparison(ctx context.Context, userID uuid.UUID, months int) ([]model.MonthlyComparison, error) {
 	query := `
 		SELECT 
 			TO_CHAR(date, 'YYYY-MM') as month,
-			COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE 0 END), 0) as income,
-			COALESCE(SUM(CASE WHEN type = 'expense' THEN amount ELSE 0 END), 0) as expenses
+			COALESCE(SUM(CASE WHEN type = 'income' THEN base_amount ELSE 0 END), 0) as income,
+			COALESCE(SUM(CASE WHEN type = 'expense' THEN base_amount ELSE 0 END), 0) as expenses
 		FROM transactions
 		WHERE user_id = $1 AND date >= NOW() - INTERVAL '%d months'
 		GROUP BY TO_CHAR(date, 'YYYY-MM')
@@ -194,6 +520,42 @@ type TransactionFilters struct {
 	MaxAmount  *decimal.Decimal
 	StartDate  *time.Time
 	EndDate    *time.Time
+	AccountID  *uuid.UUID
+	Tags       []string    // Tag names, matched regardless of case
+	TagMatch   string      // TagMatchAny (default) or TagMatchAll of Tags
+	IDs        []uuid.UUID // Only these transactions, when not nil
 	Limit      int
 	Offset     int
+	After      *TransactionKey // Only transactions listed after this key
+	Before     *TransactionKey // Only transactions listed before this key
+}
+
+// TransactionKey is the position of a transaction in the list, which is ordered
+// by date, then creation time, then ID, newest first
+type TransactionKey struct {
+	Date      time.Time
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// tagFilter returns the distinct lower-cased tag names to filter on, or nil when
+// not filtering by tag
+func tagFilter(tags []string) interface{} {
+	if len(tags) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(tags))
+	lowered := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		lowered = append(lowered, tag)
+	}
+	if len(lowered) == 0 {
+		return nil
+	}
+	return pq.Array(lowered)
 }
@@ -3,14 +3,17 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/wealthpath/backend/internal/model"
 )
 
@@ -24,6 +27,11 @@ func newMockDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
 	return sqlxDB, mock
 }
 
+var (
+	splitColumns = []string{"id", "transaction_id", "category", "amount", "note", "position"}
+	tagColumns   = []string{"owner_id", "name"}
+)
+
 func TestNewTransactionRepository(t *testing.T) {
 	t.Parallel()
 
@@ -55,9 +63,12 @@ func TestTransactionRepository_Create(t *testing.T) {
 	now := time.Now()
 	rows := sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now)
 
+	mock.ExpectBegin()
 	mock.ExpectQuery(`INSERT INTO transactions`).
-		WithArgs(sqlmock.AnyArg(), tx.UserID, tx.Type, tx.Amount, tx.Currency, tx.Category, tx.Description, tx.Date).
+		WithArgs(sqlmock.AnyArg(), tx.UserID, tx.Type, tx.Amount, tx.Currency, tx.Category, tx.Description, tx.Date, tx.AccountID, tx.ImportBatchID, tx.ExternalID,
+			tx.BaseCurrency, tx.ExchangeRate, tx.BaseAmount).
 		WillReturnRows(rows)
+	mock.ExpectCommit()
 
 	err := repo.Create(ctx, tx)
 
@@ -66,6 +77,87 @@ func TestTransactionRepository_Create(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestTransactionRepository_Create_WithSplits(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewTransactionRepository(db)
+
+	ctx := context.Background()
+	tx := &model.Transaction{
+		UserID:   uuid.New(),
+		Type:     model.TransactionTypeExpense,
+		Amount:   decimal.NewFromInt(300),
+		Currency: "USD",
+		Category: "Groceries",
+		Date:     time.Now(),
+		Splits: []model.TransactionSplit{
+			{Category: "Groceries", Amount: decimal.NewFromInt(200)},
+			{Category: "Household", Amount: decimal.NewFromInt(100), Note: "Detergent"},
+		},
+	}
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO transactions`).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now))
+	mock.ExpectExec(`INSERT INTO transaction_splits`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "Groceries", decimal.NewFromInt(200), "", 0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO transaction_splits`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "Household", decimal.NewFromInt(100), "Detergent", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.Create(ctx, tx)
+
+	assert.NoError(t, err)
+	for i, split := range tx.Splits {
+		assert.Equal(t, tx.ID, split.TransactionID)
+		assert.Equal(t, i, split.Position)
+		assert.NotEqual(t, uuid.Nil, split.ID)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionRepository_Create_WithTags(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewTransactionRepository(db)
+
+	tx := &model.Transaction{
+		UserID:   uuid.New(),
+		Type:     model.TransactionTypeExpense,
+		Amount:   decimal.NewFromInt(120),
+		Currency: "USD",
+		Category: "Travel",
+		Date:     time.Now(),
+		Tags:     []string{"Vacation", "Family"},
+	}
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO transactions`).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now))
+	for _, name := range tx.Tags {
+		mock.ExpectExec(`INSERT INTO tags .* ON CONFLICT \(user_id, \(lower\(name\)\)\) DO NOTHING`).
+			WithArgs(sqlmock.AnyArg(), tx.UserID, name).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectExec(`INSERT INTO transaction_tags \(transaction_id, tag_id\)`).
+		WithArgs(sqlmock.AnyArg(), tx.UserID, pq.Array([]string{"vacation", "family"})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := repo.Create(context.Background(), tx)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestTransactionRepository_GetByID(t *testing.T) {
 	t.Parallel()
 
@@ -83,6 +175,12 @@ func TestTransactionRepository_GetByID(t *testing.T) {
 				mock.ExpectQuery(`SELECT \* FROM transactions WHERE id = \$1`).
 					WithArgs(id).
 					WillReturnRows(rows)
+				mock.ExpectQuery(`SELECT \* FROM transaction_splits`).
+					WillReturnRows(sqlmock.NewRows(splitColumns).
+						AddRow(uuid.New(), id, "Food", decimal.NewFromFloat(30), "", 0).
+						AddRow(uuid.New(), id, "Drinks", decimal.NewFromFloat(20), "Coffee", 1))
+				mock.ExpectQuery(`SELECT l.transaction_id AS owner_id, t.name\s+FROM transaction_tags l`).
+					WillReturnRows(sqlmock.NewRows(tagColumns).AddRow(id, "work"))
 			},
 			wantErr: false,
 		},
@@ -122,6 +220,8 @@ func TestTransactionRepository_GetByID(t *testing.T) {
 				assert.NoError(t, err)
 				assert.NotNil(t, tx)
 				assert.Equal(t, txID, tx.ID)
+				assert.Len(t, tx.Splits, 2)
+				assert.Equal(t, []string{"work"}, tx.Tags)
 			}
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
@@ -146,10 +246,15 @@ func TestTransactionRepository_List(t *testing.T) {
 		AddRow(uuid.New(), userID, "expense", decimal.NewFromFloat(50), "USD", "Food", "Lunch", time.Now(), time.Now(), time.Now()).
 		AddRow(uuid.New(), userID, "income", decimal.NewFromFloat(5000), "USD", "Salary", "Monthly", time.Now(), time.Now(), time.Now())
 
-	// Parameters: userID, type, category, categories[], search, minAmount, maxAmount, startDate, endDate, limit, offset
+	// Parameters: userID, type, category, categories[], search, minAmount, maxAmount, startDate, endDate,
+	// accountID, tags[], match all tags, IDs[], limit, offset
 	mock.ExpectQuery(`SELECT \* FROM transactions`).
-		WithArgs(userID, nil, nil, nil, nil, nil, nil, nil, nil, 20, 0).
+		WithArgs(userID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, false, nil, 20, 0).
 		WillReturnRows(rows)
+	mock.ExpectQuery(`SELECT \* FROM transaction_splits`).
+		WillReturnRows(sqlmock.NewRows(splitColumns))
+	mock.ExpectQuery(`FROM transaction_tags l`).
+		WillReturnRows(sqlmock.NewRows(tagColumns))
 
 	txs, err := repo.List(ctx, userID, filters)
 
@@ -158,6 +263,119 @@ func TestTransactionRepository_List(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestTransactionRepository_List_TagFilter(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewTransactionRepository(db)
+
+	userID := uuid.New()
+	filters := TransactionFilters{
+		Tags:     []string{"Vacation", " vacation", "Family"},
+		TagMatch: TagMatchAll,
+		Limit:    20,
+	}
+
+	mock.ExpectQuery(`SELECT \* FROM transactions`).
+		WithArgs(userID, nil, nil, nil, nil, nil, nil, nil, nil, nil, pq.Array([]string{"vacation", "family"}), true, nil, 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	txs, err := repo.List(context.Background(), userID, filters)
+
+	assert.NoError(t, err)
+	assert.Empty(t, txs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionRepository_List_Keyset(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	key := TransactionKey{Date: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), CreatedAt: time.Now(), ID: uuid.New()}
+	older, newer := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		filters TransactionFilters
+		query   string
+		rows    []uuid.UUID // As the database returns them
+		wantIDs []uuid.UUID
+	}{
+		{
+			name:    "after a key",
+			filters: TransactionFilters{Limit: 3, After: &key},
+			query:   `AND \(date, created_at, id\) < \(\$16::date, \$17::timestamptz, \$18::uuid\)\s+ORDER BY date DESC, created_at DESC, id DESC`,
+			rows:    []uuid.UUID{newer, older},
+			wantIDs: []uuid.UUID{newer, older},
+		},
+		{
+			name:    "before a key, nearest first",
+			filters: TransactionFilters{Limit: 3, Before: &key},
+			query:   `AND \(date, created_at, id\) > \(\$16::date, \$17::timestamptz, \$18::uuid\)\s+ORDER BY date ASC, created_at ASC, id ASC`,
+			rows:    []uuid.UUID{older, newer},
+			wantIDs: []uuid.UUID{newer, older},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := newMockDB(t)
+			defer func() { _ = db.Close() }()
+			repo := NewTransactionRepository(db)
+
+			rows := sqlmock.NewRows([]string{"id", "user_id"})
+			for _, id := range tt.rows {
+				rows.AddRow(id, userID)
+			}
+			mock.ExpectQuery(tt.query).
+				WithArgs(userID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, false, nil, 3, 0, key.Date, key.CreatedAt, key.ID).
+				WillReturnRows(rows)
+			mock.ExpectQuery(`SELECT \* FROM transaction_splits`).
+				WillReturnRows(sqlmock.NewRows(splitColumns))
+			mock.ExpectQuery(`FROM transaction_tags l`).
+				WillReturnRows(sqlmock.NewRows(tagColumns))
+
+			txs, err := repo.List(context.Background(), userID, tt.filters)
+
+			require.NoError(t, err)
+			require.Len(t, txs, len(tt.wantIDs))
+			for i, id := range tt.wantIDs {
+				assert.Equal(t, id, txs[i].ID)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTransactionRepository_Totals(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewTransactionRepository(db)
+
+	userID := uuid.New()
+	expense := "expense"
+	filters := TransactionFilters{Type: &expense, Limit: 20, After: &TransactionKey{ID: uuid.New()}}
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) AS count`).
+		WithArgs(userID, &expense, nil, nil, nil, nil, nil, nil, nil, nil, nil, false, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "income", "expenses", "unconverted"}).AddRow(3, "0", "150000.50", 1))
+
+	totals, err := repo.Totals(context.Background(), userID, filters)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, totals.Count)
+	assert.True(t, totals.Income.IsZero())
+	assert.Equal(t, "150000.5", totals.Expenses.String())
+	assert.Equal(t, 1, totals.Unconverted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestTransactionRepository_Update(t *testing.T) {
 	t.Parallel()
 
@@ -180,9 +398,18 @@ func TestTransactionRepository_Update(t *testing.T) {
 	now := time.Now()
 	rows := sqlmock.NewRows([]string{"updated_at"}).AddRow(now)
 
+	mock.ExpectBegin()
 	mock.ExpectQuery(`UPDATE transactions`).
-		WithArgs(tx.ID, tx.Type, tx.Amount, tx.Currency, tx.Category, tx.Description, tx.Date, tx.UserID).
+		WithArgs(tx.ID, tx.Type, tx.Amount, tx.Currency, tx.Category, tx.Description, tx.Date, tx.UserID, tx.AccountID,
+			tx.BaseCurrency, tx.ExchangeRate, tx.BaseAmount).
 		WillReturnRows(rows)
+	mock.ExpectExec(`DELETE FROM transaction_splits WHERE transaction_id = \$1`).
+		WithArgs(tx.ID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`DELETE FROM transaction_tags WHERE transaction_id = \$1`).
+		WithArgs(tx.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
 	err := repo.Update(ctx, tx)
 
@@ -190,6 +417,97 @@ func TestTransactionRepository_Update(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestTransactionRepository_ApplyBulk(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	tx := model.Transaction{ID: uuid.New(), Type: model.TransactionTypeExpense, Category: "Shopping", Date: time.Now()}
+	deleted := []uuid.UUID{uuid.New(), uuid.New()}
+	filters := TransactionFilters{IDs: []uuid.UUID{tx.ID, deleted[0], deleted[1]}, Limit: 1001}
+	errChange := errors.New("no rate")
+
+	tests := []struct {
+		name        string
+		changeErr   error
+		noChange    bool
+		dryRun      bool
+		deletedRows int64
+		wantErr     error
+	}{
+		{name: "success", deletedRows: 2},
+		{name: "dry run reads without locking and saves nothing", dryRun: true},
+		{name: "transaction deleted meanwhile", deletedRows: 1, wantErr: ErrTransactionNotFound},
+		{name: "change fails", changeErr: errChange, wantErr: errChange},
+		{name: "nothing to change", noChange: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock := newMockDB(t)
+			defer func() { _ = db.Close() }()
+			repo := NewTransactionRepository(db)
+
+			lock := `\s+FOR UPDATE`
+			if tt.dryRun {
+				lock = `$`
+			}
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT \* FROM transactions .* LIMIT \$14 OFFSET \$15`+lock).
+				WithArgs(userID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, false,
+					pq.Array([]string{tx.ID.String(), deleted[0].String(), deleted[1].String()}), 1001, 0).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "type"}).
+					AddRow(tx.ID, userID, "expense").
+					AddRow(deleted[0], userID, "expense").
+					AddRow(deleted[1], userID, "expense"))
+			mock.ExpectQuery(`SELECT \* FROM transaction_splits`).
+				WillReturnRows(sqlmock.NewRows(splitColumns))
+			mock.ExpectQuery(`FROM transaction_tags l`).
+				WillReturnRows(sqlmock.NewRows(tagColumns))
+			saved := tt.changeErr == nil && !tt.noChange && !tt.dryRun
+			if saved {
+				mock.ExpectQuery(`UPDATE transactions`).
+					WithArgs(tx.ID, tx.Type, tx.Amount, tx.Currency, tx.Category, tx.Description, tx.Date, userID, tx.AccountID,
+						tx.BaseCurrency, tx.ExchangeRate, tx.BaseAmount).
+					WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(time.Now()))
+				mock.ExpectExec(`DELETE FROM transaction_splits WHERE transaction_id = \$1`).
+					WithArgs(tx.ID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`DELETE FROM transaction_tags WHERE transaction_id = \$1`).
+					WithArgs(tx.ID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`DELETE FROM transactions WHERE user_id = \$1 AND id = ANY\(\$2::uuid\[\]\)`).
+					WithArgs(userID, pq.Array([]string{deleted[0].String(), deleted[1].String()})).
+					WillReturnResult(sqlmock.NewResult(0, tt.deletedRows))
+			}
+			if tt.wantErr == nil && saved {
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			var selected []model.Transaction
+			err := repo.ApplyBulk(context.Background(), userID, filters, tt.dryRun, func(txs []model.Transaction) ([]model.Transaction, []uuid.UUID, error) {
+				selected = txs
+				if tt.changeErr != nil || tt.noChange {
+					return nil, nil, tt.changeErr
+				}
+				return []model.Transaction{tx}, deleted, nil
+			})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Len(t, selected, 3)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestTransactionRepository_Delete(t *testing.T) {
 	t.Parallel()
 
@@ -274,6 +592,33 @@ func TestTransactionRepository_GetMonthlyTotals(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestTransactionRepository_GetTotalsForPeriod(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newMockDB(t)
+	defer func() { _ = db.Close() }()
+	repo := NewTransactionRepository(db)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	startDate := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	endDate := startDate.AddDate(0, 0, 6)
+
+	rows := sqlmock.NewRows([]string{"income", "expenses"}).
+		AddRow(decimal.NewFromFloat(1200), decimal.NewFromFloat(450))
+
+	mock.ExpectQuery(`SELECT`).
+		WithArgs(userID, startDate, endDate).
+		WillReturnRows(rows)
+
+	income, expenses, err := repo.GetTotalsForPeriod(ctx, userID, startDate, endDate)
+
+	assert.NoError(t, err)
+	assert.True(t, income.Equal(decimal.NewFromFloat(1200)))
+	assert.True(t, expenses.Equal(decimal.NewFromFloat(450)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestTransactionRepository_GetExpensesByCategory(t *testing.T) {
 	t.Parallel()
 
@@ -290,7 +635,7 @@ func TestTransactionRepository_GetExpensesByCategory(t *testing.T) {
 		AddRow("Food", decimal.NewFromFloat(500)).
 		AddRow("Transport", decimal.NewFromFloat(200))
 
-	mock.ExpectQuery(`SELECT category, SUM`).
+	mock.ExpectQuery(`SELECT category, SUM\(base_amount\) as total\s+FROM transaction_lines`).
 		WithArgs(userID, startDate, endDate).
 		WillReturnRows(rows)
 
@@ -344,6 +689,10 @@ func TestTransactionRepository_GetRecentTransactions(t *testing.T) {
 	mock.ExpectQuery(`SELECT \* FROM transactions WHERE user_id = \$1 ORDER BY date DESC`).
 		WithArgs(userID, 5).
 		WillReturnRows(rows)
+	mock.ExpectQuery(`SELECT \* FROM transaction_splits`).
+		WillReturnRows(sqlmock.NewRows(splitColumns))
+	mock.ExpectQuery(`FROM transaction_tags l`).
+		WillReturnRows(sqlmock.NewRows(tagColumns))
 
 	txs, err := repo.GetRecentTransactions(ctx, userID, 5)
 
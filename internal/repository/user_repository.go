@@ -25,13 +25,13 @@ func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
 	query := `
 		INSERT INTO users (id, email, password_hash, name, currency, oauth_provider, oauth_id, avatar_url, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
-		RETURNING created_at, updated_at`
+		RETURNING locale, created_at, updated_at`
 
 	user.ID = uuid.New()
 	return r.db.QueryRowxContext(ctx, query,
 		user.ID, user.Email, user.PasswordHash, user.Name, user.Currency,
 		user.OAuthProvider, user.OAuthID, user.AvatarURL,
-	).Scan(&user.CreatedAt, &user.UpdatedAt)
+	).Scan(&user.Locale, &user.CreatedAt, &user.UpdatedAt)
 }
 
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
@@ -57,12 +57,12 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.User
 func (r *UserRepository) Update(ctx context.Context, user *model.User) error {
 	query := `
 		UPDATE users 
-		SET name = $2, currency = $3, oauth_provider = $4, oauth_id = $5, avatar_url = $6, updated_at = NOW()
+		SET name = $2, currency = $3, oauth_provider = $4, oauth_id = $5, avatar_url = $6, locale = $7, updated_at = NOW()
 		WHERE id = $1
 		RETURNING updated_at`
 	return r.db.QueryRowxContext(ctx, query,
 		user.ID, user.Name, user.Currency,
-		user.OAuthProvider, user.OAuthID, user.AvatarURL,
+		user.OAuthProvider, user.OAuthID, user.AvatarURL, user.Locale,
 	).Scan(&user.UpdatedAt)
 }
 
@@ -42,7 +42,7 @@ func TestUserRepository_Create(t *testing.T) {
 	}
 
 	now := time.Now()
-	rows := sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now)
+	rows := sqlmock.NewRows([]string{"locale", "created_at", "updated_at"}).AddRow("vi", now, now)
 
 	mock.ExpectQuery(`INSERT INTO users`).
 		WithArgs(sqlmock.AnyArg(), user.Email, user.PasswordHash, user.Name, user.Currency, nil, nil, nil).
@@ -52,6 +52,7 @@ func TestUserRepository_Create(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.NotEqual(t, uuid.Nil, user.ID)
+	assert.Equal(t, "vi", user.Locale)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -198,13 +199,14 @@ func TestUserRepository_Update(t *testing.T) {
 		ID:       uuid.New(),
 		Name:     "Updated Name",
 		Currency: "EUR",
+		Locale:   "en",
 	}
 
 	now := time.Now()
 	rows := sqlmock.NewRows([]string{"updated_at"}).AddRow(now)
 
 	mock.ExpectQuery(`UPDATE users`).
-		WithArgs(user.ID, user.Name, user.Currency, nil, nil, nil).
+		WithArgs(user.ID, user.Name, user.Currency, nil, nil, nil, user.Locale).
 		WillReturnRows(rows)
 
 	err := repo.Update(ctx, user)
@@ -392,7 +394,7 @@ func TestUserRepository_GetOrCreateByOAuth(t *testing.T) {
 
 				// Then Create is called
 				now := time.Now()
-				rows := sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now)
+				rows := sqlmock.NewRows([]string{"locale", "created_at", "updated_at"}).AddRow("vi", now, now)
 				mock.ExpectQuery(`INSERT INTO users`).
 					WithArgs(sqlmock.AnyArg(), user.Email, nil, user.Name, user.Currency, user.OAuthProvider, user.OAuthID, nil).
 					WillReturnRows(rows)
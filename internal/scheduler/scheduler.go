@@ -1,143 +1,278 @@
-// Package scheduler provides cron-based job scheduling for the interest rate scraper.
+// Package scheduler provides cron-based scheduling for named background jobs
+// (interest rate scraping, recurring transactions, reminders, ...).
 package scheduler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/robfig/cron/v3"
-	"github.com/wealthpath/backend/internal/service"
+	"github.com/wealthpath/backend/internal/model"
 )
 
-// Config holds the scheduler configuration
-type Config struct {
-	// Schedule is a cron expression for when to run the scraper (e.g., "0 * * * *" for hourly)
+// DefaultTimeout is used for jobs registered without an explicit timeout
+const DefaultTimeout = 5 * time.Minute
+
+var (
+	// ErrJobExists is returned when registering a job name twice
+	ErrJobExists = errors.New("job already registered")
+	// ErrJobNotFound is returned when referring to an unknown job
+	ErrJobNotFound = errors.New("job not found")
+)
+
+// JobFunc performs the work of a job and returns the number of items processed
+type JobFunc func(ctx context.Context) (int, error)
+
+// Job describes a named, scheduled unit of work
+type Job struct {
+	// Name uniquely identifies the job (e.g., "interest_rate_scraper")
+	Name string
+	// Schedule is a standard 5-field cron expression (e.g., "0 * * * *" for hourly)
 	Schedule string
-	// Timeout is the maximum duration for a complete scrape cycle
+	// Timeout is the maximum duration of a single run
 	Timeout time.Duration
-	// Enabled determines if the scheduler should run
+	// Enabled determines if the job is scheduled at all
 	Enabled bool
+	// Run is the work to perform
+	Run JobFunc
 }
 
-// DefaultConfig returns the default scheduler configuration
-func DefaultConfig() Config {
-	return Config{
-		Schedule: "0 * * * *", // Every hour at minute 0
-		Timeout:  5 * time.Minute,
-		Enabled:  true,
-	}
+// RunRecorder persists the history of job runs
+type RunRecorder interface {
+	StartRun(ctx context.Context, run *model.JobRun) error
+	FinishRun(ctx context.Context, run *model.JobRun) error
+}
+
+// registeredJob is a job added to the scheduler
+type registeredJob struct {
+	Job
+	entryID cron.EntryID
+	running atomic.Bool
 }
 
-// Scheduler manages scheduled scraping jobs
+// Scheduler manages a registry of scheduled jobs
 type Scheduler struct {
-	cron        *cron.Cron
-	rateService *service.InterestRateService
-	config      Config
-	logger      *slog.Logger
-	entryID     cron.EntryID
+	cron     *cron.Cron
+	recorder RunRecorder
+	logger   *slog.Logger
+
+	mu      sync.RWMutex
+	jobs    map[string]*registeredJob
+	started bool
 }
 
-// New creates a new Scheduler instance
-func New(cfg Config, rateService *service.InterestRateService, logger *slog.Logger) *Scheduler {
+// New creates a new Scheduler instance. recorder may be nil, in which case
+// runs are only logged.
+func New(recorder RunRecorder, logger *slog.Logger) *Scheduler {
 	if logger == nil {
 		logger = slog.Default()
 	}
 
 	return &Scheduler{
-		cron:        cron.New(cron.WithSeconds()),
-		rateService: rateService,
-		config:      cfg,
-		logger:      logger,
+		cron:     cron.New(cron.WithSeconds()),
+		recorder: recorder,
+		logger:   logger,
+		jobs:     make(map[string]*registeredJob),
 	}
 }
 
-// Start begins the scheduler
-func (s *Scheduler) Start() error {
-	if !s.config.Enabled {
-		s.logger.Info("Scheduler is disabled, skipping start")
-		return nil
+// Register adds a job to the scheduler. Disabled jobs are kept in the registry
+// (so they can still be triggered with RunNow) but are not scheduled.
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" {
+		return errors.New("job name is required")
+	}
+	if job.Run == nil {
+		return fmt.Errorf("job %s: run func is required", job.Name)
+	}
+	if job.Timeout <= 0 {
+		job.Timeout = DefaultTimeout
 	}
 
-	// Convert standard cron (5 fields) to cron with seconds (6 fields)
-	// Add "0" at the beginning for seconds
-	schedule := "0 " + s.config.Schedule
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	entryID, err := s.cron.AddFunc(schedule, func() {
-		s.runScrapeJob()
-	})
-	if err != nil {
-		return err
+	if _, ok := s.jobs[job.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrJobExists, job.Name)
 	}
 
-	s.entryID = entryID
-	s.cron.Start()
+	rj := &registeredJob{Job: job}
+	if job.Enabled {
+		// Convert standard cron (5 fields) to cron with seconds (6 fields)
+		// Add "0" at the beginning for seconds
+		entryID, err := s.cron.AddFunc("0 "+job.Schedule, func() {
+			s.run(rj)
+		})
+		if err != nil {
+			return fmt.Errorf("job %s: invalid schedule %q: %w", job.Name, job.Schedule, err)
+		}
+		rj.entryID = entryID
+	}
 
-	s.logger.Info("Scheduler started",
-		slog.String("schedule", s.config.Schedule),
-		slog.Duration("timeout", s.config.Timeout),
+	s.jobs[job.Name] = rj
+
+	s.logger.Info("Job registered",
+		slog.String("job", job.Name),
+		slog.String("schedule", job.Schedule),
+		slog.Duration("timeout", job.Timeout),
+		slog.Bool("enabled", job.Enabled),
 	)
 
 	return nil
 }
 
-// Stop gracefully stops the scheduler
+// Start begins running the scheduled jobs
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return
+	}
+	s.started = true
+	s.cron.Start()
+
+	s.logger.Info("Scheduler started", slog.Int("jobs", len(s.jobs)))
+}
+
+// Stop gracefully stops the scheduler. The returned context is done once all
+// running jobs have completed.
 func (s *Scheduler) Stop() context.Context {
 	s.logger.Info("Stopping scheduler...")
 	return s.cron.Stop()
 }
 
-// RunNow triggers an immediate scrape job (useful for manual triggers)
-func (s *Scheduler) RunNow() {
-	go s.runScrapeJob()
+// RunNow triggers an immediate run of the named job (useful for manual triggers)
+func (s *Scheduler) RunNow(name string) error {
+	rj, err := s.get(name)
+	if err != nil {
+		return err
+	}
+	go s.run(rj)
+	return nil
 }
 
-// runScrapeJob executes the scraping job
-func (s *Scheduler) runScrapeJob() {
-	ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+// run executes a job with its timeout and records the outcome. Overlapping
+// runs of the same job are skipped.
+func (s *Scheduler) run(rj *registeredJob) {
+	logger := s.logger.With(slog.String("job", rj.Name))
+
+	if !rj.running.CompareAndSwap(false, true) {
+		logger.Warn("Job is still running, skipping this run")
+		return
+	}
+	defer rj.running.Store(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), rj.Timeout)
 	defer cancel()
 
-	startTime := time.Now()
-	s.logger.Info("Starting scheduled scrape job",
-		slog.Time("start_time", startTime),
-	)
+	run := &model.JobRun{
+		JobName:   rj.Name,
+		Status:    model.JobRunStatusRunning,
+		StartedAt: time.Now(),
+	}
+	s.recordStart(logger, run)
+
+	logger.Info("Starting job", slog.Time("start_time", run.StartedAt))
+
+	count, err := s.execute(ctx, rj.Run)
 
-	count, err := s.rateService.ScrapeAndUpdateRates(ctx)
-	duration := time.Since(startTime)
+	finishedAt := time.Now()
+	duration := finishedAt.Sub(run.StartedAt)
+	run.FinishedAt = &finishedAt
+	run.ItemsProcessed = count
 
 	if err != nil {
-		s.logger.Error("Scrape job failed",
-			slog.String("error", err.Error()),
+		errMsg := err.Error()
+		run.Status = model.JobRunStatusFailed
+		run.ErrorMessage = &errMsg
+		logger.Error("Job failed",
+			slog.String("error", errMsg),
+			slog.Int("items_processed", count),
 			slog.Duration("duration", duration),
 		)
+	} else {
+		run.Status = model.JobRunStatusSucceeded
+		logger.Info("Job completed successfully",
+			slog.Int("items_processed", count),
+			slog.Duration("duration", duration),
+		)
+	}
+
+	s.recordFinish(logger, run)
+}
+
+// execute calls the job func, turning a panic into an error so one bad job
+// cannot take down the scheduler
+func (s *Scheduler) execute(ctx context.Context, fn JobFunc) (count int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
+
+func (s *Scheduler) recordStart(logger *slog.Logger, run *model.JobRun) {
+	if s.recorder == nil {
 		return
 	}
+	// Recording uses its own context so an expired job context doesn't lose history
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.recorder.StartRun(ctx, run); err != nil {
+		logger.Warn("Failed to record job start", slog.String("error", err.Error()))
+	}
+}
 
-	s.logger.Info("Scrape job completed successfully",
-		slog.Int("rates_scraped", count),
-		slog.Duration("duration", duration),
-	)
+func (s *Scheduler) recordFinish(logger *slog.Logger, run *model.JobRun) {
+	if s.recorder == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.recorder.FinishRun(ctx, run); err != nil {
+		logger.Warn("Failed to record job result", slog.String("error", err.Error()))
+	}
+}
+
+func (s *Scheduler) get(name string) (*registeredJob, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rj, ok := s.jobs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, name)
+	}
+	return rj, nil
 }
 
-// GetNextRunTime returns the next scheduled run time
-func (s *Scheduler) GetNextRunTime() time.Time {
-	if s.entryID == 0 {
+// GetNextRunTime returns the next scheduled run time of the named job
+func (s *Scheduler) GetNextRunTime(name string) time.Time {
+	rj, err := s.get(name)
+	if err != nil || rj.entryID == 0 {
 		return time.Time{}
 	}
-	entry := s.cron.Entry(s.entryID)
-	return entry.Next
+	return s.cron.Entry(rj.entryID).Next
 }
 
-// GetLastRunTime returns the last run time
-func (s *Scheduler) GetLastRunTime() time.Time {
-	if s.entryID == 0 {
+// GetLastRunTime returns the last scheduled run time of the named job
+func (s *Scheduler) GetLastRunTime(name string) time.Time {
+	rj, err := s.get(name)
+	if err != nil || rj.entryID == 0 {
 		return time.Time{}
 	}
-	entry := s.cron.Entry(s.entryID)
-	return entry.Prev
+	return s.cron.Entry(rj.entryID).Prev
 }
 
-// IsRunning returns true if the scheduler is running
+// IsRunning returns true if the scheduler has been started and has scheduled jobs
 func (s *Scheduler) IsRunning() bool {
-	return s.cron != nil && len(s.cron.Entries()) > 0
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.started && len(s.cron.Entries()) > 0
 }
@@ -0,0 +1,205 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+// fakeRecorder records job runs in memory
+type fakeRecorder struct {
+	mu       sync.Mutex
+	started  []model.JobRun
+	finished []model.JobRun
+	done     chan struct{}
+}
+
+func newFakeRecorder() *fakeRecorder {
+	return &fakeRecorder{done: make(chan struct{}, 10)}
+}
+
+func (f *fakeRecorder) StartRun(_ context.Context, run *model.JobRun) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.started = append(f.started, *run)
+	return nil
+}
+
+func (f *fakeRecorder) FinishRun(_ context.Context, run *model.JobRun) error {
+	f.mu.Lock()
+	f.finished = append(f.finished, *run)
+	f.mu.Unlock()
+	f.done <- struct{}{}
+	return nil
+}
+
+func (f *fakeRecorder) wait(t *testing.T) model.JobRun {
+	t.Helper()
+	select {
+	case <-f.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for job run")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.finished[len(f.finished)-1]
+}
+
+func TestScheduler_Register(t *testing.T) {
+	t.Parallel()
+
+	noop := func(ctx context.Context) (int, error) { return 0, nil }
+
+	tests := []struct {
+		name    string
+		jobs    []Job
+		wantErr error
+	}{
+		{
+			name: "valid job",
+			jobs: []Job{{Name: "a", Schedule: "0 * * * *", Enabled: true, Run: noop}},
+		},
+		{
+			name: "disabled job with invalid schedule is not parsed",
+			jobs: []Job{{Name: "a", Schedule: "bogus", Enabled: false, Run: noop}},
+		},
+		{
+			name:    "duplicate name",
+			jobs:    []Job{{Name: "a", Schedule: "0 * * * *", Run: noop}, {Name: "a", Schedule: "0 * * * *", Run: noop}},
+			wantErr: ErrJobExists,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := New(nil, nil)
+			var err error
+			for _, job := range tt.jobs {
+				if err = s.Register(job); err != nil {
+					break
+				}
+			}
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestScheduler_Register_InvalidJobs(t *testing.T) {
+	t.Parallel()
+
+	s := New(nil, nil)
+	assert.Error(t, s.Register(Job{Schedule: "0 * * * *", Run: func(ctx context.Context) (int, error) { return 0, nil }}))
+	assert.Error(t, s.Register(Job{Name: "no-run", Schedule: "0 * * * *"}))
+	assert.Error(t, s.Register(Job{Name: "bad", Schedule: "not a cron", Enabled: true, Run: func(ctx context.Context) (int, error) { return 0, nil }}))
+}
+
+func TestScheduler_RunNow_RecordsSuccess(t *testing.T) {
+	t.Parallel()
+
+	rec := newFakeRecorder()
+	s := New(rec, nil)
+	require.NoError(t, s.Register(Job{
+		Name:     "count",
+		Schedule: "0 * * * *",
+		Enabled:  true,
+		Run:      func(ctx context.Context) (int, error) { return 7, nil },
+	}))
+
+	require.NoError(t, s.RunNow("count"))
+	run := rec.wait(t)
+
+	assert.Equal(t, "count", run.JobName)
+	assert.Equal(t, model.JobRunStatusSucceeded, run.Status)
+	assert.Equal(t, 7, run.ItemsProcessed)
+	assert.Nil(t, run.ErrorMessage)
+	assert.NotNil(t, run.FinishedAt)
+	assert.Len(t, rec.started, 1)
+}
+
+func TestScheduler_RunNow_RecordsFailureAndPanic(t *testing.T) {
+	t.Parallel()
+
+	rec := newFakeRecorder()
+	s := New(rec, nil)
+	require.NoError(t, s.Register(Job{
+		Name: "fails",
+		Run:  func(ctx context.Context) (int, error) { return 2, errors.New("boom") },
+	}))
+	require.NoError(t, s.Register(Job{
+		Name: "panics",
+		Run:  func(ctx context.Context) (int, error) { panic("oops") },
+	}))
+
+	require.NoError(t, s.RunNow("fails"))
+	run := rec.wait(t)
+	assert.Equal(t, model.JobRunStatusFailed, run.Status)
+	assert.Equal(t, 2, run.ItemsProcessed)
+	require.NotNil(t, run.ErrorMessage)
+	assert.Equal(t, "boom", *run.ErrorMessage)
+
+	require.NoError(t, s.RunNow("panics"))
+	run = rec.wait(t)
+	assert.Equal(t, model.JobRunStatusFailed, run.Status)
+	require.NotNil(t, run.ErrorMessage)
+	assert.Contains(t, *run.ErrorMessage, "oops")
+}
+
+func TestScheduler_RunNow_AppliesTimeout(t *testing.T) {
+	t.Parallel()
+
+	rec := newFakeRecorder()
+	s := New(rec, nil)
+	require.NoError(t, s.Register(Job{
+		Name:    "slow",
+		Timeout: 20 * time.Millisecond,
+		Run: func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		},
+	}))
+
+	require.NoError(t, s.RunNow("slow"))
+	run := rec.wait(t)
+	assert.Equal(t, model.JobRunStatusFailed, run.Status)
+	require.NotNil(t, run.ErrorMessage)
+	assert.Contains(t, *run.ErrorMessage, context.DeadlineExceeded.Error())
+}
+
+func TestScheduler_RunNow_UnknownJob(t *testing.T) {
+	t.Parallel()
+
+	s := New(nil, nil)
+	assert.ErrorIs(t, s.RunNow("missing"), ErrJobNotFound)
+	assert.True(t, s.GetNextRunTime("missing").IsZero())
+}
+
+func TestScheduler_StartStop(t *testing.T) {
+	t.Parallel()
+
+	s := New(nil, nil)
+	require.NoError(t, s.Register(Job{
+		Name:     "hourly",
+		Schedule: "0 * * * *",
+		Enabled:  true,
+		Run:      func(ctx context.Context) (int, error) { return 0, nil },
+	}))
+
+	assert.False(t, s.IsRunning())
+	s.Start()
+	assert.True(t, s.IsRunning())
+	assert.False(t, s.GetNextRunTime("hourly").IsZero())
+
+	<-s.Stop().Done()
+}
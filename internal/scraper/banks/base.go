@@ -1,4 +1,4 @@
-// Package banks provides bank-specific interest rate scrapers.
+// Package banks provides bank-specific interest rate and exchange rate scrapers.
 package banks
 
 import (
@@ -123,18 +123,29 @@ func (b *BaseScraper) FetchPage(ctx context.Context, url string) (*goquery.Docum
 
 // FetchJSON fetches JSON from a URL and returns the response body
 func (b *BaseScraper) FetchJSON(ctx context.Context, url string) ([]byte, error) {
+	return b.fetchBody(ctx, url, "application/json")
+}
+
+// FetchXML fetches XML from a URL and returns the response body
+func (b *BaseScraper) FetchXML(ctx context.Context, url string) ([]byte, error) {
+	return b.fetchBody(ctx, url, "application/xml,text/xml")
+}
+
+// fetchBody fetches a URL accepting the given content types and returns the
+// response body
+func (b *BaseScraper) fetchBody(ctx context.Context, url, accept string) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
 
 	req.Header.Set("User-Agent", GetRandomUserAgent())
-	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept", accept)
 	req.Header.Set("Accept-Language", "vi-VN,vi;q=0.9,en-US;q=0.8,en;q=0.7")
 
 	resp, err := b.Client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("fetching JSON: %w", err)
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
@@ -61,13 +61,33 @@ type bankScraperInterface interface {
 	ScrapeRates(ctx context.Context) ([]model.InterestRate, error)
 }
 
+// ExchangeRateResult holds the result of scraping the exchange rates of a
+// single bank
+type ExchangeRateResult struct {
+	BankCode string
+	BankName string
+	Rates    []model.BankExchangeRate
+	Success  bool
+	Error    error
+	Duration time.Duration
+}
+
+// exchangeRateScraperInterface is implemented by exchange rate scrapers in the
+// banks package
+type exchangeRateScraperInterface interface {
+	BankCode() string
+	BankName() string
+	ScrapeExchangeRates(ctx context.Context) ([]model.BankExchangeRate, error)
+}
+
 // Orchestrator coordinates scraping from multiple banks
 type Orchestrator struct {
-	config   OrchestratorConfig
-	scrapers []bankScraperInterface
-	metrics  *MetricsCollector
-	logger   *slog.Logger
-	mu       sync.RWMutex
+	config               OrchestratorConfig
+	scrapers             []bankScraperInterface
+	exchangeRateScrapers []exchangeRateScraperInterface
+	metrics              *MetricsCollector
+	logger               *slog.Logger
+	mu                   sync.RWMutex
 }
 
 // NewOrchestrator creates a new scraper orchestrator
@@ -101,11 +121,16 @@ func NewOrchestrator(cfg OrchestratorConfig, logger *slog.Logger) *Orchestrator
 		banks.NewHDBankScraper(client),
 	}
 
+	exchangeRateScrapers := []exchangeRateScraperInterface{
+		banks.NewVietcombankExchangeRateScraper(client),
+	}
+
 	return &Orchestrator{
-		config:   cfg,
-		scrapers: scrapers,
-		metrics:  NewMetricsCollector(),
-		logger:   logger,
+		config:               cfg,
+		scrapers:             scrapers,
+		exchangeRateScrapers: exchangeRateScrapers,
+		metrics:              NewMetricsCollector(),
+		logger:               logger,
 	}
 }
 
@@ -453,6 +478,67 @@ func (o *Orchestrator) ScrapeAllParallel(ctx context.Context) ([]ScrapeResult, e
 	return results, nil
 }
 
+// ScrapeExchangeRates scrapes foreign exchange rates from all banks that
+// publish them. Failed banks are reported in their result, not as an error.
+func (o *Orchestrator) ScrapeExchangeRates(ctx context.Context) ([]ExchangeRateResult, error) {
+	results := make([]ExchangeRateResult, 0, len(o.exchangeRateScrapers))
+
+	for i, scraper := range o.exchangeRateScrapers {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return results, ctx.Err()
+			case <-time.After(o.randomDelay()):
+			}
+		}
+
+		results = append(results, o.scrapeExchangeRates(ctx, scraper))
+	}
+
+	return results, nil
+}
+
+// scrapeExchangeRates scrapes the exchange rates of a single bank with retry logic
+func (o *Orchestrator) scrapeExchangeRates(ctx context.Context, scraper exchangeRateScraperInterface) ExchangeRateResult {
+	result := ExchangeRateResult{
+		BankCode: scraper.BankCode(),
+		BankName: scraper.BankName(),
+	}
+	startTime := time.Now()
+
+	err := WithRetry(ctx, o.config.RetryConfig, o.logger, func() error {
+		var err error
+		result.Rates, err = scraper.ScrapeExchangeRates(ctx)
+		if err != nil {
+			return err
+		}
+		if len(result.Rates) == 0 {
+			return ErrNoExchangeRates
+		}
+		return nil
+	})
+	result.Duration = time.Since(startTime)
+
+	if err != nil {
+		o.logger.Error("Failed to scrape exchange rates",
+			slog.String("bank_code", result.BankCode),
+			slog.String("error", err.Error()),
+			slog.Duration("duration", result.Duration),
+		)
+		result.Rates = nil
+		result.Error = err
+		return result
+	}
+
+	o.logger.Info("Successfully scraped exchange rates",
+		slog.String("bank_code", result.BankCode),
+		slog.Int("rates_count", len(result.Rates)),
+		slog.Duration("duration", result.Duration),
+	)
+	result.Success = true
+	return result
+}
+
 // ScrapeBank scrapes rates from a specific bank
 func (o *Orchestrator) ScrapeBank(ctx context.Context, bankCode string) (ScrapeResult, error) {
 	for _, scraper := range o.scrapers {
@@ -0,0 +1,146 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// TextCell is a run of text on one line, separated from its neighbours by a
+// gap wider than a space, such as a table cell
+type TextCell struct {
+	X     float64 // Left edge, in points from the left of the page
+	Right float64 // Right edge
+	Text  string
+}
+
+// TextLine is a line of text on a page. Lines are returned top to bottom,
+// page by page.
+type TextLine struct {
+	Page  int
+	Y     float64 // Baseline, in points from the bottom of the page
+	Size  float64 // Largest font size on the line
+	Cells []TextCell
+}
+
+// Text returns the line's cells separated by spaces
+func (l TextLine) Text() string {
+	parts := make([]string, len(l.Cells))
+	for i, c := range l.Cells {
+		parts[i] = c.Text
+	}
+	return strings.Join(parts, " ")
+}
+
+const (
+	// lineTolerance is how far apart, as a fraction of the font size, two
+	// baselines can be and still be on the same line
+	lineTolerance = 0.3
+	// cellGap is the gap, as a fraction of the font size, that starts a new cell
+	cellGap = 0.8
+	// wordGap is the gap that separates two words in the same cell
+	wordGap = 0.15
+)
+
+// ExtractLines extracts the text of a PDF with its layout, for reading tables.
+// Characters are grouped into lines by their baseline and lines are split into
+// cells wherever there is a wide horizontal gap.
+func ExtractLines(data []byte) (lines []TextLine, err error) {
+	// The PDF library panics on some malformed files
+	defer func() {
+		if r := recover(); r != nil {
+			lines, err = nil, fmt.Errorf("reading PDF: %v", r)
+		}
+	}()
+
+	r, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("opening PDF: %w", err)
+	}
+	for i := 1; i <= r.NumPage(); i++ {
+		page := r.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		lines = append(lines, pageLines(i, page.Content().Text)...)
+	}
+	return lines, nil
+}
+
+// pageLines groups the characters of a page into lines of cells
+func pageLines(page int, chars []pdf.Text) []TextLine {
+	sort.SliceStable(chars, func(i, j int) bool { return chars[i].Y > chars[j].Y })
+
+	var lines []TextLine
+	var row []pdf.Text
+	flush := func() {
+		if len(row) > 0 {
+			if line := buildLine(page, row); len(line.Cells) > 0 {
+				lines = append(lines, line)
+			}
+		}
+		row = nil
+	}
+	for _, c := range chars {
+		if len(row) > 0 && row[0].Y-c.Y > max(row[0].FontSize, c.FontSize, 1)*lineTolerance {
+			flush()
+		}
+		row = append(row, c)
+	}
+	flush()
+	return lines
+}
+
+// buildLine splits the characters of one line into cells
+func buildLine(page int, chars []pdf.Text) TextLine {
+	sort.SliceStable(chars, func(i, j int) bool { return chars[i].X < chars[j].X })
+
+	line := TextLine{Page: page, Y: chars[0].Y}
+	var text strings.Builder
+	var cell TextCell
+	lastX := 0.0
+	flush := func() {
+		cell.Text = strings.TrimSpace(normalizeVietnameseText(text.String()))
+		if cell.Text != "" {
+			line.Cells = append(line.Cells, cell)
+		}
+		text.Reset()
+	}
+
+	for i, c := range chars {
+		size := max(c.FontSize, 1)
+		line.Size = max(line.Size, size)
+
+		// Fonts without width tables report no width, and glyphs drawn from
+		// them do not advance, so guess half an em per character
+		width := c.W
+		if width <= 0 {
+			width = size / 2
+		}
+		right := c.X + width
+		if i > 0 && c.X <= lastX {
+			right = cell.Right + width
+		}
+
+		if i == 0 {
+			cell = TextCell{X: c.X}
+		} else {
+			gap := c.X - cell.Right
+			switch {
+			case gap > size*cellGap:
+				flush()
+				cell = TextCell{X: c.X}
+			case gap > size*wordGap:
+				text.WriteByte(' ')
+			}
+		}
+		text.WriteString(c.S)
+		cell.Right = max(cell.Right, right)
+		lastX = c.X
+	}
+	flush()
+	return line
+}
@@ -0,0 +1,122 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// placedText is a string drawn at a position on the page
+type placedText struct {
+	x, y float64
+	s    string
+}
+
+// buildPDF writes a one-page PDF drawing ASCII text in a 10 point font whose
+// characters are all 6 points wide
+func buildPDF(t *testing.T, texts []placedText) []byte {
+	t.Helper()
+
+	var content strings.Builder
+	for _, tx := range texts {
+		fmt.Fprintf(&content, "BT /F1 10 Tf %g %g Td (%s) Tj ET\n", tx.x, tx.y, tx.s)
+	}
+	widths := strings.TrimSpace(strings.Repeat("600 ", 126-32+1))
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding /FirstChar 32 /LastChar 126 /Widths [" + widths + "] >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+func TestExtractLines(t *testing.T) {
+	t.Parallel()
+
+	data := buildPDF(t, []placedText{
+		{50, 800, "Date"},
+		{200, 800, "Description"},
+		{450, 800, "Amount"},
+		{50, 786, "01/03/2024"},
+		{200, 786, "Coffee with"},
+		{272, 786, "friends"},
+		{450, 786, "50,000"},
+		// Slightly lower, as with text in a different font, but the same line
+		{50, 771.5, "02/03/2024"},
+		{450, 772, "75,000"},
+	})
+
+	lines, err := ExtractLines(data)
+	require.NoError(t, err)
+	require.Len(t, lines, 3)
+
+	cellTexts := func(l TextLine) []string {
+		texts := make([]string, len(l.Cells))
+		for i, c := range l.Cells {
+			texts[i] = c.Text
+		}
+		return texts
+	}
+	assert.Equal(t, []string{"Date", "Description", "Amount"}, cellTexts(lines[0]))
+	// Words drawn separately but close together stay in one cell
+	assert.Equal(t, []string{"01/03/2024", "Coffee with friends", "50,000"}, cellTexts(lines[1]))
+	assert.Equal(t, []string{"02/03/2024", "75,000"}, cellTexts(lines[2]))
+
+	assert.Equal(t, 1, lines[0].Page)
+	assert.InDelta(t, 10, lines[0].Size, 0.01)
+	assert.InDelta(t, 50, lines[1].Cells[0].X, 0.01)
+	assert.InDelta(t, 50+10*6, lines[1].Cells[0].Right, 0.01)
+	assert.Equal(t, "01/03/2024 Coffee with friends 50,000", lines[1].Text())
+}
+
+func TestExtractLines_Malformed(t *testing.T) {
+	t.Parallel()
+
+	_, err := ExtractLines([]byte("%PDF-1.4\ngarbage"))
+	assert.Error(t, err)
+}
+
+func TestFoldVietnamese(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"precomposed", "Số dư đầu kỳ", "so du dau ky"},
+		{"upper case", "SỐ DƯ CUỐI KỲ", "so du cuoi ky"},
+		{"combining marks", "So\u0302\u0301 du\u031b", "so du"},
+		{"extra spaces", "  Ngày   giao\tdịch ", "ngay giao dich"},
+		{"plain text", "Balance", "balance"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, FoldVietnamese(tt.in))
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/ledongthuc/pdf"
 )
@@ -243,6 +244,42 @@ func normalizeVietnameseText(text string) string {
 	return text
 }
 
+// vietnameseBase maps each toned or modified Vietnamese letter to its base letter
+var vietnameseBase = func() map[rune]rune {
+	m := map[rune]rune{'đ': 'd', 'Đ': 'd'}
+	for base, letters := range map[rune]string{
+		'a': "àáảãạăằắẳẵặâầấẩẫậÀÁẢÃẠĂẰẮẲẴẶÂẦẤẨẪẬ",
+		'e': "èéẻẽẹêềếểễệÈÉẺẼẸÊỀẾỂỄỆ",
+		'i': "ìíỉĩịÌÍỈĨỊ",
+		'o': "òóỏõọôồốổỗộơờớởỡợÒÓỎÕỌÔỒỐỔỖỘƠỜỚỞỠỢ",
+		'u': "ùúủũụưừứửữựÙÚỦŨỤƯỪỨỬỮỰ",
+		'y': "ỳýỷỹỵỲÝỶỸỴ",
+	} {
+		for _, r := range letters {
+			m[r] = base
+		}
+	}
+	return m
+}()
+
+// FoldVietnamese lower-cases text and strips Vietnamese diacritics, whether
+// they are precomposed or written as combining marks, so that "Số dư",
+// "SỐ DƯ" and "so du" compare equal. Spaces are collapsed.
+func FoldVietnamese(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, r := range normalizeVietnameseText(text) {
+		switch {
+		case r >= 0x0300 && r <= 0x036F: // Combining diacritical marks
+			continue
+		case vietnameseBase[r] != 0:
+			r = vietnameseBase[r]
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return strings.TrimSpace(b.String())
+}
+
 // ParseRateTableAdvanced parses rate tables with more flexibility
 // It can handle tables where term and rate are on separate lines
 func ParseRateTableAdvanced(text string) []RateInfo {
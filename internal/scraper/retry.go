@@ -17,6 +17,7 @@ var (
 	ErrRateLimited     = errors.New("rate limited by bank server")
 	ErrBankUnavailable = errors.New("bank website unavailable")
 	ErrNoDataFound     = errors.New("no interest rate data found")
+	ErrNoExchangeRates = errors.New("no exchange rate data found")
 )
 
 // ScrapeError represents an error that occurred during scraping
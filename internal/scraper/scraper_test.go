@@ -2,10 +2,14 @@ package scraper
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/scraper/banks"
 )
 
 func TestNewScraper(t *testing.T) {
@@ -202,6 +206,42 @@ func TestParseRateFromString(t *testing.T) {
 	}
 }
 
+func TestVietcombankExchangeRateScraper(t *testing.T) {
+	feed := `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<ExrateList>
+<DateTime>3/1/2024 8:05:11 AM</DateTime>
+<Exrate CurrencyCode="USD" CurrencyName="US DOLLAR            " Buy="24,600.00" Transfer="24,630.00" Sell="24,970.00" />
+<Exrate CurrencyCode="KWD" CurrencyName="KUWAITI DINAR" Buy="-" Transfer="80,115.96" Sell="83,320.97" />
+<Exrate CurrencyCode="XAU" CurrencyName="GOLD" Buy="-" Transfer="-" Sell="-" />
+<Source>Joint Stock Commercial Bank for Foreign Trade of Vietnam - Vietcombank</Source>
+</ExrateList>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(feed))
+	}))
+	defer server.Close()
+
+	s := banks.NewVietcombankExchangeRateScraper(server.Client())
+	s.RateURL = server.URL
+
+	rates, err := s.ScrapeExchangeRates(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, rates, 2) // Gold has no rates
+	assert.Equal(t, "vcb", rates[0].BankCode)
+	assert.Equal(t, "USD", rates[0].Currency)
+	assert.Equal(t, "US DOLLAR", rates[0].CurrencyName)
+	assert.Equal(t, "24600", rates[0].BuyCash.String())
+	assert.Equal(t, "24630", rates[0].BuyTransfer.String())
+	assert.Equal(t, "24970", rates[0].Sell.String())
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), rates[0].RateDate)
+	assert.Nil(t, rates[1].BuyCash)
+
+	mid, ok := rates[0].MidRate()
+	assert.True(t, ok)
+	assert.Equal(t, "24800", mid.String())
+}
+
 func TestScraperRatesHaveLoanAndMortgage(t *testing.T) {
 	s := NewScraper()
 	ctx := context.Background()
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/pkg/currency"
+	"github.com/wealthpath/backend/pkg/datetime"
+)
+
+var (
+	ErrAccountNameRequired     = errors.New("account name is required")
+	ErrInvalidAccountType      = errors.New("account type must be cash, bank, credit_card or e_wallet")
+	ErrInvalidAccountCurrency  = errors.New("invalid currency code")
+	ErrAccountArchived         = errors.New("account is archived")
+	ErrAccountCurrencyMismatch = errors.New("currency does not match the account")
+	ErrInvalidTransferAmount   = errors.New("transfer amount must be greater than zero")
+	ErrTransferSameAccount     = errors.New("cannot transfer to the same account")
+)
+
+var accountTypes = map[model.AccountType]bool{
+	model.AccountTypeCash:       true,
+	model.AccountTypeBank:       true,
+	model.AccountTypeCreditCard: true,
+	model.AccountTypeEWallet:    true,
+}
+
+type CreateAccountInput struct {
+	Name           string            `json:"name"`
+	Type           model.AccountType `json:"type"`
+	Institution    string            `json:"institution"`
+	Currency       string            `json:"currency"`
+	OpeningBalance decimal.Decimal   `json:"openingBalance"` // Negative for money owed, e.g. on a credit card
+}
+
+// UpdateAccountInput replaces an account's details. The currency cannot change
+// because the account's transactions are recorded in it.
+type UpdateAccountInput struct {
+	Name           string            `json:"name"`
+	Type           model.AccountType `json:"type"`
+	Institution    string            `json:"institution"`
+	OpeningBalance decimal.Decimal   `json:"openingBalance"`
+	Archived       bool              `json:"archived"`
+}
+
+type CreateTransferInput struct {
+	FromAccountID uuid.UUID       `json:"fromAccountId"`
+	ToAccountID   uuid.UUID       `json:"toAccountId"`
+	Amount        decimal.Decimal `json:"amount"`
+	Description   string          `json:"description"`
+	Date          *datetime.Date  `json:"date,omitempty"` // Defaults to today
+}
+
+// ListTransfersInput holds the transfer filters. Limit defaults to 20 and is capped at 100.
+type ListTransfersInput struct {
+	AccountID *uuid.UUID
+	StartDate *time.Time
+	EndDate   *time.Time
+	Limit     int
+	Offset    int
+}
+
+// AccountService manages a user's accounts (cash, bank accounts, credit cards and
+// e-wallets) and the transfers between them. Transfers only move money between
+// accounts, so they never show up as income or expenses in reports.
+type AccountService struct {
+	repo repository.AccountRepository
+}
+
+// NewAccountService creates a new account service
+func NewAccountService(repo repository.AccountRepository) *AccountService {
+	return &AccountService{repo: repo}
+}
+
+// Create adds an account for the user. Currency defaults to USD.
+func (s *AccountService) Create(ctx context.Context, userID uuid.UUID, input CreateAccountInput) (*model.Account, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, ErrAccountNameRequired
+	}
+	if !accountTypes[input.Type] {
+		return nil, ErrInvalidAccountType
+	}
+	curr := input.Currency
+	if curr == "" {
+		curr = string(currency.DefaultCurrency)
+	}
+	if !currency.IsValid(curr) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAccountCurrency, curr)
+	}
+
+	account := &model.Account{
+		UserID:         userID,
+		Name:           name,
+		Type:           input.Type,
+		Institution:    strings.TrimSpace(input.Institution),
+		Currency:       curr,
+		OpeningBalance: input.OpeningBalance,
+	}
+	if err := s.repo.Create(ctx, account); err != nil {
+		return nil, fmt.Errorf("creating account: %w", err)
+	}
+	return account, nil
+}
+
+// Get returns one of the user's accounts with its current balance
+func (s *AccountService) Get(ctx context.Context, userID, id uuid.UUID) (*model.AccountWithBalance, error) {
+	account, err := s.repo.GetWithBalance(ctx, userID, id, datetime.Today().Time)
+	if err != nil {
+		return nil, fmt.Errorf("getting account %s: %w", id, err)
+	}
+	return account, nil
+}
+
+// List returns the user's accounts with their current balances. Transactions and
+// transfers dated in the future are not included yet.
+func (s *AccountService) List(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]model.AccountWithBalance, error) {
+	accounts, err := s.repo.ListWithBalances(ctx, userID, includeArchived, datetime.Today().Time)
+	if err != nil {
+		return nil, fmt.Errorf("listing accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+// Update replaces the details of one of the user's accounts
+func (s *AccountService) Update(ctx context.Context, userID, id uuid.UUID, input UpdateAccountInput) (*model.Account, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, ErrAccountNameRequired
+	}
+	if !accountTypes[input.Type] {
+		return nil, ErrInvalidAccountType
+	}
+
+	account, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("fetching account %s for update: %w", id, err)
+	}
+
+	account.Name = name
+	account.Type = input.Type
+	account.Institution = strings.TrimSpace(input.Institution)
+	account.OpeningBalance = input.OpeningBalance
+	account.Archived = input.Archived
+
+	if err := s.repo.Update(ctx, account); err != nil {
+		return nil, fmt.Errorf("updating account %s: %w", id, err)
+	}
+	return account, nil
+}
+
+// Delete removes an account. Its transactions are kept without an account.
+// Accounts with transfers cannot be deleted and should be archived instead.
+func (s *AccountService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if err := s.repo.Delete(ctx, userID, id); err != nil {
+		return fmt.Errorf("deleting account %s: %w", id, err)
+	}
+	return nil
+}
+
+// CreateTransfer moves money between two of the user's active accounts.
+// Both accounts must use the same currency.
+func (s *AccountService) CreateTransfer(ctx context.Context, userID uuid.UUID, input CreateTransferInput) (*model.Transfer, error) {
+	if !input.Amount.IsPositive() {
+		return nil, ErrInvalidTransferAmount
+	}
+	if input.FromAccountID == input.ToAccountID {
+		return nil, ErrTransferSameAccount
+	}
+
+	from, err := s.transferAccount(ctx, userID, input.FromAccountID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.transferAccount(ctx, userID, input.ToAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if from.Currency != to.Currency {
+		return nil, ErrAccountCurrencyMismatch
+	}
+
+	date := datetime.Today()
+	if input.Date != nil {
+		date = *input.Date
+	}
+
+	transfer := &model.Transfer{
+		UserID:        userID,
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        input.Amount,
+		Description:   strings.TrimSpace(input.Description),
+		Date:          date.Time,
+	}
+	if err := s.repo.CreateTransfer(ctx, transfer); err != nil {
+		return nil, fmt.Errorf("creating transfer: %w", err)
+	}
+	return transfer, nil
+}
+
+// GetTransfer returns one of the user's transfers
+func (s *AccountService) GetTransfer(ctx context.Context, userID, id uuid.UUID) (*model.Transfer, error) {
+	transfer, err := s.repo.GetTransfer(ctx, userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting transfer %s: %w", id, err)
+	}
+	return transfer, nil
+}
+
+// ListTransfers returns the user's transfers, newest first
+func (s *AccountService) ListTransfers(ctx context.Context, userID uuid.UUID, input ListTransfersInput) ([]model.Transfer, error) {
+	if input.Limit <= 0 {
+		input.Limit = 20
+	}
+	if input.Limit > 100 {
+		input.Limit = 100
+	}
+	if input.Offset < 0 {
+		input.Offset = 0
+	}
+
+	transfers, err := s.repo.ListTransfers(ctx, userID, repository.TransferFilter{
+		AccountID: input.AccountID,
+		StartDate: input.StartDate,
+		EndDate:   input.EndDate,
+		Limit:     input.Limit,
+		Offset:    input.Offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing transfers: %w", err)
+	}
+	return transfers, nil
+}
+
+// DeleteTransfer removes one of the user's transfers
+func (s *AccountService) DeleteTransfer(ctx context.Context, userID, id uuid.UUID) error {
+	if err := s.repo.DeleteTransfer(ctx, userID, id); err != nil {
+		return fmt.Errorf("deleting transfer %s: %w", id, err)
+	}
+	return nil
+}
+
+// transferAccount returns an account that money can be moved into or out of
+func (s *AccountService) transferAccount(ctx context.Context, userID, id uuid.UUID) (*model.Account, error) {
+	account, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting account %s: %w", id, err)
+	}
+	if account.Archived {
+		return nil, ErrAccountArchived
+	}
+	return account, nil
+}
@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/pkg/datetime"
+)
+
+// MockAccountRepository implements repository.AccountRepository for testing
+type MockAccountRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccountRepository) Create(ctx context.Context, account *model.Account) error {
+	args := m.Called(ctx, account)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) GetByID(ctx context.Context, userID, id uuid.UUID) (*model.Account, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) GetWithBalance(ctx context.Context, userID, id uuid.UUID, asOf time.Time) (*model.AccountWithBalance, error) {
+	args := m.Called(ctx, userID, id, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AccountWithBalance), args.Error(1)
+}
+
+func (m *MockAccountRepository) ListWithBalances(ctx context.Context, userID uuid.UUID, includeArchived bool, asOf time.Time) ([]model.AccountWithBalance, error) {
+	args := m.Called(ctx, userID, includeArchived, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AccountWithBalance), args.Error(1)
+}
+
+func (m *MockAccountRepository) Update(ctx context.Context, account *model.Account) error {
+	args := m.Called(ctx, account)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) CreateTransfer(ctx context.Context, transfer *model.Transfer) error {
+	args := m.Called(ctx, transfer)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) GetTransfer(ctx context.Context, userID, id uuid.UUID) (*model.Transfer, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Transfer), args.Error(1)
+}
+
+func (m *MockAccountRepository) ListTransfers(ctx context.Context, userID uuid.UUID, filter repository.TransferFilter) ([]model.Transfer, error) {
+	args := m.Called(ctx, userID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Transfer), args.Error(1)
+}
+
+func (m *MockAccountRepository) DeleteTransfer(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func TestAccountService_Create(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		input        CreateAccountInput
+		wantCurrency string
+		wantErr      error
+	}{
+		{
+			name:         "e-wallet with opening balance",
+			input:        CreateAccountInput{Name: " MoMo ", Type: model.AccountTypeEWallet, Currency: "VND", OpeningBalance: decimal.NewFromInt(500000)},
+			wantCurrency: "VND",
+		},
+		{
+			name:         "currency defaults to USD",
+			input:        CreateAccountInput{Name: "Wallet", Type: model.AccountTypeCash},
+			wantCurrency: "USD",
+		},
+		{
+			name:    "name required",
+			input:   CreateAccountInput{Name: "  ", Type: model.AccountTypeCash},
+			wantErr: ErrAccountNameRequired,
+		},
+		{
+			name:    "unknown type",
+			input:   CreateAccountInput{Name: "Stocks", Type: "brokerage"},
+			wantErr: ErrInvalidAccountType,
+		},
+		{
+			name:    "invalid currency",
+			input:   CreateAccountInput{Name: "Bank", Type: model.AccountTypeBank, Currency: "XXX"},
+			wantErr: ErrInvalidAccountCurrency,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockAccountRepository)
+			svc := NewAccountService(repo)
+			userID := uuid.New()
+
+			if tt.wantErr == nil {
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*model.Account")).Return(nil)
+			}
+
+			account, err := svc.Create(context.Background(), userID, tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, userID, account.UserID)
+			assert.Equal(t, tt.wantCurrency, account.Currency)
+			assert.Equal(t, strings.TrimSpace(tt.input.Name), account.Name)
+			assert.True(t, tt.input.OpeningBalance.Equal(account.OpeningBalance))
+		})
+	}
+}
+
+func TestAccountService_Update(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockAccountRepository)
+	svc := NewAccountService(repo)
+	userID := uuid.New()
+	accountID := uuid.New()
+
+	existing := &model.Account{ID: accountID, UserID: userID, Name: "Cash", Type: model.AccountTypeCash, Currency: "VND"}
+	repo.On("GetByID", mock.Anything, userID, accountID).Return(existing, nil)
+	repo.On("Update", mock.Anything, mock.AnythingOfType("*model.Account")).Return(nil)
+
+	account, err := svc.Update(context.Background(), userID, accountID, UpdateAccountInput{
+		Name: "Old wallet", Type: model.AccountTypeCash, Archived: true,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Old wallet", account.Name)
+	assert.True(t, account.Archived)
+	assert.Equal(t, "VND", account.Currency) // Currency is kept
+
+	repo.On("GetByID", mock.Anything, userID, mock.Anything).Return(nil, repository.ErrAccountNotFound)
+
+	_, err = svc.Update(context.Background(), userID, uuid.New(), UpdateAccountInput{Name: "x", Type: model.AccountTypeBank})
+	assert.ErrorIs(t, err, repository.ErrAccountNotFound)
+}
+
+func TestAccountService_Delete_InUse(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockAccountRepository)
+	svc := NewAccountService(repo)
+	userID := uuid.New()
+	accountID := uuid.New()
+
+	repo.On("Delete", mock.Anything, userID, accountID).Return(repository.ErrAccountInUse)
+
+	err := svc.Delete(context.Background(), userID, accountID)
+
+	assert.ErrorIs(t, err, repository.ErrAccountInUse)
+}
+
+func TestAccountService_CreateTransfer(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	bank := &model.Account{ID: uuid.New(), UserID: userID, Currency: "VND"}
+	momo := &model.Account{ID: uuid.New(), UserID: userID, Currency: "VND"}
+	usd := &model.Account{ID: uuid.New(), UserID: userID, Currency: "USD"}
+	closed := &model.Account{ID: uuid.New(), UserID: userID, Currency: "VND", Archived: true}
+	june := datetime.NewDate(2024, 6, 15)
+
+	tests := []struct {
+		name    string
+		input   CreateTransferInput
+		wantErr error
+	}{
+		{
+			name:  "success",
+			input: CreateTransferInput{FromAccountID: bank.ID, ToAccountID: momo.ID, Amount: decimal.NewFromInt(200000), Description: "Top up", Date: &june},
+		},
+		{
+			name:    "amount must be positive",
+			input:   CreateTransferInput{FromAccountID: bank.ID, ToAccountID: momo.ID, Amount: decimal.Zero},
+			wantErr: ErrInvalidTransferAmount,
+		},
+		{
+			name:    "same account",
+			input:   CreateTransferInput{FromAccountID: bank.ID, ToAccountID: bank.ID, Amount: decimal.NewFromInt(1)},
+			wantErr: ErrTransferSameAccount,
+		},
+		{
+			name:    "different currencies",
+			input:   CreateTransferInput{FromAccountID: bank.ID, ToAccountID: usd.ID, Amount: decimal.NewFromInt(1)},
+			wantErr: ErrAccountCurrencyMismatch,
+		},
+		{
+			name:    "archived account",
+			input:   CreateTransferInput{FromAccountID: closed.ID, ToAccountID: bank.ID, Amount: decimal.NewFromInt(1)},
+			wantErr: ErrAccountArchived,
+		},
+		{
+			name:    "unknown account",
+			input:   CreateTransferInput{FromAccountID: bank.ID, ToAccountID: uuid.New(), Amount: decimal.NewFromInt(1)},
+			wantErr: repository.ErrAccountNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockAccountRepository)
+			svc := NewAccountService(repo)
+
+			for _, a := range []*model.Account{bank, momo, usd, closed} {
+				repo.On("GetByID", mock.Anything, userID, a.ID).Return(a, nil).Maybe()
+			}
+			repo.On("GetByID", mock.Anything, userID, mock.Anything).Return(nil, repository.ErrAccountNotFound).Maybe()
+			repo.On("CreateTransfer", mock.Anything, mock.AnythingOfType("*model.Transfer")).Return(nil).Maybe()
+
+			transfer, err := svc.CreateTransfer(context.Background(), userID, tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "CreateTransfer", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, bank.ID, transfer.FromAccountID)
+			assert.Equal(t, momo.ID, transfer.ToAccountID)
+			assert.Equal(t, june.Time, transfer.Date)
+			assert.Equal(t, "Top up", transfer.Description)
+		})
+	}
+}
+
+func TestAccountService_ListTransfers(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockAccountRepository)
+	svc := NewAccountService(repo)
+	userID := uuid.New()
+	accountID := uuid.New()
+
+	repo.On("ListTransfers", mock.Anything, userID, repository.TransferFilter{AccountID: &accountID, Limit: 100}).
+		Return([]model.Transfer{{ID: uuid.New()}}, nil)
+
+	transfers, err := svc.ListTransfers(context.Background(), userID, ListTransfersInput{AccountID: &accountID, Limit: 500, Offset: -1})
+
+	require.NoError(t, err)
+	assert.Len(t, transfers, 1)
+
+	repo.On("ListTransfers", mock.Anything, userID, repository.TransferFilter{Limit: 20}).Return(nil, errors.New("db error"))
+
+	_, err = svc.ListTransfers(context.Background(), userID, ListTransfersInput{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/storage"
+)
+
+// DefaultMaxAttachmentSize is the largest upload accepted when no limit is configured
+const DefaultMaxAttachmentSize = 10 << 20
+
+// blobDeletionBatchSize is the number of queued blobs removed per cleanup run
+const blobDeletionBatchSize = 200
+
+// allowedAttachmentTypes are the MIME types accepted for receipts and invoices
+var allowedAttachmentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/webp":      true,
+	"image/heic":      true,
+	"application/pdf": true,
+}
+
+var (
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+	ErrAttachmentType     = errors.New("attachments must be JPEG, PNG, WebP or HEIC images or PDF documents")
+	ErrEmptyAttachment    = errors.New("attachment is empty")
+)
+
+// AttachmentService stores receipts and invoices for transactions and debt
+// payments. Metadata is kept in the database and the files in blob storage.
+type AttachmentService struct {
+	repo    repository.AttachmentRepository
+	storage storage.Storage
+	maxSize int64
+}
+
+// NewAttachmentService creates a new attachment service. Uploads larger than
+// maxSize bytes are rejected; zero or less uses DefaultMaxAttachmentSize.
+func NewAttachmentService(repo repository.AttachmentRepository, store storage.Storage, maxSize int64) *AttachmentService {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxAttachmentSize
+	}
+	return &AttachmentService{repo: repo, storage: store, maxSize: maxSize}
+}
+
+// MaxSize returns the largest accepted upload in bytes
+func (s *AttachmentService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// UploadForTransaction attaches a file to one of the user's transactions
+func (s *AttachmentService) UploadForTransaction(ctx context.Context, userID, transactionID uuid.UUID, fileName string, body io.Reader) (*model.Attachment, error) {
+	owns, err := s.repo.OwnsTransaction(ctx, userID, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("checking transaction %s: %w", transactionID, err)
+	}
+	if !owns {
+		return nil, repository.ErrTransactionNotFound
+	}
+	return s.upload(ctx, &model.Attachment{UserID: userID, TransactionID: &transactionID}, fileName, body)
+}
+
+// UploadForDebtPayment attaches a file to a payment on one of the user's debts
+func (s *AttachmentService) UploadForDebtPayment(ctx context.Context, userID, debtID, paymentID uuid.UUID, fileName string, body io.Reader) (*model.Attachment, error) {
+	owns, err := s.repo.OwnsDebtPayment(ctx, userID, debtID, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("checking debt payment %s: %w", paymentID, err)
+	}
+	if !owns {
+		return nil, repository.ErrDebtPaymentNotFound
+	}
+	return s.upload(ctx, &model.Attachment{UserID: userID, DebtPaymentID: &paymentID}, fileName, body)
+}
+
+// upload validates the file, stores the blob and then records the metadata. If
+// the metadata cannot be saved the blob is removed again.
+func (s *AttachmentService) upload(ctx context.Context, attachment *model.Attachment, fileName string, body io.Reader) (*model.Attachment, error) {
+	data, err := io.ReadAll(io.LimitReader(body, s.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading attachment: %w", err)
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, ErrAttachmentTooLarge
+	}
+	if len(data) == 0 {
+		return nil, ErrEmptyAttachment
+	}
+
+	contentType := detectAttachmentType(data)
+	if !allowedAttachmentTypes[contentType] {
+		return nil, ErrAttachmentType
+	}
+
+	attachment.ID = uuid.New()
+	attachment.FileName = sanitizeFileName(fileName)
+	attachment.ContentType = contentType
+	attachment.Size = int64(len(data))
+	attachment.StorageKey = attachment.UserID.String() + "/" + attachment.ID.String()
+
+	if err := s.storage.Put(ctx, attachment.StorageKey, bytes.NewReader(data), attachment.Size, contentType); err != nil {
+		return nil, fmt.Errorf("storing attachment: %w", err)
+	}
+	if err := s.repo.Create(ctx, attachment); err != nil {
+		if delErr := s.storage.Delete(context.WithoutCancel(ctx), attachment.StorageKey); delErr != nil {
+			slog.Error("Failed to remove orphaned attachment blob",
+				slog.String("key", attachment.StorageKey),
+				slog.String("error", delErr.Error()))
+		}
+		return nil, fmt.Errorf("creating attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// ListForTransaction returns the attachments of one of the user's transactions
+func (s *AttachmentService) ListForTransaction(ctx context.Context, userID, transactionID uuid.UUID) ([]model.Attachment, error) {
+	owns, err := s.repo.OwnsTransaction(ctx, userID, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("checking transaction %s: %w", transactionID, err)
+	}
+	if !owns {
+		return nil, repository.ErrTransactionNotFound
+	}
+
+	attachments, err := s.repo.ListByTransaction(ctx, userID, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("listing attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+// ListForDebtPayment returns the attachments of a payment on one of the user's debts
+func (s *AttachmentService) ListForDebtPayment(ctx context.Context, userID, debtID, paymentID uuid.UUID) ([]model.Attachment, error) {
+	owns, err := s.repo.OwnsDebtPayment(ctx, userID, debtID, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("checking debt payment %s: %w", paymentID, err)
+	}
+	if !owns {
+		return nil, repository.ErrDebtPaymentNotFound
+	}
+
+	attachments, err := s.repo.ListByDebtPayment(ctx, userID, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("listing attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+// Open returns an attachment and its contents. The caller must close the reader.
+func (s *AttachmentService) Open(ctx context.Context, userID, id uuid.UUID) (*model.Attachment, io.ReadCloser, error) {
+	attachment, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting attachment %s: %w", id, err)
+	}
+
+	body, err := s.storage.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, fmt.Errorf("attachment %s has no stored file: %w", id, repository.ErrAttachmentNotFound)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading attachment %s: %w", id, err)
+	}
+	return attachment, body, nil
+}
+
+// Delete removes an attachment. The blob is deleted right away when possible;
+// otherwise the cleanup job retries it later.
+func (s *AttachmentService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	attachment, err := s.repo.Delete(ctx, userID, id)
+	if err != nil {
+		return fmt.Errorf("deleting attachment %s: %w", id, err)
+	}
+	s.deleteBlob(ctx, attachment.StorageKey)
+	return nil
+}
+
+// PurgeDeletedBlobs removes the blobs of attachments deleted directly or
+// through a transaction, debt or user being deleted. It returns the number of
+// blobs removed. Blobs that cannot be removed stay queued for the next run.
+func (s *AttachmentService) PurgeDeletedBlobs(ctx context.Context) (int, error) {
+	keys, err := s.repo.PendingBlobDeletions(ctx, blobDeletionBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("listing blobs to delete: %w", err)
+	}
+
+	deleted := 0
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			return deleted, ctx.Err()
+		}
+		if s.deleteBlob(ctx, key) {
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// deleteBlob removes a queued blob and takes it off the queue, or records the
+// failure so the cleanup job can retry
+func (s *AttachmentService) deleteBlob(ctx context.Context, key string) bool {
+	if err := s.storage.Delete(ctx, key); err != nil {
+		slog.Warn("Failed to delete attachment blob", slog.String("key", key), slog.String("error", err.Error()))
+		if err := s.repo.RecordBlobDeletionFailure(ctx, key, err.Error()); err != nil {
+			slog.Error("Failed to record blob deletion failure", slog.String("key", key), slog.String("error", err.Error()))
+		}
+		return false
+	}
+	if err := s.repo.ForgetBlobDeletion(ctx, key); err != nil {
+		slog.Error("Failed to dequeue deleted blob", slog.String("key", key), slog.String("error", err.Error()))
+	}
+	return true
+}
+
+// detectAttachmentType sniffs the MIME type from the file contents. HEIC, the
+// default format of iPhone photos, is not known to http.DetectContentType.
+func detectAttachmentType(data []byte) string {
+	if len(data) >= 12 && string(data[4:8]) == "ftyp" {
+		switch string(data[8:12]) {
+		case "heic", "heix", "heim", "heis", "mif1", "msf1":
+			return "image/heic"
+		}
+	}
+	contentType := http.DetectContentType(data)
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return contentType
+}
+
+// sanitizeFileName keeps only the base name of an uploaded file, without
+// control characters and at most 255 characters long
+func sanitizeFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	if utf8.RuneCountInString(name) > 255 {
+		name = string([]rune(name)[:255])
+	}
+	return name
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/storage"
+)
+
+// MockAttachmentRepository implements repository.AttachmentRepository for testing
+type MockAttachmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentRepository) Create(ctx context.Context, attachment *model.Attachment) error {
+	args := m.Called(ctx, attachment)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) GetByID(ctx context.Context, userID, id uuid.UUID) (*model.Attachment, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) ListByTransaction(ctx context.Context, userID, transactionID uuid.UUID) ([]model.Attachment, error) {
+	args := m.Called(ctx, userID, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) ListByDebtPayment(ctx context.Context, userID, paymentID uuid.UUID) ([]model.Attachment, error) {
+	args := m.Called(ctx, userID, paymentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) Delete(ctx context.Context, userID, id uuid.UUID) (*model.Attachment, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) OwnsTransaction(ctx context.Context, userID, transactionID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID, transactionID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) OwnsDebtPayment(ctx context.Context, userID, debtID, paymentID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID, debtID, paymentID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) PendingBlobDeletions(ctx context.Context, limit int) ([]string, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) ForgetBlobDeletion(ctx context.Context, storageKey string) error {
+	args := m.Called(ctx, storageKey)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) RecordBlobDeletionFailure(ctx context.Context, storageKey, reason string) error {
+	args := m.Called(ctx, storageKey, reason)
+	return args.Error(0)
+}
+
+const testPDF = "%PDF-1.7\n1 0 obj\n<<>>\nendobj\n"
+
+var errDBDown = errors.New("db down")
+
+func newTestAttachmentStorage(t *testing.T) (*storage.LocalStorage, string) {
+	t.Helper()
+	dir := t.TempDir()
+	store, err := storage.NewLocalStorage(dir)
+	require.NoError(t, err)
+	return store, dir
+}
+
+func TestAttachmentService_UploadForTransaction(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		body      string
+		owns      bool
+		createErr error
+		wantErr   error
+		wantType  string
+	}{
+		{name: "pdf", body: testPDF, owns: true, wantType: "application/pdf"},
+		{name: "heic photo", body: "\x00\x00\x00\x18ftypheic\x00\x00\x00\x00", owns: true, wantType: "image/heic"},
+		{name: "not owner", body: testPDF, wantErr: repository.ErrTransactionNotFound},
+		{name: "empty", body: "", owns: true, wantErr: ErrEmptyAttachment},
+		{name: "too large", body: testPDF + strings.Repeat(" ", 100), owns: true, wantErr: ErrAttachmentTooLarge},
+		{name: "unsupported type", body: "<html><script>alert(1)</script></html>", owns: true, wantErr: ErrAttachmentType},
+		{name: "metadata not saved", body: testPDF, owns: true, createErr: errDBDown, wantErr: errDBDown},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockAttachmentRepository)
+			store, dir := newTestAttachmentStorage(t)
+			svc := NewAttachmentService(repo, store, 64)
+			userID, transactionID := uuid.New(), uuid.New()
+
+			repo.On("OwnsTransaction", mock.Anything, userID, transactionID).Return(tt.owns, nil)
+			repo.On("Create", mock.Anything, mock.AnythingOfType("*model.Attachment")).Return(tt.createErr).Maybe()
+
+			attachment, err := svc.UploadForTransaction(context.Background(), userID, transactionID, `C:\Users\an\hoa don.pdf`, strings.NewReader(tt.body))
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				entries, _ := os.ReadDir(filepath.Join(dir, userID.String()))
+				assert.Empty(t, entries, "no blob may be left behind")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "hoa don.pdf", attachment.FileName)
+			assert.Equal(t, tt.wantType, attachment.ContentType)
+			assert.Equal(t, int64(len(tt.body)), attachment.Size)
+			assert.Equal(t, &transactionID, attachment.TransactionID)
+			assert.Equal(t, userID.String()+"/"+attachment.ID.String(), attachment.StorageKey)
+
+			rc, err := store.Get(context.Background(), attachment.StorageKey)
+			require.NoError(t, err)
+			data, _ := io.ReadAll(rc)
+			_ = rc.Close()
+			assert.Equal(t, tt.body, string(data))
+		})
+	}
+}
+
+func TestAttachmentService_Delete(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockAttachmentRepository)
+	store, _ := newTestAttachmentStorage(t)
+	svc := NewAttachmentService(repo, store, 0)
+	ctx := context.Background()
+	userID, id := uuid.New(), uuid.New()
+	key := userID.String() + "/" + id.String()
+	require.NoError(t, store.Put(ctx, key, strings.NewReader(testPDF), int64(len(testPDF)), "application/pdf"))
+
+	repo.On("Delete", mock.Anything, userID, id).Return(&model.Attachment{ID: id, StorageKey: key}, nil)
+	repo.On("ForgetBlobDeletion", mock.Anything, key).Return(nil)
+
+	require.NoError(t, svc.Delete(ctx, userID, id))
+
+	_, err := store.Get(ctx, key)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	repo.AssertExpectations(t)
+}
+
+// failingStorage fails to delete one key
+type failingStorage struct {
+	storage.Storage
+	failKey string
+}
+
+func (s *failingStorage) Delete(ctx context.Context, key string) error {
+	if key == s.failKey {
+		return errors.New("bucket unavailable")
+	}
+	return s.Storage.Delete(ctx, key)
+}
+
+func TestAttachmentService_PurgeDeletedBlobs(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockAttachmentRepository)
+	local, _ := newTestAttachmentStorage(t)
+	svc := NewAttachmentService(repo, &failingStorage{Storage: local, failKey: "user/b"}, 0)
+
+	repo.On("PendingBlobDeletions", mock.Anything, blobDeletionBatchSize).Return([]string{"user/a", "user/b", "user/c"}, nil)
+	repo.On("ForgetBlobDeletion", mock.Anything, "user/a").Return(nil)
+	repo.On("ForgetBlobDeletion", mock.Anything, "user/c").Return(nil)
+	repo.On("RecordBlobDeletionFailure", mock.Anything, "user/b", "bucket unavailable").Return(nil)
+
+	deleted, err := svc.PurgeDeletedBlobs(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "ForgetBlobDeletion", mock.Anything, "user/b")
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "receipt.jpg", want: "receipt.jpg"},
+		{in: "../../etc/passwd", want: "passwd"},
+		{in: `C:\Users\an\hóa đơn.pdf`, want: "hóa đơn.pdf"},
+		{in: "bad\r\nname.png", want: "badname.png"},
+		{in: "", want: "attachment"},
+		{in: "/", want: "attachment"},
+		{in: strings.Repeat("a", 300), want: strings.Repeat("a", 255)},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, sanitizeFileName(tt.in))
+		})
+	}
+}
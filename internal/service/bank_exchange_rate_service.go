@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/scraper"
+	"github.com/wealthpath/backend/pkg/currency"
+)
+
+// ExchangeRateScraper scrapes the foreign exchange rates banks publish
+type ExchangeRateScraper interface {
+	ScrapeExchangeRates(ctx context.Context) ([]scraper.ExchangeRateResult, error)
+}
+
+// ExchangeRateStore stores the rates transactions are converted with and
+// converts the ones waiting for a rate
+type ExchangeRateStore interface {
+	SaveRates(ctx context.Context, rates []model.ExchangeRate) error
+	ConvertPending(ctx context.Context) (int, error)
+}
+
+// BankExchangeRateService collects the exchange rates banks publish and makes
+// them the rates transactions are converted to their owner's currency with
+type BankExchangeRateService struct {
+	repo    repository.BankExchangeRateRepository
+	scraper ExchangeRateScraper
+	rates   ExchangeRateStore
+}
+
+// NewBankExchangeRateService creates a new bank exchange rate service
+func NewBankExchangeRateService(repo repository.BankExchangeRateRepository, rates ExchangeRateStore) *BankExchangeRateService {
+	return &BankExchangeRateService{
+		repo:    repo,
+		scraper: scraper.NewOrchestrator(scraper.DefaultOrchestratorConfig(), slog.Default()),
+		rates:   rates,
+	}
+}
+
+// ScrapeAndUpdateRates scrapes exchange rates from all banks, stores them and
+// saves their mid rates against VND as conversion rates, sourced by bank code.
+// Transactions waiting for one of these rates are converted straight away.
+func (s *BankExchangeRateService) ScrapeAndUpdateRates(ctx context.Context) (int, error) {
+	results, err := s.scraper.ScrapeExchangeRates(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("scrape exchange rates: %w", err)
+	}
+
+	var bankRates []model.BankExchangeRate
+	for _, result := range results {
+		if result.Success {
+			bankRates = append(bankRates, result.Rates...)
+		}
+	}
+
+	if len(bankRates) == 0 {
+		return 0, fmt.Errorf("no exchange rates scraped successfully")
+	}
+
+	if err := s.repo.Upsert(ctx, bankRates); err != nil {
+		return 0, fmt.Errorf("upsert exchange rates: %w", err)
+	}
+
+	if err := s.rates.SaveRates(ctx, conversionRates(bankRates)); err != nil {
+		return 0, fmt.Errorf("save conversion rates: %w", err)
+	}
+
+	// A failed conversion is retried by the conversion job and must not fail the scrape
+	converted, err := s.rates.ConvertPending(ctx)
+	if err != nil {
+		slog.Error("Converting pending transactions failed", slog.String("error", err.Error()))
+	} else if converted > 0 {
+		slog.Info("Pending transactions converted", slog.Int("transactions", converted))
+	}
+
+	return len(bankRates), nil
+}
+
+// ListRates returns the latest exchange rates, optionally of one bank or currency
+func (s *BankExchangeRateService) ListRates(ctx context.Context, bankCode, curr string) ([]model.BankExchangeRate, error) {
+	return s.repo.List(ctx, bankCode, curr)
+}
+
+// GetRateHistory returns a bank's daily rates for a currency over the last days
+func (s *BankExchangeRateService) GetRateHistory(ctx context.Context, bankCode, curr string, days int) ([]repository.ExchangeRateHistoryEntry, error) {
+	return s.repo.GetHistory(ctx, bankCode, curr, days)
+}
+
+// conversionRates returns the mid rates against VND of the supported currencies
+func conversionRates(bankRates []model.BankExchangeRate) []model.ExchangeRate {
+	var rates []model.ExchangeRate
+	for _, bankRate := range bankRates {
+		if bankRate.Currency == string(currency.VND) || !currency.IsValid(bankRate.Currency) {
+			continue
+		}
+		mid, ok := bankRate.MidRate()
+		if !ok {
+			continue
+		}
+		rates = append(rates, model.ExchangeRate{
+			FromCurrency: bankRate.Currency,
+			ToCurrency:   string(currency.VND),
+			Rate:         mid.Round(exchangeRatePlaces),
+			RateDate:     bankRate.RateDate,
+			Source:       bankRate.BankCode,
+		})
+	}
+	return rates
+}
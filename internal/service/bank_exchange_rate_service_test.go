@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/scraper"
+)
+
+// MockBankExchangeRateRepository implements repository.BankExchangeRateRepository for testing
+type MockBankExchangeRateRepository struct {
+	mock.Mock
+}
+
+func (m *MockBankExchangeRateRepository) Upsert(ctx context.Context, rates []model.BankExchangeRate) error {
+	args := m.Called(ctx, rates)
+	return args.Error(0)
+}
+
+func (m *MockBankExchangeRateRepository) List(ctx context.Context, bankCode, currency string) ([]model.BankExchangeRate, error) {
+	args := m.Called(ctx, bankCode, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.BankExchangeRate), args.Error(1)
+}
+
+func (m *MockBankExchangeRateRepository) GetHistory(ctx context.Context, bankCode, currency string, days int) ([]repository.ExchangeRateHistoryEntry, error) {
+	args := m.Called(ctx, bankCode, currency, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.ExchangeRateHistoryEntry), args.Error(1)
+}
+
+// MockExchangeRateScraper implements ExchangeRateScraper for testing
+type MockExchangeRateScraper struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateScraper) ScrapeExchangeRates(ctx context.Context) ([]scraper.ExchangeRateResult, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]scraper.ExchangeRateResult), args.Error(1)
+}
+
+// MockExchangeRateStore implements ExchangeRateStore for testing
+type MockExchangeRateStore struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateStore) SaveRates(ctx context.Context, rates []model.ExchangeRate) error {
+	args := m.Called(ctx, rates)
+	return args.Error(0)
+}
+
+func (m *MockExchangeRateStore) ConvertPending(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func TestBankExchangeRateService_ScrapeAndUpdateRates(t *testing.T) {
+	t.Parallel()
+
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	dec := func(s string) *decimal.Decimal {
+		d := decimal.RequireFromString(s)
+		return &d
+	}
+	usd := model.BankExchangeRate{BankCode: "vcb", Currency: "USD", BuyCash: dec("24980"), BuyTransfer: dec("25010"), Sell: dec("25390"), RateDate: date}
+	eur := model.BankExchangeRate{BankCode: "vcb", Currency: "EUR", BuyTransfer: dec("26500.5"), RateDate: date}
+	kwd := model.BankExchangeRate{BankCode: "vcb", Currency: "KWD", BuyTransfer: dec("82797.35"), Sell: dec("86110.06"), RateDate: date}
+
+	tests := []struct {
+		name      string
+		results   []scraper.ExchangeRateResult
+		scrapeErr error
+		convert   error
+		wantRates []model.ExchangeRate
+		wantCount int
+		wantErr   bool
+	}{
+		{
+			name: "saves mid rates of supported currencies",
+			results: []scraper.ExchangeRateResult{
+				{BankCode: "vcb", Success: true, Rates: []model.BankExchangeRate{usd, eur, kwd}},
+			},
+			wantRates: []model.ExchangeRate{
+				{FromCurrency: "USD", ToCurrency: "VND", Rate: decimal.RequireFromString("25200"), RateDate: date, Source: "vcb"},
+				{FromCurrency: "EUR", ToCurrency: "VND", Rate: decimal.RequireFromString("26500.5"), RateDate: date, Source: "vcb"},
+			},
+			wantCount: 3,
+		},
+		{
+			name: "conversion failure doesn't fail the scrape",
+			results: []scraper.ExchangeRateResult{
+				{BankCode: "vcb", Success: true, Rates: []model.BankExchangeRate{usd}},
+			},
+			convert: errors.New("db down"),
+			wantRates: []model.ExchangeRate{
+				{FromCurrency: "USD", ToCurrency: "VND", Rate: decimal.RequireFromString("25200"), RateDate: date, Source: "vcb"},
+			},
+			wantCount: 1,
+		},
+		{
+			name:    "no bank scraped",
+			results: []scraper.ExchangeRateResult{{BankCode: "vcb", Error: scraper.ErrNoExchangeRates}},
+			wantErr: true,
+		},
+		{
+			name:      "scrape cancelled",
+			scrapeErr: context.Canceled,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockBankExchangeRateRepository)
+			scr := new(MockExchangeRateScraper)
+			store := new(MockExchangeRateStore)
+			svc := &BankExchangeRateService{repo: repo, scraper: scr, rates: store}
+
+			scr.On("ScrapeExchangeRates", mock.Anything).Return(tt.results, tt.scrapeErr)
+			if !tt.wantErr {
+				repo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+				store.On("SaveRates", mock.Anything, mock.Anything).Return(nil)
+				store.On("ConvertPending", mock.Anything).Return(2, tt.convert)
+			}
+
+			count, err := svc.ScrapeAndUpdateRates(context.Background())
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				repo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCount, count)
+
+			saved := store.Calls[0].Arguments.Get(1).([]model.ExchangeRate)
+			require.Len(t, saved, len(tt.wantRates))
+			for i, want := range tt.wantRates {
+				assert.Equal(t, want.FromCurrency, saved[i].FromCurrency)
+				assert.Equal(t, want.ToCurrency, saved[i].ToCurrency)
+				assert.True(t, want.Rate.Equal(saved[i].Rate), "rate %s, want %s", saved[i].Rate, want.Rate)
+				assert.Equal(t, want.RateDate, saved[i].RateDate)
+				assert.Equal(t, want.Source, saved[i].Source)
+			}
+			store.AssertExpectations(t)
+		})
+	}
+}
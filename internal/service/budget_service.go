@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 
 	"github.com/wealthpath/backend/internal/model"
 	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/pkg/currency"
 )
 
 // BudgetRepositoryInterface defines the contract for budget data access.
@@ -21,6 +23,9 @@ type BudgetRepositoryInterface interface {
 	GetActiveForUser(ctx context.Context, userID uuid.UUID) ([]model.Budget, error)
 	Update(ctx context.Context, budget *model.Budget) error
 	Delete(ctx context.Context, id, userID uuid.UUID) error
+	ListRolloverEnabled(ctx context.Context, since time.Time) ([]model.Budget, error)
+	ListRollovers(ctx context.Context, budgetID uuid.UUID) ([]model.BudgetRollover, error)
+	ApplyRollover(ctx context.Context, rollover *model.BudgetRollover) (bool, error)
 }
 
 // TransactionRepoForBudget provides transaction data needed for budget calculations.
@@ -28,11 +33,25 @@ type TransactionRepoForBudget interface {
 	GetSpentByCategory(ctx context.Context, userID uuid.UUID, category string, startDate, endDate time.Time) (decimal.Decimal, error)
 }
 
+// BudgetCurrencyRepo provides the user's base currency, which spending is summed in.
+type BudgetCurrencyRepo interface {
+	GetUserCurrency(ctx context.Context, userID uuid.UUID) (string, error)
+}
+
+// BudgetAlertNotifier delivers budget alerts to a user (e.g. PushNotificationService).
+type BudgetAlertNotifier interface {
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*model.NotificationPreferences, error)
+	SendBudgetAlert(ctx context.Context, userID uuid.UUID, category string, percentage int, budgetID uuid.UUID) error
+}
+
 // BudgetService handles business logic for budget management.
 // It tracks spending against budget limits and calculates remaining amounts.
 type BudgetService struct {
 	repo            BudgetRepositoryInterface
 	transactionRepo TransactionRepoForBudget
+	alertNotifier   BudgetAlertNotifier
+	currencyRepo    BudgetCurrencyRepo
+	rates           currency.RateSource
 }
 
 // NewBudgetService creates a new BudgetService with the given repository.
@@ -45,6 +64,19 @@ func (s *BudgetService) SetTransactionRepo(repo TransactionRepoForBudget) {
 	s.transactionRepo = repo
 }
 
+// SetAlertNotifier sets the notifier used by CheckAlerts.
+func (s *BudgetService) SetAlertNotifier(notifier BudgetAlertNotifier) {
+	s.alertNotifier = notifier
+}
+
+// SetCurrencyConverter sets the user's base currency, which spending is in, and
+// the rates used to compare it with budgets in another currency. Without it,
+// spending is taken to be in USD and only USD budgets are compared.
+func (s *BudgetService) SetCurrencyConverter(currencyRepo BudgetCurrencyRepo, rates currency.RateSource) {
+	s.currencyRepo = currencyRepo
+	s.rates = rates
+}
+
 type CreateBudgetInput struct {
 	Category          string           `json:"category"`
 	Amount            decimal.Decimal  `json:"amount"`
@@ -54,6 +86,7 @@ type CreateBudgetInput struct {
 	EndDate           *time.Time       `json:"endDate"`
 	EnableRollover    bool             `json:"enableRollover"`
 	MaxRolloverAmount *decimal.Decimal `json:"maxRolloverAmount,omitempty"`
+	RolloverOverspend bool             `json:"rolloverOverspend"`
 }
 
 type UpdateBudgetInput struct {
@@ -65,6 +98,7 @@ type UpdateBudgetInput struct {
 	EndDate           *time.Time       `json:"endDate"`
 	EnableRollover    bool             `json:"enableRollover"`
 	MaxRolloverAmount *decimal.Decimal `json:"maxRolloverAmount,omitempty"`
+	RolloverOverspend bool             `json:"rolloverOverspend"`
 }
 
 // Create creates a new budget for the given user.
@@ -80,6 +114,7 @@ func (s *BudgetService) Create(ctx context.Context, userID uuid.UUID, input Crea
 		EndDate:           input.EndDate,
 		EnableRollover:    input.EnableRollover,
 		MaxRolloverAmount: input.MaxRolloverAmount,
+		RolloverOverspend: input.RolloverOverspend,
 		RolloverAmount:    decimal.Zero,
 	}
 
@@ -116,8 +151,15 @@ func (s *BudgetService) List(ctx context.Context, userID uuid.UUID) ([]model.Bud
 }
 
 // ListWithSpent retrieves active budgets with calculated spending data.
-// It calculates spent amount, remaining amount, and percentage used for each budget.
+// It calculates spent amount, remaining amount, and percentage used for each budget,
+// in the budget's currency. Budgets without a rate to it yet are flagged unconverted.
 func (s *BudgetService) ListWithSpent(ctx context.Context, userID uuid.UUID) ([]model.BudgetWithSpent, error) {
+	return s.ListWithSpentAt(ctx, userID, time.Now())
+}
+
+// ListWithSpentAt is ListWithSpent for the budget periods containing date
+// rather than the current ones.
+func (s *BudgetService) ListWithSpentAt(ctx context.Context, userID uuid.UUID, date time.Time) ([]model.BudgetWithSpent, error) {
 	budgets, err := s.repo.GetActiveForUser(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("getting active budgets for user %s: %w", userID, err)
@@ -131,24 +173,35 @@ func (s *BudgetService) ListWithSpent(ctx context.Context, userID uuid.UUID) ([]
 		return result, nil
 	}
 
+	baseCurrency, err := s.baseCurrency(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	result := make([]model.BudgetWithSpent, len(budgets))
-	now := time.Now()
 
 	for i, budget := range budgets {
-		startDate, endDate := getPeriodDates(budget.Period, now)
+		startDate, endDate := getPeriodDates(budget.Period, date)
 
 		spent, err := s.transactionRepo.GetSpentByCategory(ctx, userID, budget.Category, startDate, endDate)
 		if err != nil {
 			return nil, fmt.Errorf("calculating spent for budget %s: %w", budget.ID, err)
 		}
 
+		// Spending is in the base currency, the budget in its own
+		spent, ok, err := convertBetween(ctx, s.rates, spent, baseCurrency, budget.Currency, date)
+		if err != nil {
+			return nil, fmt.Errorf("converting spent for budget %s: %w", budget.ID, err)
+		}
+		if !ok {
+			result[i] = model.BudgetWithSpent{Budget: budget, Unconverted: true}
+			continue
+		}
+
 		// Effective budget includes rollover amount
 		effectiveBudget := budget.Amount.Add(budget.RolloverAmount)
 		remaining := effectiveBudget.Sub(spent)
-		percentage := float64(0)
-		if !effectiveBudget.IsZero() {
-			percentage = spent.Div(effectiveBudget).Mul(decimal.NewFromInt(100)).InexactFloat64()
-		}
+		percentage := spentPercentage(spent, effectiveBudget)
 
 		result[i] = model.BudgetWithSpent{
 			Budget:     budget,
@@ -161,6 +214,120 @@ func (s *BudgetService) ListWithSpent(ctx context.Context, userID uuid.UUID) ([]
 	return result, nil
 }
 
+// CheckAlerts re-evaluates the budgets affected by a transaction write and sends
+// an alert when spending for the current period crosses the user's alert
+// threshold or 100%. before is the transaction as it was before the write (nil
+// on create) and after is the transaction as written (nil on delete).
+func (s *BudgetService) CheckAlerts(ctx context.Context, userID uuid.UUID, before, after *model.Transaction) error {
+	if after != nil && after.BaseAmount == nil {
+		// Spending is summed in the base currency, which the transaction has no
+		// amount in until a rate is known
+		return nil
+	}
+
+	var befores, afters []model.Transaction
+	if before != nil {
+		befores = append(befores, *before)
+	}
+	if after != nil {
+		afters = append(afters, *after)
+	}
+	return s.CheckBulkAlerts(ctx, userID, befores, afters)
+}
+
+// CheckBulkAlerts is CheckAlerts for many transaction writes at once, such as
+// a bulk action. before holds the changed and deleted transactions as they
+// were and after the changed ones as written. Each budget is checked once,
+// for the change in spending of all the writes together.
+func (s *BudgetService) CheckBulkAlerts(ctx context.Context, userID uuid.UUID, before, after []model.Transaction) error {
+	if s.alertNotifier == nil || s.transactionRepo == nil {
+		return nil
+	}
+
+	categories := make(map[string]bool, 2)
+	for _, txs := range [][]model.Transaction{before, after} {
+		for i := range txs {
+			if txs[i].Type == model.TransactionTypeExpense {
+				for category := range categoryAmounts(&txs[i]) {
+					categories[category] = true
+				}
+			}
+		}
+	}
+	if len(categories) == 0 {
+		return nil
+	}
+
+	prefs, err := s.alertNotifier.GetPreferences(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("getting notification preferences for user %s: %w", userID, err)
+	}
+	if !prefs.BudgetAlertsEnabled {
+		return nil
+	}
+
+	budgets, err := s.repo.GetActiveForUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("getting active budgets for user %s: %w", userID, err)
+	}
+
+	baseCurrency, err := s.baseCurrency(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	now := time.Now()
+	for _, budget := range budgets {
+		if !categories[budget.Category] {
+			continue
+		}
+
+		startDate, endDate := getPeriodDates(budget.Period, now)
+		delta := decimal.Zero
+		for i := range after {
+			delta = delta.Add(budgetContribution(&after[i], budget.Category, startDate, endDate))
+		}
+		for i := range before {
+			delta = delta.Sub(budgetContribution(&before[i], budget.Category, startDate, endDate))
+		}
+		if !delta.IsPositive() {
+			// Spending for this period did not go up, so no level can have been crossed
+			continue
+		}
+
+		spent, err := s.transactionRepo.GetSpentByCategory(ctx, userID, budget.Category, startDate, endDate)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("calculating spent for budget %s: %w", budget.ID, err))
+			continue
+		}
+
+		// Compared in the base currency, which spending is in. A budget without
+		// a rate to it yet cannot be checked.
+		effectiveBudget, ok, err := convertBetween(ctx, s.rates, budget.Amount.Add(budget.RolloverAmount), budget.Currency, baseCurrency, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("converting budget %s: %w", budget.ID, err))
+			continue
+		}
+		if !ok || !effectiveBudget.IsPositive() {
+			continue
+		}
+
+		previous := spentPercentage(spent.Sub(delta), effectiveBudget)
+		current := spentPercentage(spent, effectiveBudget)
+		if !crossedBudgetAlertLevel(previous, current, prefs.BudgetAlertThreshold) {
+			continue
+		}
+
+		err = s.alertNotifier.SendBudgetAlert(ctx, userID, budget.Category, int(current), budget.ID)
+		if err != nil && !errors.Is(err, ErrNoSubscriptions) {
+			errs = append(errs, fmt.Errorf("sending alert for budget %s: %w", budget.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // Update modifies an existing budget.
 // Returns ErrBudgetNotFound if the budget does not exist or belongs to another user.
 func (s *BudgetService) Update(ctx context.Context, id uuid.UUID, userID uuid.UUID, input UpdateBudgetInput) (*model.Budget, error) {
@@ -181,6 +348,7 @@ func (s *BudgetService) Update(ctx context.Context, id uuid.UUID, userID uuid.UU
 	budget.EndDate = input.EndDate
 	budget.EnableRollover = input.EnableRollover
 	budget.MaxRolloverAmount = input.MaxRolloverAmount
+	budget.RolloverOverspend = input.RolloverOverspend
 
 	if err := s.repo.Update(ctx, budget); err != nil {
 		return nil, fmt.Errorf("updating budget %s: %w", id, err)
@@ -197,12 +365,235 @@ func (s *BudgetService) Delete(ctx context.Context, id uuid.UUID, userID uuid.UU
 	return nil
 }
 
+// spentPercentage returns spent as a percentage of the effective budget amount.
+func spentPercentage(spent, effectiveBudget decimal.Decimal) float64 {
+	if effectiveBudget.IsZero() {
+		return 0
+	}
+	return spent.Div(effectiveBudget).Mul(decimal.NewFromInt(100)).InexactFloat64()
+}
+
+// budgetContribution returns the amount a transaction adds to a budget's spending
+// for the period, or zero if it does not count towards the budget.
+func budgetContribution(tx *model.Transaction, category string, startDate, endDate time.Time) decimal.Decimal {
+	if tx == nil || tx.Type != model.TransactionTypeExpense {
+		return decimal.Zero
+	}
+	if tx.Date.Before(startDate) || tx.Date.After(endDate) {
+		return decimal.Zero
+	}
+	return categoryAmounts(tx)[category]
+}
+
+// categoryAmounts returns how much of a transaction falls in each category in
+// the base currency: the split lines at the transaction's rate if it has any,
+// otherwise its base amount in its own category. A transaction not converted
+// yet counts nowhere, as in the base currency totals.
+func categoryAmounts(tx *model.Transaction) map[string]decimal.Decimal {
+	if tx.BaseAmount == nil {
+		return nil
+	}
+	if len(tx.Splits) == 0 {
+		return map[string]decimal.Decimal{tx.Category: *tx.BaseAmount}
+	}
+	if tx.ExchangeRate == nil {
+		return nil
+	}
+	amounts := make(map[string]decimal.Decimal, len(tx.Splits))
+	for _, split := range tx.Splits {
+		amounts[split.Category] = amounts[split.Category].Add(split.Amount.Mul(*tx.ExchangeRate))
+	}
+	return amounts
+}
+
+// crossedBudgetAlertLevel reports whether spending moved from below to at or above
+// the alert threshold or 100%. Thresholds outside 1-99 only alert at 100%.
+func crossedBudgetAlertLevel(previous, current float64, threshold int) bool {
+	levels := []float64{100}
+	if threshold > 0 && threshold < 100 {
+		levels = append(levels, float64(threshold))
+	}
+	for _, level := range levels {
+		if previous < level && current >= level {
+			return true
+		}
+	}
+	return false
+}
+
+// ListRollovers returns the rollover history of a budget, newest period first.
+// Returns ErrBudgetNotFound if the budget does not exist or belongs to another user.
+func (s *BudgetService) ListRollovers(ctx context.Context, id uuid.UUID, userID uuid.UUID) ([]model.BudgetRollover, error) {
+	budget, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting budget %s: %w", id, err)
+	}
+	if budget.UserID != userID {
+		return nil, repository.ErrBudgetNotFound
+	}
+
+	rollovers, err := s.repo.ListRollovers(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("listing rollovers for budget %s: %w", id, err)
+	}
+	return rollovers, nil
+}
+
+// ProcessRollovers closes finished periods of budgets with rollover enabled. The
+// remainder of each closed period is carried into the next one and recorded in
+// the rollover history. Periods that were already closed are skipped, so running
+// it again is safe. It returns the number of periods closed.
+func (s *BudgetService) ProcessRollovers(ctx context.Context) (int, error) {
+	if s.transactionRepo == nil {
+		return 0, errors.New("budget rollovers need a transaction repository")
+	}
+
+	now := time.Now()
+	budgets, err := s.repo.ListRolloverEnabled(ctx, now.AddDate(-1, 0, 0))
+	if err != nil {
+		return 0, fmt.Errorf("listing rollover budgets: %w", err)
+	}
+
+	closed := 0
+	var errs []error
+	for i := range budgets {
+		n, err := s.rollOverBudget(ctx, &budgets[i], now)
+		closed += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rolling over budget %s: %w", budgets[i].ID, err))
+		}
+	}
+
+	return closed, errors.Join(errs...)
+}
+
+// rollOverBudget closes the budget's finished periods, oldest first. It resumes
+// after the last closed period, or closes only the previous period the first
+// time a budget is processed.
+func (s *BudgetService) rollOverBudget(ctx context.Context, budget *model.Budget, now time.Time) (int, error) {
+	currentStart, _ := getPeriodDates(budget.Period, now)
+
+	history, err := s.repo.ListRollovers(ctx, budget.ID)
+	if err != nil {
+		return 0, fmt.Errorf("listing rollovers: %w", err)
+	}
+
+	var periodStart time.Time
+	carryIn := decimal.Zero
+	if len(history) > 0 {
+		periodStart, _ = getPeriodDates(budget.Period, dateIn(history[0].ToPeriodStart, now.Location()))
+		carryIn = history[0].Amount
+	} else {
+		periodStart, _ = getPeriodDates(budget.Period, currentStart.Add(-time.Second))
+	}
+
+	closed := 0
+	for periodStart.Before(currentStart) {
+		var periodEnd time.Time
+		periodStart, periodEnd = getPeriodDates(budget.Period, periodStart)
+		nextStart := periodEnd.Add(time.Second)
+
+		if budget.EndDate != nil && periodStart.After(dateIn(*budget.EndDate, now.Location())) {
+			break
+		}
+
+		amount := decimal.Zero
+		if !periodEnd.Before(dateIn(budget.StartDate, now.Location())) {
+			spent, err := s.transactionRepo.GetSpentByCategory(ctx, budget.UserID, budget.Category, periodStart, periodEnd)
+			if err != nil {
+				return closed, fmt.Errorf("calculating spent for %s: %w", periodStart.Format("2006-01-02"), err)
+			}
+			spent, err = s.spentInBudgetCurrency(ctx, budget, spent, periodEnd)
+			if err != nil {
+				return closed, fmt.Errorf("converting spent for %s: %w", periodStart.Format("2006-01-02"), err)
+			}
+			amount = rolloverAmount(budget, carryIn, spent)
+
+			applied, err := s.repo.ApplyRollover(ctx, &model.BudgetRollover{
+				BudgetID:        budget.ID,
+				FromPeriodStart: periodStart,
+				FromPeriodEnd:   periodEnd,
+				ToPeriodStart:   nextStart,
+				Amount:          amount,
+			})
+			if err != nil {
+				return closed, fmt.Errorf("saving rollover for %s: %w", periodStart.Format("2006-01-02"), err)
+			}
+			if applied {
+				closed++
+			}
+		}
+
+		carryIn = amount
+		periodStart = nextStart
+	}
+
+	return closed, nil
+}
+
+// spentInBudgetCurrency converts spending, summed in the user's base currency,
+// to the budget's currency at the rate on the date. Returns ErrNoExchangeRate if
+// there is none yet, so the period is closed once there is.
+func (s *BudgetService) spentInBudgetCurrency(ctx context.Context, budget *model.Budget, spent decimal.Decimal, date time.Time) (decimal.Decimal, error) {
+	baseCurrency, err := s.baseCurrency(ctx, budget.UserID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	converted, ok, err := convertBetween(ctx, s.rates, spent, baseCurrency, budget.Currency, date)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if !ok {
+		return decimal.Zero, ErrNoExchangeRate
+	}
+	return converted, nil
+}
+
+// baseCurrency returns the user's base currency, or USD without a currency repository.
+func (s *BudgetService) baseCurrency(ctx context.Context, userID uuid.UUID) (string, error) {
+	if s.currencyRepo == nil {
+		return string(currency.DefaultCurrency), nil
+	}
+	curr, err := s.currencyRepo.GetUserCurrency(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("getting currency for user %s: %w", userID, err)
+	}
+	return curr, nil
+}
+
+// rolloverAmount returns what a closed period carries into the next one: the
+// unspent remainder, or the overspend as a negative amount when RolloverOverspend
+// is set. Either way it is capped at MaxRolloverAmount.
+func rolloverAmount(budget *model.Budget, carryIn, spent decimal.Decimal) decimal.Decimal {
+	remainder := budget.Amount.Add(carryIn).Sub(spent)
+	if remainder.IsNegative() && !budget.RolloverOverspend {
+		return decimal.Zero
+	}
+
+	if budget.MaxRolloverAmount != nil {
+		limit := budget.MaxRolloverAmount.Abs()
+		if remainder.GreaterThan(limit) {
+			return limit
+		}
+		if remainder.LessThan(limit.Neg()) {
+			return limit.Neg()
+		}
+	}
+	return remainder
+}
+
+// dateIn returns midnight of t's calendar date in loc. DATE columns are read back
+// as UTC midnight, which would otherwise shift across a day boundary.
+func dateIn(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
 // getPeriodDates calculates the start and end dates for a budget period.
 func getPeriodDates(period string, now time.Time) (start, end time.Time) {
 	switch period {
 	case "weekly":
 		weekday := int(now.Weekday())
-		start = now.AddDate(0, 0, -weekday)
+		start = time.Date(now.Year(), now.Month(), now.Day()-weekday, 0, 0, 0, 0, now.Location())
 		end = start.AddDate(0, 0, 7).Add(-time.Second)
 	case "yearly":
 		start = time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
@@ -61,6 +61,27 @@ func (m *MockBudgetRepo) Delete(ctx context.Context, id, userID uuid.UUID) error
 	return args.Error(0)
 }
 
+func (m *MockBudgetRepo) ListRolloverEnabled(ctx context.Context, since time.Time) ([]model.Budget, error) {
+	args := m.Called(ctx, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Budget), args.Error(1)
+}
+
+func (m *MockBudgetRepo) ListRollovers(ctx context.Context, budgetID uuid.UUID) ([]model.BudgetRollover, error) {
+	args := m.Called(ctx, budgetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.BudgetRollover), args.Error(1)
+}
+
+func (m *MockBudgetRepo) ApplyRollover(ctx context.Context, rollover *model.BudgetRollover) (bool, error) {
+	args := m.Called(ctx, rollover)
+	return args.Bool(0), args.Error(1)
+}
+
 // Tests - Following Go rules: table-driven tests with parallel execution
 func TestBudgetService_Create(t *testing.T) {
 	t.Parallel()
@@ -417,3 +438,592 @@ func TestBudgetService_ListWithSpent(t *testing.T) {
 		})
 	}
 }
+
+func TestBudgetService_ListWithSpentAt(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	date := time.Date(2024, 5, 18, 0, 0, 0, 0, time.UTC)
+	mockBudgetRepo := new(MockBudgetRepo)
+	mockBudgetRepo.On("GetActiveForUser", mock.Anything, userID).Return([]model.Budget{
+		{ID: uuid.New(), UserID: userID, Category: "Food", Period: "monthly", Amount: decimal.NewFromInt(500)},
+	}, nil)
+	mockTxRepo := new(MockTransactionRepo)
+	mockTxRepo.On("GetSpentByCategory", mock.Anything, userID, "Food",
+		time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC).Add(-time.Second)).
+		Return(decimal.NewFromInt(450), nil)
+	service := NewBudgetService(mockBudgetRepo)
+	service.SetTransactionRepo(mockTxRepo)
+
+	budgets, err := service.ListWithSpentAt(context.Background(), userID, date)
+
+	assert.NoError(t, err)
+	if assert.Len(t, budgets, 1) {
+		assert.True(t, decimal.NewFromInt(450).Equal(budgets[0].Spent))
+		assert.Equal(t, 90.0, budgets[0].Percentage)
+	}
+	mockTxRepo.AssertExpectations(t)
+}
+
+// MockBudgetAlertNotifier for testing
+type MockBudgetAlertNotifier struct {
+	mock.Mock
+}
+
+func (m *MockBudgetAlertNotifier) GetPreferences(ctx context.Context, userID uuid.UUID) (*model.NotificationPreferences, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockBudgetAlertNotifier) SendBudgetAlert(ctx context.Context, userID uuid.UUID, category string, percentage int, budgetID uuid.UUID) error {
+	args := m.Called(ctx, userID, category, percentage, budgetID)
+	return args.Error(0)
+}
+
+func TestBudgetService_CheckAlerts(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	one := decimal.NewFromInt(1)
+	expense := func(category string, amount float64, date time.Time) *model.Transaction {
+		baseAmount := decimal.NewFromFloat(amount)
+		return &model.Transaction{
+			Type:         model.TransactionTypeExpense,
+			Category:     category,
+			Amount:       decimal.NewFromFloat(amount),
+			Date:         date,
+			ExchangeRate: &one,
+			BaseAmount:   &baseAmount,
+		}
+	}
+	splitBase := decimal.NewFromInt(400)
+	foreignRate, foreignBase := decimal.RequireFromString("0.00004"), decimal.NewFromInt(80)
+	prefs := &model.NotificationPreferences{BudgetAlertsEnabled: true, BudgetAlertThreshold: 90}
+
+	tests := []struct {
+		name          string
+		before        *model.Transaction
+		after         *model.Transaction
+		prefs         *model.NotificationPreferences
+		spent         map[string]float64 // spent after the write, by category
+		wantAlerts    map[string]int     // expected alert percentage, by category
+		wantNoBudgets bool               // budgets should not be loaded at all
+		sendErr       error
+		wantErr       bool
+	}{
+		{
+			name:       "crossing threshold sends alert",
+			after:      expense("Food", 200, now),
+			prefs:      prefs,
+			spent:      map[string]float64{"Food": 950},
+			wantAlerts: map[string]int{"Food": 95},
+		},
+		{
+			name:       "crossing 100% sends alert",
+			after:      expense("Food", 100, now),
+			prefs:      prefs,
+			spent:      map[string]float64{"Food": 1050},
+			wantAlerts: map[string]int{"Food": 105},
+		},
+		{
+			name:  "already above threshold does not alert again",
+			after: expense("Food", 10, now),
+			prefs: prefs,
+			spent: map[string]float64{"Food": 960},
+		},
+		{
+			name:  "below threshold does not alert",
+			after: expense("Food", 100, now),
+			prefs: prefs,
+			spent: map[string]float64{"Food": 500},
+		},
+		{
+			name:   "update moving spend to another category alerts that budget",
+			before: expense("Food", 300, now),
+			after:  expense("Transport", 300, now),
+			prefs:  prefs,
+			spent:  map[string]float64{"Transport": 400},
+			// Transport budget is 400: 25% -> 100%
+			wantAlerts: map[string]int{"Transport": 100},
+		},
+		{
+			name: "split lines count towards each category's budget",
+			after: &model.Transaction{
+				Type: model.TransactionTypeExpense, Category: "Food", Amount: decimal.NewFromInt(400), Date: now,
+				ExchangeRate: &one, BaseAmount: &splitBase,
+				Splits: []model.TransactionSplit{
+					{Category: "Food", Amount: decimal.NewFromInt(100)},
+					{Category: "Transport", Amount: decimal.NewFromInt(300)},
+				},
+			},
+			prefs: prefs,
+			spent: map[string]float64{"Food": 950, "Transport": 400},
+			// Food 85% -> 95%, Transport 25% -> 100%
+			wantAlerts: map[string]int{"Food": 95, "Transport": 100},
+		},
+		{
+			name: "foreign currency expense counts its base amount",
+			after: &model.Transaction{
+				Type: model.TransactionTypeExpense, Category: "Food", Amount: decimal.NewFromInt(2000000), Currency: "VND", Date: now,
+				ExchangeRate: &foreignRate, BaseAmount: &foreignBase,
+			},
+			prefs: prefs,
+			spent: map[string]float64{"Food": 950},
+			// 870 -> 950 in the base currency
+			wantAlerts: map[string]int{"Food": 95},
+		},
+		{
+			name: "foreign currency split lines count at the transaction's rate",
+			after: &model.Transaction{
+				Type: model.TransactionTypeExpense, Category: "Food", Amount: decimal.NewFromInt(10000000), Currency: "VND", Date: now,
+				ExchangeRate: &foreignRate, BaseAmount: &splitBase,
+				Splits: []model.TransactionSplit{
+					{Category: "Food", Amount: decimal.NewFromInt(2500000)},
+					{Category: "Transport", Amount: decimal.NewFromInt(7500000)},
+				},
+			},
+			prefs: prefs,
+			spent: map[string]float64{"Food": 950, "Transport": 400},
+			// Food 850 -> 950, Transport 100 -> 400
+			wantAlerts: map[string]int{"Food": 95, "Transport": 100},
+		},
+		{
+			name:          "unconverted transaction does not alert",
+			after:         &model.Transaction{Type: model.TransactionTypeExpense, Category: "Food", Amount: decimal.NewFromInt(2000000), Currency: "VND", Date: now},
+			wantNoBudgets: true,
+		},
+		{
+			name:   "delete lowers spending and never alerts",
+			before: expense("Food", 300, now),
+			prefs:  prefs,
+		},
+		{
+			name:  "transaction outside current period is ignored",
+			after: expense("Food", 900, now.AddDate(-2, 0, 0)),
+			prefs: prefs,
+		},
+		{
+			name:          "income does not touch budgets",
+			after:         &model.Transaction{Type: model.TransactionTypeIncome, Category: "Salary", Amount: decimal.NewFromInt(5000), Date: now},
+			wantNoBudgets: true,
+		},
+		{
+			name:          "alerts disabled",
+			after:         expense("Food", 200, now),
+			prefs:         &model.NotificationPreferences{BudgetAlertsEnabled: false, BudgetAlertThreshold: 90},
+			wantNoBudgets: true,
+		},
+		{
+			name:       "no push subscriptions is not an error",
+			after:      expense("Food", 200, now),
+			prefs:      prefs,
+			spent:      map[string]float64{"Food": 950},
+			wantAlerts: map[string]int{"Food": 95},
+			sendErr:    ErrNoSubscriptions,
+		},
+		{
+			name:       "send failure is returned",
+			after:      expense("Food", 200, now),
+			prefs:      prefs,
+			spent:      map[string]float64{"Food": 950},
+			wantAlerts: map[string]int{"Food": 95},
+			sendErr:    errors.New("push failed"),
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockBudgetRepo := new(MockBudgetRepo)
+			mockTxRepo := new(MockTransactionRepo)
+			notifier := new(MockBudgetAlertNotifier)
+			service := NewBudgetService(mockBudgetRepo)
+			service.SetTransactionRepo(mockTxRepo)
+			service.SetAlertNotifier(notifier)
+
+			userID := uuid.New()
+			budgets := []model.Budget{
+				{ID: uuid.New(), UserID: userID, Category: "Food", Period: "monthly", Amount: decimal.NewFromInt(1000), RolloverAmount: decimal.Zero},
+				{ID: uuid.New(), UserID: userID, Category: "Transport", Period: "monthly", Amount: decimal.NewFromInt(400), RolloverAmount: decimal.Zero},
+			}
+
+			if tt.prefs != nil {
+				notifier.On("GetPreferences", mock.Anything, userID).Return(tt.prefs, nil)
+			}
+			if !tt.wantNoBudgets && tt.prefs != nil && tt.prefs.BudgetAlertsEnabled {
+				mockBudgetRepo.On("GetActiveForUser", mock.Anything, userID).Return(budgets, nil)
+			}
+			for category, spent := range tt.spent {
+				mockTxRepo.On("GetSpentByCategory", mock.Anything, userID, category, mock.Anything, mock.Anything).
+					Return(decimal.NewFromFloat(spent), nil)
+			}
+			for _, b := range budgets {
+				if pct, ok := tt.wantAlerts[b.Category]; ok {
+					notifier.On("SendBudgetAlert", mock.Anything, userID, b.Category, pct, b.ID).Return(tt.sendErr)
+				}
+			}
+
+			err := service.CheckAlerts(context.Background(), userID, tt.before, tt.after)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			if len(tt.wantAlerts) == 0 {
+				notifier.AssertNotCalled(t, "SendBudgetAlert", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			if tt.wantNoBudgets {
+				mockBudgetRepo.AssertNotCalled(t, "GetActiveForUser", mock.Anything, mock.Anything)
+			}
+			mockBudgetRepo.AssertExpectations(t)
+			mockTxRepo.AssertExpectations(t)
+			notifier.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBudgetService_CheckBulkAlerts(t *testing.T) {
+	t.Parallel()
+
+	mockBudgetRepo := new(MockBudgetRepo)
+	mockTxRepo := new(MockTransactionRepo)
+	notifier := new(MockBudgetAlertNotifier)
+	service := NewBudgetService(mockBudgetRepo)
+	service.SetTransactionRepo(mockTxRepo)
+	service.SetAlertNotifier(notifier)
+
+	userID := uuid.New()
+	food := model.Budget{ID: uuid.New(), UserID: userID, Category: "Food", Period: "monthly", Amount: decimal.NewFromInt(1000)}
+	expense := func(category string, amount int64) model.Transaction {
+		one, baseAmount := decimal.NewFromInt(1), decimal.NewFromInt(amount)
+		return model.Transaction{
+			Type: model.TransactionTypeExpense, Category: category, Amount: baseAmount, Date: time.Now(),
+			ExchangeRate: &one, BaseAmount: &baseAmount,
+		}
+	}
+
+	notifier.On("GetPreferences", mock.Anything, userID).Return(&model.NotificationPreferences{BudgetAlertsEnabled: true, BudgetAlertThreshold: 90}, nil)
+	mockBudgetRepo.On("GetActiveForUser", mock.Anything, userID).Return([]model.Budget{food}, nil)
+	mockTxRepo.On("GetSpentByCategory", mock.Anything, userID, "Food", mock.Anything, mock.Anything).Return(decimal.NewFromInt(950), nil).Once()
+	// Three expenses recategorized to Food take it from 65% to 95%, which is one alert
+	notifier.On("SendBudgetAlert", mock.Anything, userID, "Food", 95, food.ID).Return(nil).Once()
+
+	err := service.CheckBulkAlerts(context.Background(), userID,
+		[]model.Transaction{expense("Coffee", 50), expense("Coffee", 100), expense("Shopping", 150)},
+		[]model.Transaction{expense("Food", 50), expense("Food", 100), expense("Food", 150)},
+	)
+
+	assert.NoError(t, err)
+	mockTxRepo.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
+
+func TestBudgetService_ForeignCurrencyBudget(t *testing.T) {
+	t.Parallel()
+
+	mockBudgetRepo := new(MockBudgetRepo)
+	mockTxRepo := new(MockTransactionRepo)
+	notifier := new(MockBudgetAlertNotifier)
+	currencyRepo := new(MockReportRepository)
+	rates := new(MockRateSource)
+	service := NewBudgetService(mockBudgetRepo)
+	service.SetTransactionRepo(mockTxRepo)
+	service.SetAlertNotifier(notifier)
+	service.SetCurrencyConverter(currencyRepo, rates)
+
+	userID := uuid.New()
+	travel := model.Budget{ID: uuid.New(), UserID: userID, Category: "Travel", Period: "monthly", Amount: decimal.NewFromInt(200), Currency: "USD"}
+	books := model.Budget{ID: uuid.New(), UserID: userID, Category: "Books", Period: "monthly", Amount: decimal.NewFromInt(50), Currency: "GBP"}
+
+	currencyRepo.On("GetUserCurrency", mock.Anything, userID).Return("VND", nil)
+	rates.On("Rate", mock.Anything, "USD", "VND", mock.Anything).Return(decimal.NewFromInt(25000), nil)
+	rates.On("Rate", mock.Anything, "VND", "USD", mock.Anything).Return(decimal.RequireFromString("0.00004"), nil)
+	rates.On("Rate", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(decimal.Zero, ErrNoExchangeRate)
+	mockBudgetRepo.On("GetActiveForUser", mock.Anything, userID).Return([]model.Budget{travel, books}, nil)
+	mockTxRepo.On("GetSpentByCategory", mock.Anything, userID, "Travel", mock.Anything, mock.Anything).Return(decimal.NewFromInt(4750000), nil)
+	mockTxRepo.On("GetSpentByCategory", mock.Anything, userID, "Books", mock.Anything, mock.Anything).Return(decimal.NewFromInt(1000000), nil)
+
+	// 4,750,000 VND spent is 190 of the 200 USD budget
+	result, err := service.ListWithSpent(context.Background(), userID)
+	assert.NoError(t, err)
+	if !assert.Len(t, result, 2) {
+		return
+	}
+	assert.Equal(t, "190", result[0].Spent.String())
+	assert.Equal(t, "10", result[0].Remaining.String())
+	assert.Equal(t, float64(95), result[0].Percentage)
+	assert.False(t, result[0].Unconverted)
+	assert.True(t, result[1].Unconverted)
+
+	// A 1,000,000 VND expense takes Travel from 75% to 95%. The GBP budget has
+	// no rate to compare with, so is not alerted on.
+	baseAmount, rate := decimal.NewFromInt(1000000), decimal.NewFromInt(1)
+	notifier.On("GetPreferences", mock.Anything, userID).Return(&model.NotificationPreferences{BudgetAlertsEnabled: true, BudgetAlertThreshold: 90}, nil)
+	notifier.On("SendBudgetAlert", mock.Anything, userID, "Travel", 95, travel.ID).Return(nil)
+	for _, category := range []string{"Travel", "Books"} {
+		err = service.CheckAlerts(context.Background(), userID, nil, &model.Transaction{
+			Type: model.TransactionTypeExpense, Category: category, Amount: baseAmount, Currency: "VND", Date: time.Now(),
+			ExchangeRate: &rate, BaseAmount: &baseAmount,
+		})
+		assert.NoError(t, err)
+	}
+	notifier.AssertNumberOfCalls(t, "SendBudgetAlert", 1)
+}
+
+func TestCrossedBudgetAlertLevel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		previous  float64
+		current   float64
+		threshold int
+		want      bool
+	}{
+		{"crosses threshold", 80, 92, 90, true},
+		{"reaches threshold exactly", 80, 90, 90, true},
+		{"crosses 100", 95, 101, 90, true},
+		{"jumps over both", 10, 150, 90, true},
+		{"stays below", 10, 50, 90, false},
+		{"stays above", 91, 99, 90, false},
+		{"already exceeded", 101, 120, 90, false},
+		{"threshold of 100 only alerts at 100", 80, 95, 100, false},
+		{"zero threshold only alerts at 100", 0, 100, 0, true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, crossedBudgetAlertLevel(tt.previous, tt.current, tt.threshold))
+		})
+	}
+}
+
+func TestGetPeriodDates_WeeklyStartsAtMidnight(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 6, 12, 15, 30, 0, 0, time.UTC) // Wednesday
+	start, end := getPeriodDates("weekly", now)
+
+	assert.Equal(t, time.Date(2024, 6, 9, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2024, 6, 15, 23, 59, 59, 0, time.UTC), end)
+}
+
+func TestRolloverAmount(t *testing.T) {
+	t.Parallel()
+
+	limit := decimal.NewFromInt(150)
+
+	tests := []struct {
+		name      string
+		overspend bool
+		max       *decimal.Decimal
+		carryIn   int64
+		spent     int64
+		want      int64
+	}{
+		{name: "unspent remainder", spent: 600, want: 400},
+		{name: "carry in is included", carryIn: 100, spent: 600, want: 500},
+		{name: "remainder capped", max: &limit, spent: 600, want: 150},
+		{name: "overspend dropped by default", spent: 1200, want: 0},
+		{name: "overspend carried when enabled", overspend: true, spent: 1200, want: -200},
+		{name: "overspend capped", overspend: true, max: &limit, spent: 1500, want: -150},
+		{name: "negative carry in reduces remainder", overspend: true, carryIn: -200, spent: 700, want: 100},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			budget := &model.Budget{
+				Amount:            decimal.NewFromInt(1000),
+				RolloverOverspend: tt.overspend,
+				MaxRolloverAmount: tt.max,
+			}
+			got := rolloverAmount(budget, decimal.NewFromInt(tt.carryIn), decimal.NewFromInt(tt.spent))
+			assert.True(t, got.Equal(decimal.NewFromInt(tt.want)), "got %s, want %d", got, tt.want)
+		})
+	}
+}
+
+func TestBudgetService_ProcessRollovers(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	currentStart, _ := getPeriodDates("monthly", now)
+	monthsAgo := func(n int) time.Time { return currentStart.AddDate(0, -n, 0) }
+
+	tests := []struct {
+		name        string
+		startDate   time.Time
+		history     []model.BudgetRollover
+		spent       int64
+		applied     bool
+		wantPeriods []time.Time // expected from_period_start of each rollover, in order
+		wantAmounts []int64
+		wantClosed  int
+	}{
+		{
+			name:        "first run closes the previous period",
+			startDate:   monthsAgo(6),
+			spent:       600,
+			applied:     true,
+			wantPeriods: []time.Time{monthsAgo(1)},
+			wantAmounts: []int64{400},
+			wantClosed:  1,
+		},
+		{
+			name:      "already closed period is not processed again",
+			startDate: monthsAgo(6),
+			history: []model.BudgetRollover{
+				{FromPeriodStart: monthsAgo(1), ToPeriodStart: currentStart, Amount: decimal.NewFromInt(400)},
+			},
+		},
+		{
+			name:      "missed periods are caught up with the carry chained",
+			startDate: monthsAgo(6),
+			history: []model.BudgetRollover{
+				{FromPeriodStart: monthsAgo(4), ToPeriodStart: monthsAgo(3), Amount: decimal.NewFromInt(100)},
+			},
+			spent:       900,
+			applied:     true,
+			wantPeriods: []time.Time{monthsAgo(3), monthsAgo(2), monthsAgo(1)},
+			wantAmounts: []int64{200, 300, 400},
+			wantClosed:  3,
+		},
+		{
+			name:        "period closed concurrently is not counted",
+			startDate:   monthsAgo(6),
+			spent:       600,
+			applied:     false,
+			wantPeriods: []time.Time{monthsAgo(1)},
+			wantAmounts: []int64{400},
+			wantClosed:  0,
+		},
+		{
+			name:      "budget created this period has nothing to close",
+			startDate: currentStart,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockBudgetRepo := new(MockBudgetRepo)
+			mockTxRepo := new(MockTransactionRepo)
+			service := NewBudgetService(mockBudgetRepo)
+			service.SetTransactionRepo(mockTxRepo)
+
+			budget := model.Budget{
+				ID:             uuid.New(),
+				UserID:         uuid.New(),
+				Category:       "Food",
+				Period:         "monthly",
+				Amount:         decimal.NewFromInt(1000),
+				StartDate:      tt.startDate,
+				EnableRollover: true,
+			}
+
+			history := tt.history
+			if history == nil {
+				history = []model.BudgetRollover{}
+			}
+			mockBudgetRepo.On("ListRolloverEnabled", mock.Anything, mock.Anything).Return([]model.Budget{budget}, nil)
+			mockBudgetRepo.On("ListRollovers", mock.Anything, budget.ID).Return(history, nil)
+
+			var saved []model.BudgetRollover
+			if len(tt.wantPeriods) > 0 {
+				mockTxRepo.On("GetSpentByCategory", mock.Anything, budget.UserID, "Food", mock.Anything, mock.Anything).
+					Return(decimal.NewFromInt(tt.spent), nil)
+				mockBudgetRepo.On("ApplyRollover", mock.Anything, mock.AnythingOfType("*model.BudgetRollover")).
+					Run(func(args mock.Arguments) {
+						saved = append(saved, *args.Get(1).(*model.BudgetRollover))
+					}).
+					Return(tt.applied, nil)
+			}
+
+			closed, err := service.ProcessRollovers(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantClosed, closed)
+			if assert.Len(t, saved, len(tt.wantPeriods)) {
+				for i, r := range saved {
+					assert.Equal(t, tt.wantPeriods[i], r.FromPeriodStart)
+					assert.Equal(t, tt.wantPeriods[i].AddDate(0, 1, 0), r.ToPeriodStart)
+					assert.True(t, r.Amount.Equal(decimal.NewFromInt(tt.wantAmounts[i])), "rollover %d: got %s", i, r.Amount)
+				}
+			}
+			mockBudgetRepo.AssertExpectations(t)
+			mockTxRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBudgetService_ListRollovers(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	budgetID := uuid.New()
+
+	tests := []struct {
+		name      string
+		setupMock func(*MockBudgetRepo)
+		wantErr   error
+		wantLen   int
+	}{
+		{
+			name: "success",
+			setupMock: func(m *MockBudgetRepo) {
+				m.On("GetByID", mock.Anything, budgetID).Return(&model.Budget{ID: budgetID, UserID: userID}, nil)
+				m.On("ListRollovers", mock.Anything, budgetID).Return([]model.BudgetRollover{{BudgetID: budgetID}, {BudgetID: budgetID}}, nil)
+			},
+			wantLen: 2,
+		},
+		{
+			name: "not owner",
+			setupMock: func(m *MockBudgetRepo) {
+				m.On("GetByID", mock.Anything, budgetID).Return(&model.Budget{ID: budgetID, UserID: uuid.New()}, nil)
+			},
+			wantErr: repository.ErrBudgetNotFound,
+		},
+		{
+			name: "not found",
+			setupMock: func(m *MockBudgetRepo) {
+				m.On("GetByID", mock.Anything, budgetID).Return(nil, repository.ErrBudgetNotFound)
+			},
+			wantErr: repository.ErrBudgetNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockBudgetRepo)
+			tt.setupMock(mockRepo)
+			service := NewBudgetService(mockRepo)
+
+			rollovers, err := service.ListRollovers(context.Background(), budgetID, userID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, rollovers, tt.wantLen)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+)
+
+var (
+	ErrInvalidCategoryName   = errors.New("category name is required and must be at most 100 characters")
+	ErrInvalidCategoryType   = errors.New("category type must be income or expense")
+	ErrInvalidCategoryColor  = errors.New("color must be a hex colour such as #4CAF50")
+	ErrInvalidCategoryIcon   = errors.New("icon must be at most 50 characters")
+	ErrInvalidCategoryParent = errors.New("parent must be a top-level category of the same type")
+	ErrCategoryHasChildren   = errors.New("a category with subcategories cannot be moved under another category")
+	ErrCategoryMergeSelf     = errors.New("cannot merge a category into itself")
+	ErrCategoryMergeType     = errors.New("cannot merge categories of different types")
+)
+
+var categoryColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+type CreateCategoryInput struct {
+	Name     string                `json:"name"`
+	Type     model.TransactionType `json:"type"`
+	ParentID *uuid.UUID            `json:"parentId,omitempty"`
+	Icon     string                `json:"icon"`
+	Color    string                `json:"color"`
+}
+
+// UpdateCategoryInput replaces a category's details. The type cannot change
+// because the transactions filed under the category have that type.
+type UpdateCategoryInput struct {
+	Name     string     `json:"name"`
+	ParentID *uuid.UUID `json:"parentId,omitempty"`
+	Icon     string     `json:"icon"`
+	Color    string     `json:"color"`
+	Archived bool       `json:"archived"`
+}
+
+// CategoryService manages the user's income and expense categories. Every user
+// starts with the default categories from model.ExpenseCategories and
+// model.IncomeCategories, plus any category name they had already used.
+type CategoryService struct {
+	repo repository.CategoryRepository
+}
+
+// NewCategoryService creates a new category service
+func NewCategoryService(repo repository.CategoryRepository) *CategoryService {
+	return &CategoryService{repo: repo}
+}
+
+// List returns the user's categories, each top-level category followed by its
+// subcategories
+func (s *CategoryService) List(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]model.Category, error) {
+	if err := s.seed(ctx, userID); err != nil {
+		return nil, err
+	}
+	categories, err := s.repo.List(ctx, userID, includeArchived)
+	if err != nil {
+		return nil, fmt.Errorf("listing categories: %w", err)
+	}
+	return categories, nil
+}
+
+// Create adds a category, optionally as a subcategory of a top-level category
+func (s *CategoryService) Create(ctx context.Context, userID uuid.UUID, input CreateCategoryInput) (*model.Category, error) {
+	if input.Type != model.TransactionTypeIncome && input.Type != model.TransactionTypeExpense {
+		return nil, ErrInvalidCategoryType
+	}
+	category := &model.Category{UserID: userID, Type: input.Type}
+	if err := applyCategoryDetails(category, input.Name, input.Icon, input.Color); err != nil {
+		return nil, err
+	}
+	if err := s.seed(ctx, userID); err != nil {
+		return nil, err
+	}
+	if input.ParentID != nil {
+		if err := s.checkParent(ctx, category, *input.ParentID); err != nil {
+			return nil, err
+		}
+		category.ParentID = input.ParentID
+	}
+
+	if err := s.repo.Create(ctx, category); err != nil {
+		return nil, fmt.Errorf("creating category: %w", err)
+	}
+	return category, nil
+}
+
+// Update changes a category. Renaming it refiles the user's transactions, budgets
+// and recurring transactions under the new name.
+func (s *CategoryService) Update(ctx context.Context, userID, id uuid.UUID, input UpdateCategoryInput) (*model.Category, error) {
+	category, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting category %s: %w", id, err)
+	}
+	if err := applyCategoryDetails(category, input.Name, input.Icon, input.Color); err != nil {
+		return nil, err
+	}
+
+	if input.ParentID != nil && (category.ParentID == nil || *category.ParentID != *input.ParentID) {
+		hasChildren, err := s.repo.HasChildren(ctx, userID, id)
+		if err != nil {
+			return nil, fmt.Errorf("checking subcategories of %s: %w", id, err)
+		}
+		if hasChildren {
+			return nil, ErrCategoryHasChildren
+		}
+		if err := s.checkParent(ctx, category, *input.ParentID); err != nil {
+			return nil, err
+		}
+	}
+	category.ParentID = input.ParentID
+	category.Archived = input.Archived
+
+	if err := s.repo.Update(ctx, category); err != nil {
+		return nil, fmt.Errorf("updating category %s: %w", id, err)
+	}
+	return category, nil
+}
+
+// Merge refiles everything under the source category to the target category and
+// deletes the source. Its subcategories move to the target. Returns
+// repository.ErrCategoryBudgetCurrency if both have a budget for the same period
+// in different currencies, which cannot be folded into one.
+func (s *CategoryService) Merge(ctx context.Context, userID, sourceID, targetID uuid.UUID) (*model.Category, error) {
+	if sourceID == targetID {
+		return nil, ErrCategoryMergeSelf
+	}
+	source, err := s.repo.GetByID(ctx, userID, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("getting category %s: %w", sourceID, err)
+	}
+	target, err := s.repo.GetByID(ctx, userID, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("getting category %s: %w", targetID, err)
+	}
+	if source.Type != target.Type {
+		return nil, ErrCategoryMergeType
+	}
+
+	if err := s.repo.Merge(ctx, source, target); err != nil {
+		return nil, fmt.Errorf("merging category %s into %s: %w", sourceID, targetID, err)
+	}
+	return target, nil
+}
+
+// seed gives the user the default categories the first time they use categories
+func (s *CategoryService) seed(ctx context.Context, userID uuid.UUID) error {
+	defaults := make([]model.Category, 0, len(model.ExpenseCategories)+len(model.IncomeCategories))
+	for _, name := range model.ExpenseCategories {
+		defaults = append(defaults, model.Category{Name: name, Type: model.TransactionTypeExpense})
+	}
+	for _, name := range model.IncomeCategories {
+		defaults = append(defaults, model.Category{Name: name, Type: model.TransactionTypeIncome})
+	}
+	if err := s.repo.Seed(ctx, userID, defaults); err != nil {
+		return fmt.Errorf("seeding categories: %w", err)
+	}
+	return nil
+}
+
+// checkParent checks that parentID can be the parent of category: a different
+// top-level category of the same type
+func (s *CategoryService) checkParent(ctx context.Context, category *model.Category, parentID uuid.UUID) error {
+	if parentID == category.ID {
+		return ErrInvalidCategoryParent
+	}
+	parent, err := s.repo.GetByID(ctx, category.UserID, parentID)
+	if errors.Is(err, repository.ErrCategoryNotFound) {
+		return ErrInvalidCategoryParent
+	}
+	if err != nil {
+		return fmt.Errorf("getting parent category %s: %w", parentID, err)
+	}
+	if parent.Type != category.Type || parent.ParentID != nil {
+		return ErrInvalidCategoryParent
+	}
+	return nil
+}
+
+// applyCategoryDetails validates and sets the name, icon and colour of a category
+func applyCategoryDetails(category *model.Category, name, icon, color string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > 100 {
+		return ErrInvalidCategoryName
+	}
+	icon = strings.TrimSpace(icon)
+	if utf8.RuneCountInString(icon) > 50 {
+		return ErrInvalidCategoryIcon
+	}
+	if color != "" && !categoryColorPattern.MatchString(color) {
+		return ErrInvalidCategoryColor
+	}
+
+	category.Name = name
+	category.Icon = icon
+	category.Color = strings.ToUpper(color)
+	return nil
+}
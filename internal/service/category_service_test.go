@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+)
+
+// MockCategoryRepository implements repository.CategoryRepository for testing
+type MockCategoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) Create(ctx context.Context, category *model.Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) GetByID(ctx context.Context, userID, id uuid.UUID) (*model.Category, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) List(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]model.Category, error) {
+	args := m.Called(ctx, userID, includeArchived)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) HasChildren(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Update(ctx context.Context, category *model.Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) Merge(ctx context.Context, source, target *model.Category) error {
+	args := m.Called(ctx, source, target)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) Seed(ctx context.Context, userID uuid.UUID, defaults []model.Category) error {
+	args := m.Called(ctx, userID, defaults)
+	return args.Error(0)
+}
+
+func TestCategoryService_List_SeedsDefaults(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockCategoryRepository)
+	svc := NewCategoryService(repo)
+	userID := uuid.New()
+
+	repo.On("Seed", mock.Anything, userID, mock.MatchedBy(func(defaults []model.Category) bool {
+		return len(defaults) == len(model.ExpenseCategories)+len(model.IncomeCategories) &&
+			defaults[0].Type == model.TransactionTypeExpense &&
+			defaults[len(defaults)-1].Type == model.TransactionTypeIncome
+	})).Return(nil)
+	repo.On("List", mock.Anything, userID, false).Return([]model.Category{{Name: "Housing"}}, nil)
+
+	categories, err := svc.List(context.Background(), userID, false)
+
+	require.NoError(t, err)
+	assert.Len(t, categories, 1)
+	repo.AssertExpectations(t)
+}
+
+func TestCategoryService_Create(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	food := &model.Category{ID: uuid.New(), UserID: userID, Name: "Food & Dining", Type: model.TransactionTypeExpense}
+	coffee := &model.Category{ID: uuid.New(), UserID: userID, ParentID: &food.ID, Name: "Coffee", Type: model.TransactionTypeExpense}
+	salary := &model.Category{ID: uuid.New(), UserID: userID, Name: "Salary", Type: model.TransactionTypeIncome}
+	unknown := uuid.New()
+
+	tests := []struct {
+		name    string
+		input   CreateCategoryInput
+		wantErr error
+	}{
+		{
+			name:  "subcategory",
+			input: CreateCategoryInput{Name: " Street food ", Type: model.TransactionTypeExpense, ParentID: &food.ID, Icon: "🍜", Color: "#ff9800"},
+		},
+		{
+			name:    "name required",
+			input:   CreateCategoryInput{Name: " ", Type: model.TransactionTypeExpense},
+			wantErr: ErrInvalidCategoryName,
+		},
+		{
+			name:    "unknown type",
+			input:   CreateCategoryInput{Name: "Transfers", Type: "transfer"},
+			wantErr: ErrInvalidCategoryType,
+		},
+		{
+			name:    "invalid colour",
+			input:   CreateCategoryInput{Name: "Pets", Type: model.TransactionTypeExpense, Color: "orange"},
+			wantErr: ErrInvalidCategoryColor,
+		},
+		{
+			name:    "parent of another type",
+			input:   CreateCategoryInput{Name: "Bonus", Type: model.TransactionTypeExpense, ParentID: &salary.ID},
+			wantErr: ErrInvalidCategoryParent,
+		},
+		{
+			name:    "parent is a subcategory",
+			input:   CreateCategoryInput{Name: "Espresso", Type: model.TransactionTypeExpense, ParentID: &coffee.ID},
+			wantErr: ErrInvalidCategoryParent,
+		},
+		{
+			name:    "unknown parent",
+			input:   CreateCategoryInput{Name: "Pets", Type: model.TransactionTypeExpense, ParentID: &unknown},
+			wantErr: ErrInvalidCategoryParent,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockCategoryRepository)
+			svc := NewCategoryService(repo)
+
+			repo.On("Seed", mock.Anything, userID, mock.Anything).Return(nil).Maybe()
+			for _, c := range []*model.Category{food, coffee, salary} {
+				repo.On("GetByID", mock.Anything, userID, c.ID).Return(c, nil).Maybe()
+			}
+			repo.On("GetByID", mock.Anything, userID, unknown).Return(nil, repository.ErrCategoryNotFound).Maybe()
+			repo.On("Create", mock.Anything, mock.AnythingOfType("*model.Category")).Return(nil).Maybe()
+
+			category, err := svc.Create(context.Background(), userID, tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Street food", category.Name)
+			assert.Equal(t, &food.ID, category.ParentID)
+			assert.Equal(t, "#FF9800", category.Color)
+		})
+	}
+}
+
+func TestCategoryService_Update(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	shopping := &model.Category{ID: uuid.New(), UserID: userID, Name: "Shopping", Type: model.TransactionTypeExpense}
+
+	t.Run("rename", func(t *testing.T) {
+		t.Parallel()
+
+		repo := new(MockCategoryRepository)
+		svc := NewCategoryService(repo)
+		food := &model.Category{ID: uuid.New(), UserID: userID, Name: "Food & Dining", Type: model.TransactionTypeExpense}
+
+		repo.On("GetByID", mock.Anything, userID, food.ID).Return(food, nil)
+		repo.On("Update", mock.Anything, mock.MatchedBy(func(c *model.Category) bool {
+			return c.Name == "Ăn uống" && c.Archived
+		})).Return(nil)
+
+		category, err := svc.Update(context.Background(), userID, food.ID, UpdateCategoryInput{Name: "Ăn uống", Archived: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Ăn uống", category.Name)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("category with subcategories cannot get a parent", func(t *testing.T) {
+		t.Parallel()
+
+		repo := new(MockCategoryRepository)
+		svc := NewCategoryService(repo)
+		food := &model.Category{ID: uuid.New(), UserID: userID, Name: "Food & Dining", Type: model.TransactionTypeExpense}
+
+		repo.On("GetByID", mock.Anything, userID, food.ID).Return(food, nil)
+		repo.On("HasChildren", mock.Anything, userID, food.ID).Return(true, nil)
+
+		_, err := svc.Update(context.Background(), userID, food.ID, UpdateCategoryInput{Name: "Food & Dining", ParentID: &shopping.ID})
+
+		assert.ErrorIs(t, err, ErrCategoryHasChildren)
+		repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("category cannot be its own parent", func(t *testing.T) {
+		t.Parallel()
+
+		repo := new(MockCategoryRepository)
+		svc := NewCategoryService(repo)
+		gifts := &model.Category{ID: uuid.New(), UserID: userID, Name: "Gifts", Type: model.TransactionTypeExpense}
+
+		repo.On("GetByID", mock.Anything, userID, gifts.ID).Return(gifts, nil)
+		repo.On("HasChildren", mock.Anything, userID, gifts.ID).Return(false, nil)
+
+		_, err := svc.Update(context.Background(), userID, gifts.ID, UpdateCategoryInput{Name: "Gifts", ParentID: &gifts.ID})
+
+		assert.ErrorIs(t, err, ErrInvalidCategoryParent)
+	})
+}
+
+func TestCategoryService_Merge(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	coffee := &model.Category{ID: uuid.New(), UserID: userID, Name: "Coffee", Type: model.TransactionTypeExpense}
+	cafe := &model.Category{ID: uuid.New(), UserID: userID, Name: "Cafe", Type: model.TransactionTypeExpense}
+	gifts := &model.Category{ID: uuid.New(), UserID: userID, Name: "Gifts", Type: model.TransactionTypeIncome}
+
+	tests := []struct {
+		name     string
+		sourceID uuid.UUID
+		targetID uuid.UUID
+		wantErr  error
+	}{
+		{name: "success", sourceID: cafe.ID, targetID: coffee.ID},
+		{name: "into itself", sourceID: cafe.ID, targetID: cafe.ID, wantErr: ErrCategoryMergeSelf},
+		{name: "different types", sourceID: cafe.ID, targetID: gifts.ID, wantErr: ErrCategoryMergeType},
+		{name: "unknown target", sourceID: cafe.ID, targetID: uuid.New(), wantErr: repository.ErrCategoryNotFound},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockCategoryRepository)
+			svc := NewCategoryService(repo)
+
+			for _, c := range []*model.Category{coffee, cafe, gifts} {
+				repo.On("GetByID", mock.Anything, userID, c.ID).Return(c, nil).Maybe()
+			}
+			repo.On("GetByID", mock.Anything, userID, mock.Anything).Return(nil, repository.ErrCategoryNotFound).Maybe()
+			repo.On("Merge", mock.Anything, cafe, coffee).Return(nil).Maybe()
+
+			target, err := svc.Merge(context.Background(), userID, tt.sourceID, tt.targetID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, coffee, target)
+			repo.AssertCalled(t, "Merge", mock.Anything, cafe, coffee)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/shopspring/decimal"
 
 	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/pkg/currency"
 )
 
 // DashboardTransactionRepo provides transaction data needed for dashboard aggregations.
@@ -27,12 +29,23 @@ type DashboardBudgetRepo interface {
 // DashboardSavingsRepo provides savings data needed for dashboard.
 type DashboardSavingsRepo interface {
 	List(ctx context.Context, userID uuid.UUID) ([]model.SavingsGoal, error)
-	GetTotalSavings(ctx context.Context, userID uuid.UUID) (decimal.Decimal, error)
 }
 
 // DashboardDebtRepo provides debt data needed for dashboard.
 type DashboardDebtRepo interface {
-	GetTotalDebt(ctx context.Context, userID uuid.UUID) (decimal.Decimal, error)
+	List(ctx context.Context, userID uuid.UUID) ([]model.Debt, error)
+}
+
+// DashboardAccountRepo provides account balances needed for dashboard.
+type DashboardAccountRepo interface {
+	ListWithBalances(ctx context.Context, userID uuid.UUID, includeArchived bool, asOf time.Time) ([]model.AccountWithBalance, error)
+}
+
+// DashboardCurrencyRepo provides the user's base currency, which transaction
+// totals are in, and counts the transactions left out of them until converted.
+type DashboardCurrencyRepo interface {
+	GetUserCurrency(ctx context.Context, userID uuid.UUID) (string, error)
+	CountUnconverted(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) (int, error)
 }
 
 // DashboardService aggregates financial data from multiple sources for dashboard display.
@@ -41,6 +54,9 @@ type DashboardService struct {
 	budgetRepo      DashboardBudgetRepo
 	savingsRepo     DashboardSavingsRepo
 	debtRepo        DashboardDebtRepo
+	accountRepo     DashboardAccountRepo
+	currencyRepo    DashboardCurrencyRepo
+	rates           currency.RateSource
 }
 
 // NewDashboardService creates a new DashboardService with the required repository dependencies.
@@ -58,6 +74,23 @@ func NewDashboardService(
 	}
 }
 
+// SetAccountRepo sets the repository used to show account balances.
+func (s *DashboardService) SetAccountRepo(repo DashboardAccountRepo) {
+	s.accountRepo = repo
+}
+
+// SetCurrencyRepo sets the repository used to find the user's base currency.
+// Without it, totals are rounded as USD.
+func (s *DashboardService) SetCurrencyRepo(repo DashboardCurrencyRepo) {
+	s.currencyRepo = repo
+}
+
+// SetRateSource sets the rates used to bring savings goals, debts and budgets
+// in another currency to the base currency. Without it, they are left out.
+func (s *DashboardService) SetRateSource(rates currency.RateSource) {
+	s.rates = rates
+}
+
 // GetDashboard retrieves dashboard data for the current month.
 func (s *DashboardService) GetDashboard(ctx context.Context, userID uuid.UUID) (*model.DashboardData, error) {
 	now := time.Now()
@@ -66,44 +99,86 @@ func (s *DashboardService) GetDashboard(ctx context.Context, userID uuid.UUID) (
 
 // GetMonthlyDashboard retrieves aggregated financial data for a specific month.
 // Includes income/expenses, budget progress, savings goals, debt totals, and trends.
+// Transaction totals are in the user's base currency, rounded to its decimal places.
+// Transactions without a rate to it yet are left out and counted instead, as
+// are savings goals, debts and budgets in a currency without a rate to it.
 func (s *DashboardService) GetMonthlyDashboard(ctx context.Context, userID uuid.UUID, year, month int) (*model.DashboardData, error) {
+	curr := string(currency.DefaultCurrency)
+	if s.currencyRepo != nil {
+		userCurrency, err := s.currencyRepo.GetUserCurrency(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("getting user currency: %w", err)
+		}
+		curr = userCurrency
+	}
+
 	income, expenses, err := s.transactionRepo.GetMonthlyTotals(ctx, userID, year, month)
 	if err != nil {
 		return nil, fmt.Errorf("getting monthly totals: %w", err)
 	}
+	income, expenses = roundAmount(income, curr), roundAmount(expenses, curr)
 
 	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
 	endDate := startDate.AddDate(0, 1, 0).Add(-time.Second)
 
+	unconverted := 0
+	if s.currencyRepo != nil {
+		unconverted, err = s.currencyRepo.CountUnconverted(ctx, userID, startDate, endDate)
+		if err != nil {
+			return nil, fmt.Errorf("counting unconverted transactions: %w", err)
+		}
+	}
+
 	expensesByCategory, err := s.transactionRepo.GetExpensesByCategory(ctx, userID, startDate, endDate)
 	if err != nil {
 		return nil, fmt.Errorf("getting expenses by category: %w", err)
 	}
+	for category, amount := range expensesByCategory {
+		expensesByCategory[category] = roundAmount(amount, curr)
+	}
 
 	budgets, err := s.budgetRepo.GetActiveForUser(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("getting active budgets: %w", err)
 	}
 
-	budgetSummary := make([]model.BudgetWithSpent, len(budgets))
-	for i, budget := range budgets {
+	// Rates are as of the end of the month, or today for the current month
+	asOf := endDate
+	if today := time.Now().UTC(); today.Before(asOf) {
+		asOf = today
+	}
+
+	unconvertedBalances := 0
+	budgetSummary := make([]model.BudgetWithSpent, 0, len(budgets))
+	for _, budget := range budgets {
 		spent, err := s.transactionRepo.GetSpentByCategory(ctx, userID, budget.Category, startDate, endDate)
 		if err != nil {
 			return nil, fmt.Errorf("getting spent for budget %s: %w", budget.Category, err)
 		}
 
+		// Spent is in the base currency, the budget in its own
+		spent, ok, err := convertBetween(ctx, s.rates, roundAmount(spent, curr), curr, budget.Currency, asOf)
+		if err != nil {
+			return nil, fmt.Errorf("converting spent for budget %s: %w", budget.Category, err)
+		}
+		if !ok {
+			unconvertedBalances++
+			budgetSummary = append(budgetSummary, model.BudgetWithSpent{Budget: budget, Unconverted: true})
+			continue
+		}
+
 		remaining := budget.Amount.Sub(spent)
 		percentage := float64(0)
 		if !budget.Amount.IsZero() {
 			percentage = spent.Div(budget.Amount).Mul(decimal.NewFromInt(100)).InexactFloat64()
 		}
 
-		budgetSummary[i] = model.BudgetWithSpent{
+		budgetSummary = append(budgetSummary, model.BudgetWithSpent{
 			Budget:     budget,
 			Spent:      spent,
 			Remaining:  remaining,
 			Percentage: percentage,
-		}
+		})
 	}
 
 	savingsGoals, err := s.savingsRepo.List(ctx, userID)
@@ -111,14 +186,35 @@ func (s *DashboardService) GetMonthlyDashboard(ctx context.Context, userID uuid.
 		return nil, fmt.Errorf("getting savings goals: %w", err)
 	}
 
-	totalSavings, err := s.savingsRepo.GetTotalSavings(ctx, userID)
-	if err != nil {
-		return nil, fmt.Errorf("getting total savings: %w", err)
+	totalSavings := decimal.Zero
+	for _, goal := range savingsGoals {
+		amount, ok, err := convertBetween(ctx, s.rates, goal.CurrentAmount, goal.Currency, curr, asOf)
+		if err != nil {
+			return nil, fmt.Errorf("converting savings goal %s: %w", goal.Name, err)
+		}
+		if !ok {
+			unconvertedBalances++
+			continue
+		}
+		totalSavings = totalSavings.Add(amount)
 	}
 
-	totalDebt, err := s.debtRepo.GetTotalDebt(ctx, userID)
+	debts, err := s.debtRepo.List(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("getting total debt: %w", err)
+		return nil, fmt.Errorf("getting debts: %w", err)
+	}
+
+	totalDebt := decimal.Zero
+	for _, debt := range debts {
+		amount, ok, err := convertBetween(ctx, s.rates, debt.CurrentBalance, debt.Currency, curr, asOf)
+		if err != nil {
+			return nil, fmt.Errorf("converting debt %s: %w", debt.Name, err)
+		}
+		if !ok {
+			unconvertedBalances++
+			continue
+		}
+		totalDebt = totalDebt.Add(amount)
 	}
 
 	recentTransactions, err := s.transactionRepo.GetRecentTransactions(ctx, userID, 10)
@@ -135,21 +231,70 @@ func (s *DashboardService) GetMonthlyDashboard(ctx context.Context, userID uuid.
 		}
 		incomeVsExpenses[5-i] = model.MonthlyComparison{
 			Month:    m.Format("Jan 2006"),
-			Income:   inc,
-			Expenses: exp,
+			Income:   roundAmount(inc, curr),
+			Expenses: roundAmount(exp, curr),
+		}
+	}
+
+	// Balances are as of the end of the month, or today for the current month
+	accounts := []model.AccountWithBalance{}
+	if s.accountRepo != nil {
+		accounts, err = s.accountRepo.ListWithBalances(ctx, userID, false, asOf)
+		if err != nil {
+			return nil, fmt.Errorf("getting account balances: %w", err)
 		}
 	}
 
 	return &model.DashboardData{
-		TotalIncome:        income,
-		TotalExpenses:      expenses,
-		NetCashFlow:        income.Sub(expenses),
-		TotalSavings:       totalSavings,
-		TotalDebt:          totalDebt,
-		BudgetSummary:      budgetSummary,
-		SavingsGoals:       savingsGoals,
-		RecentTransactions: recentTransactions,
-		ExpensesByCategory: expensesByCategory,
-		IncomeVsExpenses:   incomeVsExpenses,
+		Currency:                curr,
+		TotalIncome:             income,
+		TotalExpenses:           expenses,
+		NetCashFlow:             income.Sub(expenses),
+		TotalSavings:            totalSavings,
+		TotalDebt:               totalDebt,
+		BudgetSummary:           budgetSummary,
+		SavingsGoals:            savingsGoals,
+		RecentTransactions:      recentTransactions,
+		ExpensesByCategory:      expensesByCategory,
+		IncomeVsExpenses:        incomeVsExpenses,
+		Accounts:                accounts,
+		UnconvertedTransactions: unconverted,
+		UnconvertedBalances:     unconvertedBalances,
 	}, nil
 }
+
+// roundAmount rounds an amount to the decimal places of the currency. An amount
+// that needs no rounding is returned as is.
+func roundAmount(amount decimal.Decimal, curr string) decimal.Decimal {
+	rounded := currency.NewMoney(amount, currency.Currency(curr)).Round().Amount
+	if rounded.Equal(amount) {
+		return amount
+	}
+	return rounded
+}
+
+// convertBetween converts an amount between currencies at the rate on the date,
+// treating an empty currency as USD. ok is false when there are no rates or no
+// rate between the two yet.
+func convertBetween(ctx context.Context, rates currency.RateSource, amount decimal.Decimal, from, to string, date time.Time) (converted decimal.Decimal, ok bool, err error) {
+	if from == "" {
+		from = string(currency.DefaultCurrency)
+	}
+	if to == "" {
+		to = string(currency.DefaultCurrency)
+	}
+	if from == to {
+		return amount, true, nil
+	}
+	if rates == nil {
+		return decimal.Zero, false, nil
+	}
+	money, err := currency.NewMoney(amount, currency.Currency(from)).ConvertAt(ctx, rates, currency.Currency(to), date)
+	if errors.Is(err, ErrNoExchangeRate) {
+		return decimal.Zero, false, nil
+	}
+	if err != nil {
+		return decimal.Zero, false, err
+	}
+	return money.Amount, true, nil
+}
@@ -68,17 +68,36 @@ func (m *MockDashboardSavingsRepo) List(ctx context.Context, userID uuid.UUID) (
 	return args.Get(0).([]model.SavingsGoal), args.Error(1)
 }
 
-func (m *MockDashboardSavingsRepo) GetTotalSavings(ctx context.Context, userID uuid.UUID) (decimal.Decimal, error) {
+type MockDashboardDebtRepo struct {
+	mock.Mock
+}
+
+func (m *MockDashboardDebtRepo) List(ctx context.Context, userID uuid.UUID) ([]model.Debt, error) {
 	args := m.Called(ctx, userID)
-	return args.Get(0).(decimal.Decimal), args.Error(1)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Debt), args.Error(1)
 }
 
-type MockDashboardDebtRepo struct {
+type MockDashboardAccountRepo struct {
 	mock.Mock
 }
 
-func (m *MockDashboardDebtRepo) GetTotalDebt(ctx context.Context, userID uuid.UUID) (decimal.Decimal, error) {
-	args := m.Called(ctx, userID)
+func (m *MockDashboardAccountRepo) ListWithBalances(ctx context.Context, userID uuid.UUID, includeArchived bool, asOf time.Time) ([]model.AccountWithBalance, error) {
+	args := m.Called(ctx, userID, includeArchived, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AccountWithBalance), args.Error(1)
+}
+
+type MockRateSource struct {
+	mock.Mock
+}
+
+func (m *MockRateSource) Rate(ctx context.Context, from, to string, date time.Time) (decimal.Decimal, error) {
+	args := m.Called(ctx, from, to, date)
 	return args.Get(0).(decimal.Decimal), args.Error(1)
 }
 
@@ -115,8 +134,7 @@ func TestDashboardService_GetDashboard(t *testing.T) {
 	)
 	budgetRepo.On("GetActiveForUser", mock.Anything, userID).Return([]model.Budget{}, nil)
 	savingsRepo.On("List", mock.Anything, userID).Return([]model.SavingsGoal{}, nil)
-	savingsRepo.On("GetTotalSavings", mock.Anything, userID).Return(decimal.NewFromFloat(10000), nil)
-	debtRepo.On("GetTotalDebt", mock.Anything, userID).Return(decimal.NewFromFloat(5000), nil)
+	debtRepo.On("List", mock.Anything, userID).Return([]model.Debt{}, nil)
 	txRepo.On("GetRecentTransactions", mock.Anything, userID, 10).Return([]model.Transaction{}, nil)
 
 	dashboard, err := service.GetDashboard(context.Background(), userID)
@@ -160,8 +178,7 @@ func TestDashboardService_GetMonthlyDashboard_Success(t *testing.T) {
 	savingsRepo.On("List", mock.Anything, userID).Return([]model.SavingsGoal{
 		{ID: uuid.New(), Name: "Emergency Fund", TargetAmount: decimal.NewFromFloat(10000)},
 	}, nil)
-	savingsRepo.On("GetTotalSavings", mock.Anything, userID).Return(decimal.NewFromFloat(10000), nil)
-	debtRepo.On("GetTotalDebt", mock.Anything, userID).Return(decimal.NewFromFloat(5000), nil)
+	debtRepo.On("List", mock.Anything, userID).Return([]model.Debt{}, nil)
 	txRepo.On("GetRecentTransactions", mock.Anything, userID, 10).Return([]model.Transaction{
 		{ID: uuid.New(), Description: "Groceries"},
 	}, nil)
@@ -229,21 +246,7 @@ func TestDashboardService_GetMonthlyDashboard_Errors(t *testing.T) {
 			},
 		},
 		{
-			name: "total savings error",
-			setupMocks: func(tx *MockDashboardTxRepo, b *MockDashboardBudgetRepo, s *MockDashboardSavingsRepo, d *MockDashboardDebtRepo, userID uuid.UUID) {
-				tx.On("GetMonthlyTotals", mock.Anything, userID, mock.Anything, mock.Anything).Return(
-					decimal.NewFromFloat(5000), decimal.NewFromFloat(3000), nil,
-				)
-				tx.On("GetExpensesByCategory", mock.Anything, userID, mock.Anything, mock.Anything).Return(
-					map[string]decimal.Decimal{}, nil,
-				)
-				b.On("GetActiveForUser", mock.Anything, userID).Return([]model.Budget{}, nil)
-				s.On("List", mock.Anything, userID).Return([]model.SavingsGoal{}, nil)
-				s.On("GetTotalSavings", mock.Anything, userID).Return(decimal.Zero, errors.New("db error"))
-			},
-		},
-		{
-			name: "total debt error",
+			name: "debt list error",
 			setupMocks: func(tx *MockDashboardTxRepo, b *MockDashboardBudgetRepo, s *MockDashboardSavingsRepo, d *MockDashboardDebtRepo, userID uuid.UUID) {
 				tx.On("GetMonthlyTotals", mock.Anything, userID, mock.Anything, mock.Anything).Return(
 					decimal.NewFromFloat(5000), decimal.NewFromFloat(3000), nil,
@@ -253,8 +256,7 @@ func TestDashboardService_GetMonthlyDashboard_Errors(t *testing.T) {
 				)
 				b.On("GetActiveForUser", mock.Anything, userID).Return([]model.Budget{}, nil)
 				s.On("List", mock.Anything, userID).Return([]model.SavingsGoal{}, nil)
-				s.On("GetTotalSavings", mock.Anything, userID).Return(decimal.NewFromFloat(10000), nil)
-				d.On("GetTotalDebt", mock.Anything, userID).Return(decimal.Zero, errors.New("db error"))
+				d.On("List", mock.Anything, userID).Return(nil, errors.New("db error"))
 			},
 		},
 		{
@@ -268,8 +270,7 @@ func TestDashboardService_GetMonthlyDashboard_Errors(t *testing.T) {
 				)
 				b.On("GetActiveForUser", mock.Anything, userID).Return([]model.Budget{}, nil)
 				s.On("List", mock.Anything, userID).Return([]model.SavingsGoal{}, nil)
-				s.On("GetTotalSavings", mock.Anything, userID).Return(decimal.NewFromFloat(10000), nil)
-				d.On("GetTotalDebt", mock.Anything, userID).Return(decimal.NewFromFloat(5000), nil)
+				d.On("List", mock.Anything, userID).Return([]model.Debt{}, nil)
 				tx.On("GetRecentTransactions", mock.Anything, userID, 10).Return(nil, errors.New("db error"))
 			},
 		},
@@ -327,8 +328,7 @@ func TestDashboardService_BudgetPercentageCalculation(t *testing.T) {
 		decimal.NewFromFloat(100), nil,
 	)
 	savingsRepo.On("List", mock.Anything, userID).Return([]model.SavingsGoal{}, nil)
-	savingsRepo.On("GetTotalSavings", mock.Anything, userID).Return(decimal.Zero, nil)
-	debtRepo.On("GetTotalDebt", mock.Anything, userID).Return(decimal.Zero, nil)
+	debtRepo.On("List", mock.Anything, userID).Return([]model.Debt{}, nil)
 	txRepo.On("GetRecentTransactions", mock.Anything, userID, 10).Return([]model.Transaction{}, nil)
 
 	dashboard, err := service.GetMonthlyDashboard(context.Background(), userID, 2024, 6)
@@ -338,3 +338,136 @@ func TestDashboardService_BudgetPercentageCalculation(t *testing.T) {
 	assert.Len(t, dashboard.BudgetSummary, 1)
 	assert.Equal(t, float64(0), dashboard.BudgetSummary[0].Percentage) // No division by zero
 }
+
+func TestDashboardService_AccountBalances(t *testing.T) {
+	t.Parallel()
+
+	txRepo := new(MockDashboardTxRepo)
+	budgetRepo := new(MockDashboardBudgetRepo)
+	savingsRepo := new(MockDashboardSavingsRepo)
+	debtRepo := new(MockDashboardDebtRepo)
+	accountRepo := new(MockDashboardAccountRepo)
+
+	service := NewDashboardService(txRepo, budgetRepo, savingsRepo, debtRepo)
+	service.SetAccountRepo(accountRepo)
+	userID := uuid.New()
+
+	txRepo.On("GetMonthlyTotals", mock.Anything, userID, mock.Anything, mock.Anything).Return(decimal.Zero, decimal.Zero, nil)
+	txRepo.On("GetExpensesByCategory", mock.Anything, userID, mock.Anything, mock.Anything).Return(map[string]decimal.Decimal{}, nil)
+	budgetRepo.On("GetActiveForUser", mock.Anything, userID).Return([]model.Budget{}, nil)
+	savingsRepo.On("List", mock.Anything, userID).Return([]model.SavingsGoal{}, nil)
+	debtRepo.On("List", mock.Anything, userID).Return([]model.Debt{}, nil)
+	txRepo.On("GetRecentTransactions", mock.Anything, userID, 10).Return([]model.Transaction{}, nil)
+
+	// A past month shows balances as of its last day
+	endOfJune := time.Date(2024, 6, 30, 23, 59, 59, 0, time.UTC)
+	accountRepo.On("ListWithBalances", mock.Anything, userID, false, endOfJune).Return([]model.AccountWithBalance{
+		{Account: model.Account{Name: "Cash", Type: model.AccountTypeCash}, Balance: decimal.NewFromInt(150)},
+		{Account: model.Account{Name: "MoMo", Type: model.AccountTypeEWallet}, Balance: decimal.NewFromInt(80)},
+	}, nil)
+
+	dashboard, err := service.GetMonthlyDashboard(context.Background(), userID, 2024, 6)
+
+	assert.NoError(t, err)
+	assert.Len(t, dashboard.Accounts, 2)
+	assert.True(t, decimal.NewFromInt(80).Equal(dashboard.Accounts[1].Balance))
+	accountRepo.AssertExpectations(t)
+
+	accountRepo.On("ListWithBalances", mock.Anything, userID, false, mock.Anything).Return(nil, errors.New("db error"))
+
+	_, err = service.GetMonthlyDashboard(context.Background(), userID, 2024, 7)
+	assert.Error(t, err)
+}
+
+func TestDashboardService_BaseCurrency(t *testing.T) {
+	t.Parallel()
+
+	txRepo := new(MockDashboardTxRepo)
+	budgetRepo := new(MockDashboardBudgetRepo)
+	savingsRepo := new(MockDashboardSavingsRepo)
+	debtRepo := new(MockDashboardDebtRepo)
+	currencyRepo := new(MockReportRepository)
+
+	service := NewDashboardService(txRepo, budgetRepo, savingsRepo, debtRepo)
+	service.SetCurrencyRepo(currencyRepo)
+	userID := uuid.New()
+
+	// Converted amounts can have cents, which VND does not
+	currencyRepo.On("GetUserCurrency", mock.Anything, userID).Return("VND", nil)
+	currencyRepo.On("CountUnconverted", mock.Anything, userID, mock.Anything, mock.Anything).Return(2, nil)
+	txRepo.On("GetMonthlyTotals", mock.Anything, userID, mock.Anything, mock.Anything).
+		Return(decimal.RequireFromString("1500000.40"), decimal.RequireFromString("308500.55"), nil)
+	txRepo.On("GetExpensesByCategory", mock.Anything, userID, mock.Anything, mock.Anything).
+		Return(map[string]decimal.Decimal{"Food": decimal.RequireFromString("308500.55")}, nil)
+	budgetRepo.On("GetActiveForUser", mock.Anything, userID).Return([]model.Budget{}, nil)
+	savingsRepo.On("List", mock.Anything, userID).Return([]model.SavingsGoal{}, nil)
+	debtRepo.On("List", mock.Anything, userID).Return([]model.Debt{}, nil)
+	txRepo.On("GetRecentTransactions", mock.Anything, userID, 10).Return([]model.Transaction{}, nil)
+
+	dashboard, err := service.GetMonthlyDashboard(context.Background(), userID, 2024, 6)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "VND", dashboard.Currency)
+	assert.Equal(t, "1500000", dashboard.TotalIncome.String())
+	assert.Equal(t, "308501", dashboard.TotalExpenses.String())
+	assert.Equal(t, "1191499", dashboard.NetCashFlow.String())
+	assert.Equal(t, "308501", dashboard.ExpensesByCategory["Food"].String())
+	assert.Equal(t, "308501", dashboard.IncomeVsExpenses[5].Expenses.String())
+	assert.Equal(t, 2, dashboard.UnconvertedTransactions)
+}
+
+func TestDashboardService_ForeignCurrencyBalances(t *testing.T) {
+	t.Parallel()
+
+	txRepo := new(MockDashboardTxRepo)
+	budgetRepo := new(MockDashboardBudgetRepo)
+	savingsRepo := new(MockDashboardSavingsRepo)
+	debtRepo := new(MockDashboardDebtRepo)
+	currencyRepo := new(MockReportRepository)
+	rates := new(MockRateSource)
+
+	service := NewDashboardService(txRepo, budgetRepo, savingsRepo, debtRepo)
+	service.SetCurrencyRepo(currencyRepo)
+	service.SetRateSource(rates)
+	userID := uuid.New()
+
+	currencyRepo.On("GetUserCurrency", mock.Anything, userID).Return("VND", nil)
+	currencyRepo.On("CountUnconverted", mock.Anything, userID, mock.Anything, mock.Anything).Return(0, nil)
+	txRepo.On("GetMonthlyTotals", mock.Anything, userID, mock.Anything, mock.Anything).Return(decimal.Zero, decimal.Zero, nil)
+	txRepo.On("GetExpensesByCategory", mock.Anything, userID, mock.Anything, mock.Anything).Return(map[string]decimal.Decimal{}, nil)
+	txRepo.On("GetRecentTransactions", mock.Anything, userID, 10).Return([]model.Transaction{}, nil)
+
+	rates.On("Rate", mock.Anything, "USD", "VND", mock.Anything).Return(decimal.NewFromInt(25000), nil)
+	rates.On("Rate", mock.Anything, "VND", "USD", mock.Anything).Return(decimal.RequireFromString("0.00004"), nil)
+	rates.On("Rate", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(decimal.Zero, ErrNoExchangeRate)
+
+	budgetRepo.On("GetActiveForUser", mock.Anything, userID).Return([]model.Budget{
+		{Category: "Travel", Amount: decimal.NewFromInt(200), Currency: "USD"},
+		{Category: "Books", Amount: decimal.NewFromInt(50), Currency: "GBP"},
+	}, nil)
+	txRepo.On("GetSpentByCategory", mock.Anything, userID, "Travel", mock.Anything, mock.Anything).Return(decimal.NewFromInt(2500000), nil)
+	txRepo.On("GetSpentByCategory", mock.Anything, userID, "Books", mock.Anything, mock.Anything).Return(decimal.NewFromInt(100000), nil)
+	savingsRepo.On("List", mock.Anything, userID).Return([]model.SavingsGoal{
+		{Name: "Trip", CurrentAmount: decimal.NewFromInt(1000), Currency: "USD"},
+		{Name: "Tet", CurrentAmount: decimal.NewFromInt(5000000), Currency: "VND"},
+		{Name: "Paris", CurrentAmount: decimal.NewFromInt(300), Currency: "EUR"},
+	}, nil)
+	debtRepo.On("List", mock.Anything, userID).Return([]model.Debt{
+		{Name: "Card", CurrentBalance: decimal.NewFromInt(400), Currency: "USD"},
+	}, nil)
+
+	dashboard, err := service.GetMonthlyDashboard(context.Background(), userID, 2024, 6)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "30000000", dashboard.TotalSavings.String())
+	assert.Equal(t, "10000000", dashboard.TotalDebt.String())
+	// The USD budget is compared in USD, the GBP one without a rate is flagged
+	assert.Len(t, dashboard.BudgetSummary, 2)
+	assert.Equal(t, "100", dashboard.BudgetSummary[0].Spent.String())
+	assert.Equal(t, "100", dashboard.BudgetSummary[0].Remaining.String())
+	assert.Equal(t, float64(50), dashboard.BudgetSummary[0].Percentage)
+	assert.True(t, dashboard.BudgetSummary[1].Unconverted)
+	assert.False(t, dashboard.BudgetSummary[0].Unconverted)
+	assert.Len(t, dashboard.SavingsGoals, 3)
+	assert.Equal(t, 2, dashboard.UnconvertedBalances)
+}
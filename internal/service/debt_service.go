@@ -22,6 +22,7 @@ type DebtRepositoryInterface interface {
 	Update(ctx context.Context, debt *model.Debt) error
 	Delete(ctx context.Context, id, userID uuid.UUID) error
 	RecordPayment(ctx context.Context, payment *model.DebtPayment) error
+	GetPayments(ctx context.Context, debtID uuid.UUID) ([]model.DebtPayment, error)
 }
 
 // DebtService handles business logic for debt management and payoff calculations.
@@ -222,6 +223,26 @@ func (s *DebtService) MakePayment(ctx context.Context, debtID uuid.UUID, userID
 	return s.repo.GetByID(ctx, debtID)
 }
 
+// ListPayments returns the payments made on one of the user's debts, newest first
+func (s *DebtService) ListPayments(ctx context.Context, debtID, userID uuid.UUID) ([]model.DebtPayment, error) {
+	debt, err := s.repo.GetByID(ctx, debtID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching debt %s: %w", debtID, err)
+	}
+	if debt.UserID != userID {
+		return nil, repository.ErrDebtNotFound
+	}
+
+	payments, err := s.repo.GetPayments(ctx, debtID)
+	if err != nil {
+		return nil, fmt.Errorf("listing payments for debt %s: %w", debtID, err)
+	}
+	if payments == nil {
+		payments = []model.DebtPayment{}
+	}
+	return payments, nil
+}
+
 // GetPayoffPlan calculates a debt payoff plan based on the monthly payment amount.
 // If monthlyPayment is zero, uses the minimum payment from the debt.
 func (s *DebtService) GetPayoffPlan(ctx context.Context, id uuid.UUID, monthlyPayment decimal.Decimal) (*model.PayoffPlan, error) {
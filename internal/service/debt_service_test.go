@@ -58,6 +58,14 @@ func (m *MockDebtRepo) RecordPayment(ctx context.Context, payment *model.DebtPay
 	return args.Error(0)
 }
 
+func (m *MockDebtRepo) GetPayments(ctx context.Context, debtID uuid.UUID) ([]model.DebtPayment, error) {
+	args := m.Called(ctx, debtID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.DebtPayment), args.Error(1)
+}
+
 // Table-driven tests with parallel execution (following Go rules)
 func TestDebtService_Create(t *testing.T) {
 	t.Parallel()
@@ -384,6 +392,44 @@ func TestDebtService_MakePayment(t *testing.T) {
 	}
 }
 
+func TestDebtService_ListPayments(t *testing.T) {
+	t.Parallel()
+
+	userID, debtID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		owner   uuid.UUID
+		wantErr error
+	}{
+		{name: "own debt", owner: userID},
+		{name: "other user's debt", owner: uuid.New(), wantErr: repository.ErrDebtNotFound},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockDebtRepo)
+			svc := NewDebtService(repo)
+			repo.On("GetByID", mock.Anything, debtID).Return(&model.Debt{ID: debtID, UserID: tt.owner}, nil)
+			repo.On("GetPayments", mock.Anything, debtID).Return(nil, nil).Maybe()
+
+			payments, err := svc.ListPayments(context.Background(), debtID, userID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "GetPayments", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, payments)
+			assert.Empty(t, payments)
+		})
+	}
+}
+
 func TestDebtService_GetPayoffPlan(t *testing.T) {
 	t.Parallel()
 
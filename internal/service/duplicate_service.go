@@ -0,0 +1,302 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+)
+
+const (
+	// The review queue looks back duplicateDefaultDays days unless asked for
+	// more, up to duplicateMaxDays
+	duplicateDefaultDays = 90
+	duplicateMaxDays     = 365
+
+	// Transactions more than duplicateMaxDaysApart days apart are never
+	// suspected duplicates
+	duplicateMaxDaysApart = 3
+
+	duplicateDefaultMinScore = 60
+	duplicateDefaultLimit    = 50
+	duplicateMaxLimit        = 200
+
+	// maxDuplicateCandidates caps the pairs scored for one queue
+	maxDuplicateCandidates = 1000
+)
+
+// Weights of the signals in a duplicate score out of 100
+const (
+	duplicateAmountWeight      = 40
+	duplicateDateWeight        = 30
+	duplicateDescriptionWeight = 20
+	duplicateCategoryWeight    = 10
+)
+
+// duplicateAmountTolerance is how far apart, as a fraction of the larger amount,
+// the amounts of suspected duplicates can be
+var duplicateAmountTolerance = decimal.NewFromFloat(0.05)
+
+var (
+	ErrInvalidDuplicatePair = errors.New("a duplicate pair needs two different transactions")
+	ErrInvalidKeepID        = errors.New("keepId must be one of the two transactions")
+	ErrDuplicateMismatch    = errors.New("only transactions of the same type and currency can be merged")
+)
+
+// DuplicateQueueInput narrows the duplicate review queue
+type DuplicateQueueInput struct {
+	Days     int // How far back to look, default 90, max 365
+	MinScore int // Lowest score to include, default 60
+	Limit    int // Default 50, max 200
+}
+
+// DuplicateSignals show how alike a pair is on each signal, from 0 to 1
+type DuplicateSignals struct {
+	Amount      float64 `json:"amount"`
+	Date        float64 `json:"date"`
+	Description float64 `json:"description"`
+	Category    float64 `json:"category"`
+}
+
+// DuplicatePair is a pair of transactions that may be the same one recorded
+// twice, scored out of 100
+type DuplicatePair struct {
+	Score       int               `json:"score"`
+	Signals     DuplicateSignals  `json:"signals"`
+	Transaction model.Transaction `json:"transaction"`
+	Duplicate   model.Transaction `json:"duplicate"`
+}
+
+// DuplicatePairInput names a pair of transactions, in either order
+type DuplicatePairInput struct {
+	TransactionID uuid.UUID `json:"transactionId"`
+	DuplicateID   uuid.UUID `json:"duplicateId"`
+}
+
+// MergeDuplicatesInput names a pair of transactions and the one to keep
+type MergeDuplicatesInput struct {
+	DuplicatePairInput
+	KeepID uuid.UUID `json:"keepId"`
+}
+
+// DuplicateService finds transactions recorded twice, whether by hand, from the
+// AI chat, by recurring transactions or by imports, and resolves them
+type DuplicateService struct {
+	repo repository.DuplicateRepository
+}
+
+// NewDuplicateService creates a new duplicate service
+func NewDuplicateService(repo repository.DuplicateRepository) *DuplicateService {
+	return &DuplicateService{repo: repo}
+}
+
+// Queue returns the pairs waiting for review, the most likely duplicates first.
+// Pairs the user dismissed or marked as not duplicates are never returned again.
+func (s *DuplicateService) Queue(ctx context.Context, userID uuid.UUID, input DuplicateQueueInput) ([]DuplicatePair, error) {
+	days := input.Days
+	if days <= 0 {
+		days = duplicateDefaultDays
+	}
+	if days > duplicateMaxDays {
+		days = duplicateMaxDays
+	}
+	minScore := input.MinScore
+	if minScore <= 0 {
+		minScore = duplicateDefaultMinScore
+	}
+	limit := input.Limit
+	if limit <= 0 {
+		limit = duplicateDefaultLimit
+	}
+	if limit > duplicateMaxLimit {
+		limit = duplicateMaxLimit
+	}
+
+	now := time.Now()
+	since := time.Date(now.Year(), now.Month(), now.Day()-days, 0, 0, 0, 0, time.UTC)
+	candidates, err := s.repo.Candidates(ctx, userID, since, duplicateMaxDaysApart, duplicateAmountTolerance, maxDuplicateCandidates)
+	if err != nil {
+		return nil, fmt.Errorf("finding duplicate candidates: %w", err)
+	}
+	if len(candidates) == 0 {
+		return []DuplicatePair{}, nil
+	}
+
+	ids := make([]uuid.UUID, 0, 2*len(candidates))
+	for _, c := range candidates {
+		ids = append(ids, c.TransactionID, c.DuplicateID)
+	}
+	transactions, err := s.repo.TransactionsByIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("loading duplicate candidates: %w", err)
+	}
+	byID := make(map[uuid.UUID]model.Transaction, len(transactions))
+	for _, tx := range transactions {
+		byID[tx.ID] = tx
+	}
+
+	pairs := []DuplicatePair{}
+	for _, c := range candidates {
+		a, okA := byID[c.TransactionID]
+		b, okB := byID[c.DuplicateID]
+		if !okA || !okB {
+			continue
+		}
+		score, signals := scoreDuplicate(a, b, c.DescriptionSimilarity)
+		if score < minScore {
+			continue
+		}
+		pairs = append(pairs, DuplicatePair{Score: score, Signals: signals, Transaction: a, Duplicate: b})
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].Score > pairs[j].Score })
+	if len(pairs) > limit {
+		pairs = pairs[:limit]
+	}
+	return pairs, nil
+}
+
+// Dismiss hides a pair from the review queue for good
+func (s *DuplicateService) Dismiss(ctx context.Context, userID uuid.UUID, input DuplicatePairInput) (*model.DuplicateReview, error) {
+	return s.review(ctx, userID, input, model.DuplicateStatusDismissed)
+}
+
+// NotDuplicate records that a pair are separate transactions, which also keeps
+// it out of the review queue for good
+func (s *DuplicateService) NotDuplicate(ctx context.Context, userID uuid.UUID, input DuplicatePairInput) (*model.DuplicateReview, error) {
+	return s.review(ctx, userID, input, model.DuplicateStatusNotDuplicate)
+}
+
+// Merge keeps one transaction of a pair and deletes the other, moving its tags,
+// attachments, savings contributions and bank's ID to the one kept
+func (s *DuplicateService) Merge(ctx context.Context, userID uuid.UUID, input MergeDuplicatesInput) (*model.Transaction, error) {
+	pair, err := s.pair(ctx, userID, input.DuplicatePairInput)
+	if err != nil {
+		return nil, err
+	}
+
+	var keep, drop model.Transaction
+	switch input.KeepID {
+	case pair[0].ID:
+		keep, drop = pair[0], pair[1]
+	case pair[1].ID:
+		keep, drop = pair[1], pair[0]
+	default:
+		return nil, ErrInvalidKeepID
+	}
+	if keep.Type != drop.Type || keep.Currency != drop.Currency {
+		return nil, ErrDuplicateMismatch
+	}
+
+	if err := s.repo.Merge(ctx, userID, keep.ID, drop.ID); err != nil {
+		return nil, fmt.Errorf("merging transaction %s into %s: %w", drop.ID, keep.ID, err)
+	}
+
+	merged, err := s.repo.TransactionsByIDs(ctx, userID, []uuid.UUID{keep.ID})
+	if err != nil {
+		return nil, fmt.Errorf("getting transaction %s: %w", keep.ID, err)
+	}
+	if len(merged) == 0 {
+		return nil, repository.ErrTransactionNotFound
+	}
+	return &merged[0], nil
+}
+
+// review stores the user's decision on a pair
+func (s *DuplicateService) review(ctx context.Context, userID uuid.UUID, input DuplicatePairInput, status string) (*model.DuplicateReview, error) {
+	pair, err := s.pair(ctx, userID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	review := &model.DuplicateReview{
+		UserID:        userID,
+		TransactionID: pair[0].ID,
+		DuplicateID:   pair[1].ID,
+		Status:        status,
+	}
+	if err := s.repo.SaveReview(ctx, review); err != nil {
+		return nil, fmt.Errorf("saving duplicate review: %w", err)
+	}
+	return review, nil
+}
+
+// pair loads both transactions of a pair, lower ID first
+func (s *DuplicateService) pair(ctx context.Context, userID uuid.UUID, input DuplicatePairInput) ([2]model.Transaction, error) {
+	var pair [2]model.Transaction
+	if input.TransactionID == uuid.Nil || input.DuplicateID == uuid.Nil || input.TransactionID == input.DuplicateID {
+		return pair, ErrInvalidDuplicatePair
+	}
+
+	transactions, err := s.repo.TransactionsByIDs(ctx, userID, []uuid.UUID{input.TransactionID, input.DuplicateID})
+	if err != nil {
+		return pair, fmt.Errorf("getting transactions: %w", err)
+	}
+	if len(transactions) != 2 {
+		return pair, repository.ErrTransactionNotFound
+	}
+
+	pair[0], pair[1] = transactions[0], transactions[1]
+	if bytes.Compare(pair[0].ID[:], pair[1].ID[:]) > 0 {
+		pair[0], pair[1] = pair[1], pair[0]
+	}
+	return pair, nil
+}
+
+// scoreDuplicate scores out of 100 how likely two transactions are the same one.
+// Equal amounts, close dates, similar descriptions and the same category each
+// add to the score.
+func scoreDuplicate(a, b model.Transaction, descriptionSimilarity float64) (int, DuplicateSignals) {
+	var signals DuplicateSignals
+
+	larger := decimal.Max(a.Amount.Abs(), b.Amount.Abs())
+	diff := a.Amount.Sub(b.Amount).Abs()
+	if diff.IsZero() {
+		signals.Amount = 1
+	} else if larger.IsPositive() {
+		ratio, _ := diff.Div(larger.Mul(duplicateAmountTolerance)).Float64()
+		signals.Amount = math.Max(0, 1-ratio)
+	}
+
+	days := math.Abs(math.Round(a.Date.Sub(b.Date).Hours() / 24))
+	signals.Date = math.Max(0, 1-days/(duplicateMaxDaysApart+1))
+
+	descA, descB := strings.TrimSpace(a.Description), strings.TrimSpace(b.Description)
+	switch {
+	case descA == "" && descB == "":
+		signals.Description = 0.5
+	case descA == "" || descB == "":
+		signals.Description = 0
+	case strings.EqualFold(descA, descB):
+		signals.Description = 1
+	default:
+		signals.Description = math.Min(1, math.Max(0, descriptionSimilarity))
+	}
+
+	if strings.EqualFold(a.Category, b.Category) {
+		signals.Category = 1
+	}
+
+	score := duplicateAmountWeight*signals.Amount + duplicateDateWeight*signals.Date +
+		duplicateDescriptionWeight*signals.Description + duplicateCategoryWeight*signals.Category
+
+	signals.Amount = roundSignal(signals.Amount)
+	signals.Date = roundSignal(signals.Date)
+	signals.Description = roundSignal(signals.Description)
+	return int(math.Round(score)), signals
+}
+
+// roundSignal rounds a signal to two decimals for display
+func roundSignal(v float64) float64 {
+	return math.Round(v*100) / 100
+}
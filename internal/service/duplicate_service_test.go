@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+)
+
+// MockDuplicateRepository implements repository.DuplicateRepository for testing
+type MockDuplicateRepository struct {
+	mock.Mock
+}
+
+func (m *MockDuplicateRepository) Candidates(ctx context.Context, userID uuid.UUID, since time.Time, maxDaysApart int, amountTolerance decimal.Decimal, limit int) ([]repository.DuplicateCandidate, error) {
+	args := m.Called(ctx, userID, since, maxDaysApart, amountTolerance, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.DuplicateCandidate), args.Error(1)
+}
+
+func (m *MockDuplicateRepository) TransactionsByIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) ([]model.Transaction, error) {
+	args := m.Called(ctx, userID, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Transaction), args.Error(1)
+}
+
+func (m *MockDuplicateRepository) SaveReview(ctx context.Context, review *model.DuplicateReview) error {
+	args := m.Called(ctx, review)
+	return args.Error(0)
+}
+
+func (m *MockDuplicateRepository) Merge(ctx context.Context, userID, keepID, dropID uuid.UUID) error {
+	args := m.Called(ctx, userID, keepID, dropID)
+	return args.Error(0)
+}
+
+func duplicateTx(id uuid.UUID, amount int64, day int, description, category string) model.Transaction {
+	return model.Transaction{
+		ID:          id,
+		Type:        model.TransactionTypeExpense,
+		Amount:      decimal.NewFromInt(amount),
+		Currency:    "VND",
+		Category:    category,
+		Description: description,
+		Date:        time.Date(2024, 3, day, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestScoreDuplicate(t *testing.T) {
+	t.Parallel()
+
+	a, b := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name       string
+		a, b       model.Transaction
+		similarity float64
+		wantScore  int
+	}{
+		{
+			name:      "identical",
+			a:         duplicateTx(a, 50000, 5, "Highlands Coffee", "Food & Dining"),
+			b:         duplicateTx(b, 50000, 5, "highlands coffee", "food & dining"),
+			wantScore: 100,
+		},
+		{
+			name:       "a day apart with similar descriptions",
+			a:          duplicateTx(a, 50000, 5, "Highlands Coffee", "Food & Dining"),
+			b:          duplicateTx(b, 50000, 6, "HIGHLANDS COFFEE Q1", "Food & Dining"),
+			similarity: 0.6,
+			wantScore:  85, // 40 + 22.5 + 12 + 10
+		},
+		{
+			name:      "amount at the tolerance",
+			a:         duplicateTx(a, 100000, 5, "", "Other"),
+			b:         duplicateTx(b, 95000, 5, "", "Other"),
+			wantScore: 50, // 0 + 30 + 10 + 10
+		},
+		{
+			name:      "one description missing",
+			a:         duplicateTx(a, 50000, 5, "Grab", "Transportation"),
+			b:         duplicateTx(b, 50000, 9, "", "Other"),
+			wantScore: 40,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			score, signals := scoreDuplicate(tt.a, tt.b, tt.similarity)
+
+			assert.Equal(t, tt.wantScore, score)
+			assert.GreaterOrEqual(t, signals.Amount, 0.0)
+			assert.LessOrEqual(t, signals.Amount, 1.0)
+		})
+	}
+}
+
+func TestDuplicateService_Queue(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+	transactions := []model.Transaction{
+		duplicateTx(ids[0], 50000, 5, "Highlands Coffee", "Food & Dining"),
+		duplicateTx(ids[1], 50000, 5, "Highlands Coffee", "Food & Dining"),
+		duplicateTx(ids[2], 100000, 5, "Grab", "Transportation"),
+		duplicateTx(ids[3], 96000, 8, "Shopee", "Shopping"),
+	}
+
+	repo := new(MockDuplicateRepository)
+	repo.On("Candidates", mock.Anything, userID, mock.Anything, duplicateMaxDaysApart, duplicateAmountTolerance, maxDuplicateCandidates).
+		Return([]repository.DuplicateCandidate{
+			{TransactionID: ids[2], DuplicateID: ids[3], DescriptionSimilarity: 0},
+			{TransactionID: ids[0], DuplicateID: ids[1], DescriptionSimilarity: 1},
+		}, nil)
+	repo.On("TransactionsByIDs", mock.Anything, userID, []uuid.UUID{ids[2], ids[3], ids[0], ids[1]}).
+		Return(transactions, nil)
+
+	pairs, err := NewDuplicateService(repo).Queue(context.Background(), userID, DuplicateQueueInput{})
+
+	require.NoError(t, err)
+	require.Len(t, pairs, 1, "the low scoring pair is left out")
+	assert.Equal(t, 100, pairs[0].Score)
+	assert.Equal(t, ids[0], pairs[0].Transaction.ID)
+	assert.Equal(t, ids[1], pairs[0].Duplicate.ID)
+	repo.AssertExpectations(t)
+}
+
+func TestDuplicateService_Dismiss(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	first, second := uuid.New(), uuid.New()
+	if first.String() > second.String() {
+		first, second = second, first
+	}
+
+	tests := []struct {
+		name    string
+		input   DuplicatePairInput
+		found   []model.Transaction
+		wantErr error
+	}{
+		{
+			name:  "stores the pair lower ID first",
+			input: DuplicatePairInput{TransactionID: second, DuplicateID: first},
+			found: []model.Transaction{{ID: second}, {ID: first}},
+		},
+		{
+			name:    "same transaction twice",
+			input:   DuplicatePairInput{TransactionID: first, DuplicateID: first},
+			wantErr: ErrInvalidDuplicatePair,
+		},
+		{
+			name:    "transaction not found",
+			input:   DuplicatePairInput{TransactionID: first, DuplicateID: second},
+			found:   []model.Transaction{{ID: first}},
+			wantErr: repository.ErrTransactionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockDuplicateRepository)
+			if tt.found != nil {
+				repo.On("TransactionsByIDs", mock.Anything, userID, mock.Anything).Return(tt.found, nil)
+			}
+			repo.On("SaveReview", mock.Anything, mock.MatchedBy(func(r *model.DuplicateReview) bool {
+				return r.TransactionID == first && r.DuplicateID == second && r.Status == model.DuplicateStatusDismissed
+			})).Return(nil).Maybe()
+
+			review, err := NewDuplicateService(repo).Dismiss(context.Background(), userID, tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "SaveReview", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, first, review.TransactionID)
+			assert.Equal(t, second, review.DuplicateID)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDuplicateService_Merge(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	keepID, dropID := uuid.New(), uuid.New()
+	keep := duplicateTx(keepID, 50000, 5, "Highlands Coffee", "Food & Dining")
+	drop := duplicateTx(dropID, 50000, 5, "", "Food & Dining")
+	usd := drop
+	usd.Currency = "USD"
+
+	tests := []struct {
+		name    string
+		keepID  uuid.UUID
+		found   []model.Transaction
+		wantErr error
+	}{
+		{name: "success", keepID: keepID, found: []model.Transaction{keep, drop}},
+		{name: "keep ID outside the pair", keepID: uuid.New(), found: []model.Transaction{keep, drop}, wantErr: ErrInvalidKeepID},
+		{name: "different currencies", keepID: keepID, found: []model.Transaction{keep, usd}, wantErr: ErrDuplicateMismatch},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockDuplicateRepository)
+			repo.On("TransactionsByIDs", mock.Anything, userID, []uuid.UUID{keepID, dropID}).Return(tt.found, nil)
+			repo.On("Merge", mock.Anything, userID, keepID, dropID).Return(nil).Maybe()
+			repo.On("TransactionsByIDs", mock.Anything, userID, []uuid.UUID{keepID}).Return([]model.Transaction{keep}, nil).Maybe()
+
+			tx, err := NewDuplicateService(repo).Merge(context.Background(), userID, MergeDuplicatesInput{
+				DuplicatePairInput: DuplicatePairInput{TransactionID: keepID, DuplicateID: dropID},
+				KeepID:             tt.keepID,
+			})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, keepID, tx.ID)
+			repo.AssertExpectations(t)
+		})
+	}
+}
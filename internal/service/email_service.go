@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wealthpath/backend/internal/email"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+// Outbox delivery defaults
+const (
+	DefaultEmailMaxAttempts = 6
+	emailBatchSize          = 50
+	emailLease              = 5 * time.Minute
+	emailBaseBackoff        = time.Minute
+	emailMaxBackoff         = 2 * time.Hour
+)
+
+// EmailOutboxRepositoryInterface stores outgoing email until it is delivered
+type EmailOutboxRepositoryInterface interface {
+	Create(ctx context.Context, email *model.OutboxEmail) error
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxEmail, error)
+	MarkSent(ctx context.Context, id uuid.UUID) error
+	MarkRetry(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time, lastError string) error
+	MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error
+}
+
+// EmailTransport delivers a rendered message (e.g. email.SMTPTransport)
+type EmailTransport interface {
+	Deliver(ctx context.Context, msg *email.Message) error
+}
+
+// EmailService queues email in the outbox and delivers it in the background.
+// It implements EmailSender for other services.
+type EmailService struct {
+	repo        EmailOutboxRepositoryInterface
+	transport   EmailTransport
+	renderer    *email.Renderer
+	maxAttempts int
+}
+
+// NewEmailService creates a new email service
+func NewEmailService(repo EmailOutboxRepositoryInterface, transport EmailTransport, renderer *email.Renderer, maxAttempts int) *EmailService {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultEmailMaxAttempts
+	}
+	return &EmailService{
+		repo:        repo,
+		transport:   transport,
+		renderer:    renderer,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Send queues a plain-text email
+func (s *EmailService) Send(to, subject, body string) error {
+	return s.Enqueue(context.Background(), &email.Message{To: to, Subject: subject, TextBody: body}, "")
+}
+
+// SendTemplate renders a template in the given locale and queues the result
+func (s *EmailService) SendTemplate(ctx context.Context, to, locale, template string, data any) error {
+	msg, err := s.renderer.Render(template, locale, data)
+	if err != nil {
+		return err
+	}
+	msg.To = to
+	return s.Enqueue(ctx, msg, template)
+}
+
+// Enqueue stores a rendered message in the outbox
+func (s *EmailService) Enqueue(ctx context.Context, msg *email.Message, template string) error {
+	if msg.To == "" {
+		return errors.New("email recipient is required")
+	}
+
+	outbox := &model.OutboxEmail{
+		ToAddress: msg.To,
+		Subject:   msg.Subject,
+		TextBody:  msg.TextBody,
+	}
+	if msg.HTMLBody != "" {
+		outbox.HTMLBody = &msg.HTMLBody
+	}
+	if template != "" {
+		outbox.Template = &template
+	}
+
+	if err := s.repo.Create(ctx, outbox); err != nil {
+		return fmt.Errorf("queueing email: %w", err)
+	}
+	return nil
+}
+
+// ProcessOutbox delivers due emails. Failed deliveries are retried with
+// exponential backoff until maxAttempts is reached. It returns the number of
+// emails sent.
+func (s *EmailService) ProcessOutbox(ctx context.Context) (int, error) {
+	sent := 0
+	for {
+		batch, err := s.repo.ClaimDue(ctx, emailBatchSize, emailLease)
+		if err != nil {
+			return sent, fmt.Errorf("claiming due emails: %w", err)
+		}
+		if len(batch) == 0 {
+			return sent, nil
+		}
+
+		for _, e := range batch {
+			if ctx.Err() != nil {
+				// Unprocessed emails become due again when their lease expires
+				return sent, ctx.Err()
+			}
+			if s.deliver(ctx, &e) {
+				sent++
+			}
+		}
+
+		if len(batch) < emailBatchSize {
+			return sent, nil
+		}
+	}
+}
+
+// deliver sends one claimed email and records the outcome
+func (s *EmailService) deliver(ctx context.Context, e *model.OutboxEmail) bool {
+	msg := &email.Message{To: e.ToAddress, Subject: e.Subject, TextBody: e.TextBody}
+	if e.HTMLBody != nil {
+		msg.HTMLBody = *e.HTMLBody
+	}
+
+	err := s.transport.Deliver(ctx, msg)
+	if err == nil {
+		if err := s.repo.MarkSent(ctx, e.ID); err != nil {
+			slog.Error("Failed to mark email sent", slog.String("id", e.ID.String()), slog.String("error", err.Error()))
+		}
+		return true
+	}
+
+	if e.Attempts >= s.maxAttempts {
+		slog.Error("Giving up on email",
+			slog.String("id", e.ID.String()),
+			slog.Int("attempts", e.Attempts),
+			slog.String("error", err.Error()),
+		)
+		if err := s.repo.MarkFailed(ctx, e.ID, err.Error()); err != nil {
+			slog.Error("Failed to mark email failed", slog.String("id", e.ID.String()), slog.String("error", err.Error()))
+		}
+		return false
+	}
+
+	next := time.Now().Add(emailRetryBackoff(e.Attempts))
+	if err := s.repo.MarkRetry(ctx, e.ID, next, err.Error()); err != nil {
+		slog.Error("Failed to schedule email retry", slog.String("id", e.ID.String()), slog.String("error", err.Error()))
+	}
+	return false
+}
+
+// emailRetryBackoff returns the delay before the next attempt: 1m, 2m, 4m, ... capped at 2h
+func emailRetryBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := emailBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= emailMaxBackoff {
+			return emailMaxBackoff
+		}
+	}
+	return backoff
+}
+
+// emailLocaleForUser picks the language for a user's emails: the one in their
+// settings, or Vietnamese when none is set.
+func emailLocaleForUser(user *model.User) string {
+	return email.NormalizeLocale(user.Locale)
+}
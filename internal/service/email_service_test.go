@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/email"
+	"github.com/wealthpath/backend/internal/email/smtptest"
+	"github.com/wealthpath/backend/internal/model"
+)
+
+// memoryOutbox is an in-memory EmailOutboxRepositoryInterface
+type memoryOutbox struct {
+	mu     sync.Mutex
+	emails map[uuid.UUID]*model.OutboxEmail
+}
+
+func newMemoryOutbox() *memoryOutbox {
+	return &memoryOutbox{emails: make(map[uuid.UUID]*model.OutboxEmail)}
+}
+
+func (m *memoryOutbox) Create(ctx context.Context, e *model.OutboxEmail) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e.ID = uuid.New()
+	e.Status = model.EmailStatusPending
+	e.NextAttemptAt = time.Now()
+	e.CreatedAt = time.Now()
+	copied := *e
+	m.emails[e.ID] = &copied
+	return nil
+}
+
+func (m *memoryOutbox) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxEmail, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []model.OutboxEmail
+	for _, e := range m.emails {
+		if len(due) == limit {
+			break
+		}
+		if (e.Status == model.EmailStatusPending || e.Status == model.EmailStatusSending) && !e.NextAttemptAt.After(time.Now()) {
+			e.Status = model.EmailStatusSending
+			e.Attempts++
+			e.NextAttemptAt = time.Now().Add(lease)
+			due = append(due, *e)
+		}
+	}
+	return due, nil
+}
+
+func (m *memoryOutbox) MarkSent(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	m.emails[id].Status = model.EmailStatusSent
+	m.emails[id].SentAt = &now
+	return nil
+}
+
+func (m *memoryOutbox) MarkRetry(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time, lastError string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.emails[id].Status = model.EmailStatusPending
+	m.emails[id].NextAttemptAt = nextAttemptAt
+	m.emails[id].LastError = &lastError
+	return nil
+}
+
+func (m *memoryOutbox) MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.emails[id].Status = model.EmailStatusFailed
+	m.emails[id].LastError = &lastError
+	return nil
+}
+
+// makeDue makes every pending email due immediately, skipping the backoff
+func (m *memoryOutbox) makeDue() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range m.emails {
+		e.NextAttemptAt = time.Now().Add(-time.Second)
+	}
+}
+
+func (m *memoryOutbox) only(t *testing.T) model.OutboxEmail {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	require.Len(t, m.emails, 1)
+	for _, e := range m.emails {
+		return *e
+	}
+	return model.OutboxEmail{}
+}
+
+func newTestEmailService(t *testing.T, maxAttempts int) (*EmailService, *memoryOutbox, *smtptest.Server) {
+	t.Helper()
+
+	srv, err := smtptest.NewServer()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = srv.Close() })
+
+	renderer, err := email.NewRenderer("https://wealthpath.app")
+	require.NoError(t, err)
+
+	transport := email.NewSMTPTransport(email.SMTPConfig{
+		Host: srv.Host(),
+		Port: srv.Port(),
+		From: "notifications@wealthpath.app",
+	})
+
+	outbox := newMemoryOutbox()
+	return NewEmailService(outbox, transport, renderer, maxAttempts), outbox, srv
+}
+
+func TestEmailService_SendTemplate_DeliversFromOutbox(t *testing.T) {
+	t.Parallel()
+
+	svc, outbox, srv := newTestEmailService(t, 3)
+	ctx := context.Background()
+
+	err := svc.SendTemplate(ctx, "an@example.com", "vi", email.TemplateRateAlert, email.RateAlertData{
+		Name: "An", BankName: "Techcombank", TermMonths: 12, OldRate: "4.80", NewRate: "5.00", Change: "0.20", Increased: true,
+	})
+	require.NoError(t, err)
+
+	// Nothing is sent until the outbox is processed
+	assert.Empty(t, srv.Messages())
+	queued := outbox.only(t)
+	require.NotNil(t, queued.Template)
+	assert.Equal(t, email.TemplateRateAlert, *queued.Template)
+	assert.NotNil(t, queued.HTMLBody)
+
+	sent, err := svc.ProcessOutbox(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	msgs := srv.Messages()
+	require.Len(t, msgs, 1)
+	assert.Equal(t, []string{"an@example.com"}, msgs[0].To)
+	assert.Equal(t, model.EmailStatusSent, outbox.only(t).Status)
+
+	// Already sent emails are not delivered again
+	sent, err = svc.ProcessOutbox(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	assert.Len(t, srv.Messages(), 1)
+}
+
+func TestEmailService_ProcessOutbox_RetriesWithBackoff(t *testing.T) {
+	t.Parallel()
+
+	svc, outbox, srv := newTestEmailService(t, 3)
+	ctx := context.Background()
+
+	require.NoError(t, svc.Send("an@example.com", "Hello", "Plain body"))
+	srv.RejectNext(1)
+
+	sent, err := svc.ProcessOutbox(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	e := outbox.only(t)
+	assert.Equal(t, model.EmailStatusPending, e.Status)
+	assert.Equal(t, 1, e.Attempts)
+	require.NotNil(t, e.LastError)
+	assert.True(t, e.NextAttemptAt.After(time.Now().Add(30*time.Second)), "retry should be delayed")
+
+	// Not due yet
+	sent, err = svc.ProcessOutbox(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	outbox.makeDue()
+	sent, err = svc.ProcessOutbox(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, model.EmailStatusSent, outbox.only(t).Status)
+	assert.Len(t, srv.Messages(), 1)
+}
+
+func TestEmailService_ProcessOutbox_GivesUpAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	svc, outbox, srv := newTestEmailService(t, 2)
+	ctx := context.Background()
+
+	require.NoError(t, svc.Send("an@example.com", "Hello", "Plain body"))
+	srv.RejectNext(5)
+
+	_, err := svc.ProcessOutbox(ctx)
+	require.NoError(t, err)
+	outbox.makeDue()
+	_, err = svc.ProcessOutbox(ctx)
+	require.NoError(t, err)
+
+	e := outbox.only(t)
+	assert.Equal(t, model.EmailStatusFailed, e.Status)
+	assert.Equal(t, 2, e.Attempts)
+}
+
+func TestEmailRetryBackoff(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, time.Minute, emailRetryBackoff(0))
+	assert.Equal(t, time.Minute, emailRetryBackoff(1))
+	assert.Equal(t, 2*time.Minute, emailRetryBackoff(2))
+	assert.Equal(t, 16*time.Minute, emailRetryBackoff(5))
+	assert.Equal(t, 2*time.Hour, emailRetryBackoff(20))
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/pkg/currency"
+)
+
+// exchangeRatePlaces is the precision of stored and derived exchange rates
+const exchangeRatePlaces = 10
+
+// convertBatchSize is how many transactions a conversion run reads at a time
+const convertBatchSize = 500
+
+// exchangeRatePivots are tried in order to derive a rate between two currencies
+// from their rates against a third
+var exchangeRatePivots = []string{string(currency.VND), string(currency.USD)}
+
+var (
+	ErrNoExchangeRate      = errors.New("no exchange rate for this currency")
+	ErrInvalidExchangeRate = errors.New("exchange rate must be greater than zero")
+)
+
+// ExchangeRateService looks up exchange rates and converts transactions to their
+// owner's base currency at the rate of the transaction's date
+type ExchangeRateService struct {
+	repo repository.ExchangeRateRepository
+}
+
+// NewExchangeRateService creates a new ExchangeRateService
+func NewExchangeRateService(repo repository.ExchangeRateRepository) *ExchangeRateService {
+	return &ExchangeRateService{repo: repo}
+}
+
+// SaveRates stores the given rates, replacing any from the same source for the
+// same pair and day
+func (s *ExchangeRateService) SaveRates(ctx context.Context, rates []model.ExchangeRate) error {
+	for _, rate := range rates {
+		if !currency.IsValid(rate.FromCurrency) || !currency.IsValid(rate.ToCurrency) {
+			return fmt.Errorf("invalid currency pair %s/%s", rate.FromCurrency, rate.ToCurrency)
+		}
+		if !rate.Rate.IsPositive() {
+			return ErrInvalidExchangeRate
+		}
+	}
+	if err := s.repo.SaveRates(ctx, rates); err != nil {
+		return fmt.Errorf("saving exchange rates: %w", err)
+	}
+	return nil
+}
+
+// Rate returns how many units of to one unit of from buys on the date. Without
+// a stored rate for the pair, the inverse of the opposite rate is used, then a
+// cross rate through VND or USD. Returns ErrNoExchangeRate if none is known.
+// This makes the service a currency.RateSource.
+func (s *ExchangeRateService) Rate(ctx context.Context, from, to string, date time.Time) (decimal.Decimal, error) {
+	if from == to {
+		return decimal.NewFromInt(1), nil
+	}
+
+	rate, err := s.pairRate(ctx, from, to, date)
+	if !errors.Is(err, ErrNoExchangeRate) {
+		return rate, err
+	}
+
+	for _, pivot := range exchangeRatePivots {
+		if pivot == from || pivot == to {
+			continue
+		}
+		toPivot, err := s.pairRate(ctx, from, pivot, date)
+		if errors.Is(err, ErrNoExchangeRate) {
+			continue
+		}
+		if err != nil {
+			return decimal.Zero, err
+		}
+		fromPivot, err := s.pairRate(ctx, pivot, to, date)
+		if errors.Is(err, ErrNoExchangeRate) {
+			continue
+		}
+		if err != nil {
+			return decimal.Zero, err
+		}
+		return toPivot.Mul(fromPivot).Round(exchangeRatePlaces), nil
+	}
+	return decimal.Zero, ErrNoExchangeRate
+}
+
+// pairRate returns the stored rate from one currency to another, or the inverse
+// of the stored rate the other way
+func (s *ExchangeRateService) pairRate(ctx context.Context, from, to string, date time.Time) (decimal.Decimal, error) {
+	rate, err := s.repo.FindRate(ctx, from, to, date)
+	if err == nil {
+		return rate.Rate, nil
+	}
+	if !errors.Is(err, repository.ErrExchangeRateNotFound) {
+		return decimal.Zero, fmt.Errorf("finding %s/%s rate: %w", from, to, err)
+	}
+
+	inverse, err := s.repo.FindRate(ctx, to, from, date)
+	if errors.Is(err, repository.ErrExchangeRateNotFound) {
+		return decimal.Zero, ErrNoExchangeRate
+	}
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("finding %s/%s rate: %w", to, from, err)
+	}
+	return decimal.NewFromInt(1).DivRound(inverse.Rate, exchangeRatePlaces), nil
+}
+
+// Convert sets the base currency amount of a transaction about to be saved. The
+// rate is, in order: 1 for a transaction in the user's currency, the given rate,
+// the rate the transaction was converted at before, or the rate of its date.
+// When no rate is known the transaction is left unconverted for ConvertPending.
+func (s *ExchangeRateService) Convert(ctx context.Context, userID uuid.UUID, tx *model.Transaction, rate *decimal.Decimal) error {
+	if rate != nil && !rate.IsPositive() {
+		return ErrInvalidExchangeRate
+	}
+
+	base, err := s.userCurrency(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var r decimal.Decimal
+	switch {
+	case tx.Currency == base:
+		r = decimal.NewFromInt(1)
+	case rate != nil:
+		r = *rate
+	case tx.ExchangeRate != nil && tx.BaseCurrency != nil && *tx.BaseCurrency == base:
+		r = *tx.ExchangeRate
+	default:
+		r, err = s.Rate(ctx, tx.Currency, base, tx.Date)
+		if errors.Is(err, ErrNoExchangeRate) {
+			tx.BaseCurrency, tx.ExchangeRate, tx.BaseAmount = nil, nil, nil
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	baseAmount := convertAmount(tx.Amount, tx.Currency, base, r)
+	tx.BaseCurrency, tx.ExchangeRate, tx.BaseAmount = &base, &r, &baseAmount
+	return nil
+}
+
+// ConvertPending converts the transactions that have no base amount, or one in
+// a currency their owner no longer uses, and whose rate is now known. It
+// returns the number of transactions converted.
+func (s *ExchangeRateService) ConvertPending(ctx context.Context) (int, error) {
+	return s.convertPending(ctx, nil)
+}
+
+// ConvertUser converts the user's transactions that are not in their base
+// currency yet, e.g. after they changed it. It returns the number converted.
+func (s *ExchangeRateService) ConvertUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.convertPending(ctx, &userID)
+}
+
+func (s *ExchangeRateService) convertPending(ctx context.Context, userID *uuid.UUID) (int, error) {
+	type rateKey struct {
+		from, to string
+		date     time.Time
+	}
+	rates := make(map[rateKey]*decimal.Decimal)
+
+	converted := 0
+	after := uuid.Nil
+	for {
+		batch, err := s.repo.Unconverted(ctx, userID, after, convertBatchSize)
+		if err != nil {
+			return converted, fmt.Errorf("listing unconverted transactions: %w", err)
+		}
+
+		for _, tx := range batch {
+			key := rateKey{from: tx.Currency, to: tx.BaseCurrency, date: tx.Date}
+			rate, ok := rates[key]
+			if !ok {
+				r, err := s.Rate(ctx, tx.Currency, tx.BaseCurrency, tx.Date)
+				if err != nil && !errors.Is(err, ErrNoExchangeRate) {
+					return converted, err
+				}
+				if err == nil {
+					rate = &r
+				}
+				rates[key] = rate
+			}
+			if rate == nil {
+				continue
+			}
+
+			baseAmount := convertAmount(tx.Amount, tx.Currency, tx.BaseCurrency, *rate)
+			if err := s.repo.SetBaseAmount(ctx, tx, *rate, baseAmount); err != nil {
+				return converted, fmt.Errorf("converting transaction %s: %w", tx.ID, err)
+			}
+			converted++
+		}
+
+		if len(batch) < convertBatchSize {
+			return converted, nil
+		}
+		after = batch[len(batch)-1].ID
+	}
+}
+
+// userCurrency returns the user's base currency, USD when none is set
+func (s *ExchangeRateService) userCurrency(ctx context.Context, userID uuid.UUID) (string, error) {
+	base, err := s.repo.UserCurrency(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("getting currency of user %s: %w", userID, err)
+	}
+	if base == "" {
+		base = string(currency.DefaultCurrency)
+	}
+	return base, nil
+}
+
+// convertAmount converts an amount at the rate, rounded to the decimal places
+// of the target currency
+func convertAmount(amount decimal.Decimal, from, to string, rate decimal.Decimal) decimal.Decimal {
+	return currency.NewMoney(amount, currency.Currency(from)).Convert(currency.Currency(to), rate).Amount
+}
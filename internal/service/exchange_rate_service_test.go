@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+)
+
+// MockExchangeRateRepository implements repository.ExchangeRateRepository for testing
+type MockExchangeRateRepository struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateRepository) SaveRates(ctx context.Context, rates []model.ExchangeRate) error {
+	args := m.Called(ctx, rates)
+	return args.Error(0)
+}
+
+func (m *MockExchangeRateRepository) FindRate(ctx context.Context, from, to string, date time.Time) (*model.ExchangeRate, error) {
+	args := m.Called(ctx, from, to, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) UserCurrency(ctx context.Context, userID uuid.UUID) (string, error) {
+	args := m.Called(ctx, userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) Unconverted(ctx context.Context, userID *uuid.UUID, after uuid.UUID, limit int) ([]repository.UnconvertedTransaction, error) {
+	args := m.Called(ctx, userID, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.UnconvertedTransaction), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) SetBaseAmount(ctx context.Context, tx repository.UnconvertedTransaction, rate, baseAmount decimal.Decimal) error {
+	args := m.Called(ctx, tx, rate, baseAmount)
+	return args.Error(0)
+}
+
+// onRates has FindRate return the given rates, keyed "FROM/TO", and not found
+// for any other pair
+func onRates(repo *MockExchangeRateRepository, rates map[string]string) {
+	for pair, rate := range rates {
+		from, to, _ := strings.Cut(pair, "/")
+		repo.On("FindRate", mock.Anything, from, to, mock.Anything).
+			Return(&model.ExchangeRate{FromCurrency: from, ToCurrency: to, Rate: decimal.RequireFromString(rate)}, nil)
+	}
+	repo.On("FindRate", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, repository.ErrExchangeRateNotFound).Maybe()
+}
+
+func TestExchangeRateService_Rate(t *testing.T) {
+	t.Parallel()
+
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		from, to string
+		rates    map[string]string
+		want     string
+		wantErr  error
+	}{
+		{name: "same currency", from: "USD", to: "USD", want: "1"},
+		{name: "direct", from: "USD", to: "VND", rates: map[string]string{"USD/VND": "25000"}, want: "25000"},
+		{name: "inverse", from: "VND", to: "USD", rates: map[string]string{"USD/VND": "25000"}, want: "0.00004"},
+		{name: "cross through VND", from: "EUR", to: "JPY", rates: map[string]string{"EUR/VND": "27000", "JPY/VND": "200"}, want: "135"},
+		{name: "unknown", from: "EUR", to: "JPY", rates: map[string]string{}, wantErr: ErrNoExchangeRate},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockExchangeRateRepository)
+			onRates(repo, tt.rates)
+			svc := NewExchangeRateService(repo)
+
+			rate, err := svc.Rate(context.Background(), tt.from, tt.to, date)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rate.String())
+		})
+	}
+}
+
+func TestExchangeRateService_Convert(t *testing.T) {
+	t.Parallel()
+
+	explicit := decimal.NewFromInt(24000)
+	zero := decimal.Zero
+
+	tests := []struct {
+		name           string
+		txCurrency     string
+		rate           *decimal.Decimal
+		rates          map[string]string
+		wantBaseAmount string // Empty for an unconverted transaction
+		wantRate       string
+		wantErr        error
+	}{
+		{name: "same currency", txCurrency: "VND", wantBaseAmount: "123457", wantRate: "1"},
+		{name: "rate of the day, rounded to whole dong", txCurrency: "USD", rates: map[string]string{"USD/VND": "25432.5"}, wantBaseAmount: "3139813", wantRate: "25432.5"},
+		{name: "given rate", txCurrency: "USD", rate: &explicit, wantBaseAmount: "2962961", wantRate: "24000"},
+		{name: "no rate yet", txCurrency: "USD", rates: map[string]string{}},
+		{name: "invalid rate", txCurrency: "USD", rate: &zero, wantErr: ErrInvalidExchangeRate},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockExchangeRateRepository)
+			onRates(repo, tt.rates)
+			userID := uuid.New()
+			repo.On("UserCurrency", mock.Anything, userID).Return("VND", nil).Maybe()
+			svc := NewExchangeRateService(repo)
+
+			tx := &model.Transaction{
+				Amount:   decimal.RequireFromString("123.4567"),
+				Currency: tt.txCurrency,
+				Date:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			}
+			if tt.txCurrency == "VND" {
+				tx.Amount = decimal.RequireFromString("123456.78")
+			}
+
+			err := svc.Convert(context.Background(), userID, tx, tt.rate)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantBaseAmount == "" {
+				assert.Nil(t, tx.BaseAmount)
+				assert.Nil(t, tx.ExchangeRate)
+				return
+			}
+			require.NotNil(t, tx.BaseAmount)
+			assert.Equal(t, "VND", *tx.BaseCurrency)
+			assert.Equal(t, tt.wantBaseAmount, tx.BaseAmount.String())
+			assert.Equal(t, tt.wantRate, tx.ExchangeRate.String())
+		})
+	}
+}
+
+func TestExchangeRateService_ConvertPending(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockExchangeRateRepository)
+	onRates(repo, map[string]string{"USD/VND": "25000"})
+	svc := NewExchangeRateService(repo)
+
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	usd := repository.UnconvertedTransaction{ID: uuid.New(), Amount: decimal.NewFromInt(2), Currency: "USD", Date: date, BaseCurrency: "VND"}
+	eur := repository.UnconvertedTransaction{ID: uuid.New(), Amount: decimal.NewFromInt(3), Currency: "EUR", Date: date, BaseCurrency: "VND"}
+	same := repository.UnconvertedTransaction{ID: uuid.New(), Amount: decimal.NewFromInt(5000), Currency: "VND", Date: date, BaseCurrency: "VND"}
+
+	repo.On("Unconverted", mock.Anything, (*uuid.UUID)(nil), uuid.Nil, convertBatchSize).
+		Return([]repository.UnconvertedTransaction{usd, eur, same}, nil)
+	repo.On("SetBaseAmount", mock.Anything, usd, decimal.NewFromInt(25000), decimal.NewFromInt(50000)).Return(nil)
+	repo.On("SetBaseAmount", mock.Anything, same, decimal.NewFromInt(1), decimal.NewFromInt(5000)).Return(nil)
+
+	converted, err := svc.ConvertPending(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, converted)
+	repo.AssertExpectations(t)
+}
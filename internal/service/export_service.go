@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/csv"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -39,7 +40,7 @@ func (s *ExportService) ExportTransactionsCSV(ctx context.Context, userID uuid.U
 	writer := csv.NewWriter(&buf)
 
 	// Write header
-	header := []string{"Date", "Type", "Category", "Amount", "Currency", "Description"}
+	header := []string{"Date", "Type", "Category", "Amount", "Currency", "Description", "Tags"}
 	if err := writer.Write(header); err != nil {
 		return nil, fmt.Errorf("writing CSV header: %w", err)
 	}
@@ -53,6 +54,7 @@ func (s *ExportService) ExportTransactionsCSV(ctx context.Context, userID uuid.U
 			tx.Amount.String(),
 			tx.Currency,
 			tx.Description,
+			strings.Join(tx.Tags, ", "),
 		}
 		if err := writer.Write(row); err != nil {
 			return nil, fmt.Errorf("writing CSV row: %w", err)
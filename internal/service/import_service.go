@@ -0,0 +1,677 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/wealthpath/backend/internal/importer"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/pkg/currency"
+)
+
+const (
+	// MaxImportSize is the largest file accepted for import
+	MaxImportSize = 5 << 20
+	// MaxImportRows is the most rows a single import can have
+	MaxImportRows = 10000
+	// staleImportAge is how long an uploaded file waits to be committed before it is removed
+	staleImportAge = 24 * time.Hour
+	// importSampleRecords is how many raw records a preview shows
+	importSampleRecords = 5
+	// importDefaultCategory is used for rows without a category
+	importDefaultCategory = "Other"
+	// maxCategoryLength matches the transactions.category column
+	maxCategoryLength = 100
+)
+
+// maxImportAmount is the largest amount the transactions.amount column can hold
+var maxImportAmount = decimal.New(1, 13)
+
+var (
+	ErrImportTooLarge    = fmt.Errorf("import files must be at most %d MB", MaxImportSize>>20)
+	ErrImportTooManyRows = fmt.Errorf("import files can have at most %d rows", MaxImportRows)
+	ErrNothingToImport   = errors.New("there are no rows to import")
+)
+
+// ImportSettings describe how to read an uploaded file. The upload preview
+// fills them in from what it detects; the user can change them before
+// committing. Only Currency and AccountID apply to OFX, QIF and PDF
+// statements, which describe their own layout.
+type ImportSettings struct {
+	Delimiter    string                 `json:"delimiter"`
+	HasHeader    bool                   `json:"hasHeader"`
+	DateFormat   string                 `json:"dateFormat"`
+	NumberFormat string                 `json:"numberFormat"`
+	Mapping      importer.ColumnMapping `json:"mapping"`
+	// Currency is used for rows without a currency column. It defaults to the
+	// account's currency, or USD.
+	Currency string `json:"currency,omitempty"`
+	// AccountID records every imported transaction against one of the user's accounts
+	AccountID *uuid.UUID `json:"accountId,omitempty"`
+}
+
+// delimiter returns the delimiter as a rune, or false if it is not a single
+// character that can separate CSV fields
+func (s ImportSettings) delimiter() (rune, bool) {
+	if s.Delimiter == `\t` {
+		return '\t', true
+	}
+	r, size := utf8.DecodeRuneInString(s.Delimiter)
+	if size == 0 || size != len(s.Delimiter) || r == '"' || r == '\r' || r == '\n' || r == utf8.RuneError {
+		return 0, false
+	}
+	return r, true
+}
+
+// ImportRow is a row of the file together with whether it looks like a
+// transaction the user already has. AlreadyImported rows carry a bank
+// transaction ID that was imported before and are always skipped.
+type ImportRow struct {
+	importer.Row
+	Duplicate       bool `json:"duplicate,omitempty"`
+	AlreadyImported bool `json:"alreadyImported,omitempty"`
+}
+
+// ImportPreview shows how a file will be imported with the given settings
+type ImportPreview struct {
+	Batch    *model.ImportBatch `json:"batch"`
+	Settings ImportSettings     `json:"settings"`
+	Header   []string           `json:"header,omitempty"`
+	Sample   [][]string         `json:"sample"`
+	Rows     []ImportRow        `json:"rows"`
+
+	TotalRows           int `json:"totalRows"`
+	ValidRows           int `json:"validRows"`
+	InvalidRows         int `json:"invalidRows"`
+	DuplicateRows       int `json:"duplicateRows"`
+	AlreadyImportedRows int `json:"alreadyImportedRows"`
+}
+
+// CommitImportInput holds the final settings of an import. Rows flagged as
+// duplicates are skipped unless IncludeDuplicates is set, as are rows whose
+// line numbers are in SkipLines and rows that cannot be read.
+type CommitImportInput struct {
+	Settings          ImportSettings `json:"settings"`
+	IncludeDuplicates bool           `json:"includeDuplicates"`
+	SkipLines         []int          `json:"skipLines,omitempty"`
+}
+
+// ImportResult reports what a committed import did
+type ImportResult struct {
+	Batch                  *model.ImportBatch `json:"batch"`
+	Imported               int                `json:"imported"`
+	SkippedInvalid         int                `json:"skippedInvalid"`
+	SkippedDuplicates      int                `json:"skippedDuplicates"`
+	SkippedAlreadyImported int                `json:"skippedAlreadyImported"`
+	SkippedByUser          int                `json:"skippedByUser"`
+}
+
+// ImportService imports transactions from files. An upload is stored as a
+// pending batch and previewed; committing creates all its transactions at once
+// and the whole batch can be undone later.
+type ImportService struct {
+	repo     repository.ImportRepository
+	accounts TransactionAccountRepo
+	rules    TransactionRuleApplier
+}
+
+// NewImportService creates a new import service. Without an account
+// repository, imports cannot be recorded against an account.
+func NewImportService(repo repository.ImportRepository, accounts TransactionAccountRepo) *ImportService {
+	return &ImportService{repo: repo, accounts: accounts}
+}
+
+// SetRuleApplier sets the categorization rules run over transactions as an
+// import is committed
+func (s *ImportService) SetRuleApplier(rules TransactionRuleApplier) {
+	s.rules = rules
+}
+
+// UploadCSV stores a CSV file as a pending import and previews it with the
+// delimiter, header, column mapping, date format and number format detected
+// from its contents
+func (s *ImportService) UploadCSV(ctx context.Context, userID uuid.UUID, fileName string, body io.Reader) (*ImportPreview, error) {
+	text, encoding, err := readImportFile(body)
+	if err != nil {
+		return nil, err
+	}
+	settings, records, err := detectCSVSettings(text)
+	if err != nil {
+		return nil, err
+	}
+	rowCount := len(records)
+	if settings.HasHeader {
+		rowCount--
+	}
+	if rowCount > MaxImportRows {
+		return nil, ErrImportTooManyRows
+	}
+
+	return s.createBatch(ctx, userID, model.ImportSourceCSV, fileName, encoding, text, rowCount, settings)
+}
+
+// UploadStatement stores an OFX, QFX or QIF statement, or a bank's PDF
+// e-statement, as a pending import and previews it. Transactions already
+// imported from an earlier statement are flagged so that overlapping
+// statements can be imported safely.
+func (s *ImportService) UploadStatement(ctx context.Context, userID uuid.UUID, fileName string, body io.Reader) (*ImportPreview, error) {
+	data, err := readImportData(body)
+	if err != nil {
+		return nil, err
+	}
+	if importer.IsPDF(data) {
+		return s.uploadPDF(ctx, userID, fileName, data)
+	}
+
+	text, encoding := importer.Decode(data)
+	format, err := importer.DetectStatementFormat(text)
+	if err != nil {
+		return nil, err
+	}
+	statement, err := importer.ParseStatement(text, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(statement.Transactions) > MaxImportRows {
+		return nil, ErrImportTooManyRows
+	}
+
+	settings := ImportSettings{Currency: statement.Currency}
+	return s.createBatch(ctx, userID, format, fileName, encoding, text, len(statement.Transactions), settings)
+}
+
+// uploadPDF stores a PDF e-statement. The file is kept as base64 text until
+// the import is committed, and read again for every preview.
+func (s *ImportService) uploadPDF(ctx context.Context, userID uuid.UUID, fileName string, data []byte) (*ImportPreview, error) {
+	statement, err := importer.ParseBankPDF(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(statement.Transactions) > MaxImportRows {
+		return nil, ErrImportTooManyRows
+	}
+
+	content := base64.StdEncoding.EncodeToString(data)
+	settings := ImportSettings{Currency: statement.Currency}
+	return s.createBatch(ctx, userID, model.ImportSourcePDF, fileName, importer.EncodingBase64, content, len(statement.Transactions), settings)
+}
+
+// createBatch stores an upload as a pending import and previews it
+func (s *ImportService) createBatch(ctx context.Context, userID uuid.UUID, source, fileName, encoding, text string, rowCount int, settings ImportSettings) (*ImportPreview, error) {
+	batch := &model.ImportBatch{
+		UserID:   userID,
+		Source:   source,
+		FileName: sanitizeFileName(fileName),
+		Encoding: encoding,
+		Content:  &text,
+		RowCount: rowCount,
+	}
+	if err := s.repo.CreateBatch(ctx, batch); err != nil {
+		return nil, fmt.Errorf("creating import: %w", err)
+	}
+	return s.preview(ctx, batch, settings)
+}
+
+// Preview shows how a pending import will be read with the given settings
+func (s *ImportService) Preview(ctx context.Context, userID, id uuid.UUID, settings ImportSettings) (*ImportPreview, error) {
+	batch, err := s.pendingBatch(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.preview(ctx, batch, settings)
+}
+
+// Commit creates the transactions of a pending import in one go
+func (s *ImportService) Commit(ctx context.Context, userID, id uuid.UUID, input CommitImportInput) (*ImportResult, error) {
+	batch, err := s.pendingBatch(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	preview, err := s.preview(ctx, batch, input.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	skip := make(map[int]bool, len(input.SkipLines))
+	for _, line := range input.SkipLines {
+		skip[line] = true
+	}
+
+	result := &ImportResult{Batch: batch}
+	var transactions []model.Transaction
+	for _, row := range preview.Rows {
+		switch {
+		case row.Error != "":
+			result.SkippedInvalid++
+		case row.AlreadyImported:
+			result.SkippedAlreadyImported++
+		case skip[row.Line]:
+			result.SkippedByUser++
+		case row.Duplicate && !input.IncludeDuplicates:
+			result.SkippedDuplicates++
+		default:
+			tx := model.Transaction{
+				UserID:      userID,
+				Type:        row.Type,
+				Amount:      row.Amount,
+				Currency:    row.Currency,
+				Category:    row.Category,
+				Description: row.Description,
+				Date:        row.Date,
+				AccountID:   input.Settings.AccountID,
+			}
+			if row.ExternalID != "" {
+				tx.ExternalID = &row.ExternalID
+			}
+			transactions = append(transactions, tx)
+		}
+	}
+	if len(transactions) == 0 {
+		return nil, ErrNothingToImport
+	}
+	if s.rules != nil {
+		txs := make([]*model.Transaction, len(transactions))
+		for i := range transactions {
+			txs[i] = &transactions[i]
+		}
+		if err := s.rules.Apply(ctx, userID, txs); err != nil {
+			return nil, fmt.Errorf("applying rules: %w", err)
+		}
+	}
+
+	if err := s.repo.CommitBatch(ctx, batch, transactions); err != nil {
+		return nil, fmt.Errorf("committing import %s: %w", id, err)
+	}
+	result.Imported = len(transactions)
+	return result, nil
+}
+
+// List returns the user's imports, newest first
+func (s *ImportService) List(ctx context.Context, userID uuid.UUID) ([]model.ImportBatch, error) {
+	batches, err := s.repo.ListBatches(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing imports: %w", err)
+	}
+	return batches, nil
+}
+
+// Undo deletes every transaction a committed import created, including any
+// the user has edited since
+func (s *ImportService) Undo(ctx context.Context, userID, id uuid.UUID) (*model.ImportBatch, error) {
+	batch, err := s.repo.UndoBatch(ctx, userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("undoing import %s: %w", id, err)
+	}
+	return batch, nil
+}
+
+// PurgeStaleImports removes uploads that were never committed. It returns the
+// number of imports removed.
+func (s *ImportService) PurgeStaleImports(ctx context.Context) (int, error) {
+	n, err := s.repo.DeleteStalePending(ctx, time.Now().Add(-staleImportAge))
+	if err != nil {
+		return 0, fmt.Errorf("deleting stale imports: %w", err)
+	}
+	return n, nil
+}
+
+// readImportFile reads an upload up to the size limit and decodes it to UTF-8
+func readImportFile(body io.Reader) (string, string, error) {
+	data, err := readImportData(body)
+	if err != nil {
+		return "", "", err
+	}
+	text, encoding := importer.Decode(data)
+	return text, encoding, nil
+}
+
+// readImportData reads an upload up to the size limit
+func readImportData(body io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(body, MaxImportSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading import file: %w", err)
+	}
+	if len(data) > MaxImportSize {
+		return nil, ErrImportTooLarge
+	}
+	return data, nil
+}
+
+// pendingBatch loads an import that has not been committed
+func (s *ImportService) pendingBatch(ctx context.Context, userID, id uuid.UUID) (*model.ImportBatch, error) {
+	batch, err := s.repo.GetBatch(ctx, userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting import %s: %w", id, err)
+	}
+	if batch.Status != model.ImportStatusPending || batch.Content == nil {
+		return nil, repository.ErrImportNotPending
+	}
+	return batch, nil
+}
+
+// readBatch reads the rows of an uploaded file. CSV files are read with the
+// settings and also return their header and first records.
+func readBatch(batch *model.ImportBatch, settings ImportSettings) ([]importer.Row, []string, [][]string, error) {
+	switch batch.Source {
+	case model.ImportSourcePDF:
+		data, err := base64.StdEncoding.DecodeString(*batch.Content)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("decoding stored PDF: %w", err)
+		}
+		statement, err := importer.ParseBankPDF(data)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return statement.Rows(), nil, nil, nil
+	case model.ImportSourceOFX, model.ImportSourceQIF:
+		statement, err := importer.ParseStatement(*batch.Content, batch.Source)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return statement.Rows(), nil, nil, nil
+	}
+
+	delimiter, ok := settings.delimiter()
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("%w: the delimiter must be a single character", importer.ErrInvalidSettings)
+	}
+	records, err := importer.ReadCSV(*batch.Content, delimiter)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := validateCSVSettings(settings, records); err != nil {
+		return nil, nil, nil, err
+	}
+
+	var header []string
+	if settings.HasHeader {
+		header = records[0]
+	}
+	rows := importer.ApplyMapping(records, importer.Settings{
+		HasHeader:    settings.HasHeader,
+		DateFormat:   settings.DateFormat,
+		NumberFormat: settings.NumberFormat,
+		Mapping:      settings.Mapping,
+	})
+	return rows, header, records[:min(len(records), importSampleRecords)], nil
+}
+
+// preview reads the file with the settings and flags rows matching
+// transactions the user already has
+func (s *ImportService) preview(ctx context.Context, batch *model.ImportBatch, settings ImportSettings) (*ImportPreview, error) {
+	if settings.Currency != "" && !currency.IsValid(strings.ToUpper(settings.Currency)) {
+		return nil, fmt.Errorf("%w: unsupported currency %q", importer.ErrInvalidSettings, settings.Currency)
+	}
+	rows, header, sample, err := readBatch(batch, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultCurrency := strings.ToUpper(settings.Currency)
+	if settings.AccountID != nil {
+		account, err := s.importAccount(ctx, batch.UserID, *settings.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		if defaultCurrency == "" {
+			defaultCurrency = account.Currency
+		}
+		if defaultCurrency != account.Currency {
+			return nil, ErrAccountCurrencyMismatch
+		}
+	}
+	if defaultCurrency == "" {
+		defaultCurrency = string(currency.DefaultCurrency)
+	}
+
+	preview := &ImportPreview{
+		Batch:    batch,
+		Settings: settings,
+		Header:   header,
+		Sample:   sample,
+		Rows:     make([]ImportRow, len(rows)),
+	}
+	for i, row := range rows {
+		preview.Rows[i] = ImportRow{Row: finishImportRow(row, defaultCurrency, settings.AccountID != nil)}
+	}
+
+	if err := s.flagAlreadyImported(ctx, batch.UserID, preview.Rows); err != nil {
+		return nil, err
+	}
+	if err := s.flagDuplicates(ctx, batch.UserID, preview.Rows); err != nil {
+		return nil, err
+	}
+
+	preview.TotalRows = len(preview.Rows)
+	for _, row := range preview.Rows {
+		switch {
+		case row.Error != "":
+			preview.InvalidRows++
+		case row.AlreadyImported:
+			preview.AlreadyImportedRows++
+		case row.Duplicate:
+			preview.DuplicateRows++
+			preview.ValidRows++
+		default:
+			preview.ValidRows++
+		}
+	}
+	return preview, nil
+}
+
+// finishImportRow fills in the defaults of a row read from a file and checks it
+// can be stored. On an account, every row must be in the account's currency.
+func finishImportRow(row importer.Row, defaultCurrency string, onAccount bool) importer.Row {
+	if row.Error != "" {
+		return row
+	}
+	if row.Currency == "" {
+		row.Currency = defaultCurrency
+	}
+	if row.Category == "" {
+		row.Category = importDefaultCategory
+	}
+	if utf8.RuneCountInString(row.Category) > maxCategoryLength {
+		row.Category = string([]rune(row.Category)[:maxCategoryLength])
+	}
+
+	switch {
+	case !currency.IsValid(row.Currency):
+		row.Error = fmt.Sprintf("%q is not a supported currency", row.Currency)
+	case onAccount && row.Currency != defaultCurrency:
+		row.Error = ErrAccountCurrencyMismatch.Error()
+	case row.Amount.GreaterThanOrEqual(maxImportAmount):
+		row.Error = "the amount is too large"
+	}
+	return row
+}
+
+// flagAlreadyImported marks rows whose bank transaction ID the user already
+// has, or that repeat an earlier row of the same file
+func (s *ImportService) flagAlreadyImported(ctx context.Context, userID uuid.UUID, rows []ImportRow) error {
+	var ids []string
+	for _, row := range rows {
+		if row.ExternalID != "" {
+			ids = append(ids, row.ExternalID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	existing, err := s.repo.ExistingExternalIDs(ctx, userID, ids)
+	if err != nil {
+		return fmt.Errorf("checking for imported transactions: %w", err)
+	}
+	seen := make(map[string]bool, len(existing)+len(ids))
+	for _, id := range existing {
+		seen[id] = true
+	}
+	for i := range rows {
+		id := rows[i].ExternalID
+		if id == "" || rows[i].Error != "" {
+			continue
+		}
+		rows[i].AlreadyImported = seen[id]
+		seen[id] = true
+	}
+	return nil
+}
+
+// flagDuplicates marks rows with the same date, amount and description as one
+// of the user's existing transactions
+func (s *ImportService) flagDuplicates(ctx context.Context, userID uuid.UUID, rows []ImportRow) error {
+	var from, to time.Time
+	for _, row := range rows {
+		if row.Error != "" || row.AlreadyImported {
+			continue
+		}
+		if from.IsZero() || row.Date.Before(from) {
+			from = row.Date
+		}
+		if to.IsZero() || row.Date.After(to) {
+			to = row.Date
+		}
+	}
+	if from.IsZero() {
+		return nil
+	}
+
+	existing, err := s.repo.TransactionsBetween(ctx, userID, from, to)
+	if err != nil {
+		return fmt.Errorf("loading transactions to check for duplicates: %w", err)
+	}
+	seen := make(map[string]bool, len(existing))
+	for _, tx := range existing {
+		seen[duplicateKey(tx.Date, tx.Amount, tx.Description)] = true
+	}
+	for i := range rows {
+		if rows[i].Error == "" && !rows[i].AlreadyImported {
+			rows[i].Duplicate = seen[duplicateKey(rows[i].Date, rows[i].Amount, rows[i].Description)]
+		}
+	}
+	return nil
+}
+
+// duplicateKey identifies transactions that are probably the same one
+func duplicateKey(date time.Time, amount decimal.Decimal, description string) string {
+	return date.Format("2006-01-02") + "|" + amount.String() + "|" + strings.ToLower(strings.TrimSpace(description))
+}
+
+// importAccount returns one of the user's accounts that is not archived
+func (s *ImportService) importAccount(ctx context.Context, userID, id uuid.UUID) (*model.Account, error) {
+	if s.accounts == nil {
+		return nil, repository.ErrAccountNotFound
+	}
+	account, err := s.accounts.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting account %s: %w", id, err)
+	}
+	if account.Archived {
+		return nil, ErrAccountArchived
+	}
+	return account, nil
+}
+
+// validateCSVSettings checks the formats are known and the mapping fits the file
+func validateCSVSettings(settings ImportSettings, records [][]string) error {
+	if !importer.IsDateFormat(settings.DateFormat) {
+		return fmt.Errorf("%w: unknown date format %q", importer.ErrInvalidSettings, settings.DateFormat)
+	}
+	if !importer.IsNumberFormat(settings.NumberFormat) {
+		return fmt.Errorf("%w: unknown number format %q", importer.ErrInvalidSettings, settings.NumberFormat)
+	}
+
+	columns := 0
+	for _, rec := range records {
+		columns = max(columns, len(rec))
+	}
+	return settings.Mapping.Validate(columns)
+}
+
+// detectCSVSettings reads CSV text and guesses how to import it. Columns are
+// mapped by their headers first; without a recognised header, the first column
+// holding dates and the first other column holding amounts are used.
+func detectCSVSettings(text string) (ImportSettings, [][]string, error) {
+	delimiter := importer.DetectDelimiter(text)
+	records, err := importer.ReadCSV(text, delimiter)
+	if err != nil {
+		return ImportSettings{}, nil, err
+	}
+
+	settings := ImportSettings{
+		Delimiter:    string(delimiter),
+		HasHeader:    importer.LooksLikeHeader(records[0]),
+		DateFormat:   importer.DateFormatDMYSlash,
+		NumberFormat: importer.NumberFormatVI,
+	}
+	if delimiter == '\t' {
+		settings.Delimiter = `\t`
+	}
+
+	data := records
+	if settings.HasHeader {
+		settings.Mapping = importer.SuggestMapping(records[0])
+		data = records[1:]
+	}
+
+	columns := 0
+	for _, rec := range data {
+		columns = max(columns, len(rec))
+	}
+	if settings.Mapping.Date == nil {
+		for col := 0; col < columns; col++ {
+			if _, ok := importer.DetectDateFormat(importer.Column(data, col)); ok {
+				settings.Mapping.Date = &col
+				break
+			}
+		}
+	}
+	if settings.Mapping.Amount == nil {
+		for col := 0; col < columns; col++ {
+			if settings.Mapping.Date != nil && col == *settings.Mapping.Date {
+				continue
+			}
+			if isAmountColumn(importer.Column(data, col)) {
+				settings.Mapping.Amount = &col
+				break
+			}
+		}
+	}
+
+	if settings.Mapping.Date != nil {
+		if format, ok := importer.DetectDateFormat(importer.Column(data, *settings.Mapping.Date)); ok {
+			settings.DateFormat = format
+		}
+	}
+	if settings.Mapping.Amount != nil {
+		settings.NumberFormat = importer.DetectNumberFormat(importer.Column(data, *settings.Mapping.Amount))
+	}
+	return settings, records, nil
+}
+
+// isAmountColumn reports whether every non-empty value is an amount
+func isAmountColumn(values []string) bool {
+	format := importer.DetectNumberFormat(values)
+	found := false
+	for _, v := range values {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		if _, err := importer.ParseAmount(v, format); err != nil {
+			return false
+		}
+		found = true
+	}
+	return found
+}
@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/importer"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+)
+
+// MockImportRepository implements repository.ImportRepository for testing
+type MockImportRepository struct {
+	mock.Mock
+}
+
+func (m *MockImportRepository) CreateBatch(ctx context.Context, batch *model.ImportBatch) error {
+	args := m.Called(ctx, batch)
+	return args.Error(0)
+}
+
+func (m *MockImportRepository) GetBatch(ctx context.Context, userID, id uuid.UUID) (*model.ImportBatch, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImportBatch), args.Error(1)
+}
+
+func (m *MockImportRepository) ListBatches(ctx context.Context, userID uuid.UUID) ([]model.ImportBatch, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ImportBatch), args.Error(1)
+}
+
+func (m *MockImportRepository) CommitBatch(ctx context.Context, batch *model.ImportBatch, transactions []model.Transaction) error {
+	args := m.Called(ctx, batch, transactions)
+	return args.Error(0)
+}
+
+func (m *MockImportRepository) UndoBatch(ctx context.Context, userID, id uuid.UUID) (*model.ImportBatch, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImportBatch), args.Error(1)
+}
+
+func (m *MockImportRepository) DeleteStalePending(ctx context.Context, olderThan time.Time) (int, error) {
+	args := m.Called(ctx, olderThan)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockImportRepository) ExistingExternalIDs(ctx context.Context, userID uuid.UUID, ids []string) ([]string, error) {
+	args := m.Called(ctx, userID, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockImportRepository) TransactionsBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]model.Transaction, error) {
+	args := m.Called(ctx, userID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Transaction), args.Error(1)
+}
+
+func (m *MockImportRepository) TransfersBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]model.Transfer, error) {
+	args := m.Called(ctx, userID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Transfer), args.Error(1)
+}
+
+func (m *MockImportRepository) CommitMigration(ctx context.Context, batch *model.ImportBatch, migration *repository.Migration) error {
+	args := m.Called(ctx, batch, migration)
+	return args.Error(0)
+}
+
+// vietnameseCSV is a bank export with a header, day-first dates and amounts
+// grouped with dots
+const vietnameseCSV = "Ngày;Số tiền;Nội dung;Danh mục\n" +
+	"05/03/2024;-150.000;Cà phê;Food & Dining\n" +
+	"06/03/2024;25.000.000;Lương tháng 3;Salary\n" +
+	"07/03/2024;abc;Lỗi;\n"
+
+func importDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestImportService_UploadCSV(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockImportRepository)
+	svc := NewImportService(repo, nil)
+	userID := uuid.New()
+
+	repo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(b *model.ImportBatch) bool {
+		return b.UserID == userID && b.Source == "csv" && b.FileName == "vcb.csv" &&
+			b.Encoding == importer.EncodingUTF8 && b.RowCount == 3 && b.Content != nil
+	})).Return(nil)
+	repo.On("TransactionsBetween", mock.Anything, userID, importDate(2024, 3, 5), importDate(2024, 3, 6)).Return([]model.Transaction{
+		{Date: importDate(2024, 3, 5), Amount: decimal.RequireFromString("150000.00"), Description: " cà phê "},
+	}, nil)
+
+	preview, err := svc.UploadCSV(context.Background(), userID, "../vcb.csv", strings.NewReader(vietnameseCSV))
+	require.NoError(t, err)
+
+	assert.Equal(t, ";", preview.Settings.Delimiter)
+	assert.True(t, preview.Settings.HasHeader)
+	assert.Equal(t, importer.DateFormatDMYSlash, preview.Settings.DateFormat)
+	assert.Equal(t, importer.NumberFormatVI, preview.Settings.NumberFormat)
+	assert.Equal(t, []string{"Ngày", "Số tiền", "Nội dung", "Danh mục"}, preview.Header)
+
+	require.Len(t, preview.Rows, 3)
+	assert.True(t, preview.Rows[0].Duplicate)
+	assert.Equal(t, model.TransactionTypeExpense, preview.Rows[0].Type)
+	assert.Equal(t, "USD", preview.Rows[0].Currency)
+	assert.False(t, preview.Rows[1].Duplicate)
+	assert.True(t, decimal.NewFromInt(25000000).Equal(preview.Rows[1].Amount))
+	assert.NotEmpty(t, preview.Rows[2].Error)
+
+	assert.Equal(t, 3, preview.TotalRows)
+	assert.Equal(t, 2, preview.ValidRows)
+	assert.Equal(t, 1, preview.InvalidRows)
+	assert.Equal(t, 1, preview.DuplicateRows)
+	repo.AssertExpectations(t)
+}
+
+func TestImportService_UploadCSV_WithoutHeader(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockImportRepository)
+	svc := NewImportService(repo, nil)
+
+	repo.On("CreateBatch", mock.Anything, mock.Anything).Return(nil)
+	repo.On("TransactionsBetween", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.Transaction{}, nil)
+
+	csv := "Coffee,03/15/2024,-4.50\nRefund,03/28/2024,12.00\n"
+	preview, err := svc.UploadCSV(context.Background(), uuid.New(), "export.csv", strings.NewReader(csv))
+	require.NoError(t, err)
+
+	assert.False(t, preview.Settings.HasHeader)
+	assert.Equal(t, importer.DateFormatMDYSlash, preview.Settings.DateFormat)
+	assert.Equal(t, importer.NumberFormatEN, preview.Settings.NumberFormat)
+	require.NotNil(t, preview.Settings.Mapping.Date)
+	require.NotNil(t, preview.Settings.Mapping.Amount)
+	assert.Equal(t, 1, *preview.Settings.Mapping.Date)
+	assert.Equal(t, 2, *preview.Settings.Mapping.Amount)
+	assert.Equal(t, 2, preview.ValidRows)
+}
+
+func TestImportService_UploadCSV_TooLarge(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockImportRepository)
+	svc := NewImportService(repo, nil)
+
+	_, err := svc.UploadCSV(context.Background(), uuid.New(), "big.csv", strings.NewReader(strings.Repeat("x", MaxImportSize+1)))
+	assert.ErrorIs(t, err, ErrImportTooLarge)
+	repo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+}
+
+func TestImportService_Commit(t *testing.T) {
+	t.Parallel()
+
+	col := func(i int) *int { return &i }
+	settings := ImportSettings{
+		Delimiter:    ";",
+		HasHeader:    true,
+		DateFormat:   importer.DateFormatDMYSlash,
+		NumberFormat: importer.NumberFormatVI,
+		Currency:     "vnd",
+		Mapping:      importer.ColumnMapping{Date: col(0), Amount: col(1), Description: col(2), Category: col(3)},
+	}
+	existing := []model.Transaction{{Date: importDate(2024, 3, 5), Amount: decimal.NewFromInt(150000), Description: "Cà phê"}}
+
+	tests := []struct {
+		name         string
+		status       string
+		input        CommitImportInput
+		wantImported []string
+		wantErr      error
+		wantResult   ImportResult
+	}{
+		{
+			name:         "skips duplicates and invalid rows",
+			status:       model.ImportStatusPending,
+			input:        CommitImportInput{Settings: settings},
+			wantImported: []string{"Lương tháng 3"},
+			wantResult:   ImportResult{Imported: 1, SkippedInvalid: 1, SkippedDuplicates: 1},
+		},
+		{
+			name:         "includes duplicates when asked",
+			status:       model.ImportStatusPending,
+			input:        CommitImportInput{Settings: settings, IncludeDuplicates: true},
+			wantImported: []string{"Cà phê", "Lương tháng 3"},
+			wantResult:   ImportResult{Imported: 2, SkippedInvalid: 1},
+		},
+		{
+			name:    "nothing left to import",
+			status:  model.ImportStatusPending,
+			input:   CommitImportInput{Settings: settings, SkipLines: []int{3}},
+			wantErr: ErrNothingToImport,
+		},
+		{
+			name:    "already committed",
+			status:  model.ImportStatusCommitted,
+			input:   CommitImportInput{Settings: settings},
+			wantErr: repository.ErrImportNotPending,
+		},
+		{
+			name:    "unknown date format",
+			status:  model.ImportStatusPending,
+			input:   CommitImportInput{Settings: ImportSettings{Delimiter: ";", DateFormat: "DD MMM", NumberFormat: importer.NumberFormatVI, Mapping: settings.Mapping}},
+			wantErr: importer.ErrInvalidSettings,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockImportRepository)
+			svc := NewImportService(repo, nil)
+			userID, id := uuid.New(), uuid.New()
+			content := vietnameseCSV
+			batch := &model.ImportBatch{ID: id, UserID: userID, Source: model.ImportSourceCSV, Status: tt.status, Content: &content}
+			if tt.status != model.ImportStatusPending {
+				batch.Content = nil
+			}
+
+			repo.On("GetBatch", mock.Anything, userID, id).Return(batch, nil)
+			repo.On("TransactionsBetween", mock.Anything, userID, mock.Anything, mock.Anything).Return(existing, nil).Maybe()
+			repo.On("CommitBatch", mock.Anything, batch, mock.Anything).Return(nil).Maybe()
+
+			result, err := svc.Commit(context.Background(), userID, id, tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "CommitBatch", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantResult.Imported, result.Imported)
+			assert.Equal(t, tt.wantResult.SkippedInvalid, result.SkippedInvalid)
+			assert.Equal(t, tt.wantResult.SkippedDuplicates, result.SkippedDuplicates)
+
+			txns := repo.Calls[len(repo.Calls)-1].Arguments.Get(2).([]model.Transaction)
+			var descriptions []string
+			for _, tx := range txns {
+				descriptions = append(descriptions, tx.Description)
+				assert.Equal(t, userID, tx.UserID)
+				assert.Equal(t, "VND", tx.Currency)
+			}
+			assert.Equal(t, tt.wantImported, descriptions)
+		})
+	}
+}
+
+func TestImportService_Commit_AccountCurrency(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockImportRepository)
+	accounts := new(MockAccountRepository)
+	svc := NewImportService(repo, accounts)
+	userID, id, accountID := uuid.New(), uuid.New(), uuid.New()
+
+	content := "date,amount,currency,description\n2024-03-05,-20,USD,Lunch\n2024-03-06,-100000,VND,Taxi\n"
+	repo.On("GetBatch", mock.Anything, userID, id).Return(&model.ImportBatch{ID: id, UserID: userID, Source: model.ImportSourceCSV, Status: model.ImportStatusPending, Content: &content}, nil)
+	repo.On("TransactionsBetween", mock.Anything, userID, mock.Anything, mock.Anything).Return([]model.Transaction{}, nil)
+	repo.On("CommitBatch", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	accounts.On("GetByID", mock.Anything, userID, accountID).Return(&model.Account{ID: accountID, Currency: "VND"}, nil)
+
+	col := func(i int) *int { return &i }
+	result, err := svc.Commit(context.Background(), userID, id, CommitImportInput{Settings: ImportSettings{
+		Delimiter:    ",",
+		HasHeader:    true,
+		DateFormat:   importer.DateFormatISO,
+		NumberFormat: importer.NumberFormatEN,
+		AccountID:    &accountID,
+		Mapping:      importer.ColumnMapping{Date: col(0), Amount: col(1), Currency: col(2), Description: col(3)},
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, 1, result.SkippedInvalid)
+	txns := repo.Calls[len(repo.Calls)-1].Arguments.Get(2).([]model.Transaction)
+	require.Len(t, txns, 1)
+	assert.Equal(t, "Taxi", txns[0].Description)
+	assert.Equal(t, &accountID, txns[0].AccountID)
+}
+
+func TestImportService_Undo(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockImportRepository)
+	svc := NewImportService(repo, nil)
+	userID, id := uuid.New(), uuid.New()
+
+	repo.On("UndoBatch", mock.Anything, userID, id).Return(nil, repository.ErrImportNotCommitted)
+
+	_, err := svc.Undo(context.Background(), userID, id)
+	assert.ErrorIs(t, err, repository.ErrImportNotCommitted)
+}
+
+const sampleOFX = `<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>VND
+<BANKACCTFROM><ACCTID>0071000123456</BANKACCTFROM>
+<BANKTRANLIST>
+<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20240301<TRNAMT>-50000<FITID>A1<NAME>Grab</STMTTRN>
+<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20240302<TRNAMT>-75000<FITID>A2<NAME>Circle K</STMTTRN>
+<STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20240303<TRNAMT>1000000<FITID>A3<NAME>Hoàn tiền</STMTTRN>
+</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>`
+
+func TestImportService_UploadStatement(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockImportRepository)
+	svc := NewImportService(repo, nil)
+	userID := uuid.New()
+
+	repo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(b *model.ImportBatch) bool {
+		return b.Source == model.ImportSourceOFX && b.RowCount == 3
+	})).Return(nil)
+	repo.On("ExistingExternalIDs", mock.Anything, userID, []string{"ofx:0071000123456:A1", "ofx:0071000123456:A2", "ofx:0071000123456:A3"}).
+		Return([]string{"ofx:0071000123456:A1"}, nil)
+	repo.On("TransactionsBetween", mock.Anything, userID, importDate(2024, 3, 2), importDate(2024, 3, 3)).Return([]model.Transaction{}, nil)
+
+	preview, err := svc.UploadStatement(context.Background(), userID, "statement.ofx", strings.NewReader(sampleOFX))
+	require.NoError(t, err)
+
+	assert.Equal(t, "VND", preview.Settings.Currency)
+	require.Len(t, preview.Rows, 3)
+	assert.True(t, preview.Rows[0].AlreadyImported)
+	assert.False(t, preview.Rows[1].AlreadyImported)
+	assert.Equal(t, "VND", preview.Rows[1].Currency)
+	assert.Equal(t, "Other", preview.Rows[1].Category)
+	assert.Equal(t, 1, preview.AlreadyImportedRows)
+	assert.Equal(t, 2, preview.ValidRows)
+	repo.AssertExpectations(t)
+}
+
+func TestImportService_UploadStatement_UnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockImportRepository)
+	svc := NewImportService(repo, nil)
+
+	_, err := svc.UploadStatement(context.Background(), uuid.New(), "export.csv", strings.NewReader("date,amount\n2024-03-01,5\n"))
+	assert.ErrorIs(t, err, importer.ErrUnknownStatementFormat)
+}
+
+func TestImportService_Commit_Statement(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockImportRepository)
+	svc := NewImportService(repo, nil)
+	userID, id := uuid.New(), uuid.New()
+	content := sampleOFX
+
+	batch := &model.ImportBatch{ID: id, UserID: userID, Source: model.ImportSourceOFX, Status: model.ImportStatusPending, Content: &content}
+	repo.On("GetBatch", mock.Anything, userID, id).Return(batch, nil)
+	repo.On("ExistingExternalIDs", mock.Anything, userID, mock.Anything).Return([]string{"ofx:0071000123456:A1"}, nil)
+	repo.On("TransactionsBetween", mock.Anything, userID, mock.Anything, mock.Anything).Return([]model.Transaction{}, nil)
+	repo.On("CommitBatch", mock.Anything, batch, mock.Anything).Return(nil)
+
+	// Settings meant for CSV files do not matter for statements
+	result, err := svc.Commit(context.Background(), userID, id, CommitImportInput{
+		Settings:          ImportSettings{Delimiter: "", DateFormat: "bogus"},
+		IncludeDuplicates: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Imported)
+	assert.Equal(t, 1, result.SkippedAlreadyImported)
+	txns := repo.Calls[len(repo.Calls)-1].Arguments.Get(2).([]model.Transaction)
+	require.Len(t, txns, 2)
+	require.NotNil(t, txns[0].ExternalID)
+	assert.Equal(t, "ofx:0071000123456:A2", *txns[0].ExternalID)
+	assert.Equal(t, model.TransactionTypeIncome, txns[1].Type)
+}
+
+func TestImportService_UploadStatement_UnreadablePDF(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockImportRepository)
+	svc := NewImportService(repo, nil)
+
+	_, err := svc.UploadStatement(context.Background(), uuid.New(), "saoke.pdf", strings.NewReader("%PDF-1.4\nnot a statement"))
+	assert.ErrorIs(t, err, importer.ErrMalformedFile)
+	repo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+}
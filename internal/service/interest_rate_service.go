@@ -16,6 +16,12 @@ import (
 type InterestRateService struct {
 	repo         repository.InterestRateRepository
 	orchestrator *scraper.Orchestrator
+	notifier     RateChangeNotifier
+}
+
+// RateChangeNotifier detects rate changes and alerts subscribers
+type RateChangeNotifier interface {
+	CheckAndNotify(ctx context.Context) (int, error)
 }
 
 // NewInterestRateService creates a new interest rate service
@@ -26,6 +32,11 @@ func NewInterestRateService(repo repository.InterestRateRepository) *InterestRat
 	}
 }
 
+// SetRateChangeNotifier sets the notifier that runs after every successful scrape
+func (s *InterestRateService) SetRateChangeNotifier(notifier RateChangeNotifier) {
+	s.notifier = notifier
+}
+
 // ScrapeAndUpdateRates scrapes rates from all banks and updates the database
 func (s *InterestRateService) ScrapeAndUpdateRates(ctx context.Context) (int, error) {
 	results, err := s.orchestrator.ScrapeAll(ctx)
@@ -49,6 +60,16 @@ func (s *InterestRateService) ScrapeAndUpdateRates(ctx context.Context) (int, er
 		return 0, fmt.Errorf("upsert rates: %w", err)
 	}
 
+	// Alert subscribers straight away; a failure here must not fail the scrape
+	if s.notifier != nil {
+		sent, err := s.notifier.CheckAndNotify(ctx)
+		if err != nil {
+			slog.Error("Rate change notification failed", slog.String("error", err.Error()))
+		} else if sent > 0 {
+			slog.Info("Rate change alerts sent", slog.Int("subscriptions", sent))
+		}
+	}
+
 	return len(allRates), nil
 }
 
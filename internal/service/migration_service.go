@@ -0,0 +1,710 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/wealthpath/backend/internal/importer"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/pkg/currency"
+)
+
+// Outcomes of the records of a migration
+const (
+	MigrationImported = "imported"
+	MigrationMerged   = "merged" // Matched something the user already has
+	MigrationSkipped  = "skipped"
+)
+
+// What the items of a migration report are
+const (
+	MigrationItemAccount     = "account"
+	MigrationItemCategory    = "category"
+	MigrationItemTransaction = "transaction"
+	MigrationItemTransfer    = "transfer"
+	MigrationItemDebt        = "debt"
+	MigrationItemDebtPayment = "debtPayment"
+	MigrationItemBudget      = "budget"
+)
+
+// migrationDebtName names debts whose lender the export does not say
+const migrationDebtName = "Borrowed money"
+
+const (
+	// maxAccountNameLength matches the accounts.name column
+	maxAccountNameLength = 100
+	// maxDebtNameLength matches the debts.name column
+	maxDebtNameLength = 255
+)
+
+// MigrationAccountLister lists the user's accounts (e.g. AccountService)
+type MigrationAccountLister interface {
+	List(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]model.AccountWithBalance, error)
+}
+
+// MigrationCategoryLister lists the user's categories (e.g. CategoryService,
+// which creates the default categories first)
+type MigrationCategoryLister interface {
+	List(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]model.Category, error)
+}
+
+// MigrationDebtLister lists the user's debts (e.g. DebtService)
+type MigrationDebtLister interface {
+	List(ctx context.Context, userID uuid.UUID) ([]model.Debt, error)
+}
+
+// MigrationBudgetLister lists the user's budgets (e.g. BudgetService)
+type MigrationBudgetLister interface {
+	List(ctx context.Context, userID uuid.UUID) ([]model.Budget, error)
+}
+
+// MigrationInput describes an export to migrate
+type MigrationInput struct {
+	App      string // importer.AppMoneyLover or importer.AppMISA
+	FileName string
+	Currency string // For records that do not say; defaults to VND
+	DryRun   bool   // Report what would happen without saving anything
+}
+
+// MigrationItem is an entry of a migration report: an account, category, debt
+// or budget, or a record of the export that was skipped or merged
+type MigrationItem struct {
+	Line    int    `json:"line,omitempty"` // Row in the export, for records
+	Kind    string `json:"kind"`
+	Name    string `json:"name,omitempty"`
+	Outcome string `json:"outcome"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// MigrationSummary counts the outcomes for one kind of item
+type MigrationSummary struct {
+	Imported int `json:"imported"`
+	Merged   int `json:"merged"`
+	Skipped  int `json:"skipped"`
+}
+
+// MigrationReport is what a migration imported, merged and skipped.
+// Imported transactions, transfers and debt payments are only counted; every
+// other outcome is listed in Items.
+type MigrationReport struct {
+	App      string                      `json:"app"`
+	FileName string                      `json:"fileName"`
+	DryRun   bool                        `json:"dryRun"`
+	Batch    *model.ImportBatch          `json:"batch,omitempty"` // Nil for dry runs and when nothing was imported
+	Summary  map[string]MigrationSummary `json:"summary"`
+	Items    []MigrationItem             `json:"items"`
+}
+
+// count adds an outcome to the summary
+func (r *MigrationReport) count(kind, outcome string) {
+	s := r.Summary[kind]
+	switch outcome {
+	case MigrationImported:
+		s.Imported++
+	case MigrationMerged:
+		s.Merged++
+	default:
+		s.Skipped++
+	}
+	r.Summary[kind] = s
+}
+
+// add lists an item and counts it
+func (r *MigrationReport) add(item MigrationItem) {
+	r.Items = append(r.Items, item)
+	r.count(item.Kind, item.Outcome)
+}
+
+// MigrationService moves a user's data from other budgeting apps. Unlike
+// file imports, a migration is committed straight away; it can be undone like
+// any other import.
+type MigrationService struct {
+	repo       repository.ImportRepository
+	accounts   MigrationAccountLister
+	categories MigrationCategoryLister
+	debts      MigrationDebtLister
+	budgets    MigrationBudgetLister
+	rules      TransactionRuleApplier
+}
+
+// NewMigrationService creates a new migration service
+func NewMigrationService(repo repository.ImportRepository, accounts MigrationAccountLister, categories MigrationCategoryLister,
+	debts MigrationDebtLister, budgets MigrationBudgetLister) *MigrationService {
+	return &MigrationService{repo: repo, accounts: accounts, categories: categories, debts: debts, budgets: budgets}
+}
+
+// SetRuleApplier sets the categorization rules run over the migrated
+// transactions as a migration is committed, as they are for imports
+func (s *MigrationService) SetRuleApplier(rules TransactionRuleApplier) {
+	s.rules = rules
+}
+
+// Import migrates a Money Lover or MISA MoneyKeeper export. Wallets become
+// accounts and categories are matched by name, so both are merged with the
+// user's own; debts and budgets the user already has are left alone, and
+// transactions imported before are skipped, so the same export can be
+// imported again safely.
+func (s *MigrationService) Import(ctx context.Context, userID uuid.UUID, input MigrationInput, body io.Reader) (*MigrationReport, error) {
+	curr := strings.ToUpper(strings.TrimSpace(input.Currency))
+	if curr == "" {
+		curr = string(currency.VND)
+	}
+	if !currency.IsValid(curr) {
+		return nil, fmt.Errorf("%w: unsupported currency %q", importer.ErrInvalidSettings, input.Currency)
+	}
+
+	data, err := readImportData(body)
+	if err != nil {
+		return nil, err
+	}
+	export, err := importer.ParseMigration(input.App, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(export.Records)+len(export.Budgets) > MaxImportRows {
+		return nil, ErrImportTooManyRows
+	}
+
+	plan, err := s.newPlan(ctx, userID, curr, export)
+	if err != nil {
+		return nil, err
+	}
+	plan.report.FileName = sanitizeFileName(input.FileName)
+	plan.report.DryRun = input.DryRun
+	plan.addRecords(export.Records)
+	plan.addBudgets(export.Budgets)
+
+	m := &plan.migration
+	if input.DryRun || len(m.Transactions)+len(m.Transfers)+len(m.Debts)+len(m.Budgets) == 0 {
+		return plan.report, nil
+	}
+
+	batch := &model.ImportBatch{
+		UserID:        userID,
+		Source:        export.App,
+		FileName:      plan.report.FileName,
+		RowCount:      len(export.Records) + len(export.Budgets),
+		ImportedCount: plan.imported,
+	}
+	if s.rules != nil {
+		txs := make([]*model.Transaction, len(m.Transactions))
+		for i := range m.Transactions {
+			txs[i] = &m.Transactions[i]
+		}
+		if err := s.rules.Apply(ctx, userID, txs); err != nil {
+			return nil, fmt.Errorf("applying rules: %w", err)
+		}
+	}
+	if err := s.repo.CommitMigration(ctx, batch, m); err != nil {
+		return nil, fmt.Errorf("committing migration: %w", err)
+	}
+	plan.report.Batch = batch
+	return plan.report, nil
+}
+
+// migrationAccount is an account records are matched to
+type migrationAccount struct {
+	id       uuid.UUID
+	currency string
+	reported bool // Whether the report lists it yet
+}
+
+// migrationCategory is a category records are matched to
+type migrationCategory struct {
+	id       uuid.UUID
+	topLevel bool
+	reported bool
+}
+
+// migrationDebt collects the records of one debt
+type migrationDebt struct {
+	name     string
+	currency string
+	records  []importer.MigrationRecord
+}
+
+// migrationPlan works out what a migration creates
+type migrationPlan struct {
+	userID    uuid.UUID
+	currency  string
+	report    *MigrationReport
+	migration repository.Migration
+	imported  int // Records and budgets imported
+
+	accounts        map[string]*migrationAccount  // By lower-cased name
+	categories      map[string]*migrationCategory // By type and lower-cased name
+	debts           []*migrationDebt
+	debtsByKey      map[string]*migrationDebt
+	existingDebts   map[string]bool // Lower-cased names
+	existingBudgets map[string]bool // Lower-cased category and period
+	importedIDs     map[string]bool // External IDs of transactions imported before
+	transfers       map[string]int  // Existing transfers by transferKey
+}
+
+// newPlan loads what the user already has that records may match
+func (s *MigrationService) newPlan(ctx context.Context, userID uuid.UUID, curr string, export *importer.MigrationExport) (*migrationPlan, error) {
+	p := &migrationPlan{
+		userID:          userID,
+		currency:        curr,
+		report:          &MigrationReport{App: export.App, Summary: map[string]MigrationSummary{}, Items: []MigrationItem{}},
+		accounts:        map[string]*migrationAccount{},
+		categories:      map[string]*migrationCategory{},
+		debtsByKey:      map[string]*migrationDebt{},
+		existingDebts:   map[string]bool{},
+		existingBudgets: map[string]bool{},
+		importedIDs:     map[string]bool{},
+		transfers:       map[string]int{},
+	}
+
+	accounts, err := s.accounts.List(ctx, userID, true)
+	if err != nil {
+		return nil, fmt.Errorf("listing accounts: %w", err)
+	}
+	for _, a := range accounts {
+		key := strings.ToLower(a.Name)
+		if _, ok := p.accounts[key]; !ok {
+			p.accounts[key] = &migrationAccount{id: a.ID, currency: a.Currency}
+		}
+	}
+
+	categories, err := s.categories.List(ctx, userID, true)
+	if err != nil {
+		return nil, fmt.Errorf("listing categories: %w", err)
+	}
+	for _, c := range categories {
+		p.categories[categoryKey(c.Type, c.Name)] = &migrationCategory{id: c.ID, topLevel: c.ParentID == nil}
+	}
+
+	debts, err := s.debts.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing debts: %w", err)
+	}
+	for _, d := range debts {
+		p.existingDebts[strings.ToLower(d.Name)] = true
+	}
+
+	budgets, err := s.budgets.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing budgets: %w", err)
+	}
+	for _, b := range budgets {
+		p.existingBudgets[budgetKey(b.Category, b.Period)] = true
+	}
+
+	var ids []string
+	var from, to time.Time
+	for _, r := range export.Records {
+		switch {
+		case r.Error != "":
+		case r.Kind == importer.KindTransfer:
+			if from.IsZero() || r.Date.Before(from) {
+				from = r.Date
+			}
+			if r.Date.After(to) {
+				to = r.Date
+			}
+		case r.Kind != importer.KindBorrow && r.Kind != importer.KindRepay && r.Kind != importer.KindAdjustment:
+			ids = append(ids, r.ID)
+		}
+	}
+	if len(ids) > 0 {
+		existing, err := s.repo.ExistingExternalIDs(ctx, userID, ids)
+		if err != nil {
+			return nil, fmt.Errorf("checking for imported transactions: %w", err)
+		}
+		for _, id := range existing {
+			p.importedIDs[id] = true
+		}
+	}
+	if !from.IsZero() {
+		transfers, err := s.repo.TransfersBetween(ctx, userID, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("checking for imported transfers: %w", err)
+		}
+		for _, t := range transfers {
+			p.transfers[transferKey(t.FromAccountID, t.ToAccountID, t.Date, t.Amount)]++
+		}
+	}
+	return p, nil
+}
+
+func categoryKey(t model.TransactionType, name string) string {
+	return string(t) + "\x00" + strings.ToLower(name)
+}
+
+func budgetKey(category, period string) string {
+	return strings.ToLower(category) + "\x00" + period
+}
+
+func transferKey(from, to uuid.UUID, date time.Time, amount decimal.Decimal) string {
+	return from.String() + to.String() + date.Format("2006-01-02") + amount.StringFixed(2)
+}
+
+// skip reports a record that is not imported
+func (p *migrationPlan) skip(r importer.MigrationRecord, kind, reason string) {
+	p.report.add(MigrationItem{Line: r.Line, Kind: kind, Name: r.Note, Outcome: MigrationSkipped, Reason: reason})
+}
+
+// recordCurrency returns the currency of a record, or the default when the
+// record does not say. It returns false for currencies WealthPath does not support.
+func (p *migrationPlan) recordCurrency(code string) (string, bool) {
+	if code == "" {
+		return p.currency, true
+	}
+	// Vietnamese exports write the dong as VNĐ
+	code = strings.ReplaceAll(code, "Đ", "D")
+	return code, currency.IsValid(code)
+}
+
+// addRecords works out what each record of the export becomes
+func (p *migrationPlan) addRecords(records []importer.MigrationRecord) {
+	for _, r := range records {
+		kind := MigrationItemTransaction
+		switch r.Kind {
+		case importer.KindTransfer:
+			kind = MigrationItemTransfer
+		case importer.KindBorrow:
+			kind = MigrationItemDebt
+		case importer.KindRepay:
+			kind = MigrationItemDebtPayment
+		}
+
+		curr, ok := p.recordCurrency(r.Currency)
+		switch {
+		case r.Error != "":
+			p.skip(r, kind, r.Error)
+			continue
+		case !ok:
+			p.skip(r, kind, fmt.Sprintf("unsupported currency %q", r.Currency))
+			continue
+		case r.Amount.GreaterThanOrEqual(maxImportAmount):
+			p.skip(r, kind, "the amount is too large")
+			continue
+		case len([]rune(r.Wallet)) > maxAccountNameLength || len([]rune(r.ToWallet)) > maxAccountNameLength:
+			p.skip(r, kind, "the wallet name is too long")
+			continue
+		}
+		r.Currency = curr
+
+		switch r.Kind {
+		case importer.KindExpense, importer.KindLend:
+			p.addTransaction(r, model.TransactionTypeExpense)
+		case importer.KindIncome, importer.KindCollect:
+			p.addTransaction(r, model.TransactionTypeIncome)
+		case importer.KindTransfer:
+			p.addTransfer(r)
+		case importer.KindBorrow, importer.KindRepay:
+			p.collectDebt(r)
+		default:
+			p.skip(r, kind, "balance adjustments are not imported; set the account's opening balance instead")
+		}
+	}
+	p.addDebts()
+}
+
+// addTransaction imports an income or expense record
+func (p *migrationPlan) addTransaction(r importer.MigrationRecord, txType model.TransactionType) {
+	if p.importedIDs[r.ID] {
+		p.skip(r, MigrationItemTransaction, "already imported")
+		return
+	}
+	category := r.Category
+	if category == "" {
+		category = importDefaultCategory
+	}
+	if len([]rune(category)) > maxCategoryLength || len([]rune(r.ParentCategory)) > maxCategoryLength {
+		p.skip(r, MigrationItemTransaction, "the category name is too long")
+		return
+	}
+	if reason := p.currencyMismatch(r.Wallet, r.Currency); reason != "" {
+		p.skip(r, MigrationItemTransaction, reason)
+		return
+	}
+	p.category(txType, category, r.ParentCategory, r.Line)
+
+	externalID := r.ID
+	description := r.Note
+	if r.Counterparty != "" && r.Kind != importer.KindExpense && r.Kind != importer.KindIncome {
+		description = strings.TrimSpace(r.Counterparty + " " + r.Note)
+	}
+	p.migration.Transactions = append(p.migration.Transactions, model.Transaction{
+		UserID:      p.userID,
+		Type:        txType,
+		Amount:      r.Amount,
+		Currency:    r.Currency,
+		Category:    category,
+		Description: description,
+		Date:        r.Date,
+		AccountID:   p.account(r.Wallet, r.Currency, r.Line),
+		ExternalID:  &externalID,
+	})
+	p.report.count(MigrationItemTransaction, MigrationImported)
+	p.imported++
+}
+
+// addTransfer imports a transfer between two wallets in the currency of the record
+func (p *migrationPlan) addTransfer(r importer.MigrationRecord) {
+	if r.Wallet == "" || strings.EqualFold(r.Wallet, r.ToWallet) {
+		p.skip(r, MigrationItemTransfer, "a transfer needs two different wallets")
+		return
+	}
+	for _, wallet := range []string{r.Wallet, r.ToWallet} {
+		if reason := p.currencyMismatch(wallet, r.Currency); reason != "" {
+			p.skip(r, MigrationItemTransfer, reason)
+			return
+		}
+	}
+	from, to := p.accounts[strings.ToLower(r.Wallet)], p.accounts[strings.ToLower(r.ToWallet)]
+	if from != nil && to != nil {
+		key := transferKey(from.id, to.id, r.Date, r.Amount)
+		if p.transfers[key] > 0 {
+			p.transfers[key]--
+			p.skip(r, MigrationItemTransfer, "already imported")
+			return
+		}
+	}
+
+	p.migration.Transfers = append(p.migration.Transfers, model.Transfer{
+		UserID:        p.userID,
+		FromAccountID: *p.account(r.Wallet, r.Currency, r.Line),
+		ToAccountID:   *p.account(r.ToWallet, r.Currency, r.Line),
+		Amount:        r.Amount,
+		Description:   r.Note,
+		Date:          r.Date,
+	})
+	p.report.count(MigrationItemTransfer, MigrationImported)
+	p.imported++
+}
+
+// collectDebt adds a borrow or repay record to the debt it belongs to. Debts
+// are told apart by who the money was borrowed from and the currency.
+func (p *migrationPlan) collectDebt(r importer.MigrationRecord) {
+	name := r.Counterparty
+	if name == "" {
+		name = migrationDebtName
+	}
+	if runes := []rune(name); len(runes) > maxDebtNameLength {
+		name = string(runes[:maxDebtNameLength])
+	}
+	key := strings.ToLower(name) + "\x00" + r.Currency
+	debt, ok := p.debtsByKey[key]
+	if !ok {
+		debt = &migrationDebt{name: name, currency: r.Currency}
+		p.debtsByKey[key] = debt
+		p.debts = append(p.debts, debt)
+	}
+	debt.records = append(debt.records, r)
+}
+
+// addDebts creates a personal loan for each lender. The original amount is
+// everything borrowed and the balance what is left after the repayments, each
+// of which is recorded as a payment.
+func (p *migrationPlan) addDebts() {
+	for _, d := range p.debts {
+		if p.existingDebts[strings.ToLower(d.name)] {
+			p.report.add(MigrationItem{Kind: MigrationItemDebt, Name: d.name, Outcome: MigrationMerged,
+				Reason: "a debt with this name already exists; its records were not imported"})
+			for _, r := range d.records {
+				kind := MigrationItemDebt
+				if r.Kind == importer.KindRepay {
+					kind = MigrationItemDebtPayment
+				}
+				p.skip(r, kind, "the debt already exists")
+			}
+			continue
+		}
+
+		debt := model.Debt{
+			ID:       uuid.New(),
+			UserID:   p.userID,
+			Name:     d.name,
+			Type:     model.DebtTypePersonalLoan,
+			Currency: d.currency,
+		}
+		borrowed, repaid := decimal.Zero, decimal.Zero
+		for _, r := range d.records {
+			if debt.StartDate.IsZero() || r.Date.Before(debt.StartDate) {
+				debt.StartDate = r.Date
+			}
+			if r.Kind == importer.KindBorrow {
+				borrowed = borrowed.Add(r.Amount)
+				continue
+			}
+			repaid = repaid.Add(r.Amount)
+			p.migration.DebtPayments = append(p.migration.DebtPayments, model.DebtPayment{
+				DebtID:    debt.ID,
+				Amount:    r.Amount,
+				Principal: r.Amount,
+				Interest:  decimal.Zero,
+				Date:      r.Date,
+			})
+			p.report.count(MigrationItemDebtPayment, MigrationImported)
+		}
+		// Money borrowed before the export began was still repaid
+		debt.OriginalAmount = decimal.Max(borrowed, repaid)
+		debt.CurrentBalance = decimal.Max(borrowed.Sub(repaid), decimal.Zero)
+		debt.DueDay = debt.StartDate.Day()
+
+		p.migration.Debts = append(p.migration.Debts, debt)
+		p.existingDebts[strings.ToLower(d.name)] = true
+		p.imported += len(d.records)
+
+		reason := fmt.Sprintf("%d records", len(d.records))
+		if len(d.records) == 1 {
+			reason = "1 record"
+		}
+		p.report.add(MigrationItem{Kind: MigrationItemDebt, Name: d.name, Outcome: MigrationImported, Reason: reason})
+	}
+}
+
+// addBudgets imports budgets for categories that do not have one for the same period yet
+func (p *migrationPlan) addBudgets(budgets []importer.MigrationBudget) {
+	for _, b := range budgets {
+		item := MigrationItem{Line: b.Line, Kind: MigrationItemBudget, Name: b.Category, Outcome: MigrationSkipped}
+		curr, ok := p.recordCurrency(b.Currency)
+		switch {
+		case b.Error != "":
+			item.Reason = b.Error
+		case !ok:
+			item.Reason = fmt.Sprintf("unsupported currency %q", b.Currency)
+		case b.Amount.GreaterThanOrEqual(maxImportAmount):
+			item.Reason = "the amount is too large"
+		case len([]rune(b.Category)) > maxCategoryLength:
+			item.Reason = "the category name is too long"
+		case p.existingBudgets[budgetKey(b.Category, b.Period)]:
+			item.Outcome = MigrationMerged
+			item.Reason = fmt.Sprintf("there is already a %s budget for this category", b.Period)
+		default:
+			p.category(model.TransactionTypeExpense, b.Category, "", b.Line)
+			// Both apps export the dates of the current period; the budget
+			// repeats from the start of it
+			start := time.Date(time.Now().Year(), time.Now().Month(), 1, 0, 0, 0, 0, time.UTC)
+			if b.Start != nil {
+				start = *b.Start
+			}
+			p.migration.Budgets = append(p.migration.Budgets, model.Budget{
+				UserID:    p.userID,
+				Category:  b.Category,
+				Amount:    b.Amount,
+				Currency:  curr,
+				Period:    b.Period,
+				StartDate: start,
+			})
+			p.existingBudgets[budgetKey(b.Category, b.Period)] = true
+			p.imported++
+			item.Outcome = MigrationImported
+		}
+		p.report.add(item)
+	}
+}
+
+// currencyMismatch returns why a record in curr cannot be recorded against the
+// account a wallet is matched to, or "" when the account is in curr or the
+// wallet has none yet
+func (p *migrationPlan) currencyMismatch(wallet, curr string) string {
+	if wallet == "" {
+		return ""
+	}
+	if a, ok := p.accounts[strings.ToLower(wallet)]; ok && a.currency != curr {
+		return fmt.Sprintf("%s is a %s account and the record is in %s", wallet, a.currency, curr)
+	}
+	return ""
+}
+
+// account returns the account a wallet is matched to, creating one the first
+// time a wallet without an account is seen. Records without a wallet are not
+// recorded against an account. Callers check the account's currency with
+// currencyMismatch first.
+func (p *migrationPlan) account(wallet, curr string, line int) *uuid.UUID {
+	if wallet == "" {
+		return nil
+	}
+	key := strings.ToLower(wallet)
+	a, ok := p.accounts[key]
+	if !ok {
+		account := model.Account{
+			ID:       uuid.New(),
+			UserID:   p.userID,
+			Name:     wallet,
+			Type:     migrationAccountType(wallet),
+			Currency: curr,
+		}
+		p.migration.Accounts = append(p.migration.Accounts, account)
+		a = &migrationAccount{id: account.ID, currency: curr, reported: true}
+		p.accounts[key] = a
+		p.report.add(MigrationItem{Line: line, Kind: MigrationItemAccount, Name: wallet, Outcome: MigrationImported,
+			Reason: fmt.Sprintf("new %s account", strings.ReplaceAll(string(account.Type), "_", " "))})
+	}
+	if !a.reported {
+		a.reported = true
+		p.report.add(MigrationItem{Line: line, Kind: MigrationItemAccount, Name: wallet, Outcome: MigrationMerged,
+			Reason: "matches an existing account"})
+	}
+	id := a.id
+	return &id
+}
+
+// category matches a category by name, creating it, and its parent, when the
+// user does not have one
+func (p *migrationPlan) category(t model.TransactionType, name, parent string, line int) {
+	key := categoryKey(t, name)
+	if c, ok := p.categories[key]; ok {
+		if !c.reported {
+			c.reported = true
+			p.report.add(MigrationItem{Line: line, Kind: MigrationItemCategory, Name: name, Outcome: MigrationMerged,
+				Reason: "matches an existing category"})
+		}
+		return
+	}
+
+	// Subcategories can only be one level deep
+	var parentID *uuid.UUID
+	if parent != "" && !strings.EqualFold(parent, name) {
+		p.category(t, parent, "", line)
+		if pc := p.categories[categoryKey(t, parent)]; pc.topLevel {
+			id := pc.id
+			parentID = &id
+		}
+	}
+	category := model.Category{ID: uuid.New(), UserID: p.userID, ParentID: parentID, Name: name, Type: t}
+	p.migration.Categories = append(p.migration.Categories, category)
+	p.categories[key] = &migrationCategory{id: category.ID, topLevel: parentID == nil, reported: true}
+
+	item := MigrationItem{Line: line, Kind: MigrationItemCategory, Name: name, Outcome: MigrationImported}
+	if parentID != nil {
+		item.Reason = "subcategory of " + parent
+	}
+	p.report.add(item)
+}
+
+// migrationAccountType guesses the type of an account from the name of a wallet
+func migrationAccountType(wallet string) model.AccountType {
+	name := " " + strings.ToLower(wallet) + " "
+	has := func(words ...string) bool {
+		for _, w := range words {
+			if strings.Contains(name, w) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case has("credit", "tín dụng", "tin dung", "visa", "mastercard", " jcb "):
+		return model.AccountTypeCreditCard
+	case has("momo", "zalopay", "zalo pay", "shopeepay", "vnpay", "viettel money", "e-wallet", "ví điện tử", "vi dien tu", "paypal"):
+		return model.AccountTypeEWallet
+	case has("bank", "ngân hàng", "ngan hang", "tài khoản", "tai khoan", "vietcombank", " vcb ", "techcombank", " tcb ",
+		" mb ", "mbbank", " acb ", "bidv", "vietinbank", "agribank", "tpbank", "vpbank", "sacombank", "hdbank",
+		" vib ", " ocb ", " shb ", " msb ", "timo", " cake "):
+		return model.AccountTypeBank
+	default:
+		return model.AccountTypeCash
+	}
+}
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/importer"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+)
+
+// MockMigrationAccounts implements MigrationAccountLister for testing
+type MockMigrationAccounts struct {
+	mock.Mock
+}
+
+func (m *MockMigrationAccounts) List(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]model.AccountWithBalance, error) {
+	args := m.Called(ctx, userID, includeArchived)
+	return args.Get(0).([]model.AccountWithBalance), args.Error(1)
+}
+
+// MockMigrationCategories implements MigrationCategoryLister for testing
+type MockMigrationCategories struct {
+	mock.Mock
+}
+
+func (m *MockMigrationCategories) List(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]model.Category, error) {
+	args := m.Called(ctx, userID, includeArchived)
+	return args.Get(0).([]model.Category), args.Error(1)
+}
+
+// MockMigrationDebts implements MigrationDebtLister for testing
+type MockMigrationDebts struct {
+	mock.Mock
+}
+
+func (m *MockMigrationDebts) List(ctx context.Context, userID uuid.UUID) ([]model.Debt, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]model.Debt), args.Error(1)
+}
+
+// MockMigrationBudgets implements MigrationBudgetLister for testing
+type MockMigrationBudgets struct {
+	mock.Mock
+}
+
+func (m *MockMigrationBudgets) List(ctx context.Context, userID uuid.UUID) ([]model.Budget, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]model.Budget), args.Error(1)
+}
+
+// moneyLoverExport is a Money Lover export with a transaction imported
+// before, a transfer, two debts and a balance adjustment
+const moneyLoverExport = "Id,Date,Category,Amount,Currency,Note,Wallet,With\n" +
+	"1,01/03/2024,Food & Beverage,\"-50,000\",VND,Pho bo,Tiền mặt,\n" +
+	"2,02/03/2024,Salary,\"15,000,000\",VND,Luong,vietcombank,\n" +
+	"3,03/03/2024,Outgoing Transfer,\"-2,000,000\",VND,Rut tien,Vietcombank,\n" +
+	"4,03/03/2024,Incoming Transfer,\"2,000,000\",VND,Rut tien,Tiền mặt,\n" +
+	"5,05/03/2024,Debt,\"5,000,000\",VND,,Vietcombank,Anh Tuan\n" +
+	"6,20/03/2024,Repayment,\"-1,000,000\",VND,,Vietcombank,Anh Tuan\n" +
+	"7,21/03/2024,Debt,\"300,000\",VND,,Tiền mặt,Chị Hoa\n" +
+	"8,22/03/2024,Adjustment,\"120,000\",VND,,Tiền mặt,\n" +
+	"9,23/03/2024,Quà cưới,\"-500,000\",VND,,Tiền mặt,\n"
+
+type migrationMocks struct {
+	repo       *MockImportRepository
+	accounts   *MockMigrationAccounts
+	categories *MockMigrationCategories
+	debts      *MockMigrationDebts
+	budgets    *MockMigrationBudgets
+}
+
+func newMigrationService() (*MigrationService, migrationMocks) {
+	m := migrationMocks{
+		repo:       new(MockImportRepository),
+		accounts:   new(MockMigrationAccounts),
+		categories: new(MockMigrationCategories),
+		debts:      new(MockMigrationDebts),
+		budgets:    new(MockMigrationBudgets),
+	}
+	return NewMigrationService(m.repo, m.accounts, m.categories, m.debts, m.budgets), m
+}
+
+func TestMigrationService_Import(t *testing.T) {
+	t.Parallel()
+
+	svc, m := newMigrationService()
+	userID, bankID, foodID := uuid.New(), uuid.New(), uuid.New()
+
+	m.accounts.On("List", mock.Anything, userID, true).Return([]model.AccountWithBalance{
+		{Account: model.Account{ID: bankID, Name: "Vietcombank", Type: model.AccountTypeBank, Currency: "VND"}},
+	}, nil)
+	m.categories.On("List", mock.Anything, userID, true).Return([]model.Category{
+		{ID: foodID, Name: "Food & Dining", Type: model.TransactionTypeExpense},
+		{ID: uuid.New(), Name: "Salary", Type: model.TransactionTypeIncome},
+	}, nil)
+	m.debts.On("List", mock.Anything, userID).Return([]model.Debt{{Name: "chị hoa"}}, nil)
+	m.budgets.On("List", mock.Anything, userID).Return([]model.Budget{}, nil)
+	m.repo.On("ExistingExternalIDs", mock.Anything, userID, []string{"moneylover:1", "moneylover:2", "moneylover:9"}).
+		Return([]string{"moneylover:2"}, nil)
+	m.repo.On("TransfersBetween", mock.Anything, userID, importDate(2024, 3, 3), importDate(2024, 3, 3)).
+		Return([]model.Transfer{}, nil)
+
+	var migration *repository.Migration
+	m.repo.On("CommitMigration", mock.Anything, mock.MatchedBy(func(b *model.ImportBatch) bool {
+		return b.UserID == userID && b.Source == importer.AppMoneyLover && b.FileName == "moneylover.csv" &&
+			b.RowCount == 8 && b.ImportedCount == 5
+	}), mock.Anything).Run(func(args mock.Arguments) {
+		migration = args.Get(2).(*repository.Migration)
+	}).Return(nil)
+
+	report, err := svc.Import(context.Background(), userID,
+		MigrationInput{App: importer.AppMoneyLover, FileName: "moneylover.csv"}, strings.NewReader(moneyLoverExport))
+	require.NoError(t, err)
+	require.NotNil(t, migration)
+
+	// The cash wallet is new; Vietcombank matches the existing account
+	require.Len(t, migration.Accounts, 1)
+	cash := migration.Accounts[0]
+	assert.Equal(t, "Tiền mặt", cash.Name)
+	assert.Equal(t, model.AccountTypeCash, cash.Type)
+	assert.Equal(t, "VND", cash.Currency)
+
+	require.Len(t, migration.Categories, 1)
+	assert.Equal(t, "Quà cưới", migration.Categories[0].Name)
+
+	require.Len(t, migration.Transactions, 2)
+	food := migration.Transactions[0]
+	assert.Equal(t, "Food & Dining", food.Category)
+	assert.Equal(t, &cash.ID, food.AccountID)
+	assert.Equal(t, "moneylover:1", *food.ExternalID)
+	assert.True(t, decimal.NewFromInt(50000).Equal(food.Amount))
+	assert.Equal(t, "Quà cưới", migration.Transactions[1].Category)
+
+	require.Len(t, migration.Transfers, 1)
+	assert.Equal(t, bankID, migration.Transfers[0].FromAccountID)
+	assert.Equal(t, cash.ID, migration.Transfers[0].ToAccountID)
+
+	// Chị Hoa's debt already exists, so only Anh Tuan's is created
+	require.Len(t, migration.Debts, 1)
+	debt := migration.Debts[0]
+	assert.Equal(t, "Anh Tuan", debt.Name)
+	assert.Equal(t, model.DebtTypePersonalLoan, debt.Type)
+	assert.True(t, decimal.NewFromInt(5000000).Equal(debt.OriginalAmount))
+	assert.True(t, decimal.NewFromInt(4000000).Equal(debt.CurrentBalance))
+	assert.Equal(t, importDate(2024, 3, 5), debt.StartDate)
+	require.Len(t, migration.DebtPayments, 1)
+	assert.Equal(t, debt.ID, migration.DebtPayments[0].DebtID)
+
+	assert.Equal(t, MigrationSummary{Imported: 2, Skipped: 2}, report.Summary[MigrationItemTransaction])
+	assert.Equal(t, MigrationSummary{Imported: 1}, report.Summary[MigrationItemTransfer])
+	assert.Equal(t, MigrationSummary{Imported: 1, Merged: 1, Skipped: 1}, report.Summary[MigrationItemDebt])
+	assert.Equal(t, MigrationSummary{Imported: 1}, report.Summary[MigrationItemDebtPayment])
+	assert.Equal(t, MigrationSummary{Imported: 1, Merged: 1}, report.Summary[MigrationItemAccount])
+	assert.Equal(t, MigrationSummary{Imported: 1, Merged: 1}, report.Summary[MigrationItemCategory])
+	assert.Contains(t, report.Items, MigrationItem{Line: 3, Kind: MigrationItemTransaction, Name: "Luong",
+		Outcome: MigrationSkipped, Reason: "already imported"})
+	assert.NotNil(t, report.Batch)
+	m.repo.AssertExpectations(t)
+}
+
+func TestMigrationService_Import_DryRun(t *testing.T) {
+	t.Parallel()
+
+	svc, m := newMigrationService()
+	userID := uuid.New()
+
+	m.accounts.On("List", mock.Anything, userID, true).Return([]model.AccountWithBalance{}, nil)
+	m.categories.On("List", mock.Anything, userID, true).Return([]model.Category{}, nil)
+	m.debts.On("List", mock.Anything, userID).Return([]model.Debt{}, nil)
+	m.budgets.On("List", mock.Anything, userID).Return([]model.Budget{}, nil)
+	m.repo.On("ExistingExternalIDs", mock.Anything, userID, mock.Anything).Return([]string{}, nil)
+	m.repo.On("TransfersBetween", mock.Anything, userID, mock.Anything, mock.Anything).Return([]model.Transfer{}, nil)
+
+	report, err := svc.Import(context.Background(), userID,
+		MigrationInput{App: importer.AppMoneyLover, FileName: "moneylover.csv", DryRun: true}, strings.NewReader(moneyLoverExport))
+	require.NoError(t, err)
+
+	assert.True(t, report.DryRun)
+	assert.Nil(t, report.Batch)
+	assert.Equal(t, MigrationSummary{Imported: 2}, report.Summary[MigrationItemAccount])
+	assert.Equal(t, MigrationSummary{Imported: 3, Skipped: 1}, report.Summary[MigrationItemTransaction])
+	assert.Equal(t, MigrationSummary{Imported: 3}, report.Summary[MigrationItemCategory])
+	m.repo.AssertNotCalled(t, "CommitMigration", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMigrationService_Import_Rules(t *testing.T) {
+	t.Parallel()
+
+	svc, m := newMigrationService()
+	userID := uuid.New()
+	ruleRepo := new(MockRuleRepository)
+	ruleRepo.On("List", mock.Anything, userID).Return([]model.CategorizationRule{
+		{Name: "Pho", Enabled: true, DescriptionContains: "pho", SetCategory: "Food & Dining", AddTags: []string{"pho"}},
+	}, nil)
+	svc.SetRuleApplier(NewRuleService(ruleRepo, nil, nil))
+
+	m.accounts.On("List", mock.Anything, userID, true).Return([]model.AccountWithBalance{}, nil)
+	m.categories.On("List", mock.Anything, userID, true).Return([]model.Category{}, nil)
+	m.debts.On("List", mock.Anything, userID).Return([]model.Debt{}, nil)
+	m.budgets.On("List", mock.Anything, userID).Return([]model.Budget{}, nil)
+	m.repo.On("ExistingExternalIDs", mock.Anything, userID, mock.Anything).Return([]string{}, nil)
+	m.repo.On("TransfersBetween", mock.Anything, userID, mock.Anything, mock.Anything).Return([]model.Transfer{}, nil)
+
+	var migration *repository.Migration
+	m.repo.On("CommitMigration", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		migration = args.Get(2).(*repository.Migration)
+	}).Return(nil)
+
+	_, err := svc.Import(context.Background(), userID,
+		MigrationInput{App: importer.AppMoneyLover, FileName: "moneylover.csv"}, strings.NewReader(moneyLoverExport))
+	require.NoError(t, err)
+	require.NotNil(t, migration)
+
+	require.NotEmpty(t, migration.Transactions)
+	pho := migration.Transactions[0]
+	assert.Equal(t, "Pho bo", pho.Description)
+	assert.Equal(t, "Food & Dining", pho.Category)
+	assert.Equal(t, []string{"pho"}, pho.Tags)
+}
+
+func TestMigrationService_Import_CurrencyMismatch(t *testing.T) {
+	t.Parallel()
+
+	svc, m := newMigrationService()
+	userID := uuid.New()
+
+	m.accounts.On("List", mock.Anything, userID, true).Return([]model.AccountWithBalance{
+		{Account: model.Account{ID: uuid.New(), Name: "Vietcombank", Type: model.AccountTypeBank, Currency: "VND"}},
+	}, nil)
+	m.categories.On("List", mock.Anything, userID, true).Return([]model.Category{}, nil)
+	m.debts.On("List", mock.Anything, userID).Return([]model.Debt{}, nil)
+	m.budgets.On("List", mock.Anything, userID).Return([]model.Budget{}, nil)
+	m.repo.On("ExistingExternalIDs", mock.Anything, userID, mock.Anything).Return([]string{}, nil)
+	m.repo.On("TransfersBetween", mock.Anything, userID, mock.Anything, mock.Anything).Return([]model.Transfer{}, nil).Maybe()
+
+	export := "Id,Date,Category,Amount,Currency,Note,Wallet,With\n" +
+		"1,01/03/2024,Food & Beverage,-20,USD,Burger,Vietcombank,\n" +
+		"2,02/03/2024,Food & Beverage,\"-50,000\",VND,Pho bo,Vietcombank,\n" +
+		"3,03/03/2024,Outgoing Transfer,-100,USD,Top up,Vietcombank,\n" +
+		"4,03/03/2024,Incoming Transfer,100,USD,Top up,Wise,\n"
+
+	report, err := svc.Import(context.Background(), userID,
+		MigrationInput{App: importer.AppMoneyLover, FileName: "moneylover.csv", DryRun: true}, strings.NewReader(export))
+	require.NoError(t, err)
+
+	assert.Equal(t, MigrationSummary{Imported: 1, Skipped: 1}, report.Summary[MigrationItemTransaction])
+	assert.Equal(t, MigrationSummary{Skipped: 1}, report.Summary[MigrationItemTransfer])
+	assert.Contains(t, report.Items, MigrationItem{Line: 2, Kind: MigrationItemTransaction, Name: "Burger",
+		Outcome: MigrationSkipped, Reason: "Vietcombank is a VND account and the record is in USD"})
+}
+
+func TestMigrationService_Import_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   MigrationInput
+		body    string
+		wantErr error
+	}{
+		{
+			name:    "unknown app",
+			input:   MigrationInput{App: "mint"},
+			body:    moneyLoverExport,
+			wantErr: importer.ErrUnknownApp,
+		},
+		{
+			name:    "unsupported currency",
+			input:   MigrationInput{App: importer.AppMoneyLover, Currency: "XYZ"},
+			body:    moneyLoverExport,
+			wantErr: importer.ErrInvalidSettings,
+		},
+		{
+			name:    "export of another app",
+			input:   MigrationInput{App: importer.AppMISA},
+			body:    moneyLoverExport,
+			wantErr: importer.ErrNotAppExport,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc, _ := newMigrationService()
+			_, err := svc.Import(context.Background(), uuid.New(), tt.input, strings.NewReader(tt.body))
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestMigrationAccountType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		wallet string
+		want   model.AccountType
+	}{
+		{"Tiền mặt", model.AccountTypeCash},
+		{"Ví chính", model.AccountTypeCash},
+		{"Vietcombank", model.AccountTypeBank},
+		{"TK MB", model.AccountTypeBank},
+		{"Ví MoMo", model.AccountTypeEWallet},
+		{"Thẻ tín dụng VIB", model.AccountTypeCreditCard},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.wallet, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, migrationAccountType(tt.wallet))
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+)
+
+// ErrInvalidNotificationType is returned when filtering by an unknown notification type
+var ErrInvalidNotificationType = errors.New("unknown notification type")
+
+// inboxNotificationTypes lists the notification types that can be filtered on
+var inboxNotificationTypes = map[model.NotificationType]bool{
+	model.NotificationTypeBillReminder:   true,
+	model.NotificationTypeDebtReminder:   true,
+	model.NotificationTypeBudgetAlert:    true,
+	model.NotificationTypeBudgetExceeded: true,
+	model.NotificationTypeGoalMilestone:  true,
+	model.NotificationTypeWeeklySummary:  true,
+}
+
+// ListNotificationsInput holds the inbox filters. Limit defaults to 20 and is capped at 100.
+type ListNotificationsInput struct {
+	Type       *model.NotificationType
+	UnreadOnly bool
+	Limit      int
+	Offset     int
+}
+
+// NotificationInboxPage is one page of a user's notifications
+type NotificationInboxPage struct {
+	Notifications []model.NotificationLog `json:"notifications"`
+	Total         int                     `json:"total"`       // Matching the filters, across all pages
+	UnreadCount   int                     `json:"unreadCount"` // Across all types
+	Limit         int                     `json:"limit"`
+	Offset        int                     `json:"offset"`
+}
+
+// NotificationInboxService lets users read the notifications that were sent to them,
+// so nothing is lost when a push is not delivered.
+type NotificationInboxService struct {
+	repo repository.NotificationInboxRepository
+}
+
+// NewNotificationInboxService creates a new notification inbox service
+func NewNotificationInboxService(repo repository.NotificationInboxRepository) *NotificationInboxService {
+	return &NotificationInboxService{repo: repo}
+}
+
+// List returns a page of the user's notifications, newest first
+func (s *NotificationInboxService) List(ctx context.Context, userID uuid.UUID, input ListNotificationsInput) (*NotificationInboxPage, error) {
+	if err := validateNotificationType(input.Type); err != nil {
+		return nil, err
+	}
+
+	if input.Limit <= 0 {
+		input.Limit = 20
+	}
+	if input.Limit > 100 {
+		input.Limit = 100
+	}
+	if input.Offset < 0 {
+		input.Offset = 0
+	}
+
+	filter := repository.NotificationInboxFilter{
+		Type:       input.Type,
+		UnreadOnly: input.UnreadOnly,
+		Limit:      input.Limit,
+		Offset:     input.Offset,
+	}
+
+	notifications, err := s.repo.List(ctx, userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("listing notifications: %w", err)
+	}
+	total, err := s.repo.Count(ctx, userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("counting notifications: %w", err)
+	}
+	unread, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("counting unread notifications: %w", err)
+	}
+
+	return &NotificationInboxPage{
+		Notifications: notifications,
+		Total:         total,
+		UnreadCount:   unread,
+		Limit:         input.Limit,
+		Offset:        input.Offset,
+	}, nil
+}
+
+// UnreadCount returns the number of unread notifications
+func (s *NotificationInboxService) UnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	count, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("counting unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead marks one of the user's notifications as read
+func (s *NotificationInboxService) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	if err := s.repo.MarkRead(ctx, userID, id); err != nil {
+		return fmt.Errorf("marking notification %s read: %w", id, err)
+	}
+	return nil
+}
+
+// MarkAllRead marks all of the user's notifications as read, optionally only one
+// type. It returns the number of notifications marked.
+func (s *NotificationInboxService) MarkAllRead(ctx context.Context, userID uuid.UUID, notifType *model.NotificationType) (int64, error) {
+	if err := validateNotificationType(notifType); err != nil {
+		return 0, err
+	}
+
+	n, err := s.repo.MarkAllRead(ctx, userID, notifType)
+	if err != nil {
+		return 0, fmt.Errorf("marking notifications read: %w", err)
+	}
+	return n, nil
+}
+
+// Delete removes a notification from the user's inbox
+func (s *NotificationInboxService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if err := s.repo.Delete(ctx, userID, id); err != nil {
+		return fmt.Errorf("deleting notification %s: %w", id, err)
+	}
+	return nil
+}
+
+func validateNotificationType(notifType *model.NotificationType) error {
+	if notifType != nil && !inboxNotificationTypes[*notifType] {
+		return ErrInvalidNotificationType
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+)
+
+// MockNotificationInboxRepository implements repository.NotificationInboxRepository for testing
+type MockNotificationInboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationInboxRepository) List(ctx context.Context, userID uuid.UUID, filter repository.NotificationInboxFilter) ([]model.NotificationLog, error) {
+	args := m.Called(ctx, userID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.NotificationLog), args.Error(1)
+}
+
+func (m *MockNotificationInboxRepository) Count(ctx context.Context, userID uuid.UUID, filter repository.NotificationInboxFilter) (int, error) {
+	args := m.Called(ctx, userID, filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockNotificationInboxRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockNotificationInboxRepository) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockNotificationInboxRepository) MarkAllRead(ctx context.Context, userID uuid.UUID, notifType *model.NotificationType) (int64, error) {
+	args := m.Called(ctx, userID, notifType)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationInboxRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func TestNotificationInboxService_List(t *testing.T) {
+	t.Parallel()
+
+	weekly := model.NotificationTypeWeeklySummary
+	unknown := model.NotificationType("spam")
+
+	tests := []struct {
+		name       string
+		input      ListNotificationsInput
+		wantFilter repository.NotificationInboxFilter
+		wantErr    error
+	}{
+		{
+			name:       "defaults",
+			input:      ListNotificationsInput{},
+			wantFilter: repository.NotificationInboxFilter{Limit: 20},
+		},
+		{
+			name:       "limit is capped and negative offset ignored",
+			input:      ListNotificationsInput{Type: &weekly, UnreadOnly: true, Limit: 500, Offset: -5},
+			wantFilter: repository.NotificationInboxFilter{Type: &weekly, UnreadOnly: true, Limit: 100},
+		},
+		{
+			name:    "unknown type",
+			input:   ListNotificationsInput{Type: &unknown},
+			wantErr: ErrInvalidNotificationType,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockNotificationInboxRepository)
+			svc := NewNotificationInboxService(repo)
+			userID := uuid.New()
+
+			if tt.wantErr == nil {
+				repo.On("List", mock.Anything, userID, tt.wantFilter).Return([]model.NotificationLog{{ID: uuid.New()}}, nil)
+				repo.On("Count", mock.Anything, userID, tt.wantFilter).Return(42, nil)
+				repo.On("CountUnread", mock.Anything, userID).Return(7, nil)
+			}
+
+			page, err := svc.List(context.Background(), userID, tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, page.Notifications, 1)
+			assert.Equal(t, 42, page.Total)
+			assert.Equal(t, 7, page.UnreadCount)
+			assert.Equal(t, tt.wantFilter.Limit, page.Limit)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationInboxService_MarkAllRead(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockNotificationInboxRepository)
+	svc := NewNotificationInboxService(repo)
+	userID := uuid.New()
+	billReminder := model.NotificationTypeBillReminder
+	unknown := model.NotificationType("spam")
+
+	repo.On("MarkAllRead", mock.Anything, userID, &billReminder).Return(int64(3), nil)
+
+	marked, err := svc.MarkAllRead(context.Background(), userID, &billReminder)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), marked)
+
+	_, err = svc.MarkAllRead(context.Background(), userID, &unknown)
+	assert.ErrorIs(t, err, ErrInvalidNotificationType)
+	repo.AssertExpectations(t)
+}
+
+func TestNotificationInboxService_MarkRead_NotFound(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockNotificationInboxRepository)
+	svc := NewNotificationInboxService(repo)
+	userID := uuid.New()
+	id := uuid.New()
+
+	repo.On("MarkRead", mock.Anything, userID, id).Return(repository.ErrNotificationNotFound)
+
+	err := svc.MarkRead(context.Background(), userID, id)
+
+	assert.ErrorIs(t, err, repository.ErrNotificationNotFound)
+}
@@ -3,34 +3,92 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
+	"github.com/wealthpath/backend/internal/email"
 	"github.com/wealthpath/backend/internal/model"
 	"github.com/wealthpath/backend/internal/repository"
 )
 
+var (
+	ErrInvalidBankCode         = errors.New("unknown bank code")
+	ErrInvalidProductType      = errors.New("product type must be deposit, loan or mortgage")
+	ErrInvalidTermMonths       = errors.New("term is not a standard deposit term")
+	ErrInvalidThresholdPercent = errors.New("threshold percent must be at least 0 and less than 100")
+	ErrNoNotificationChannel   = errors.New("no notification channel is enabled and configured")
+)
+
+// rateProductTypes lists the product types that can be subscribed to
+var rateProductTypes = map[string]bool{
+	"deposit":  true,
+	"loan":     true,
+	"mortgage": true,
+}
+
+// CreateRateSubscriptionInput is the payload for subscribing to rate changes.
+// Omitted flags default to notifying on both directions by email only.
+type CreateRateSubscriptionInput struct {
+	BankCode         string          `json:"bankCode"`
+	ProductType      string          `json:"productType"` // deposit (default), loan, mortgage
+	TermMonths       int             `json:"termMonths"`
+	ThresholdPercent decimal.Decimal `json:"thresholdPercent"`
+	NotifyOnIncrease *bool           `json:"notifyOnIncrease,omitempty"`
+	NotifyOnDecrease *bool           `json:"notifyOnDecrease,omitempty"`
+	EmailEnabled     *bool           `json:"emailEnabled,omitempty"`
+	PushEnabled      *bool           `json:"pushEnabled,omitempty"`
+}
+
+// UpdateRateSubscriptionInput holds the fields of a subscription that can be changed.
+// Bank, product and term identify the subscription and cannot be changed.
+type UpdateRateSubscriptionInput struct {
+	ThresholdPercent *decimal.Decimal `json:"thresholdPercent,omitempty"`
+	NotifyOnIncrease *bool            `json:"notifyOnIncrease,omitempty"`
+	NotifyOnDecrease *bool            `json:"notifyOnDecrease,omitempty"`
+	EmailEnabled     *bool            `json:"emailEnabled,omitempty"`
+	PushEnabled      *bool            `json:"pushEnabled,omitempty"`
+}
+
 // NotificationService handles rate change notifications
 type NotificationService struct {
 	subRepo     repository.RateSubscriptionRepository
 	rateRepo    repository.InterestRateRepository
-	userRepo    repository.UserRepository
+	userRepo    NotificationUserRepository
 	emailSender EmailSender
+	pushSender  PushSender
+	cooldown    time.Duration
+}
+
+// DefaultRateAlertCooldown is the minimum time between two alerts for the same subscription
+const DefaultRateAlertCooldown = 12 * time.Hour
+
+// NotificationUserRepository looks up the recipient of an alert
+type NotificationUserRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*model.User, error)
 }
 
 // EmailSender defines the interface for sending emails
 type EmailSender interface {
+	// Send sends a plain-text email
 	Send(to, subject, body string) error
+	// SendTemplate renders a localized template (see package email) and sends it
+	SendTemplate(ctx context.Context, to, locale, template string, data any) error
+}
+
+// PushSender delivers web push notifications to all of a user's devices
+type PushSender interface {
+	SendToUser(ctx context.Context, userID uuid.UUID, payload *NotificationPayload) error
 }
 
 // NewNotificationService creates a new notification service
 func NewNotificationService(
 	subRepo repository.RateSubscriptionRepository,
 	rateRepo repository.InterestRateRepository,
-	userRepo repository.UserRepository,
+	userRepo NotificationUserRepository,
 	emailSender EmailSender,
 ) *NotificationService {
 	return &NotificationService{
@@ -38,11 +96,45 @@ func NewNotificationService(
 		rateRepo:    rateRepo,
 		userRepo:    userRepo,
 		emailSender: emailSender,
+		cooldown:    DefaultRateAlertCooldown,
 	}
 }
 
+// SetPushSender enables push delivery of rate alerts
+func (s *NotificationService) SetPushSender(sender PushSender) {
+	s.pushSender = sender
+}
+
+// SetCooldown sets the minimum time between two alerts for the same subscription
+func (s *NotificationService) SetCooldown(cooldown time.Duration) {
+	s.cooldown = cooldown
+}
+
+// CreateSubscription validates the input and subscribes the user to rate changes.
+// Subscribing again to the same bank, product and term updates the existing subscription.
+func (s *NotificationService) CreateSubscription(ctx context.Context, userID uuid.UUID, input CreateRateSubscriptionInput) (*model.RateSubscription, error) {
+	sub := &model.RateSubscription{
+		BankCode:         input.BankCode,
+		ProductType:      input.ProductType,
+		TermMonths:       input.TermMonths,
+		ThresholdPercent: input.ThresholdPercent,
+		NotifyOnIncrease: boolOrDefault(input.NotifyOnIncrease, true),
+		NotifyOnDecrease: boolOrDefault(input.NotifyOnDecrease, true),
+		EmailEnabled:     boolOrDefault(input.EmailEnabled, true),
+		PushEnabled:      boolOrDefault(input.PushEnabled, false),
+	}
+	if sub.ProductType == "" {
+		sub.ProductType = "deposit"
+	}
+
+	return s.Subscribe(ctx, userID, sub)
+}
+
 // Subscribe creates a new rate subscription for a user
 func (s *NotificationService) Subscribe(ctx context.Context, userID uuid.UUID, input *model.RateSubscription) (*model.RateSubscription, error) {
+	if err := validateRateSubscription(input); err != nil {
+		return nil, err
+	}
 	input.UserID = userID
 
 	// Get current rate to store as baseline
@@ -62,6 +154,44 @@ func (s *NotificationService) Subscribe(ctx context.Context, userID uuid.UUID, i
 	return input, nil
 }
 
+// GetSubscription returns a subscription owned by the user
+func (s *NotificationService) GetSubscription(ctx context.Context, userID uuid.UUID, subscriptionID int64) (*model.RateSubscription, error) {
+	sub, err := s.subRepo.GetByID(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if sub.UserID != userID {
+		return nil, repository.ErrRateSubscriptionNotFound
+	}
+	return sub, nil
+}
+
+// UpdateSubscription changes the threshold and delivery options of a subscription
+func (s *NotificationService) UpdateSubscription(ctx context.Context, userID uuid.UUID, subscriptionID int64, input UpdateRateSubscriptionInput) (*model.RateSubscription, error) {
+	sub, err := s.GetSubscription(ctx, userID, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.ThresholdPercent != nil {
+		sub.ThresholdPercent = *input.ThresholdPercent
+	}
+	sub.NotifyOnIncrease = boolOrDefault(input.NotifyOnIncrease, sub.NotifyOnIncrease)
+	sub.NotifyOnDecrease = boolOrDefault(input.NotifyOnDecrease, sub.NotifyOnDecrease)
+	sub.EmailEnabled = boolOrDefault(input.EmailEnabled, sub.EmailEnabled)
+	sub.PushEnabled = boolOrDefault(input.PushEnabled, sub.PushEnabled)
+
+	if err := validateRateSubscription(sub); err != nil {
+		return nil, err
+	}
+
+	if err := s.subRepo.Update(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
 // Unsubscribe removes a rate subscription
 func (s *NotificationService) Unsubscribe(ctx context.Context, userID uuid.UUID, subscriptionID int64) error {
 	return s.subRepo.Delete(ctx, userID, subscriptionID)
@@ -72,8 +202,20 @@ func (s *NotificationService) GetUserSubscriptions(ctx context.Context, userID u
 	return s.subRepo.ListByUser(ctx, userID)
 }
 
-// CheckAndNotify checks for rate changes and sends notifications
-// This should be called periodically (e.g., daily via cron)
+// GetNotificationHistory returns the rate alerts already sent to the user, newest first.
+// If subscriptionID is set, only alerts for that subscription are returned.
+func (s *NotificationService) GetNotificationHistory(ctx context.Context, userID uuid.UUID, subscriptionID *int64, limit, offset int) ([]model.RateNotificationHistory, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.subRepo.ListNotificationsByUser(ctx, userID, subscriptionID, limit, offset)
+}
+
+// CheckAndNotify checks for rate changes and alerts matching subscribers.
+// It runs right after each scrape and returns the number of subscriptions alerted.
 func (s *NotificationService) CheckAndNotify(ctx context.Context) (int, error) {
 	// Get all active subscriptions
 	subscriptions, err := s.subRepo.ListAll(ctx)
@@ -122,6 +264,20 @@ func (s *NotificationService) CheckAndNotify(ctx context.Context) (int, error) {
 			continue
 		}
 
+		// De-duplicate: the last rate is kept so the change keeps accumulating
+		// until the cooldown has passed. Failed alerts count too, so a failing
+		// channel is retried once per cooldown rather than on every scrape.
+		if s.cooldown > 0 {
+			recent, err := s.subRepo.HasRecentNotification(ctx, sub.ID, time.Now().Add(-s.cooldown))
+			if err != nil {
+				log.Printf("Error checking recent notifications for subscription %d: %v", sub.ID, err)
+				continue
+			}
+			if recent {
+				continue
+			}
+		}
+
 		// Get bank name
 		bankName := sub.BankCode
 		for _, bank := range model.VietnameseBanks {
@@ -145,14 +301,14 @@ func (s *NotificationService) CheckAndNotify(ctx context.Context) (int, error) {
 		}
 
 		if err := s.sendNotification(ctx, alert); err != nil {
-			log.Printf("Error sending notification for subscription %d: %v", sub.ID, err)
+			// Keep the old rate so the alert is retried on the next run
+			if !errors.Is(err, ErrNoNotificationChannel) {
+				log.Printf("Error sending notification for subscription %d: %v", sub.ID, err)
+			}
 			continue
 		}
 
 		// Update subscription with new rate
-		now := time.Now()
-		sub.LastRate = currentRate
-		sub.LastNotifiedAt = &now
 		if err := s.subRepo.UpdateLastRate(ctx, sub.ID, currentRate); err != nil {
 			log.Printf("Error updating subscription %d: %v", sub.ID, err)
 		}
@@ -163,62 +319,154 @@ func (s *NotificationService) CheckAndNotify(ctx context.Context) (int, error) {
 	return notificationsSent, nil
 }
 
+// sendNotification delivers an alert on every channel enabled for the subscription.
+// It returns an error only if no channel delivered the alert, and
+// ErrNoNotificationChannel if there was none to deliver it on. Push counts as
+// unavailable while the user has no device registered.
 func (s *NotificationService) sendNotification(ctx context.Context, alert *model.RateChangeAlert) error {
-	// Get user email
-	user, err := s.userRepo.GetByID(ctx, alert.Subscription.UserID)
-	if err != nil {
-		return fmt.Errorf("get user: %w", err)
+	sub := alert.Subscription
+	var errs []error
+	delivered := false
+
+	if sub.PushEnabled && s.pushSender != nil {
+		err := s.deliver(ctx, alert, model.RateNotificationChannelPush, func() error {
+			return s.pushSender.SendToUser(ctx, sub.UserID, ratePushPayload(alert))
+		})
+		switch {
+		case errors.Is(err, ErrNoSubscriptions):
+			// No device to push to, as if push were disabled
+		case err != nil:
+			errs = append(errs, fmt.Errorf("push: %w", err))
+		default:
+			delivered = true
+		}
 	}
 
-	if alert.Subscription.EmailEnabled && s.emailSender != nil {
-		subject := fmt.Sprintf("📈 Lãi suất %s đã thay đổi", alert.BankName)
-		if alert.ChangeType == "decrease" {
-			subject = fmt.Sprintf("📉 Lãi suất %s đã giảm", alert.BankName)
+	if sub.EmailEnabled && s.emailSender != nil {
+		err := s.deliver(ctx, alert, model.RateNotificationChannelEmail, func() error {
+			user, err := s.userRepo.GetByID(ctx, sub.UserID)
+			if err != nil {
+				return fmt.Errorf("get user: %w", err)
+			}
+			return s.emailSender.SendTemplate(ctx, user.Email, emailLocaleForUser(user), email.TemplateRateAlert, email.RateAlertData{
+				Name:       user.Name,
+				BankName:   alert.BankName,
+				TermMonths: sub.TermMonths,
+				OldRate:    alert.OldRate.StringFixed(2),
+				NewRate:    alert.NewRate.StringFixed(2),
+				Change:     alert.Change.StringFixed(2),
+				Increased:  alert.ChangeType == "increase",
+			})
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("email: %w", err))
+		} else {
+			delivered = true
 		}
+	}
 
-		body := fmt.Sprintf(`
-Xin chào %s,
-
-Lãi suất %s %s kỳ hạn %d tháng đã %s:
-
-• Lãi suất cũ: %s%%
-• Lãi suất mới: %s%%
-• Thay đổi: %s%%
-
-Truy cập WealthPath để xem chi tiết và so sánh lãi suất các ngân hàng khác.
-
----
-WealthPath - Quản lý tài chính cá nhân
-		`,
-			user.Name,
-			alert.BankName,
-			alert.Subscription.ProductType,
-			alert.Subscription.TermMonths,
-			alert.ChangeType,
-			alert.OldRate.StringFixed(2),
-			alert.NewRate.StringFixed(2),
-			alert.Change.StringFixed(2),
-		)
+	if !delivered && len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if !delivered {
+		return ErrNoNotificationChannel
+	}
+	for _, err := range errs {
+		log.Printf("Partial delivery failure for subscription %d: %v", sub.ID, err)
+	}
+	return nil
+}
 
-		if err := s.emailSender.Send(user.Email, subject, body); err != nil {
-			log.Printf("Failed to send email to %s: %v", user.Email, err)
-			// Don't return error, continue with other notifications
-		}
+// deliver runs send and records the outcome as a RateNotification. A push
+// channel without devices was not tried and is not recorded. Email is recorded
+// as sent once queued; the outbox keeps the status of the actual delivery.
+func (s *NotificationService) deliver(ctx context.Context, alert *model.RateChangeAlert, channel string, send func() error) error {
+	sendErr := send()
+	if errors.Is(sendErr, ErrNoSubscriptions) {
+		return sendErr
 	}
 
-	// Log the notification
+	status := model.RateNotificationStatusSent
+	if sendErr != nil {
+		status = model.RateNotificationStatusFailed
+	}
 	notification := &model.RateNotification{
 		SubscriptionID:   alert.Subscription.ID,
 		OldRate:          alert.OldRate,
 		NewRate:          alert.NewRate,
 		ChangePercent:    alert.Change.Div(alert.OldRate).Mul(decimal.NewFromInt(100)),
-		NotificationType: "email",
-		Status:           "sent",
+		NotificationType: channel,
+		Status:           status,
 	}
-
 	if err := s.subRepo.LogNotification(ctx, notification); err != nil {
 		log.Printf("Failed to log notification: %v", err)
 	}
 
+	return sendErr
+}
+
+// ratePushPayload builds the web push payload for a rate change alert
+func ratePushPayload(alert *model.RateChangeAlert) *NotificationPayload {
+	sub := alert.Subscription
+	direction := "up"
+	if alert.ChangeType == "decrease" {
+		direction = "down"
+	}
+
+	return &NotificationPayload{
+		Title: fmt.Sprintf("%s %d-month rate %s", alert.BankName, sub.TermMonths, direction),
+		Body:  fmt.Sprintf("%s%% → %s%% (%s%%)", alert.OldRate.StringFixed(2), alert.NewRate.StringFixed(2), alert.Change.StringFixed(2)),
+		Icon:  "/icon-192.png",
+		Badge: "/badge-72.png",
+		Tag:   fmt.Sprintf("rate-%d", sub.ID),
+		Data: map[string]interface{}{
+			"type":           "rate_change",
+			"subscriptionId": sub.ID,
+			"bankCode":       sub.BankCode,
+			"url":            "/interest-rates",
+		},
+	}
+}
+
+// validateRateSubscription checks the subscription against the known banks, product types and terms
+func validateRateSubscription(sub *model.RateSubscription) error {
+	knownBank := false
+	for _, bank := range model.VietnameseBanks {
+		if bank.Code == sub.BankCode {
+			knownBank = true
+			break
+		}
+	}
+	if !knownBank {
+		return ErrInvalidBankCode
+	}
+
+	if !rateProductTypes[sub.ProductType] {
+		return ErrInvalidProductType
+	}
+
+	standardTerm := false
+	for _, term := range model.StandardTerms {
+		if term.Months == sub.TermMonths {
+			standardTerm = true
+			break
+		}
+	}
+	if !standardTerm {
+		return ErrInvalidTermMonths
+	}
+
+	if sub.ThresholdPercent.IsNegative() || sub.ThresholdPercent.GreaterThanOrEqual(decimal.NewFromInt(100)) {
+		return ErrInvalidThresholdPercent
+	}
+
 	return nil
 }
+
+// boolOrDefault dereferences an optional flag
+func boolOrDefault(v *bool, def bool) bool {
+	if v == nil {
+		return def
+	}
+	return *v
+}
@@ -0,0 +1,432 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+)
+
+// MockRateSubscriptionRepository is a mock implementation of RateSubscriptionRepository
+type MockRateSubscriptionRepository struct {
+	mock.Mock
+}
+
+func (m *MockRateSubscriptionRepository) Create(ctx context.Context, sub *model.RateSubscription) error {
+	args := m.Called(ctx, sub)
+	return args.Error(0)
+}
+
+func (m *MockRateSubscriptionRepository) GetByID(ctx context.Context, id int64) (*model.RateSubscription, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RateSubscription), args.Error(1)
+}
+
+func (m *MockRateSubscriptionRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]model.RateSubscription, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.RateSubscription), args.Error(1)
+}
+
+func (m *MockRateSubscriptionRepository) ListAll(ctx context.Context) ([]model.RateSubscription, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.RateSubscription), args.Error(1)
+}
+
+func (m *MockRateSubscriptionRepository) Update(ctx context.Context, sub *model.RateSubscription) error {
+	args := m.Called(ctx, sub)
+	return args.Error(0)
+}
+
+func (m *MockRateSubscriptionRepository) UpdateLastRate(ctx context.Context, id int64, rate decimal.Decimal) error {
+	args := m.Called(ctx, id, rate)
+	return args.Error(0)
+}
+
+func (m *MockRateSubscriptionRepository) Delete(ctx context.Context, userID uuid.UUID, id int64) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockRateSubscriptionRepository) LogNotification(ctx context.Context, notification *model.RateNotification) error {
+	args := m.Called(ctx, notification)
+	return args.Error(0)
+}
+
+func (m *MockRateSubscriptionRepository) HasRecentNotification(ctx context.Context, subscriptionID int64, since time.Time) (bool, error) {
+	args := m.Called(ctx, subscriptionID, since)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRateSubscriptionRepository) ListNotificationsByUser(ctx context.Context, userID uuid.UUID, subscriptionID *int64, limit, offset int) ([]model.RateNotificationHistory, error) {
+	args := m.Called(ctx, userID, subscriptionID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.RateNotificationHistory), args.Error(1)
+}
+
+// MockPushSender is a mock implementation of PushSender
+type MockPushSender struct {
+	mock.Mock
+}
+
+func (m *MockPushSender) SendToUser(ctx context.Context, userID uuid.UUID, payload *NotificationPayload) error {
+	args := m.Called(ctx, userID, payload)
+	return args.Error(0)
+}
+
+// MockEmailSender is a mock implementation of EmailSender
+type MockEmailSender struct {
+	mock.Mock
+}
+
+func (m *MockEmailSender) Send(to, subject, body string) error {
+	args := m.Called(to, subject, body)
+	return args.Error(0)
+}
+
+func (m *MockEmailSender) SendTemplate(ctx context.Context, to, locale, template string, data any) error {
+	args := m.Called(ctx, to, locale, template, data)
+	return args.Error(0)
+}
+
+// MockNotificationUserRepo is a mock implementation of NotificationUserRepository
+type MockNotificationUserRepo struct {
+	mock.Mock
+}
+
+func (m *MockNotificationUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func TestNotificationService_CreateSubscription(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   CreateRateSubscriptionInput
+		wantErr error
+	}{
+		{
+			name:  "valid deposit subscription",
+			input: CreateRateSubscriptionInput{BankCode: "tcb", TermMonths: 12, ThresholdPercent: decimal.NewFromFloat(0.2)},
+		},
+		{
+			name:    "unknown bank",
+			input:   CreateRateSubscriptionInput{BankCode: "xyz", TermMonths: 12},
+			wantErr: ErrInvalidBankCode,
+		},
+		{
+			name:    "non-standard term",
+			input:   CreateRateSubscriptionInput{BankCode: "tcb", TermMonths: 7},
+			wantErr: ErrInvalidTermMonths,
+		},
+		{
+			name:    "unknown product type",
+			input:   CreateRateSubscriptionInput{BankCode: "tcb", ProductType: "bond", TermMonths: 12},
+			wantErr: ErrInvalidProductType,
+		},
+		{
+			name:    "negative threshold",
+			input:   CreateRateSubscriptionInput{BankCode: "tcb", TermMonths: 12, ThresholdPercent: decimal.NewFromInt(-1)},
+			wantErr: ErrInvalidThresholdPercent,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subRepo := new(MockRateSubscriptionRepository)
+			rateRepo := new(MockInterestRateRepository)
+			svc := &NotificationService{subRepo: subRepo, rateRepo: rateRepo}
+			userID := uuid.New()
+
+			if tt.wantErr == nil {
+				rateRepo.On("List", mock.Anything, "deposit", mock.Anything, tt.input.BankCode).
+					Return([]model.InterestRate{{Rate: decimal.NewFromFloat(4.85)}}, nil)
+				subRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.RateSubscription")).Return(nil)
+			}
+
+			sub, err := svc.CreateSubscription(context.Background(), userID, tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				subRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, userID, sub.UserID)
+			assert.Equal(t, "deposit", sub.ProductType)
+			assert.True(t, sub.NotifyOnIncrease)
+			assert.True(t, sub.NotifyOnDecrease)
+			assert.True(t, sub.EmailEnabled)
+			assert.False(t, sub.PushEnabled)
+			assert.True(t, decimal.NewFromFloat(4.85).Equal(sub.LastRate))
+			subRepo.AssertExpectations(t)
+			rateRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationService_GetSubscription_OtherUser(t *testing.T) {
+	t.Parallel()
+
+	subRepo := new(MockRateSubscriptionRepository)
+	svc := &NotificationService{subRepo: subRepo}
+
+	subRepo.On("GetByID", mock.Anything, int64(5)).Return(&model.RateSubscription{ID: 5, UserID: uuid.New()}, nil)
+
+	_, err := svc.GetSubscription(context.Background(), uuid.New(), 5)
+	assert.ErrorIs(t, err, repository.ErrRateSubscriptionNotFound)
+}
+
+func TestNotificationService_UpdateSubscription(t *testing.T) {
+	t.Parallel()
+
+	subRepo := new(MockRateSubscriptionRepository)
+	svc := &NotificationService{subRepo: subRepo}
+	userID := uuid.New()
+
+	existing := &model.RateSubscription{
+		ID: 5, UserID: userID, BankCode: "tcb", ProductType: "deposit", TermMonths: 12,
+		NotifyOnIncrease: true, NotifyOnDecrease: true, EmailEnabled: true,
+	}
+	subRepo.On("GetByID", mock.Anything, int64(5)).Return(existing, nil)
+	subRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.RateSubscription")).Return(nil)
+
+	push := true
+	increase := false
+	threshold := decimal.NewFromFloat(0.5)
+	sub, err := svc.UpdateSubscription(context.Background(), userID, 5, UpdateRateSubscriptionInput{
+		ThresholdPercent: &threshold,
+		NotifyOnIncrease: &increase,
+		PushEnabled:      &push,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, threshold.Equal(sub.ThresholdPercent))
+	assert.False(t, sub.NotifyOnIncrease)
+	assert.True(t, sub.NotifyOnDecrease)
+	assert.True(t, sub.EmailEnabled)
+	assert.True(t, sub.PushEnabled)
+	subRepo.AssertExpectations(t)
+}
+
+func TestNotificationService_GetNotificationHistory_ClampsLimit(t *testing.T) {
+	t.Parallel()
+
+	subRepo := new(MockRateSubscriptionRepository)
+	svc := &NotificationService{subRepo: subRepo}
+	userID := uuid.New()
+
+	subRepo.On("ListNotificationsByUser", mock.Anything, userID, (*int64)(nil), 20, 0).
+		Return([]model.RateNotificationHistory{{BankCode: "tcb"}}, nil)
+
+	history, err := svc.GetNotificationHistory(context.Background(), userID, nil, 1000, -5)
+
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+	subRepo.AssertExpectations(t)
+}
+
+func TestNotificationService_CheckAndNotify(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	baseSub := model.RateSubscription{
+		ID: 9, UserID: userID, BankCode: "tcb", ProductType: "deposit", TermMonths: 12,
+		LastRate: decimal.NewFromFloat(4.80), ThresholdPercent: decimal.NewFromFloat(0.5),
+		NotifyOnIncrease: true, NotifyOnDecrease: true, EmailEnabled: true, PushEnabled: true,
+	}
+
+	tests := []struct {
+		name        string
+		sub         model.RateSubscription
+		currentRate float64
+		recent      bool
+		pushErr     error
+		emailErr    error
+		wantSent    int
+		wantPush    bool
+		wantEmail   bool
+		wantUpdate  bool
+	}{
+		{
+			name:        "delivers on push and email",
+			sub:         baseSub,
+			currentRate: 5.00,
+			wantSent:    1, wantPush: true, wantEmail: true, wantUpdate: true,
+		},
+		{
+			name: "push only when email disabled",
+			sub: func() model.RateSubscription {
+				s := baseSub
+				s.EmailEnabled = false
+				return s
+			}(),
+			currentRate: 5.00,
+			wantSent:    1, wantPush: true, wantUpdate: true,
+		},
+		{
+			name:        "change below threshold is ignored",
+			sub:         baseSub,
+			currentRate: 4.81,
+		},
+		{
+			name:        "recent alert within cooldown is skipped",
+			sub:         baseSub,
+			currentRate: 5.00,
+			recent:      true,
+		},
+		{
+			name:        "one channel failing still counts as delivered",
+			sub:         baseSub,
+			currentRate: 4.50,
+			pushErr:     errors.New("push gateway down"),
+			wantSent:    1, wantPush: true, wantEmail: true, wantUpdate: true,
+		},
+		{
+			name: "no channel to deliver on keeps the old rate",
+			sub: func() model.RateSubscription {
+				s := baseSub
+				s.PushEnabled, s.EmailEnabled = false, false
+				return s
+			}(),
+			currentRate: 5.00,
+		},
+		{
+			name:        "push without devices is skipped unrecorded",
+			sub:         baseSub,
+			currentRate: 5.00,
+			pushErr:     ErrNoSubscriptions,
+			wantSent:    1, wantPush: true, wantEmail: true, wantUpdate: true,
+		},
+		{
+			name:        "all channels failing keeps the old rate for retry",
+			sub:         baseSub,
+			currentRate: 4.50,
+			pushErr:     errors.New("push gateway down"),
+			emailErr:    errors.New("smtp down"),
+			wantPush:    true, wantEmail: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subRepo := new(MockRateSubscriptionRepository)
+			rateRepo := new(MockInterestRateRepository)
+			userRepo := new(MockNotificationUserRepo)
+			push := new(MockPushSender)
+			email := new(MockEmailSender)
+
+			svc := NewNotificationService(subRepo, rateRepo, userRepo, email)
+			svc.SetPushSender(push)
+
+			current := decimal.NewFromFloat(tt.currentRate)
+			subRepo.On("ListAll", mock.Anything).Return([]model.RateSubscription{tt.sub}, nil)
+			rateRepo.On("List", mock.Anything, "deposit", mock.Anything, "tcb").
+				Return([]model.InterestRate{{Rate: current}}, nil)
+			subRepo.On("HasRecentNotification", mock.Anything, int64(9), mock.AnythingOfType("time.Time")).Return(tt.recent, nil).Maybe()
+			expectLog := func(channel string, sendErr error) {
+				wantStatus := model.RateNotificationStatusSent
+				if sendErr != nil {
+					wantStatus = model.RateNotificationStatusFailed
+				}
+				subRepo.On("LogNotification", mock.Anything, mock.MatchedBy(func(n *model.RateNotification) bool {
+					return n.NotificationType == channel && n.Status == wantStatus
+				})).Return(nil).Once()
+			}
+			if tt.wantPush {
+				push.On("SendToUser", mock.Anything, userID, mock.AnythingOfType("*service.NotificationPayload")).Return(tt.pushErr)
+				if !errors.Is(tt.pushErr, ErrNoSubscriptions) {
+					expectLog(model.RateNotificationChannelPush, tt.pushErr)
+				}
+			}
+			if tt.wantEmail {
+				userRepo.On("GetByID", mock.Anything, userID).Return(&model.User{Email: "a@example.com", Name: "An", Currency: "VND"}, nil)
+				email.On("SendTemplate", mock.Anything, "a@example.com", "vi", "rate_alert", mock.AnythingOfType("email.RateAlertData")).Return(tt.emailErr)
+				expectLog(model.RateNotificationChannelEmail, tt.emailErr)
+			}
+			if tt.wantUpdate {
+				subRepo.On("UpdateLastRate", mock.Anything, int64(9), current).Return(nil)
+			}
+
+			sent, err := svc.CheckAndNotify(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSent, sent)
+			if !tt.wantPush {
+				push.AssertNotCalled(t, "SendToUser", mock.Anything, mock.Anything, mock.Anything)
+			}
+			if !tt.wantEmail {
+				email.AssertNotCalled(t, "SendTemplate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			if !tt.wantUpdate {
+				subRepo.AssertNotCalled(t, "UpdateLastRate", mock.Anything, mock.Anything, mock.Anything)
+			}
+			subRepo.AssertExpectations(t)
+			push.AssertExpectations(t)
+			email.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationService_CheckAndNotify_PushWithoutDevices(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	sub := model.RateSubscription{
+		ID: 9, UserID: userID, BankCode: "tcb", ProductType: "deposit", TermMonths: 12,
+		LastRate: decimal.NewFromFloat(4.80), NotifyOnIncrease: true, NotifyOnDecrease: true, PushEnabled: true,
+	}
+
+	subRepo := new(MockRateSubscriptionRepository)
+	rateRepo := new(MockInterestRateRepository)
+	push := new(MockPushSender)
+	svc := NewNotificationService(subRepo, rateRepo, new(MockNotificationUserRepo), new(MockEmailSender))
+	svc.SetPushSender(push)
+
+	subRepo.On("ListAll", mock.Anything).Return([]model.RateSubscription{sub}, nil)
+	rateRepo.On("List", mock.Anything, "deposit", mock.Anything, "tcb").
+		Return([]model.InterestRate{{Rate: decimal.NewFromFloat(5.00)}}, nil)
+	subRepo.On("HasRecentNotification", mock.Anything, int64(9), mock.AnythingOfType("time.Time")).Return(false, nil)
+	push.On("SendToUser", mock.Anything, userID, mock.AnythingOfType("*service.NotificationPayload")).Return(ErrNoSubscriptions)
+
+	// Each scrape finds nothing to deliver on; none records a failed alert or
+	// moves the last rate on
+	for scrape := 0; scrape < 2; scrape++ {
+		sent, err := svc.CheckAndNotify(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, sent)
+	}
+
+	push.AssertNumberOfCalls(t, "SendToUser", 2)
+	subRepo.AssertNotCalled(t, "LogNotification", mock.Anything, mock.Anything)
+	subRepo.AssertNotCalled(t, "UpdateLastRate", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/SherClockHolmes/webpush-go"
 	"github.com/google/uuid"
 	"github.com/wealthpath/backend/internal/config"
 	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/pkg/currency"
 )
 
 var (
@@ -27,6 +30,10 @@ type PushRepositoryInterface interface {
 	UpsertPreferences(ctx context.Context, prefs *model.NotificationPreferences) error
 	LogNotification(ctx context.Context, log *model.NotificationLog) error
 	HasRecentNotification(ctx context.Context, userID uuid.UUID, notifType model.NotificationType, refID *uuid.UUID, refDate *time.Time) (bool, error)
+	HasNotificationForDate(ctx context.Context, userID uuid.UUID, notifType model.NotificationType, refID *uuid.UUID, refDate time.Time) (bool, error)
+	HasMilestoneNotification(ctx context.Context, userID, goalID uuid.UUID, milestone int) (bool, error)
+	GetDueBills(ctx context.Context) ([]repository.DueBill, error)
+	GetDebtsForReminders(ctx context.Context) ([]repository.ReminderDebt, error)
 }
 
 type PushNotificationService struct {
@@ -192,19 +199,9 @@ func (s *PushNotificationService) SendBillReminder(ctx context.Context, userID u
 		return nil // Already notified
 	}
 
-	daysUntil := int(time.Until(dueDate).Hours() / 24)
-	var body string
-	if daysUntil == 0 {
-		body = "Due today"
-	} else if daysUntil == 1 {
-		body = "Due tomorrow"
-	} else {
-		body = "Due in " + string(rune(daysUntil+'0')) + " days"
-	}
-
 	payload := &NotificationPayload{
 		Title: "Upcoming Bill: " + billName,
-		Body:  amount + " - " + body,
+		Body:  amount + " - " + dueInText(daysUntil(dueDate, time.Now())),
 		Icon:  "/icon-192.png",
 		Badge: "/badge-72.png",
 		Tag:   "bill-" + recurringID.String(),
@@ -238,11 +235,202 @@ func (s *PushNotificationService) SendBillReminder(ctx context.Context, userID u
 	return err
 }
 
-// SendBudgetAlert sends a budget overspending alert
+// SendDueBillReminders sends a reminder for every recurring bill inside its owner's
+// reminder window. It returns the number of bills processed without error.
+func (s *PushNotificationService) SendDueBillReminders(ctx context.Context) (int, error) {
+	if !s.IsConfigured() {
+		return 0, ErrVAPIDNotConfigured
+	}
+
+	bills, err := s.repo.GetDueBills(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("getting due bills: %w", err)
+	}
+
+	sent := 0
+	var errs []error
+	for _, bill := range bills {
+		name := bill.Description
+		if name == "" {
+			name = bill.Category
+		}
+		amount := currency.NewMoney(bill.Amount, currency.Currency(bill.Currency)).Format()
+
+		err := s.SendBillReminder(ctx, bill.UserID, name, amount, bill.NextOccurrence, bill.RecurringID)
+		if err != nil && !errors.Is(err, ErrNoSubscriptions) {
+			errs = append(errs, fmt.Errorf("bill %s: %w", bill.RecurringID, err))
+			continue
+		}
+		sent++
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// SendDebtReminder sends a reminder that a debt payment is coming up. Each due date
+// of a debt is reminded at most once.
+func (s *PushNotificationService) SendDebtReminder(ctx context.Context, userID uuid.UUID, debtName string, amount string, dueDate time.Time, debtID uuid.UUID) error {
+	sent, err := s.repo.HasNotificationForDate(ctx, userID, model.NotificationTypeDebtReminder, &debtID, dueDate)
+	if err != nil {
+		return err
+	}
+	if sent {
+		return nil // Already reminded for this due date
+	}
+
+	body := dueInText(daysUntil(dueDate, time.Now()))
+	if amount != "" {
+		body = "Minimum payment " + amount + " - " + body
+	}
+
+	payload := &NotificationPayload{
+		Title: "Payment Due: " + debtName,
+		Body:  body,
+		Icon:  "/icon-192.png",
+		Badge: "/badge-72.png",
+		Tag:   "debt-" + debtID.String(),
+		Data: map[string]interface{}{
+			"type":    "debt_reminder",
+			"debtId":  debtID.String(),
+			"dueDate": dueDate.Format("2006-01-02"),
+			"url":     "/debts",
+		},
+	}
+
+	err = s.SendToUser(ctx, userID, payload)
+
+	// Log the notification
+	log := &model.NotificationLog{
+		ID:               uuid.New(),
+		UserID:           userID,
+		NotificationType: model.NotificationTypeDebtReminder,
+		ReferenceID:      &debtID,
+		ReferenceDate:    &dueDate,
+		Title:            payload.Title,
+		Body:             payload.Body,
+		SentAt:           time.Now(),
+		Success:          err == nil || errors.Is(err, ErrNoSubscriptions),
+	}
+	if err != nil && !errors.Is(err, ErrNoSubscriptions) {
+		errMsg := err.Error()
+		log.ErrorMessage = &errMsg
+	}
+	_ = s.repo.LogNotification(ctx, log)
+
+	return err
+}
+
+// SendDueDebtReminders reminds users of debt payments whose next due date falls
+// inside their bill reminder window. It returns the number of debts processed
+// without error.
+func (s *PushNotificationService) SendDueDebtReminders(ctx context.Context) (int, error) {
+	if !s.IsConfigured() {
+		return 0, ErrVAPIDNotConfigured
+	}
+
+	debts, err := s.repo.GetDebtsForReminders(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("getting debts for reminders: %w", err)
+	}
+
+	now := time.Now()
+	sent := 0
+	var errs []error
+	for _, debt := range debts {
+		dueDate, ok := debtReminderDueDate(debt, now)
+		if !ok {
+			continue
+		}
+
+		amount := ""
+		if debt.MinimumPayment.IsPositive() {
+			amount = currency.NewMoney(debt.MinimumPayment, currency.Currency(debt.Currency)).Format()
+		}
+
+		err := s.SendDebtReminder(ctx, debt.UserID, debt.Name, amount, dueDate, debt.DebtID)
+		if err != nil && !errors.Is(err, ErrNoSubscriptions) {
+			errs = append(errs, fmt.Errorf("debt %s: %w", debt.DebtID, err))
+			continue
+		}
+		sent++
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// debtReminderDueDate returns the debt's next due date if it falls inside the
+// owner's reminder window, counting today as day 0.
+func debtReminderDueDate(debt repository.ReminderDebt, now time.Time) (time.Time, bool) {
+	if debt.DueDay < 1 {
+		return time.Time{}, false
+	}
+
+	due := nextDueDate(debt.DueDay, now)
+	if due.Before(dateOnly(debt.StartDate)) {
+		return time.Time{}, false // No payment is due before the debt starts
+	}
+	days := daysUntil(due, now)
+	if days < 0 || days > debt.DaysBefore {
+		return time.Time{}, false
+	}
+	return due, true
+}
+
+// nextDueDate returns the first date on or after now's date that falls on dueDay of
+// its month. In months shorter than dueDay the payment is due on the last day.
+func nextDueDate(dueDay int, now time.Time) time.Time {
+	today := dateOnly(now)
+	due := dueDateInMonth(today.Year(), today.Month(), dueDay)
+	if due.Before(today) {
+		due = dueDateInMonth(today.Year(), today.Month()+1, dueDay)
+	}
+	return due
+}
+
+// dueDateInMonth returns dueDay of the month, clamped to the month's last day
+func dueDateInMonth(year int, month time.Month, dueDay int) time.Time {
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if dueDay > lastDay {
+		dueDay = lastDay
+	}
+	return time.Date(year, month, dueDay, 0, 0, 0, 0, time.UTC)
+}
+
+// dateOnly returns the calendar date of t as midnight UTC, matching how DATE
+// columns are scanned
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// daysUntil returns the number of calendar days from now's date to due's date
+func daysUntil(due, now time.Time) int {
+	return int(dateOnly(due).Sub(dateOnly(now)).Hours() / 24)
+}
+
+// dueInText describes how soon something is due
+func dueInText(days int) string {
+	switch {
+	case days <= 0:
+		return "Due today"
+	case days == 1:
+		return "Due tomorrow"
+	default:
+		return fmt.Sprintf("Due in %d days", days)
+	}
+}
+
+// SendBudgetAlert sends a budget overspending alert. Threshold and exceeded alerts
+// are logged under separate types so crossing 100% is not suppressed by an
+// earlier threshold alert on the same day.
 func (s *PushNotificationService) SendBudgetAlert(ctx context.Context, userID uuid.UUID, category string, percentage int, budgetID uuid.UUID) error {
+	notifType := model.NotificationTypeBudgetAlert
+	if percentage >= 100 {
+		notifType = model.NotificationTypeBudgetExceeded
+	}
+
 	// Check if we've already sent this notification today
 	today := time.Now().Truncate(24 * time.Hour)
-	hasRecent, err := s.repo.HasRecentNotification(ctx, userID, model.NotificationTypeBudgetAlert, &budgetID, &today)
+	hasRecent, err := s.repo.HasRecentNotification(ctx, userID, notifType, &budgetID, &today)
 	if err != nil {
 		return err
 	}
@@ -256,7 +444,7 @@ func (s *PushNotificationService) SendBudgetAlert(ctx context.Context, userID uu
 		body = "You've exceeded your budget for " + category
 	} else {
 		title = "Budget Alert: " + category
-		body = "You've used " + string(rune(percentage/10+'0')) + string(rune(percentage%10+'0')) + "% of your " + category + " budget"
+		body = fmt.Sprintf("You've used %d%% of your %s budget", percentage, category)
 	}
 
 	payload := &NotificationPayload{
@@ -278,7 +466,7 @@ func (s *PushNotificationService) SendBudgetAlert(ctx context.Context, userID uu
 	log := &model.NotificationLog{
 		ID:               uuid.New(),
 		UserID:           userID,
-		NotificationType: model.NotificationTypeBudgetAlert,
+		NotificationType: notifType,
 		ReferenceID:      &budgetID,
 		ReferenceDate:    &today,
 		Title:            payload.Title,
@@ -295,17 +483,18 @@ func (s *PushNotificationService) SendBudgetAlert(ctx context.Context, userID uu
 	return err
 }
 
-// SendGoalMilestone sends a savings goal milestone notification
+// SendGoalMilestone sends a savings goal milestone notification. Each milestone
+// of a goal is sent once.
 func (s *PushNotificationService) SendGoalMilestone(ctx context.Context, userID uuid.UUID, goalName string, percentage int, goalID uuid.UUID) error {
-	// Check if we've already sent this milestone notification
-	today := time.Now().Truncate(24 * time.Hour)
-	hasRecent, err := s.repo.HasRecentNotification(ctx, userID, model.NotificationTypeGoalMilestone, &goalID, &today)
+	// A goal that drops below a milestone and crosses it again is not re-announced
+	sent, err := s.repo.HasMilestoneNotification(ctx, userID, goalID, percentage)
 	if err != nil {
 		return err
 	}
-	if hasRecent {
+	if sent {
 		return nil // Already notified
 	}
+	today := time.Now().Truncate(24 * time.Hour)
 
 	var title, body string
 	if percentage >= 100 {
@@ -313,7 +502,7 @@ func (s *PushNotificationService) SendGoalMilestone(ctx context.Context, userID
 		body = "Congratulations! You've reached your goal: " + goalName
 	} else {
 		title = "Milestone Reached: " + goalName
-		body = "You're " + string(rune(percentage/10+'0')) + string(rune(percentage%10+'0')) + "% of the way to your goal!"
+		body = fmt.Sprintf("You're %d%% of the way to your goal!", percentage)
 	}
 
 	payload := &NotificationPayload{
@@ -338,6 +527,7 @@ func (s *PushNotificationService) SendGoalMilestone(ctx context.Context, userID
 		NotificationType: model.NotificationTypeGoalMilestone,
 		ReferenceID:      &goalID,
 		ReferenceDate:    &today,
+		Milestone:        &percentage,
 		Title:            payload.Title,
 		Body:             payload.Body,
 		SentAt:           time.Now(),
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wealthpath/backend/internal/config"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+)
+
+// MockPushRepo implements PushRepositoryInterface for testing
+type MockPushRepo struct {
+	mock.Mock
+}
+
+func (m *MockPushRepo) CreateSubscription(ctx context.Context, sub *model.PushSubscription) error {
+	args := m.Called(ctx, sub)
+	return args.Error(0)
+}
+
+func (m *MockPushRepo) GetSubscriptionsByUserID(ctx context.Context, userID uuid.UUID) ([]model.PushSubscription, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PushSubscription), args.Error(1)
+}
+
+func (m *MockPushRepo) GetAllActiveSubscriptions(ctx context.Context) ([]model.PushSubscription, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PushSubscription), args.Error(1)
+}
+
+func (m *MockPushRepo) DeleteSubscription(ctx context.Context, userID uuid.UUID, endpoint string) error {
+	args := m.Called(ctx, userID, endpoint)
+	return args.Error(0)
+}
+
+func (m *MockPushRepo) DeleteSubscriptionByEndpoint(ctx context.Context, endpoint string) error {
+	args := m.Called(ctx, endpoint)
+	return args.Error(0)
+}
+
+func (m *MockPushRepo) GetPreferences(ctx context.Context, userID uuid.UUID) (*model.NotificationPreferences, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockPushRepo) UpsertPreferences(ctx context.Context, prefs *model.NotificationPreferences) error {
+	args := m.Called(ctx, prefs)
+	return args.Error(0)
+}
+
+func (m *MockPushRepo) LogNotification(ctx context.Context, log *model.NotificationLog) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
+func (m *MockPushRepo) HasRecentNotification(ctx context.Context, userID uuid.UUID, notifType model.NotificationType, refID *uuid.UUID, refDate *time.Time) (bool, error) {
+	args := m.Called(ctx, userID, notifType, refID, refDate)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPushRepo) HasMilestoneNotification(ctx context.Context, userID, goalID uuid.UUID, milestone int) (bool, error) {
+	args := m.Called(ctx, userID, goalID, milestone)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPushRepo) HasNotificationForDate(ctx context.Context, userID uuid.UUID, notifType model.NotificationType, refID *uuid.UUID, refDate time.Time) (bool, error) {
+	args := m.Called(ctx, userID, notifType, refID, refDate)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPushRepo) GetDueBills(ctx context.Context) ([]repository.DueBill, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.DueBill), args.Error(1)
+}
+
+func (m *MockPushRepo) GetDebtsForReminders(ctx context.Context) ([]repository.ReminderDebt, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.ReminderDebt), args.Error(1)
+}
+
+func newTestPushService(repo PushRepositoryInterface) *PushNotificationService {
+	return NewPushNotificationService(repo, &config.Config{
+		VAPIDPublicKey:  "test-public-key",
+		VAPIDPrivateKey: "test-private-key",
+	})
+}
+
+func TestNextDueDate(t *testing.T) {
+	t.Parallel()
+
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name   string
+		dueDay int
+		now    time.Time
+		want   time.Time
+	}{
+		{"later this month", 15, date(2024, 3, 10), date(2024, 3, 15)},
+		{"due today", 15, date(2024, 3, 15).Add(18 * time.Hour), date(2024, 3, 15)},
+		{"already passed rolls to next month", 5, date(2024, 3, 10), date(2024, 4, 5)},
+		{"31st in a 30-day month", 31, date(2024, 4, 10), date(2024, 4, 30)},
+		{"31st in February of a leap year", 31, date(2024, 2, 10), date(2024, 2, 29)},
+		{"30th in February", 30, date(2023, 2, 1), date(2023, 2, 28)},
+		{"clamped due date is today", 30, date(2024, 2, 29).Add(time.Hour), date(2024, 2, 29)},
+		{"31st after January rolls to end of February", 31, date(2025, 2, 1), date(2025, 2, 28)},
+		{"December rolls into next year", 3, date(2024, 12, 20), date(2025, 1, 3)},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, nextDueDate(tt.dueDay, tt.now))
+		})
+	}
+}
+
+func TestDebtReminderDueDate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 4, 27, 9, 0, 0, 0, time.UTC)
+	startDate := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		debt    repository.ReminderDebt
+		wantDue time.Time
+		wantOK  bool
+	}{
+		{
+			name:    "31st due on the 30th, inside window",
+			debt:    repository.ReminderDebt{DueDay: 31, DaysBefore: 3, StartDate: startDate},
+			wantDue: time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC),
+			wantOK:  true,
+		},
+		{
+			name:   "outside window",
+			debt:   repository.ReminderDebt{DueDay: 31, DaysBefore: 2, StartDate: startDate},
+			wantOK: false,
+		},
+		{
+			name:    "due today with zero days before",
+			debt:    repository.ReminderDebt{DueDay: 27, DaysBefore: 0, StartDate: startDate},
+			wantDue: time.Date(2024, 4, 27, 0, 0, 0, 0, time.UTC),
+			wantOK:  true,
+		},
+		{
+			name:   "debt starts after the due date",
+			debt:   repository.ReminderDebt{DueDay: 28, DaysBefore: 3, StartDate: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+			wantOK: false,
+		},
+		{
+			name:   "invalid due day",
+			debt:   repository.ReminderDebt{DueDay: 0, DaysBefore: 3, StartDate: startDate},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			due, ok := debtReminderDueDate(tt.debt, now)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.wantDue, due)
+			}
+		})
+	}
+}
+
+func TestDueInText(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Due today", dueInText(0))
+	assert.Equal(t, "Due tomorrow", dueInText(1))
+	assert.Equal(t, "Due in 5 days", dueInText(5))
+	assert.Equal(t, "Due in 12 days", dueInText(12))
+}
+
+func TestPushNotificationService_SendDebtReminder(t *testing.T) {
+	t.Parallel()
+
+	dueDate := time.Now().AddDate(0, 0, 2)
+
+	tests := []struct {
+		name        string
+		alreadySent bool
+	}{
+		{name: "sends and logs", alreadySent: false},
+		{name: "already reminded for this due date", alreadySent: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockPushRepo)
+			svc := newTestPushService(repo)
+			userID := uuid.New()
+			debtID := uuid.New()
+
+			repo.On("HasNotificationForDate", mock.Anything, userID, model.NotificationTypeDebtReminder, &debtID, dueDate).Return(tt.alreadySent, nil)
+			if !tt.alreadySent {
+				repo.On("GetSubscriptionsByUserID", mock.Anything, userID).Return([]model.PushSubscription{}, nil)
+				repo.On("LogNotification", mock.Anything, mock.MatchedBy(func(l *model.NotificationLog) bool {
+					return l.NotificationType == model.NotificationTypeDebtReminder &&
+						*l.ReferenceID == debtID && l.ReferenceDate.Equal(dueDate) &&
+						l.Title == "Payment Due: Visa" &&
+						l.Body == "Minimum payment $50.00 - Due in 2 days"
+				})).Return(nil)
+			}
+
+			err := svc.SendDebtReminder(context.Background(), userID, "Visa", "$50.00", dueDate, debtID)
+
+			if tt.alreadySent {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrNoSubscriptions)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPushNotificationService_SendGoalMilestone(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		alreadySent bool
+	}{
+		{name: "sends and logs the milestone", alreadySent: false},
+		{name: "milestone sent before", alreadySent: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockPushRepo)
+			svc := newTestPushService(repo)
+			userID := uuid.New()
+			goalID := uuid.New()
+
+			repo.On("HasMilestoneNotification", mock.Anything, userID, goalID, 75).Return(tt.alreadySent, nil)
+			if !tt.alreadySent {
+				repo.On("GetSubscriptionsByUserID", mock.Anything, userID).Return([]model.PushSubscription{}, nil)
+				repo.On("LogNotification", mock.Anything, mock.MatchedBy(func(l *model.NotificationLog) bool {
+					return l.NotificationType == model.NotificationTypeGoalMilestone &&
+						*l.ReferenceID == goalID && *l.Milestone == 75 &&
+						l.Body == "You're 75% of the way to your goal!"
+				})).Return(nil)
+			}
+
+			err := svc.SendGoalMilestone(context.Background(), userID, "Vacation", 75, goalID)
+
+			if tt.alreadySent {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrNoSubscriptions)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPushNotificationService_SendDueDebtReminders(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockPushRepo)
+	svc := newTestPushService(repo)
+	userID := uuid.New()
+	dueSoon := uuid.New()
+	dueLater := uuid.New()
+	today := time.Now()
+
+	repo.On("GetDebtsForReminders", mock.Anything).Return([]repository.ReminderDebt{
+		{DebtID: dueSoon, UserID: userID, Name: "Mortgage", MinimumPayment: decimal.NewFromInt(1200), Currency: "USD", DueDay: today.Day(), DaysBefore: 3},
+		{DebtID: dueLater, UserID: userID, Name: "Car loan", MinimumPayment: decimal.NewFromInt(300), Currency: "USD", DueDay: today.AddDate(0, 0, 10).Day(), DaysBefore: 3},
+	}, nil)
+	repo.On("HasNotificationForDate", mock.Anything, userID, model.NotificationTypeDebtReminder, &dueSoon, dateOnly(today)).Return(true, nil)
+
+	sent, err := svc.SendDueDebtReminders(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "HasNotificationForDate", mock.Anything, mock.Anything, mock.Anything, &dueLater, mock.Anything)
+}
@@ -60,6 +60,7 @@ type CreateRecurringInput struct {
 	Frequency   model.RecurringFrequency `json:"frequency"`
 	StartDate   time.Time                `json:"startDate"`
 	EndDate     *time.Time               `json:"endDate"`
+	Tags        []string                 `json:"tags"`
 }
 
 type UpdateRecurringInput struct {
@@ -72,10 +73,11 @@ type UpdateRecurringInput struct {
 	StartDate   *time.Time                `json:"startDate"`
 	EndDate     *time.Time                `json:"endDate"`
 	IsActive    *bool                     `json:"isActive"`
+	Tags        *[]string                 `json:"tags"`
 }
 
 // Create creates a new recurring transaction for the given user.
-// Validates amount, type, and frequency before creation.
+// Validates amount, type, frequency and tags before creation.
 func (s *RecurringService) Create(ctx context.Context, userID uuid.UUID, input CreateRecurringInput) (*model.RecurringTransaction, error) {
 	if input.Amount.LessThanOrEqual(decimal.Zero) {
 		return nil, ErrInvalidAmount
@@ -89,6 +91,11 @@ func (s *RecurringService) Create(ctx context.Context, userID uuid.UUID, input C
 		return nil, ErrInvalidFrequency
 	}
 
+	tags, err := normalizeTags(input.Tags)
+	if err != nil {
+		return nil, err
+	}
+
 	rt := &model.RecurringTransaction{
 		UserID:         userID,
 		Type:           input.Type,
@@ -101,6 +108,7 @@ func (s *RecurringService) Create(ctx context.Context, userID uuid.UUID, input C
 		EndDate:        input.EndDate,
 		NextOccurrence: input.StartDate,
 		IsActive:       true,
+		Tags:           tags,
 	}
 
 	if rt.Currency == "" {
@@ -181,6 +189,13 @@ func (s *RecurringService) Update(ctx context.Context, userID, id uuid.UUID, inp
 	if input.IsActive != nil {
 		rt.IsActive = *input.IsActive
 	}
+	if input.Tags != nil {
+		tags, err := normalizeTags(*input.Tags)
+		if err != nil {
+			return nil, err
+		}
+		rt.Tags = tags
+	}
 
 	if err := s.recurringRepo.Update(ctx, rt); err != nil {
 		return nil, fmt.Errorf("updating recurring transaction %s: %w", id, err)
@@ -229,6 +244,7 @@ func (s *RecurringService) GetUpcoming(ctx context.Context, userID uuid.UUID, li
 }
 
 // ProcessDueTransactions generates transactions for all due recurring items.
+// Generated transactions carry the tags of their recurring item.
 // This should be called by a cron job. Returns the count of processed items.
 func (s *RecurringService) ProcessDueTransactions(ctx context.Context) (int, error) {
 	now := time.Now()
@@ -247,6 +263,7 @@ func (s *RecurringService) ProcessDueTransactions(ctx context.Context) (int, err
 			Category:    rt.Category,
 			Description: rt.Description + " (recurring)",
 			Date:        rt.NextOccurrence,
+			Tags:        rt.Tags,
 		}
 
 		if err := s.transactionRepo.Create(ctx, tx); err != nil {
@@ -731,6 +731,61 @@ func TestRecurringService_ProcessDueTransactions(t *testing.T) {
 	mockTxRepo.AssertExpectations(t)
 }
 
+func TestRecurringService_Tags(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	t.Run("generated transactions carry the tags", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := new(MockRecurringRepo)
+		mockTxRepo := new(MockTransactionCreator)
+		service := NewRecurringService(mockRepo, mockTxRepo)
+
+		mockRepo.On("GetDueTransactions", mock.Anything, mock.Anything).Return([]model.RecurringTransaction{{
+			ID: uuid.New(), UserID: userID, Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(15),
+			Category: "Entertainment", Description: "Streaming", Frequency: model.FrequencyMonthly,
+			NextOccurrence: time.Now(), Tags: []string{"subscriptions"},
+		}}, nil)
+		mockTxRepo.On("Create", mock.Anything, mock.MatchedBy(func(tx *model.Transaction) bool {
+			return len(tx.Tags) == 1 && tx.Tags[0] == "subscriptions"
+		})).Return(nil)
+		mockRepo.On("UpdateLastGenerated", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		count, err := service.ProcessDueTransactions(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		mockTxRepo.AssertExpectations(t)
+	})
+
+	t.Run("update keeps tags unless given", func(t *testing.T) {
+		t.Parallel()
+
+		mockRepo := new(MockRecurringRepo)
+		service := NewRecurringService(mockRepo, new(MockTransactionCreator))
+		id := uuid.New()
+
+		mockRepo.On("GetByID", mock.Anything, id).Return(&model.RecurringTransaction{
+			ID: id, UserID: userID, Frequency: model.FrequencyMonthly, Tags: []string{"home"},
+		}, nil)
+		mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.RecurringTransaction")).Return(nil)
+
+		description := "Rent"
+		rt, err := service.Update(context.Background(), userID, id, UpdateRecurringInput{Description: &description})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"home"}, rt.Tags)
+
+		_, err = service.Update(context.Background(), userID, id, UpdateRecurringInput{Tags: &[]string{"bad,tag"}})
+		assert.ErrorIs(t, err, ErrInvalidTag)
+
+		rt, err = service.Update(context.Background(), userID, id, UpdateRecurringInput{Tags: &[]string{}})
+		assert.NoError(t, err)
+		assert.Empty(t, rt.Tags)
+	})
+}
+
 func TestRecurringService_ProcessDueTransactions_Error(t *testing.T) {
 	t.Parallel()
 
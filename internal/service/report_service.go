@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/pkg/currency"
 )
 
 // AnomalyThreshold is the percentage increase that triggers an anomaly detection (50%).
@@ -82,15 +84,34 @@ type CategoryTrendsResponse struct {
 	GeneratedAt string          `json:"generatedAt"`
 }
 
+// TagBreakdown represents income and expenses for a single tag.
+type TagBreakdown struct {
+	Tag              string          `json:"tag"`
+	TotalIncome      string          `json:"totalIncome"`
+	TotalExpenses    string          `json:"totalExpenses"`
+	TransactionCount int             `json:"transactionCount"`
+	MonthlyExpenses  []MonthlyAmount `json:"monthlyExpenses"`
+}
+
+// TagBreakdownResponse represents the response for the tag breakdown.
+type TagBreakdownResponse struct {
+	Currency    string         `json:"currency"`
+	PeriodStart string         `json:"periodStart"`
+	PeriodEnd   string         `json:"periodEnd"`
+	Tags        []TagBreakdown `json:"tags"`
+	GeneratedAt string         `json:"generatedAt"`
+}
+
 // ReportRepositoryInterface defines the contract for report data access.
 type ReportRepositoryInterface interface {
 	GetMonthlyTotals(ctx context.Context, userID uuid.UUID, year, month int) (decimal.Decimal, decimal.Decimal, error)
-	GetTopExpenseCategories(ctx context.Context, userID uuid.UUID, year, month int, limit int) ([]repository.CategoryTotal, error)
+	GetTopExpenseCategories(ctx context.Context, userID uuid.UUID, year, month int, limit int, byParent bool) ([]repository.CategoryTotal, error)
 	GetCategoryAverageForPeriod(ctx context.Context, userID uuid.UUID, category string, startDate, endDate time.Time) (decimal.Decimal, int, error)
 	GetIncomeCategoryAverageForPeriod(ctx context.Context, userID uuid.UUID, category string, startDate, endDate time.Time) (decimal.Decimal, int, error)
 	GetCategoryAmountForMonth(ctx context.Context, userID uuid.UUID, category string, year, month int) (decimal.Decimal, error)
 	GetIncomeCategoryAmountForMonth(ctx context.Context, userID uuid.UUID, category string, year, month int) (decimal.Decimal, error)
-	GetCategoryTrendsData(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, categoryLimit int) ([]repository.CategoryMonthlyAmount, error)
+	GetCategoryTrendsData(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, categoryLimit int, byParent bool) ([]repository.CategoryMonthlyAmount, error)
+	GetTagMonthlyAmounts(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]repository.TagMonthlyAmount, error)
 	GetDistinctExpenseCategories(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]string, error)
 	GetDistinctIncomeCategories(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]string, error)
 	GetUserCurrency(ctx context.Context, userID uuid.UUID) (string, error)
@@ -107,10 +128,11 @@ func NewReportService(reportRepo ReportRepositoryInterface) *ReportService {
 }
 
 // GetMonthlyReport generates a comprehensive monthly financial report.
-func (s *ReportService) GetMonthlyReport(ctx context.Context, userID uuid.UUID, year, month int) (*MonthlyReport, error) {
-	currency, err := s.reportRepo.GetUserCurrency(ctx, userID)
+// With byParent, the top categories roll subcategories up into their parent.
+func (s *ReportService) GetMonthlyReport(ctx context.Context, userID uuid.UUID, year, month int, byParent bool) (*MonthlyReport, error) {
+	curr, err := s.reportRepo.GetUserCurrency(ctx, userID)
 	if err != nil {
-		currency = "USD"
+		curr = string(currency.DefaultCurrency)
 	}
 
 	// Get current month totals
@@ -127,7 +149,7 @@ func (s *ReportService) GetMonthlyReport(ctx context.Context, userID uuid.UUID,
 	}
 
 	// Get top expense categories
-	topCats, err := s.reportRepo.GetTopExpenseCategories(ctx, userID, year, month, 5)
+	topCats, err := s.reportRepo.GetTopExpenseCategories(ctx, userID, year, month, 5, byParent)
 	if err != nil {
 		return nil, fmt.Errorf("getting top categories: %w", err)
 	}
@@ -141,14 +163,14 @@ func (s *ReportService) GetMonthlyReport(ctx context.Context, userID uuid.UUID,
 		}
 		topCategories[i] = TopCategory{
 			Category:         cat.Category,
-			Amount:           cat.Amount.StringFixed(2),
+			Amount:           formatAmount(cat.Amount, curr),
 			Percentage:       percentage,
 			TransactionCount: cat.TransactionCount,
 		}
 	}
 
 	// Detect anomalies
-	anomalies, err := s.detectAnomalies(ctx, userID, year, month)
+	anomalies, err := s.detectAnomalies(ctx, userID, year, month, curr)
 	if err != nil {
 		anomalies = []Anomaly{}
 	}
@@ -162,10 +184,10 @@ func (s *ReportService) GetMonthlyReport(ctx context.Context, userID uuid.UUID,
 	return &MonthlyReport{
 		Year:           year,
 		Month:          month,
-		Currency:       currency,
-		TotalIncome:    income.StringFixed(2),
-		TotalExpenses:  expenses.StringFixed(2),
-		NetSavings:     netSavings.StringFixed(2),
+		Currency:       curr,
+		TotalIncome:    formatAmount(income, curr),
+		TotalExpenses:  formatAmount(expenses, curr),
+		NetSavings:     formatAmount(netSavings, curr),
 		SavingsRate:    savingsRate,
 		TopCategories:  topCategories,
 		Anomalies:      anomalies,
@@ -175,7 +197,8 @@ func (s *ReportService) GetMonthlyReport(ctx context.Context, userID uuid.UUID,
 }
 
 // detectAnomalies identifies unusual spending patterns by comparing current month to 3-month average.
-func (s *ReportService) detectAnomalies(ctx context.Context, userID uuid.UUID, year, month int) ([]Anomaly, error) {
+// Amounts are in the user's currency curr.
+func (s *ReportService) detectAnomalies(ctx context.Context, userID uuid.UUID, year, month int, curr string) ([]Anomaly, error) {
 	var anomalies []Anomaly
 
 	currentMonthStart := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
@@ -211,7 +234,7 @@ func (s *ReportService) detectAnomalies(ctx context.Context, userID uuid.UUID, y
 				anomalies = append(anomalies, Anomaly{
 					Type:        "unusual_expense",
 					Category:    category,
-					Amount:      currentAmount.StringFixed(2),
+					Amount:      formatAmount(currentAmount, curr),
 					Description: fmt.Sprintf("Spending %s%% higher than your %d-month average", percentStr, AnomalyHistoryMonths),
 					Severity:    severity,
 				})
@@ -241,8 +264,8 @@ func (s *ReportService) detectAnomalies(ctx context.Context, userID uuid.UUID, y
 			anomalies = append(anomalies, Anomaly{
 				Type:        "missed_income",
 				Category:    category,
-				Amount:      "0.00",
-				Description: fmt.Sprintf("No %s income recorded this month (usually $%s/month)", category, avgIncome.StringFixed(0)),
+				Amount:      formatAmount(decimal.Zero, curr),
+				Description: fmt.Sprintf("No %s income recorded this month (usually %s/month)", category, currency.NewMoney(avgIncome.Round(0), currency.Currency(curr)).Format()),
 				Severity:    "info",
 			})
 		}
@@ -317,7 +340,8 @@ func determineTrend(incomeChange, expenseChange, savingsChange float64) string {
 }
 
 // GetCategoryTrends retrieves spending trends by category over multiple months.
-func (s *ReportService) GetCategoryTrends(ctx context.Context, userID uuid.UUID, months, categoryLimit int) (*CategoryTrendsResponse, error) {
+// With byParent, subcategories roll up into their parent category.
+func (s *ReportService) GetCategoryTrends(ctx context.Context, userID uuid.UUID, months, categoryLimit int, byParent bool) (*CategoryTrendsResponse, error) {
 	if months <= 0 {
 		months = 6
 	}
@@ -331,9 +355,9 @@ func (s *ReportService) GetCategoryTrends(ctx context.Context, userID uuid.UUID,
 		categoryLimit = 20
 	}
 
-	currency, err := s.reportRepo.GetUserCurrency(ctx, userID)
+	curr, err := s.reportRepo.GetUserCurrency(ctx, userID)
 	if err != nil {
-		currency = "USD"
+		curr = string(currency.DefaultCurrency)
 	}
 
 	now := time.Now()
@@ -342,7 +366,7 @@ func (s *ReportService) GetCategoryTrends(ctx context.Context, userID uuid.UUID,
 	periodStart := currentMonthStart.AddDate(0, -months+1, 0)
 
 	// Get category trends data
-	trendsData, err := s.reportRepo.GetCategoryTrendsData(ctx, userID, periodStart, periodEnd, categoryLimit)
+	trendsData, err := s.reportRepo.GetCategoryTrendsData(ctx, userID, periodStart, periodEnd, categoryLimit, byParent)
 	if err != nil {
 		return nil, fmt.Errorf("getting category trends data: %w", err)
 	}
@@ -385,7 +409,7 @@ func (s *ReportService) GetCategoryTrends(ctx context.Context, userID uuid.UUID,
 
 			monthlyData = append(monthlyData, MonthlyAmount{
 				Month:  monthStr,
-				Amount: amount.StringFixed(2),
+				Amount: formatAmount(amount, curr),
 			})
 		}
 
@@ -398,8 +422,8 @@ func (s *ReportService) GetCategoryTrends(ctx context.Context, userID uuid.UUID,
 
 		trends = append(trends, CategoryTrend{
 			Category:        category,
-			TotalAmount:     total.StringFixed(2),
-			AverageAmount:   average.StringFixed(2),
+			TotalAmount:     formatAmount(total, curr),
+			AverageAmount:   formatAmount(average, curr),
 			TrendDirection:  trendDirection,
 			TrendPercentage: trendPercentage,
 			MonthlyData:     monthlyData,
@@ -407,7 +431,7 @@ func (s *ReportService) GetCategoryTrends(ctx context.Context, userID uuid.UUID,
 	}
 
 	return &CategoryTrendsResponse{
-		Currency:    currency,
+		Currency:    curr,
 		PeriodStart: periodStart.Format("2006-01-02"),
 		PeriodEnd:   periodEnd.AddDate(0, 0, -1).Format("2006-01-02"),
 		Trends:      trends,
@@ -415,6 +439,86 @@ func (s *ReportService) GetCategoryTrends(ctx context.Context, userID uuid.UUID,
 	}, nil
 }
 
+// GetTagBreakdown retrieves income and expenses by tag over multiple months,
+// ordered by total expenses. A transaction with several tags counts towards each.
+func (s *ReportService) GetTagBreakdown(ctx context.Context, userID uuid.UUID, months int) (*TagBreakdownResponse, error) {
+	if months <= 0 {
+		months = 6
+	}
+	if months > 24 {
+		months = 24
+	}
+
+	curr, err := s.reportRepo.GetUserCurrency(ctx, userID)
+	if err != nil {
+		curr = string(currency.DefaultCurrency)
+	}
+
+	now := time.Now()
+	currentMonthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := currentMonthStart.AddDate(0, 1, 0)
+	periodStart := currentMonthStart.AddDate(0, -months+1, 0)
+
+	amounts, err := s.reportRepo.GetTagMonthlyAmounts(ctx, userID, periodStart, periodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("getting tag amounts: %w", err)
+	}
+
+	type tagTotals struct {
+		income, expenses decimal.Decimal
+		count            int
+		monthly          map[string]decimal.Decimal
+	}
+	var order []string
+	totals := make(map[string]*tagTotals)
+	for _, item := range amounts {
+		t, ok := totals[item.Tag]
+		if !ok {
+			t = &tagTotals{monthly: make(map[string]decimal.Decimal)}
+			totals[item.Tag] = t
+			order = append(order, item.Tag)
+		}
+		t.income = t.income.Add(item.Income)
+		t.expenses = t.expenses.Add(item.Expenses)
+		t.count += item.TransactionCount
+		t.monthly[item.Month] = item.Expenses
+	}
+
+	allMonths := generateMonthRange(periodStart, months)
+	tags := make([]TagBreakdown, 0, len(order))
+	for _, tag := range order {
+		t := totals[tag]
+		monthly := make([]MonthlyAmount, len(allMonths))
+		for i, month := range allMonths {
+			monthly[i] = MonthlyAmount{Month: month, Amount: formatAmount(t.monthly[month], curr)}
+		}
+		tags = append(tags, TagBreakdown{
+			Tag:              tag,
+			TotalIncome:      formatAmount(t.income, curr),
+			TotalExpenses:    formatAmount(t.expenses, curr),
+			TransactionCount: t.count,
+			MonthlyExpenses:  monthly,
+		})
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return totals[tags[i].Tag].expenses.GreaterThan(totals[tags[j].Tag].expenses)
+	})
+
+	return &TagBreakdownResponse{
+		Currency:    curr,
+		PeriodStart: periodStart.Format("2006-01-02"),
+		PeriodEnd:   periodEnd.AddDate(0, 0, -1).Format("2006-01-02"),
+		Tags:        tags,
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// formatAmount formats an amount with the decimal places of the currency,
+// e.g. "1234.50" for USD and "1234" for VND.
+func formatAmount(amount decimal.Decimal, curr string) string {
+	return currency.NewMoney(amount, currency.Currency(curr)).StringFixed()
+}
+
 // generateMonthRange generates a slice of month strings (YYYY-MM) for the given period.
 func generateMonthRange(start time.Time, months int) []string {
 	result := make([]string, months)
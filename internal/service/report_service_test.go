@@ -23,8 +23,8 @@ func (m *MockReportRepository) GetMonthlyTotals(ctx context.Context, userID uuid
 	return args.Get(0).(decimal.Decimal), args.Get(1).(decimal.Decimal), args.Error(2)
 }
 
-func (m *MockReportRepository) GetTopExpenseCategories(ctx context.Context, userID uuid.UUID, year, month int, limit int) ([]repository.CategoryTotal, error) {
-	args := m.Called(ctx, userID, year, month, limit)
+func (m *MockReportRepository) GetTopExpenseCategories(ctx context.Context, userID uuid.UUID, year, month int, limit int, byParent bool) ([]repository.CategoryTotal, error) {
+	args := m.Called(ctx, userID, year, month, limit, byParent)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -51,14 +51,22 @@ func (m *MockReportRepository) GetIncomeCategoryAmountForMonth(ctx context.Conte
 	return args.Get(0).(decimal.Decimal), args.Error(1)
 }
 
-func (m *MockReportRepository) GetCategoryTrendsData(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, categoryLimit int) ([]repository.CategoryMonthlyAmount, error) {
-	args := m.Called(ctx, userID, startDate, endDate, categoryLimit)
+func (m *MockReportRepository) GetCategoryTrendsData(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, categoryLimit int, byParent bool) ([]repository.CategoryMonthlyAmount, error) {
+	args := m.Called(ctx, userID, startDate, endDate, categoryLimit, byParent)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]repository.CategoryMonthlyAmount), args.Error(1)
 }
 
+func (m *MockReportRepository) GetTagMonthlyAmounts(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]repository.TagMonthlyAmount, error) {
+	args := m.Called(ctx, userID, startDate, endDate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.TagMonthlyAmount), args.Error(1)
+}
+
 func (m *MockReportRepository) GetDistinctExpenseCategories(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]string, error) {
 	args := m.Called(ctx, userID, startDate, endDate)
 	if args.Get(0) == nil {
@@ -75,6 +83,11 @@ func (m *MockReportRepository) GetDistinctIncomeCategories(ctx context.Context,
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *MockReportRepository) CountUnconverted(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) (int, error) {
+	args := m.Called(ctx, userID, startDate, endDate)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockReportRepository) GetUserCurrency(ctx context.Context, userID uuid.UUID) (string, error) {
 	args := m.Called(ctx, userID)
 	return args.String(0), args.Error(1)
@@ -111,7 +124,7 @@ func TestReportService_GetMonthlyReport(t *testing.T) {
 					decimal.NewFromFloat(5200),
 					nil,
 				)
-				repo.On("GetTopExpenseCategories", mock.Anything, userID, 2026, 1, 5).Return(
+				repo.On("GetTopExpenseCategories", mock.Anything, userID, 2026, 1, 5, false).Return(
 					[]repository.CategoryTotal{
 						{Category: "Housing", Amount: decimal.NewFromFloat(1800), TransactionCount: 2},
 						{Category: "Food & Dining", Amount: decimal.NewFromFloat(850), TransactionCount: 24},
@@ -176,7 +189,7 @@ func TestReportService_GetMonthlyReport(t *testing.T) {
 					decimal.Zero,
 					nil,
 				)
-				repo.On("GetTopExpenseCategories", mock.Anything, userID, 2026, 1, 5).Return(
+				repo.On("GetTopExpenseCategories", mock.Anything, userID, 2026, 1, 5, false).Return(
 					[]repository.CategoryTotal{},
 					nil,
 				)
@@ -232,7 +245,7 @@ func TestReportService_GetMonthlyReport(t *testing.T) {
 
 			tt.setupMock(mockRepo, userID)
 
-			report, err := svc.GetMonthlyReport(context.Background(), userID, tt.year, tt.month)
+			report, err := svc.GetMonthlyReport(context.Background(), userID, tt.year, tt.month, false)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -265,7 +278,7 @@ func TestReportService_GetCategoryTrends(t *testing.T) {
 			limit:  5,
 			setupMock: func(repo *MockReportRepository, userID uuid.UUID) {
 				repo.On("GetUserCurrency", mock.Anything, userID).Return("USD", nil)
-				repo.On("GetCategoryTrendsData", mock.Anything, userID, mock.Anything, mock.Anything, 5).Return(
+				repo.On("GetCategoryTrendsData", mock.Anything, userID, mock.Anything, mock.Anything, 5, false).Return(
 					[]repository.CategoryMonthlyAmount{
 						{Category: "Housing", Month: "2025-08", Amount: decimal.NewFromFloat(1800)},
 						{Category: "Housing", Month: "2025-09", Amount: decimal.NewFromFloat(1800)},
@@ -289,7 +302,7 @@ func TestReportService_GetCategoryTrends(t *testing.T) {
 			limit:  10,
 			setupMock: func(repo *MockReportRepository, userID uuid.UUID) {
 				repo.On("GetUserCurrency", mock.Anything, userID).Return("USD", nil)
-				repo.On("GetCategoryTrendsData", mock.Anything, userID, mock.Anything, mock.Anything, 10).Return(
+				repo.On("GetCategoryTrendsData", mock.Anything, userID, mock.Anything, mock.Anything, 10, false).Return(
 					[]repository.CategoryMonthlyAmount{},
 					nil,
 				)
@@ -305,7 +318,7 @@ func TestReportService_GetCategoryTrends(t *testing.T) {
 			limit:  10,
 			setupMock: func(repo *MockReportRepository, userID uuid.UUID) {
 				repo.On("GetUserCurrency", mock.Anything, userID).Return("USD", nil)
-				repo.On("GetCategoryTrendsData", mock.Anything, userID, mock.Anything, mock.Anything, 10).Return(
+				repo.On("GetCategoryTrendsData", mock.Anything, userID, mock.Anything, mock.Anything, 10, false).Return(
 					nil,
 					errors.New("database error"),
 				)
@@ -326,7 +339,7 @@ func TestReportService_GetCategoryTrends(t *testing.T) {
 
 			tt.setupMock(mockRepo, userID)
 
-			result, err := svc.GetCategoryTrends(context.Background(), userID, tt.months, tt.limit)
+			result, err := svc.GetCategoryTrends(context.Background(), userID, tt.months, tt.limit, false)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -342,6 +355,46 @@ func TestReportService_GetCategoryTrends(t *testing.T) {
 	}
 }
 
+func TestReportService_GetTagBreakdown(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockReportRepository)
+	svc := NewReportService(mockRepo)
+	userID := uuid.New()
+
+	now := time.Now()
+	thisMonth := now.Format("2006-01")
+	lastMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0).Format("2006-01")
+
+	mockRepo.On("GetUserCurrency", mock.Anything, userID).Return("VND", nil)
+	mockRepo.On("GetTagMonthlyAmounts", mock.Anything, userID, mock.Anything, mock.Anything).Return(
+		[]repository.TagMonthlyAmount{
+			{Tag: "business", Month: lastMonth, Income: decimal.NewFromInt(500), Expenses: decimal.NewFromInt(100), TransactionCount: 2},
+			{Tag: "vacation", Month: lastMonth, Expenses: decimal.NewFromInt(300), TransactionCount: 1},
+			{Tag: "vacation", Month: thisMonth, Expenses: decimal.NewFromInt(200), TransactionCount: 3},
+		},
+		nil,
+	)
+
+	result, err := svc.GetTagBreakdown(context.Background(), userID, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "VND", result.Currency)
+	if assert.Len(t, result.Tags, 2) {
+		vacation := result.Tags[0]
+		assert.Equal(t, "vacation", vacation.Tag)
+		assert.Equal(t, "500", vacation.TotalExpenses)
+		assert.Equal(t, "0", vacation.TotalIncome)
+		assert.Equal(t, 4, vacation.TransactionCount)
+		assert.Len(t, vacation.MonthlyExpenses, 3)
+		assert.Equal(t, MonthlyAmount{Month: thisMonth, Amount: "200"}, vacation.MonthlyExpenses[2])
+
+		assert.Equal(t, "business", result.Tags[1].Tag)
+		assert.Equal(t, "500", result.Tags[1].TotalIncome)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
 func TestDetermineTrendFunc(t *testing.T) {
 	t.Parallel()
 
@@ -468,7 +521,7 @@ func BenchmarkReportService_GetMonthlyReport(b *testing.B) {
 		decimal.NewFromFloat(5200),
 		nil,
 	)
-	mockRepo.On("GetTopExpenseCategories", mock.Anything, userID, mock.Anything, mock.Anything, mock.Anything).Return(
+	mockRepo.On("GetTopExpenseCategories", mock.Anything, userID, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
 		[]repository.CategoryTotal{{Category: "Housing", Amount: decimal.NewFromFloat(1800), TransactionCount: 2}},
 		nil,
 	)
@@ -477,7 +530,7 @@ func BenchmarkReportService_GetMonthlyReport(b *testing.B) {
 	mockRepo.On("GetMonthlyTotals", mock.Anything, userID, 2025, 12).Return(decimal.Zero, decimal.Zero, nil).Maybe()
 
 	for i := 0; i < b.N; i++ {
-		_, _ = svc.GetMonthlyReport(context.Background(), userID, 2026, 1)
+		_, _ = svc.GetMonthlyReport(context.Background(), userID, 2026, 1, false)
 	}
 }
 
@@ -487,12 +540,12 @@ func BenchmarkReportService_GetCategoryTrends(b *testing.B) {
 	userID := uuid.New()
 
 	mockRepo.On("GetUserCurrency", mock.Anything, userID).Return("USD", nil)
-	mockRepo.On("GetCategoryTrendsData", mock.Anything, userID, mock.Anything, mock.Anything, mock.Anything).Return(
+	mockRepo.On("GetCategoryTrendsData", mock.Anything, userID, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
 		[]repository.CategoryMonthlyAmount{},
 		nil,
 	)
 
 	for i := 0; i < b.N; i++ {
-		_, _ = svc.GetCategoryTrends(context.Background(), userID, 6, 10)
+		_, _ = svc.GetCategoryTrends(context.Background(), userID, 6, 10, false)
 	}
 }
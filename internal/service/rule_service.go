@@ -0,0 +1,511 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/internal/scraper/pdf"
+	"github.com/wealthpath/backend/pkg/datetime"
+)
+
+const (
+	maxRuleNameLength = 100
+	// maxRuleTextLength matches the description columns of categorization_rules
+	maxRuleTextLength = 255
+
+	// A description is suggested for a rule once it has been used
+	// ruleSuggestionMinCount times, at least ruleSuggestionMinShare percent of
+	// them under the same category
+	ruleSuggestionMinCount = 3
+	ruleSuggestionMinShare = 80
+	maxRuleSuggestions     = 20
+
+	// reapplyPageSize is how many transactions are re-categorized at a time
+	reapplyPageSize = 500
+)
+
+var (
+	ErrInvalidRuleName     = errors.New("rule name is required and must be at most 100 characters")
+	ErrRuleNoConditions    = errors.New("a rule needs at least one condition")
+	ErrRuleNoActions       = errors.New("a rule needs to set a category, tags or a description")
+	ErrRuleTextTooLong     = errors.New("descriptions must be at most 255 characters and the category at most 100")
+	ErrInvalidRulePattern  = errors.New("description pattern is not a valid regular expression")
+	ErrInvalidRuleAmounts  = errors.New("amounts cannot be negative and the minimum cannot be above the maximum")
+	ErrInvalidReapplyRange = errors.New("startDate and endDate are required and startDate cannot be after endDate")
+)
+
+// RuleInput holds the conditions and actions of a categorization rule. A rule
+// matches transactions meeting all of its conditions.
+type RuleInput struct {
+	Name     string `json:"name"`
+	Priority int    `json:"priority"`          // Lower runs first
+	Enabled  *bool  `json:"enabled,omitempty"` // Defaults to true
+
+	TransactionType     *model.TransactionType `json:"transactionType,omitempty"`
+	DescriptionContains string                 `json:"descriptionContains,omitempty"` // Ignores case and Vietnamese diacritics
+	DescriptionPattern  string                 `json:"descriptionPattern,omitempty"`  // Regular expression, ignores case
+	MinAmount           *decimal.Decimal       `json:"minAmount,omitempty"`
+	MaxAmount           *decimal.Decimal       `json:"maxAmount,omitempty"`
+	AccountID           *uuid.UUID             `json:"accountId,omitempty"`
+
+	SetCategory    string   `json:"setCategory,omitempty"`
+	SetDescription string   `json:"setDescription,omitempty"`
+	AddTags        []string `json:"addTags,omitempty"`
+}
+
+// ReapplyRulesInput selects the transactions to run the rules over again
+type ReapplyRulesInput struct {
+	StartDate datetime.Date `json:"startDate"`
+	EndDate   datetime.Date `json:"endDate"`
+	RuleIDs   []uuid.UUID   `json:"ruleIds,omitempty"` // Only these rules, even if disabled; all enabled rules when empty
+	DryRun    bool          `json:"dryRun"`            // Report the changes without saving them
+}
+
+// RuleFields are the fields of a transaction that rules change
+type RuleFields struct {
+	Category    string   `json:"category"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// RuleChange is a transaction the rules changed
+type RuleChange struct {
+	TransactionID uuid.UUID       `json:"transactionId"`
+	Date          time.Time       `json:"date"`
+	Amount        decimal.Decimal `json:"amount"`
+	Before        RuleFields      `json:"before"`
+	After         RuleFields      `json:"after"`
+}
+
+// ReapplyRulesResult reports what re-applying rules did, or would do in a dry run
+type ReapplyRulesResult struct {
+	DryRun  bool         `json:"dryRun"`
+	Scanned int          `json:"scanned"`
+	Changed int          `json:"changed"`
+	Changes []RuleChange `json:"changes"`
+}
+
+// RuleSuggestion is a rule the user's history suggests: transactions with the
+// description were nearly always filed under the same category
+type RuleSuggestion struct {
+	Rule         RuleInput `json:"rule"`
+	Transactions int       `json:"transactions"` // Transactions with the description
+	Categorized  int       `json:"categorized"`  // Of those, how many are under the suggested category
+}
+
+// RuleService manages the user's categorization rules and applies them to
+// transactions as they are created or imported, or retroactively
+type RuleService struct {
+	repo         repository.RuleRepository
+	transactions TransactionRepositoryInterface
+	accounts     TransactionAccountRepo
+}
+
+// NewRuleService creates a new rule service. Without an account repository,
+// rules cannot match on an account.
+func NewRuleService(repo repository.RuleRepository, transactions TransactionRepositoryInterface, accounts TransactionAccountRepo) *RuleService {
+	return &RuleService{repo: repo, transactions: transactions, accounts: accounts}
+}
+
+// List returns the user's rules in the order they run
+func (s *RuleService) List(ctx context.Context, userID uuid.UUID) ([]model.CategorizationRule, error) {
+	rules, err := s.repo.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing rules: %w", err)
+	}
+	return rules, nil
+}
+
+// Create adds a rule
+func (s *RuleService) Create(ctx context.Context, userID uuid.UUID, input RuleInput) (*model.CategorizationRule, error) {
+	rule := &model.CategorizationRule{UserID: userID}
+	if err := s.applyRuleDetails(ctx, rule, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, rule); err != nil {
+		return nil, fmt.Errorf("creating rule: %w", err)
+	}
+	return rule, nil
+}
+
+// Update replaces the conditions and actions of a rule
+func (s *RuleService) Update(ctx context.Context, userID, id uuid.UUID, input RuleInput) (*model.CategorizationRule, error) {
+	rule, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting rule %s: %w", id, err)
+	}
+	if err := s.applyRuleDetails(ctx, rule, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, rule); err != nil {
+		return nil, fmt.Errorf("updating rule %s: %w", id, err)
+	}
+	return rule, nil
+}
+
+// Delete removes a rule. Transactions it already changed keep their changes.
+func (s *RuleService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if err := s.repo.Delete(ctx, userID, id); err != nil {
+		return fmt.Errorf("deleting rule %s: %w", id, err)
+	}
+	return nil
+}
+
+// Apply runs the user's enabled rules over new transactions before they are saved
+func (s *RuleService) Apply(ctx context.Context, userID uuid.UUID, txs []*model.Transaction) error {
+	if len(txs) == 0 {
+		return nil
+	}
+	rules, err := s.repo.List(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("listing rules: %w", err)
+	}
+
+	matchers := compileRules(rules, true)
+	for _, tx := range txs {
+		applyRules(matchers, tx)
+	}
+	return nil
+}
+
+// Reapply runs the rules over the user's transactions in a date range and saves
+// the transactions they change
+func (s *RuleService) Reapply(ctx context.Context, userID uuid.UUID, input ReapplyRulesInput) (*ReapplyRulesResult, error) {
+	if input.StartDate.IsZero() || input.EndDate.IsZero() || input.StartDate.After(input.EndDate.Time) {
+		return nil, ErrInvalidReapplyRange
+	}
+
+	rules, err := s.repo.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing rules: %w", err)
+	}
+	matchers := compileRules(rules, true)
+	if len(input.RuleIDs) > 0 {
+		if matchers, err = selectRules(rules, input.RuleIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &ReapplyRulesResult{DryRun: input.DryRun, Changes: []RuleChange{}}
+	filters := repository.TransactionFilters{
+		StartDate: &input.StartDate.Time,
+		EndDate:   &input.EndDate.Time,
+		Limit:     reapplyPageSize,
+	}
+	for {
+		txs, err := s.transactions.List(ctx, userID, filters)
+		if err != nil {
+			return nil, fmt.Errorf("listing transactions: %w", err)
+		}
+
+		for i := range txs {
+			tx := &txs[i]
+			before := ruleFields(tx)
+			if !applyRules(matchers, tx) {
+				continue
+			}
+			if !input.DryRun {
+				if err := s.transactions.Update(ctx, tx); err != nil {
+					return nil, fmt.Errorf("updating transaction %s: %w", tx.ID, err)
+				}
+			}
+			result.Changes = append(result.Changes, RuleChange{
+				TransactionID: tx.ID,
+				Date:          tx.Date,
+				Amount:        tx.Amount,
+				Before:        before,
+				After:         ruleFields(tx),
+			})
+		}
+		result.Scanned += len(txs)
+
+		if len(txs) < reapplyPageSize {
+			break
+		}
+		filters.Offset += reapplyPageSize
+	}
+	result.Changed = len(result.Changes)
+	return result, nil
+}
+
+// Suggest proposes rules for descriptions the user keeps filing under the same
+// category, leaving out those an enabled rule already categorizes. The most
+// used descriptions come first.
+func (s *RuleService) Suggest(ctx context.Context, userID uuid.UUID) ([]RuleSuggestion, error) {
+	rules, err := s.repo.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing rules: %w", err)
+	}
+	counts, err := s.repo.DescriptionCategories(ctx, userID, ruleSuggestionMinCount)
+	if err != nil {
+		return nil, fmt.Errorf("counting categories by description: %w", err)
+	}
+
+	matchers := compileRules(rules, true)
+	suggestions := []RuleSuggestion{}
+	// Counts come grouped by description and type, the most used category first
+	for i := 0; i < len(counts); {
+		best := counts[i]
+		key := strings.ToLower(strings.TrimSpace(best.Description))
+		total := 0
+		for ; i < len(counts) && counts[i].Type == best.Type &&
+			strings.ToLower(strings.TrimSpace(counts[i].Description)) == key; i++ {
+			total += counts[i].Count
+		}
+
+		if best.Count*100 < total*ruleSuggestionMinShare || best.Category == importDefaultCategory ||
+			categorizedByRule(matchers, best.Type, best.Description) {
+			continue
+		}
+
+		description := strings.TrimSpace(best.Description)
+		if utf8.RuneCountInString(description) > maxRuleTextLength {
+			description = string([]rune(description)[:maxRuleTextLength])
+		}
+		name := description
+		if utf8.RuneCountInString(name) > maxRuleNameLength {
+			name = string([]rune(name)[:maxRuleNameLength])
+		}
+		txType := best.Type
+		suggestions = append(suggestions, RuleSuggestion{
+			Rule: RuleInput{
+				Name:                name,
+				TransactionType:     &txType,
+				DescriptionContains: description,
+				SetCategory:         best.Category,
+			},
+			Transactions: total,
+			Categorized:  best.Count,
+		})
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Transactions > suggestions[j].Transactions
+	})
+	if len(suggestions) > maxRuleSuggestions {
+		suggestions = suggestions[:maxRuleSuggestions]
+	}
+	return suggestions, nil
+}
+
+// applyRuleDetails validates and sets the conditions and actions of a rule
+func (s *RuleService) applyRuleDetails(ctx context.Context, rule *model.CategorizationRule, input RuleInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxRuleNameLength {
+		return ErrInvalidRuleName
+	}
+	if input.TransactionType != nil &&
+		*input.TransactionType != model.TransactionTypeIncome && *input.TransactionType != model.TransactionTypeExpense {
+		return ErrInvalidType
+	}
+
+	contains := strings.TrimSpace(input.DescriptionContains)
+	pattern := strings.TrimSpace(input.DescriptionPattern)
+	category := strings.TrimSpace(input.SetCategory)
+	description := strings.TrimSpace(input.SetDescription)
+	if utf8.RuneCountInString(contains) > maxRuleTextLength || utf8.RuneCountInString(pattern) > maxRuleTextLength ||
+		utf8.RuneCountInString(description) > maxRuleTextLength || utf8.RuneCountInString(category) > maxCategoryLength {
+		return ErrRuleTextTooLong
+	}
+	if pattern != "" {
+		if _, err := regexp.Compile("(?i)" + pattern); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidRulePattern, err.Error())
+		}
+	}
+	if (input.MinAmount != nil && input.MinAmount.IsNegative()) || (input.MaxAmount != nil && input.MaxAmount.IsNegative()) ||
+		(input.MinAmount != nil && input.MaxAmount != nil && input.MinAmount.GreaterThan(*input.MaxAmount)) {
+		return ErrInvalidRuleAmounts
+	}
+	if input.AccountID != nil {
+		if s.accounts == nil {
+			return repository.ErrAccountNotFound
+		}
+		if _, err := s.accounts.GetByID(ctx, rule.UserID, *input.AccountID); err != nil {
+			return fmt.Errorf("getting account %s: %w", *input.AccountID, err)
+		}
+	}
+	tags, err := normalizeTags(input.AddTags)
+	if err != nil {
+		return err
+	}
+
+	if input.TransactionType == nil && contains == "" && pattern == "" &&
+		input.MinAmount == nil && input.MaxAmount == nil && input.AccountID == nil {
+		return ErrRuleNoConditions
+	}
+	if category == "" && description == "" && len(tags) == 0 {
+		return ErrRuleNoActions
+	}
+
+	rule.Name = name
+	rule.Priority = input.Priority
+	rule.Enabled = input.Enabled == nil || *input.Enabled
+	rule.TransactionType = input.TransactionType
+	rule.DescriptionContains = contains
+	rule.DescriptionPattern = pattern
+	rule.MinAmount = input.MinAmount
+	rule.MaxAmount = input.MaxAmount
+	rule.AccountID = input.AccountID
+	rule.SetCategory = category
+	rule.SetDescription = description
+	rule.AddTags = tags
+	return nil
+}
+
+// ruleMatcher is a rule ready to be matched against transactions
+type ruleMatcher struct {
+	rule     model.CategorizationRule
+	contains string // Folded for comparison
+	pattern  *regexp.Regexp
+}
+
+// compileRules prepares rules for matching, in the order given. Rules whose
+// pattern no longer compiles are left out.
+func compileRules(rules []model.CategorizationRule, enabledOnly bool) []ruleMatcher {
+	matchers := make([]ruleMatcher, 0, len(rules))
+	for _, rule := range rules {
+		if enabledOnly && !rule.Enabled {
+			continue
+		}
+		m := ruleMatcher{rule: rule, contains: pdf.FoldVietnamese(rule.DescriptionContains)}
+		if rule.DescriptionPattern != "" {
+			pattern, err := regexp.Compile("(?i)" + rule.DescriptionPattern)
+			if err != nil {
+				continue
+			}
+			m.pattern = pattern
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers
+}
+
+// selectRules prepares the rules with the given IDs, in the order they run
+func selectRules(rules []model.CategorizationRule, ids []uuid.UUID) ([]ruleMatcher, error) {
+	wanted := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	var selected []model.CategorizationRule
+	for _, rule := range rules {
+		if wanted[rule.ID] {
+			selected = append(selected, rule)
+			delete(wanted, rule.ID)
+		}
+	}
+	for id := range wanted {
+		return nil, fmt.Errorf("getting rule %s: %w", id, repository.ErrRuleNotFound)
+	}
+	return compileRules(selected, false), nil
+}
+
+// matchesDescription reports whether a description meets the rule's description
+// conditions
+func (m ruleMatcher) matchesDescription(description string) bool {
+	if m.contains != "" && !strings.Contains(pdf.FoldVietnamese(description), m.contains) {
+		return false
+	}
+	return m.pattern == nil || m.pattern.MatchString(description)
+}
+
+// matches reports whether a transaction meets all of the rule's conditions
+func (m ruleMatcher) matches(tx *model.Transaction) bool {
+	rule := m.rule
+	switch {
+	case rule.TransactionType != nil && *rule.TransactionType != tx.Type,
+		rule.MinAmount != nil && tx.Amount.LessThan(*rule.MinAmount),
+		rule.MaxAmount != nil && tx.Amount.GreaterThan(*rule.MaxAmount),
+		rule.AccountID != nil && (tx.AccountID == nil || *tx.AccountID != *rule.AccountID):
+		return false
+	}
+	return m.matchesDescription(tx.Description)
+}
+
+// applyRules changes a transaction as the matching rules say and reports whether
+// anything changed. Rules match the transaction as it was; the first matching
+// rule to set the category or the description wins, and the tags of every
+// matching rule are added. The category of a split transaction comes from its
+// lines and is left alone.
+func applyRules(matchers []ruleMatcher, tx *model.Transaction) bool {
+	original := *tx
+	categorySet, descriptionSet, changed := len(tx.Splits) > 0, false, false
+	for _, m := range matchers {
+		if !m.matches(&original) {
+			continue
+		}
+		rule := m.rule
+		if rule.SetCategory != "" && !categorySet {
+			categorySet = true
+			changed = changed || tx.Category != rule.SetCategory
+			tx.Category = rule.SetCategory
+		}
+		if rule.SetDescription != "" && !descriptionSet {
+			descriptionSet = true
+			changed = changed || tx.Description != rule.SetDescription
+			tx.Description = rule.SetDescription
+		}
+		var added bool
+		tx.Tags, added = addTags(tx.Tags, rule.AddTags)
+		changed = changed || added
+	}
+	return changed
+}
+
+// addTags adds tags a transaction does not have yet, comparing names regardless
+// of case, up to MaxTagsPerItem. It reports whether any were added.
+func addTags(tags, add []string) ([]string, bool) {
+	added := false
+	for _, tag := range add {
+		if len(tags) >= MaxTagsPerItem {
+			break
+		}
+		exists := false
+		for _, existing := range tags {
+			if strings.EqualFold(existing, tag) {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			tags = append(tags, tag)
+			added = true
+		}
+	}
+	return tags, added
+}
+
+// categorizedByRule reports whether a rule already sets the category of
+// transactions with the description
+func categorizedByRule(matchers []ruleMatcher, txType model.TransactionType, description string) bool {
+	for _, m := range matchers {
+		rule := m.rule
+		if rule.SetCategory == "" || (rule.TransactionType != nil && *rule.TransactionType != txType) {
+			continue
+		}
+		if (m.contains != "" || m.pattern != nil) && m.matchesDescription(description) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleFields copies the fields of a transaction that rules change
+func ruleFields(tx *model.Transaction) RuleFields {
+	return RuleFields{
+		Category:    tx.Category,
+		Description: tx.Description,
+		Tags:        append([]string(nil), tx.Tags...),
+	}
+}
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/pkg/datetime"
+)
+
+// MockRuleRepository implements repository.RuleRepository for testing
+type MockRuleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRuleRepository) Create(ctx context.Context, rule *model.CategorizationRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockRuleRepository) GetByID(ctx context.Context, userID, id uuid.UUID) (*model.CategorizationRule, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CategorizationRule), args.Error(1)
+}
+
+func (m *MockRuleRepository) List(ctx context.Context, userID uuid.UUID) ([]model.CategorizationRule, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.CategorizationRule), args.Error(1)
+}
+
+func (m *MockRuleRepository) Update(ctx context.Context, rule *model.CategorizationRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockRuleRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *MockRuleRepository) DescriptionCategories(ctx context.Context, userID uuid.UUID, minCount int) ([]repository.DescriptionCategory, error) {
+	args := m.Called(ctx, userID, minCount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.DescriptionCategory), args.Error(1)
+}
+
+func decimalPtr(v int64) *decimal.Decimal {
+	d := decimal.NewFromInt(v)
+	return &d
+}
+
+func TestRuleService_Create(t *testing.T) {
+	t.Parallel()
+
+	userID, accountID := uuid.New(), uuid.New()
+	expense := model.TransactionTypeExpense
+	transfer := model.TransactionType("transfer")
+	disabled := false
+
+	tests := []struct {
+		name    string
+		input   RuleInput
+		wantErr error
+		check   func(t *testing.T, rule *model.CategorizationRule)
+	}{
+		{
+			name: "trimmed and enabled by default",
+			input: RuleInput{Name: " Grab ", DescriptionContains: " grab ", SetCategory: " Transportation ",
+				AddTags: []string{"ride", "Ride"}},
+			check: func(t *testing.T, rule *model.CategorizationRule) {
+				assert.Equal(t, "Grab", rule.Name)
+				assert.True(t, rule.Enabled)
+				assert.Equal(t, "grab", rule.DescriptionContains)
+				assert.Equal(t, "Transportation", rule.SetCategory)
+				assert.Equal(t, []string{"ride"}, rule.AddTags)
+			},
+		},
+		{
+			name: "every condition",
+			input: RuleInput{Name: "Rent", Enabled: &disabled, TransactionType: &expense, DescriptionPattern: `^CK .*tien nha`,
+				MinAmount: decimalPtr(5000000), MaxAmount: decimalPtr(8000000), AccountID: &accountID, SetDescription: "Rent"},
+			check: func(t *testing.T, rule *model.CategorizationRule) {
+				assert.False(t, rule.Enabled)
+				assert.Equal(t, &accountID, rule.AccountID)
+			},
+		},
+		{name: "missing name", input: RuleInput{DescriptionContains: "grab", SetCategory: "Transportation"}, wantErr: ErrInvalidRuleName},
+		{name: "no conditions", input: RuleInput{Name: "All", SetCategory: "Transportation"}, wantErr: ErrRuleNoConditions},
+		{name: "no actions", input: RuleInput{Name: "Grab", DescriptionContains: "grab"}, wantErr: ErrRuleNoActions},
+		{name: "invalid pattern", input: RuleInput{Name: "Grab", DescriptionPattern: "grab(", SetCategory: "Transportation"}, wantErr: ErrInvalidRulePattern},
+		{name: "invalid type", input: RuleInput{Name: "Grab", TransactionType: &transfer, SetCategory: "Transportation"}, wantErr: ErrInvalidType},
+		{name: "negative amount", input: RuleInput{Name: "Small", MaxAmount: decimalPtr(-1), SetCategory: "Other"}, wantErr: ErrInvalidRuleAmounts},
+		{name: "minimum above maximum", input: RuleInput{Name: "Odd", MinAmount: decimalPtr(10), MaxAmount: decimalPtr(5), SetCategory: "Other"}, wantErr: ErrInvalidRuleAmounts},
+		{name: "invalid tag", input: RuleInput{Name: "Grab", DescriptionContains: "grab", AddTags: []string{"a,b"}}, wantErr: ErrInvalidTag},
+		{name: "unknown account", input: RuleInput{Name: "Card", AccountID: &uuid.UUID{}, SetCategory: "Other"}, wantErr: repository.ErrAccountNotFound},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockRuleRepository)
+			repo.On("Create", mock.Anything, mock.AnythingOfType("*model.CategorizationRule")).Return(nil).Maybe()
+			accounts := new(MockAccountRepository)
+			accounts.On("GetByID", mock.Anything, userID, accountID).Return(&model.Account{ID: accountID}, nil).Maybe()
+			accounts.On("GetByID", mock.Anything, userID, uuid.UUID{}).Return(nil, repository.ErrAccountNotFound).Maybe()
+			svc := NewRuleService(repo, new(MockTransactionRepo), accounts)
+
+			rule, err := svc.Create(context.Background(), userID, tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, userID, rule.UserID)
+			tt.check(t, rule)
+		})
+	}
+}
+
+func TestApplyRules(t *testing.T) {
+	t.Parallel()
+
+	cardID := uuid.New()
+	expense := model.TransactionTypeExpense
+	rules := []model.CategorizationRule{
+		{Name: "Grab", Enabled: true, DescriptionPattern: `^grab\*`, SetCategory: "Transportation", SetDescription: "Grab"},
+		{Name: "Coffee", Enabled: true, DescriptionContains: "highlands", SetCategory: "Food & Dining", AddTags: []string{"coffee"}},
+		{Name: "Electricity", Enabled: true, DescriptionContains: "tien dien", SetCategory: "Utilities", AddTags: []string{"bills"}},
+		{Name: "Big card spend", Enabled: true, TransactionType: &expense, AccountID: &cardID, MinAmount: decimalPtr(1000000), AddTags: []string{"review"}},
+		{Name: "Disabled", Enabled: false, DescriptionContains: "highlands", SetCategory: "Shopping"},
+		// Runs after Coffee, so only its tag is used
+		{Name: "Highlands card", Enabled: true, DescriptionContains: "highlands", AccountID: &cardID, SetCategory: "Entertainment", AddTags: []string{"Coffee", "card"}},
+	}
+	matchers := compileRules(rules, true)
+
+	tests := []struct {
+		name        string
+		tx          model.Transaction
+		wantChanged bool
+		want        RuleFields
+	}{
+		{
+			name:        "pattern ignores case and rewrites the description",
+			tx:          model.Transaction{Type: expense, Amount: decimal.NewFromInt(45000), Category: "Other", Description: "GRAB*A-5XK2 HCMC"},
+			wantChanged: true,
+			want:        RuleFields{Category: "Transportation", Description: "Grab"},
+		},
+		{
+			name:        "contains ignores Vietnamese diacritics",
+			tx:          model.Transaction{Type: expense, Amount: decimal.NewFromInt(650000), Category: "Other", Description: "Tiền điện EVN tháng 3"},
+			wantChanged: true,
+			want:        RuleFields{Category: "Utilities", Description: "Tiền điện EVN tháng 3", Tags: []string{"bills"}},
+		},
+		{
+			name: "first category wins and tags of every rule are added",
+			tx: model.Transaction{Type: expense, Amount: decimal.NewFromInt(1200000), Category: "Other",
+				Description: "Highlands Coffee", AccountID: &cardID, Tags: []string{"work"}},
+			wantChanged: true,
+			want:        RuleFields{Category: "Food & Dining", Description: "Highlands Coffee", Tags: []string{"work", "coffee", "review", "card"}},
+		},
+		{
+			name:        "amount below the range",
+			tx:          model.Transaction{Type: expense, Amount: decimal.NewFromInt(50000), Category: "Shopping", Description: "Shopee", AccountID: &cardID},
+			wantChanged: false,
+			want:        RuleFields{Category: "Shopping", Description: "Shopee"},
+		},
+		{
+			name: "split transaction keeps its category",
+			tx: model.Transaction{Type: expense, Amount: decimal.NewFromInt(90000), Category: "Groceries", Description: "grab*food",
+				Splits: []model.TransactionSplit{{Category: "Groceries"}, {Category: "Household"}}},
+			wantChanged: true,
+			want:        RuleFields{Category: "Groceries", Description: "Grab"},
+		},
+		{
+			name:        "already as the rules say",
+			tx:          model.Transaction{Type: expense, Amount: decimal.NewFromInt(45000), Category: "Transportation", Description: "Grab"},
+			wantChanged: false,
+			want:        RuleFields{Category: "Transportation", Description: "Grab"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tx := tt.tx
+			assert.Equal(t, tt.wantChanged, applyRules(matchers, &tx))
+			assert.Equal(t, tt.want, ruleFields(&tx))
+		})
+	}
+}
+
+func TestRuleService_Reapply(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	grab := model.CategorizationRule{ID: uuid.New(), Name: "Grab", Enabled: true, DescriptionContains: "grab", SetCategory: "Transportation"}
+	coffee := model.CategorizationRule{ID: uuid.New(), Name: "Coffee", Enabled: false, DescriptionContains: "coffee", SetCategory: "Food & Dining"}
+	history := func() []model.Transaction {
+		return []model.Transaction{
+			{ID: uuid.New(), UserID: userID, Category: "Other", Description: "Grab ride"},
+			{ID: uuid.New(), UserID: userID, Category: "Transportation", Description: "Grab bike"},
+			{ID: uuid.New(), UserID: userID, Category: "Other", Description: "Coffee"},
+		}
+	}
+	march := ReapplyRulesInput{StartDate: datetime.NewDate(2024, 3, 1), EndDate: datetime.NewDate(2024, 3, 31)}
+
+	t.Run("saves changed transactions", func(t *testing.T) {
+		t.Parallel()
+
+		repo := new(MockRuleRepository)
+		repo.On("List", mock.Anything, userID).Return([]model.CategorizationRule{grab, coffee}, nil)
+		txRepo := new(MockTransactionRepo)
+		txRepo.On("List", mock.Anything, userID, mock.MatchedBy(func(f repository.TransactionFilters) bool {
+			return f.StartDate.Equal(march.StartDate.Time) && f.EndDate.Equal(march.EndDate.Time) && f.Limit == reapplyPageSize
+		})).Return(history(), nil)
+		txRepo.On("Update", mock.Anything, mock.MatchedBy(func(tx *model.Transaction) bool {
+			return tx.Description == "Grab ride" && tx.Category == "Transportation"
+		})).Return(nil).Once()
+		svc := NewRuleService(repo, txRepo, nil)
+
+		result, err := svc.Reapply(context.Background(), userID, march)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Scanned)
+		assert.Equal(t, 1, result.Changed)
+		assert.Equal(t, "Other", result.Changes[0].Before.Category)
+		assert.Equal(t, "Transportation", result.Changes[0].After.Category)
+		txRepo.AssertExpectations(t)
+	})
+
+	t.Run("dry run with selected rules", func(t *testing.T) {
+		t.Parallel()
+
+		repo := new(MockRuleRepository)
+		repo.On("List", mock.Anything, userID).Return([]model.CategorizationRule{grab, coffee}, nil)
+		txRepo := new(MockTransactionRepo)
+		txRepo.On("List", mock.Anything, userID, mock.Anything).Return(history(), nil)
+		svc := NewRuleService(repo, txRepo, nil)
+
+		input := march
+		input.DryRun = true
+		input.RuleIDs = []uuid.UUID{coffee.ID}
+		result, err := svc.Reapply(context.Background(), userID, input)
+
+		require.NoError(t, err)
+		assert.True(t, result.DryRun)
+		require.Len(t, result.Changes, 1)
+		assert.Equal(t, "Food & Dining", result.Changes[0].After.Category)
+		txRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown rule", func(t *testing.T) {
+		t.Parallel()
+
+		repo := new(MockRuleRepository)
+		repo.On("List", mock.Anything, userID).Return([]model.CategorizationRule{grab}, nil)
+		svc := NewRuleService(repo, new(MockTransactionRepo), nil)
+
+		input := march
+		input.RuleIDs = []uuid.UUID{uuid.New()}
+		_, err := svc.Reapply(context.Background(), userID, input)
+
+		assert.ErrorIs(t, err, repository.ErrRuleNotFound)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		t.Parallel()
+
+		svc := NewRuleService(new(MockRuleRepository), new(MockTransactionRepo), nil)
+
+		_, err := svc.Reapply(context.Background(), userID, ReapplyRulesInput{StartDate: march.EndDate, EndDate: march.StartDate})
+
+		assert.ErrorIs(t, err, ErrInvalidReapplyRange)
+	})
+}
+
+func TestRuleService_Suggest(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	expense, income := model.TransactionTypeExpense, model.TransactionTypeIncome
+
+	repo := new(MockRuleRepository)
+	repo.On("List", mock.Anything, userID).Return([]model.CategorizationRule{
+		{Name: "Grab", Enabled: true, DescriptionContains: "grab", SetCategory: "Transportation"},
+	}, nil)
+	repo.On("DescriptionCategories", mock.Anything, userID, ruleSuggestionMinCount).Return([]repository.DescriptionCategory{
+		// Already categorized by a rule
+		{Description: "Grab", Type: expense, Category: "Transportation", Count: 9},
+		{Description: "Highlands Coffee", Type: expense, Category: "Food & Dining", Count: 9},
+		{Description: "highlands coffee", Type: expense, Category: "Entertainment", Count: 1},
+		// Split between categories
+		{Description: "Shopee", Type: expense, Category: "Shopping", Count: 3},
+		{Description: "Shopee", Type: expense, Category: "Household", Count: 2},
+		// Not worth a rule
+		{Description: "Misc", Type: expense, Category: "Other", Count: 5},
+		{Description: "Lương tháng", Type: income, Category: "Salary", Count: 12},
+	}, nil)
+	svc := NewRuleService(repo, new(MockTransactionRepo), nil)
+
+	suggestions, err := svc.Suggest(context.Background(), userID)
+
+	require.NoError(t, err)
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, "Lương tháng", suggestions[0].Rule.DescriptionContains)
+	assert.Equal(t, "Salary", suggestions[0].Rule.SetCategory)
+	assert.Equal(t, &income, suggestions[0].Rule.TransactionType)
+	assert.Equal(t, 12, suggestions[0].Transactions)
+	assert.Equal(t, "Highlands Coffee", suggestions[1].Rule.Name)
+	assert.Equal(t, 10, suggestions[1].Transactions)
+	assert.Equal(t, 9, suggestions[1].Categorized)
+}
+
+func TestTransactionService_Rules(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	rules := []model.CategorizationRule{
+		{Name: "Grab", Enabled: true, DescriptionContains: "grab", SetCategory: "Transportation", AddTags: []string{"ride"}},
+	}
+
+	tests := []struct {
+		name      string
+		skipRules bool
+		want      RuleFields
+	}{
+		{name: "rules run on create", want: RuleFields{Category: "Transportation", Description: "Grab ride", Tags: []string{"work", "ride"}}},
+		{name: "skipped on request", skipRules: true, want: RuleFields{Category: "Other", Description: "Grab ride", Tags: []string{"work"}}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ruleRepo := new(MockRuleRepository)
+			ruleRepo.On("List", mock.Anything, userID).Return(rules, nil)
+			repo := new(MockTransactionRepo)
+			repo.On("Create", mock.Anything, mock.AnythingOfType("*model.Transaction")).Return(nil)
+			svc := NewTransactionService(repo)
+			svc.SetRuleApplier(NewRuleService(ruleRepo, repo, nil))
+
+			tx, err := svc.Create(context.Background(), userID, CreateTransactionInput{
+				Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(45000), Category: "Other",
+				Description: "Grab ride", Tags: []string{"work"}, SkipRules: tt.skipRules,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ruleFields(tx))
+		})
+	}
+}
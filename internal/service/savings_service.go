@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,8 +12,18 @@ import (
 
 	"github.com/wealthpath/backend/internal/model"
 	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/pkg/datetime"
 )
 
+var (
+	ErrInvalidContributionAmount = errors.New("amount must be greater than zero")
+	ErrInvalidLinkedTransaction  = errors.New("linked transaction not found")
+	ErrCannotReverseReversal     = errors.New("a reversal cannot be reversed")
+)
+
+// goalMilestones are the progress percentages that trigger a milestone notification.
+var goalMilestones = []int{25, 50, 75, 100}
+
 // SavingsGoalRepositoryInterface defines the contract for savings goal data access.
 // Implementations must be safe for concurrent use.
 type SavingsGoalRepositoryInterface interface {
@@ -20,12 +32,27 @@ type SavingsGoalRepositoryInterface interface {
 	List(ctx context.Context, userID uuid.UUID) ([]model.SavingsGoal, error)
 	Update(ctx context.Context, goal *model.SavingsGoal) error
 	Delete(ctx context.Context, id, userID uuid.UUID) error
-	AddContribution(ctx context.Context, id, userID uuid.UUID, amount decimal.Decimal) error
+	RecordContribution(ctx context.Context, entry *model.SavingsContribution) (*model.SavingsGoal, error)
+	GetContribution(ctx context.Context, id uuid.UUID) (*model.SavingsContribution, error)
+	ListContributions(ctx context.Context, goalID uuid.UUID, limit, offset int) ([]model.SavingsContribution, error)
+}
+
+// TransactionRepoForSavings looks up transactions linked to contributions.
+type TransactionRepoForSavings interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Transaction, error)
+}
+
+// GoalMilestoneNotifier delivers savings goal milestones to a user (e.g. PushNotificationService).
+type GoalMilestoneNotifier interface {
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*model.NotificationPreferences, error)
+	SendGoalMilestone(ctx context.Context, userID uuid.UUID, goalName string, percentage int, goalID uuid.UUID) error
 }
 
 // SavingsGoalService handles business logic for savings goals and contributions.
 type SavingsGoalService struct {
-	repo SavingsGoalRepositoryInterface
+	repo              SavingsGoalRepositoryInterface
+	transactionRepo   TransactionRepoForSavings
+	milestoneNotifier GoalMilestoneNotifier
 }
 
 // NewSavingsGoalService creates a new SavingsGoalService with the given repository.
@@ -33,6 +60,16 @@ func NewSavingsGoalService(repo SavingsGoalRepositoryInterface) *SavingsGoalServ
 	return &SavingsGoalService{repo: repo}
 }
 
+// SetTransactionRepo sets the transaction repository used to validate linked transactions.
+func (s *SavingsGoalService) SetTransactionRepo(repo TransactionRepoForSavings) {
+	s.transactionRepo = repo
+}
+
+// SetMilestoneNotifier sets the notifier for 25/50/75/100% progress milestones.
+func (s *SavingsGoalService) SetMilestoneNotifier(notifier GoalMilestoneNotifier) {
+	s.milestoneNotifier = notifier
+}
+
 type CreateSavingsGoalInput struct {
 	Name         string          `json:"name"`
 	TargetAmount decimal.Decimal `json:"targetAmount"`
@@ -52,8 +89,12 @@ type UpdateSavingsGoalInput struct {
 	Icon          string          `json:"icon"`
 }
 
+// ContributeInput is used for both contributions and withdrawals. Amount is always positive.
 type ContributeInput struct {
-	Amount decimal.Decimal `json:"amount"`
+	Amount        decimal.Decimal `json:"amount"`
+	Date          *datetime.Date  `json:"date,omitempty"` // Defaults to today
+	Note          *string         `json:"note,omitempty"`
+	TransactionID *uuid.UUID      `json:"transactionId,omitempty"`
 }
 
 // SavingsGoalWithProjection extends SavingsGoal with calculated projection fields.
@@ -125,9 +166,24 @@ func (s *SavingsGoalService) Update(ctx context.Context, id uuid.UUID, userID uu
 		return nil, repository.ErrSavingsGoalNotFound
 	}
 
+	// Changing the saved amount directly is recorded in the ledger as an adjustment
+	if delta := input.CurrentAmount.Sub(goal.CurrentAmount); !delta.IsZero() {
+		note := "Balance adjustment"
+		updated, err := s.record(ctx, &model.SavingsContribution{
+			GoalID: id,
+			UserID: userID,
+			Amount: delta,
+			Date:   datetime.Today().Time,
+			Note:   &note,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("adjusting savings goal %s: %w", id, err)
+		}
+		goal = updated
+	}
+
 	goal.Name = input.Name
 	goal.TargetAmount = input.TargetAmount
-	goal.CurrentAmount = input.CurrentAmount
 	goal.Currency = input.Currency
 	goal.TargetDate = input.TargetDate
 	goal.Color = input.Color
@@ -148,18 +204,179 @@ func (s *SavingsGoalService) Delete(ctx context.Context, id uuid.UUID, userID uu
 	return nil
 }
 
-// Contribute adds a contribution amount to a savings goal.
-func (s *SavingsGoalService) Contribute(ctx context.Context, id uuid.UUID, userID uuid.UUID, amount decimal.Decimal) (*model.SavingsGoal, error) {
-	if err := s.repo.AddContribution(ctx, id, userID, amount); err != nil {
+// Contribute adds money to a savings goal and records it in the goal's ledger.
+func (s *SavingsGoalService) Contribute(ctx context.Context, id uuid.UUID, userID uuid.UUID, input ContributeInput) (*model.SavingsGoal, error) {
+	entry, err := s.newEntry(ctx, id, userID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	goal, err := s.record(ctx, entry)
+	if err != nil {
 		return nil, fmt.Errorf("adding contribution to savings goal %s: %w", id, err)
 	}
+	return goal, nil
+}
+
+// Withdraw takes money out of a savings goal. The goal cannot go below zero.
+func (s *SavingsGoalService) Withdraw(ctx context.Context, id uuid.UUID, userID uuid.UUID, input ContributeInput) (*model.SavingsGoal, error) {
+	entry, err := s.newEntry(ctx, id, userID, input)
+	if err != nil {
+		return nil, err
+	}
+	entry.Amount = entry.Amount.Neg()
+
+	goal, err := s.record(ctx, entry)
+	if err != nil {
+		return nil, fmt.Errorf("withdrawing from savings goal %s: %w", id, err)
+	}
+	return goal, nil
+}
+
+// ListContributions returns a goal's contributions and withdrawals, newest first.
+// Limit defaults to 50 and is capped at 100.
+func (s *SavingsGoalService) ListContributions(ctx context.Context, id uuid.UUID, userID uuid.UUID, limit, offset int) ([]model.SavingsContribution, error) {
 	goal, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("fetching updated savings goal %s: %w", id, err)
+		return nil, fmt.Errorf("getting savings goal %s: %w", id, err)
+	}
+	if goal.UserID != userID {
+		return nil, repository.ErrSavingsGoalNotFound
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	entries, err := s.repo.ListContributions(ctx, id, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing contributions for savings goal %s: %w", id, err)
+	}
+	return entries, nil
+}
+
+// ReverseContribution undoes a ledger entry by recording an opposite entry, so the
+// history keeps both. Each entry can be reversed once.
+func (s *SavingsGoalService) ReverseContribution(ctx context.Context, goalID, contributionID, userID uuid.UUID) (*model.SavingsGoal, error) {
+	original, err := s.repo.GetContribution(ctx, contributionID)
+	if err != nil {
+		return nil, fmt.Errorf("getting contribution %s: %w", contributionID, err)
+	}
+	if original.UserID != userID || original.GoalID != goalID {
+		return nil, repository.ErrSavingsContributionNotFound
+	}
+	if original.ReversalOf != nil {
+		return nil, ErrCannotReverseReversal
+	}
+
+	note := "Reversal"
+	goal, err := s.record(ctx, &model.SavingsContribution{
+		GoalID:     goalID,
+		UserID:     userID,
+		Amount:     original.Amount.Neg(),
+		Date:       datetime.Today().Time,
+		Note:       &note,
+		ReversalOf: &original.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reversing contribution %s: %w", contributionID, err)
 	}
 	return goal, nil
 }
 
+// newEntry validates a contribution or withdrawal request and builds its ledger entry.
+func (s *SavingsGoalService) newEntry(ctx context.Context, id, userID uuid.UUID, input ContributeInput) (*model.SavingsContribution, error) {
+	if !input.Amount.IsPositive() {
+		return nil, ErrInvalidContributionAmount
+	}
+
+	if input.TransactionID != nil && s.transactionRepo != nil {
+		tx, err := s.transactionRepo.GetByID(ctx, *input.TransactionID)
+		if err != nil || tx.UserID != userID {
+			return nil, ErrInvalidLinkedTransaction
+		}
+	}
+
+	date := datetime.Today()
+	if input.Date != nil && !input.Date.IsZero() {
+		date = *input.Date
+	}
+
+	return &model.SavingsContribution{
+		GoalID:        id,
+		UserID:        userID,
+		Amount:        input.Amount,
+		Date:          date.Time,
+		Note:          input.Note,
+		TransactionID: input.TransactionID,
+	}, nil
+}
+
+// record saves a ledger entry and sends a milestone notification if it moved the
+// goal past one.
+func (s *SavingsGoalService) record(ctx context.Context, entry *model.SavingsContribution) (*model.SavingsGoal, error) {
+	goal, err := s.repo.RecordContribution(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyMilestone(ctx, goal, entry.Amount)
+	return goal, nil
+}
+
+// notifyMilestone sends the highest milestone crossed by adding delta to the goal.
+// Failures are logged because the contribution itself was saved.
+func (s *SavingsGoalService) notifyMilestone(ctx context.Context, goal *model.SavingsGoal, delta decimal.Decimal) {
+	if s.milestoneNotifier == nil || !delta.IsPositive() {
+		return
+	}
+
+	milestone := crossedGoalMilestone(goal.CurrentAmount.Sub(delta), goal.CurrentAmount, goal.TargetAmount)
+	if milestone == 0 {
+		return
+	}
+
+	prefs, err := s.milestoneNotifier.GetPreferences(ctx, goal.UserID)
+	if err != nil || !prefs.GoalMilestonesEnabled {
+		return
+	}
+
+	err = s.milestoneNotifier.SendGoalMilestone(ctx, goal.UserID, goal.Name, milestone, goal.ID)
+	if err != nil && !errors.Is(err, ErrNoSubscriptions) {
+		slog.Error("Failed to send goal milestone",
+			slog.String("goal_id", goal.ID.String()),
+			slog.Int("milestone", milestone),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// crossedGoalMilestone returns the highest milestone percentage passed when the
+// saved amount went from previous to current, or 0 if none was.
+func crossedGoalMilestone(previous, current, target decimal.Decimal) int {
+	if !target.IsPositive() {
+		return 0
+	}
+
+	hundred := decimal.NewFromInt(100)
+	before := previous.Mul(hundred).Div(target)
+	after := current.Mul(hundred).Div(target)
+
+	crossed := 0
+	for _, m := range goalMilestones {
+		level := decimal.NewFromInt(int64(m))
+		if before.LessThan(level) && after.GreaterThanOrEqual(level) {
+			crossed = m
+		}
+	}
+	return crossed
+}
+
 // ListWithProjections retrieves all savings goals with calculated projections.
 func (s *SavingsGoalService) ListWithProjections(ctx context.Context, userID uuid.UUID) ([]SavingsGoalWithProjection, error) {
 	goals, err := s.repo.List(ctx, userID)
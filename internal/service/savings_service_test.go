@@ -53,8 +53,45 @@ func (m *MockSavingsGoalRepo) Delete(ctx context.Context, id, userID uuid.UUID)
 	return args.Error(0)
 }
 
-func (m *MockSavingsGoalRepo) AddContribution(ctx context.Context, id, userID uuid.UUID, amount decimal.Decimal) error {
-	args := m.Called(ctx, id, userID, amount)
+func (m *MockSavingsGoalRepo) RecordContribution(ctx context.Context, entry *model.SavingsContribution) (*model.SavingsGoal, error) {
+	args := m.Called(ctx, entry)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SavingsGoal), args.Error(1)
+}
+
+func (m *MockSavingsGoalRepo) GetContribution(ctx context.Context, id uuid.UUID) (*model.SavingsContribution, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SavingsContribution), args.Error(1)
+}
+
+func (m *MockSavingsGoalRepo) ListContributions(ctx context.Context, goalID uuid.UUID, limit, offset int) ([]model.SavingsContribution, error) {
+	args := m.Called(ctx, goalID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.SavingsContribution), args.Error(1)
+}
+
+// MockGoalMilestoneNotifier implements GoalMilestoneNotifier for testing
+type MockGoalMilestoneNotifier struct {
+	mock.Mock
+}
+
+func (m *MockGoalMilestoneNotifier) GetPreferences(ctx context.Context, userID uuid.UUID) (*model.NotificationPreferences, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockGoalMilestoneNotifier) SendGoalMilestone(ctx context.Context, userID uuid.UUID, goalName string, percentage int, goalID uuid.UUID) error {
+	args := m.Called(ctx, userID, goalName, percentage, goalID)
 	return args.Error(0)
 }
 
@@ -267,6 +304,21 @@ func TestSavingsGoalService_Update(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "changed amount is recorded as adjustment",
+			setupMock: func(m *MockSavingsGoalRepo, goalID, userID uuid.UUID) {
+				m.On("GetByID", mock.Anything, goalID).Return(&model.SavingsGoal{
+					ID:            goalID,
+					UserID:        userID,
+					CurrentAmount: decimal.NewFromInt(150),
+				}, nil)
+				m.On("RecordContribution", mock.Anything, mock.MatchedBy(func(e *model.SavingsContribution) bool {
+					return e.Amount.Equal(decimal.NewFromInt(-150)) && e.Note != nil && *e.Note == "Balance adjustment"
+				})).Return(&model.SavingsGoal{ID: goalID, UserID: userID}, nil)
+				m.On("Update", mock.Anything, mock.AnythingOfType("*model.SavingsGoal")).Return(nil)
+			},
+			wantErr: false,
+		},
 		{
 			name: "not owner",
 			setupMock: func(m *MockSavingsGoalRepo, goalID, userID uuid.UUID) {
@@ -337,27 +389,36 @@ func TestSavingsGoalService_Contribute(t *testing.T) {
 
 	tests := []struct {
 		name      string
+		input     ContributeInput
 		setupMock func(*MockSavingsGoalRepo, uuid.UUID, uuid.UUID)
-		wantErr   bool
+		wantErr   error
 	}{
 		{
-			name: "success",
+			name:  "success",
+			input: ContributeInput{Amount: decimal.NewFromFloat(500)},
 			setupMock: func(m *MockSavingsGoalRepo, goalID, userID uuid.UUID) {
-				m.On("AddContribution", mock.Anything, goalID, userID, decimal.NewFromFloat(500)).Return(nil)
-				m.On("GetByID", mock.Anything, goalID).Return(&model.SavingsGoal{
+				m.On("RecordContribution", mock.Anything, mock.MatchedBy(func(e *model.SavingsContribution) bool {
+					return e.GoalID == goalID && e.UserID == userID && e.Amount.Equal(decimal.NewFromFloat(500)) && !e.Date.IsZero()
+				})).Return(&model.SavingsGoal{
 					ID:            goalID,
 					UserID:        userID,
 					CurrentAmount: decimal.NewFromFloat(2500),
 				}, nil)
 			},
-			wantErr: false,
 		},
 		{
-			name: "contribution error",
+			name:      "zero amount",
+			input:     ContributeInput{Amount: decimal.Zero},
+			setupMock: func(m *MockSavingsGoalRepo, goalID, userID uuid.UUID) {},
+			wantErr:   ErrInvalidContributionAmount,
+		},
+		{
+			name:  "contribution error",
+			input: ContributeInput{Amount: decimal.NewFromFloat(500)},
 			setupMock: func(m *MockSavingsGoalRepo, goalID, userID uuid.UUID) {
-				m.On("AddContribution", mock.Anything, goalID, userID, decimal.NewFromFloat(500)).Return(errors.New("error"))
+				m.On("RecordContribution", mock.Anything, mock.Anything).Return(nil, repository.ErrSavingsGoalNotFound)
 			},
-			wantErr: true,
+			wantErr: repository.ErrSavingsGoalNotFound,
 		},
 	}
 
@@ -372,10 +433,112 @@ func TestSavingsGoalService_Contribute(t *testing.T) {
 			userID := uuid.New()
 			tt.setupMock(mockRepo, goalID, userID)
 
-			goal, err := service.Contribute(context.Background(), goalID, userID, decimal.NewFromFloat(500))
+			goal, err := service.Contribute(context.Background(), goalID, userID, tt.input)
 
-			if tt.wantErr {
-				assert.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, goal)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, goal)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSavingsGoalService_Withdraw(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockSavingsGoalRepo)
+	notifier := new(MockGoalMilestoneNotifier)
+	service := NewSavingsGoalService(mockRepo)
+	service.SetMilestoneNotifier(notifier)
+	goalID := uuid.New()
+	userID := uuid.New()
+
+	mockRepo.On("RecordContribution", mock.Anything, mock.MatchedBy(func(e *model.SavingsContribution) bool {
+		return e.Amount.Equal(decimal.NewFromInt(-300))
+	})).Return(&model.SavingsGoal{
+		ID:            goalID,
+		UserID:        userID,
+		TargetAmount:  decimal.NewFromInt(1000),
+		CurrentAmount: decimal.NewFromInt(200),
+	}, nil)
+
+	goal, err := service.Withdraw(context.Background(), goalID, userID, ContributeInput{Amount: decimal.NewFromInt(300)})
+
+	assert.NoError(t, err)
+	assert.True(t, goal.CurrentAmount.Equal(decimal.NewFromInt(200)))
+	mockRepo.AssertExpectations(t)
+	// Withdrawals never trigger milestones
+	notifier.AssertNotCalled(t, "GetPreferences", mock.Anything, mock.Anything)
+}
+
+func TestSavingsGoalService_ReverseContribution(t *testing.T) {
+	t.Parallel()
+
+	goalID := uuid.New()
+	userID := uuid.New()
+	originalID := uuid.New()
+
+	tests := []struct {
+		name      string
+		original  *model.SavingsContribution
+		setupMock func(*MockSavingsGoalRepo)
+		wantErr   error
+	}{
+		{
+			name:     "success",
+			original: &model.SavingsContribution{ID: originalID, GoalID: goalID, UserID: userID, Amount: decimal.NewFromInt(400)},
+			setupMock: func(m *MockSavingsGoalRepo) {
+				m.On("RecordContribution", mock.Anything, mock.MatchedBy(func(e *model.SavingsContribution) bool {
+					return e.Amount.Equal(decimal.NewFromInt(-400)) && e.ReversalOf != nil && *e.ReversalOf == originalID
+				})).Return(&model.SavingsGoal{ID: goalID, UserID: userID}, nil)
+			},
+		},
+		{
+			name:      "belongs to another user",
+			original:  &model.SavingsContribution{ID: originalID, GoalID: goalID, UserID: uuid.New(), Amount: decimal.NewFromInt(400)},
+			setupMock: func(m *MockSavingsGoalRepo) {},
+			wantErr:   repository.ErrSavingsContributionNotFound,
+		},
+		{
+			name:      "belongs to another goal",
+			original:  &model.SavingsContribution{ID: originalID, GoalID: uuid.New(), UserID: userID, Amount: decimal.NewFromInt(400)},
+			setupMock: func(m *MockSavingsGoalRepo) {},
+			wantErr:   repository.ErrSavingsContributionNotFound,
+		},
+		{
+			name:      "reversal of a reversal",
+			original:  &model.SavingsContribution{ID: originalID, GoalID: goalID, UserID: userID, Amount: decimal.NewFromInt(-400), ReversalOf: &goalID},
+			setupMock: func(m *MockSavingsGoalRepo) {},
+			wantErr:   ErrCannotReverseReversal,
+		},
+		{
+			name:     "already reversed",
+			original: &model.SavingsContribution{ID: originalID, GoalID: goalID, UserID: userID, Amount: decimal.NewFromInt(400)},
+			setupMock: func(m *MockSavingsGoalRepo) {
+				m.On("RecordContribution", mock.Anything, mock.Anything).Return(nil, repository.ErrContributionAlreadyReversed)
+			},
+			wantErr: repository.ErrContributionAlreadyReversed,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockSavingsGoalRepo)
+			service := NewSavingsGoalService(mockRepo)
+			mockRepo.On("GetContribution", mock.Anything, originalID).Return(tt.original, nil)
+			tt.setupMock(mockRepo)
+
+			goal, err := service.ReverseContribution(context.Background(), goalID, originalID, userID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
 				assert.Nil(t, goal)
 			} else {
 				assert.NoError(t, err)
@@ -385,3 +548,100 @@ func TestSavingsGoalService_Contribute(t *testing.T) {
 		})
 	}
 }
+
+func TestSavingsGoalService_ListContributions(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := new(MockSavingsGoalRepo)
+	service := NewSavingsGoalService(mockRepo)
+	goalID := uuid.New()
+	userID := uuid.New()
+
+	mockRepo.On("GetByID", mock.Anything, goalID).Return(&model.SavingsGoal{ID: goalID, UserID: userID}, nil)
+	mockRepo.On("ListContributions", mock.Anything, goalID, 100, 0).Return([]model.SavingsContribution{{GoalID: goalID}}, nil)
+
+	entries, err := service.ListContributions(context.Background(), goalID, userID, 500, -1)
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	_, err = service.ListContributions(context.Background(), goalID, uuid.New(), 10, 0)
+	assert.ErrorIs(t, err, repository.ErrSavingsGoalNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSavingsGoalService_MilestoneNotification(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		enabled  bool
+		wantSent bool
+	}{
+		{name: "enabled", enabled: true, wantSent: true},
+		{name: "disabled in preferences", enabled: false, wantSent: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockSavingsGoalRepo)
+			notifier := new(MockGoalMilestoneNotifier)
+			service := NewSavingsGoalService(mockRepo)
+			service.SetMilestoneNotifier(notifier)
+			goalID := uuid.New()
+			userID := uuid.New()
+
+			// 400 -> 600 of 1000 crosses 50%
+			mockRepo.On("RecordContribution", mock.Anything, mock.Anything).Return(&model.SavingsGoal{
+				ID:            goalID,
+				UserID:        userID,
+				Name:          "Vacation",
+				TargetAmount:  decimal.NewFromInt(1000),
+				CurrentAmount: decimal.NewFromInt(600),
+			}, nil)
+			notifier.On("GetPreferences", mock.Anything, userID).Return(&model.NotificationPreferences{GoalMilestonesEnabled: tt.enabled}, nil)
+			if tt.wantSent {
+				notifier.On("SendGoalMilestone", mock.Anything, userID, "Vacation", 50, goalID).Return(nil)
+			}
+
+			_, err := service.Contribute(context.Background(), goalID, userID, ContributeInput{Amount: decimal.NewFromInt(200)})
+
+			assert.NoError(t, err)
+			notifier.AssertExpectations(t)
+			if !tt.wantSent {
+				notifier.AssertNotCalled(t, "SendGoalMilestone", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestCrossedGoalMilestone(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		previous, current int64
+		want              int
+	}{
+		{"no milestone", 100, 200, 0},
+		{"exactly 25%", 200, 250, 25},
+		{"skips to highest", 100, 800, 75},
+		{"reaches target", 900, 1000, 100},
+		{"beyond target", 1000, 1200, 0},
+		{"already past", 300, 400, 0},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := crossedGoalMilestone(decimal.NewFromInt(tt.previous), decimal.NewFromInt(tt.current), decimal.NewFromInt(1000))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Equal(t, 0, crossedGoalMilestone(decimal.Zero, decimal.NewFromInt(10), decimal.Zero))
+}
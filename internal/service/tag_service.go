@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+)
+
+// MaxTagsPerItem is the most tags a transaction or recurring transaction can have.
+const MaxTagsPerItem = 20
+
+var (
+	ErrInvalidTag      = errors.New("tag names are required, must be at most 50 characters and cannot contain commas")
+	ErrTooManyTags     = fmt.Errorf("at most %d tags are allowed", MaxTagsPerItem)
+	ErrInvalidTagColor = errors.New("color must be a hex colour such as #4CAF50")
+)
+
+// TagInput holds the details of a tag.
+type TagInput struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// TagService manages the user's tags. Tags are also created implicitly the first
+// time a transaction or recurring transaction uses them.
+type TagService struct {
+	repo repository.TagRepository
+}
+
+// NewTagService creates a new tag service
+func NewTagService(repo repository.TagRepository) *TagService {
+	return &TagService{repo: repo}
+}
+
+// List returns the user's tags with how many transactions each is on
+func (s *TagService) List(ctx context.Context, userID uuid.UUID) ([]model.TagUsage, error) {
+	tags, err := s.repo.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing tags: %w", err)
+	}
+	return tags, nil
+}
+
+// Autocomplete suggests the user's tags matching what they have typed so far.
+// The limit defaults to 10 and is capped at 50.
+func (s *TagService) Autocomplete(ctx context.Context, userID uuid.UUID, query string, limit int) ([]model.Tag, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	tags, err := s.repo.Search(ctx, userID, strings.TrimSpace(query), limit)
+	if err != nil {
+		return nil, fmt.Errorf("searching tags: %w", err)
+	}
+	return tags, nil
+}
+
+// Create adds a tag
+func (s *TagService) Create(ctx context.Context, userID uuid.UUID, input TagInput) (*model.Tag, error) {
+	tag := &model.Tag{UserID: userID}
+	if err := applyTagDetails(tag, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, tag); err != nil {
+		return nil, fmt.Errorf("creating tag: %w", err)
+	}
+	return tag, nil
+}
+
+// Update renames or recolours a tag everywhere it is used
+func (s *TagService) Update(ctx context.Context, userID, id uuid.UUID, input TagInput) (*model.Tag, error) {
+	tag, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting tag %s: %w", id, err)
+	}
+	if err := applyTagDetails(tag, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, tag); err != nil {
+		return nil, fmt.Errorf("updating tag %s: %w", id, err)
+	}
+	return tag, nil
+}
+
+// Delete removes a tag from everything it is on
+func (s *TagService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if err := s.repo.Delete(ctx, userID, id); err != nil {
+		return fmt.Errorf("deleting tag %s: %w", id, err)
+	}
+	return nil
+}
+
+// applyTagDetails validates and sets the name and colour of a tag
+func applyTagDetails(tag *model.Tag, input TagInput) error {
+	name, err := normalizeTag(input.Name)
+	if err != nil {
+		return err
+	}
+	if input.Color != "" && !categoryColorPattern.MatchString(input.Color) {
+		return ErrInvalidTagColor
+	}
+
+	tag.Name = name
+	tag.Color = strings.ToUpper(input.Color)
+	return nil
+}
+
+// normalizeTags trims tag names and drops repeats, comparing names regardless of
+// case. The first spelling of a name is kept.
+func normalizeTags(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool, len(names))
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		tag, err := normalizeTag(name)
+		if err != nil {
+			return nil, err
+		}
+		key := strings.ToLower(tag)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		tags = append(tags, tag)
+	}
+	if len(tags) > MaxTagsPerItem {
+		return nil, ErrTooManyTags
+	}
+	return tags, nil
+}
+
+// normalizeTag trims a tag name and checks it can be stored and filtered on
+func normalizeTag(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > 50 || strings.Contains(name, ",") {
+		return "", ErrInvalidTag
+	}
+	return name, nil
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+)
+
+// MockTagRepository implements repository.TagRepository for testing
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) Create(ctx context.Context, tag *model.Tag) error {
+	args := m.Called(ctx, tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) GetByID(ctx context.Context, userID, id uuid.UUID) (*model.Tag, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) List(ctx context.Context, userID uuid.UUID) ([]model.TagUsage, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.TagUsage), args.Error(1)
+}
+
+func (m *MockTagRepository) Search(ctx context.Context, userID uuid.UUID, query string, limit int) ([]model.Tag, error) {
+	args := m.Called(ctx, userID, query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Tag), args.Error(1)
+}
+
+func (m *MockTagRepository) Update(ctx context.Context, tag *model.Tag) error {
+	args := m.Called(ctx, tag)
+	return args.Error(0)
+}
+
+func (m *MockTagRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func TestTagService_Create(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		input   TagInput
+		wantErr error
+	}{
+		{name: "success", input: TagInput{Name: " Vacation ", Color: "#ff9800"}},
+		{name: "name required", input: TagInput{Name: "  "}, wantErr: ErrInvalidTag},
+		{name: "name with comma", input: TagInput{Name: "work, travel"}, wantErr: ErrInvalidTag},
+		{name: "invalid colour", input: TagInput{Name: "Vacation", Color: "orange"}, wantErr: ErrInvalidTagColor},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockTagRepository)
+			svc := NewTagService(repo)
+			repo.On("Create", mock.Anything, mock.AnythingOfType("*model.Tag")).Return(nil).Maybe()
+
+			tag, err := svc.Create(context.Background(), userID, tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Vacation", tag.Name)
+			assert.Equal(t, "#FF9800", tag.Color)
+			assert.Equal(t, userID, tag.UserID)
+		})
+	}
+}
+
+func TestTagService_Update_NotFound(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockTagRepository)
+	svc := NewTagService(repo)
+	userID, id := uuid.New(), uuid.New()
+
+	repo.On("GetByID", mock.Anything, userID, id).Return(nil, repository.ErrTagNotFound)
+
+	_, err := svc.Update(context.Background(), userID, id, TagInput{Name: "Vacation"})
+
+	assert.ErrorIs(t, err, repository.ErrTagNotFound)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestTagService_Autocomplete_Limit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		limit     int
+		wantLimit int
+	}{
+		{name: "default", limit: 0, wantLimit: 10},
+		{name: "custom", limit: 5, wantLimit: 5},
+		{name: "capped", limit: 500, wantLimit: 50},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockTagRepository)
+			svc := NewTagService(repo)
+			userID := uuid.New()
+
+			repo.On("Search", mock.Anything, userID, "vac", tt.wantLimit).Return([]model.Tag{{Name: "Vacation"}}, nil)
+
+			tags, err := svc.Autocomplete(context.Background(), userID, " vac ", tt.limit)
+
+			require.NoError(t, err)
+			assert.Len(t, tags, 1)
+			repo.AssertExpectations(t)
+		})
+	}
+}
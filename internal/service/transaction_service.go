@@ -4,8 +4,14 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -16,20 +22,91 @@ import (
 	"github.com/wealthpath/backend/pkg/datetime"
 )
 
+// Split validation errors
+var (
+	ErrSplitTooFewLines = errors.New("a split transaction needs at least two lines")
+	ErrInvalidSplitLine = errors.New("each split line needs a category and an amount greater than zero")
+	ErrSplitSumMismatch = errors.New("split lines must add up to the transaction amount")
+)
+
+// ErrInvalidCursor is returned for a page cursor that was not issued by ListPage
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Bulk action errors
+var (
+	ErrInvalidBulkAction    = errors.New("action must be recategorize, retag, change_date, change_account or delete")
+	ErrInvalidBulkSelector  = errors.New("select transactions either by ids or by filter")
+	ErrBulkTooMany          = fmt.Errorf("a bulk action can change at most %d transactions", maxBulkTransactions)
+	ErrBulkCategoryRequired = fmt.Errorf("recategorize needs a category of at most %d characters", maxCategoryLength)
+	ErrBulkTagsRequired     = errors.New("retag needs tags to add or remove")
+	ErrBulkDateRequired     = errors.New("change_date needs a date")
+)
+
+// Bulk actions
+const (
+	BulkActionRecategorize  = "recategorize"
+	BulkActionRetag         = "retag"
+	BulkActionChangeDate    = "change_date"
+	BulkActionChangeAccount = "change_account"
+	BulkActionDelete        = "delete"
+)
+
+// Outcomes of a bulk action for one transaction
+const (
+	BulkStatusUpdated   = "updated"
+	BulkStatusDeleted   = "deleted"
+	BulkStatusUnchanged = "unchanged"
+	BulkStatusSkipped   = "skipped"
+	BulkStatusNotFound  = "not_found"
+)
+
+// maxBulkTransactions caps the transactions one bulk action can select
+const maxBulkTransactions = 1000
+
 // TransactionRepositoryInterface defines the contract for transaction data access.
 // Implementations must be safe for concurrent use.
 type TransactionRepositoryInterface interface {
 	Create(ctx context.Context, tx *model.Transaction) error
 	GetByID(ctx context.Context, id uuid.UUID) (*model.Transaction, error)
 	List(ctx context.Context, userID uuid.UUID, filters repository.TransactionFilters) ([]model.Transaction, error)
+	Totals(ctx context.Context, userID uuid.UUID, filters repository.TransactionFilters) (*model.TransactionTotals, error)
 	Update(ctx context.Context, tx *model.Transaction) error
 	Delete(ctx context.Context, id, userID uuid.UUID) error
+	ApplyBulk(ctx context.Context, userID uuid.UUID, filters repository.TransactionFilters, dryRun bool, change repository.BulkChange) error
+}
+
+// BudgetAlertChecker evaluates budgets after a transaction is written (e.g. BudgetService).
+type BudgetAlertChecker interface {
+	CheckAlerts(ctx context.Context, userID uuid.UUID, before, after *model.Transaction) error
+	CheckBulkAlerts(ctx context.Context, userID uuid.UUID, before, after []model.Transaction) error
+}
+
+// TransactionAccountRepo looks up the account a transaction is recorded against.
+type TransactionAccountRepo interface {
+	GetByID(ctx context.Context, userID, id uuid.UUID) (*model.Account, error)
+}
+
+// TransactionRuleApplier applies the user's categorization rules to new
+// transactions before they are saved (e.g. RuleService).
+type TransactionRuleApplier interface {
+	Apply(ctx context.Context, userID uuid.UUID, txs []*model.Transaction) error
+}
+
+// TransactionConverter sets the amount of a transaction in the user's base
+// currency before it is saved (e.g. ExchangeRateService). A nil rate means the
+// rate of the transaction's date.
+type TransactionConverter interface {
+	Convert(ctx context.Context, userID uuid.UUID, tx *model.Transaction, rate *decimal.Decimal) error
 }
 
 // TransactionService handles business logic for financial transactions.
 // It enforces validation rules and coordinates repository operations.
 type TransactionService struct {
-	repo TransactionRepositoryInterface
+	repo         TransactionRepositoryInterface
+	budgetAlerts BudgetAlertChecker
+	accounts     TransactionAccountRepo
+	rules        TransactionRuleApplier
+	converter    TransactionConverter
 }
 
 // NewTransactionService creates a new TransactionService with the given repository.
@@ -38,6 +115,29 @@ func NewTransactionService(repo TransactionRepositoryInterface) *TransactionServ
 	return &TransactionService{repo: repo}
 }
 
+// SetBudgetAlertChecker sets the checker that runs after expenses are created,
+// updated or deleted.
+func (s *TransactionService) SetBudgetAlertChecker(checker BudgetAlertChecker) {
+	s.budgetAlerts = checker
+}
+
+// SetRuleApplier sets the categorization rules run over new transactions.
+func (s *TransactionService) SetRuleApplier(rules TransactionRuleApplier) {
+	s.rules = rules
+}
+
+// SetConverter sets the converter to the user's base currency. Without it,
+// transactions are saved unconverted.
+func (s *TransactionService) SetConverter(converter TransactionConverter) {
+	s.converter = converter
+}
+
+// SetAccountRepo sets the repository used to check the account of a transaction.
+// Without it, transactions cannot be assigned to an account.
+func (s *TransactionService) SetAccountRepo(repo TransactionAccountRepo) {
+	s.accounts = repo
+}
+
 type CreateTransactionInput struct {
 	Type        model.TransactionType `json:"type"`
 	Amount      decimal.Decimal       `json:"amount"`
@@ -45,6 +145,12 @@ type CreateTransactionInput struct {
 	Category    string                `json:"category"`
 	Description string                `json:"description"`
 	Date        datetime.Date         `json:"date"`
+	AccountID   *uuid.UUID            `json:"accountId,omitempty"`
+	Splits      []SplitInput          `json:"splits,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	SkipRules   bool                  `json:"skipRules,omitempty"` // Keep the category, description and tags as given
+
+	ExchangeRate *decimal.Decimal `json:"exchangeRate,omitempty"` // Units of the user's currency per unit of Currency; defaults to the rate of the day
 }
 
 type UpdateTransactionInput struct {
@@ -54,26 +160,107 @@ type UpdateTransactionInput struct {
 	Category    string                `json:"category"`
 	Description string                `json:"description"`
 	Date        datetime.Date         `json:"date"`
+	AccountID   *uuid.UUID            `json:"accountId,omitempty"`
+	Splits      []SplitInput          `json:"splits,omitempty"`
+	Tags        *[]string             `json:"tags,omitempty"` // Replaces the tags when set; leaving it out keeps them
+
+	ExchangeRate *decimal.Decimal `json:"exchangeRate,omitempty"` // Units of the user's currency per unit of Currency; defaults to the rate used before
+}
+
+// SplitInput is one category line of a split transaction.
+type SplitInput struct {
+	Category string          `json:"category"`
+	Amount   decimal.Decimal `json:"amount"`
+	Note     string          `json:"note"`
 }
 
 type ListTransactionsInput struct {
 	Type       *string          `json:"type"`
 	Category   *string          `json:"category"`
-	Categories []string         `json:"categories"`  // Multiple categories
-	Search     *string          `json:"search"`      // Text search in description
-	MinAmount  *decimal.Decimal `json:"minAmount"`   // Minimum amount filter
-	MaxAmount  *decimal.Decimal `json:"maxAmount"`   // Maximum amount filter
-	DatePreset *string          `json:"datePreset"`  // Preset: last7days, last30days, thisMonth, lastMonth
+	Categories []string         `json:"categories"` // Multiple categories
+	Search     *string          `json:"search"`     // Text search in description
+	MinAmount  *decimal.Decimal `json:"minAmount"`  // Minimum amount filter
+	MaxAmount  *decimal.Decimal `json:"maxAmount"`  // Maximum amount filter
+	DatePreset *string          `json:"datePreset"` // Preset: last7days, last30days, thisMonth, lastMonth
 	StartDate  *time.Time       `json:"startDate"`
 	EndDate    *time.Time       `json:"endDate"`
+	AccountID  *uuid.UUID       `json:"accountId"`
+	Tags       []string         `json:"tags"`     // Tag names
+	TagMatch   string           `json:"tagMatch"` // any (default) or all of Tags
 	Page       int              `json:"page"`
 	PageSize   int              `json:"pageSize"`
+
+	Cursor        string `json:"cursor"`        // From a previous TransactionPage, for ListPage
+	IncludeTotals bool   `json:"includeTotals"` // Have ListPage total all matching transactions
+}
+
+// TransactionPage is one page of a user's transactions. NextCursor and
+// PrevCursor are empty at either end of the list.
+type TransactionPage struct {
+	Transactions []model.Transaction      `json:"transactions"`
+	NextCursor   string                   `json:"nextCursor,omitempty"`
+	PrevCursor   string                   `json:"prevCursor,omitempty"`
+	Totals       *model.TransactionTotals `json:"totals,omitempty"`
+}
+
+// BulkTransactionInput picks transactions either by ID or by the same filters as
+// the transactions list, and the action to run on them
+type BulkTransactionInput struct {
+	IDs        []uuid.UUID            `json:"ids,omitempty"`
+	Filter     *ListTransactionsInput `json:"filter,omitempty"` // Pages are ignored
+	Action     string                 `json:"action"`
+	Category   string                 `json:"category,omitempty"`   // recategorize
+	AddTags    []string               `json:"addTags,omitempty"`    // retag
+	RemoveTags []string               `json:"removeTags,omitempty"` // retag
+	Date       *datetime.Date         `json:"date,omitempty"`       // change_date
+	AccountID  *uuid.UUID             `json:"accountId,omitempty"`  // change_account; leave out to take transactions off their account
+	DryRun     bool                   `json:"dryRun"`               // Report what would change without saving
+}
+
+// BulkItemResult is the outcome of a bulk action for one transaction
+type BulkItemResult struct {
+	ID     uuid.UUID `json:"id"`
+	Status string    `json:"status"`
+	Reason string    `json:"reason,omitempty"` // Why it was skipped
+}
+
+// BulkTransactionResult reports a bulk action transaction by transaction
+type BulkTransactionResult struct {
+	Action   string           `json:"action"`
+	DryRun   bool             `json:"dryRun"`
+	Matched  int              `json:"matched"`  // Transactions selected
+	Affected int              `json:"affected"` // Updated or deleted, or would be in a dry run
+	Results  []BulkItemResult `json:"results"`
 }
 
 // Create validates and persists a new transaction for the given user.
 // It sets default currency to USD if not specified and validates the currency code.
+// A transaction recorded against an account defaults to the account's currency.
+// Split lines must add up to the amount; the category defaults to the first line's.
+// The user's categorization rules then run over it unless input.SkipRules is set.
 func (s *TransactionService) Create(ctx context.Context, userID uuid.UUID, input CreateTransactionInput) (*model.Transaction, error) {
+	splits, err := buildSplits(input.Amount, input.Splits)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := normalizeTags(input.Tags)
+	if err != nil {
+		return nil, err
+	}
+
 	curr := input.Currency
+	if input.AccountID != nil {
+		account, err := s.activeAccount(ctx, userID, *input.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		if curr == "" {
+			curr = account.Currency
+		}
+		if curr != account.Currency {
+			return nil, ErrAccountCurrencyMismatch
+		}
+	}
 	if curr == "" {
 		curr = string(currency.DefaultCurrency)
 	}
@@ -89,12 +276,28 @@ func (s *TransactionService) Create(ctx context.Context, userID uuid.UUID, input
 		Category:    input.Category,
 		Description: input.Description,
 		Date:        input.Date.Time,
+		AccountID:   input.AccountID,
+		Splits:      splits,
+		Tags:        tags,
+	}
+	if tx.Category == "" && len(splits) > 0 {
+		tx.Category = splits[0].Category
+	}
+	if s.rules != nil && !input.SkipRules {
+		if err := s.rules.Apply(ctx, userID, []*model.Transaction{tx}); err != nil {
+			return nil, fmt.Errorf("applying rules: %w", err)
+		}
+	}
+	if err := s.convert(ctx, userID, tx, input.ExchangeRate); err != nil {
+		return nil, err
 	}
 
 	if err := s.repo.Create(ctx, tx); err != nil {
 		return nil, fmt.Errorf("creating transaction: %w", err)
 	}
 
+	s.checkBudgetAlerts(ctx, userID, nil, tx)
+
 	return tx, nil
 }
 
@@ -111,6 +314,80 @@ func (s *TransactionService) Get(ctx context.Context, id uuid.UUID) (*model.Tran
 // List retrieves transactions for a user with optional filters and pagination.
 // PageSize is capped at 100 and defaults to 20.
 func (s *TransactionService) List(ctx context.Context, userID uuid.UUID, input ListTransactionsInput) ([]model.Transaction, error) {
+	filters := listFilters(input)
+	filters.Offset = input.Page * filters.Limit
+
+	txs, err := s.repo.List(ctx, userID, filters)
+	if err != nil {
+		return nil, fmt.Errorf("listing transactions for user %s: %w", userID, err)
+	}
+	return txs, nil
+}
+
+// ListPage retrieves a page of transactions after or before input.Cursor, or the
+// first page without one. Unlike offset pages, these stay stable while
+// transactions are added. PageSize is capped at 100 and defaults to 20; Page is
+// ignored. With IncludeTotals the page also carries totals for all matching
+// transactions.
+func (s *TransactionService) ListPage(ctx context.Context, userID uuid.UUID, input ListTransactionsInput) (*TransactionPage, error) {
+	filters := listFilters(input)
+	limit := filters.Limit
+
+	var before bool
+	if input.Cursor != "" {
+		key, b, err := decodeTransactionCursor(input.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if b {
+			filters.Before = &key
+		} else {
+			filters.After = &key
+		}
+		before = b
+	}
+
+	// One extra transaction tells whether there is another page
+	filters.Limit = limit + 1
+	txs, err := s.repo.List(ctx, userID, filters)
+	if err != nil {
+		return nil, fmt.Errorf("listing transactions for user %s: %w", userID, err)
+	}
+	more := len(txs) > limit
+	if more {
+		if before {
+			txs = txs[1:]
+		} else {
+			txs = txs[:limit]
+		}
+	}
+
+	page := &TransactionPage{Transactions: txs}
+	if page.Transactions == nil {
+		page.Transactions = []model.Transaction{}
+	}
+	if len(txs) > 0 {
+		// Paging back always leaves the cursor's own transaction after the page
+		if more || before {
+			page.NextCursor = encodeTransactionCursor(txs[len(txs)-1], false)
+		}
+		if (more && before) || (input.Cursor != "" && !before) {
+			page.PrevCursor = encodeTransactionCursor(txs[0], true)
+		}
+	}
+
+	if input.IncludeTotals {
+		page.Totals, err = s.repo.Totals(ctx, userID, filters)
+		if err != nil {
+			return nil, fmt.Errorf("totaling transactions for user %s: %w", userID, err)
+		}
+	}
+	return page, nil
+}
+
+// listFilters converts list input to repository filters, with Limit set to the
+// page size
+func listFilters(input ListTransactionsInput) repository.TransactionFilters {
 	if input.PageSize <= 0 {
 		input.PageSize = 20
 	}
@@ -130,7 +407,7 @@ func (s *TransactionService) List(ctx context.Context, userID uuid.UUID, input L
 		}
 	}
 
-	filters := repository.TransactionFilters{
+	return repository.TransactionFilters{
 		Type:       input.Type,
 		Category:   input.Category,
 		Categories: input.Categories,
@@ -139,15 +416,50 @@ func (s *TransactionService) List(ctx context.Context, userID uuid.UUID, input L
 		MaxAmount:  input.MaxAmount,
 		StartDate:  startDate,
 		EndDate:    endDate,
+		AccountID:  input.AccountID,
+		Tags:       input.Tags,
+		TagMatch:   input.TagMatch,
 		Limit:      input.PageSize,
-		Offset:     input.Page * input.PageSize,
 	}
+}
 
-	txs, err := s.repo.List(ctx, userID, filters)
+// transactionCursor is what an opaque page cursor holds: the key of the
+// transaction at the edge of a page and which way to go from it
+type transactionCursor struct {
+	Date      string    `json:"d"`
+	CreatedAt time.Time `json:"c"`
+	ID        uuid.UUID `json:"i"`
+	Before    bool      `json:"b,omitempty"`
+}
+
+// encodeTransactionCursor returns the cursor of the page after tx, or before it
+func encodeTransactionCursor(tx model.Transaction, before bool) string {
+	data, _ := json.Marshal(transactionCursor{
+		Date:      tx.Date.Format("2006-01-02"),
+		CreatedAt: tx.CreatedAt,
+		ID:        tx.ID,
+		Before:    before,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeTransactionCursor returns the key a cursor points at and whether the
+// page lies before it
+func decodeTransactionCursor(cursor string) (repository.TransactionKey, bool, error) {
+	var key repository.TransactionKey
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
 	if err != nil {
-		return nil, fmt.Errorf("listing transactions for user %s: %w", userID, err)
+		return key, false, ErrInvalidCursor
 	}
-	return txs, nil
+	var c transactionCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == uuid.Nil || c.CreatedAt.IsZero() {
+		return key, false, ErrInvalidCursor
+	}
+	date, err := time.Parse("2006-01-02", c.Date)
+	if err != nil {
+		return key, false, ErrInvalidCursor
+	}
+	return repository.TransactionKey{Date: date, CreatedAt: c.CreatedAt, ID: c.ID}, c.Before, nil
 }
 
 // resolveDatePreset converts a date preset string to start and end times.
@@ -175,7 +487,20 @@ func resolveDatePreset(preset string) (start, end time.Time) {
 
 // Update modifies an existing transaction.
 // Returns ErrTransactionNotFound if the transaction does not exist or belongs to another user.
+// The split lines are replaced by input.Splits; leaving them out removes the split.
+// The tags are only replaced when input.Tags is set.
 func (s *TransactionService) Update(ctx context.Context, id uuid.UUID, userID uuid.UUID, input UpdateTransactionInput) (*model.Transaction, error) {
+	splits, err := buildSplits(input.Amount, input.Splits)
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	if input.Tags != nil {
+		if tags, err = normalizeTags(*input.Tags); err != nil {
+			return nil, err
+		}
+	}
+
 	tx, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("fetching transaction %s for update: %w", id, err)
@@ -190,27 +515,378 @@ func (s *TransactionService) Update(ctx context.Context, id uuid.UUID, userID uu
 		return nil, fmt.Errorf("invalid currency code: %s", curr)
 	}
 
+	before := *tx
 	tx.Type = input.Type
 	tx.Amount = input.Amount
 	if curr != "" {
 		tx.Currency = curr
 	}
 	tx.Category = input.Category
+	if tx.Category == "" && len(splits) > 0 {
+		tx.Category = splits[0].Category
+	}
 	tx.Description = input.Description
 	tx.Date = input.Date.Time
+	tx.Splits = splits
+	if input.Tags != nil {
+		tx.Tags = tags
+	}
+
+	if input.AccountID != nil {
+		// Transactions already on an archived account can still be edited
+		var account *model.Account
+		if tx.AccountID != nil && *tx.AccountID == *input.AccountID {
+			account, err = s.account(ctx, userID, *input.AccountID)
+		} else {
+			account, err = s.activeAccount(ctx, userID, *input.AccountID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if tx.Currency != account.Currency {
+			return nil, ErrAccountCurrencyMismatch
+		}
+	}
+	tx.AccountID = input.AccountID
+
+	// A new currency or date needs a new rate, a new amount a new base amount
+	if tx.Currency != before.Currency || !tx.Date.Equal(before.Date) {
+		tx.BaseCurrency, tx.ExchangeRate = nil, nil
+	}
+	if tx.ExchangeRate == nil || !tx.Amount.Equal(before.Amount) {
+		tx.BaseAmount = nil
+	}
+	if err := s.convert(ctx, userID, tx, input.ExchangeRate); err != nil {
+		return nil, err
+	}
 
 	if err := s.repo.Update(ctx, tx); err != nil {
 		return nil, fmt.Errorf("updating transaction %s: %w", id, err)
 	}
 
+	s.checkBudgetAlerts(ctx, userID, &before, tx)
+
 	return tx, nil
 }
 
 // Delete removes a transaction by ID for the given user.
 // Returns ErrTransactionNotFound if the transaction does not exist or belongs to another user.
 func (s *TransactionService) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	// Budget alerts need the transaction as it was before it is removed
+	var before *model.Transaction
+	if s.budgetAlerts != nil {
+		if tx, err := s.repo.GetByID(ctx, id); err == nil && tx.UserID == userID {
+			before = tx
+		}
+	}
+
 	if err := s.repo.Delete(ctx, id, userID); err != nil {
 		return fmt.Errorf("deleting transaction %s: %w", id, err)
 	}
+
+	s.checkBudgetAlerts(ctx, userID, before, nil)
+	return nil
+}
+
+// Bulk runs one action over many transactions in a single database transaction:
+// either every change is saved or none is. Transactions the action does not
+// apply to are skipped and reported rather than failing the rest. Once saved,
+// budget alerts are checked once for all the changes together.
+func (s *TransactionService) Bulk(ctx context.Context, userID uuid.UUID, input BulkTransactionInput) (*BulkTransactionResult, error) {
+	change, err := s.bulkChange(ctx, userID, input)
+	if err != nil {
+		return nil, err
+	}
+	if (len(input.IDs) > 0) == (input.Filter != nil) {
+		return nil, ErrInvalidBulkSelector
+	}
+
+	var filters repository.TransactionFilters
+	if input.Filter != nil {
+		filters = listFilters(*input.Filter)
+	} else {
+		if len(input.IDs) > maxBulkTransactions {
+			return nil, ErrBulkTooMany
+		}
+		filters.IDs = input.IDs
+	}
+	filters.Limit = maxBulkTransactions + 1
+
+	// The transactions are selected and changed in one database transaction,
+	// so the change never overwrites an edit made in between
+	var result *BulkTransactionResult
+	var before []model.Transaction
+	var updated []model.Transaction
+	err = s.repo.ApplyBulk(ctx, userID, filters, input.DryRun, func(txs []model.Transaction) ([]model.Transaction, []uuid.UUID, error) {
+		if len(txs) > maxBulkTransactions {
+			return nil, nil, ErrBulkTooMany
+		}
+
+		result = &BulkTransactionResult{
+			Action:  input.Action,
+			DryRun:  input.DryRun,
+			Matched: len(txs),
+			Results: make([]BulkItemResult, 0, len(txs)),
+		}
+		before, updated = nil, nil
+		var deleted []uuid.UUID
+		found := make(map[uuid.UUID]bool, len(txs))
+		for i := range txs {
+			tx := &txs[i]
+			found[tx.ID] = true
+			item := BulkItemResult{ID: tx.ID}
+			original := *tx
+			switch {
+			case input.Action == BulkActionDelete:
+				item.Status = BulkStatusDeleted
+				deleted = append(deleted, tx.ID)
+				before = append(before, original)
+			default:
+				changed, skipReason := change(tx)
+				switch {
+				case skipReason != "":
+					item.Status, item.Reason = BulkStatusSkipped, skipReason
+				case !changed:
+					item.Status = BulkStatusUnchanged
+				default:
+					if input.Action == BulkActionChangeDate {
+						// Like Update, a new date means the rate of that date
+						tx.BaseCurrency, tx.ExchangeRate, tx.BaseAmount = nil, nil, nil
+						if err := s.convert(ctx, userID, tx, nil); err != nil {
+							return nil, nil, err
+						}
+					}
+					item.Status = BulkStatusUpdated
+					updated = append(updated, *tx)
+					before = append(before, original)
+				}
+			}
+			result.Results = append(result.Results, item)
+		}
+		for _, id := range input.IDs {
+			if !found[id] {
+				found[id] = true
+				result.Results = append(result.Results, BulkItemResult{ID: id, Status: BulkStatusNotFound})
+			}
+		}
+		result.Affected = len(updated) + len(deleted)
+
+		if input.DryRun {
+			return nil, nil, nil
+		}
+		return updated, deleted, nil
+	})
+	if errors.Is(err, ErrBulkTooMany) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("applying %s to transactions of user %s: %w", input.Action, userID, err)
+	}
+
+	if !input.DryRun && result.Affected > 0 && s.budgetAlerts != nil {
+		if err := s.budgetAlerts.CheckBulkAlerts(ctx, userID, before, updated); err != nil {
+			slog.Error("Budget alert check failed",
+				slog.String("user_id", userID.String()),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+	return result, nil
+}
+
+// bulkChange checks the parameters of a bulk action and returns the change it
+// makes to one transaction: whether it changed and, if the action does not
+// apply to it, why. Delete needs no change.
+func (s *TransactionService) bulkChange(ctx context.Context, userID uuid.UUID, input BulkTransactionInput) (func(tx *model.Transaction) (bool, string), error) {
+	switch input.Action {
+	case BulkActionDelete:
+		return nil, nil
+
+	case BulkActionRecategorize:
+		category := strings.TrimSpace(input.Category)
+		if category == "" || utf8.RuneCountInString(category) > maxCategoryLength {
+			return nil, ErrBulkCategoryRequired
+		}
+		return func(tx *model.Transaction) (bool, string) {
+			if len(tx.Splits) > 0 {
+				return false, "split transactions keep the categories of their lines"
+			}
+			if tx.Category == category {
+				return false, ""
+			}
+			tx.Category = category
+			return true, ""
+		}, nil
+
+	case BulkActionRetag:
+		add, err := normalizeTags(input.AddTags)
+		if err != nil {
+			return nil, err
+		}
+		remove, err := normalizeTags(input.RemoveTags)
+		if err != nil {
+			return nil, err
+		}
+		if len(add) == 0 && len(remove) == 0 {
+			return nil, ErrBulkTagsRequired
+		}
+		removed := make(map[string]bool, len(remove))
+		for _, tag := range remove {
+			removed[strings.ToLower(tag)] = true
+		}
+		return func(tx *model.Transaction) (bool, string) {
+			tags := make([]string, 0, len(tx.Tags)+len(add))
+			has := make(map[string]bool, len(tx.Tags)+len(add))
+			for _, tag := range tx.Tags {
+				if !removed[strings.ToLower(tag)] {
+					tags = append(tags, tag)
+					has[strings.ToLower(tag)] = true
+				}
+			}
+			for _, tag := range add {
+				if !has[strings.ToLower(tag)] {
+					tags = append(tags, tag)
+					has[strings.ToLower(tag)] = true
+				}
+			}
+			if len(tags) > MaxTagsPerItem {
+				return false, ErrTooManyTags.Error()
+			}
+			if len(tags) == len(tx.Tags) && sameTags(tags, tx.Tags) {
+				return false, ""
+			}
+			tx.Tags = tags
+			return true, ""
+		}, nil
+
+	case BulkActionChangeDate:
+		if input.Date == nil || input.Date.IsZero() {
+			return nil, ErrBulkDateRequired
+		}
+		date := input.Date.Time
+		return func(tx *model.Transaction) (bool, string) {
+			if tx.Date.Format("2006-01-02") == date.Format("2006-01-02") {
+				return false, ""
+			}
+			tx.Date = date
+			return true, ""
+		}, nil
+
+	case BulkActionChangeAccount:
+		if input.AccountID == nil {
+			return func(tx *model.Transaction) (bool, string) {
+				if tx.AccountID == nil {
+					return false, ""
+				}
+				tx.AccountID = nil
+				return true, ""
+			}, nil
+		}
+		account, err := s.activeAccount(ctx, userID, *input.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		return func(tx *model.Transaction) (bool, string) {
+			if tx.AccountID != nil && *tx.AccountID == account.ID {
+				return false, ""
+			}
+			if tx.Currency != account.Currency {
+				return false, ErrAccountCurrencyMismatch.Error()
+			}
+			tx.AccountID = &account.ID
+			return true, ""
+		}, nil
+
+	default:
+		return nil, ErrInvalidBulkAction
+	}
+}
+
+// sameTags reports whether two tag lists hold the same names in the same order
+func sameTags(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// buildSplits validates split lines against the transaction amount. No lines
+// means the transaction is not split.
+func buildSplits(amount decimal.Decimal, lines []SplitInput) ([]model.TransactionSplit, error) {
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	if len(lines) < 2 {
+		return nil, ErrSplitTooFewLines
+	}
+
+	splits := make([]model.TransactionSplit, len(lines))
+	total := decimal.Zero
+	for i, line := range lines {
+		category := strings.TrimSpace(line.Category)
+		if category == "" || !line.Amount.IsPositive() {
+			return nil, ErrInvalidSplitLine
+		}
+		splits[i] = model.TransactionSplit{
+			Category: category,
+			Amount:   line.Amount,
+			Note:     strings.TrimSpace(line.Note),
+		}
+		total = total.Add(line.Amount)
+	}
+	if !total.Equal(amount) {
+		return nil, ErrSplitSumMismatch
+	}
+	return splits, nil
+}
+
+// account returns one of the user's accounts
+func (s *TransactionService) account(ctx context.Context, userID, id uuid.UUID) (*model.Account, error) {
+	if s.accounts == nil {
+		return nil, repository.ErrAccountNotFound
+	}
+	account, err := s.accounts.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting account %s: %w", id, err)
+	}
+	return account, nil
+}
+
+// activeAccount returns one of the user's accounts that is not archived
+func (s *TransactionService) activeAccount(ctx context.Context, userID, id uuid.UUID) (*model.Account, error) {
+	account, err := s.account(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if account.Archived {
+		return nil, ErrAccountArchived
+	}
+	return account, nil
+}
+
+// convert sets the base currency amount of tx when a converter is set
+func (s *TransactionService) convert(ctx context.Context, userID uuid.UUID, tx *model.Transaction, rate *decimal.Decimal) error {
+	if s.converter == nil {
+		return nil
+	}
+	if err := s.converter.Convert(ctx, userID, tx, rate); err != nil {
+		return fmt.Errorf("converting to base currency: %w", err)
+	}
 	return nil
 }
+
+// checkBudgetAlerts re-evaluates budgets after a write. Failures are logged
+// rather than returned because the transaction itself was saved.
+func (s *TransactionService) checkBudgetAlerts(ctx context.Context, userID uuid.UUID, before, after *model.Transaction) {
+	if s.budgetAlerts == nil || (before == nil && after == nil) {
+		return
+	}
+	if err := s.budgetAlerts.CheckAlerts(ctx, userID, before, after); err != nil {
+		slog.Error("Budget alert check failed",
+			slog.String("user_id", userID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,13 +12,17 @@ import (
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/wealthpath/backend/internal/model"
 	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/pkg/datetime"
 )
 
 // MockTransactionRepo for testing
 type MockTransactionRepo struct {
 	mock.Mock
+	bulkUpdated []model.Transaction
+	bulkDeleted []uuid.UUID
 }
 
 func (m *MockTransactionRepo) Create(ctx context.Context, tx *model.Transaction) error {
@@ -43,6 +49,14 @@ func (m *MockTransactionRepo) List(ctx context.Context, userID uuid.UUID, filter
 	return ret.Get(0).([]model.Transaction), ret.Error(1)
 }
 
+func (m *MockTransactionRepo) Totals(ctx context.Context, userID uuid.UUID, filters repository.TransactionFilters) (*model.TransactionTotals, error) {
+	ret := m.Called(ctx, userID, filters)
+	if ret.Get(0) == nil {
+		return nil, ret.Error(1)
+	}
+	return ret.Get(0).(*model.TransactionTotals), ret.Error(1)
+}
+
 func (m *MockTransactionRepo) Update(ctx context.Context, tx *model.Transaction) error {
 	ret := m.Called(ctx, tx)
 	return ret.Error(0)
@@ -53,6 +67,21 @@ func (m *MockTransactionRepo) Delete(ctx context.Context, id, userID uuid.UUID)
 	return ret.Error(0)
 }
 
+// ApplyBulk hands the transactions the test selects to the change and keeps
+// what it saves in bulkUpdated and bulkDeleted
+func (m *MockTransactionRepo) ApplyBulk(ctx context.Context, userID uuid.UUID, filters repository.TransactionFilters, dryRun bool, change repository.BulkChange) error {
+	ret := m.Called(ctx, userID, filters, dryRun)
+	if err := ret.Error(1); err != nil {
+		return err
+	}
+	updated, deleted, err := change(ret.Get(0).([]model.Transaction))
+	if err != nil {
+		return err
+	}
+	m.bulkUpdated, m.bulkDeleted = updated, deleted
+	return nil
+}
+
 func (m *MockTransactionRepo) GetSpentByCategory(ctx context.Context, userID uuid.UUID, category string, startDate, endDate time.Time) (decimal.Decimal, error) {
 	ret := m.Called(ctx, userID, category, startDate, endDate)
 	return ret.Get(0).(decimal.Decimal), ret.Error(1)
@@ -615,7 +644,265 @@ func TestTransactionService_Delete_Error(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+// MockBudgetAlertChecker for testing
+type MockBudgetAlertChecker struct {
+	mock.Mock
+}
+
+func (m *MockBudgetAlertChecker) CheckAlerts(ctx context.Context, userID uuid.UUID, before, after *model.Transaction) error {
+	args := m.Called(ctx, userID, before, after)
+	return args.Error(0)
+}
+
+func (m *MockBudgetAlertChecker) CheckBulkAlerts(ctx context.Context, userID uuid.UUID, before, after []model.Transaction) error {
+	args := m.Called(ctx, userID, before, after)
+	return args.Error(0)
+}
+
+func TestTransactionService_BudgetAlerts(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	txID := uuid.New()
+
+	t.Run("create checks the new transaction", func(t *testing.T) {
+		mockRepo := new(MockTransactionRepo)
+		checker := new(MockBudgetAlertChecker)
+		service := NewTransactionService(mockRepo)
+		service.SetBudgetAlertChecker(checker)
+
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*model.Transaction")).Return(nil)
+		checker.On("CheckAlerts", ctx, userID, (*model.Transaction)(nil), mock.MatchedBy(func(tx *model.Transaction) bool {
+			return tx.Category == "Food" && tx.Amount.Equal(decimal.NewFromInt(100))
+		})).Return(nil)
+
+		_, err := service.Create(ctx, userID, CreateTransactionInput{
+			Type:     model.TransactionTypeExpense,
+			Amount:   decimal.NewFromInt(100),
+			Category: "Food",
+		})
+
+		assert.NoError(t, err)
+		checker.AssertExpectations(t)
+	})
+
+	t.Run("update passes the previous and new values", func(t *testing.T) {
+		mockRepo := new(MockTransactionRepo)
+		checker := new(MockBudgetAlertChecker)
+		service := NewTransactionService(mockRepo)
+		service.SetBudgetAlertChecker(checker)
+
+		existing := &model.Transaction{ID: txID, UserID: userID, Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(50), Category: "Food"}
+		mockRepo.On("GetByID", ctx, txID).Return(existing, nil)
+		mockRepo.On("Update", ctx, mock.AnythingOfType("*model.Transaction")).Return(nil)
+		checker.On("CheckAlerts", ctx, userID,
+			mock.MatchedBy(func(tx *model.Transaction) bool {
+				return tx.Category == "Food" && tx.Amount.Equal(decimal.NewFromInt(50))
+			}),
+			mock.MatchedBy(func(tx *model.Transaction) bool {
+				return tx.Category == "Shopping" && tx.Amount.Equal(decimal.NewFromInt(75))
+			}),
+		).Return(nil)
+
+		_, err := service.Update(ctx, txID, userID, UpdateTransactionInput{
+			Type:     model.TransactionTypeExpense,
+			Amount:   decimal.NewFromInt(75),
+			Category: "Shopping",
+		})
+
+		assert.NoError(t, err)
+		checker.AssertExpectations(t)
+	})
+
+	t.Run("delete passes the removed transaction", func(t *testing.T) {
+		mockRepo := new(MockTransactionRepo)
+		checker := new(MockBudgetAlertChecker)
+		service := NewTransactionService(mockRepo)
+		service.SetBudgetAlertChecker(checker)
+
+		existing := &model.Transaction{ID: txID, UserID: userID, Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(50), Category: "Food"}
+		mockRepo.On("GetByID", ctx, txID).Return(existing, nil)
+		mockRepo.On("Delete", ctx, txID, userID).Return(nil)
+		checker.On("CheckAlerts", ctx, userID, existing, (*model.Transaction)(nil)).Return(nil)
+
+		err := service.Delete(ctx, txID, userID)
+
+		assert.NoError(t, err)
+		checker.AssertExpectations(t)
+	})
+
+	t.Run("bulk checks all the changes once", func(t *testing.T) {
+		mockRepo := new(MockTransactionRepo)
+		checker := new(MockBudgetAlertChecker)
+		service := NewTransactionService(mockRepo)
+		service.SetBudgetAlertChecker(checker)
+
+		coffee := model.Transaction{ID: uuid.New(), UserID: userID, Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(5), Category: "Coffee"}
+		lunch := model.Transaction{ID: uuid.New(), UserID: userID, Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(12), Category: "Food"}
+		mockRepo.On("ApplyBulk", ctx, userID, mock.Anything, false).Return([]model.Transaction{coffee, lunch}, nil)
+		checker.On("CheckBulkAlerts", ctx, userID, []model.Transaction{coffee}, mock.MatchedBy(func(after []model.Transaction) bool {
+			return len(after) == 1 && after[0].ID == coffee.ID && after[0].Category == "Food"
+		})).Return(nil).Once()
+
+		_, err := service.Bulk(ctx, userID, BulkTransactionInput{
+			IDs: []uuid.UUID{coffee.ID, lunch.ID}, Action: BulkActionRecategorize, Category: "Food",
+		})
+
+		assert.NoError(t, err)
+		checker.AssertExpectations(t)
+		checker.AssertNotCalled(t, "CheckAlerts", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("bulk dry run checks nothing", func(t *testing.T) {
+		mockRepo := new(MockTransactionRepo)
+		checker := new(MockBudgetAlertChecker)
+		service := NewTransactionService(mockRepo)
+		service.SetBudgetAlertChecker(checker)
+
+		existing := model.Transaction{ID: txID, UserID: userID, Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(50), Category: "Food"}
+		mockRepo.On("ApplyBulk", ctx, userID, mock.Anything, true).Return([]model.Transaction{existing}, nil)
+
+		_, err := service.Bulk(ctx, userID, BulkTransactionInput{IDs: []uuid.UUID{txID}, Action: BulkActionDelete, DryRun: true})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		checker.AssertNotCalled(t, "CheckBulkAlerts", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("check failure does not fail the write", func(t *testing.T) {
+		mockRepo := new(MockTransactionRepo)
+		checker := new(MockBudgetAlertChecker)
+		service := NewTransactionService(mockRepo)
+		service.SetBudgetAlertChecker(checker)
+
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*model.Transaction")).Return(nil)
+		checker.On("CheckAlerts", ctx, userID, mock.Anything, mock.Anything).Return(errors.New("push failed"))
+
+		tx, err := service.Create(ctx, userID, CreateTransactionInput{
+			Type:     model.TransactionTypeExpense,
+			Amount:   decimal.NewFromInt(100),
+			Category: "Food",
+		})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, tx)
+	})
+}
+
 // Test categories
+func TestTransactionService_Conversion(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	txID := uuid.New()
+	march := datetime.Date{Time: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	april := datetime.Date{Time: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)}
+
+	newService := func(rates map[string]string) (*TransactionService, *MockTransactionRepo, *MockExchangeRateRepository) {
+		mockRepo := new(MockTransactionRepo)
+		rateRepo := new(MockExchangeRateRepository)
+		rateRepo.On("UserCurrency", ctx, userID).Return("VND", nil)
+		onRates(rateRepo, rates)
+		service := NewTransactionService(mockRepo)
+		service.SetConverter(NewExchangeRateService(rateRepo))
+		return service, mockRepo, rateRepo
+	}
+
+	t.Run("create converts at the rate of the day", func(t *testing.T) {
+		service, mockRepo, _ := newService(map[string]string{"USD/VND": "25000"})
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*model.Transaction")).Return(nil)
+
+		tx, err := service.Create(ctx, userID, CreateTransactionInput{
+			Type: model.TransactionTypeExpense, Amount: decimal.RequireFromString("12.34"), Currency: "USD", Category: "Food", Date: march,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "USD", tx.Currency)
+		assert.Equal(t, "12.34", tx.Amount.String())
+		assert.Equal(t, "VND", *tx.BaseCurrency)
+		assert.Equal(t, "308500", tx.BaseAmount.String())
+	})
+
+	t.Run("create uses the given rate", func(t *testing.T) {
+		service, mockRepo, rateRepo := newService(nil)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*model.Transaction")).Return(nil)
+		rate := decimal.NewFromInt(24000)
+
+		tx, err := service.Create(ctx, userID, CreateTransactionInput{
+			Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(10), Currency: "USD", Category: "Food", Date: march, ExchangeRate: &rate,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "240000", tx.BaseAmount.String())
+		rateRepo.AssertNotCalled(t, "FindRate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("create rejects a rate of zero", func(t *testing.T) {
+		service, _, _ := newService(nil)
+		rate := decimal.Zero
+
+		_, err := service.Create(ctx, userID, CreateTransactionInput{
+			Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(10), Currency: "USD", Category: "Food", Date: march, ExchangeRate: &rate,
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidExchangeRate)
+	})
+
+	t.Run("bulk date change converts at the rate of the new date", func(t *testing.T) {
+		service, mockRepo, _ := newService(map[string]string{"USD/VND": "25000"})
+		base, rate, baseAmount := "VND", decimal.NewFromInt(24000), decimal.NewFromInt(240000)
+		existing := model.Transaction{
+			ID: txID, UserID: userID, Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(10), Currency: "USD", Date: march.Time,
+			BaseCurrency: &base, ExchangeRate: &rate, BaseAmount: &baseAmount,
+		}
+		mockRepo.On("ApplyBulk", ctx, userID, mock.Anything, false).Return([]model.Transaction{existing}, nil)
+
+		_, err := service.Bulk(ctx, userID, BulkTransactionInput{IDs: []uuid.UUID{txID}, Action: BulkActionChangeDate, Date: &april})
+
+		require.NoError(t, err)
+		require.Len(t, mockRepo.bulkUpdated, 1)
+		assert.Equal(t, "25000", mockRepo.bulkUpdated[0].ExchangeRate.String())
+		assert.Equal(t, "250000", mockRepo.bulkUpdated[0].BaseAmount.String())
+	})
+
+	t.Run("update of the amount keeps the rate used before", func(t *testing.T) {
+		service, mockRepo, rateRepo := newService(map[string]string{"USD/VND": "25000"})
+		base, rate, baseAmount := "VND", decimal.NewFromInt(24000), decimal.NewFromInt(240000)
+		existing := &model.Transaction{
+			ID: txID, UserID: userID, Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(10), Currency: "USD", Date: march.Time,
+			BaseCurrency: &base, ExchangeRate: &rate, BaseAmount: &baseAmount,
+		}
+		mockRepo.On("GetByID", ctx, txID).Return(existing, nil)
+		mockRepo.On("Update", ctx, mock.AnythingOfType("*model.Transaction")).Return(nil)
+
+		tx, err := service.Update(ctx, txID, userID, UpdateTransactionInput{
+			Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(20), Category: "Food", Date: march,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "24000", tx.ExchangeRate.String())
+		assert.Equal(t, "480000", tx.BaseAmount.String())
+		rateRepo.AssertNotCalled(t, "FindRate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("update of the date takes that day's rate", func(t *testing.T) {
+		service, mockRepo, _ := newService(map[string]string{"USD/VND": "25000"})
+		base, rate, baseAmount := "VND", decimal.NewFromInt(24000), decimal.NewFromInt(240000)
+		existing := &model.Transaction{
+			ID: txID, UserID: userID, Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(10), Currency: "USD", Date: march.Time,
+			BaseCurrency: &base, ExchangeRate: &rate, BaseAmount: &baseAmount,
+		}
+		mockRepo.On("GetByID", ctx, txID).Return(existing, nil)
+		mockRepo.On("Update", ctx, mock.AnythingOfType("*model.Transaction")).Return(nil)
+
+		tx, err := service.Update(ctx, txID, userID, UpdateTransactionInput{
+			Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(10), Category: "Food", Date: april,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "25000", tx.ExchangeRate.String())
+		assert.Equal(t, "250000", tx.BaseAmount.String())
+	})
+}
+
 func TestExpenseCategories(t *testing.T) {
 	expectedCategories := []string{
 		"Housing", "Transportation", "Food & Dining", "Utilities",
@@ -652,3 +939,564 @@ func BenchmarkCreateTransactionInput_Validation(b *testing.B) {
 		_ = input.Type == "" || input.Amount.LessThanOrEqual(decimal.Zero) || input.Category == ""
 	}
 }
+
+func TestTransactionService_Accounts(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	wallet := &model.Account{ID: uuid.New(), UserID: userID, Currency: "VND"}
+	closed := &model.Account{ID: uuid.New(), UserID: userID, Currency: "VND", Archived: true}
+	unknown := uuid.New()
+
+	newService := func() (*TransactionService, *MockTransactionRepo) {
+		repo := new(MockTransactionRepo)
+		accounts := new(MockAccountRepository)
+		accounts.On("GetByID", mock.Anything, userID, wallet.ID).Return(wallet, nil)
+		accounts.On("GetByID", mock.Anything, userID, closed.ID).Return(closed, nil)
+		accounts.On("GetByID", mock.Anything, userID, unknown).Return(nil, repository.ErrAccountNotFound)
+		repo.On("Create", mock.Anything, mock.AnythingOfType("*model.Transaction")).Return(nil)
+		repo.On("Update", mock.Anything, mock.AnythingOfType("*model.Transaction")).Return(nil)
+
+		svc := NewTransactionService(repo)
+		svc.SetAccountRepo(accounts)
+		return svc, repo
+	}
+
+	t.Run("create defaults to the account currency", func(t *testing.T) {
+		t.Parallel()
+		svc, _ := newService()
+
+		tx, err := svc.Create(context.Background(), userID, CreateTransactionInput{
+			Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(50000), Category: "Food", AccountID: &wallet.ID,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "VND", tx.Currency)
+		assert.Equal(t, &wallet.ID, tx.AccountID)
+	})
+
+	createErrors := []struct {
+		name    string
+		input   CreateTransactionInput
+		wantErr error
+	}{
+		{"currency mismatch", CreateTransactionInput{Currency: "USD", AccountID: &wallet.ID}, ErrAccountCurrencyMismatch},
+		{"archived account", CreateTransactionInput{AccountID: &closed.ID}, ErrAccountArchived},
+		{"unknown account", CreateTransactionInput{AccountID: &unknown}, repository.ErrAccountNotFound},
+	}
+	for _, tt := range createErrors {
+		tt := tt
+		t.Run("create "+tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc, repo := newService()
+
+			_, err := svc.Create(context.Background(), userID, tt.input)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("without an account repository no account can be assigned", func(t *testing.T) {
+		t.Parallel()
+		svc := NewTransactionService(new(MockTransactionRepo))
+
+		_, err := svc.Create(context.Background(), userID, CreateTransactionInput{AccountID: &wallet.ID})
+
+		assert.ErrorIs(t, err, repository.ErrAccountNotFound)
+	})
+
+	t.Run("update keeps an archived account", func(t *testing.T) {
+		t.Parallel()
+		svc, repo := newService()
+		txID := uuid.New()
+		repo.On("GetByID", mock.Anything, txID).Return(&model.Transaction{
+			ID: txID, UserID: userID, Currency: "VND", AccountID: &closed.ID,
+		}, nil)
+
+		tx, err := svc.Update(context.Background(), txID, userID, UpdateTransactionInput{
+			Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(1), AccountID: &closed.ID,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, &closed.ID, tx.AccountID)
+	})
+
+	t.Run("update cannot move to an archived account", func(t *testing.T) {
+		t.Parallel()
+		svc, repo := newService()
+		txID := uuid.New()
+		repo.On("GetByID", mock.Anything, txID).Return(&model.Transaction{
+			ID: txID, UserID: userID, Currency: "VND", AccountID: &wallet.ID,
+		}, nil)
+
+		_, err := svc.Update(context.Background(), txID, userID, UpdateTransactionInput{AccountID: &closed.ID})
+
+		assert.ErrorIs(t, err, ErrAccountArchived)
+	})
+
+	t.Run("update without an account clears it", func(t *testing.T) {
+		t.Parallel()
+		svc, repo := newService()
+		txID := uuid.New()
+		repo.On("GetByID", mock.Anything, txID).Return(&model.Transaction{
+			ID: txID, UserID: userID, Currency: "VND", AccountID: &wallet.ID,
+		}, nil)
+
+		tx, err := svc.Update(context.Background(), txID, userID, UpdateTransactionInput{})
+
+		require.NoError(t, err)
+		assert.Nil(t, tx.AccountID)
+	})
+}
+
+func TestTransactionService_Splits(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	lines := func(amounts ...int64) []SplitInput {
+		categories := []string{"Groceries", "Household", "Personal Care"}
+		splits := make([]SplitInput, len(amounts))
+		for i, amount := range amounts {
+			splits[i] = SplitInput{Category: categories[i], Amount: decimal.NewFromInt(amount)}
+		}
+		return splits
+	}
+
+	tests := []struct {
+		name    string
+		splits  []SplitInput
+		wantErr error
+	}{
+		{name: "lines add up", splits: lines(200, 80, 20)},
+		{name: "single line", splits: lines(300), wantErr: ErrSplitTooFewLines},
+		{name: "lines do not add up", splits: lines(200, 50), wantErr: ErrSplitSumMismatch},
+		{name: "zero line", splits: lines(300, 0), wantErr: ErrInvalidSplitLine},
+		{
+			name:    "missing category",
+			splits:  []SplitInput{{Category: "Groceries", Amount: decimal.NewFromInt(200)}, {Category: " ", Amount: decimal.NewFromInt(100)}},
+			wantErr: ErrInvalidSplitLine,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockTransactionRepo)
+			repo.On("Create", mock.Anything, mock.AnythingOfType("*model.Transaction")).Return(nil).Maybe()
+			svc := NewTransactionService(repo)
+
+			tx, err := svc.Create(context.Background(), userID, CreateTransactionInput{
+				Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(300), Splits: tt.splits,
+			})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Groceries", tx.Category) // Defaults to the first line
+			require.Len(t, tx.Splits, 3)
+			assert.Equal(t, "Household", tx.Splits[1].Category)
+		})
+	}
+
+	t.Run("update without splits removes them", func(t *testing.T) {
+		t.Parallel()
+
+		id := uuid.New()
+		repo := new(MockTransactionRepo)
+		repo.On("GetByID", mock.Anything, id).Return(&model.Transaction{
+			ID: id, UserID: userID, Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(300), Currency: "USD",
+			Category: "Groceries", Splits: []model.TransactionSplit{{Category: "Groceries"}, {Category: "Household"}},
+		}, nil)
+		repo.On("Update", mock.Anything, mock.AnythingOfType("*model.Transaction")).Return(nil)
+		svc := NewTransactionService(repo)
+
+		tx, err := svc.Update(context.Background(), id, userID, UpdateTransactionInput{
+			Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(300), Category: "Groceries",
+		})
+
+		require.NoError(t, err)
+		assert.Empty(t, tx.Splits)
+	})
+}
+
+func TestTransactionService_Tags(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	longTag := strings.Repeat("a", 51)
+	manyTags := make([]string, MaxTagsPerItem+1)
+	for i := range manyTags {
+		manyTags[i] = fmt.Sprintf("tag%d", i)
+	}
+
+	tests := []struct {
+		name     string
+		tags     []string
+		wantTags []string
+		wantErr  error
+	}{
+		{name: "trimmed and deduplicated", tags: []string{" Vacation ", "family", "vacation"}, wantTags: []string{"Vacation", "family"}},
+		{name: "no tags", tags: nil, wantTags: nil},
+		{name: "empty tag", tags: []string{"work", " "}, wantErr: ErrInvalidTag},
+		{name: "tag with comma", tags: []string{"work, travel"}, wantErr: ErrInvalidTag},
+		{name: "tag too long", tags: []string{longTag}, wantErr: ErrInvalidTag},
+		{name: "too many tags", tags: manyTags, wantErr: ErrTooManyTags},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockTransactionRepo)
+			repo.On("Create", mock.Anything, mock.AnythingOfType("*model.Transaction")).Return(nil).Maybe()
+			svc := NewTransactionService(repo)
+
+			tx, err := svc.Create(context.Background(), userID, CreateTransactionInput{
+				Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(50), Category: "Travel", Tags: tt.tags,
+			})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTags, tx.Tags)
+		})
+	}
+
+	updates := []struct {
+		name     string
+		tags     *[]string
+		wantTags []string
+	}{
+		{name: "update without tags keeps them", tags: nil, wantTags: []string{"work"}},
+		{name: "update with tags replaces them", tags: &[]string{"trip"}, wantTags: []string{"trip"}},
+		{name: "update with no tags clears them", tags: &[]string{}, wantTags: nil},
+	}
+
+	for _, tt := range updates {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			id := uuid.New()
+			repo := new(MockTransactionRepo)
+			repo.On("GetByID", mock.Anything, id).Return(&model.Transaction{
+				ID: id, UserID: userID, Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(50), Currency: "USD",
+				Category: "Travel", Tags: []string{"work"},
+			}, nil)
+			repo.On("Update", mock.Anything, mock.AnythingOfType("*model.Transaction")).Return(nil)
+			svc := NewTransactionService(repo)
+
+			tx, err := svc.Update(context.Background(), id, userID, UpdateTransactionInput{
+				Type: model.TransactionTypeExpense, Amount: decimal.NewFromInt(50), Category: "Travel", Tags: tt.tags,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTags, tx.Tags)
+		})
+	}
+
+	t.Run("list passes tag filters through", func(t *testing.T) {
+		t.Parallel()
+
+		repo := new(MockTransactionRepo)
+		repo.On("List", mock.Anything, userID, mock.MatchedBy(func(f repository.TransactionFilters) bool {
+			return len(f.Tags) == 2 && f.TagMatch == repository.TagMatchAll
+		})).Return([]model.Transaction{}, nil)
+		svc := NewTransactionService(repo)
+
+		_, err := svc.List(context.Background(), userID, ListTransactionsInput{
+			Tags: []string{"vacation", "family"}, TagMatch: repository.TagMatchAll,
+		})
+
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestTransactionService_ListPage(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	txs := make([]model.Transaction, 4)
+	for i := range txs {
+		txs[i] = model.Transaction{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Date:      time.Date(2024, 3, 10-i, 0, 0, 0, 0, time.UTC),
+			CreatedAt: time.Date(2024, 3, 10, 8, 30, i, 123456000, time.UTC),
+		}
+	}
+	after := encodeTransactionCursor(txs[0], false)
+	before := encodeTransactionCursor(txs[3], true)
+
+	tests := []struct {
+		name      string
+		cursor    string
+		found     []model.Transaction
+		wantKey   func(f repository.TransactionFilters) bool
+		wantIDs   []uuid.UUID
+		wantNext  bool
+		wantPrev  bool
+		wantError error
+	}{
+		{
+			name:     "first page with more after it",
+			found:    txs[:3],
+			wantKey:  func(f repository.TransactionFilters) bool { return f.After == nil && f.Before == nil },
+			wantIDs:  []uuid.UUID{txs[0].ID, txs[1].ID},
+			wantNext: true,
+		},
+		{
+			name:   "last page after a cursor",
+			cursor: after,
+			found:  txs[1:3],
+			wantKey: func(f repository.TransactionFilters) bool {
+				return f.After != nil && f.After.ID == txs[0].ID && f.After.Date.Equal(txs[0].Date) && f.After.CreatedAt.Equal(txs[0].CreatedAt)
+			},
+			wantIDs:  []uuid.UUID{txs[1].ID, txs[2].ID},
+			wantPrev: true,
+		},
+		{
+			name:     "page before a cursor with more before it",
+			cursor:   before,
+			found:    txs[:3],
+			wantKey:  func(f repository.TransactionFilters) bool { return f.Before != nil && f.Before.ID == txs[3].ID },
+			wantIDs:  []uuid.UUID{txs[1].ID, txs[2].ID},
+			wantNext: true,
+			wantPrev: true,
+		},
+		{
+			name:      "invalid cursor",
+			cursor:    "not-a-cursor",
+			wantError: ErrInvalidCursor,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockTransactionRepo)
+			if tt.wantKey != nil {
+				repo.On("List", mock.Anything, userID, mock.MatchedBy(func(f repository.TransactionFilters) bool {
+					return f.Limit == 3 && f.Offset == 0 && tt.wantKey(f)
+				})).Return(tt.found, nil)
+			}
+
+			page, err := NewTransactionService(repo).ListPage(context.Background(), userID, ListTransactionsInput{
+				Page:     5,
+				PageSize: 2,
+				Cursor:   tt.cursor,
+			})
+
+			if tt.wantError != nil {
+				assert.ErrorIs(t, err, tt.wantError)
+				return
+			}
+			require.NoError(t, err)
+			ids := make([]uuid.UUID, len(page.Transactions))
+			for i, tx := range page.Transactions {
+				ids[i] = tx.ID
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			assert.Equal(t, tt.wantNext, page.NextCursor != "")
+			assert.Equal(t, tt.wantPrev, page.PrevCursor != "")
+			assert.Nil(t, page.Totals)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTransactionService_ListPage_Totals(t *testing.T) {
+	t.Parallel()
+
+	repo := new(MockTransactionRepo)
+	userID := uuid.New()
+	income := "income"
+	totals := &model.TransactionTotals{Count: 42, Income: decimal.NewFromInt(1000)}
+
+	repo.On("List", mock.Anything, userID, mock.Anything).Return([]model.Transaction{}, nil)
+	repo.On("Totals", mock.Anything, userID, mock.MatchedBy(func(f repository.TransactionFilters) bool {
+		return f.Type != nil && *f.Type == income
+	})).Return(totals, nil)
+
+	page, err := NewTransactionService(repo).ListPage(context.Background(), userID, ListTransactionsInput{
+		Type:          &income,
+		IncludeTotals: true,
+	})
+
+	require.NoError(t, err)
+	assert.Empty(t, page.Transactions)
+	assert.NotNil(t, page.Transactions)
+	assert.Empty(t, page.NextCursor)
+	assert.Equal(t, totals, page.Totals)
+	repo.AssertExpectations(t)
+}
+
+func TestTransactionService_Bulk(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	wallet := &model.Account{ID: uuid.New(), UserID: userID, Currency: "VND"}
+	grab := model.Transaction{ID: uuid.New(), UserID: userID, Currency: "VND", Category: "Other", Tags: []string{"work"}}
+	coffee := model.Transaction{ID: uuid.New(), UserID: userID, Currency: "VND", Category: "Food & Dining", AccountID: &wallet.ID}
+	split := model.Transaction{ID: uuid.New(), UserID: userID, Currency: "USD", Category: "Shopping",
+		Splits: []model.TransactionSplit{{Category: "Shopping"}, {Category: "Groceries"}}}
+	missing := uuid.New()
+	march := datetime.NewDate(2024, 3, 5)
+	expense := "expense"
+
+	tests := []struct {
+		name        string
+		input       BulkTransactionInput
+		found       []model.Transaction
+		wantErr     error
+		wantStatus  []string
+		wantUpdated int
+		wantDeleted int
+		check       func(t *testing.T, updated []model.Transaction)
+	}{
+		{
+			name:        "recategorize by ID",
+			input:       BulkTransactionInput{IDs: []uuid.UUID{grab.ID, coffee.ID, split.ID, missing}, Action: BulkActionRecategorize, Category: " Food & Dining "},
+			found:       []model.Transaction{grab, coffee, split},
+			wantStatus:  []string{BulkStatusUpdated, BulkStatusUnchanged, BulkStatusSkipped, BulkStatusNotFound},
+			wantUpdated: 1,
+			check: func(t *testing.T, updated []model.Transaction) {
+				assert.Equal(t, "Food & Dining", updated[0].Category)
+			},
+		},
+		{
+			name:        "retag by filter",
+			input:       BulkTransactionInput{Filter: &ListTransactionsInput{Type: &expense}, Action: BulkActionRetag, AddTags: []string{"Trip"}, RemoveTags: []string{"WORK"}},
+			found:       []model.Transaction{grab},
+			wantStatus:  []string{BulkStatusUpdated},
+			wantUpdated: 1,
+			check: func(t *testing.T, updated []model.Transaction) {
+				assert.Equal(t, []string{"Trip"}, updated[0].Tags)
+			},
+		},
+		{
+			name:        "change date",
+			input:       BulkTransactionInput{IDs: []uuid.UUID{grab.ID}, Action: BulkActionChangeDate, Date: &march},
+			found:       []model.Transaction{grab},
+			wantStatus:  []string{BulkStatusUpdated},
+			wantUpdated: 1,
+			check: func(t *testing.T, updated []model.Transaction) {
+				assert.Equal(t, "2024-03-05", updated[0].Date.Format("2006-01-02"))
+			},
+		},
+		{
+			name:        "change account skips other currencies",
+			input:       BulkTransactionInput{IDs: []uuid.UUID{grab.ID, split.ID}, Action: BulkActionChangeAccount, AccountID: &wallet.ID},
+			found:       []model.Transaction{grab, split},
+			wantStatus:  []string{BulkStatusUpdated, BulkStatusSkipped},
+			wantUpdated: 1,
+			check: func(t *testing.T, updated []model.Transaction) {
+				assert.Equal(t, wallet.ID, *updated[0].AccountID)
+			},
+		},
+		{
+			name:        "delete",
+			input:       BulkTransactionInput{IDs: []uuid.UUID{grab.ID, coffee.ID}, Action: BulkActionDelete},
+			found:       []model.Transaction{grab, coffee},
+			wantStatus:  []string{BulkStatusDeleted, BulkStatusDeleted},
+			wantDeleted: 2,
+		},
+		{
+			name:        "dry run saves nothing",
+			input:       BulkTransactionInput{IDs: []uuid.UUID{grab.ID}, Action: BulkActionDelete, DryRun: true},
+			found:       []model.Transaction{grab},
+			wantStatus:  []string{BulkStatusDeleted},
+			wantDeleted: 1,
+		},
+		{
+			name:    "unknown action",
+			input:   BulkTransactionInput{IDs: []uuid.UUID{grab.ID}, Action: "archive"},
+			wantErr: ErrInvalidBulkAction,
+		},
+		{
+			name:    "both selectors",
+			input:   BulkTransactionInput{IDs: []uuid.UUID{grab.ID}, Filter: &ListTransactionsInput{}, Action: BulkActionDelete},
+			wantErr: ErrInvalidBulkSelector,
+		},
+		{
+			name:    "no selector",
+			input:   BulkTransactionInput{Action: BulkActionDelete},
+			wantErr: ErrInvalidBulkSelector,
+		},
+		{
+			name:    "retag without tags",
+			input:   BulkTransactionInput{IDs: []uuid.UUID{grab.ID}, Action: BulkActionRetag},
+			wantErr: ErrBulkTagsRequired,
+		},
+		{
+			name:    "change date without a date",
+			input:   BulkTransactionInput{IDs: []uuid.UUID{grab.ID}, Action: BulkActionChangeDate},
+			wantErr: ErrBulkDateRequired,
+		},
+		{
+			name:    "too many matches",
+			input:   BulkTransactionInput{Filter: &ListTransactionsInput{}, Action: BulkActionDelete},
+			found:   make([]model.Transaction, maxBulkTransactions+1),
+			wantErr: ErrBulkTooMany,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := new(MockTransactionRepo)
+			accounts := new(MockAccountRepository)
+			accounts.On("GetByID", mock.Anything, userID, wallet.ID).Return(wallet, nil)
+			if tt.found != nil {
+				repo.On("ApplyBulk", mock.Anything, userID, mock.MatchedBy(func(f repository.TransactionFilters) bool {
+					return f.Limit == maxBulkTransactions+1 && f.Offset == 0
+				}), tt.input.DryRun).Return(tt.found, nil)
+			}
+
+			svc := NewTransactionService(repo)
+			svc.SetAccountRepo(accounts)
+			result, err := svc.Bulk(context.Background(), userID, tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, repo.bulkUpdated)
+				assert.Nil(t, repo.bulkDeleted)
+				return
+			}
+			require.NoError(t, err)
+			statuses := make([]string, len(result.Results))
+			for i, item := range result.Results {
+				statuses[i] = item.Status
+			}
+			assert.Equal(t, tt.wantStatus, statuses)
+			assert.Equal(t, tt.wantUpdated+tt.wantDeleted, result.Affected)
+
+			if tt.input.DryRun {
+				assert.Nil(t, repo.bulkUpdated)
+				assert.Nil(t, repo.bulkDeleted)
+				return
+			}
+			assert.Len(t, repo.bulkDeleted, tt.wantDeleted)
+			updated := repo.bulkUpdated
+			require.Len(t, updated, tt.wantUpdated)
+			if tt.check != nil {
+				tt.check(t, updated)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"strings"
 	"time"
@@ -16,6 +17,7 @@ import (
 	"github.com/pquerna/otp/totp"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/wealthpath/backend/internal/email"
 	"github.com/wealthpath/backend/internal/model"
 	"github.com/wealthpath/backend/internal/repository"
 	"github.com/wealthpath/backend/pkg/currency"
@@ -35,6 +37,7 @@ var (
 	ErrEmailTaken            = errors.New("email already taken")
 	ErrOAuthFailed           = errors.New("OAuth authentication failed")
 	ErrUnsupportedCurrency   = errors.New("unsupported currency")
+	ErrUnsupportedLocale     = errors.New("unsupported locale")
 	ErrTOTPRequired          = errors.New("2FA verification required")
 	ErrRefreshTokenInvalid   = errors.New("refresh token invalid")
 	ErrRefreshTokenExpired   = errors.New("refresh token expired")
@@ -68,10 +71,17 @@ type RefreshTokenRepositoryInterface interface {
 	DeleteExpired(ctx context.Context) (int64, error)
 }
 
+// UserCurrencyConverter converts a user's transactions to their base currency
+// (e.g. ExchangeRateService).
+type UserCurrencyConverter interface {
+	ConvertUser(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
 // UserService handles business logic for user authentication and profile management.
 type UserService struct {
 	repo         UserRepositoryInterface
 	refreshRepo  RefreshTokenRepositoryInterface
+	converter    UserCurrencyConverter
 }
 
 // NewUserService creates a new UserService with the given repository.
@@ -89,6 +99,12 @@ func (s *UserService) SetRefreshTokenRepo(repo RefreshTokenRepositoryInterface)
 	s.refreshRepo = repo
 }
 
+// SetCurrencyConverter sets the converter run when a user changes currency.
+// Without it, their transactions are converted by the next scheduled run.
+func (s *UserService) SetCurrencyConverter(converter UserCurrencyConverter) {
+	s.converter = converter
+}
+
 type RegisterInput struct {
 	Email      string `json:"email"`
 	Password   string `json:"password"`
@@ -395,10 +411,13 @@ var SupportedCurrencies = currency.SupportedCurrencyCodes()
 type UpdateSettingsInput struct {
 	Name     *string `json:"name"`
 	Currency *string `json:"currency"`
+	Locale   *string `json:"locale"` // Language of the user's emails: vi or en
 }
 
-// UpdateSettings updates user profile settings (name, currency).
-// Returns ErrUnsupportedCurrency if the currency is not supported.
+// UpdateSettings updates user profile settings (name, currency, locale).
+// A new currency converts the user's transactions to it where rates are known.
+// Returns ErrUnsupportedCurrency if the currency is not supported and
+// ErrUnsupportedLocale if the locale is not one emails are written in.
 func (s *UserService) UpdateSettings(ctx context.Context, userID uuid.UUID, input UpdateSettingsInput) (*model.User, error) {
 	user, err := s.repo.GetByID(ctx, userID)
 	if err != nil {
@@ -409,6 +428,7 @@ func (s *UserService) UpdateSettings(ctx context.Context, userID uuid.UUID, inpu
 		user.Name = *input.Name
 	}
 
+	previousCurrency := user.Currency
 	if input.Currency != nil && *input.Currency != "" {
 		if !currency.IsValid(*input.Currency) {
 			return nil, ErrUnsupportedCurrency
@@ -416,10 +436,29 @@ func (s *UserService) UpdateSettings(ctx context.Context, userID uuid.UUID, inpu
 		user.Currency = *input.Currency
 	}
 
+	if input.Locale != nil && *input.Locale != "" {
+		switch *input.Locale {
+		case email.LocaleVietnamese, email.LocaleEnglish:
+			user.Locale = *input.Locale
+		default:
+			return nil, ErrUnsupportedLocale
+		}
+	}
+
 	if err := s.repo.Update(ctx, user); err != nil {
 		return nil, fmt.Errorf("updating user %s: %w", userID, err)
 	}
 
+	// Totals are in the base currency, so convert to the new one straight away
+	if s.converter != nil && user.Currency != previousCurrency {
+		if _, err := s.converter.ConvertUser(ctx, userID); err != nil {
+			slog.Error("Converting transactions to new currency failed",
+				slog.String("user_id", userID.String()),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
 	return user, nil
 }
 
@@ -301,6 +301,31 @@ func TestUserService_UpdateSettings(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "locale",
+			input: func() UpdateSettingsInput {
+				locale := "en"
+				return UpdateSettingsInput{Locale: &locale}
+			}(),
+			setupMock: func(m *MockUserRepo, id uuid.UUID) {
+				m.On("GetByID", mock.Anything, id).Return(&model.User{ID: id, Currency: "VND", Locale: "vi"}, nil)
+				m.On("Update", mock.Anything, mock.MatchedBy(func(u *model.User) bool {
+					return u.Locale == "en"
+				})).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "unsupported locale",
+			input: func() UpdateSettingsInput {
+				locale := "fr"
+				return UpdateSettingsInput{Locale: &locale}
+			}(),
+			setupMock: func(m *MockUserRepo, id uuid.UUID) {
+				m.On("GetByID", mock.Anything, id).Return(&model.User{ID: id, Locale: "vi"}, nil)
+			},
+			wantErr: true,
+		},
 		{
 			name:  "user not found",
 			input: UpdateSettingsInput{},
@@ -335,6 +360,57 @@ func TestUserService_UpdateSettings(t *testing.T) {
 	}
 }
 
+// MockUserCurrencyConverter implements UserCurrencyConverter for testing
+type MockUserCurrencyConverter struct {
+	mock.Mock
+}
+
+func (m *MockUserCurrencyConverter) ConvertUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func TestUserService_UpdateSettings_ConvertsTransactions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		currency    string
+		wantConvert bool
+	}{
+		{name: "new currency", currency: "VND", wantConvert: true},
+		{name: "same currency", currency: "USD"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockUserRepo)
+			converter := new(MockUserCurrencyConverter)
+			service := NewUserService(mockRepo)
+			service.SetCurrencyConverter(converter)
+			userID := uuid.New()
+
+			mockRepo.On("GetByID", mock.Anything, userID).Return(&model.User{ID: userID, Currency: "USD"}, nil)
+			mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.User")).Return(nil)
+			if tt.wantConvert {
+				converter.On("ConvertUser", mock.Anything, userID).Return(3, nil)
+			}
+
+			user, err := service.UpdateSettings(context.Background(), userID, UpdateSettingsInput{Currency: &tt.currency})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.currency, user.Currency)
+			converter.AssertExpectations(t)
+			if !tt.wantConvert {
+				converter.AssertNotCalled(t, "ConvertUser", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
 func TestGenerateToken(t *testing.T) {
 	userID := uuid.New()
 
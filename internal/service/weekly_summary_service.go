@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+	"github.com/wealthpath/backend/pkg/currency"
+)
+
+// Weekly summary content limits
+const (
+	weeklySummaryTopCategories     = 3
+	weeklySummaryUpcomingLimit     = 20
+	defaultWeeklySummaryBudgetNear = 90 // Used when a user has no valid alert threshold
+)
+
+// WeeklySummaryRepository finds recipients and records delivered summaries
+// (e.g. repository.PushRepository).
+type WeeklySummaryRepository interface {
+	GetWeeklySummaryRecipients(ctx context.Context) ([]repository.WeeklySummaryRecipient, error)
+	HasNotificationForDate(ctx context.Context, userID uuid.UUID, notifType model.NotificationType, refID *uuid.UUID, refDate time.Time) (bool, error)
+	LogNotification(ctx context.Context, log *model.NotificationLog) error
+}
+
+// WeeklySummaryTransactionRepo provides the week's income and spending.
+type WeeklySummaryTransactionRepo interface {
+	GetTotalsForPeriod(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) (decimal.Decimal, decimal.Decimal, error)
+	GetExpensesByCategory(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) (map[string]decimal.Decimal, error)
+}
+
+// WeeklySummaryBudgetSource provides budgets with their spending for the
+// periods containing a date (e.g. BudgetService).
+type WeeklySummaryBudgetSource interface {
+	ListWithSpentAt(ctx context.Context, userID uuid.UUID, date time.Time) ([]model.BudgetWithSpent, error)
+}
+
+// WeeklySummaryRecurringRepo provides the user's upcoming bills.
+type WeeklySummaryRecurringRepo interface {
+	GetUpcoming(ctx context.Context, userID uuid.UUID, limit int) ([]model.UpcomingBill, error)
+}
+
+// WeeklySummary is one user's financial summary for an ISO week.
+type WeeklySummary struct {
+	Year             int                     `json:"year"` // ISO year
+	Week             int                     `json:"week"` // ISO week number
+	WeekStart        time.Time               `json:"weekStart"`
+	WeekEnd          time.Time               `json:"weekEnd"`
+	Currency         string                  `json:"currency"`
+	Income           decimal.Decimal         `json:"income"`
+	Expenses         decimal.Decimal         `json:"expenses"`
+	TopCategories    []WeeklySummaryCategory `json:"topCategories"`
+	BudgetsNearLimit []WeeklySummaryBudget   `json:"budgetsNearLimit"`
+	UpcomingBills    []model.UpcomingBill    `json:"upcomingBills"`
+}
+
+// WeeklySummaryCategory is an expense category and what was spent on it during the week.
+type WeeklySummaryCategory struct {
+	Category string          `json:"category"`
+	Amount   decimal.Decimal `json:"amount"`
+}
+
+// WeeklySummaryBudget is a budget at or above the user's alert threshold in
+// the budget period the week falls in.
+type WeeklySummaryBudget struct {
+	BudgetID   uuid.UUID       `json:"budgetId"`
+	Category   string          `json:"category"`
+	Spent      decimal.Decimal `json:"spent"`
+	Amount     decimal.Decimal `json:"amount"`
+	Percentage float64         `json:"percentage"`
+}
+
+// WeeklySummaryService builds and pushes the weekly summary to opted-in users.
+type WeeklySummaryService struct {
+	repo            WeeklySummaryRepository
+	transactionRepo WeeklySummaryTransactionRepo
+	budgets         WeeklySummaryBudgetSource
+	recurringRepo   WeeklySummaryRecurringRepo
+	sender          PushSender
+}
+
+// NewWeeklySummaryService creates a new WeeklySummaryService.
+func NewWeeklySummaryService(
+	repo WeeklySummaryRepository,
+	transactionRepo WeeklySummaryTransactionRepo,
+	budgets WeeklySummaryBudgetSource,
+	recurringRepo WeeklySummaryRecurringRepo,
+	sender PushSender,
+) *WeeklySummaryService {
+	return &WeeklySummaryService{
+		repo:            repo,
+		transactionRepo: transactionRepo,
+		budgets:         budgets,
+		recurringRepo:   recurringRepo,
+		sender:          sender,
+	}
+}
+
+// SendWeeklySummaries sends every opted-in user a summary of the last complete
+// ISO week. It returns the number of summaries sent.
+func (s *WeeklySummaryService) SendWeeklySummaries(ctx context.Context) (int, error) {
+	return s.SendForWeek(ctx, isoWeekStart(time.Now()).AddDate(0, 0, -7))
+}
+
+// SendForWeek sends the summary of the ISO week starting on weekStart. Users who
+// already received that week's summary are skipped, so running it again is safe.
+func (s *WeeklySummaryService) SendForWeek(ctx context.Context, weekStart time.Time) (int, error) {
+	weekStart = isoWeekStart(weekStart)
+
+	recipients, err := s.repo.GetWeeklySummaryRecipients(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("getting weekly summary recipients: %w", err)
+	}
+
+	sent := 0
+	var errs []error
+	for _, recipient := range recipients {
+		if ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+		ok, err := s.sendSummary(ctx, recipient, weekStart)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", recipient.UserID, err))
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// sendSummary builds and delivers one user's summary. It returns false when the
+// summary for the week was already sent.
+func (s *WeeklySummaryService) sendSummary(ctx context.Context, recipient repository.WeeklySummaryRecipient, weekStart time.Time) (bool, error) {
+	already, err := s.repo.HasNotificationForDate(ctx, recipient.UserID, model.NotificationTypeWeeklySummary, nil, weekStart)
+	if err != nil {
+		return false, err
+	}
+	if already {
+		return false, nil
+	}
+
+	summary, err := s.Build(ctx, recipient, weekStart)
+	if err != nil {
+		return false, err
+	}
+
+	payload := &NotificationPayload{
+		Title: weeklySummaryTitle(summary),
+		Body:  weeklySummaryBody(summary),
+		Icon:  "/icon-192.png",
+		Badge: "/badge-72.png",
+		Tag:   fmt.Sprintf("weekly-summary-%d-W%02d", summary.Year, summary.Week),
+		Data: map[string]interface{}{
+			"type":      "weekly_summary",
+			"weekStart": weekStart.Format("2006-01-02"),
+			"url":       "/reports",
+		},
+	}
+
+	err = s.sender.SendToUser(ctx, recipient.UserID, payload)
+
+	// Log the notification
+	log := &model.NotificationLog{
+		ID:               uuid.New(),
+		UserID:           recipient.UserID,
+		NotificationType: model.NotificationTypeWeeklySummary,
+		ReferenceDate:    &weekStart,
+		Title:            payload.Title,
+		Body:             payload.Body,
+		SentAt:           time.Now(),
+		Success:          err == nil || errors.Is(err, ErrNoSubscriptions),
+	}
+	if err != nil && !errors.Is(err, ErrNoSubscriptions) {
+		errMsg := err.Error()
+		log.ErrorMessage = &errMsg
+	}
+	_ = s.repo.LogNotification(ctx, log)
+
+	if err != nil {
+		if errors.Is(err, ErrNoSubscriptions) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Build computes a user's summary for the ISO week starting on weekStart. Upcoming
+// bills are those due before the end of the following week, when the summary is read.
+func (s *WeeklySummaryService) Build(ctx context.Context, recipient repository.WeeklySummaryRecipient, weekStart time.Time) (*WeeklySummary, error) {
+	weekStart = isoWeekStart(weekStart)
+	weekEnd := weekStart.AddDate(0, 0, 6)
+	year, week := weekStart.ISOWeek()
+
+	summary := &WeeklySummary{
+		Year:             year,
+		Week:             week,
+		WeekStart:        weekStart,
+		WeekEnd:          weekEnd,
+		Currency:         recipient.Currency,
+		TopCategories:    []WeeklySummaryCategory{},
+		BudgetsNearLimit: []WeeklySummaryBudget{},
+		UpcomingBills:    []model.UpcomingBill{},
+	}
+
+	income, expenses, err := s.transactionRepo.GetTotalsForPeriod(ctx, recipient.UserID, weekStart, weekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("getting weekly totals: %w", err)
+	}
+	summary.Income = income
+	summary.Expenses = expenses
+
+	byCategory, err := s.transactionRepo.GetExpensesByCategory(ctx, recipient.UserID, weekStart, weekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("getting weekly expenses by category: %w", err)
+	}
+	summary.TopCategories = topWeeklyCategories(byCategory, weeklySummaryTopCategories)
+
+	// Budget weeks run Sunday to Saturday, so the periods containing the
+	// week's Saturday are the ones covering most of it
+	budgets, err := s.budgets.ListWithSpentAt(ctx, recipient.UserID, weekStart.AddDate(0, 0, 5))
+	if err != nil {
+		return nil, fmt.Errorf("getting budgets: %w", err)
+	}
+	threshold := recipient.BudgetAlertThreshold
+	if threshold <= 0 || threshold > 100 {
+		threshold = defaultWeeklySummaryBudgetNear
+	}
+	for _, b := range budgets {
+		if b.Percentage >= float64(threshold) {
+			summary.BudgetsNearLimit = append(summary.BudgetsNearLimit, WeeklySummaryBudget{
+				BudgetID:   b.ID,
+				Category:   b.Category,
+				Spent:      b.Spent,
+				Amount:     b.Amount,
+				Percentage: b.Percentage,
+			})
+		}
+	}
+	sort.SliceStable(summary.BudgetsNearLimit, func(i, j int) bool {
+		return summary.BudgetsNearLimit[i].Percentage > summary.BudgetsNearLimit[j].Percentage
+	})
+
+	upcoming, err := s.recurringRepo.GetUpcoming(ctx, recipient.UserID, weeklySummaryUpcomingLimit)
+	if err != nil {
+		return nil, fmt.Errorf("getting upcoming bills: %w", err)
+	}
+	horizon := weekStart.AddDate(0, 0, 14)
+	for _, bill := range upcoming {
+		if bill.Type == model.TransactionTypeExpense && bill.DueDate.Before(horizon) {
+			summary.UpcomingBills = append(summary.UpcomingBills, bill)
+		}
+	}
+
+	return summary, nil
+}
+
+// topWeeklyCategories returns the n categories with the highest spending
+func topWeeklyCategories(byCategory map[string]decimal.Decimal, n int) []WeeklySummaryCategory {
+	categories := make([]WeeklySummaryCategory, 0, len(byCategory))
+	for category, amount := range byCategory {
+		if amount.IsPositive() {
+			categories = append(categories, WeeklySummaryCategory{Category: category, Amount: amount})
+		}
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if !categories[i].Amount.Equal(categories[j].Amount) {
+			return categories[i].Amount.GreaterThan(categories[j].Amount)
+		}
+		return categories[i].Category < categories[j].Category
+	})
+	if len(categories) > n {
+		categories = categories[:n]
+	}
+	return categories
+}
+
+func weeklySummaryTitle(summary *WeeklySummary) string {
+	return fmt.Sprintf("Your week: %s – %s", summary.WeekStart.Format("Jan 2"), summary.WeekEnd.Format("Jan 2"))
+}
+
+func weeklySummaryBody(summary *WeeklySummary) string {
+	format := func(amount decimal.Decimal) string {
+		return currency.NewMoney(amount, currency.Currency(summary.Currency)).Format()
+	}
+
+	parts := []string{fmt.Sprintf("Income %s, spent %s.", format(summary.Income), format(summary.Expenses))}
+
+	if len(summary.TopCategories) > 0 {
+		top := make([]string, len(summary.TopCategories))
+		for i, c := range summary.TopCategories {
+			top[i] = c.Category + " " + format(c.Amount)
+		}
+		parts = append(parts, "Top: "+strings.Join(top, ", ")+".")
+	}
+
+	switch n := len(summary.BudgetsNearLimit); n {
+	case 0:
+	case 1:
+		parts = append(parts, "1 budget near its limit.")
+	default:
+		parts = append(parts, fmt.Sprintf("%d budgets near their limit.", n))
+	}
+
+	switch n := len(summary.UpcomingBills); n {
+	case 0:
+	case 1:
+		parts = append(parts, "1 bill due this week.")
+	default:
+		parts = append(parts, fmt.Sprintf("%d bills due this week.", n))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// isoWeekStart returns midnight on the Monday of t's ISO week
+func isoWeekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7 // Days since Monday
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wealthpath/backend/internal/model"
+	"github.com/wealthpath/backend/internal/repository"
+)
+
+// MockWeeklySummaryRepo implements WeeklySummaryRepository for testing
+type MockWeeklySummaryRepo struct {
+	mock.Mock
+}
+
+func (m *MockWeeklySummaryRepo) GetWeeklySummaryRecipients(ctx context.Context) ([]repository.WeeklySummaryRecipient, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.WeeklySummaryRecipient), args.Error(1)
+}
+
+func (m *MockWeeklySummaryRepo) HasNotificationForDate(ctx context.Context, userID uuid.UUID, notifType model.NotificationType, refID *uuid.UUID, refDate time.Time) (bool, error) {
+	args := m.Called(ctx, userID, notifType, refID, refDate)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWeeklySummaryRepo) LogNotification(ctx context.Context, log *model.NotificationLog) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
+// MockWeeklySummaryTransactionRepo implements WeeklySummaryTransactionRepo for testing
+type MockWeeklySummaryTransactionRepo struct {
+	mock.Mock
+}
+
+func (m *MockWeeklySummaryTransactionRepo) GetTotalsForPeriod(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) (decimal.Decimal, decimal.Decimal, error) {
+	args := m.Called(ctx, userID, startDate, endDate)
+	return args.Get(0).(decimal.Decimal), args.Get(1).(decimal.Decimal), args.Error(2)
+}
+
+func (m *MockWeeklySummaryTransactionRepo) GetExpensesByCategory(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) (map[string]decimal.Decimal, error) {
+	args := m.Called(ctx, userID, startDate, endDate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]decimal.Decimal), args.Error(1)
+}
+
+// MockWeeklySummaryBudgets implements WeeklySummaryBudgetSource for testing
+type MockWeeklySummaryBudgets struct {
+	mock.Mock
+}
+
+func (m *MockWeeklySummaryBudgets) ListWithSpentAt(ctx context.Context, userID uuid.UUID, date time.Time) ([]model.BudgetWithSpent, error) {
+	args := m.Called(ctx, userID, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.BudgetWithSpent), args.Error(1)
+}
+
+// MockWeeklySummaryRecurring implements WeeklySummaryRecurringRepo for testing
+type MockWeeklySummaryRecurring struct {
+	mock.Mock
+}
+
+func (m *MockWeeklySummaryRecurring) GetUpcoming(ctx context.Context, userID uuid.UUID, limit int) ([]model.UpcomingBill, error) {
+	args := m.Called(ctx, userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.UpcomingBill), args.Error(1)
+}
+
+type weeklySummaryMocks struct {
+	repo      *MockWeeklySummaryRepo
+	txRepo    *MockWeeklySummaryTransactionRepo
+	budgets   *MockWeeklySummaryBudgets
+	recurring *MockWeeklySummaryRecurring
+	sender    *MockPushSender
+}
+
+func newTestWeeklySummaryService() (*WeeklySummaryService, weeklySummaryMocks) {
+	m := weeklySummaryMocks{
+		repo:      new(MockWeeklySummaryRepo),
+		txRepo:    new(MockWeeklySummaryTransactionRepo),
+		budgets:   new(MockWeeklySummaryBudgets),
+		recurring: new(MockWeeklySummaryRecurring),
+		sender:    new(MockPushSender),
+	}
+	return NewWeeklySummaryService(m.repo, m.txRepo, m.budgets, m.recurring, m.sender), m
+}
+
+// expectWeekData sets up a week with some income, spending, one budget near its
+// limit and one bill due the following week
+func expectWeekData(m weeklySummaryMocks, userID uuid.UUID, weekStart time.Time) {
+	weekEnd := weekStart.AddDate(0, 0, 6)
+	m.txRepo.On("GetTotalsForPeriod", mock.Anything, userID, weekStart, weekEnd).
+		Return(decimal.NewFromInt(5000000), decimal.NewFromInt(1800000), nil)
+	m.txRepo.On("GetExpensesByCategory", mock.Anything, userID, weekStart, weekEnd).Return(map[string]decimal.Decimal{
+		"Food & Dining":  decimal.NewFromInt(900000),
+		"Transportation": decimal.NewFromInt(500000),
+		"Shopping":       decimal.NewFromInt(300000),
+		"Entertainment":  decimal.NewFromInt(100000),
+	}, nil)
+	// Budgets for the periods the week falls in, taken on its Saturday
+	m.budgets.On("ListWithSpentAt", mock.Anything, userID, weekStart.AddDate(0, 0, 5)).Return([]model.BudgetWithSpent{
+		{Budget: model.Budget{ID: uuid.New(), Category: "Food & Dining"}, Percentage: 95},
+		{Budget: model.Budget{ID: uuid.New(), Category: "Shopping"}, Percentage: 40},
+	}, nil)
+	m.recurring.On("GetUpcoming", mock.Anything, userID, weeklySummaryUpcomingLimit).Return([]model.UpcomingBill{
+		{ID: uuid.New(), Description: "Rent", Type: model.TransactionTypeExpense, DueDate: weekStart.AddDate(0, 0, 9)},
+		{ID: uuid.New(), Description: "Salary", Type: model.TransactionTypeIncome, DueDate: weekStart.AddDate(0, 0, 10)},
+		{ID: uuid.New(), Description: "Insurance", Type: model.TransactionTypeExpense, DueDate: weekStart.AddDate(0, 0, 30)},
+	}, nil)
+}
+
+func TestWeeklySummaryService_Build(t *testing.T) {
+	t.Parallel()
+
+	svc, m := newTestWeeklySummaryService()
+	userID := uuid.New()
+	weekStart := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC) // Monday, ISO week 23
+	expectWeekData(m, userID, weekStart)
+
+	// Any day of the week resolves to its Monday
+	summary, err := svc.Build(context.Background(), repository.WeeklySummaryRecipient{
+		UserID: userID, Currency: "VND", BudgetAlertThreshold: 90,
+	}, weekStart.AddDate(0, 0, 3))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2024, summary.Year)
+	assert.Equal(t, 23, summary.Week)
+	assert.Equal(t, weekStart, summary.WeekStart)
+	assert.Equal(t, weekStart.AddDate(0, 0, 6), summary.WeekEnd)
+	assert.True(t, summary.Income.Equal(decimal.NewFromInt(5000000)))
+	assert.True(t, summary.Expenses.Equal(decimal.NewFromInt(1800000)))
+
+	require.Len(t, summary.TopCategories, 3)
+	assert.Equal(t, "Food & Dining", summary.TopCategories[0].Category)
+	assert.Equal(t, "Transportation", summary.TopCategories[1].Category)
+	assert.Equal(t, "Shopping", summary.TopCategories[2].Category)
+
+	require.Len(t, summary.BudgetsNearLimit, 1)
+	assert.Equal(t, "Food & Dining", summary.BudgetsNearLimit[0].Category)
+
+	require.Len(t, summary.UpcomingBills, 1)
+	assert.Equal(t, "Rent", summary.UpcomingBills[0].Description)
+}
+
+func TestWeeklySummaryService_SendForWeek(t *testing.T) {
+	t.Parallel()
+
+	weekStart := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		alreadySent bool
+		sendErr     error
+		wantSent    int
+		wantErr     bool
+		wantLog     bool
+		wantSuccess bool
+	}{
+		{name: "sends and logs", wantSent: 1, wantLog: true, wantSuccess: true},
+		{name: "already sent this week", alreadySent: true, wantSent: 0},
+		{name: "no subscriptions", sendErr: ErrNoSubscriptions, wantSent: 0, wantLog: true, wantSuccess: true},
+		{name: "push failure is logged and returned", sendErr: errors.New("push failed"), wantSent: 0, wantErr: true, wantLog: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc, m := newTestWeeklySummaryService()
+			userID := uuid.New()
+
+			m.repo.On("GetWeeklySummaryRecipients", mock.Anything).Return([]repository.WeeklySummaryRecipient{
+				{UserID: userID, Currency: "VND", BudgetAlertThreshold: 90},
+			}, nil)
+			m.repo.On("HasNotificationForDate", mock.Anything, userID, model.NotificationTypeWeeklySummary, (*uuid.UUID)(nil), weekStart).Return(tt.alreadySent, nil)
+			if !tt.alreadySent {
+				expectWeekData(m, userID, weekStart)
+				m.sender.On("SendToUser", mock.Anything, userID, mock.MatchedBy(func(p *NotificationPayload) bool {
+					return p.Tag == "weekly-summary-2024-W23" && len(p.Data) == 3 && p.Data["type"] == "weekly_summary" &&
+						p.Data["weekStart"] == weekStart.Format("2006-01-02") && p.Data["url"] == "/reports"
+				})).Return(tt.sendErr)
+			}
+			if tt.wantLog {
+				m.repo.On("LogNotification", mock.Anything, mock.MatchedBy(func(l *model.NotificationLog) bool {
+					return l.NotificationType == model.NotificationTypeWeeklySummary &&
+						l.ReferenceDate != nil && l.ReferenceDate.Equal(weekStart) &&
+						l.Success == tt.wantSuccess
+				})).Return(nil)
+			}
+
+			sent, err := svc.SendForWeek(context.Background(), weekStart)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantSent, sent)
+			m.repo.AssertExpectations(t)
+			m.sender.AssertExpectations(t)
+			if tt.alreadySent {
+				m.sender.AssertNotCalled(t, "SendToUser", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestWeeklySummaryBody(t *testing.T) {
+	t.Parallel()
+
+	summary := &WeeklySummary{
+		Currency: "USD",
+		Income:   decimal.NewFromInt(1200),
+		Expenses: decimal.NewFromInt(450),
+		TopCategories: []WeeklySummaryCategory{
+			{Category: "Food & Dining", Amount: decimal.NewFromInt(200)},
+		},
+		BudgetsNearLimit: []WeeklySummaryBudget{{Category: "Food & Dining"}, {Category: "Shopping"}},
+		UpcomingBills:    []model.UpcomingBill{{Description: "Rent"}},
+	}
+
+	body := weeklySummaryBody(summary)
+
+	assert.Contains(t, body, "Income $1200.00, spent $450.00.")
+	assert.Contains(t, body, "Top: Food & Dining $200.00.")
+	assert.Contains(t, body, "2 budgets near their limit.")
+	assert.Contains(t, body, "1 bill due this week.")
+}
+
+func TestIsoWeekStart(t *testing.T) {
+	t.Parallel()
+
+	monday := time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC) // ISO week 1 of 2025
+	for i := 0; i < 7; i++ {
+		day := monday.AddDate(0, 0, i).Add(15 * time.Hour)
+		assert.Equal(t, monday, isoWeekStart(day), day.Weekday().String())
+	}
+	year, week := isoWeekStart(monday).ISOWeek()
+	assert.Equal(t, 2025, year)
+	assert.Equal(t, 1, week)
+}
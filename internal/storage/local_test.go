@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage_PutGetDelete(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, s.Put(ctx, "user/receipt", strings.NewReader("%PDF-1.7"), 8, "application/pdf"))
+
+	rc, err := s.Get(ctx, "user/receipt")
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	assert.Equal(t, "%PDF-1.7", string(data))
+
+	require.NoError(t, s.Delete(ctx, "user/receipt"))
+	_, err = s.Get(ctx, "user/receipt")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Deleting again is not an error
+	assert.NoError(t, s.Delete(ctx, "user/receipt"))
+}
+
+func TestLocalStorage_Put_SizeMismatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	err = s.Put(ctx, "user/receipt", strings.NewReader("short"), 100, "image/png")
+	require.Error(t, err)
+
+	_, err = s.Get(ctx, "user/receipt")
+	assert.ErrorIs(t, err, ErrNotFound, "a failed upload must not leave a file behind")
+}
+
+func TestValidateKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key   string
+		valid bool
+	}{
+		{key: "user/receipt", valid: true},
+		{key: "receipt.pdf", valid: true},
+		{key: ""},
+		{key: "/etc/passwd"},
+		{key: "user/../../etc/passwd"},
+		{key: "user/./receipt"},
+		{key: "user//receipt"},
+		{key: `user\receipt`},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.key, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateKey(tt.key)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
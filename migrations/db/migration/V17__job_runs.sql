@@ -0,0 +1,21 @@
+-- Create job_runs table to record every execution of a scheduled background job
+-- (scraper, recurring transactions, bill reminders, rate alerts, ...)
+
+CREATE TABLE IF NOT EXISTS job_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    items_processed INTEGER NOT NULL DEFAULT 0,
+    error_message TEXT,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ,
+
+    CONSTRAINT chk_job_runs_status CHECK (status IN ('running', 'succeeded', 'failed'))
+);
+
+-- Index for "latest runs of job X" queries
+CREATE INDEX IF NOT EXISTS idx_job_runs_name_started ON job_runs(job_name, started_at DESC);
+
+COMMENT ON TABLE job_runs IS 'Execution history of scheduled background jobs';
+COMMENT ON COLUMN job_runs.items_processed IS 'Number of items handled by the run (rates scraped, transactions created, reminders sent, ...)';
+COMMENT ON COLUMN job_runs.finished_at IS 'NULL while the run is still in progress';
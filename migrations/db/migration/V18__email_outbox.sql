@@ -0,0 +1,27 @@
+-- Create email_outbox table: every outgoing email is written here first and
+-- delivered by a background job, so sends survive restarts and are retried with backoff
+
+CREATE TABLE IF NOT EXISTS email_outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    to_address VARCHAR(255) NOT NULL,
+    subject TEXT NOT NULL,
+    text_body TEXT NOT NULL,
+    html_body TEXT,
+    template VARCHAR(100),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ,
+
+    CONSTRAINT chk_email_outbox_status CHECK (status IN ('pending', 'sending', 'sent', 'failed'))
+);
+
+-- Index for the delivery job picking up due messages
+CREATE INDEX IF NOT EXISTS idx_email_outbox_due ON email_outbox(next_attempt_at)
+    WHERE status IN ('pending', 'sending');
+
+COMMENT ON TABLE email_outbox IS 'Transactional outbox for outgoing email';
+COMMENT ON COLUMN email_outbox.template IS 'Template the message was rendered from (rate_alert, ...), NULL for plain messages';
+COMMENT ON COLUMN email_outbox.next_attempt_at IS 'When the message is next due; for sending rows this is the lease expiry after which a crashed delivery is retried';
@@ -0,0 +1,4 @@
+-- Allow overspending to be carried into the next period as a negative rollover
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS rollover_overspend BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN budgets.rollover_overspend IS 'Whether overspending reduces the next period budget';
@@ -0,0 +1,26 @@
+-- Ledger of savings goal contributions and withdrawals.
+-- savings_goals.current_amount is kept equal to the sum of a goal's entries.
+CREATE TABLE IF NOT EXISTS savings_contributions (
+    id UUID PRIMARY KEY,
+    goal_id UUID NOT NULL REFERENCES savings_goals(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount DECIMAL(15, 2) NOT NULL,  -- Positive for contributions, negative for withdrawals
+    date DATE NOT NULL DEFAULT CURRENT_DATE,
+    note TEXT,
+    transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    reversal_of UUID REFERENCES savings_contributions(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (amount <> 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_savings_contributions_goal_date ON savings_contributions(goal_id, date DESC, created_at DESC);
+
+-- An entry can be reversed at most once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_savings_contributions_reversal_of
+    ON savings_contributions(reversal_of) WHERE reversal_of IS NOT NULL;
+
+-- Open the ledger with the amount each goal has saved so far
+INSERT INTO savings_contributions (id, goal_id, user_id, amount, date, note, created_at)
+SELECT gen_random_uuid(), id, user_id, current_amount, created_at::date, 'Opening balance', created_at
+FROM savings_goals
+WHERE COALESCE(current_amount, 0) <> 0;
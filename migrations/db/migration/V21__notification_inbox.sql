@@ -0,0 +1,17 @@
+-- Read state and soft delete for the in-app notification inbox.
+-- Deleted entries are kept so they still de-duplicate reminders and alerts.
+ALTER TABLE notification_log ADD COLUMN IF NOT EXISTS read_at TIMESTAMP;
+ALTER TABLE notification_log ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+-- Notifications sent before the inbox existed start out read
+UPDATE notification_log SET read_at = sent_at WHERE read_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notification_log_inbox
+    ON notification_log(user_id, sent_at DESC, id DESC)
+    WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_notification_log_unread
+    ON notification_log(user_id)
+    WHERE read_at IS NULL AND deleted_at IS NULL;
+
+COMMENT ON COLUMN notification_log.read_at IS 'When the user read the notification in the app, NULL if unread';
+COMMENT ON COLUMN notification_log.deleted_at IS 'When the user removed the notification from their inbox';
@@ -0,0 +1,43 @@
+-- Financial accounts (wallets) and transfers between them.
+-- Balances are not stored: they are the opening balance plus the account's
+-- transactions and transfers.
+CREATE TABLE IF NOT EXISTS accounts (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('cash', 'bank', 'credit_card', 'e_wallet')),
+    institution VARCHAR(100) NOT NULL DEFAULT '',
+    currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    opening_balance DECIMAL(15, 2) NOT NULL DEFAULT 0,
+    archived BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_accounts_user_id ON accounts(user_id);
+
+ALTER TABLE transactions
+ADD COLUMN IF NOT EXISTS account_id UUID REFERENCES accounts(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN transactions.account_id IS 'Account the money came from or went to; NULL when not tracked';
+
+CREATE INDEX IF NOT EXISTS idx_transactions_account_date ON transactions(account_id, date)
+    WHERE account_id IS NOT NULL;
+
+-- Transfers are not income or expenses, so they live outside the transactions table.
+-- An account with transfers cannot be deleted, only archived.
+CREATE TABLE IF NOT EXISTS transfers (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_account_id UUID NOT NULL REFERENCES accounts(id),
+    to_account_id UUID NOT NULL REFERENCES accounts(id),
+    amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+    description TEXT NOT NULL DEFAULT '',
+    date DATE NOT NULL DEFAULT CURRENT_DATE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (from_account_id <> to_account_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfers_user_date ON transfers(user_id, date DESC, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_transfers_from_account ON transfers(from_account_id, date);
+CREATE INDEX IF NOT EXISTS idx_transfers_to_account ON transfers(to_account_id, date);
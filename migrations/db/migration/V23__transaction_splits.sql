@@ -0,0 +1,29 @@
+-- Split lines let one transaction cover several categories, e.g. a supermarket
+-- receipt that is part groceries and part household. The lines of a transaction
+-- add up to its amount; this is checked by the application.
+CREATE TABLE IF NOT EXISTS transaction_splits (
+    id UUID PRIMARY KEY,
+    transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    category VARCHAR(100) NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+    note TEXT NOT NULL DEFAULT '',
+    position INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_splits_transaction ON transaction_splits(transaction_id, position);
+CREATE INDEX IF NOT EXISTS idx_transaction_splits_category ON transaction_splits(category);
+
+-- One row per category line: the split lines of a split transaction, or the
+-- transaction itself. Anything that totals by category must read from this view.
+CREATE OR REPLACE VIEW transaction_lines AS
+SELECT
+    t.id AS transaction_id,
+    t.user_id,
+    t.type,
+    COALESCE(s.category, t.category) AS category,
+    COALESCE(s.amount, t.amount) AS amount,
+    t.currency,
+    t.date,
+    t.account_id
+FROM transactions t
+LEFT JOIN transaction_splits s ON s.transaction_id = t.id;
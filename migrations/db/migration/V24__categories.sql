@@ -0,0 +1,24 @@
+-- Per-user categories with one level of subcategories. Transactions, budgets and
+-- recurring transactions keep storing the category name, so renaming or merging a
+-- category rewrites those rows. Users get the default categories the first time
+-- they use the categories API.
+CREATE TABLE IF NOT EXISTS categories (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES categories(id) ON DELETE RESTRICT,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('income', 'expense')),
+    icon VARCHAR(50) NOT NULL DEFAULT '',
+    color VARCHAR(7) NOT NULL DEFAULT '',
+    archived BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (user_id, type, name),
+    CHECK (parent_id <> id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_user ON categories(user_id, type);
+CREATE INDEX IF NOT EXISTS idx_categories_parent ON categories(parent_id) WHERE parent_id IS NOT NULL;
+
+COMMENT ON COLUMN categories.parent_id IS 'Top-level category this one rolls up into in reports, NULL for top-level categories';
+COMMENT ON COLUMN categories.color IS 'Hex colour such as #4CAF50, empty to let the client choose';
@@ -0,0 +1,47 @@
+-- Receipts and invoices attached to transactions and debt payments. The files
+-- themselves live in blob storage under storage_key.
+CREATE TABLE IF NOT EXISTS attachments (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    transaction_id UUID REFERENCES transactions(id) ON DELETE CASCADE,
+    debt_payment_id UUID REFERENCES debt_payments(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT attachments_one_owner CHECK (num_nonnulls(transaction_id, debt_payment_id) = 1)
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_transaction ON attachments(transaction_id) WHERE transaction_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_attachments_debt_payment ON attachments(debt_payment_id) WHERE debt_payment_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_attachments_user ON attachments(user_id);
+
+-- Blobs waiting to be removed from storage. Every deleted attachment row is queued
+-- here, including rows removed by cascades when a transaction, debt payment, debt
+-- or user is deleted, and a background job deletes the blobs.
+CREATE TABLE IF NOT EXISTS attachment_blob_deletions (
+    storage_key VARCHAR(255) PRIMARY KEY,
+    deleted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT
+);
+
+CREATE OR REPLACE FUNCTION queue_attachment_blob_deletion()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO attachment_blob_deletions (storage_key)
+    VALUES (OLD.storage_key)
+    ON CONFLICT (storage_key) DO NOTHING;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_queue_attachment_blob_deletion ON attachments;
+CREATE TRIGGER trg_queue_attachment_blob_deletion
+    AFTER DELETE ON attachments
+    FOR EACH ROW
+    EXECUTE FUNCTION queue_attachment_blob_deletion();
+
+COMMENT ON COLUMN attachments.content_type IS 'MIME type detected from the file contents, not the one sent by the client';
+COMMENT ON COLUMN attachments.storage_key IS 'Key of the blob in attachment storage, <user_id>/<attachment_id>';
@@ -0,0 +1,26 @@
+-- Files imported into transactions. An upload is kept as a pending batch while
+-- the user reviews the preview; committing creates all its transactions at once
+-- and undoing deletes them again.
+CREATE TABLE IF NOT EXISTS import_batches (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source VARCHAR(20) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    encoding VARCHAR(20) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'committed', 'undone')),
+    content TEXT,
+    row_count INTEGER NOT NULL DEFAULT 0,
+    imported_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    committed_at TIMESTAMP WITH TIME ZONE,
+    undone_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_batches_user ON import_batches(user_id, created_at DESC);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS import_batch_id UUID REFERENCES import_batches(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_import_batch ON transactions(import_batch_id) WHERE import_batch_id IS NOT NULL;
+
+COMMENT ON COLUMN import_batches.source IS 'File format: csv';
+COMMENT ON COLUMN import_batches.content IS 'Uploaded file as UTF-8 text, kept until the batch is committed';
@@ -0,0 +1,9 @@
+-- Statement imports (OFX, QFX, QIF) record the bank's ID for each transaction so
+-- that importing the same or an overlapping statement again skips what is
+-- already there.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS external_id VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_external_id ON transactions(user_id, external_id) WHERE external_id IS NOT NULL;
+
+COMMENT ON COLUMN transactions.external_id IS 'Source-specific ID of an imported transaction, such as ofx:<account>:<FITID>';
+COMMENT ON COLUMN import_batches.source IS 'File format: csv, ofx or qif';
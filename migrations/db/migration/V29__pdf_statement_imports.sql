@@ -0,0 +1,4 @@
+-- PDF e-statements are binary, so a pending PDF import keeps its upload as
+-- base64 text, recorded as the batch's encoding.
+COMMENT ON COLUMN import_batches.source IS 'File format: csv, ofx, qif or pdf';
+COMMENT ON COLUMN import_batches.content IS 'Uploaded file as UTF-8 text, or base64 for PDFs, kept until the batch is committed';
@@ -0,0 +1,12 @@
+-- Migrations from other budgeting apps create transfers, debts and budgets as
+-- well as transactions. Each records the import batch that created it, so
+-- that undoing the import removes it again.
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS import_batch_id UUID REFERENCES import_batches(id) ON DELETE SET NULL;
+ALTER TABLE debts ADD COLUMN IF NOT EXISTS import_batch_id UUID REFERENCES import_batches(id) ON DELETE SET NULL;
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS import_batch_id UUID REFERENCES import_batches(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_transfers_import_batch ON transfers(import_batch_id) WHERE import_batch_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_debts_import_batch ON debts(import_batch_id) WHERE import_batch_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_budgets_import_batch ON budgets(import_batch_id) WHERE import_batch_id IS NOT NULL;
+
+COMMENT ON COLUMN import_batches.source IS 'File format: csv, ofx, qif or pdf, or the app migrated from: moneylover or misa';
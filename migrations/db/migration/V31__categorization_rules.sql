@@ -0,0 +1,39 @@
+-- Per-user rules that categorize transactions as they are created, imported or
+-- added from the AI chat. A rule matches when all of its conditions hold; empty
+-- conditions match everything. Rules run in priority order and the first rule
+-- to set the category or description wins; tags from every matching rule are
+-- added.
+CREATE TABLE IF NOT EXISTS categorization_rules (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    transaction_type VARCHAR(20) CHECK (transaction_type IN ('income', 'expense')),
+    description_contains VARCHAR(255) NOT NULL DEFAULT '',
+    description_pattern VARCHAR(255) NOT NULL DEFAULT '',
+    min_amount DECIMAL(15, 2),
+    max_amount DECIMAL(15, 2),
+    account_id UUID REFERENCES accounts(id) ON DELETE CASCADE,
+    set_category VARCHAR(100) NOT NULL DEFAULT '',
+    set_description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_categorization_rules_user ON categorization_rules(user_id, priority);
+
+-- Tags a rule adds, linked by ID like transaction tags so renaming a tag renames
+-- it in rules too
+CREATE TABLE IF NOT EXISTS categorization_rule_tags (
+    rule_id UUID NOT NULL REFERENCES categorization_rules(id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (rule_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_categorization_rule_tags_tag ON categorization_rule_tags(tag_id);
+
+COMMENT ON COLUMN categorization_rules.priority IS 'Rules with a lower priority run first';
+COMMENT ON COLUMN categorization_rules.description_contains IS 'Text the description must contain, ignoring case and Vietnamese diacritics';
+COMMENT ON COLUMN categorization_rules.description_pattern IS 'Regular expression (RE2 syntax) the description must match, ignoring case';
+COMMENT ON COLUMN categorization_rules.set_description IS 'Replaces the description, e.g. "Grab" for "GRAB*A-5XK2 HCMC"';
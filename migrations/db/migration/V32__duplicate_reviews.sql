@@ -0,0 +1,19 @@
+-- Decisions on suspected duplicate transactions. Pairs the user dismissed or
+-- marked as not duplicates are left out of the review queue for good. A pair is
+-- stored with the lower transaction ID first.
+CREATE TABLE IF NOT EXISTS duplicate_reviews (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    duplicate_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('dismissed', 'not_duplicate')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (user_id, transaction_id, duplicate_id),
+    CHECK (transaction_id < duplicate_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_duplicate_reviews_duplicate ON duplicate_reviews(duplicate_id);
+
+-- Candidate pairs are looked up by user, type and date
+CREATE INDEX IF NOT EXISTS idx_transactions_user_type_date ON transactions(user_id, type, date);
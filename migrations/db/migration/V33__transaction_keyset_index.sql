@@ -0,0 +1,7 @@
+-- Transactions are listed by date, then creation time, then ID, newest first.
+-- Keyset pagination compares all three, so none may be NULL.
+UPDATE transactions SET created_at = NOW() WHERE created_at IS NULL;
+ALTER TABLE transactions ALTER COLUMN created_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_user_list_order
+ON transactions (user_id, date DESC, created_at DESC, id DESC);
@@ -0,0 +1,46 @@
+-- Exchange rates, one per currency pair, day and source. A rate is the number
+-- of units of to_currency one unit of from_currency buys.
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    from_currency VARCHAR(3) NOT NULL,
+    to_currency VARCHAR(3) NOT NULL,
+    rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    rate_date DATE NOT NULL,
+    source VARCHAR(50) NOT NULL DEFAULT 'manual',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (from_currency, to_currency, rate_date, source)
+);
+
+CREATE INDEX IF NOT EXISTS idx_exchange_rates_pair_date
+ON exchange_rates (from_currency, to_currency, rate_date DESC);
+
+-- The amount of a transaction in the user's base currency, and the rate used
+-- to convert it. NULL until a rate is known for the transaction's date.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS base_currency VARCHAR(3);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC(20, 10);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS base_amount DECIMAL(15, 2);
+
+UPDATE transactions t
+SET base_currency = u.currency, exchange_rate = 1, base_amount = t.amount
+FROM users u
+WHERE u.id = t.user_id AND t.currency = u.currency;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_unconverted
+ON transactions (user_id) WHERE base_amount IS NULL;
+
+-- Report lines in the base currency. Split lines are converted at their
+-- transaction's rate; lines of unconverted transactions keep their own amount.
+CREATE OR REPLACE VIEW transaction_lines AS
+SELECT
+    t.id AS transaction_id,
+    t.user_id,
+    t.type,
+    COALESCE(s.category, t.category) AS category,
+    COALESCE(s.amount, t.amount) AS amount,
+    t.currency,
+    t.date,
+    t.account_id,
+    COALESCE(s.amount * t.exchange_rate, t.base_amount, s.amount, t.amount) AS base_amount
+FROM transactions t
+LEFT JOIN transaction_splits s ON s.transaction_id = t.id;
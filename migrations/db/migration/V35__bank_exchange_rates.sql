@@ -0,0 +1,62 @@
+-- Foreign exchange rates against VND published by Vietnamese banks, one row per
+-- bank and currency holding the latest scraped rates
+CREATE TABLE IF NOT EXISTS bank_exchange_rates (
+    id SERIAL PRIMARY KEY,
+    bank_code VARCHAR(20) NOT NULL,
+    bank_name VARCHAR(100) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    currency_name VARCHAR(100),
+    buy_cash NUMERIC(20, 4),
+    buy_transfer NUMERIC(20, 4),
+    sell NUMERIC(20, 4),
+    rate_date DATE NOT NULL,
+    scraped_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (bank_code, currency)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bank_exchange_rates_currency ON bank_exchange_rates(currency);
+
+COMMENT ON TABLE bank_exchange_rates IS 'Foreign exchange rates against VND scraped from Vietnamese banks';
+COMMENT ON COLUMN bank_exchange_rates.buy_cash IS 'VND the bank pays for one unit of cash (NULL if not bought in cash)';
+COMMENT ON COLUMN bank_exchange_rates.buy_transfer IS 'VND the bank pays for one unit by transfer';
+COMMENT ON COLUMN bank_exchange_rates.sell IS 'VND the bank charges for one unit';
+
+-- Daily history of bank exchange rates for charts
+CREATE TABLE IF NOT EXISTS bank_exchange_rate_history (
+    id SERIAL PRIMARY KEY,
+    bank_code VARCHAR(20) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    buy_cash NUMERIC(20, 4),
+    buy_transfer NUMERIC(20, 4),
+    sell NUMERIC(20, 4),
+    recorded_date DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (bank_code, currency, recorded_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bank_exchange_rate_history_lookup
+ON bank_exchange_rate_history(bank_code, currency, recorded_date);
+
+-- Record the rates of the day whenever they are inserted or change
+CREATE OR REPLACE FUNCTION record_bank_exchange_rate_history()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' OR OLD.buy_cash IS DISTINCT FROM NEW.buy_cash
+        OR OLD.buy_transfer IS DISTINCT FROM NEW.buy_transfer
+        OR OLD.sell IS DISTINCT FROM NEW.sell THEN
+        INSERT INTO bank_exchange_rate_history (bank_code, currency, buy_cash, buy_transfer, sell, recorded_date)
+        VALUES (NEW.bank_code, NEW.currency, NEW.buy_cash, NEW.buy_transfer, NEW.sell, NEW.rate_date)
+        ON CONFLICT (bank_code, currency, recorded_date)
+        DO UPDATE SET buy_cash = EXCLUDED.buy_cash, buy_transfer = EXCLUDED.buy_transfer, sell = EXCLUDED.sell;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_record_bank_exchange_rate_history ON bank_exchange_rates;
+CREATE TRIGGER trg_record_bank_exchange_rate_history
+    AFTER INSERT OR UPDATE ON bank_exchange_rates
+    FOR EACH ROW
+    EXECUTE FUNCTION record_bank_exchange_rate_history();
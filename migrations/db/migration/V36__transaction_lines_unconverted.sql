@@ -0,0 +1,24 @@
+-- Transactions of users without a currency are in USD, like the application
+-- assumes; convert the ones already in it.
+UPDATE transactions t
+SET base_currency = t.currency, exchange_rate = 1, base_amount = t.amount
+FROM users u
+WHERE u.id = t.user_id
+AND t.base_amount IS NULL
+AND t.currency = COALESCE(NULLIF(u.currency, ''), 'USD');
+
+-- Lines of transactions not converted yet have no base amount, so that they are
+-- left out of base currency totals instead of being added in their own currency.
+CREATE OR REPLACE VIEW transaction_lines AS
+SELECT
+    t.id AS transaction_id,
+    t.user_id,
+    t.type,
+    COALESCE(s.category, t.category) AS category,
+    COALESCE(s.amount, t.amount) AS amount,
+    t.currency,
+    t.date,
+    t.account_id,
+    CASE WHEN t.base_amount IS NOT NULL THEN COALESCE(s.amount * t.exchange_rate, t.base_amount) END AS base_amount
+FROM transactions t
+LEFT JOIN transaction_splits s ON s.transaction_id = t.id;
@@ -0,0 +1,17 @@
+-- The goal milestone a notification was sent for, so each milestone of a goal
+-- is announced once rather than once a day.
+ALTER TABLE notification_log ADD COLUMN IF NOT EXISTS milestone SMALLINT;
+
+-- Milestones sent before are read back from their text
+UPDATE notification_log
+SET milestone = CASE
+    WHEN title = 'Goal Achieved!' THEN 100
+    ELSE substring(body FROM 'You''re (\d+)%')::smallint
+END
+WHERE notification_type = 'goal_milestone' AND milestone IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notification_log_milestone
+    ON notification_log(user_id, reference_id, milestone)
+    WHERE milestone IS NOT NULL;
+
+COMMENT ON COLUMN notification_log.milestone IS 'Percentage of the goal reached, for goal milestone notifications';
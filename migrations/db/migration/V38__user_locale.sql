@@ -0,0 +1,8 @@
+-- The language a user's emails are sent in. Vietnamese unless they choose otherwise.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(5) NOT NULL DEFAULT 'vi';
+
+-- Existing users keep the language their emails were sent in so far, which was
+-- English for anyone not tracking money in VND
+UPDATE users SET locale = 'en' WHERE currency IS NOT NULL AND currency NOT IN ('', 'VND');
+
+COMMENT ON COLUMN users.locale IS 'Language of the user''s emails: vi or en';
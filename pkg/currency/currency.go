@@ -3,7 +3,9 @@
 package currency
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/shopspring/decimal"
 )
@@ -156,6 +158,31 @@ func (m Money) Abs() Money {
 	return NewMoney(m.Amount.Abs(), m.Currency)
 }
 
+// Convert returns the amount in another currency at the given rate (units of
+// to per unit of m's currency), rounded to the target currency's decimal places.
+func (m Money) Convert(to Currency, rate decimal.Decimal) Money {
+	return NewMoney(m.Amount.Mul(rate), to).Round()
+}
+
+// RateSource provides exchange rates, e.g. the ones published by banks.
+type RateSource interface {
+	// Rate returns how many units of to one unit of from buys on the date.
+	Rate(ctx context.Context, from, to string, date time.Time) (decimal.Decimal, error)
+}
+
+// ConvertAt returns the amount in another currency at the source's rate on the
+// given date, rounded to the target currency's decimal places.
+func (m Money) ConvertAt(ctx context.Context, rates RateSource, to Currency, date time.Time) (Money, error) {
+	if m.Currency == to {
+		return m, nil
+	}
+	rate, err := rates.Rate(ctx, string(m.Currency), string(to), date)
+	if err != nil {
+		return Money{}, err
+	}
+	return m.Convert(to, rate), nil
+}
+
 // Round rounds the amount to the currency's decimal places.
 func (m Money) Round() Money {
 	info, ok := GetInfo(m.Currency)
@@ -189,3 +216,13 @@ func (m Money) String() string {
 	}
 	return m.Amount.Round(int32(info.DecimalPlaces)).String()
 }
+
+// StringFixed returns the amount rounded to the currency's decimal places,
+// keeping trailing zeros (e.g. "100.50" for USD, "25000" for VND).
+func (m Money) StringFixed() string {
+	info, ok := GetInfo(m.Currency)
+	if !ok {
+		info = currencies[DefaultCurrency]
+	}
+	return m.Amount.StringFixed(int32(info.DecimalPlaces))
+}
@@ -1,7 +1,10 @@
 package currency
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
@@ -203,6 +206,57 @@ func TestMoneyRound(t *testing.T) {
 	})
 }
 
+func TestMoneyConvert(t *testing.T) {
+	t.Run("USD to VND rounds to whole dong", func(t *testing.T) {
+		m := NewMoneyFromFloat(12.34, USD)
+		result := m.Convert(VND, decimal.RequireFromString("25432.5"))
+		assert.Equal(t, "313837", result.Amount.String())
+		assert.Equal(t, VND, result.Currency)
+	})
+
+	t.Run("VND to USD rounds to cents", func(t *testing.T) {
+		m := NewMoneyFromFloat(100000, VND)
+		result := m.Convert(USD, decimal.RequireFromString("0.0000393"))
+		assert.Equal(t, "3.93", result.Amount.String())
+		assert.Equal(t, USD, result.Currency)
+	})
+}
+
+// fixedRates is a RateSource with one rate per "FROM/TO" pair
+type fixedRates map[string]string
+
+func (f fixedRates) Rate(_ context.Context, from, to string, _ time.Time) (decimal.Decimal, error) {
+	rate, ok := f[from+"/"+to]
+	if !ok {
+		return decimal.Zero, errors.New("no rate")
+	}
+	return decimal.RequireFromString(rate), nil
+}
+
+func TestMoneyConvertAt(t *testing.T) {
+	ctx := context.Background()
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	rates := fixedRates{"USD/VND": "25432.5"}
+
+	t.Run("at the source's rate", func(t *testing.T) {
+		result, err := NewMoneyFromFloat(12.34, USD).ConvertAt(ctx, rates, VND, date)
+		require.NoError(t, err)
+		assert.Equal(t, "313837", result.Amount.String())
+		assert.Equal(t, VND, result.Currency)
+	})
+
+	t.Run("same currency", func(t *testing.T) {
+		result, err := NewMoneyFromFloat(12.34, USD).ConvertAt(ctx, rates, USD, date)
+		require.NoError(t, err)
+		assert.Equal(t, "12.34", result.Amount.String())
+	})
+
+	t.Run("unknown rate", func(t *testing.T) {
+		_, err := NewMoneyFromFloat(12.34, EUR).ConvertAt(ctx, rates, VND, date)
+		assert.Error(t, err)
+	})
+}
+
 func TestMoneyFormat(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -246,3 +300,22 @@ func TestMoneyString(t *testing.T) {
 		assert.Equal(t, "100.5", m.String())
 	})
 }
+
+func TestMoneyStringFixed(t *testing.T) {
+	tests := []struct {
+		name     string
+		money    Money
+		expected string
+	}{
+		{"USD keeps trailing zeros", NewMoneyFromFloat(100.5, USD), "100.50"},
+		{"VND", NewMoneyFromFloat(25000.4, VND), "25000"},
+		{"JPY", NewMoneyFromFloat(99.5, JPY), "100"},
+		{"invalid currency", Money{Amount: decimal.NewFromFloat(7), Currency: Currency("INVALID")}, "7.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.money.StringFixed())
+		})
+	}
+}
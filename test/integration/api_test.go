@@ -177,6 +177,22 @@ func (m *MockTransactionService) List(ctx context.Context, userID uuid.UUID, inp
 	return args.Get(0).([]model.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionService) ListPage(ctx context.Context, userID uuid.UUID, input service.ListTransactionsInput) (*service.TransactionPage, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.TransactionPage), args.Error(1)
+}
+
+func (m *MockTransactionService) Bulk(ctx context.Context, userID uuid.UUID, input service.BulkTransactionInput) (*service.BulkTransactionResult, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.BulkTransactionResult), args.Error(1)
+}
+
 func (m *MockTransactionService) Update(ctx context.Context, id, userID uuid.UUID, input service.UpdateTransactionInput) (*model.Transaction, error) {
 	args := m.Called(ctx, id, userID, input)
 	if args.Get(0) == nil {
@@ -239,6 +255,14 @@ func (m *MockBudgetService) Delete(ctx context.Context, id, userID uuid.UUID) er
 	return args.Error(0)
 }
 
+func (m *MockBudgetService) ListRollovers(ctx context.Context, id, userID uuid.UUID) ([]model.BudgetRollover, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.BudgetRollover), args.Error(1)
+}
+
 // ============ Test Server Setup ============
 
 func setupTestRouter(